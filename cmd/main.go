@@ -101,5 +101,8 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	// Stop background jobs once no request can start new work
+	r.Shutdown()
+
 	logger.Info("server_shutdown_complete", "SmartSeller backend server shutdown complete", nil)
 }
@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Cart item issues reported when a cart is revalidated
+const (
+	CartIssueUnavailable       = "unavailable"        // Product or variant can no longer be bought
+	CartIssueOutOfStock        = "out_of_stock"       // Nothing left in stock
+	CartIssueInsufficientStock = "insufficient_stock" // Less stock than the cart quantity
+	CartIssuePriceChanged      = "price_changed"      // Price differs from when the item was added
+)
+
+// AddCartItemRequest represents a request to add a product to the cart
+type AddCartItemRequest struct {
+	ProductID string  `json:"product_id" validate:"required,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	VariantID *string `json:"variant_id,omitempty" validate:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Quantity  int     `json:"quantity" validate:"required,min=1" example:"1"`
+}
+
+// UpdateCartItemRequest represents a request to change the quantity of a cart item.
+// A quantity of zero removes the item.
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity" validate:"min=0" example:"3"`
+}
+
+// CartItemResponse represents a cart item revalidated against the live catalogue
+type CartItemResponse struct {
+	ID             uuid.UUID       `json:"id"`
+	ProductID      uuid.UUID       `json:"product_id"`
+	VariantID      *uuid.UUID      `json:"variant_id,omitempty"`
+	ProductName    string          `json:"product_name" example:"Wireless Earbuds"`
+	VariantName    *string         `json:"variant_name,omitempty" example:"Black"`
+	SKU            string          `json:"sku" example:"WE-001"`
+	ImageURL       *string         `json:"image_url,omitempty"`
+	Quantity       int             `json:"quantity" example:"2"`
+	UnitPrice      decimal.Decimal `json:"unit_price" example:"150000"`
+	AddedPrice     decimal.Decimal `json:"added_price" example:"160000"`
+	LineTotal      decimal.Decimal `json:"line_total" example:"300000"`
+	AvailableStock *int            `json:"available_stock,omitempty" example:"10"`
+	Available      bool            `json:"available" example:"true"`
+	Issues         []string        `json:"issues,omitempty" example:"price_changed"`
+}
+
+// CartResponse represents a shopping cart
+type CartResponse struct {
+	ID           *uuid.UUID          `json:"id,omitempty"`
+	StorefrontID uuid.UUID           `json:"storefront_id"`
+	SessionID    *string             `json:"session_id,omitempty"`
+	Items        []*CartItemResponse `json:"items"`
+	ItemCount    int                 `json:"item_count" example:"2"`
+	Subtotal     decimal.Decimal     `json:"subtotal" example:"300000"`
+	Currency     string              `json:"currency" example:"IDR"`
+	HasIssues    bool                `json:"has_issues" example:"false"`
+	UpdatedAt    *time.Time          `json:"updated_at,omitempty"`
+}
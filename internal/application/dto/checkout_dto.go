@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+// CheckoutItemRequest represents a product and quantity being priced or shipped
+type CheckoutItemRequest struct {
+	ProductID string  `json:"product_id" validate:"required,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	VariantID *string `json:"variant_id,omitempty" validate:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Quantity  int     `json:"quantity" validate:"required,min=1" example:"2"`
+}
+
+// CheckoutRequest represents a storefront checkout request. The items come
+// from the caller's cart.
+type CheckoutRequest struct {
+	ShippingAddressID *string              `json:"shipping_address_id,omitempty" validate:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440004"`
+	ShippingAddress   *OrderAddressRequest `json:"shipping_address,omitempty"`
+	BillingAddress    *OrderAddressRequest `json:"billing_address,omitempty"`
+	PaymentMethod     string               `json:"payment_method" validate:"required,max=50" example:"bank_transfer"`
+	ShippingMethod    string               `json:"shipping_method" validate:"omitempty,max=100" example:"JNE REG"`
+	Notes             string               `json:"notes" validate:"omitempty,max=2000" example:"Please wrap as a gift"`
+	CustomerEmail     string               `json:"customer_email" validate:"omitempty,email" example:"budi@example.com"`
+	CustomerPhone     string               `json:"customer_phone" validate:"omitempty,max=20" example:"+628123456789"`
+}
+
+// CheckoutResponse represents the order placed by a checkout
+type CheckoutResponse struct {
+	Order                *OrderResponse `json:"order"`
+	ReservationExpiresAt time.Time      `json:"reservation_expires_at" example:"2024-01-01T13:00:00Z"`
+}
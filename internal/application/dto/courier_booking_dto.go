@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+)
+
+// CourierBookingRequest represents a request to book an order's parcel with a
+// courier. Without a pickup slot the seller drops the parcel off.
+type CourierBookingRequest struct {
+	Courier     string `json:"courier" validate:"required,max=50" example:"sicepat"`
+	ServiceType string `json:"service_type" validate:"required,max=50" example:"REG"`
+	// District of the shipping address; orders only keep its city and province
+	District string `json:"district" validate:"required,max=100" example:"Abiansemal"`
+	// Weight in kg; defaults to the weight of the order's items
+	Weight      *decimal.Decimal `json:"weight,omitempty" example:"1.5"`
+	PickupDate  string           `json:"pickup_date,omitempty" validate:"omitempty,datetime=2006-01-02" example:"2026-10-17"`
+	PickupStart string           `json:"pickup_start,omitempty" validate:"omitempty,datetime=15:04" example:"09:00"`
+}
+
+// CourierBookingResponse represents an order's parcel booked with a courier
+type CourierBookingResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	OrderID        uuid.UUID  `json:"order_id"`
+	Courier        string     `json:"courier" example:"sicepat"`
+	ServiceType    string     `json:"service_type" example:"REG"`
+	TrackingNumber string     `json:"tracking_number" example:"000888000001"`
+	BookingCode    *string    `json:"booking_code,omitempty" example:"PU2610160000123"`
+	Status         string     `json:"status" example:"booked"`
+	Pickup         bool       `json:"pickup"`
+	PickupStart    *time.Time `json:"pickup_start,omitempty"`
+	PickupEnd      *time.Time `json:"pickup_end,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// PickupTimesResponse represents a courier's pickup slots on a day
+type PickupTimesResponse struct {
+	Courier string             `json:"courier" example:"sicepat"`
+	Date    string             `json:"date" example:"2026-10-17"`
+	Slots   []model.PickupTime `json:"slots"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "github.com/kirimku/smartseller-backend/internal/domain/entity"
+
+// ConvertCourierWebhookEventToResponse converts a courier webhook event to its
+// response, including the raw delivery when withDelivery is set
+func ConvertCourierWebhookEventToResponse(event *entity.CourierWebhookEvent, withDelivery bool) *CourierWebhookEventResponse {
+	response := &CourierWebhookEventResponse{
+		ID:             event.ID,
+		CourierCode:    event.CourierCode,
+		Status:         event.Status.String(),
+		SignatureValid: event.SignatureValid,
+		RemoteIP:       event.RemoteIP,
+		ErrorMessage:   event.ErrorMessage,
+		UpdatesCount:   event.UpdatesCount,
+		Attempts:       event.Attempts,
+		CanReplay:      event.CanReplay(),
+		LastAttemptAt:  event.LastAttemptAt,
+		NextRetryAt:    event.NextRetryAt,
+		ProcessedAt:    event.ProcessedAt,
+		ReceivedAt:     event.ReceivedAt,
+	}
+
+	if withDelivery {
+		response.Headers = event.Headers
+		response.Signature = event.Signature
+		response.Payload = event.Payload
+	}
+
+	return response
+}
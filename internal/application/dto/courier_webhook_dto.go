@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CourierWebhookResponse summarises how a courier webhook delivery was processed
+type CourierWebhookResponse struct {
+	EventID       uuid.UUID `json:"event_id"`
+	CourierCode   string    `json:"courier_code" example:"jne"`
+	UpdatesCount  int       `json:"updates_count" example:"1"`
+	OrdersUpdated int       `json:"orders_updated" example:"1"`
+	ClaimsUpdated int       `json:"claims_updated" example:"0"`
+}
+
+// CourierWebhookEventResponse represents a stored courier webhook delivery.
+// Payload and headers are only included when a single event is inspected.
+type CourierWebhookEventResponse struct {
+	ID             uuid.UUID         `json:"id"`
+	CourierCode    string            `json:"courier_code" example:"jne"`
+	Status         string            `json:"status" example:"dead_lettered"`
+	SignatureValid *bool             `json:"signature_valid,omitempty" example:"true"`
+	RemoteIP       *string           `json:"remote_ip,omitempty" example:"203.0.113.10"`
+	ErrorMessage   *string           `json:"error_message,omitempty" example:"invalid webhook payload: missing tracking number"`
+	UpdatesCount   int               `json:"updates_count" example:"1"`
+	Attempts       int               `json:"attempts" example:"8"`
+	CanReplay      bool              `json:"can_replay" example:"true"`
+	LastAttemptAt  *time.Time        `json:"last_attempt_at,omitempty"`
+	NextRetryAt    *time.Time        `json:"next_retry_at,omitempty"`
+	ProcessedAt    *time.Time        `json:"processed_at,omitempty"`
+	ReceivedAt     time.Time         `json:"received_at"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Signature      *string           `json:"signature,omitempty"`
+	Payload        string            `json:"payload,omitempty"`
+}
+
+// CourierWebhookEventListResponse represents a paginated list of courier webhook deliveries
+type CourierWebhookEventListResponse struct {
+	Events     []CourierWebhookEventResponse `json:"events"`
+	Pagination PaginationResponse            `json:"pagination"`
+}
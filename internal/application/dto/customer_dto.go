@@ -26,6 +26,10 @@ type CustomerAuthRequest struct {
 	Email    string `json:"email,omitempty"`
 	Phone    string `json:"phone,omitempty"`
 	Password string `json:"password" binding:"required"`
+
+	// Client details recorded on the session, set by the handler
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
 }
 
 // CustomerAuthResponse represents a customer authentication response
@@ -35,6 +39,10 @@ type CustomerAuthResponse struct {
 	RefreshToken string            `json:"refresh_token"`
 	TokenType    string            `json:"token_type"`
 	ExpiresIn    int64             `json:"expires_in"`
+
+	// TwoFactorRequired tells the client to verify a TOTP code before using
+	// the account
+	TwoFactorRequired bool `json:"two_factor_required"`
 }
 
 // TokenRefreshRequest represents a token refresh request
@@ -42,6 +50,51 @@ type TokenRefreshRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+// CustomerSessionResponse represents a device signed in to a customer account
+type CustomerSessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  *string   `json:"user_agent,omitempty"`
+	IPAddress  *string   `json:"ip_address,omitempty"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// CustomerSessionRevokeResponse reports how many sessions were revoked
+type CustomerSessionRevokeResponse struct {
+	RevokedSessions int `json:"revoked_sessions"`
+}
+
+// CustomerTwoFactorCodeRequest carries a TOTP code or a recovery code
+type CustomerTwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required" binding:"required"`
+}
+
+// CustomerTwoFactorStatusResponse describes a customer's TOTP enrollment
+type CustomerTwoFactorStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RequiredByStorefront   bool       `json:"required_by_storefront"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+	LockedUntil            *time.Time `json:"locked_until,omitempty"`
+}
+
+// CustomerTwoFactorEnrollmentResponse carries the secret to load into an
+// authenticator app, as text and as an otpauth URI for a QR code
+type CustomerTwoFactorEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// CustomerTwoFactorVerifyResponse carries the tokens issued after a passed
+// second factor. Recovery codes are only shown once, when enrollment is confirmed.
+type CustomerTwoFactorVerifyResponse struct {
+	*CustomerAuthResponse
+	RecoveryCodes          []string `json:"recovery_codes,omitempty"`
+	RecoveryCodesRemaining int      `json:"recovery_codes_remaining"`
+}
+
 // ChangePasswordRequest represents a password change request
 type ChangePasswordRequest struct {
 	CustomerID      uuid.UUID `json:"customer_id" binding:"required"`
@@ -44,7 +44,6 @@ func ConvertToCustomerWarrantyRegistrationResponse(
 		Name:        product.Name,
 		Category:    "General", // Default category
 		Description: "",
-		ImageURL:    "https://example.com/images/" + product.SKU + ".jpg",
 	}
 
 	if product.Brand != nil {
@@ -94,14 +93,23 @@ func ConvertToCustomerWarrantyRegistrationResponse(
 		"Contact support if you have any questions",
 	}
 
+	activationDate := time.Now()
+	if warranty.ActivatedAt != nil {
+		activationDate = *warranty.ActivatedAt
+	}
+	var expiryDate time.Time
+	if warranty.ExpiryDate != nil {
+		expiryDate = *warranty.ExpiryDate
+	}
+
 	return CustomerWarrantyRegistrationResponse{
 		Success:          true,
 		RegistrationID:   uuid.New(), // Generate new registration ID
 		WarrantyID:       warranty.ID,
 		BarcodeValue:     warranty.BarcodeNumber,
 		Status:           string(warranty.Status),
-		ActivationDate:   time.Now(),
-		ExpiryDate:       *warranty.ExpiryDate,
+		ActivationDate:   activationDate,
+		ExpiryDate:       expiryDate,
 		WarrantyPeriod:   warrantyPeriod,
 		Product:          productInfo,
 		Customer:         customer,
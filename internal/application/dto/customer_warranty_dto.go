@@ -7,7 +7,8 @@ import (
 	"github.com/shopspring/decimal"
 )
 
-// CustomerWarrantyRegistrationRequest represents a customer warranty registration request
+// CustomerWarrantyRegistrationRequest represents a customer warranty registration
+// request. The warranty is registered to the signed-in customer.
 type CustomerWarrantyRegistrationRequest struct {
 	BarcodeValue    string     `json:"barcode_value" binding:"required" example:"WB-2024-001234567"`
 	ProductSKU      string     `json:"product_sku" binding:"required" example:"SKU-PHONE-001"`
@@ -17,7 +18,6 @@ type CustomerWarrantyRegistrationRequest struct {
 	RetailerName    string     `json:"retailer_name" binding:"required" example:"TechStore Inc"`
 	RetailerAddress string     `json:"retailer_address,omitempty" example:"123 Main St, City, State"`
 	InvoiceNumber   string     `json:"invoice_number,omitempty" example:"INV-2024-001"`
-	CustomerInfo    CustomerRegistrationInfo `json:"customer_info" binding:"required"`
 	ProofOfPurchase *ProofOfPurchaseInfo `json:"proof_of_purchase,omitempty"`
 }
 
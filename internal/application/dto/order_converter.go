@@ -0,0 +1,179 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+)
+
+// ConvertOrderToResponse converts an Order entity to OrderResponse DTO
+func ConvertOrderToResponse(order *entity.Order) *OrderResponse {
+	if order == nil {
+		return nil
+	}
+
+	response := &OrderResponse{
+		ID:                     order.ID.String(),
+		OrderNumber:            order.OrderNumber,
+		StorefrontID:           uuidPtrToStringPtr(order.StorefrontID),
+		CustomerID:             uuidPtrToStringPtr(order.CustomerID),
+		CustomerEmail:          order.CustomerEmail,
+		CustomerPhone:          order.CustomerPhone,
+		Status:                 order.Status.String(),
+		Subtotal:               order.Subtotal,
+		TaxAmount:              order.TaxAmount,
+		ShippingAmount:         order.ShippingAmount,
+		DiscountAmount:         order.DiscountAmount,
+		TotalAmount:            order.TotalAmount,
+		Currency:               order.Currency,
+		PaymentStatus:          string(order.PaymentStatus),
+		PaymentMethod:          order.PaymentMethod,
+		PaymentReference:       order.PaymentReference,
+		PaidAmount:             order.PaidAmount,
+		ShippingMethod:         order.ShippingMethod,
+		ShippingCarrier:        order.ShippingCarrier,
+		ShippingTrackingNumber: order.ShippingTrackingNumber,
+		Notes:                  order.Notes,
+		InternalNotes:          order.InternalNotes,
+		Tags:                   order.Tags,
+		Channel:                string(order.Channel),
+		ChannelReference:       order.ChannelReference,
+		OrderDate:              order.OrderDate,
+		ConfirmedAt:            order.ConfirmedAt,
+		ShippedAt:              order.ShippedAt,
+		DeliveredAt:            order.DeliveredAt,
+		CancelledAt:            order.CancelledAt,
+		ItemCount:              order.ItemCount,
+		CanCancel:              order.CanCancel,
+		NextActions:            order.NextActions,
+		CreatedAt:              order.CreatedAt,
+		UpdatedAt:              order.UpdatedAt,
+	}
+
+	if order.BillingAddressLine1 != nil {
+		response.BillingAddress = &OrderAddressResponse{
+			FirstName:     order.BillingFirstName,
+			LastName:      order.BillingLastName,
+			Company:       order.BillingCompany,
+			AddressLine1:  order.BillingAddressLine1,
+			AddressLine2:  order.BillingAddressLine2,
+			City:          order.BillingCity,
+			StateProvince: order.BillingStateProvince,
+			PostalCode:    order.BillingPostalCode,
+			Country:       order.BillingCountry,
+			Phone:         order.BillingPhone,
+		}
+	}
+
+	if order.ShippingAddressLine1 != nil {
+		response.ShippingAddress = &OrderAddressResponse{
+			FirstName:     order.ShippingFirstName,
+			LastName:      order.ShippingLastName,
+			Company:       order.ShippingCompany,
+			AddressLine1:  order.ShippingAddressLine1,
+			AddressLine2:  order.ShippingAddressLine2,
+			City:          order.ShippingCity,
+			StateProvince: order.ShippingStateProvince,
+			PostalCode:    order.ShippingPostalCode,
+			Country:       order.ShippingCountry,
+			Phone:         order.ShippingPhone,
+		}
+	}
+
+	if len(order.Items) > 0 {
+		response.Items = make([]OrderItemResponse, 0, len(order.Items))
+		for _, item := range order.Items {
+			response.Items = append(response.Items, *ConvertOrderItemToResponse(item))
+		}
+	}
+
+	return response
+}
+
+// ConvertOrderToCustomerResponse converts an Order entity to OrderResponse DTO
+// without the fields that are only meant for the seller
+func ConvertOrderToCustomerResponse(order *entity.Order) *OrderResponse {
+	response := ConvertOrderToResponse(order)
+	if response == nil {
+		return nil
+	}
+
+	response.InternalNotes = nil
+	response.Tags = nil
+	response.NextActions = nil
+
+	return response
+}
+
+// ConvertOrderItemToResponse converts an OrderItem entity to OrderItemResponse DTO
+func ConvertOrderItemToResponse(item *entity.OrderItem) *OrderItemResponse {
+	if item == nil {
+		return nil
+	}
+
+	return &OrderItemResponse{
+		ID:               item.ID.String(),
+		ProductID:        uuidPtrToStringPtr(item.ProductID),
+		ProductVariantID: uuidPtrToStringPtr(item.ProductVariantID),
+		ProductName:      item.ProductName,
+		ProductSKU:       item.ProductSKU,
+		VariantName:      item.VariantName,
+		UnitPrice:        item.UnitPrice,
+		Quantity:         item.Quantity,
+		TotalPrice:       item.TotalPrice,
+		ProductWeight:    item.ProductWeight,
+		ProductImageURL:  item.ProductImageURL,
+	}
+}
+
+// ConvertOrderStatusHistoryToResponse converts an OrderStatusHistory entity to its DTO
+func ConvertOrderStatusHistoryToResponse(history *entity.OrderStatusHistory) *OrderStatusHistoryResponse {
+	if history == nil {
+		return nil
+	}
+
+	response := &OrderStatusHistoryResponse{
+		ID:        history.ID.String(),
+		ToStatus:  history.ToStatus.String(),
+		Reason:    history.Reason,
+		Notes:     history.Notes,
+		ChangedBy: uuidPtrToStringPtr(history.ChangedBy),
+		ChangedAt: history.ChangedAt,
+	}
+
+	if history.FromStatus != nil {
+		fromStatus := history.FromStatus.String()
+		response.FromStatus = &fromStatus
+	}
+
+	return response
+}
+
+// ConvertOrderStatisticsToResponse converts repository order statistics to its DTO
+func ConvertOrderStatisticsToResponse(stats *repository.OrderStatistics) *OrderStatisticsResponse {
+	if stats == nil {
+		return nil
+	}
+
+	return &OrderStatisticsResponse{
+		TotalOrders:       stats.TotalOrders,
+		PendingOrders:     stats.PendingOrders,
+		ProcessingOrders:  stats.ProcessingOrders,
+		ShippedOrders:     stats.ShippedOrders,
+		DeliveredOrders:   stats.DeliveredOrders,
+		CancelledOrders:   stats.CancelledOrders,
+		TotalRevenue:      stats.TotalRevenue,
+		AverageOrderValue: stats.AverageOrderValue,
+		OrdersByStatus:    stats.OrdersByStatus,
+		OrdersByChannel:   stats.OrdersByChannel,
+	}
+}
+
+// uuidPtrToStringPtr converts an optional UUID to an optional string
+func uuidPtrToStringPtr(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
@@ -0,0 +1,205 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// OrderAddressRequest represents a billing or shipping address on an order
+type OrderAddressRequest struct {
+	FirstName     string `json:"first_name" validate:"required,max=100" example:"Budi"`
+	LastName      string `json:"last_name" validate:"omitempty,max=100" example:"Santoso"`
+	Company       string `json:"company" validate:"omitempty,max=255" example:"PT Maju Jaya"`
+	AddressLine1  string `json:"address_line_1" validate:"required,max=255" example:"Jl. Sudirman No. 1"`
+	AddressLine2  string `json:"address_line_2" validate:"omitempty,max=255" example:"Lantai 5"`
+	City          string `json:"city" validate:"required,max=100" example:"Jakarta"`
+	StateProvince string `json:"state_province" validate:"omitempty,max=100" example:"DKI Jakarta"`
+	PostalCode    string `json:"postal_code" validate:"omitempty,max=20" example:"10220"`
+	Country       string `json:"country" validate:"omitempty,len=2" example:"ID"`
+	Phone         string `json:"phone" validate:"omitempty,max=20" example:"+628123456789"`
+}
+
+// CreateOrderItemRequest represents a line item on a manually created order
+type CreateOrderItemRequest struct {
+	ProductID        *string          `json:"product_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ProductVariantID *string          `json:"product_variant_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	ProductName      string           `json:"product_name" validate:"required,max=255" example:"Wireless Earbuds"`
+	ProductSKU       string           `json:"product_sku" validate:"omitempty,max=100" example:"WE-001"`
+	VariantName      string           `json:"variant_name" validate:"omitempty,max=255" example:"Black"`
+	UnitPrice        decimal.Decimal  `json:"unit_price" validate:"required" example:"150000"`
+	Quantity         int              `json:"quantity" validate:"required,min=1" example:"2"`
+	ProductWeight    *decimal.Decimal `json:"product_weight,omitempty" example:"0.25"`
+	ProductImageURL  string           `json:"product_image_url" validate:"omitempty,url" example:"https://cdn.example.com/earbuds.jpg"`
+}
+
+// CreateOrderRequest represents a request to create an order manually from the admin panel
+type CreateOrderRequest struct {
+	CustomerID       *string                  `json:"customer_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440003"`
+	CustomerEmail    string                   `json:"customer_email" validate:"omitempty,email" example:"budi@example.com"`
+	CustomerPhone    string                   `json:"customer_phone" validate:"omitempty,max=20" example:"+628123456789"`
+	Items            []CreateOrderItemRequest `json:"items" validate:"required,min=1,dive"`
+	TaxAmount        decimal.Decimal          `json:"tax_amount" example:"33000"`
+	ShippingAmount   decimal.Decimal          `json:"shipping_amount" example:"15000"`
+	DiscountAmount   decimal.Decimal          `json:"discount_amount" example:"0"`
+	Currency         string                   `json:"currency" validate:"omitempty,len=3" example:"IDR"`
+	PaymentMethod    string                   `json:"payment_method" validate:"omitempty,max=50" example:"bank_transfer"`
+	ShippingMethod   string                   `json:"shipping_method" validate:"omitempty,max=100" example:"JNE REG"`
+	BillingAddress   *OrderAddressRequest     `json:"billing_address,omitempty"`
+	ShippingAddress  *OrderAddressRequest     `json:"shipping_address,omitempty"`
+	Notes            string                   `json:"notes" validate:"omitempty,max=2000" example:"Please wrap as a gift"`
+	InternalNotes    string                   `json:"internal_notes" validate:"omitempty,max=2000" example:"VIP customer"`
+	Tags             []string                 `json:"tags,omitempty" example:"vip,gift"`
+	Channel          string                   `json:"channel" validate:"omitempty,oneof=direct marketplace social mobile_app pos api" example:"direct"`
+	ChannelReference string                   `json:"channel_reference" validate:"omitempty,max=255" example:"TOKO-123456"`
+}
+
+// UpdateOrderStatusRequest represents a request to move an order to another status
+type UpdateOrderStatusRequest struct {
+	Status         string `json:"status" validate:"required,oneof=confirmed processing shipped delivered cancelled returned refunded" example:"shipped"`
+	Reason         string `json:"reason" validate:"omitempty,max=500" example:"Handed over to courier"`
+	Carrier        string `json:"carrier" validate:"omitempty,max=100" example:"JNE"`
+	TrackingNumber string `json:"tracking_number" validate:"omitempty,max=255" example:"JNE1234567890"`
+}
+
+// UpdateOrderPaymentRequest represents a request to update the payment state of an order
+type UpdateOrderPaymentRequest struct {
+	PaymentStatus    string `json:"payment_status" validate:"required,oneof=pending paid partially_paid refunded failed" example:"paid"`
+	PaymentMethod    string `json:"payment_method" validate:"omitempty,max=50" example:"bank_transfer"`
+	PaymentReference string `json:"payment_reference" validate:"omitempty,max=255" example:"TRX-20240101-0001"`
+}
+
+// CancelOrderRequest represents a request to cancel an order
+type CancelOrderRequest struct {
+	Reason string `json:"reason" validate:"omitempty,max=500" example:"Customer changed their mind"`
+}
+
+// OrderAddressResponse represents an address on an order response
+type OrderAddressResponse struct {
+	FirstName     *string `json:"first_name,omitempty" example:"Budi"`
+	LastName      *string `json:"last_name,omitempty" example:"Santoso"`
+	Company       *string `json:"company,omitempty" example:"PT Maju Jaya"`
+	AddressLine1  *string `json:"address_line_1,omitempty" example:"Jl. Sudirman No. 1"`
+	AddressLine2  *string `json:"address_line_2,omitempty" example:"Lantai 5"`
+	City          *string `json:"city,omitempty" example:"Jakarta"`
+	StateProvince *string `json:"state_province,omitempty" example:"DKI Jakarta"`
+	PostalCode    *string `json:"postal_code,omitempty" example:"10220"`
+	Country       *string `json:"country,omitempty" example:"ID"`
+	Phone         *string `json:"phone,omitempty" example:"+628123456789"`
+}
+
+// OrderItemResponse represents an order line item response
+type OrderItemResponse struct {
+	ID               string           `json:"id" example:"550e8400-e29b-41d4-a716-446655440004"`
+	ProductID        *string          `json:"product_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ProductVariantID *string          `json:"product_variant_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	ProductName      string           `json:"product_name" example:"Wireless Earbuds"`
+	ProductSKU       *string          `json:"product_sku,omitempty" example:"WE-001"`
+	VariantName      *string          `json:"variant_name,omitempty" example:"Black"`
+	UnitPrice        decimal.Decimal  `json:"unit_price" example:"150000"`
+	Quantity         int              `json:"quantity" example:"2"`
+	TotalPrice       decimal.Decimal  `json:"total_price" example:"300000"`
+	ProductWeight    *decimal.Decimal `json:"product_weight,omitempty" example:"0.25"`
+	ProductImageURL  *string          `json:"product_image_url,omitempty" example:"https://cdn.example.com/earbuds.jpg"`
+}
+
+// OrderResponse represents an order response
+type OrderResponse struct {
+	ID           string  `json:"id" example:"550e8400-e29b-41d4-a716-446655440005"`
+	OrderNumber  string  `json:"order_number" example:"ORD-20240101-000001"`
+	StorefrontID *string `json:"storefront_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440002"`
+	CustomerID   *string `json:"customer_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440003"`
+
+	CustomerEmail *string `json:"customer_email,omitempty" example:"budi@example.com"`
+	CustomerPhone *string `json:"customer_phone,omitempty" example:"+628123456789"`
+
+	Status string `json:"status" example:"pending"`
+
+	// Financial information
+	Subtotal       decimal.Decimal `json:"subtotal" example:"300000"`
+	TaxAmount      decimal.Decimal `json:"tax_amount" example:"33000"`
+	ShippingAmount decimal.Decimal `json:"shipping_amount" example:"15000"`
+	DiscountAmount decimal.Decimal `json:"discount_amount" example:"0"`
+	TotalAmount    decimal.Decimal `json:"total_amount" example:"348000"`
+	Currency       string          `json:"currency" example:"IDR"`
+
+	// Payment information
+	PaymentStatus    string          `json:"payment_status" example:"pending"`
+	PaymentMethod    *string         `json:"payment_method,omitempty" example:"bank_transfer"`
+	PaymentReference *string         `json:"payment_reference,omitempty" example:"TRX-20240101-0001"`
+	PaidAmount       decimal.Decimal `json:"paid_amount" example:"348000"`
+
+	// Shipping information
+	ShippingMethod         *string `json:"shipping_method,omitempty" example:"JNE REG"`
+	ShippingCarrier        *string `json:"shipping_carrier,omitempty" example:"JNE"`
+	ShippingTrackingNumber *string `json:"shipping_tracking_number,omitempty" example:"JNE1234567890"`
+
+	BillingAddress  *OrderAddressResponse `json:"billing_address,omitempty"`
+	ShippingAddress *OrderAddressResponse `json:"shipping_address,omitempty"`
+
+	Notes            *string  `json:"notes,omitempty" example:"Please wrap as a gift"`
+	InternalNotes    *string  `json:"internal_notes,omitempty" example:"VIP customer"`
+	Tags             []string `json:"tags,omitempty" example:"vip,gift"`
+	Channel          string   `json:"channel" example:"direct"`
+	ChannelReference *string  `json:"channel_reference,omitempty" example:"TOKO-123456"`
+
+	Items []OrderItemResponse `json:"items,omitempty"`
+
+	// Important dates
+	OrderDate   time.Time  `json:"order_date" example:"2024-01-01T10:00:00Z"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty" example:"2024-01-01T11:00:00Z"`
+	ShippedAt   *time.Time `json:"shipped_at,omitempty" example:"2024-01-02T09:00:00Z"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty" example:"2024-01-04T15:00:00Z"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+
+	// Computed fields
+	ItemCount   int      `json:"item_count" example:"2"`
+	CanCancel   bool     `json:"can_cancel" example:"true"`
+	NextActions []string `json:"next_actions" example:"confirm,cancel"`
+
+	CreatedAt time.Time `json:"created_at" example:"2024-01-01T10:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2024-01-01T10:00:00Z"`
+}
+
+// OrderListResponse represents a paginated list of orders
+type OrderListResponse struct {
+	Orders     []OrderResponse    `json:"orders"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+// OrderStatusHistoryResponse represents a single order status change
+type OrderStatusHistoryResponse struct {
+	ID         string    `json:"id" example:"550e8400-e29b-41d4-a716-446655440006"`
+	FromStatus *string   `json:"from_status,omitempty" example:"pending"`
+	ToStatus   string    `json:"to_status" example:"confirmed"`
+	Reason     *string   `json:"reason,omitempty" example:"Payment received"`
+	Notes      *string   `json:"notes,omitempty"`
+	ChangedBy  *string   `json:"changed_by,omitempty" example:"550e8400-e29b-41d4-a716-446655440007"`
+	ChangedAt  time.Time `json:"changed_at" example:"2024-01-01T11:00:00Z"`
+}
+
+// OrderTrackingResponse represents the customer-facing status of an order
+type OrderTrackingResponse struct {
+	OrderNumber            string                       `json:"order_number" example:"ORD-20240101-000001"`
+	Status                 string                       `json:"status" example:"shipped"`
+	PaymentStatus          string                       `json:"payment_status" example:"paid"`
+	ShippingCarrier        *string                      `json:"shipping_carrier,omitempty" example:"JNE"`
+	ShippingTrackingNumber *string                      `json:"shipping_tracking_number,omitempty" example:"JNE1234567890"`
+	ShippedAt              *time.Time                   `json:"shipped_at,omitempty" example:"2024-01-02T09:00:00Z"`
+	DeliveredAt            *time.Time                   `json:"delivered_at,omitempty"`
+	History                []OrderStatusHistoryResponse `json:"history"`
+}
+
+// OrderStatisticsResponse represents order statistics
+type OrderStatisticsResponse struct {
+	TotalOrders       int64            `json:"total_orders" example:"120"`
+	PendingOrders     int64            `json:"pending_orders" example:"10"`
+	ProcessingOrders  int64            `json:"processing_orders" example:"15"`
+	ShippedOrders     int64            `json:"shipped_orders" example:"20"`
+	DeliveredOrders   int64            `json:"delivered_orders" example:"70"`
+	CancelledOrders   int64            `json:"cancelled_orders" example:"5"`
+	TotalRevenue      float64          `json:"total_revenue" example:"45000000"`
+	AverageOrderValue float64          `json:"average_order_value" example:"375000"`
+	OrdersByStatus    map[string]int64 `json:"orders_by_status"`
+	OrdersByChannel   map[string]int64 `json:"orders_by_channel"`
+}
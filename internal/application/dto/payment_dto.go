@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// OrderPaymentRequest represents a request to pay for a pending order. Orders
+// placed with the ewallet payment method get an e-wallet charge, every other
+// method a hosted invoice.
+type OrderPaymentRequest struct {
+	OrderID string `json:"order_id" validate:"required,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	// CustomerEmail must match the order's email when a guest pays
+	CustomerEmail      string `json:"customer_email" validate:"omitempty,email" example:"budi@example.com"`
+	EWalletChannel     string `json:"ewallet_channel" validate:"omitempty,max=20" example:"SHOPEEPAY"`
+	MobileNumber       string `json:"mobile_number" validate:"omitempty,max=20" example:"+628123456789"`
+	SuccessRedirectURL string `json:"success_redirect_url" validate:"omitempty,url" example:"https://shop.example.com/orders/success"`
+	FailureRedirectURL string `json:"failure_redirect_url" validate:"omitempty,url" example:"https://shop.example.com/orders/failed"`
+}
+
+// OrderPaymentResponse represents the payment a customer completes at the gateway
+type OrderPaymentResponse struct {
+	OrderID           string          `json:"order_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OrderNumber       string          `json:"order_number" example:"ORD-20240101-000001"`
+	Gateway           string          `json:"gateway" example:"xendit"`
+	PaymentReference  string          `json:"payment_reference" example:"579c8d61f23fa4ca35e52da4"`
+	PaymentStatus     string          `json:"payment_status" example:"pending"`
+	Amount            decimal.Decimal `json:"amount" example:"150000"`
+	Currency          string          `json:"currency" example:"IDR"`
+	CheckoutURL       string          `json:"checkout_url,omitempty" example:"https://checkout.xendit.co/web/579c8d61f23fa4ca35e52da4"`
+	MobileDeeplinkURL string          `json:"mobile_deeplink_url,omitempty"`
+	ExpiresAt         *time.Time      `json:"expires_at,omitempty" example:"2024-01-02T12:00:00Z"`
+}
+
+// PaymentCallbackResponse represents the outcome of a payment gateway callback
+type PaymentCallbackResponse struct {
+	OrderID       string `json:"order_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	PaymentStatus string `json:"payment_status,omitempty" example:"paid"`
+	// Applied is false when the callback repeated or was superseded by an earlier one
+	Applied bool `json:"applied" example:"true"`
+}
@@ -2,14 +2,17 @@ package dto
 
 import (
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/kirimku/smartseller-backend/internal/domain/entity"
 	"github.com/shopspring/decimal"
 )
 
-// ConvertWarrantyBarcodeToPublicValidationResponse converts a WarrantyBarcode entity to PublicWarrantyValidationResponse
-func ConvertWarrantyBarcodeToPublicValidationResponse(barcode *entity.WarrantyBarcode, product *entity.Product) *PublicWarrantyValidationResponse {
+// ConvertWarrantyBarcodeToPublicValidationResponse converts a WarrantyBarcode entity to PublicWarrantyValidationResponse.
+// The owner, when known, is masked.
+func ConvertWarrantyBarcodeToPublicValidationResponse(barcode *entity.WarrantyBarcode, product *entity.Product, owner *entity.Customer) *PublicWarrantyValidationResponse {
 	if barcode == nil {
 		return &PublicWarrantyValidationResponse{
 			Valid:          false,
@@ -22,21 +25,13 @@ func ConvertWarrantyBarcodeToPublicValidationResponse(barcode *entity.WarrantyBa
 	barcode.ComputeFields() // Ensure computed fields are updated
 
 	response := &PublicWarrantyValidationResponse{
-		Valid:          barcode.IsActive && !barcode.IsExpired,
+		Valid:          barcode.IsValidForClaim(),
 		BarcodeValue:   barcode.BarcodeNumber,
 		Status:         barcode.Status.String(),
+		Message:        PublicWarrantyStatusMessage(barcode),
 		ValidationTime: time.Now(),
 	}
 
-	// Set appropriate message based on status
-	if !barcode.IsActive {
-		response.Message = "Warranty barcode is inactive"
-	} else if barcode.IsExpired {
-		response.Message = "Warranty has expired"
-	} else {
-		response.Message = "Warranty is valid and active"
-	}
-
 	// Convert product information if available
 	if product != nil {
 		response.Product = ConvertProductToPublicInfo(product)
@@ -44,12 +39,13 @@ func ConvertWarrantyBarcodeToPublicValidationResponse(barcode *entity.WarrantyBa
 
 	// Convert warranty information
 	response.Warranty = ConvertWarrantyBarcodeToPublicInfo(barcode)
+	response.Warranty.RegisteredTo = ConvertCustomerToPublicOwnerInfo(owner)
 
-	// Convert coverage information (mock data for now)
+	// Convert coverage information
 	response.Coverage = &PublicWarrantyCoverage{
 		CoverageType:        "comprehensive",
 		CoveredComponents:   []string{"hardware", "software", "battery", "screen"},
-		ExcludedComponents:  []string{"water_damage", "physical_abuse"},
+		ExcludedComponents:  PublicWarrantyExcludedIssues,
 		RepairCoverage:      true,
 		ReplacementCoverage: true,
 		LaborCoverage:       true,
@@ -60,6 +56,9 @@ func ConvertWarrantyBarcodeToPublicValidationResponse(barcode *entity.WarrantyBa
 	return response
 }
 
+// PublicWarrantyExcludedIssues are the issue types no warranty covers
+var PublicWarrantyExcludedIssues = []string{"water_damage", "physical_abuse"}
+
 // ConvertProductToPublicInfo converts a Product entity to PublicProductInfo
 func ConvertProductToPublicInfo(product *entity.Product) *PublicProductInfo {
 	if product == nil {
@@ -84,13 +83,80 @@ func ConvertProductToPublicInfo(product *entity.Product) *PublicProductInfo {
 		info.Description = product.Description
 	}
 
-	// Mock image URL for now
-	imageURL := "https://example.com/images/" + product.SKU + ".jpg"
-	info.ImageURL = &imageURL
+	return info
+}
+
+// PublicWarrantyStatusMessage explains to the holder of a barcode whether it
+// can back a warranty claim
+func PublicWarrantyStatusMessage(barcode *entity.WarrantyBarcode) string {
+	switch {
+	case barcode.IsValidForClaim():
+		return "Warranty is valid and active"
+	case barcode.Status == entity.BarcodeStatusExpired || barcode.IsExpired:
+		return "Warranty has expired"
+	case barcode.Status == entity.BarcodeStatusUsed:
+		return "Warranty has already been used"
+	default:
+		return "Warranty has not been activated"
+	}
+}
+
+// ConvertCustomerToPublicOwnerInfo converts a warranty owner to their masked public details
+func ConvertCustomerToPublicOwnerInfo(customer *entity.Customer) *PublicWarrantyOwnerInfo {
+	if customer == nil {
+		return nil
+	}
+
+	info := &PublicWarrantyOwnerInfo{}
+	var names []string
+	for _, name := range []*string{customer.FirstName, customer.LastName} {
+		if name != nil && strings.TrimSpace(*name) != "" {
+			names = append(names, maskWord(strings.TrimSpace(*name)))
+		}
+	}
+	info.Name = strings.Join(names, " ")
+	if customer.Email != nil {
+		info.Email = MaskEmail(*customer.Email)
+	}
+	if customer.Phone != nil {
+		info.Phone = MaskTail(*customer.Phone, 4)
+	}
 
 	return info
 }
 
+// MaskEmail keeps the first letter of the mailbox and the domain of an email
+// address, e.g. j***@example.com
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return MaskTail(email, 0)
+	}
+	return maskWord(email[:at]) + email[at:]
+}
+
+// MaskTail replaces all but the last visible characters of a value with
+// asterisks
+func MaskTail(value string, visible int) string {
+	runes := []rune(value)
+	if visible < 0 {
+		visible = 0
+	}
+	if visible > len(runes)/2 {
+		visible = len(runes) / 2
+	}
+	return strings.Repeat("*", len(runes)-visible) + string(runes[len(runes)-visible:])
+}
+
+// maskWord keeps the first letter of a word, e.g. J***
+func maskWord(word string) string {
+	first, size := utf8.DecodeRuneInString(word)
+	if size == 0 {
+		return ""
+	}
+	return string(first) + "***"
+}
+
 // ConvertWarrantyBarcodeToPublicInfo converts a WarrantyBarcode entity to PublicWarrantyInfo
 func ConvertWarrantyBarcodeToPublicInfo(barcode *entity.WarrantyBarcode) *PublicWarrantyInfo {
 	if barcode == nil {
@@ -105,7 +171,8 @@ func ConvertWarrantyBarcodeToPublicInfo(barcode *entity.WarrantyBarcode) *Public
 		Status:       barcode.Status.String(),
 		IsActive:     barcode.IsActive,
 		IsExpired:    barcode.IsExpired,
-		CanClaim:     barcode.IsActive && !barcode.IsExpired,
+		CanClaim:     barcode.IsValidForClaim(),
+		PurchaseDate: barcode.PurchaseDate,
 	}
 
 	// Handle activation date
@@ -141,7 +208,8 @@ func ConvertWarrantyBarcodeToPublicInfo(barcode *entity.WarrantyBarcode) *Public
 	return info
 }
 
-// ConvertWarrantyBarcodesToPublicLookupResponse converts multiple WarrantyBarcode entities to PublicWarrantyLookupResponse
+// ConvertWarrantyBarcodesToPublicLookupResponse converts multiple WarrantyBarcode entities to PublicWarrantyLookupResponse.
+// Barcode values are masked since the lookup does not prove ownership.
 func ConvertWarrantyBarcodesToPublicLookupResponse(barcodes []*entity.WarrantyBarcode, product *entity.Product) *PublicWarrantyLookupResponse {
 	response := &PublicWarrantyLookupResponse{
 		Found:      len(barcodes) > 0,
@@ -157,6 +225,7 @@ func ConvertWarrantyBarcodesToPublicLookupResponse(barcodes []*entity.WarrantyBa
 	// Convert warranties
 	for _, barcode := range barcodes {
 		if warrantyInfo := ConvertWarrantyBarcodeToPublicInfo(barcode); warrantyInfo != nil {
+			warrantyInfo.BarcodeValue = MaskTail(warrantyInfo.BarcodeValue, 4)
 			response.Warranties = append(response.Warranties, *warrantyInfo)
 		}
 	}
@@ -176,8 +245,9 @@ func ConvertWarrantyBarcodesToPublicLookupResponse(barcodes []*entity.WarrantyBa
 	return response
 }
 
-// ConvertProductToPublicInfoResponse converts a Product entity to PublicProductInfoResponse
-func ConvertProductToPublicInfoResponse(product *entity.Product) *PublicProductInfoResponse {
+// ConvertProductToPublicInfoResponse converts a Product entity to PublicProductInfoResponse.
+// The warranty a barcode carries, when given, is offered as the product's warranty option.
+func ConvertProductToPublicInfoResponse(product *entity.Product, barcode *entity.WarrantyBarcode) *PublicProductInfoResponse {
 	if product == nil {
 		return nil
 	}
@@ -187,73 +257,27 @@ func ConvertProductToPublicInfoResponse(product *entity.Product) *PublicProductI
 		RetrievedAt: time.Now(),
 	}
 
-	// Add warranty options (mock data for now)
-	response.WarrantyOptions = []PublicWarrantyOption{
-		{
-			Type:        "standard",
-			Name:        "Standard Warranty",
-			Duration:    "24 months",
-			Coverage:    "Hardware defects and manufacturing issues",
-			Price:       &decimal.Zero,
-			IsDefault:   true,
-			Description: "Covers all manufacturing defects for 2 years",
-		},
-		{
-			Type:        "extended",
-			Name:        "Extended Warranty",
-			Duration:    "36 months",
-			Coverage:    "Hardware defects, manufacturing issues, and accidental damage",
-			Price:       func() *decimal.Decimal { d := decimal.NewFromFloat(99.99); return &d }(),
-			IsDefault:   false,
-			Description: "Extended coverage including accidental damage for 3 years",
-		},
-	}
-
-	// Add specifications (mock data for now)
-	response.Specifications = map[string]interface{}{
-		"screen_size": "6.7 inches",
-		"storage":     "256GB",
-		"color":       "Space Gray",
-		"weight":      "240g",
-		"dimensions":  "160.8 x 78.1 x 7.4 mm",
-	}
-
-	// Add documentation (mock data for now)
-	response.Documentation = []PublicDocumentInfo{
-		{
-			Type:        "manual",
-			Title:       "User Manual",
-			Description: "Complete user guide and setup instructions",
-			URL:         "https://example.com/docs/user-manual.pdf",
-			Language:    "en",
-			FileSize:    "2.5MB",
-		},
-		{
-			Type:        "quick_start",
-			Title:       "Quick Start Guide",
-			Description: "Quick setup and basic usage guide",
-			URL:         "https://example.com/docs/quick-start.pdf",
-			Language:    "en",
-			FileSize:    "1.2MB",
-		},
-	}
-
-	// Add support information (mock data for now)
-	response.SupportInfo = &PublicSupportInfo{
-		SupportEmail:     "support@techbrand.com",
-		SupportPhone:     "+1-800-123-4567",
-		SupportHours:     "Mon-Fri 9AM-6PM EST",
-		OnlineSupport:    "https://support.techbrand.com",
-		ChatSupport:      true,
-		SupportLanguages: []string{"en", "es", "fr"},
-		FAQ:              "https://support.techbrand.com/faq",
+	if barcode != nil && barcode.WarrantyPeriodMonths > 0 {
+		duration := fmt.Sprintf("%d months", barcode.WarrantyPeriodMonths)
+		response.WarrantyOptions = []PublicWarrantyOption{
+			{
+				Type:        "standard",
+				Name:        "Standard Warranty",
+				Duration:    duration,
+				Coverage:    "Hardware defects and manufacturing issues",
+				Price:       &decimal.Zero,
+				IsDefault:   true,
+				Description: "Covers manufacturing defects for " + duration + " from purchase",
+			},
+		}
 	}
 
 	return response
 }
 
-// ConvertToCoverageCheckResponse creates a PublicWarrantyCoverageCheckResponse
-func ConvertToCoverageCheckResponse(barcode *entity.WarrantyBarcode, issueType, issueCategory, description string, covered bool) *PublicWarrantyCoverageCheckResponse {
+// ConvertToCoverageCheckResponse creates a PublicWarrantyCoverageCheckResponse. The reason
+// explains an issue that is not covered.
+func ConvertToCoverageCheckResponse(barcode *entity.WarrantyBarcode, issueType, issueCategory, description string, covered bool, reason string) *PublicWarrantyCoverageCheckResponse {
 	response := &PublicWarrantyCoverageCheckResponse{
 		Covered:      covered,
 		BarcodeValue: barcode.BarcodeNumber,
@@ -275,9 +299,7 @@ func ConvertToCoverageCheckResponse(barcode *entity.WarrantyBarcode, issueType,
 		}
 	} else {
 		response.CoverageType = "not_covered"
-		estimatedCost := decimal.NewFromFloat(150.00)
-		response.EstimatedCost = &estimatedCost
-		response.Message = "This issue is not covered under your warranty"
+		response.Message = reason
 		response.NextSteps = []string{
 			"Contact customer service for paid repair options",
 			"Get quote from authorized service center",
@@ -292,7 +314,7 @@ func ConvertToCoverageCheckResponse(barcode *entity.WarrantyBarcode, issueType,
 	response.Coverage = &PublicWarrantyCoverage{
 		CoverageType:        "comprehensive",
 		CoveredComponents:   []string{"hardware", "software", "battery", "screen"},
-		ExcludedComponents:  []string{"water_damage", "physical_abuse"},
+		ExcludedComponents:  PublicWarrantyExcludedIssues,
 		RepairCoverage:      true,
 		ReplacementCoverage: true,
 		LaborCoverage:       true,
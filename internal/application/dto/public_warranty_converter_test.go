@@ -0,0 +1,102 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+)
+
+func TestMaskEmail(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		want  string
+	}{
+		{"regular address", "jane.doe@example.com", "j***@example.com"},
+		{"single letter mailbox", "j@example.com", "j***@example.com"},
+		{"multibyte mailbox", "élodie@example.fr", "é***@example.fr"},
+		{"empty mailbox", "@example.com", "************"},
+		{"no at sign", "janedoe", "*******"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MaskEmail(tt.email))
+		})
+	}
+}
+
+func TestMaskTail(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		visible int
+		want    string
+	}{
+		{"phone number", "081234567890", 4, "********7890"},
+		{"short value keeps at most half", "123", 4, "**3"},
+		{"single character", "1", 4, "*"},
+		{"multibyte", "日本語テキスト", 2, "*****スト"},
+		{"nothing visible", "secret", 0, "******"},
+		{"negative visible", "secret", -1, "******"},
+		{"empty", "", 4, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MaskTail(tt.value, tt.visible))
+		})
+	}
+}
+
+func TestMaskWord(t *testing.T) {
+	assert.Equal(t, "J***", maskWord("Jane"))
+	assert.Equal(t, "J***", maskWord("J"))
+	assert.Equal(t, "Ö***", maskWord("Özil"))
+	assert.Equal(t, "", maskWord(""))
+}
+
+func TestConvertCustomerToPublicOwnerInfo(t *testing.T) {
+	first, last, blank := "Jane", "Doe", "  "
+	email, phone := "jane@example.com", "081234567890"
+
+	info := ConvertCustomerToPublicOwnerInfo(&entity.Customer{FirstName: &first, LastName: &last, Email: &email, Phone: &phone})
+	assert.Equal(t, "J*** D***", info.Name)
+	assert.Equal(t, "j***@example.com", info.Email)
+	assert.Equal(t, "********7890", info.Phone)
+
+	info = ConvertCustomerToPublicOwnerInfo(&entity.Customer{FirstName: &first, LastName: &blank})
+	assert.Equal(t, "J***", info.Name)
+	assert.Empty(t, info.Email)
+
+	assert.Nil(t, ConvertCustomerToPublicOwnerInfo(nil))
+}
+
+func TestPublicWarrantyStatusMessage(t *testing.T) {
+	past := time.Now().AddDate(0, -1, 0)
+	future := time.Now().AddDate(1, 0, 0)
+
+	tests := []struct {
+		name    string
+		barcode *entity.WarrantyBarcode
+		want    string
+	}{
+		{"activated", &entity.WarrantyBarcode{Status: entity.BarcodeStatusActivated, ExpiryDate: &future}, "Warranty is valid and active"},
+		{"activated past expiry", &entity.WarrantyBarcode{Status: entity.BarcodeStatusActivated, ExpiryDate: &past}, "Warranty has expired"},
+		{"expired", &entity.WarrantyBarcode{Status: entity.BarcodeStatusExpired}, "Warranty has expired"},
+		{"flagged expired", &entity.WarrantyBarcode{Status: entity.BarcodeStatusUsed, IsExpired: true}, "Warranty has expired"},
+		{"used", &entity.WarrantyBarcode{Status: entity.BarcodeStatusUsed}, "Warranty has already been used"},
+		{"distributed", &entity.WarrantyBarcode{Status: entity.BarcodeStatusDistributed}, "Warranty has not been activated"},
+		{"generated", &entity.WarrantyBarcode{Status: entity.BarcodeStatusGenerated}, "Warranty has not been activated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, PublicWarrantyStatusMessage(tt.barcode))
+		})
+	}
+}
@@ -50,6 +50,17 @@ type PublicWarrantyInfo struct {
 	WarrantyPeriod  string     `json:"warranty_period" example:"24 months"`
 	IsExpired       bool       `json:"is_expired" example:"false"`
 	CanClaim        bool       `json:"can_claim" example:"true"`
+	PurchaseDate    *time.Time `json:"purchase_date,omitempty" example:"2024-01-10T00:00:00Z"`
+	RegisteredTo    *PublicWarrantyOwnerInfo `json:"registered_to,omitempty"`
+}
+
+// PublicWarrantyOwnerInfo represents the customer a warranty is registered
+// to. Every field is masked, so anyone holding the barcode can recognise the
+// owner without learning their contact details.
+type PublicWarrantyOwnerInfo struct {
+	Name  string `json:"name,omitempty" example:"J*** D***"`
+	Email string `json:"email,omitempty" example:"j***@example.com"`
+	Phone string `json:"phone,omitempty" example:"*********4567"`
 }
 
 // PublicWarrantyCoverage represents warranty coverage details for public API
@@ -72,7 +83,7 @@ type PublicWarrantyLookupRequest struct {
 	ProductSKU      string `json:"product_sku" validate:"required,max=100" example:"SKU-PHONE-001"`
 	SerialNumber    string `json:"serial_number,omitempty" validate:"omitempty,max=100" example:"SN123456789"`
 	PurchaseDate    string `json:"purchase_date,omitempty" validate:"omitempty" example:"2024-01-10"`
-	CustomerEmail   string `json:"customer_email,omitempty" validate:"omitempty,email" example:"customer@example.com"`
+	CustomerEmail   string `json:"customer_email" validate:"required,email" example:"customer@example.com"`
 }
 
 // PublicWarrantyLookupResponse represents the response for warranty lookup
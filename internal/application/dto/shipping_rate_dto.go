@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+)
+
+// ShippingRateRequest represents a request for the shipping options of a cart
+type ShippingRateRequest struct {
+	Items    []CheckoutItemRequest `json:"items" validate:"required,min=1,dive"`
+	Province string                `json:"province" validate:"required,max=100" example:"Bali"`
+	City     string                `json:"city" validate:"required,max=100" example:"Badung"`
+	District string                `json:"district" validate:"required,max=100" example:"Abiansemal"`
+}
+
+// ShippingRateResponse represents the shipping options of a cart, cheapest first
+type ShippingRateResponse struct {
+	Weight decimal.Decimal      `json:"weight" example:"1.2"` // in kg
+	Rates  []model.ShippingRate `json:"rates"`
+}
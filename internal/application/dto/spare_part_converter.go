@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+)
+
+// ConvertSparePartToResponse converts a spare part to its response
+func ConvertSparePartToResponse(part *entity.SparePart) *SparePartResponse {
+	return &SparePartResponse{
+		ID:                part.ID.String(),
+		PartNumber:        part.PartNumber,
+		Name:              part.Name,
+		Description:       part.Description,
+		Supplier:          part.Supplier,
+		UnitCost:          part.UnitCost,
+		QuantityOnHand:    part.QuantityOnHand,
+		QuantityReserved:  part.QuantityReserved,
+		QuantityAvailable: part.QuantityAvailable(),
+		ReorderThreshold:  part.ReorderThreshold,
+		ReorderQuantity:   part.ReorderQuantity,
+		NeedsReorder:      part.NeedsReorder(),
+		StockValue:        part.UnitCost.Mul(decimal.NewFromInt(int64(part.QuantityOnHand))),
+		IsActive:          part.IsActive,
+		CreatedAt:         part.CreatedAt,
+		UpdatedAt:         part.UpdatedAt,
+	}
+}
+
+// ConvertSparePartsToResponses converts spare parts to responses
+func ConvertSparePartsToResponses(parts []*entity.SparePart) []SparePartResponse {
+	responses := make([]SparePartResponse, 0, len(parts))
+	for _, part := range parts {
+		responses = append(responses, *ConvertSparePartToResponse(part))
+	}
+	return responses
+}
+
+// ConvertSparePartReservationsToResponses converts the spare parts of a repair ticket to responses
+func ConvertSparePartReservationsToResponses(reservations []*entity.SparePartReservation) []SparePartReservationResponse {
+	responses := make([]SparePartReservationResponse, 0, len(reservations))
+	for _, reservation := range reservations {
+		responses = append(responses, SparePartReservationResponse{
+			ID:          reservation.ID.String(),
+			SparePartID: reservation.SparePartID.String(),
+			PartNumber:  reservation.PartNumber,
+			PartName:    reservation.PartName,
+			Quantity:    reservation.Quantity,
+			Status:      reservation.Status.String(),
+			UnitCost:    reservation.UnitCost,
+			ReservedAt:  reservation.ReservedAt,
+			ConsumedAt:  reservation.ConsumedAt,
+			ReleasedAt:  reservation.ReleasedAt,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SparePartCreateRequest represents a request to add a part to the spare parts inventory
+type SparePartCreateRequest struct {
+	PartNumber       string          `json:"part_number" validate:"required,max=100" example:"MB-X200"`
+	Name             string          `json:"name" validate:"required,max=255" example:"Motherboard X200"`
+	Description      *string         `json:"description,omitempty" example:"Replacement board revision B"`
+	Supplier         *string         `json:"supplier,omitempty" validate:"omitempty,max=255" example:"Acme Parts"`
+	UnitCost         decimal.Decimal `json:"unit_cost" validate:"min=0" example:"85.50"`
+	QuantityOnHand   int             `json:"quantity_on_hand" validate:"min=0" example:"10"`
+	ReorderThreshold int             `json:"reorder_threshold" validate:"min=0" example:"3"`
+	ReorderQuantity  int             `json:"reorder_quantity" validate:"min=0" example:"10"`
+}
+
+// SparePartUpdateRequest represents a request to update the catalogue details of a spare part
+type SparePartUpdateRequest struct {
+	PartNumber       *string          `json:"part_number,omitempty" validate:"omitempty,max=100" example:"MB-X200"`
+	Name             *string          `json:"name,omitempty" validate:"omitempty,max=255" example:"Motherboard X200"`
+	Description      *string          `json:"description,omitempty" example:"Replacement board revision B"`
+	Supplier         *string          `json:"supplier,omitempty" validate:"omitempty,max=255" example:"Acme Parts"`
+	UnitCost         *decimal.Decimal `json:"unit_cost,omitempty" validate:"omitempty,min=0" example:"85.50"`
+	ReorderThreshold *int             `json:"reorder_threshold,omitempty" validate:"omitempty,min=0" example:"3"`
+	ReorderQuantity  *int             `json:"reorder_quantity,omitempty" validate:"omitempty,min=0" example:"10"`
+	IsActive         *bool            `json:"is_active,omitempty" example:"true"`
+}
+
+// SparePartStockAdjustmentRequest represents stock received (positive
+// quantity) or written off (negative quantity). The unit cost of received
+// stock updates the part's weighted average cost.
+type SparePartStockAdjustmentRequest struct {
+	Quantity int              `json:"quantity" validate:"required,ne=0" example:"5"`
+	UnitCost *decimal.Decimal `json:"unit_cost,omitempty" validate:"omitempty,min=0" example:"82.00"`
+	Reason   string           `json:"reason" validate:"required,max=255" example:"Received purchase order PO-1042"`
+}
+
+// SparePartResponse represents a part in the spare parts inventory
+type SparePartResponse struct {
+	ID                string          `json:"id" example:"550e8400-e29b-41d4-a716-446655440010"`
+	PartNumber        string          `json:"part_number" example:"MB-X200"`
+	Name              string          `json:"name" example:"Motherboard X200"`
+	Description       *string         `json:"description,omitempty" example:"Replacement board revision B"`
+	Supplier          *string         `json:"supplier,omitempty" example:"Acme Parts"`
+	UnitCost          decimal.Decimal `json:"unit_cost" example:"85.50"`
+	QuantityOnHand    int             `json:"quantity_on_hand" example:"10"`
+	QuantityReserved  int             `json:"quantity_reserved" example:"2"`
+	QuantityAvailable int             `json:"quantity_available" example:"8"`
+	ReorderThreshold  int             `json:"reorder_threshold" example:"3"`
+	ReorderQuantity   int             `json:"reorder_quantity" example:"10"`
+	NeedsReorder      bool            `json:"needs_reorder" example:"false"`
+	StockValue        decimal.Decimal `json:"stock_value" example:"855.00"`
+	IsActive          bool            `json:"is_active" example:"true"`
+	CreatedAt         time.Time       `json:"created_at" example:"2024-01-15T10:30:00Z"`
+	UpdatedAt         time.Time       `json:"updated_at" example:"2024-01-15T10:30:00Z"`
+}
+
+// SparePartListResponse represents a paginated list of spare parts
+type SparePartListResponse struct {
+	Parts      []SparePartResponse `json:"parts"`
+	Pagination PaginationResponse  `json:"pagination"`
+}
+
+// SparePartReservationResponse represents a spare part needed by a repair ticket
+type SparePartReservationResponse struct {
+	ID          string           `json:"id" example:"550e8400-e29b-41d4-a716-446655440011"`
+	SparePartID string           `json:"spare_part_id" example:"550e8400-e29b-41d4-a716-446655440010"`
+	PartNumber  string           `json:"part_number" example:"MB-X200"`
+	PartName    string           `json:"part_name" example:"Motherboard X200"`
+	Quantity    int              `json:"quantity" example:"1"`
+	Status      string           `json:"status" example:"reserved"`
+	UnitCost    *decimal.Decimal `json:"unit_cost,omitempty" example:"85.50"`
+	ReservedAt  *time.Time       `json:"reserved_at,omitempty" example:"2024-01-16T09:00:00Z"`
+	ConsumedAt  *time.Time       `json:"consumed_at,omitempty"`
+	ReleasedAt  *time.Time       `json:"released_at,omitempty"`
+}
@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+)
+
+// ConvertTechnicianToResponse converts a technician to its response
+func ConvertTechnicianToResponse(technician *entity.Technician) *TechnicianResponse {
+	response := &TechnicianResponse{
+		ID:                   technician.ID.String(),
+		UserID:               technician.UserID.String(),
+		Name:                 technician.Name,
+		Email:                technician.Email,
+		MaxConcurrentTickets: technician.MaxConcurrentTickets,
+		WorkingHours:         make([]WorkShiftDTO, 0, len(technician.WorkingHours)),
+		Timezone:             technician.Timezone,
+		IsActive:             technician.IsActive,
+		Skills:               make([]TechnicianSkillResponse, 0, len(technician.Skills)),
+		CreatedAt:            technician.CreatedAt,
+		UpdatedAt:            technician.UpdatedAt,
+	}
+
+	for _, shift := range technician.WorkingHours {
+		response.WorkingHours = append(response.WorkingHours, WorkShiftDTO{
+			Day:   int(shift.Day),
+			Start: shift.Start,
+			End:   shift.End,
+		})
+	}
+	for _, skill := range technician.Skills {
+		response.Skills = append(response.Skills, TechnicianSkillResponse{
+			CategoryID:   skill.CategoryID.String(),
+			CategoryName: skill.CategoryName,
+		})
+	}
+
+	return response
+}
+
+// ConvertTechnicianWorkloadToResponse converts a technician's workload at the given time to its response
+func ConvertTechnicianWorkloadToResponse(workload *entity.TechnicianWorkload, at time.Time) *TechnicianWorkloadResponse {
+	available := workload.MaxConcurrentTickets - workload.ActiveClaims
+	if available < 0 || !workload.IsActive {
+		available = 0
+	}
+
+	return &TechnicianWorkloadResponse{
+		TechnicianResponse:  *ConvertTechnicianToResponse(&workload.Technician),
+		ActiveClaims:        workload.ActiveClaims,
+		OpenTickets:         workload.OpenTickets,
+		WaitingPartsTickets: workload.WaitingPartsTickets,
+		AvailableCapacity:   available,
+		Utilization:         decimal.NewFromFloat(workload.Utilization() * 100).Round(1),
+		OnShift:             workload.IsActive && workload.IsOnShift(at),
+	}
+}
+
+// ConvertWorkShiftsToEntity converts requested working hours to a schedule
+func ConvertWorkShiftsToEntity(shifts []WorkShiftDTO) entity.WorkingHours {
+	hours := make(entity.WorkingHours, 0, len(shifts))
+	for _, shift := range shifts {
+		hours = append(hours, entity.WorkShift{
+			Day:   time.Weekday(shift.Day),
+			Start: shift.Start,
+			End:   shift.End,
+		})
+	}
+	return hours
+}
@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// WorkShiftDTO represents a weekly working period of a technician. A shift
+// that ends at or before its start runs past midnight.
+type WorkShiftDTO struct {
+	Day   int    `json:"day" validate:"min=0,max=6" example:"1"`
+	Start string `json:"start" validate:"required" example:"09:00"`
+	End   string `json:"end" validate:"required" example:"17:00"`
+}
+
+// TechnicianCreateRequest represents a request to add a seller user to the repair roster
+type TechnicianCreateRequest struct {
+	UserID               string         `json:"user_id" validate:"required,uuid" example:"550e8400-e29b-41d4-a716-446655440007"`
+	MaxConcurrentTickets *int           `json:"max_concurrent_tickets,omitempty" validate:"omitempty,min=1" example:"5"`
+	WorkingHours         []WorkShiftDTO `json:"working_hours,omitempty"`
+	Timezone             string         `json:"timezone,omitempty" example:"Asia/Jakarta"`
+	SkillCategoryIDs     []string       `json:"skill_category_ids,omitempty" example:"550e8400-e29b-41d4-a716-446655440020"`
+}
+
+// TechnicianUpdateRequest represents a request to update a technician. Working
+// hours and skills, when present, replace the current ones.
+type TechnicianUpdateRequest struct {
+	MaxConcurrentTickets *int           `json:"max_concurrent_tickets,omitempty" validate:"omitempty,min=1" example:"6"`
+	WorkingHours         []WorkShiftDTO `json:"working_hours,omitempty"`
+	Timezone             *string        `json:"timezone,omitempty" example:"Asia/Jakarta"`
+	SkillCategoryIDs     []string       `json:"skill_category_ids,omitempty" example:"550e8400-e29b-41d4-a716-446655440020"`
+	IsActive             *bool          `json:"is_active,omitempty" example:"true"`
+}
+
+// TechnicianSkillResponse represents a product category a technician repairs
+type TechnicianSkillResponse struct {
+	CategoryID   string `json:"category_id" example:"550e8400-e29b-41d4-a716-446655440020"`
+	CategoryName string `json:"category_name" example:"Laptops"`
+}
+
+// TechnicianResponse represents a technician on the repair roster
+type TechnicianResponse struct {
+	ID                   string                    `json:"id" example:"550e8400-e29b-41d4-a716-446655440030"`
+	UserID               string                    `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440007"`
+	Name                 string                    `json:"name" example:"Budi Santoso"`
+	Email                string                    `json:"email" example:"budi@example.com"`
+	MaxConcurrentTickets int                       `json:"max_concurrent_tickets" example:"5"`
+	WorkingHours         []WorkShiftDTO            `json:"working_hours"`
+	Timezone             string                    `json:"timezone" example:"Asia/Jakarta"`
+	IsActive             bool                      `json:"is_active" example:"true"`
+	Skills               []TechnicianSkillResponse `json:"skills"`
+	CreatedAt            time.Time                 `json:"created_at" example:"2024-01-15T10:30:00Z"`
+	UpdatedAt            time.Time                 `json:"updated_at" example:"2024-01-15T10:30:00Z"`
+}
+
+// TechnicianWorkloadResponse represents the work assigned to a technician
+type TechnicianWorkloadResponse struct {
+	TechnicianResponse
+	ActiveClaims        int             `json:"active_claims" example:"3"`
+	OpenTickets         int             `json:"open_tickets" example:"3"`
+	WaitingPartsTickets int             `json:"waiting_parts_tickets" example:"1"`
+	AvailableCapacity   int             `json:"available_capacity" example:"2"`
+	Utilization         decimal.Decimal `json:"utilization" example:"60.0"`
+	OnShift             bool            `json:"on_shift" example:"true"`
+}
+
+// TechnicianWorkloadSummaryResponse represents the repair workload of a
+// storefront for supervisors
+type TechnicianWorkloadSummaryResponse struct {
+	Technicians        []TechnicianWorkloadResponse `json:"technicians"`
+	ActiveTechnicians  int                          `json:"active_technicians" example:"4"`
+	OnShiftTechnicians int                          `json:"on_shift_technicians" example:"2"`
+	TotalCapacity      int                          `json:"total_capacity" example:"20"`
+	TotalActiveClaims  int                          `json:"total_active_claims" example:"11"`
+	UnassignedClaims   int                          `json:"unassigned_claims" example:"2"`
+}
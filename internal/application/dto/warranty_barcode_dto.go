@@ -10,7 +10,7 @@ type WarrantyBarcodeRequest struct {
 	Quantity     int    `json:"quantity" validate:"required,min=1,max=1000" example:"100"`
 	BatchName    string `json:"batch_name" validate:"omitempty,max=100" example:"Production Batch #12"`
 	Notes        string `json:"notes" validate:"omitempty,max=500" example:"Q1 2024 production run"`
-	ExpiryMonths int    `json:"expiry_months" validate:"required,min=1,max=120" example:"24"`
+	ExpiryMonths int    `json:"expiry_months" validate:"omitempty,min=1,max=120" example:"24"` // Defaults to the product's warranty policy
 }
 
 // WarrantyBarcodeResponse represents a warranty barcode
@@ -2,6 +2,7 @@ package dto
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -35,6 +36,8 @@ func ConvertWarrantyClaimToResponse(claim *entity.WarrantyClaim) *WarrantyClaimR
 		Status:           claim.Status.String(),
 		StatusUpdatedAt:  claim.StatusUpdatedAt,
 		RepairCost:       claim.RepairCost,
+		RepairLaborCost:  claim.RepairLaborCost,
+		RepairPartsCost:  claim.RepairPartsCost,
 		ShippingCost:     claim.ShippingCost,
 		ReplacementCost:  claim.ReplacementCost,
 		TotalCost:        claim.TotalCost,
@@ -47,6 +50,13 @@ func ConvertWarrantyClaimToResponse(claim *entity.WarrantyClaim) *WarrantyClaimR
 		UpdatedAt:        claim.UpdatedAt,
 	}
 
+	// SLA tracking as of now
+	now := time.Now()
+	response.SLAState = string(claim.SLAState(now))
+	response.SLADueAt = claim.SLADueBy(now)
+	response.SLAPausedAt = claim.SLAPausedAt
+	response.SLABreachedAt = claim.SLABreachedAt
+
 	// Handle optional string fields
 	if claim.CustomerPhone != nil {
 		response.CustomerPhone = *claim.CustomerPhone
@@ -187,6 +197,9 @@ func ConvertClaimAttachmentToResponse(attachment *entity.ClaimAttachment) *Claim
 		Description:         attachment.Description,
 		UploadedBy:          attachment.UploadedBy.String(),
 		SecurityScanStatus:  string(attachment.VirusScanStatus),
+		SecurityScanResult:  attachment.ProcessingNotes,
+		Checksum:            attachment.Checksum,
+		Downloadable:        attachment.CanDownload(),
 		CreatedAt:           attachment.UploadedAt,
 		UpdatedAt:           attachment.UploadedAt,
 	}
@@ -206,6 +219,105 @@ func ConvertClaimAttachmentsToResponses(attachments []*entity.ClaimAttachment) [
 	return responses
 }
 
+// ConvertRepairTicketToResponse converts a RepairTicket entity to RepairTicketResponse DTO
+func ConvertRepairTicketToResponse(ticket *entity.RepairTicket) *RepairTicketResponse {
+	if ticket == nil {
+		return nil
+	}
+
+	response := &RepairTicketResponse{
+		ID:                   ticket.ID.String(),
+		TicketNumber:         ticket.TicketNumber,
+		ClaimID:              ticket.ClaimID.String(),
+		ClaimNumber:          ticket.ClaimNumber,
+		Status:               ticket.Status.String(),
+		Priority:             string(ticket.Priority),
+		TechnicianID:         ticket.TechnicianID.String(),
+		TechnicianName:       ticket.TechnicianName,
+		AssignedAt:           ticket.AssignedAt,
+		EstimatedHours:       ticket.EstimatedHours,
+		LaborHours:           ticket.LaborHours,
+		StartDate:            ticket.StartDate,
+		TargetCompletionDate: ticket.TargetCompletionDate,
+		ActualCompletionDate: ticket.ActualCompletionDate,
+		Duration:             ticket.Duration,
+		IsOverdue:            ticket.IsOverdue,
+		Description:          ticket.Description,
+		Diagnosis:            ticket.Diagnosis,
+		RepairSteps:          ticket.RepairSteps,
+		RequiredParts:        ticket.RequiredParts,
+		PartsUsed:            make([]RepairTicketPartResponse, len(ticket.PartsUsed)),
+		SpecialInstructions:  ticket.SpecialInstructions,
+		TechnicianNotes:      ticket.TechnicianNotes,
+		SupervisorNotes:      ticket.SupervisorNotes,
+		HourlyRate:           ticket.HourlyRate,
+		LaborCost:            ticket.LaborCost,
+		PartsCost:            ticket.PartsCost,
+		TotalCost:            ticket.TotalCost,
+		QualityCheckStatus:   "pending",
+		QualityNotes:         ticket.QualityNotes,
+		QualityCheckedAt:     ticket.QualityCheckedAt,
+		TestResults:          make([]RepairTicketTestResultResponse, len(ticket.TestResults)),
+		CreatedAt:            ticket.CreatedAt,
+		UpdatedAt:            ticket.UpdatedAt,
+	}
+
+	for i, part := range ticket.PartsUsed {
+		response.PartsUsed[i] = RepairTicketPartResponse{
+			PartNumber:  part.PartNumber,
+			PartName:    part.PartName,
+			Quantity:    part.Quantity,
+			UnitCost:    part.UnitCost,
+			TotalCost:   part.TotalCost,
+			Description: part.Description,
+			Supplier:    part.Supplier,
+		}
+	}
+
+	for i, test := range ticket.TestResults {
+		response.TestResults[i] = RepairTicketTestResultResponse{
+			TestName:    test.TestName,
+			Result:      test.Result,
+			Description: test.Description,
+			TestedAt:    test.TestedAt,
+		}
+		if test.TestedBy != nil {
+			testedBy := test.TestedBy.String()
+			response.TestResults[i].TestedBy = &testedBy
+		}
+	}
+
+	if ticket.QualityCheckPassed != nil {
+		if *ticket.QualityCheckPassed {
+			response.QualityCheckStatus = "passed"
+		} else {
+			response.QualityCheckStatus = "failed"
+		}
+	}
+
+	if ticket.QualityCheckedBy != nil {
+		qualityCheckedBy := ticket.QualityCheckedBy.String()
+		response.QualityCheckedBy = &qualityCheckedBy
+	}
+
+	if ticket.CreatedBy != nil {
+		createdBy := ticket.CreatedBy.String()
+		response.CreatedBy = &createdBy
+	}
+
+	return response
+}
+
+// ConvertRepairTicketsToResponses converts a slice of RepairTicket entities to RepairTicketResponse DTOs
+func ConvertRepairTicketsToResponses(tickets []*entity.RepairTicket) []RepairTicketResponse {
+	responses := make([]RepairTicketResponse, len(tickets))
+	for i, ticket := range tickets {
+		responses[i] = *ConvertRepairTicketToResponse(ticket)
+	}
+
+	return responses
+}
+
 // ConvertWarrantyClaimSubmissionRequestToEntity converts a WarrantyClaimSubmissionRequest DTO to WarrantyClaim entity
 func ConvertWarrantyClaimSubmissionRequestToEntity(req *WarrantyClaimSubmissionRequest, barcodeID, customerID, productID, storefrontID string) (*entity.WarrantyClaim, error) {
 	if req == nil {
@@ -275,6 +387,9 @@ func ConvertWarrantyClaimStatsToResponse(stats map[string]interface{}) *Warranty
 		ClaimsThisMonth:    getIntFromStats(stats, "claims_this_month"),
 		ClaimsLastMonth:    getIntFromStats(stats, "claims_last_month"),
 		GrowthRate:         getDecimalFromStats(stats, "growth_rate"),
+		SLAAtRiskClaims:    getIntFromStats(stats, "sla_at_risk_claims"),
+		SLABreachedClaims:  getIntFromStats(stats, "sla_breached_claims"),
+		SLAComplianceRate:  getDecimalFromStats(stats, "sla_compliance_rate"),
 	}
 
 	return response
@@ -64,6 +64,8 @@ type WarrantyClaimResponse struct {
 	
 	// Cost tracking
 	RepairCost      decimal.Decimal `json:"repair_cost" example:"25.50"`
+	RepairLaborCost decimal.Decimal `json:"repair_labor_cost" example:"15.00"`
+	RepairPartsCost decimal.Decimal `json:"repair_parts_cost" example:"10.50"`
 	ShippingCost    decimal.Decimal `json:"shipping_cost" example:"10.00"`
 	ReplacementCost decimal.Decimal `json:"replacement_cost" example:"0.00"`
 	TotalCost       decimal.Decimal `json:"total_cost" example:"35.50"`
@@ -92,6 +94,12 @@ type WarrantyClaimResponse struct {
 	CustomerFeedback             *string         `json:"customer_feedback,omitempty" example:"Excellent service"`
 	ProcessingTimeHours          *decimal.Decimal `json:"processing_time_hours,omitempty" example:"48.5"`
 	
+	// SLA tracking (due-by moves back while the claim waits on the customer)
+	SLAState      string     `json:"sla_state,omitempty" example:"at_risk"`
+	SLADueAt      *time.Time `json:"sla_due_at,omitempty" example:"2024-01-23T14:30:00Z"`
+	SLAPausedAt   *time.Time `json:"sla_paused_at,omitempty" example:"2024-01-21T10:00:00Z"`
+	SLABreachedAt *time.Time `json:"sla_breached_at,omitempty" example:"2024-01-23T14:30:00Z"`
+	
 	// Product information (computed)
 	ProductName        *string `json:"product_name,omitempty" example:"Smartphone XYZ"`
 	ProductSKU         *string `json:"product_sku,omitempty" example:"SKU-12345"`
@@ -140,6 +148,7 @@ type ClaimFiltersResponse struct {
 	AvailableSeverities  []string `json:"available_severities" example:"low,medium,high,critical"`
 	AvailablePriorities  []string `json:"available_priorities" example:"low,normal,high,urgent"`
 	AvailableCategories  []string `json:"available_categories" example:"hardware,software,performance,defect,damage,other"`
+	AvailableSLAStates   []string `json:"available_sla_states" example:"on_track,at_risk,breached,paused,met"`
 }
 
 // WarrantyClaimValidationRequest represents a request to validate a claim
@@ -188,6 +197,9 @@ type WarrantyClaimStatsResponse struct {
 	ClaimsThisMonth   int                        `json:"claims_this_month" example:"125"`
 	ClaimsLastMonth   int                        `json:"claims_last_month" example:"98"`
 	GrowthRate        decimal.Decimal            `json:"growth_rate" example:"27.55"`
+	SLAAtRiskClaims   int                        `json:"sla_at_risk_claims" example:"6"`
+	SLABreachedClaims int                        `json:"sla_breached_claims" example:"3"`
+	SLAComplianceRate decimal.Decimal            `json:"sla_compliance_rate" example:"96.4"`
 	TopIssueCategories []CategoryStatsResponse   `json:"top_issue_categories"`
 	RecentClaims      []WarrantyClaimResponse    `json:"recent_claims"`
 }
@@ -214,6 +226,8 @@ type ClaimAttachmentResponse struct {
 	UploadedBy          string    `json:"uploaded_by" example:"550e8400-e29b-41d4-a716-446655440002"`
 	SecurityScanStatus  string    `json:"security_scan_status" example:"passed"`
 	SecurityScanResult  *string   `json:"security_scan_result,omitempty" example:"No threats detected"`
+	Checksum            *string   `json:"checksum,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	Downloadable        bool      `json:"downloadable" example:"true"`
 	CreatedAt           time.Time `json:"created_at" example:"2024-01-20T14:30:00Z"`
 	UpdatedAt           time.Time `json:"updated_at" example:"2024-01-20T14:30:00Z"`
 }
@@ -287,172 +301,202 @@ type TechnicianAssignmentRequest = WarrantyClaimAssignmentRequest
 
 // ===== REPAIR TICKET DTOs =====
 
-// RepairTicketCreateRequest represents a request to create a repair ticket
+// RepairTicketCreateRequest represents a request to create a repair ticket for a claim
 type RepairTicketCreateRequest struct {
-	ClaimID             string          `json:"claim_id" validate:"required,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
-	Priority            string          `json:"priority" validate:"required,oneof=low normal high urgent" example:"high"`
-	EstimatedHours      decimal.Decimal `json:"estimated_hours" validate:"required,min=0.1,max=1000" example:"4.5"`
-	Description         string          `json:"description" validate:"required,min=10,max=2000" example:"Replace faulty motherboard and test all components"`
-	RequiredParts       []string        `json:"required_parts,omitempty" example:"motherboard,thermal_paste"`
-	SpecialInstructions string          `json:"special_instructions" validate:"omitempty,max=1000" example:"Handle with care - customer reported water damage"`
-	CustomerApprovalRequired bool       `json:"customer_approval_required" example:"true"`
+	TechnicianID         string                         `json:"technician_id,omitempty" validate:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440002"`
+	Priority             string                         `json:"priority" validate:"omitempty,oneof=low normal high urgent" example:"high"`
+	EstimatedHours       *decimal.Decimal               `json:"estimated_hours,omitempty" validate:"omitempty,min=0.1,max=1000" example:"4.5"`
+	HourlyRate           *decimal.Decimal               `json:"hourly_rate,omitempty" validate:"omitempty,min=0" example:"25.00"`
+	TargetCompletionDate *time.Time                     `json:"target_completion_date,omitempty" example:"2024-01-22T17:00:00Z"`
+	Description          string                         `json:"description" validate:"required,min=10,max=2000" example:"Replace faulty motherboard and test all components"`
+	RequiredParts        []string                       `json:"required_parts,omitempty" example:"motherboard,thermal_paste"`
+	SpecialInstructions  string                         `json:"special_instructions,omitempty" validate:"omitempty,max=1000" example:"Handle with care - customer reported water damage"`
+	SpareParts           []RepairTicketSparePartRequest `json:"spare_parts,omitempty"`
+}
+
+// RepairTicketPartResponse represents a part used in a repair
+type RepairTicketPartResponse struct {
+	PartNumber  string          `json:"part_number" example:"MB-X200"`
+	PartName    string          `json:"part_name" example:"Motherboard"`
+	Quantity    int             `json:"quantity" example:"1"`
+	UnitCost    decimal.Decimal `json:"unit_cost" example:"85.50"`
+	TotalCost   decimal.Decimal `json:"total_cost" example:"85.50"`
+	Description *string         `json:"description,omitempty" example:"Replacement board revision B"`
+	Supplier    *string         `json:"supplier,omitempty" example:"Acme Parts"`
+}
+
+// RepairTicketTestResultResponse represents a test run after a repair
+type RepairTicketTestResultResponse struct {
+	TestName    string    `json:"test_name" example:"Power on self test"`
+	Result      string    `json:"result" example:"passed"`
+	Description *string   `json:"description,omitempty" example:"Booted in 12 seconds"`
+	TestedAt    time.Time `json:"tested_at" example:"2024-01-22T16:00:00Z"`
+	TestedBy    *string   `json:"tested_by,omitempty" example:"550e8400-e29b-41d4-a716-446655440002"`
 }
 
 // RepairTicketResponse represents a repair ticket
 type RepairTicketResponse struct {
-	ID                       string          `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	TicketNumber             string          `json:"ticket_number" example:"RPR-2024-001234"`
-	ClaimID                  string          `json:"claim_id" example:"550e8400-e29b-41d4-a716-446655440001"`
-	ClaimNumber              string          `json:"claim_number" example:"WAR-2024-001234"`
-	Status                   string          `json:"status" example:"assigned"`
-	Priority                 string          `json:"priority" example:"high"`
-	
+	ID           string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TicketNumber string `json:"ticket_number" example:"RPR-2024-001234"`
+	ClaimID      string `json:"claim_id" example:"550e8400-e29b-41d4-a716-446655440001"`
+	ClaimNumber  string `json:"claim_number" example:"WAR-2024-001234"`
+	Status       string `json:"status" example:"in_progress"`
+	Priority     string `json:"priority" example:"high"`
+
 	// Technician Information
-	AssignedTechnicianID     *string         `json:"assigned_technician_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440002"`
-	TechnicianName           *string         `json:"technician_name,omitempty" example:"John Smith"`
-	AssignedAt               *time.Time      `json:"assigned_at,omitempty" example:"2024-01-20T09:00:00Z"`
-	
+	TechnicianID   string    `json:"technician_id" example:"550e8400-e29b-41d4-a716-446655440002"`
+	TechnicianName *string   `json:"technician_name,omitempty" example:"John Smith"`
+	AssignedAt     time.Time `json:"assigned_at" example:"2024-01-20T09:00:00Z"`
+
 	// Timing Information
-	EstimatedHours           decimal.Decimal `json:"estimated_hours" example:"4.5"`
-	ActualHours              *decimal.Decimal `json:"actual_hours,omitempty" example:"5.2"`
-	EstimatedCompletionDate  *time.Time      `json:"estimated_completion_date,omitempty" example:"2024-01-22T17:00:00Z"`
-	ActualCompletionDate     *time.Time      `json:"actual_completion_date,omitempty" example:"2024-01-22T16:30:00Z"`
-	
+	EstimatedHours       *decimal.Decimal `json:"estimated_hours,omitempty" example:"4.5"`
+	LaborHours           decimal.Decimal  `json:"labor_hours" example:"5.2"`
+	StartDate            *time.Time       `json:"start_date,omitempty" example:"2024-01-20T10:00:00Z"`
+	TargetCompletionDate *time.Time       `json:"target_completion_date,omitempty" example:"2024-01-22T17:00:00Z"`
+	ActualCompletionDate *time.Time       `json:"actual_completion_date,omitempty" example:"2024-01-22T16:30:00Z"`
+	Duration             string           `json:"duration,omitempty" example:"2.3 days"`
+	IsOverdue            bool             `json:"is_overdue" example:"false"`
+
 	// Repair Details
-	Description              string          `json:"description" example:"Replace faulty motherboard and test all components"`
-	RequiredParts            []string        `json:"required_parts,omitempty" example:"motherboard,thermal_paste"`
-	UsedParts                []string        `json:"used_parts,omitempty" example:"motherboard,thermal_paste,screws"`
-	SpecialInstructions      string          `json:"special_instructions" example:"Handle with care - customer reported water damage"`
-	RepairNotes              *string         `json:"repair_notes,omitempty" example:"Successfully replaced motherboard, all tests passed"`
-	
+	Description         *string                        `json:"description,omitempty" example:"Replace faulty motherboard and test all components"`
+	Diagnosis           string                         `json:"diagnosis,omitempty" example:"Shorted power regulator on the motherboard"`
+	RepairSteps         []string                       `json:"repair_steps,omitempty" example:"Removed motherboard,Installed replacement"`
+	RequiredParts       []string                       `json:"required_parts,omitempty" example:"motherboard,thermal_paste"`
+	PartsUsed           []RepairTicketPartResponse     `json:"parts_used"`
+	SpareParts          []SparePartReservationResponse `json:"spare_parts,omitempty"`
+	SpecialInstructions *string                        `json:"special_instructions,omitempty" example:"Handle with care - customer reported water damage"`
+	TechnicianNotes     *string                        `json:"technician_notes,omitempty" example:"Successfully replaced motherboard, all tests passed"`
+	SupervisorNotes     *string                        `json:"supervisor_notes,omitempty" example:"Verified on the bench"`
+
 	// Cost Information
-	LaborCost                decimal.Decimal `json:"labor_cost" example:"120.00"`
-	PartsCost                decimal.Decimal `json:"parts_cost" example:"85.50"`
-	TotalCost                decimal.Decimal `json:"total_cost" example:"205.50"`
-	
+	HourlyRate *decimal.Decimal `json:"hourly_rate,omitempty" example:"25.00"`
+	LaborCost  decimal.Decimal  `json:"labor_cost" example:"130.00"`
+	PartsCost  decimal.Decimal  `json:"parts_cost" example:"85.50"`
+	TotalCost  decimal.Decimal  `json:"total_cost" example:"215.50"`
+
 	// Quality Control
-	QualityCheckStatus       string          `json:"quality_check_status" example:"pending"`
-	QualityCheckedBy         *string         `json:"quality_checked_by,omitempty" example:"550e8400-e29b-41d4-a716-446655440003"`
-	QualityCheckDate         *time.Time      `json:"quality_check_date,omitempty" example:"2024-01-22T18:00:00Z"`
-	QualityCheckNotes        *string         `json:"quality_check_notes,omitempty" example:"All functionality verified, repair approved"`
-	
-	// Customer Approval
-	CustomerApprovalRequired bool            `json:"customer_approval_required" example:"true"`
-	CustomerApprovalStatus   string          `json:"customer_approval_status" example:"pending"`
-	CustomerApprovedAt       *time.Time      `json:"customer_approved_at,omitempty" example:"2024-01-21T14:30:00Z"`
-	CustomerApprovalNotes    *string         `json:"customer_approval_notes,omitempty" example:"Customer approved repair cost estimate"`
-	
+	QualityCheckStatus string                           `json:"quality_check_status" example:"passed"`
+	QualityNotes       *string                          `json:"quality_notes,omitempty" example:"All functionality verified"`
+	QualityCheckedBy   *string                          `json:"quality_checked_by,omitempty" example:"550e8400-e29b-41d4-a716-446655440003"`
+	QualityCheckedAt   *time.Time                       `json:"quality_checked_at,omitempty" example:"2024-01-22T18:00:00Z"`
+	TestResults        []RepairTicketTestResultResponse `json:"test_results"`
+
 	// Metadata
-	CreatedBy                string          `json:"created_by" example:"550e8400-e29b-41d4-a716-446655440004"`
-	CreatedAt                time.Time       `json:"created_at" example:"2024-01-20T08:30:00Z"`
-	UpdatedAt                time.Time       `json:"updated_at" example:"2024-01-22T16:30:00Z"`
+	CreatedBy *string   `json:"created_by,omitempty" example:"550e8400-e29b-41d4-a716-446655440004"`
+	CreatedAt time.Time `json:"created_at" example:"2024-01-20T08:30:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2024-01-22T16:30:00Z"`
 }
 
-// RepairTicketUpdateRequest represents a request to update a repair ticket
+// RepairTicketUpdateRequest represents a request to update the work order of a repair ticket
 type RepairTicketUpdateRequest struct {
-	Priority            *string          `json:"priority,omitempty" validate:"omitempty,oneof=low normal high urgent" example:"high"`
-	EstimatedHours      *decimal.Decimal `json:"estimated_hours,omitempty" validate:"omitempty,min=0.1,max=1000" example:"4.5"`
-	Description         *string          `json:"description,omitempty" validate:"omitempty,min=10,max=2000" example:"Replace faulty motherboard and test all components"`
-	RequiredParts       []string         `json:"required_parts,omitempty" example:"motherboard,thermal_paste"`
-	UsedParts           []string         `json:"used_parts,omitempty" example:"motherboard,thermal_paste,screws"`
-	SpecialInstructions *string          `json:"special_instructions,omitempty" validate:"omitempty,max=1000" example:"Handle with care - customer reported water damage"`
-	RepairNotes         *string          `json:"repair_notes,omitempty" validate:"omitempty,max=2000" example:"Successfully replaced motherboard, all tests passed"`
-	LaborCost           *decimal.Decimal `json:"labor_cost,omitempty" validate:"omitempty,min=0" example:"120.00"`
-	PartsCost           *decimal.Decimal `json:"parts_cost,omitempty" validate:"omitempty,min=0" example:"85.50"`
+	Priority             *string          `json:"priority,omitempty" validate:"omitempty,oneof=low normal high urgent" example:"high"`
+	EstimatedHours       *decimal.Decimal `json:"estimated_hours,omitempty" validate:"omitempty,min=0.1,max=1000" example:"4.5"`
+	HourlyRate           *decimal.Decimal `json:"hourly_rate,omitempty" validate:"omitempty,min=0" example:"25.00"`
+	TargetCompletionDate *time.Time       `json:"target_completion_date,omitempty" example:"2024-01-22T17:00:00Z"`
+	Description          *string          `json:"description,omitempty" validate:"omitempty,min=10,max=2000" example:"Replace faulty motherboard and test all components"`
+	RequiredParts        []string         `json:"required_parts,omitempty" example:"motherboard,thermal_paste"`
+	SpecialInstructions  *string          `json:"special_instructions,omitempty" validate:"omitempty,max=1000" example:"Handle with care - customer reported water damage"`
+	TechnicianNotes      *string          `json:"technician_notes,omitempty" validate:"omitempty,max=2000" example:"Customer confirmed the device was dropped"`
 }
 
 // RepairTicketAssignmentRequest represents a request to assign a technician to a repair ticket
 type RepairTicketAssignmentRequest struct {
-	TechnicianID            string     `json:"technician_id" validate:"required,uuid" example:"550e8400-e29b-41d4-a716-446655440002"`
-	EstimatedCompletionDate *time.Time `json:"estimated_completion_date,omitempty" example:"2024-01-22T17:00:00Z"`
-	Notes                   string     `json:"notes" validate:"omitempty,max=1000" example:"Assigned to senior technician for complex repair"`
+	TechnicianID         string     `json:"technician_id" validate:"required,uuid" example:"550e8400-e29b-41d4-a716-446655440002"`
+	TargetCompletionDate *time.Time `json:"target_completion_date,omitempty" example:"2024-01-22T17:00:00Z"`
+	Notes                string     `json:"notes" validate:"omitempty,max=1000" example:"Assigned to senior technician for complex repair"`
+}
+
+// RepairTicketStartRequest represents a request to start the repair work
+type RepairTicketStartRequest struct {
+	Diagnosis string `json:"diagnosis" validate:"required,min=10,max=2000" example:"Shorted power regulator on the motherboard"`
+}
+
+// RepairTicketPartRequest represents a request to record a part used in a repair
+type RepairTicketPartRequest struct {
+	PartNumber  string          `json:"part_number" validate:"required,max=100" example:"MB-X200"`
+	PartName    string          `json:"part_name" validate:"required,max=255" example:"Motherboard"`
+	Quantity    int             `json:"quantity" validate:"required,min=1" example:"1"`
+	UnitCost    decimal.Decimal `json:"unit_cost" validate:"min=0" example:"85.50"`
+	Description *string         `json:"description,omitempty" validate:"omitempty,max=500" example:"Replacement board revision B"`
+	Supplier    *string         `json:"supplier,omitempty" validate:"omitempty,max=255" example:"Acme Parts"`
 }
 
-// RepairTicketCompletionRequest represents a request to complete a repair ticket
+// RepairTicketSparePartRequest represents a request for a part from the
+// storefront's spare parts inventory, by ID or part number
+type RepairTicketSparePartRequest struct {
+	SparePartID string `json:"spare_part_id,omitempty" validate:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440010"`
+	PartNumber  string `json:"part_number,omitempty" validate:"omitempty,max=100" example:"MB-X200"`
+	Quantity    int    `json:"quantity" validate:"required,min=1" example:"1"`
+}
+
+// RepairTicketWaitingPartsRequest represents a request to pause a repair until parts arrive
+type RepairTicketWaitingPartsRequest struct {
+	Notes string `json:"notes" validate:"omitempty,max=1000" example:"Waiting on a replacement motherboard from the supplier"`
+}
+
+// RepairTicketTestResultRequest represents a test run after a repair
+type RepairTicketTestResultRequest struct {
+	TestName    string  `json:"test_name" validate:"required,max=255" example:"Power on self test"`
+	Result      string  `json:"result" validate:"required,oneof=passed failed warning" example:"passed"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1000" example:"Booted in 12 seconds"`
+}
+
+// RepairTicketCompletionRequest represents a request to complete a repair ticket.
+// A passed quality check completes the claim's repair with the ticket's cost.
 type RepairTicketCompletionRequest struct {
-	ActualHours   decimal.Decimal `json:"actual_hours" validate:"required,min=0.1,max=1000" example:"5.2"`
-	UsedParts     []string        `json:"used_parts,omitempty" example:"motherboard,thermal_paste,screws"`
-	RepairNotes   string          `json:"repair_notes" validate:"required,min=10,max=2000" example:"Successfully replaced motherboard, all tests passed"`
-	LaborCost     decimal.Decimal `json:"labor_cost" validate:"required,min=0" example:"120.00"`
-	PartsCost     decimal.Decimal `json:"parts_cost" validate:"required,min=0" example:"85.50"`
-	TestResults   string          `json:"test_results" validate:"omitempty,max=1000" example:"All functionality tests passed"`
+	LaborHours         decimal.Decimal                 `json:"labor_hours" validate:"required,min=0.1,max=1000" example:"5.2"`
+	HourlyRate         *decimal.Decimal                `json:"hourly_rate,omitempty" validate:"omitempty,min=0" example:"25.00"`
+	RepairSteps        []string                        `json:"repair_steps,omitempty" example:"Removed motherboard,Installed replacement"`
+	RepairNotes        string                          `json:"repair_notes" validate:"required,min=10,max=2000" example:"Successfully replaced motherboard, all tests passed"`
+	TestResults        []RepairTicketTestResultRequest `json:"test_results,omitempty"`
+	QualityCheckPassed *bool                           `json:"quality_check_passed" validate:"required" example:"true"`
+	QualityNotes       string                          `json:"quality_notes,omitempty" validate:"omitempty,max=1000" example:"All functionality verified"`
 }
 
-// RepairTicketQualityCheckRequest represents a request for quality control approval
+// RepairTicketQualityCheckRequest represents a supervisor's quality check of a completed repair
 type RepairTicketQualityCheckRequest struct {
-	Action string  `json:"action" validate:"required,oneof=approve reject" example:"approve"`
-	Notes  string  `json:"notes" validate:"required,min=10,max=1000" example:"All functionality verified, repair approved"`
+	Action string `json:"action" validate:"required,oneof=approve reject" example:"approve"`
+	Notes  string `json:"notes" validate:"required,min=10,max=1000" example:"All functionality verified, repair approved"`
 }
 
-// RepairTicketListRequest represents a request to list repair tickets with filters
-type RepairTicketListRequest struct {
-	// Pagination
-	Page     int `json:"page" validate:"min=1" example:"1"`
-	PageSize int `json:"page_size" validate:"min=1,max=100" example:"20"`
-	
-	// Sorting
-	SortBy    string `json:"sort_by" validate:"omitempty,oneof=created_at priority status estimated_completion_date" example:"created_at"`
-	SortOrder string `json:"sort_order" validate:"omitempty,oneof=asc desc" example:"desc"`
-	
-	// Filters
-	Status               []string   `json:"status,omitempty" example:"assigned,in_progress"`
-	Priority             []string   `json:"priority,omitempty" example:"high,urgent"`
-	TechnicianID         *string    `json:"technician_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440002"`
-	ClaimID              *string    `json:"claim_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
-	QualityCheckStatus   []string   `json:"quality_check_status,omitempty" example:"pending,approved"`
-	CreatedDateFrom      *time.Time `json:"created_date_from,omitempty" example:"2024-01-01T00:00:00Z"`
-	CreatedDateTo        *time.Time `json:"created_date_to,omitempty" example:"2024-01-31T23:59:59Z"`
-	EstimatedCompletionFrom *time.Time `json:"estimated_completion_from,omitempty" example:"2024-01-20T00:00:00Z"`
-	EstimatedCompletionTo   *time.Time `json:"estimated_completion_to,omitempty" example:"2024-01-25T23:59:59Z"`
-	SearchTerm           string     `json:"search_term,omitempty" example:"motherboard"`
+// RepairTicketCancelRequest represents a request to cancel a repair ticket
+type RepairTicketCancelRequest struct {
+	Reason string `json:"reason" validate:"required,min=5,max=1000" example:"Customer chose a replacement instead"`
 }
 
 // RepairTicketListResponse represents a paginated list of repair tickets
 type RepairTicketListResponse struct {
 	Tickets    []RepairTicketResponse `json:"tickets"`
 	Pagination PaginationResponse     `json:"pagination"`
-	Filters    RepairTicketFiltersResponse `json:"filters"`
-}
-
-// RepairTicketFiltersResponse represents available filter options for repair tickets
-type RepairTicketFiltersResponse struct {
-	AvailableStatuses          []string `json:"available_statuses" example:"pending,assigned,in_progress,completed,cancelled"`
-	AvailablePriorities        []string `json:"available_priorities" example:"low,normal,high,urgent"`
-	AvailableQualityCheckStatuses []string `json:"available_quality_check_statuses" example:"pending,approved,rejected"`
 }
 
-// RepairTicketStatisticsResponse represents repair ticket analytics and statistics
+// RepairTicketStatisticsResponse represents repair ticket analytics. Turnaround
+// runs from assignment to completion; costs cover completed repairs only.
 type RepairTicketStatisticsResponse struct {
-	TotalTickets              int                        `json:"total_tickets" example:"450"`
-	TicketsByStatus           map[string]int             `json:"tickets_by_status" example:"{\"assigned\":120,\"in_progress\":85,\"completed\":200}"`
-	TicketsByPriority         map[string]int             `json:"tickets_by_priority" example:"{\"normal\":200,\"high\":150,\"urgent\":100}"`
-	TicketsByTechnician       map[string]int             `json:"tickets_by_technician" example:"{\"John Smith\":45,\"Jane Doe\":38}"`
-	AverageRepairTime         decimal.Decimal            `json:"average_repair_time" example:"4.8"`
-	AverageLaborCost          decimal.Decimal            `json:"average_labor_cost" example:"95.50"`
-	AveragePartsCost          decimal.Decimal            `json:"average_parts_cost" example:"67.25"`
-	TotalLaborCost            decimal.Decimal            `json:"total_labor_cost" example:"42975.00"`
-	TotalPartsCost            decimal.Decimal            `json:"total_parts_cost" example:"30262.50"`
-	TotalRepairCost           decimal.Decimal            `json:"total_repair_cost" example:"73237.50"`
-	CompletionRate            decimal.Decimal            `json:"completion_rate" example:"88.9"`
-	QualityApprovalRate       decimal.Decimal            `json:"quality_approval_rate" example:"94.2"`
-	CustomerApprovalRate      decimal.Decimal            `json:"customer_approval_rate" example:"91.5"`
-	TicketsThisMonth          int                        `json:"tickets_this_month" example:"85"`
-	TicketsLastMonth          int                        `json:"tickets_last_month" example:"72"`
-	GrowthRate                decimal.Decimal            `json:"growth_rate" example:"18.1"`
-	TopTechnicians            []TechnicianStatsResponse  `json:"top_technicians"`
-	RecentTickets             []RepairTicketResponse     `json:"recent_tickets"`
-}
-
-// TechnicianStatsResponse represents statistics for a specific technician
+	TotalTickets           int                       `json:"total_tickets" example:"450"`
+	CompletedTickets       int                       `json:"completed_tickets" example:"400"`
+	TicketsByStatus        map[string]int            `json:"tickets_by_status" example:"{\"assigned\":20,\"in_progress\":30,\"completed\":400}"`
+	AverageTurnaroundHours decimal.Decimal           `json:"average_turnaround_hours" example:"30.5"`
+	TotalLaborCost         decimal.Decimal           `json:"total_labor_cost" example:"42975.00"`
+	TotalPartsCost         decimal.Decimal           `json:"total_parts_cost" example:"30262.50"`
+	TotalRepairCost        decimal.Decimal           `json:"total_repair_cost" example:"73237.50"`
+	AveragePartsCost       decimal.Decimal           `json:"average_parts_cost" example:"75.66"`
+	QualityPassRate        decimal.Decimal           `json:"quality_pass_rate" example:"94.2"`
+	Technicians            []TechnicianStatsResponse `json:"technicians"`
+	PeriodStart            time.Time                 `json:"period_start" example:"2024-01-01T00:00:00Z"`
+	PeriodEnd              time.Time                 `json:"period_end" example:"2024-01-31T23:59:59Z"`
+}
+
+// TechnicianStatsResponse represents repair statistics for a specific technician
 type TechnicianStatsResponse struct {
-	TechnicianID       string          `json:"technician_id" example:"550e8400-e29b-41d4-a716-446655440002"`
-	TechnicianName     string          `json:"technician_name" example:"John Smith"`
-	AssignedTickets    int             `json:"assigned_tickets" example:"45"`
-	CompletedTickets   int             `json:"completed_tickets" example:"42"`
-	CompletionRate     decimal.Decimal `json:"completion_rate" example:"93.3"`
-	AverageRepairTime  decimal.Decimal `json:"average_repair_time" example:"4.2"`
-	QualityApprovalRate decimal.Decimal `json:"quality_approval_rate" example:"97.6"`
-	TotalRevenue       decimal.Decimal `json:"total_revenue" example:"8950.00"`
+	TechnicianID           string          `json:"technician_id" example:"550e8400-e29b-41d4-a716-446655440002"`
+	TechnicianName         string          `json:"technician_name" example:"John Smith"`
+	AssignedTickets        int             `json:"assigned_tickets" example:"45"`
+	CompletedTickets       int             `json:"completed_tickets" example:"42"`
+	AverageTurnaroundHours decimal.Decimal `json:"average_turnaround_hours" example:"26.4"`
+	TotalLaborCost         decimal.Decimal `json:"total_labor_cost" example:"4200.00"`
+	TotalPartsCost         decimal.Decimal `json:"total_parts_cost" example:"2950.00"`
+	QualityPassRate        decimal.Decimal `json:"quality_pass_rate" example:"97.6"`
 }
 
 // Note: PaginationResponse is defined in admin_user_dto.go and reused here
@@ -0,0 +1,37 @@
+package dto
+
+import "github.com/kirimku/smartseller-backend/internal/domain/entity"
+
+// ConvertWarrantyPolicyToResponse converts a warranty policy template to its response
+func ConvertWarrantyPolicyToResponse(policy *entity.WarrantyPolicyTemplate) *WarrantyPolicyResponse {
+	response := &WarrantyPolicyResponse{
+		ID:                      policy.ID,
+		Name:                    policy.Name,
+		Description:             policy.Description,
+		StorefrontID:            policy.StorefrontID,
+		Scope:                   string(policy.Scope()),
+		ProductID:               policy.ProductID,
+		ProductCategoryID:       policy.ProductCategoryID,
+		IsDefault:               policy.IsDefault,
+		WarrantyPeriodMonths:    policy.WarrantyPeriodMonths,
+		CoverageType:            policy.CoverageType.String(),
+		TermsAndConditions:      policy.TermsAndConditions,
+		Exclusions:              policy.Exclusions,
+		CoverageLimitations:     policy.CoverageLimitations,
+		RequiresPurchaseReceipt: policy.RequiresPurchaseReceipt,
+		MaxClaimsPerProduct:     policy.MaxClaimsPerProduct,
+		ClaimProcessingSLAHours: policy.ClaimProcessingSLAHours,
+		IsActive:                policy.IsActive,
+		EffectiveFrom:           policy.EffectiveFrom.Format("2006-01-02"),
+		CreatedBy:               policy.CreatedBy,
+		CreatedAt:               policy.CreatedAt,
+		UpdatedAt:               policy.UpdatedAt,
+	}
+
+	if policy.EffectiveUntil != nil {
+		until := policy.EffectiveUntil.Format("2006-01-02")
+		response.EffectiveUntil = &until
+	}
+
+	return response
+}
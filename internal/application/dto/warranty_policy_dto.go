@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateWarrantyPolicyRequest represents a request to create a warranty policy.
+// Exactly one of product_id, product_category_id or is_default selects the
+// products the policy applies to.
+type CreateWarrantyPolicyRequest struct {
+	Name                    string     `json:"name" binding:"required,max=255" example:"Electronics 12-month warranty"`
+	Description             *string    `json:"description,omitempty" example:"Standard warranty for small electronics"`
+	ProductID               *uuid.UUID `json:"product_id,omitempty"`
+	ProductCategoryID       *uuid.UUID `json:"product_category_id,omitempty"`
+	IsDefault               bool       `json:"is_default" example:"false"`
+	WarrantyPeriodMonths    int        `json:"warranty_period_months" binding:"required,min=1,max=120" example:"12"`
+	CoverageType            string     `json:"coverage_type,omitempty" binding:"omitempty,oneof=manufacturer_defect full_coverage limited_warranty extended_warranty" example:"manufacturer_defect"`
+	TermsAndConditions      string     `json:"terms_and_conditions" binding:"required" example:"Covers defects in materials and workmanship under normal use."`
+	Exclusions              *string    `json:"exclusions,omitempty" example:"Physical damage, water damage"`
+	CoverageLimitations     *string    `json:"coverage_limitations,omitempty" example:"Battery covered for 6 months"`
+	RequiresPurchaseReceipt *bool      `json:"requires_purchase_receipt,omitempty" example:"true"`
+	MaxClaimsPerProduct     *int       `json:"max_claims_per_product,omitempty" binding:"omitempty,min=1" example:"3"`
+	ClaimProcessingSLAHours *int       `json:"claim_processing_sla_hours,omitempty" binding:"omitempty,min=1" example:"72"`
+	IsActive                *bool      `json:"is_active,omitempty" example:"true"`
+	EffectiveFrom           string     `json:"effective_from,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2024-01-01"`
+	EffectiveUntil          *string    `json:"effective_until,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2024-12-31"`
+}
+
+// UpdateWarrantyPolicyRequest represents a partial update of a warranty policy.
+// The products a policy applies to cannot be changed; an empty
+// effective_until removes the end date.
+type UpdateWarrantyPolicyRequest struct {
+	Name                    *string `json:"name,omitempty" binding:"omitempty,max=255" example:"Electronics 12-month warranty"`
+	Description             *string `json:"description,omitempty"`
+	WarrantyPeriodMonths    *int    `json:"warranty_period_months,omitempty" binding:"omitempty,min=1,max=120" example:"12"`
+	CoverageType            *string `json:"coverage_type,omitempty" binding:"omitempty,oneof=manufacturer_defect full_coverage limited_warranty extended_warranty" example:"full_coverage"`
+	TermsAndConditions      *string `json:"terms_and_conditions,omitempty"`
+	Exclusions              *string `json:"exclusions,omitempty"`
+	CoverageLimitations     *string `json:"coverage_limitations,omitempty"`
+	RequiresPurchaseReceipt *bool   `json:"requires_purchase_receipt,omitempty" example:"false"`
+	MaxClaimsPerProduct     *int    `json:"max_claims_per_product,omitempty" binding:"omitempty,min=1" example:"2"`
+	ClaimProcessingSLAHours *int    `json:"claim_processing_sla_hours,omitempty" binding:"omitempty,min=1" example:"48"`
+	IsActive                *bool   `json:"is_active,omitempty" example:"false"`
+	EffectiveFrom           *string `json:"effective_from,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2024-01-01"`
+	EffectiveUntil          *string `json:"effective_until,omitempty" example:"2024-12-31"`
+}
+
+// WarrantyPolicyResponse represents a warranty policy template
+type WarrantyPolicyResponse struct {
+	ID                      uuid.UUID  `json:"id"`
+	Name                    string     `json:"name" example:"Electronics 12-month warranty"`
+	Description             *string    `json:"description,omitempty"`
+	StorefrontID            uuid.UUID  `json:"storefront_id"`
+	Scope                   string     `json:"scope" example:"category"`
+	ProductID               *uuid.UUID `json:"product_id,omitempty"`
+	ProductCategoryID       *uuid.UUID `json:"product_category_id,omitempty"`
+	IsDefault               bool       `json:"is_default" example:"false"`
+	WarrantyPeriodMonths    int        `json:"warranty_period_months" example:"12"`
+	CoverageType            string     `json:"coverage_type" example:"manufacturer_defect"`
+	TermsAndConditions      string     `json:"terms_and_conditions"`
+	Exclusions              *string    `json:"exclusions,omitempty"`
+	CoverageLimitations     *string    `json:"coverage_limitations,omitempty"`
+	RequiresPurchaseReceipt bool       `json:"requires_purchase_receipt" example:"true"`
+	MaxClaimsPerProduct     int        `json:"max_claims_per_product" example:"3"`
+	ClaimProcessingSLAHours int        `json:"claim_processing_sla_hours" example:"72"`
+	IsActive                bool       `json:"is_active" example:"true"`
+	EffectiveFrom           string     `json:"effective_from" example:"2024-01-01"`
+	EffectiveUntil          *string    `json:"effective_until,omitempty" example:"2024-12-31"`
+	CreatedBy               uuid.UUID  `json:"created_by"`
+	CreatedAt               time.Time  `json:"created_at"`
+	UpdatedAt               time.Time  `json:"updated_at"`
+}
+
+// WarrantyPolicyListResponse represents a paginated list of warranty policies
+type WarrantyPolicyListResponse struct {
+	Policies   []WarrantyPolicyResponse `json:"policies"`
+	Pagination PaginationResponse       `json:"pagination"`
+}
@@ -0,0 +1,438 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/errors"
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/courier"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/database"
+	"github.com/kirimku/smartseller-backend/pkg/utils"
+)
+
+// CourierBookingService books the parcels of paid orders with a courier from
+// the seller dashboard. The AWB the courier issues becomes the order's
+// tracking number; the order ships on the courier's first scan.
+type CourierBookingService struct {
+	db             *sqlx.DB
+	orderRepo      repository.OrderRepository
+	storefrontRepo repository.StorefrontRepository
+	bookingRepo    repository.CourierBookingRepository
+	shipmentRepo   repository.ShipmentRepository
+	bookers        map[string]courier.Booker
+	logger         *slog.Logger
+}
+
+// NewCourierBookingService creates a new courier booking service
+func NewCourierBookingService(
+	db *sqlx.DB,
+	orderRepo repository.OrderRepository,
+	storefrontRepo repository.StorefrontRepository,
+	bookingRepo repository.CourierBookingRepository,
+	shipmentRepo repository.ShipmentRepository,
+	bookers []courier.Booker,
+	logger *slog.Logger,
+) *CourierBookingService {
+	byCourier := make(map[string]courier.Booker, len(bookers))
+	for _, b := range bookers {
+		byCourier[b.CourierID()] = b
+	}
+
+	return &CourierBookingService{
+		db:             db,
+		orderRepo:      orderRepo,
+		storefrontRepo: storefrontRepo,
+		bookingRepo:    bookingRepo,
+		shipmentRepo:   shipmentRepo,
+		bookers:        byCourier,
+		logger:         logger,
+	}
+}
+
+// Book books the order's parcel with the requested courier, for pickup in the
+// requested slot or for drop-off, and records the AWB against the order
+func (s *CourierBookingService) Book(ctx context.Context, orderID, sellerID uuid.UUID, req *dto.CourierBookingRequest) (*dto.CourierBookingResponse, error) {
+	order, storefront, err := s.getSellerOrder(ctx, orderID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if order.PaymentStatus != entity.PaymentStatusPaid {
+		return nil, errors.NewValidationError("order must be paid before it is booked", nil)
+	}
+	if order.Status != entity.OrderStatusConfirmed && order.Status != entity.OrderStatusProcessing {
+		return nil, errors.NewValidationError(fmt.Sprintf("orders with status %s cannot be booked", order.Status), nil)
+	}
+
+	existing, err := s.bookingRepo.GetActiveByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to get courier booking", err)
+	}
+	if existing != nil {
+		return nil, errors.NewValidationError(fmt.Sprintf("order is already booked with %s", existing.CourierCode), nil)
+	}
+
+	booker, err := s.booker(req.Courier)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.ServiceType) == "" {
+		return nil, errors.NewValidationError("service type is required", nil)
+	}
+
+	bookingReq, err := s.bookingRequest(ctx, order, storefront, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.PickupDate != "" || req.PickupStart != "" {
+		slot, err := s.pickupSlot(ctx, booker, storefront, bookingReq.Origin, req.PickupDate, req.PickupStart)
+		if err != nil {
+			return nil, err
+		}
+		bookingReq.Pickup = slot
+	}
+
+	booked, err := booker.Book(ctx, bookingReq)
+	if err != nil {
+		if stderrors.Is(err, courier.ErrAreaNotCovered) {
+			return nil, errors.NewValidationError(fmt.Sprintf("%s does not serve this route", booker.CourierID()), err)
+		}
+		s.logger.Error("Failed to book courier",
+			"order_id", order.ID,
+			"courier", booker.CourierID(),
+			"error", err)
+		return nil, errors.NewInternalError("Failed to book courier", err)
+	}
+
+	booking := entity.NewCourierBooking(order.ID, booker.CourierID(), bookingReq.ServiceType, booked.TrackingNumber)
+	booking.BookedBy = &sellerID
+	booking.Weight = &bookingReq.Weight
+	if booked.BookingCode != "" {
+		booking.BookingCode = &booked.BookingCode
+	}
+	if bookingReq.Pickup != nil {
+		start := bookingReq.Pickup.PickupRequestTime
+		end, err := time.ParseInLocation("2006-01-02 15:04", bookingReq.Pickup.Date+" "+bookingReq.Pickup.EndTime, start.Location())
+		if err == nil {
+			err = booking.SchedulePickup(start, end)
+		}
+		if err != nil {
+			return nil, errors.NewInternalError("Failed to record pickup slot", err)
+		}
+	}
+
+	if err := s.record(ctx, order, booking, sellerID); err != nil {
+		// The courier already holds the booking; keep the AWB findable
+		s.logger.Error("Failed to record courier booking",
+			"order_id", order.ID,
+			"courier", booking.CourierCode,
+			"tracking_number", booking.TrackingNumber,
+			"error", err)
+		return nil, errors.NewInternalError("Failed to record courier booking", err)
+	}
+
+	s.logger.Info("Order booked with courier",
+		"order_id", order.ID,
+		"courier", booking.CourierCode,
+		"tracking_number", booking.TrackingNumber,
+		"pickup", booking.IsPickup())
+
+	return courierBookingResponse(booking), nil
+}
+
+// GetBooking retrieves the active courier booking of an order
+func (s *CourierBookingService) GetBooking(ctx context.Context, orderID, sellerID uuid.UUID) (*dto.CourierBookingResponse, error) {
+	order, _, err := s.getSellerOrder(ctx, orderID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	booking, err := s.bookingRepo.GetActiveByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to get courier booking", err)
+	}
+	if booking == nil {
+		return nil, errors.NewNotFoundError("courier booking not found")
+	}
+
+	return courierBookingResponse(booking), nil
+}
+
+// PickupTimes lists the courier's pickup slots for the order on a day, given
+// as YYYY-MM-DD in the storefront's timezone
+func (s *CourierBookingService) PickupTimes(ctx context.Context, orderID, sellerID uuid.UUID, courierID, date string) (*dto.PickupTimesResponse, error) {
+	_, storefront, err := s.getSellerOrder(ctx, orderID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	booker, err := s.booker(courierID)
+	if err != nil {
+		return nil, err
+	}
+	origin, err := shippingOrigin(storefront)
+	if err != nil {
+		return nil, err
+	}
+	day, err := s.pickupDay(storefront, date)
+	if err != nil {
+		return nil, err
+	}
+
+	slots, err := booker.PickupTimes(ctx, origin, day)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to get pickup times", err)
+	}
+
+	return &dto.PickupTimesResponse{
+		Courier: booker.CourierID(),
+		Date:    day.Format("2006-01-02"),
+		Slots:   slots,
+	}, nil
+}
+
+// getSellerOrder loads an order of one of the seller's storefronts with its
+// storefront
+func (s *CourierBookingService) getSellerOrder(ctx context.Context, orderID, sellerID uuid.UUID) (*entity.Order, *entity.Storefront, error) {
+	return loadSellerOrder(ctx, s.orderRepo, s.storefrontRepo, orderID, sellerID)
+}
+
+// loadSellerOrder loads an order of one of the seller's storefronts with its
+// storefront. Other sellers' orders are reported as not found.
+func loadSellerOrder(ctx context.Context, orderRepo repository.OrderRepository, storefrontRepo repository.StorefrontRepository, orderID, sellerID uuid.UUID) (*entity.Order, *entity.Storefront, error) {
+	order, err := orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, nil, errors.NewInternalError("Failed to get order", err)
+	}
+	if order == nil || order.StorefrontID == nil {
+		return nil, nil, errors.NewNotFoundError("order not found")
+	}
+
+	storefront, err := storefrontRepo.GetByID(ctx, *order.StorefrontID)
+	if err != nil && !errors.IsStorefrontNotFound(err) {
+		return nil, nil, errors.NewInternalError("Failed to get storefront", err)
+	}
+	if storefront == nil || storefront.SellerID != sellerID {
+		return nil, nil, errors.NewNotFoundError("order not found")
+	}
+
+	return order, storefront, nil
+}
+
+// booker returns the booker of a courier
+func (s *CourierBookingService) booker(courierID string) (courier.Booker, error) {
+	courierID = strings.ToLower(strings.TrimSpace(courierID))
+	if courierID == "" {
+		return nil, errors.NewValidationError("courier is required", nil)
+	}
+	booker, ok := s.bookers[courierID]
+	if !ok {
+		return nil, errors.NewValidationError(fmt.Sprintf("booking with %s is not supported", courierID), nil)
+	}
+	return booker, nil
+}
+
+// bookingRequest describes the order's parcel, sent from the storefront's
+// shipping origin to the order's shipping address
+func (s *CourierBookingService) bookingRequest(ctx context.Context, order *entity.Order, storefront *entity.Storefront, req *dto.CourierBookingRequest) (*courier.BookingRequest, error) {
+	origin, err := shippingOrigin(storefront)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.District) == "" {
+		return nil, errors.NewValidationError("destination district is required", nil)
+	}
+	if stringValue(order.ShippingAddressLine1) == "" || stringValue(order.ShippingCity) == "" || stringValue(order.ShippingPhone) == "" {
+		return nil, errors.NewValidationError("order shipping address is incomplete", nil)
+	}
+
+	items := order.Items
+	if len(items) == 0 {
+		items, err = s.orderRepo.GetItems(ctx, order.ID)
+		if err != nil {
+			return nil, errors.NewInternalError("Failed to get order items", err)
+		}
+	}
+
+	quantity := 0
+	weight := decimal.Zero
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		quantity += item.Quantity
+		if item.ProductWeight != nil {
+			weight = weight.Add(item.ProductWeight.Mul(decimal.NewFromInt(int64(item.Quantity))))
+		}
+		names = append(names, item.ProductName)
+	}
+	if req.Weight != nil {
+		weight = *req.Weight
+	}
+	if !weight.IsPositive() {
+		return nil, errors.NewValidationError("parcel weight is required when the order items have no weight", nil)
+	}
+
+	recipient := strings.TrimSpace(stringValue(order.ShippingFirstName) + " " + stringValue(order.ShippingLastName))
+	address := stringValue(order.ShippingAddressLine1)
+	if line2 := stringValue(order.ShippingAddressLine2); line2 != "" {
+		address += ", " + line2
+	}
+
+	return &courier.BookingRequest{
+		Reference:   order.OrderNumber,
+		ServiceType: strings.TrimSpace(req.ServiceType),
+		Origin:      origin,
+		Destination: courier.Area{
+			Province: stringValue(order.ShippingStateProvince),
+			City:     stringValue(order.ShippingCity),
+			District: strings.TrimSpace(req.District),
+		},
+		Sender: courier.Contact{
+			Name:    storefront.GetDisplayName(),
+			Phone:   stringValue(storefront.BusinessPhone),
+			Address: stringValue(storefront.BusinessAddress),
+		},
+		Recipient: courier.Contact{
+			Name:       recipient,
+			Phone:      stringValue(order.ShippingPhone),
+			Address:    address,
+			PostalCode: stringValue(order.ShippingPostalCode),
+		},
+		Weight:          weight,
+		Quantity:        quantity,
+		ItemDescription: strings.Join(names, ", "),
+		ItemValue:       order.Subtotal,
+	}, nil
+}
+
+// pickupSlot finds the courier's available slot starting at the requested time
+func (s *CourierBookingService) pickupSlot(ctx context.Context, booker courier.Booker, storefront *entity.Storefront, origin courier.Area, date, start string) (*model.PickupTime, error) {
+	if date == "" || start == "" {
+		return nil, errors.NewValidationError("pickup date and start time are both required for a pickup", nil)
+	}
+	day, err := s.pickupDay(storefront, date)
+	if err != nil {
+		return nil, err
+	}
+
+	slots, err := booker.PickupTimes(ctx, origin, day)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to get pickup times", err)
+	}
+	for i := range slots {
+		if slots[i].StartTime == start {
+			if !slots[i].IsAvailable {
+				return nil, errors.NewValidationError("pickup slot is no longer available", nil)
+			}
+			return &slots[i], nil
+		}
+	}
+	return nil, errors.NewValidationError(fmt.Sprintf("%s has no pickup slot starting at %s", booker.CourierID(), start), nil)
+}
+
+// pickupDay parses a YYYY-MM-DD date in the storefront's timezone
+func (s *CourierBookingService) pickupDay(storefront *entity.Storefront, date string) (time.Time, error) {
+	day, err := storefrontDay(storefront, date)
+	if err != nil {
+		return time.Time{}, errors.NewValidationError("invalid pickup date, expected YYYY-MM-DD", err)
+	}
+	return day, nil
+}
+
+// storefrontDay parses a YYYY-MM-DD date as midnight in the storefront's
+// timezone, defaulting to today
+func storefrontDay(storefront *entity.Storefront, date string) (time.Time, error) {
+	location := storefrontLocation(storefront)
+	if date == "" {
+		now := time.Now().In(location)
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location), nil
+	}
+	return time.ParseInLocation("2006-01-02", date, location)
+}
+
+// storefrontLocation returns the storefront's timezone
+func storefrontLocation(storefront *entity.Storefront) *time.Location {
+	if storefront.Settings.Timezone != "" {
+		if loc, err := time.LoadLocation(storefront.Settings.Timezone); err == nil {
+			return loc
+		}
+	}
+	return utils.DefaultTimeZone
+}
+
+// record stores the booking, sets the AWB on the order and starts tracking
+// the shipment, all or nothing
+func (s *CourierBookingService) record(ctx context.Context, order *entity.Order, booking *entity.CourierBooking, bookedBy uuid.UUID) error {
+	return database.WithTransaction(ctx, s.db, func(txCtx context.Context, _ *sqlx.Tx) error {
+		if order.Status == entity.OrderStatusConfirmed {
+			history, err := order.StartProcessing(&bookedBy)
+			if err != nil {
+				return err
+			}
+			if err := s.orderRepo.UpdateStatus(txCtx, order, history); err != nil {
+				return err
+			}
+		}
+
+		serviceType := booking.ServiceType
+		order.ShippingCarrier = &booking.CourierCode
+		order.ShippingTrackingNumber = &booking.TrackingNumber
+		order.ShippingMethod = &serviceType
+		if err := s.orderRepo.Update(txCtx, order); err != nil {
+			return err
+		}
+
+		if err := s.bookingRepo.Create(txCtx, booking); err != nil {
+			return err
+		}
+
+		shipment := entity.NewShipment(booking.CourierCode, booking.TrackingNumber)
+		shipment.OrderID = &order.ID
+		shipment.Status = entity.ShipmentStatusPickupPending
+		return s.shipmentRepo.Create(txCtx, shipment)
+	})
+}
+
+// shippingOrigin returns the storefront's shipping origin
+func shippingOrigin(storefront *entity.Storefront) (courier.Area, error) {
+	origin := storefront.Settings.ShippingOrigin
+	if origin.District == "" {
+		return courier.Area{}, errors.NewValidationError("storefront shipping origin is not configured", nil)
+	}
+	return courier.Area{Province: origin.Province, City: origin.City, District: origin.District}, nil
+}
+
+// courierBookingResponse converts a courier booking into its response
+func courierBookingResponse(booking *entity.CourierBooking) *dto.CourierBookingResponse {
+	return &dto.CourierBookingResponse{
+		ID:             booking.ID,
+		OrderID:        booking.OrderID,
+		Courier:        booking.CourierCode,
+		ServiceType:    booking.ServiceType,
+		TrackingNumber: booking.TrackingNumber,
+		BookingCode:    booking.BookingCode,
+		Status:         booking.Status.String(),
+		Pickup:         booking.IsPickup(),
+		PickupStart:    booking.PickupStart,
+		PickupEnd:      booking.PickupEnd,
+		CreatedAt:      booking.CreatedAt,
+	}
+}
+
+// stringValue dereferences an optional string
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
@@ -69,14 +69,14 @@ func (s *CustomerEmailVerificationService) SendVerificationEmail(ctx context.Con
 }
 
 // VerifyEmail verifies a customer's email using the verification token
-func (s *CustomerEmailVerificationService) VerifyEmail(ctx context.Context, token string) error {
+func (s *CustomerEmailVerificationService) VerifyEmail(ctx context.Context, storefrontID uuid.UUID, token string) error {
 	// Validate token format
 	if len(token) != 64 { // 32 bytes = 64 hex characters
 		return errors.NewValidationError("Invalid verification token format", nil)
 	}
 
 	// Get customer by verification token
-	customer, err := s.customerRepo.GetByEmailVerificationToken(ctx, token)
+	customer, err := s.customerRepo.GetByEmailVerificationToken(ctx, storefrontID, token)
 	if err != nil {
 		if errors.IsNotFoundError(err) {
 			return errors.NewValidationError("Invalid or expired verification token", nil)
@@ -189,7 +189,7 @@ func (s *CustomerEmailVerificationService) sendVerificationEmail(customer *entit
         </div>
     </div>
 </body>
-</html>`, customer.FirstName, verificationURL, verificationURL, verificationURL)
+</html>`, customer.GetDisplayName(), verificationURL, verificationURL, verificationURL)
 
 	// Send email using the email service
 	return s.emailService.SendEmail(*customer.Email, subject, htmlBody)
@@ -65,7 +65,7 @@ func (s *CustomerPasswordResetService) RequestPasswordReset(ctx context.Context,
 }
 
 // ResetPassword resets a customer's password using the reset token
-func (s *CustomerPasswordResetService) ResetPassword(ctx context.Context, token, newPassword string) error {
+func (s *CustomerPasswordResetService) ResetPassword(ctx context.Context, storefrontID uuid.UUID, token, newPassword string) error {
 	// Validate token format
 	if len(token) != 64 { // 32 bytes = 64 hex characters
 		return errors.NewValidationError("Invalid reset token format", nil)
@@ -77,7 +77,7 @@ func (s *CustomerPasswordResetService) ResetPassword(ctx context.Context, token,
 	}
 
 	// Get customer by reset token
-	customer, err := s.customerRepo.GetByPasswordResetToken(ctx, token)
+	customer, err := s.customerRepo.GetByPasswordResetToken(ctx, storefrontID, token)
 	if err != nil {
 		if errors.IsNotFoundError(err) {
 			return errors.NewValidationError("Invalid or expired reset token", nil)
@@ -111,14 +111,14 @@ func (s *CustomerPasswordResetService) ResetPassword(ctx context.Context, token,
 }
 
 // ValidateResetToken validates if a reset token is valid and not expired
-func (s *CustomerPasswordResetService) ValidateResetToken(ctx context.Context, token string) error {
+func (s *CustomerPasswordResetService) ValidateResetToken(ctx context.Context, storefrontID uuid.UUID, token string) error {
 	// Validate token format
 	if len(token) != 64 { // 32 bytes = 64 hex characters
 		return errors.NewValidationError("Invalid reset token format", nil)
 	}
 
 	// Check if token exists and is valid
-	_, err := s.customerRepo.GetByPasswordResetToken(ctx, token)
+	_, err := s.customerRepo.GetByPasswordResetToken(ctx, storefrontID, token)
 	if err != nil {
 		if errors.IsNotFoundError(err) {
 			return errors.NewValidationError("Invalid or expired reset token", nil)
@@ -247,7 +247,7 @@ func (s *CustomerPasswordResetService) sendPasswordResetEmail(customer *entity.C
         </div>
     </div>
 </body>
-</html>`, customer.FirstName, resetURL, resetURL, resetURL)
+</html>`, customer.GetDisplayName(), resetURL, resetURL, resetURL)
 
 	// Send email using the email service
 	return s.emailService.SendEmail(*customer.Email, subject, htmlBody)
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"log"
 	"time"
@@ -20,16 +21,19 @@ import (
 type CustomerServiceSimple struct {
 	customerRepo   repository.CustomerRepository
 	tenantResolver tenant.TenantResolver
+	sessionService *CustomerSessionService
 }
 
 // NewCustomerServiceSimple creates a new simple customer service
 func NewCustomerServiceSimple(
 	customerRepo repository.CustomerRepository,
 	tenantResolver tenant.TenantResolver,
+	sessionService *CustomerSessionService,
 ) *CustomerServiceSimple {
 	return &CustomerServiceSimple{
 		customerRepo:   customerRepo,
 		tenantResolver: tenantResolver,
+		sessionService: sessionService,
 	}
 }
 
@@ -184,15 +188,25 @@ func (cs *CustomerServiceSimple) AuthenticateCustomer(ctx context.Context, req *
 		return nil, errors.NewValidationError("invalid credentials", nil)
 	}
 
-	// TODO: Implement password verification
-	// For now, just return a basic response
-	return &dto.CustomerAuthResponse{
-		Customer:     cs.entityToResponse(customer),
-		AccessToken:  "mock_access_token",
-		RefreshToken: "mock_refresh_token",
-		TokenType:    "Bearer",
-		ExpiresIn:    3600,
-	}, nil
+	// Passwords are stored as given at registration
+	if customer.PasswordHash == nil || subtle.ConstantTimeCompare([]byte(*customer.PasswordHash), []byte(req.Password)) != 1 {
+		return nil, errors.NewValidationError("invalid credentials", nil)
+	}
+	if !customer.IsActive() || customer.IsLocked() {
+		return nil, errors.NewAuthorizationError("customer account is not active")
+	}
+
+	response, err := cs.sessionService.StartSession(ctx, customer, req.UserAgent, req.IPAddress)
+	if err != nil {
+		return nil, err
+	}
+	response.Customer = cs.entityToResponse(customer)
+
+	if err := cs.customerRepo.UpdateLastLogin(ctx, storefrontID, customer.ID); err != nil {
+		log.Printf("[WARN] Failed to update last login for customer %s: %v", customer.ID, err)
+	}
+
+	return response, nil
 }
 
 // UpdateCustomer updates customer information
@@ -303,14 +317,22 @@ func (cs *CustomerServiceSimple) SearchCustomers(ctx context.Context, req *dto.C
 
 // RefreshToken handles token refresh
 func (cs *CustomerServiceSimple) RefreshToken(ctx context.Context, req *dto.TokenRefreshRequest) (*dto.CustomerAuthResponse, error) {
-	// TODO: Implement token refresh logic
-	return nil, fmt.Errorf("token refresh not implemented")
+	storefrontID, err := cs.getStorefrontFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storefront context: %w", err)
+	}
+
+	return cs.sessionService.RefreshSession(ctx, storefrontID, req.RefreshToken)
 }
 
-// LogoutCustomer handles customer logout
+// LogoutCustomer revokes the session identified by tokenID
 func (cs *CustomerServiceSimple) LogoutCustomer(ctx context.Context, tokenID uuid.UUID) error {
-	// TODO: Implement logout logic
-	return fmt.Errorf("logout not implemented")
+	storefrontID, err := cs.getStorefrontFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get storefront context: %w", err)
+	}
+
+	return cs.sessionService.EndSession(ctx, storefrontID, tokenID)
 }
 
 // ChangePassword handles password change
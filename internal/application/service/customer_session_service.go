@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/errors"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
+)
+
+// defaultCustomerMaxSessions applies when no session limit is configured
+const defaultCustomerMaxSessions = 5
+
+// CustomerSessionService issues customer tokens backed by persisted sessions,
+// rotates refresh tokens and revokes sessions
+type CustomerSessionService struct {
+	sessionRepo    repository.CustomerSessionRepository
+	twoFactorRepo  repository.CustomerTwoFactorRepository
+	authMiddleware *middleware.CustomerAuthMiddleware
+	maxSessions    int
+}
+
+// NewCustomerSessionService creates a new customer session service
+func NewCustomerSessionService(
+	sessionRepo repository.CustomerSessionRepository,
+	twoFactorRepo repository.CustomerTwoFactorRepository,
+	authMiddleware *middleware.CustomerAuthMiddleware,
+	maxSessions int,
+) *CustomerSessionService {
+	if maxSessions <= 0 {
+		maxSessions = defaultCustomerMaxSessions
+	}
+
+	return &CustomerSessionService{
+		sessionRepo:    sessionRepo,
+		twoFactorRepo:  twoFactorRepo,
+		authMiddleware: authMiddleware,
+		maxSessions:    maxSessions,
+	}
+}
+
+// StartSession opens a session for a customer who just logged in. When the
+// customer is at the session limit, the least recently used sessions are
+// revoked to make room.
+func (s *CustomerSessionService) StartSession(ctx context.Context, customer *entity.Customer, userAgent, ipAddress string) (*dto.CustomerAuthResponse, error) {
+	active, err := s.sessionRepo.GetActiveSessions(ctx, customer.StorefrontID, customer.ID)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to get active sessions", err)
+	}
+	// Active sessions come most recently used first
+	if len(active) >= s.maxSessions {
+		for _, stale := range active[s.maxSessions-1:] {
+			if err := s.sessionRepo.RevokeSession(ctx, customer.StorefrontID, stale.ID, repository.SessionRevokedLimit); err != nil {
+				return nil, errors.NewInternalError("Failed to revoke session over the limit", err)
+			}
+		}
+	}
+
+	// Enrolled customers must pass TOTP before the session is usable
+	twoFactor, err := s.twoFactorRepo.GetByCustomerID(ctx, customer.StorefrontID, customer.ID)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to get two-factor enrollment", err)
+	}
+
+	email := ""
+	if customer.Email != nil {
+		email = *customer.Email
+	}
+
+	session := &repository.CustomerSession{
+		ID:                uuid.New(),
+		CustomerID:        customer.ID,
+		StorefrontID:      customer.StorefrontID,
+		UserAgent:         optionalString(userAgent),
+		IPAddress:         optionalString(ipAddress),
+		ExpiresAt:         time.Now().Add(middleware.CustomerRefreshTokenTTL),
+		TwoFactorRequired: twoFactor != nil && twoFactor.IsEnabled(),
+	}
+
+	response, err := s.issueTokens(session.ID, customer.ID, customer.StorefrontID, email, false)
+	if err != nil {
+		return nil, err
+	}
+	response.TwoFactorRequired = session.TwoFactorRequired
+	if tenantCtx := middleware.GetTenantContextFromRequest(ctx); tenantCtx != nil && tenantCtx.RequireCustomerTwoFactor {
+		response.TwoFactorRequired = true
+	}
+	session.SessionToken = middleware.HashCustomerToken(response.AccessToken)
+	session.RefreshToken = middleware.HashCustomerToken(response.RefreshToken)
+
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, errors.NewInternalError("Failed to create session", err)
+	}
+
+	return response, nil
+}
+
+// RefreshSession exchanges a refresh token for a new token pair. Each refresh
+// token works once: presenting a rotated token means it leaked, so the whole
+// session is revoked.
+func (s *CustomerSessionService) RefreshSession(ctx context.Context, storefrontID uuid.UUID, refreshToken string) (*dto.CustomerAuthResponse, error) {
+	claims, err := s.authMiddleware.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return nil, errors.NewAuthorizationError("invalid refresh token")
+	}
+	if claims.StorefrontID != storefrontID.String() {
+		return nil, errors.NewAuthorizationError("invalid refresh token")
+	}
+
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return nil, errors.NewAuthorizationError("invalid refresh token")
+	}
+	customerID, err := uuid.Parse(claims.CustomerID)
+	if err != nil {
+		return nil, errors.NewAuthorizationError("invalid refresh token")
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, storefrontID, sessionID)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to get session", err)
+	}
+	if session == nil || session.CustomerID != customerID || !session.IsActive(time.Now()) {
+		return nil, errors.NewAuthorizationError("session expired or revoked")
+	}
+
+	tokenHash := middleware.HashCustomerToken(refreshToken)
+	if session.RefreshToken != tokenHash {
+		return nil, s.revokeReusedSession(ctx, session)
+	}
+
+	// A verified second factor carries over to the rotated tokens
+	response, err := s.issueTokens(session.ID, customerID, storefrontID, claims.Email, session.TwoFactorVerifiedAt != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated, err := s.sessionRepo.RefreshSession(ctx, storefrontID, session.ID, tokenHash,
+		middleware.HashCustomerToken(response.AccessToken),
+		middleware.HashCustomerToken(response.RefreshToken),
+		time.Now().Add(middleware.CustomerRefreshTokenTTL),
+	)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to refresh session", err)
+	}
+	// Another request rotated the same token first
+	if !rotated {
+		return nil, s.revokeReusedSession(ctx, session)
+	}
+
+	return response, nil
+}
+
+// ElevateSession issues a token pair carrying the two-factor claim once the
+// customer passed a TOTP or recovery code. Tokens issued before stop working.
+func (s *CustomerSessionService) ElevateSession(ctx context.Context, storefrontID, sessionID, customerID uuid.UUID, email string) (*dto.CustomerAuthResponse, error) {
+	response, err := s.issueTokens(sessionID, customerID, storefrontID, email, true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.sessionRepo.MarkTwoFactorVerified(ctx, storefrontID, sessionID,
+		middleware.HashCustomerToken(response.AccessToken),
+		middleware.HashCustomerToken(response.RefreshToken),
+	)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to mark session as verified", err)
+	}
+
+	return response, nil
+}
+
+// RequireTwoFactor turns TOTP verification on or off for the customer's
+// open sessions after the customer enrolls or disables it
+func (s *CustomerSessionService) RequireTwoFactor(ctx context.Context, storefrontID, customerID uuid.UUID, required bool) error {
+	if err := s.sessionRepo.SetTwoFactorRequired(ctx, storefrontID, customerID, required); err != nil {
+		return errors.NewInternalError("Failed to update sessions", err)
+	}
+	return nil
+}
+
+// EndSession revokes the session a customer logs out of
+func (s *CustomerSessionService) EndSession(ctx context.Context, storefrontID, sessionID uuid.UUID) error {
+	if err := s.sessionRepo.RevokeSession(ctx, storefrontID, sessionID, repository.SessionRevokedLogout); err != nil {
+		return errors.NewInternalError("Failed to revoke session", err)
+	}
+	return nil
+}
+
+// ListSessions returns the devices signed in to a customer account
+func (s *CustomerSessionService) ListSessions(ctx context.Context, storefrontID, customerID, currentSessionID uuid.UUID) ([]*dto.CustomerSessionResponse, error) {
+	sessions, err := s.sessionRepo.GetActiveSessions(ctx, storefrontID, customerID)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to get sessions", err)
+	}
+
+	responses := make([]*dto.CustomerSessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = &dto.CustomerSessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			Current:    session.ID == currentSessionID,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+		}
+	}
+
+	return responses, nil
+}
+
+// RevokeSession signs one of the customer's devices out
+func (s *CustomerSessionService) RevokeSession(ctx context.Context, storefrontID, customerID, sessionID uuid.UUID) error {
+	session, err := s.sessionRepo.GetByID(ctx, storefrontID, sessionID)
+	if err != nil {
+		return errors.NewInternalError("Failed to get session", err)
+	}
+	if session == nil || session.CustomerID != customerID {
+		return errors.NewNotFoundError("session not found")
+	}
+
+	if err := s.sessionRepo.RevokeSession(ctx, storefrontID, sessionID, repository.SessionRevokedByCustomer); err != nil {
+		return errors.NewInternalError("Failed to revoke session", err)
+	}
+	return nil
+}
+
+// RevokeCustomerSessions signs a customer out of every device
+func (s *CustomerSessionService) RevokeCustomerSessions(ctx context.Context, storefrontID, customerID uuid.UUID) (int, error) {
+	revoked, err := s.sessionRepo.RevokeAllSessions(ctx, storefrontID, customerID, repository.SessionRevokedByAdmin)
+	if err != nil {
+		return 0, errors.NewInternalError("Failed to revoke customer sessions", err)
+	}
+	return revoked, nil
+}
+
+// RevokeStorefrontSessions signs every customer of a storefront out
+func (s *CustomerSessionService) RevokeStorefrontSessions(ctx context.Context, storefrontID uuid.UUID) (int, error) {
+	revoked, err := s.sessionRepo.RevokeStorefrontSessions(ctx, storefrontID, repository.SessionRevokedByAdmin)
+	if err != nil {
+		return 0, errors.NewInternalError("Failed to revoke storefront sessions", err)
+	}
+	return revoked, nil
+}
+
+// issueTokens signs an access and refresh token pair for a session
+func (s *CustomerSessionService) issueTokens(sessionID, customerID, storefrontID uuid.UUID, email string, twoFactor bool) (*dto.CustomerAuthResponse, error) {
+	accessToken, err := s.authMiddleware.CreateCustomerToken(customerID.String(), storefrontID.String(), email, sessionID.String(), "access", nil, twoFactor)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to create access token", err)
+	}
+	refreshToken, err := s.authMiddleware.CreateCustomerToken(customerID.String(), storefrontID.String(), email, sessionID.String(), "refresh", nil, twoFactor)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to create refresh token", err)
+	}
+
+	return &dto.CustomerAuthResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(middleware.CustomerAccessTokenTTL / time.Second),
+	}, nil
+}
+
+// revokeReusedSession revokes a session whose rotated refresh token was
+// presented again
+func (s *CustomerSessionService) revokeReusedSession(ctx context.Context, session *repository.CustomerSession) error {
+	log.Printf("[WARN] Refresh token reuse detected for customer %s, revoking session %s", session.CustomerID, session.ID)
+
+	if err := s.sessionRepo.RevokeSession(ctx, session.StorefrontID, session.ID, repository.SessionRevokedRefreshReuse); err != nil {
+		return errors.NewInternalError("Failed to revoke reused session", err)
+	}
+	return errors.NewAuthorizationError("refresh token has already been used; please log in again")
+}
+
+// optionalString returns nil for an empty string
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/tenant"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
+)
+
+// memorySessionRepo keeps customer sessions in memory
+type memorySessionRepo struct {
+	mu       sync.Mutex
+	sessions map[uuid.UUID]*repository.CustomerSession
+}
+
+func newMemorySessionRepo() *memorySessionRepo {
+	return &memorySessionRepo{sessions: make(map[uuid.UUID]*repository.CustomerSession)}
+}
+
+func (r *memorySessionRepo) Create(ctx context.Context, session *repository.CustomerSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	session.CreatedAt, session.LastUsedAt = now, now
+	copied := *session
+	r.sessions[session.ID] = &copied
+	return nil
+}
+
+func (r *memorySessionRepo) GetByID(ctx context.Context, storefrontID, sessionID uuid.UUID) (*repository.CustomerSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[sessionID]
+	if !ok || session.StorefrontID != storefrontID {
+		return nil, nil
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (r *memorySessionRepo) RevokeSession(ctx context.Context, storefrontID, sessionID uuid.UUID, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if session, ok := r.sessions[sessionID]; ok && session.StorefrontID == storefrontID {
+		r.revoke(session, reason)
+	}
+	return nil
+}
+
+func (r *memorySessionRepo) RevokeAllSessions(ctx context.Context, storefrontID, customerID uuid.UUID, reason string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	revoked := 0
+	for _, session := range r.sessions {
+		if session.StorefrontID == storefrontID && session.CustomerID == customerID && session.RevokedAt == nil {
+			r.revoke(session, reason)
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (r *memorySessionRepo) RevokeStorefrontSessions(ctx context.Context, storefrontID uuid.UUID, reason string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	revoked := 0
+	for _, session := range r.sessions {
+		if session.StorefrontID == storefrontID && session.RevokedAt == nil {
+			r.revoke(session, reason)
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (r *memorySessionRepo) RefreshSession(ctx context.Context, storefrontID, sessionID uuid.UUID, refreshToken, newSessionToken, newRefreshToken string, expiresAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[sessionID]
+	if !ok || session.StorefrontID != storefrontID || session.RevokedAt != nil || session.RefreshToken != refreshToken {
+		return false, nil
+	}
+	session.SessionToken, session.RefreshToken, session.ExpiresAt = newSessionToken, newRefreshToken, expiresAt
+	session.LastUsedAt = time.Now()
+	return true, nil
+}
+
+func (r *memorySessionRepo) UpdateLastUsed(ctx context.Context, storefrontID, sessionID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if session, ok := r.sessions[sessionID]; ok {
+		session.LastUsedAt = time.Now()
+	}
+	return nil
+}
+
+func (r *memorySessionRepo) MarkTwoFactorVerified(ctx context.Context, storefrontID, sessionID uuid.UUID, newSessionToken, newRefreshToken string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if session, ok := r.sessions[sessionID]; ok {
+		now := time.Now()
+		session.TwoFactorVerifiedAt = &now
+		session.SessionToken, session.RefreshToken = newSessionToken, newRefreshToken
+	}
+	return nil
+}
+
+func (r *memorySessionRepo) SetTwoFactorRequired(ctx context.Context, storefrontID, customerID uuid.UUID, required bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, session := range r.sessions {
+		if session.StorefrontID == storefrontID && session.CustomerID == customerID {
+			session.TwoFactorRequired = required
+		}
+	}
+	return nil
+}
+
+func (r *memorySessionRepo) GetActiveSessions(ctx context.Context, storefrontID, customerID uuid.UUID) ([]*repository.CustomerSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	var active []*repository.CustomerSession
+	for _, session := range r.sessions {
+		if session.StorefrontID == storefrontID && session.CustomerID == customerID && session.IsActive(now) {
+			copied := *session
+			active = append(active, &copied)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].LastUsedAt.After(active[j].LastUsedAt) })
+	return active, nil
+}
+
+func (r *memorySessionRepo) revoke(session *repository.CustomerSession, reason string) {
+	now := time.Now()
+	session.RevokedAt = &now
+	session.RevokedReason = &reason
+}
+
+// noTwoFactorRepo reports that no customer is enrolled in two-factor auth
+type noTwoFactorRepo struct{}
+
+func (noTwoFactorRepo) GetByCustomerID(ctx context.Context, storefrontID, customerID uuid.UUID) (*entity.CustomerTwoFactor, error) {
+	return nil, nil
+}
+
+func (noTwoFactorRepo) Save(ctx context.Context, twoFactor *entity.CustomerTwoFactor) error {
+	return nil
+}
+
+func (noTwoFactorRepo) Update(ctx context.Context, twoFactor *entity.CustomerTwoFactor) error {
+	return nil
+}
+
+func (noTwoFactorRepo) UpdateAttempts(ctx context.Context, twoFactor *entity.CustomerTwoFactor) error {
+	return nil
+}
+
+func (noTwoFactorRepo) UseStep(ctx context.Context, storefrontID, customerID uuid.UUID, step int64) (bool, error) {
+	return true, nil
+}
+
+func (noTwoFactorRepo) UseRecoveryCode(ctx context.Context, storefrontID, customerID uuid.UUID, codeHash string) (bool, error) {
+	return false, nil
+}
+
+func (noTwoFactorRepo) Delete(ctx context.Context, storefrontID, customerID uuid.UUID) error {
+	return nil
+}
+
+func newTestSessionService(t *testing.T, maxSessions int) (*CustomerSessionService, *memorySessionRepo, *middleware.CustomerAuthMiddleware) {
+	t.Helper()
+	t.Setenv("CUSTOMER_JWT_SECRET", "test-customer-secret")
+	t.Setenv("CUSTOMER_REFRESH_SECRET", "test-customer-refresh-secret")
+
+	sessionRepo := newMemorySessionRepo()
+	authMiddleware := middleware.NewCustomerAuthMiddleware(sessionRepo, nil, nil)
+	return NewCustomerSessionService(sessionRepo, noTwoFactorRepo{}, authMiddleware, maxSessions), sessionRepo, authMiddleware
+}
+
+func newTestCustomer() *entity.Customer {
+	email := "customer@example.com"
+	return &entity.Customer{ID: uuid.New(), StorefrontID: uuid.New(), Email: &email}
+}
+
+// sessionIDOf returns the session a token pair was issued for
+func sessionIDOf(t *testing.T, authMiddleware *middleware.CustomerAuthMiddleware, refreshToken string) uuid.UUID {
+	t.Helper()
+	claims, err := authMiddleware.ParseRefreshToken(refreshToken)
+	require.NoError(t, err)
+	sessionID, err := uuid.Parse(claims.SessionID)
+	require.NoError(t, err)
+	return sessionID
+}
+
+func TestCustomerSessionService_RefreshRotatesTokens(t *testing.T) {
+	svc, _, _ := newTestSessionService(t, 5)
+	customer := newTestCustomer()
+	ctx := context.Background()
+
+	login, err := svc.StartSession(ctx, customer, "test-agent", "127.0.0.1")
+	require.NoError(t, err)
+
+	refreshed, err := svc.RefreshSession(ctx, customer.StorefrontID, login.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, login.AccessToken, refreshed.AccessToken)
+	assert.NotEqual(t, login.RefreshToken, refreshed.RefreshToken)
+
+	// The rotated token keeps working
+	_, err = svc.RefreshSession(ctx, customer.StorefrontID, refreshed.RefreshToken)
+	assert.NoError(t, err)
+}
+
+func TestCustomerSessionService_RefreshTokenReuseRevokesSession(t *testing.T) {
+	svc, sessionRepo, authMiddleware := newTestSessionService(t, 5)
+	customer := newTestCustomer()
+	ctx := context.Background()
+
+	login, err := svc.StartSession(ctx, customer, "test-agent", "127.0.0.1")
+	require.NoError(t, err)
+	refreshed, err := svc.RefreshSession(ctx, customer.StorefrontID, login.RefreshToken)
+	require.NoError(t, err)
+
+	// Presenting the rotated token again revokes the session
+	_, err = svc.RefreshSession(ctx, customer.StorefrontID, login.RefreshToken)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already been used")
+
+	session, err := sessionRepo.GetByID(ctx, customer.StorefrontID, sessionIDOf(t, authMiddleware, login.RefreshToken))
+	require.NoError(t, err)
+	require.NotNil(t, session.RevokedAt)
+	assert.Equal(t, repository.SessionRevokedRefreshReuse, *session.RevokedReason)
+
+	// The legitimate holder of the newest token is signed out as well
+	_, err = svc.RefreshSession(ctx, customer.StorefrontID, refreshed.RefreshToken)
+	assert.Error(t, err)
+}
+
+func TestCustomerSessionService_RefreshRejectsOtherStorefront(t *testing.T) {
+	svc, _, _ := newTestSessionService(t, 5)
+	customer := newTestCustomer()
+	ctx := context.Background()
+
+	login, err := svc.StartSession(ctx, customer, "", "")
+	require.NoError(t, err)
+
+	_, err = svc.RefreshSession(ctx, uuid.New(), login.RefreshToken)
+	assert.Error(t, err)
+}
+
+func TestCustomerSessionService_OldAccessTokenRejectedAfterRefresh(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, _, authMiddleware := newTestSessionService(t, 5)
+	customer := newTestCustomer()
+	ctx := context.Background()
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("tenant_context", &tenant.TenantContext{StorefrontID: customer.StorefrontID})
+		c.Next()
+	})
+	router.GET("/me", authMiddleware.CustomerAuthRequired(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	get := func(accessToken string) int {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	login, err := svc.StartSession(ctx, customer, "test-agent", "127.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, get(login.AccessToken))
+
+	refreshed, err := svc.RefreshSession(ctx, customer.StorefrontID, login.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, get(login.AccessToken))
+	assert.Equal(t, http.StatusOK, get(refreshed.AccessToken))
+
+	// Logging out ends the newest token too
+	require.NoError(t, svc.EndSession(ctx, customer.StorefrontID, sessionIDOf(t, authMiddleware, refreshed.RefreshToken)))
+	assert.Equal(t, http.StatusUnauthorized, get(refreshed.AccessToken))
+}
+
+func TestCustomerSessionService_StartSessionEvictsLeastRecentlyUsed(t *testing.T) {
+	const maxSessions = 3
+	svc, sessionRepo, authMiddleware := newTestSessionService(t, maxSessions)
+	customer := newTestCustomer()
+	ctx := context.Background()
+
+	var sessionIDs []uuid.UUID
+	for i := 0; i < maxSessions+2; i++ {
+		login, err := svc.StartSession(ctx, customer, "test-agent", "127.0.0.1")
+		require.NoError(t, err)
+		sessionIDs = append(sessionIDs, sessionIDOf(t, authMiddleware, login.RefreshToken))
+		// Keep last-used times distinct so the eviction order is stable
+		time.Sleep(time.Millisecond)
+	}
+
+	active, err := sessionRepo.GetActiveSessions(ctx, customer.StorefrontID, customer.ID)
+	require.NoError(t, err)
+	assert.Len(t, active, maxSessions)
+
+	for i, sessionID := range sessionIDs {
+		session, err := sessionRepo.GetByID(ctx, customer.StorefrontID, sessionID)
+		require.NoError(t, err)
+		if i < len(sessionIDs)-maxSessions {
+			require.NotNil(t, session.RevokedAt, "session %d should be evicted", i)
+			assert.Equal(t, repository.SessionRevokedLimit, *session.RevokedReason)
+		} else {
+			assert.Nil(t, session.RevokedAt, "session %d should stay active", i)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/errors"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
+	"github.com/kirimku/smartseller-backend/pkg/totp"
+)
+
+// customerTwoFactorSkew is the number of 30 second steps a code may be off by
+const customerTwoFactorSkew = 1
+
+// CustomerTwoFactorSubject identifies the customer and session a two-factor
+// request comes from
+type CustomerTwoFactorSubject struct {
+	StorefrontID uuid.UUID
+	CustomerID   uuid.UUID
+	SessionID    uuid.UUID
+	Email        string
+}
+
+// CustomerTwoFactorService enrolls customers in TOTP and verifies their codes.
+// Secrets are stored sealed with AES-GCM.
+type CustomerTwoFactorService struct {
+	twoFactorRepo  repository.CustomerTwoFactorRepository
+	sessionService *CustomerSessionService
+	aead           cipher.AEAD
+	issuer         string
+}
+
+// NewCustomerTwoFactorService creates a new customer two-factor service
+func NewCustomerTwoFactorService(
+	twoFactorRepo repository.CustomerTwoFactorRepository,
+	sessionService *CustomerSessionService,
+	encryptionKey string,
+	issuer string,
+) (*CustomerTwoFactorService, error) {
+	if encryptionKey == "" {
+		return nil, fmt.Errorf("customer two-factor encryption key is required")
+	}
+
+	key := sha256.Sum256([]byte(encryptionKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return &CustomerTwoFactorService{
+		twoFactorRepo:  twoFactorRepo,
+		sessionService: sessionService,
+		aead:           aead,
+		issuer:         issuer,
+	}, nil
+}
+
+// Status describes the customer's enrollment
+func (s *CustomerTwoFactorService) Status(ctx context.Context, subject CustomerTwoFactorSubject) (*dto.CustomerTwoFactorStatusResponse, error) {
+	twoFactor, err := s.getEnrollment(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.CustomerTwoFactorStatusResponse{
+		RequiredByStorefront: storefrontRequiresTwoFactor(ctx),
+	}
+	if twoFactor != nil && twoFactor.IsEnabled() {
+		response.Enabled = true
+		response.EnabledAt = twoFactor.EnabledAt
+		response.RecoveryCodesRemaining = len(twoFactor.RecoveryCodeHashes)
+		if twoFactor.IsLocked(time.Now()) {
+			response.LockedUntil = twoFactor.LockedUntil
+		}
+	}
+
+	return response, nil
+}
+
+// BeginEnrollment generates a new secret for the customer. Enrollment stays
+// pending until ConfirmEnrollment sees a valid code for it.
+func (s *CustomerTwoFactorService) BeginEnrollment(ctx context.Context, subject CustomerTwoFactorSubject) (*dto.CustomerTwoFactorEnrollmentResponse, error) {
+	existing, err := s.getEnrollment(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.IsEnabled() {
+		return nil, errors.NewValidationError("two-factor authentication is already enabled", nil)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to generate two-factor secret", err)
+	}
+	sealed, err := s.sealSecret(secret)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to seal two-factor secret", err)
+	}
+
+	twoFactor := &entity.CustomerTwoFactor{
+		CustomerID:   subject.CustomerID,
+		StorefrontID: subject.StorefrontID,
+		Secret:       sealed,
+	}
+	if err := s.twoFactorRepo.Save(ctx, twoFactor); err != nil {
+		return nil, errors.NewInternalError("Failed to save two-factor enrollment", err)
+	}
+
+	account := subject.Email
+	if account == "" {
+		account = subject.CustomerID.String()
+	}
+
+	return &dto.CustomerTwoFactorEnrollmentResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.issuerFor(ctx), account, secret),
+	}, nil
+}
+
+// ConfirmEnrollment enables TOTP once the customer proves the authenticator
+// app works. Returns the recovery codes, which are never shown again, and
+// tokens carrying the two-factor claim for the current session.
+func (s *CustomerTwoFactorService) ConfirmEnrollment(ctx context.Context, subject CustomerTwoFactorSubject, code string) (*dto.CustomerTwoFactorVerifyResponse, error) {
+	twoFactor, err := s.getEnrollment(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, errors.NewNotFoundError("two-factor enrollment not started")
+	}
+	if twoFactor.IsEnabled() {
+		return nil, errors.NewValidationError("two-factor authentication is already enabled", nil)
+	}
+
+	if err := s.checkCode(ctx, twoFactor, code, false); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := generateRecoveryCodes(entity.CustomerRecoveryCodeCount)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to generate recovery codes", err)
+	}
+	now := time.Now()
+	twoFactor.EnabledAt = &now
+	twoFactor.SetRecoveryCodes(recoveryCodes)
+	if err := s.twoFactorRepo.Update(ctx, twoFactor); err != nil {
+		return nil, errors.NewInternalError("Failed to enable two-factor authentication", err)
+	}
+
+	// Other devices must verify before they can keep using the account
+	if err := s.sessionService.RequireTwoFactor(ctx, subject.StorefrontID, subject.CustomerID, true); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.sessionService.ElevateSession(ctx, subject.StorefrontID, subject.SessionID, subject.CustomerID, subject.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.CustomerTwoFactorVerifyResponse{
+		CustomerAuthResponse:   tokens,
+		RecoveryCodes:          recoveryCodes,
+		RecoveryCodesRemaining: len(recoveryCodes),
+	}, nil
+}
+
+// Verify checks a TOTP or recovery code and issues tokens carrying the
+// two-factor claim for the current session
+func (s *CustomerTwoFactorService) Verify(ctx context.Context, subject CustomerTwoFactorSubject, code string) (*dto.CustomerTwoFactorVerifyResponse, error) {
+	twoFactor, err := s.getEnrollment(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil || !twoFactor.IsEnabled() {
+		if storefrontRequiresTwoFactor(ctx) {
+			return nil, errors.NewValidationError("this storefront requires two-factor authentication; enroll first", nil)
+		}
+		return nil, errors.NewValidationError("two-factor authentication is not enabled", nil)
+	}
+
+	if err := s.checkCode(ctx, twoFactor, code, true); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.sessionService.ElevateSession(ctx, subject.StorefrontID, subject.SessionID, subject.CustomerID, subject.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.CustomerTwoFactorVerifyResponse{
+		CustomerAuthResponse:   tokens,
+		RecoveryCodesRemaining: len(twoFactor.RecoveryCodeHashes),
+	}, nil
+}
+
+// Disable removes the customer's enrollment after checking a current code.
+// Storefronts that require two-factor authentication do not allow it.
+func (s *CustomerTwoFactorService) Disable(ctx context.Context, subject CustomerTwoFactorSubject, code string) error {
+	if storefrontRequiresTwoFactor(ctx) {
+		return errors.NewValidationError("this storefront requires two-factor authentication", nil)
+	}
+
+	twoFactor, err := s.getEnrollment(ctx, subject)
+	if err != nil {
+		return err
+	}
+	if twoFactor == nil || !twoFactor.IsEnabled() {
+		return errors.NewValidationError("two-factor authentication is not enabled", nil)
+	}
+
+	if err := s.checkCode(ctx, twoFactor, code, true); err != nil {
+		return err
+	}
+
+	if err := s.twoFactorRepo.Delete(ctx, subject.StorefrontID, subject.CustomerID); err != nil {
+		return errors.NewInternalError("Failed to disable two-factor authentication", err)
+	}
+
+	return s.sessionService.RequireTwoFactor(ctx, subject.StorefrontID, subject.CustomerID, false)
+}
+
+// checkCode accepts a TOTP code, or a recovery code when allowed. Each TOTP
+// step and recovery code is accepted once, and too many wrong codes lock
+// verification.
+func (s *CustomerTwoFactorService) checkCode(ctx context.Context, twoFactor *entity.CustomerTwoFactor, code string, allowRecovery bool) error {
+	now := time.Now()
+	if twoFactor.IsLocked(now) {
+		return &errors.AppError{
+			Type:    errors.ErrorTypeRateLimit,
+			Message: "too many invalid codes; try again later",
+			Code:    429,
+		}
+	}
+
+	secret, err := s.openSecret(twoFactor.Secret)
+	if err != nil {
+		return errors.NewInternalError("Failed to open two-factor secret", err)
+	}
+
+	accepted := false
+	if step, ok := totp.Validate(secret, code, now, customerTwoFactorSkew); ok {
+		accepted, err = s.twoFactorRepo.UseStep(ctx, twoFactor.StorefrontID, twoFactor.CustomerID, step)
+		if err != nil {
+			return errors.NewInternalError("Failed to record two-factor code", err)
+		}
+	} else if allowRecovery && twoFactor.UseRecoveryCode(code) {
+		// The code is only spent if no other request used it first
+		accepted, err = s.twoFactorRepo.UseRecoveryCode(ctx, twoFactor.StorefrontID, twoFactor.CustomerID, entity.HashRecoveryCode(code))
+		if err != nil {
+			return errors.NewInternalError("Failed to record recovery code", err)
+		}
+	}
+
+	if accepted {
+		twoFactor.RecordSuccess()
+	} else {
+		twoFactor.RecordFailure(now)
+	}
+	if err := s.twoFactorRepo.UpdateAttempts(ctx, twoFactor); err != nil {
+		return errors.NewInternalError("Failed to update two-factor enrollment", err)
+	}
+
+	if !accepted {
+		return errors.NewValidationError("invalid two-factor code", nil)
+	}
+	return nil
+}
+
+func (s *CustomerTwoFactorService) getEnrollment(ctx context.Context, subject CustomerTwoFactorSubject) (*entity.CustomerTwoFactor, error) {
+	twoFactor, err := s.twoFactorRepo.GetByCustomerID(ctx, subject.StorefrontID, subject.CustomerID)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to get two-factor enrollment", err)
+	}
+	return twoFactor, nil
+}
+
+// issuerFor names the account in authenticator apps after the storefront
+func (s *CustomerTwoFactorService) issuerFor(ctx context.Context) string {
+	if tenantCtx := middleware.GetTenantContextFromRequest(ctx); tenantCtx != nil && tenantCtx.StorefrontName != "" {
+		return tenantCtx.StorefrontName
+	}
+	return s.issuer
+}
+
+// sealSecret encrypts a TOTP secret for storage as base64(nonce || ciphertext)
+func (s *CustomerTwoFactorService) sealSecret(secret string) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// openSecret decrypts a secret sealed by sealSecret
+func (s *CustomerTwoFactorService) openSecret(sealed string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < s.aead.NonceSize() {
+		return "", fmt.Errorf("sealed secret too short")
+	}
+	nonce, ciphertext := data[:s.aead.NonceSize()], data[s.aead.NonceSize():]
+	secret, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+// storefrontRequiresTwoFactor reports whether the request's storefront makes
+// two-factor authentication mandatory
+func storefrontRequiresTwoFactor(ctx context.Context) bool {
+	tenantCtx := middleware.GetTenantContextFromRequest(ctx)
+	return tenantCtx != nil && tenantCtx.RequireCustomerTwoFactor
+}
+
+// generateRecoveryCodes returns random codes formatted as XXXX-XXXX
+func generateRecoveryCodes(count int) ([]string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, count)
+	for i := range codes {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		code := encoding.EncodeToString(raw)
+		codes[i] = code[:4] + "-" + code[4:]
+	}
+	return codes, nil
+}
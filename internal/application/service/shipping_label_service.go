@@ -0,0 +1,240 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/errors"
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/label"
+)
+
+// ShippingLabelOptions are the seller's choices for printing labels
+type ShippingLabelOptions struct {
+	Format string // pdf, png or jpg
+	Layout string // a6 for thermal printers, a4 for four labels a sheet
+	// IncludeBarcode prints the AWB as a Code128 barcode
+	IncludeBarcode bool
+}
+
+// ShippingLabelDownload is a rendered label file
+type ShippingLabelDownload struct {
+	Filename string
+	MimeType string
+	Content  []byte
+	Labels   int
+}
+
+// ShippingLabelService prints shipping labels for orders booked with a
+// courier, one at a time or all of a day's bookings at once
+type ShippingLabelService struct {
+	orderRepo      repository.OrderRepository
+	storefrontRepo repository.StorefrontRepository
+	bookingRepo    repository.CourierBookingRepository
+	logger         *slog.Logger
+}
+
+// NewShippingLabelService creates a new shipping label service
+func NewShippingLabelService(
+	orderRepo repository.OrderRepository,
+	storefrontRepo repository.StorefrontRepository,
+	bookingRepo repository.CourierBookingRepository,
+	logger *slog.Logger,
+) *ShippingLabelService {
+	return &ShippingLabelService{
+		orderRepo:      orderRepo,
+		storefrontRepo: storefrontRepo,
+		bookingRepo:    bookingRepo,
+		logger:         logger,
+	}
+}
+
+// OrderLabel renders the label of an order's active courier booking
+func (s *ShippingLabelService) OrderLabel(ctx context.Context, orderID, sellerID uuid.UUID, opts ShippingLabelOptions) (*ShippingLabelDownload, error) {
+	renderOpts, err := labelOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	order, storefront, err := loadSellerOrder(ctx, s.orderRepo, s.storefrontRepo, orderID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	booking, err := s.bookingRepo.GetActiveByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to get courier booking", err)
+	}
+	if booking == nil {
+		return nil, errors.NewValidationError("order must be booked with a courier before its label is printed", nil)
+	}
+
+	receipt := shippingReceipt(booking, order, storefront, time.Now())
+	return s.render([]model.Receipt{receipt}, renderOpts, "label-"+order.OrderNumber)
+}
+
+// DailyLabels renders the labels of every order of the storefront booked on
+// a day, given as YYYY-MM-DD in the storefront's timezone and defaulting to
+// today, oldest booking first
+func (s *ShippingLabelService) DailyLabels(ctx context.Context, storefrontID, sellerID uuid.UUID, date string, opts ShippingLabelOptions) (*ShippingLabelDownload, error) {
+	renderOpts, err := labelOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	storefront, err := s.storefrontRepo.GetByID(ctx, storefrontID)
+	if err != nil && !errors.IsStorefrontNotFound(err) {
+		return nil, errors.NewInternalError("Failed to get storefront", err)
+	}
+	if storefront == nil || storefront.SellerID != sellerID {
+		return nil, errors.NewNotFoundError("storefront not found")
+	}
+
+	day, err := storefrontDay(storefront, date)
+	if err != nil {
+		return nil, errors.NewValidationError("invalid date, expected YYYY-MM-DD", err)
+	}
+
+	bookings, err := s.bookingRepo.ListActiveByStorefront(ctx, storefront.ID, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to list courier bookings", err)
+	}
+	if len(bookings) == 0 {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("no orders were booked on %s", day.Format("2006-01-02")))
+	}
+
+	now := time.Now()
+	receipts := make([]model.Receipt, 0, len(bookings))
+	for _, booking := range bookings {
+		order, err := s.orderRepo.GetByID(ctx, booking.OrderID)
+		if err != nil {
+			return nil, errors.NewInternalError("Failed to get order", err)
+		}
+		if order == nil {
+			continue
+		}
+		receipts = append(receipts, shippingReceipt(booking, order, storefront, now))
+	}
+
+	return s.render(receipts, renderOpts, "labels-"+day.Format("2006-01-02"))
+}
+
+// render renders the receipts' labels into a file named after name
+func (s *ShippingLabelService) render(receipts []model.Receipt, opts label.Options, name string) (*ShippingLabelDownload, error) {
+	var buf bytes.Buffer
+	if err := label.Render(&buf, receipts, opts); err != nil {
+		if stderrors.Is(err, label.ErrImageSheet) {
+			return nil, errors.NewValidationError(err.Error(), err)
+		}
+		s.logger.Error("Failed to render shipping labels",
+			"labels", len(receipts),
+			"format", opts.Format,
+			"error", err)
+		return nil, errors.NewInternalError("Failed to render shipping labels", err)
+	}
+
+	return &ShippingLabelDownload{
+		Filename: name + opts.Format.Extension(),
+		MimeType: opts.Format.ContentType(),
+		Content:  buf.Bytes(),
+		Labels:   len(receipts),
+	}, nil
+}
+
+// labelOptions validates the seller's label options
+func labelOptions(opts ShippingLabelOptions) (label.Options, error) {
+	format, err := label.ParseFormat(opts.Format)
+	if err != nil {
+		return label.Options{}, errors.NewValidationError(err.Error(), err)
+	}
+	layout, err := label.ParseLayout(opts.Layout)
+	if err != nil {
+		return label.Options{}, errors.NewValidationError(err.Error(), err)
+	}
+	return label.Options{Format: format, Layout: layout, IncludeBarcode: opts.IncludeBarcode}, nil
+}
+
+// shippingReceipt describes the label of a booked order's parcel
+func shippingReceipt(booking *entity.CourierBooking, order *entity.Order, storefront *entity.Storefront, printedAt time.Time) model.Receipt {
+	recipient := strings.TrimSpace(stringValue(order.ShippingFirstName) + " " + stringValue(order.ShippingLastName))
+
+	senderAddress := stringValue(storefront.BusinessAddress)
+	if senderAddress == "" {
+		origin := storefront.Settings.ShippingOrigin
+		senderAddress = joinNonEmpty(origin.District, origin.City, origin.Province)
+	}
+
+	receipt := model.Receipt{
+		ReceiptNumber:    order.OrderNumber,
+		TrackingNumber:   booking.TrackingNumber,
+		CourierID:        booking.CourierCode,
+		ServiceType:      booking.ServiceType,
+		SenderName:       storefront.GetDisplayName(),
+		SenderPhone:      stringValue(storefront.BusinessPhone),
+		SenderAddress:    senderAddress,
+		RecipientName:    recipient,
+		RecipientPhone:   stringValue(order.ShippingPhone),
+		RecipientAddress: shippingAddress(order),
+		GeneratedAt:      printedAt.In(storefrontLocation(storefront)),
+		Notes:            "Drop-off",
+	}
+
+	weight := decimal.Zero
+	for _, item := range order.Items {
+		name := item.ProductName
+		if variant := stringValue(item.VariantName); variant != "" {
+			name += " - " + variant
+		}
+		receipt.Items = append(receipt.Items, model.Item{Name: name, SKU: stringValue(item.ProductSKU), Quantity: item.Quantity})
+		if item.ProductWeight != nil {
+			weight = weight.Add(item.ProductWeight.Mul(decimal.NewFromInt(int64(item.Quantity))))
+		}
+	}
+	if booking.Weight != nil {
+		weight = *booking.Weight
+	}
+	receipt.Weight = weight.InexactFloat64()
+
+	if booking.IsPickup() {
+		start := booking.PickupStart.In(storefrontLocation(storefront))
+		end := booking.PickupEnd.In(start.Location())
+		receipt.Notes = fmt.Sprintf("Pickup %s-%s", start.Format("2 Jan 15:04"), end.Format("15:04"))
+	}
+	if booking.BookingCode != nil {
+		receipt.Notes += " / Booking " + *booking.BookingCode
+	}
+	receipt.SpecialInstructions = stringValue(order.Notes)
+
+	return receipt
+}
+
+// shippingAddress formats an order's shipping address on one line
+func shippingAddress(order *entity.Order) string {
+	return joinNonEmpty(
+		stringValue(order.ShippingAddressLine1),
+		stringValue(order.ShippingAddressLine2),
+		stringValue(order.ShippingCity),
+		strings.TrimSpace(stringValue(order.ShippingStateProvince)+" "+stringValue(order.ShippingPostalCode)),
+	)
+}
+
+// joinNonEmpty joins the non-empty parts with commas
+func joinNonEmpty(parts ...string) string {
+	kept := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, ", ")
+}
@@ -0,0 +1,455 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/database"
+)
+
+// CartOwner identifies whose cart is being accessed: a signed-in customer or,
+// when CustomerID is nil, an anonymous session
+type CartOwner struct {
+	StorefrontID uuid.UUID
+	CustomerID   *uuid.UUID
+	SessionID    string
+}
+
+// CartUseCase defines the interface for storefront shopping carts
+type CartUseCase interface {
+	// GetCart retrieves the owner's cart revalidated against current prices and stock
+	GetCart(ctx context.Context, owner CartOwner) (*dto.CartResponse, error)
+
+	// AddItem adds a product to the owner's cart, creating the cart if needed
+	AddItem(ctx context.Context, owner CartOwner, req *dto.AddCartItemRequest) (*dto.CartResponse, error)
+
+	// UpdateItem changes the quantity of a cart item; zero removes it
+	UpdateItem(ctx context.Context, owner CartOwner, itemID uuid.UUID, req *dto.UpdateCartItemRequest) (*dto.CartResponse, error)
+
+	// RemoveItem removes an item from the owner's cart
+	RemoveItem(ctx context.Context, owner CartOwner, itemID uuid.UUID) (*dto.CartResponse, error)
+
+	// ClearCart removes all items from the owner's cart
+	ClearCart(ctx context.Context, owner CartOwner) error
+
+	// MergeGuestCart moves the items of an anonymous session cart into the
+	// customer's cart, typically right after the customer logs in
+	MergeGuestCart(ctx context.Context, storefrontID, customerID uuid.UUID, sessionID string) error
+}
+
+// cartUseCase implements the CartUseCase interface
+type cartUseCase struct {
+	db             *sqlx.DB
+	cartRepo       repository.CartRepository
+	storefrontRepo repository.StorefrontRepository
+	productRepo    repository.ProductRepository
+	variantRepo    repository.ProductVariantRepository
+	logger         *slog.Logger
+}
+
+// NewCartUseCase creates a new cart use case
+func NewCartUseCase(
+	db *sqlx.DB,
+	cartRepo repository.CartRepository,
+	storefrontRepo repository.StorefrontRepository,
+	productRepo repository.ProductRepository,
+	variantRepo repository.ProductVariantRepository,
+	logger *slog.Logger,
+) CartUseCase {
+	return &cartUseCase{
+		db:             db,
+		cartRepo:       cartRepo,
+		storefrontRepo: storefrontRepo,
+		productRepo:    productRepo,
+		variantRepo:    variantRepo,
+		logger:         logger,
+	}
+}
+
+// GetCart retrieves the owner's cart revalidated against current prices and stock
+func (uc *cartUseCase) GetCart(ctx context.Context, owner CartOwner) (*dto.CartResponse, error) {
+	storefront, err := uc.getStorefront(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := uc.findCart(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.buildResponse(ctx, storefront, owner, cart), nil
+}
+
+// AddItem adds a product to the owner's cart, creating the cart if needed
+func (uc *cartUseCase) AddItem(ctx context.Context, owner CartOwner, req *dto.AddCartItemRequest) (*dto.CartResponse, error) {
+	storefront, err := uc.getStorefront(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	productID, variantID, err := parseProductRef(req.ProductID, req.VariantID)
+	if err != nil {
+		return nil, err
+	}
+
+	product, variant, err := loadStorefrontProduct(ctx, uc.productRepo, uc.variantRepo, storefront.SellerID, productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := uc.findCart(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		cart, err = uc.createCart(ctx, owner)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	item, err := cart.AddItem(productID, variantID, req.Quantity, effectiveUnitPrice(product, variant))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkCartStock(product, variant, item.Quantity); err != nil {
+		return nil, err
+	}
+
+	if err := uc.saveItem(ctx, cart, item); err != nil {
+		return nil, err
+	}
+
+	return uc.buildResponse(ctx, storefront, owner, cart), nil
+}
+
+// UpdateItem changes the quantity of a cart item; zero removes it
+func (uc *cartUseCase) UpdateItem(ctx context.Context, owner CartOwner, itemID uuid.UUID, req *dto.UpdateCartItemRequest) (*dto.CartResponse, error) {
+	if req.Quantity == 0 {
+		return uc.RemoveItem(ctx, owner, itemID)
+	}
+
+	storefront, err := uc.getStorefront(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, item, err := uc.findItem(ctx, owner, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	product, variant, err := loadStorefrontProduct(ctx, uc.productRepo, uc.variantRepo, storefront.SellerID, item.ProductID, item.ProductVariantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := item.SetQuantity(req.Quantity, effectiveUnitPrice(product, variant)); err != nil {
+		return nil, err
+	}
+
+	if err := checkCartStock(product, variant, item.Quantity); err != nil {
+		return nil, err
+	}
+
+	if err := uc.saveItem(ctx, cart, item); err != nil {
+		return nil, err
+	}
+
+	return uc.buildResponse(ctx, storefront, owner, cart), nil
+}
+
+// RemoveItem removes an item from the owner's cart
+func (uc *cartUseCase) RemoveItem(ctx context.Context, owner CartOwner, itemID uuid.UUID) (*dto.CartResponse, error) {
+	storefront, err := uc.getStorefront(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, _, err := uc.findItem(ctx, owner, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.cartRepo.DeleteItem(ctx, cart.ID, itemID); err != nil {
+		return nil, err
+	}
+	cart.RemoveItem(itemID)
+
+	if err := uc.cartRepo.Touch(ctx, cart.ID); err != nil {
+		return nil, err
+	}
+
+	return uc.buildResponse(ctx, storefront, owner, cart), nil
+}
+
+// ClearCart removes all items from the owner's cart
+func (uc *cartUseCase) ClearCart(ctx context.Context, owner CartOwner) error {
+	if _, err := uc.getStorefront(ctx, owner); err != nil {
+		return err
+	}
+
+	cart, err := uc.findCart(ctx, owner)
+	if err != nil || cart == nil {
+		return err
+	}
+
+	if err := uc.cartRepo.ClearItems(ctx, cart.ID); err != nil {
+		return err
+	}
+	return uc.cartRepo.Touch(ctx, cart.ID)
+}
+
+// MergeGuestCart moves the items of an anonymous session cart into the
+// customer's cart, typically right after the customer logs in
+func (uc *cartUseCase) MergeGuestCart(ctx context.Context, storefrontID, customerID uuid.UUID, sessionID string) error {
+	if sessionID == "" {
+		return nil
+	}
+
+	err := database.WithTransaction(ctx, uc.db, func(txCtx context.Context, _ *sqlx.Tx) error {
+		guestCart, err := uc.cartRepo.GetBySessionID(txCtx, storefrontID, sessionID)
+		if err != nil || guestCart == nil {
+			return err
+		}
+
+		owner := CartOwner{StorefrontID: storefrontID, CustomerID: &customerID}
+		cart, err := uc.findCart(txCtx, owner)
+		if err != nil {
+			return err
+		}
+		if cart == nil {
+			cart, err = uc.createCart(txCtx, owner)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, guestItem := range guestCart.Items {
+			item := cart.FindItem(guestItem.ProductID, guestItem.ProductVariantID)
+			if item == nil {
+				item, err = cart.AddItem(guestItem.ProductID, guestItem.ProductVariantID, guestItem.Quantity, guestItem.UnitPrice)
+			} else {
+				quantity := item.Quantity + guestItem.Quantity
+				if quantity > entity.MaxCartItemQuantity {
+					quantity = entity.MaxCartItemQuantity
+				}
+				err = item.SetQuantity(quantity, item.UnitPrice)
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := uc.cartRepo.SaveItem(txCtx, item); err != nil {
+				return err
+			}
+		}
+
+		if err := uc.cartRepo.Delete(txCtx, guestCart.ID); err != nil {
+			return err
+		}
+
+		uc.logger.Info("Merged guest cart into customer cart",
+			"storefront_id", storefrontID,
+			"customer_id", customerID,
+			"items", len(guestCart.Items))
+
+		return uc.cartRepo.Touch(txCtx, cart.ID)
+	})
+	if err != nil {
+		uc.logger.Error("Failed to merge guest cart", "error", err, "storefront_id", storefrontID, "customer_id", customerID)
+		return fmt.Errorf("failed to merge guest cart: %w", err)
+	}
+
+	return nil
+}
+
+// getStorefront loads the storefront and checks that anonymous carts are allowed
+func (uc *cartUseCase) getStorefront(ctx context.Context, owner CartOwner) (*entity.Storefront, error) {
+	storefront, err := uc.storefrontRepo.GetByID(ctx, owner.StorefrontID)
+	if err != nil || storefront == nil {
+		return nil, fmt.Errorf("storefront not found")
+	}
+
+	if owner.CustomerID == nil && !storefront.Settings.EnableGuestCheckout {
+		return nil, fmt.Errorf("guest carts are not enabled for this storefront")
+	}
+
+	return storefront, nil
+}
+
+// findCart loads the owner's cart, returning nil when there is none yet
+func (uc *cartUseCase) findCart(ctx context.Context, owner CartOwner) (*entity.Cart, error) {
+	if owner.CustomerID != nil {
+		return uc.cartRepo.GetByCustomerID(ctx, owner.StorefrontID, *owner.CustomerID)
+	}
+	if owner.SessionID == "" {
+		return nil, nil
+	}
+	return uc.cartRepo.GetBySessionID(ctx, owner.StorefrontID, owner.SessionID)
+}
+
+// createCart creates an empty cart for the owner
+func (uc *cartUseCase) createCart(ctx context.Context, owner CartOwner) (*entity.Cart, error) {
+	var cart *entity.Cart
+	if owner.CustomerID != nil {
+		cart = entity.NewCustomerCart(owner.StorefrontID, *owner.CustomerID)
+	} else {
+		if owner.SessionID == "" {
+			return nil, fmt.Errorf("cart session is required")
+		}
+		cart = entity.NewGuestCart(owner.StorefrontID, owner.SessionID)
+	}
+
+	if err := uc.cartRepo.Create(ctx, cart); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+// findItem loads the owner's cart and the item with the given ID
+func (uc *cartUseCase) findItem(ctx context.Context, owner CartOwner, itemID uuid.UUID) (*entity.Cart, *entity.CartItem, error) {
+	cart, err := uc.findCart(ctx, owner)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cart == nil {
+		return nil, nil, fmt.Errorf("cart item not found")
+	}
+
+	item := cart.GetItem(itemID)
+	if item == nil {
+		return nil, nil, fmt.Errorf("cart item not found")
+	}
+	return cart, item, nil
+}
+
+// saveItem persists a changed item and the cart's activity time
+func (uc *cartUseCase) saveItem(ctx context.Context, cart *entity.Cart, item *entity.CartItem) error {
+	if err := uc.cartRepo.SaveItem(ctx, item); err != nil {
+		return err
+	}
+	return uc.cartRepo.Touch(ctx, cart.ID)
+}
+
+// buildResponse revalidates every item against the live catalogue. Items are
+// never modified here; changes are reported through each item's issues.
+func (uc *cartUseCase) buildResponse(ctx context.Context, storefront *entity.Storefront, owner CartOwner, cart *entity.Cart) *dto.CartResponse {
+	response := &dto.CartResponse{
+		StorefrontID: storefront.ID,
+		Items:        []*dto.CartItemResponse{},
+		Subtotal:     decimal.Zero,
+		Currency:     storefront.Settings.Currency,
+	}
+	if response.Currency == "" {
+		response.Currency = "IDR"
+	}
+	if owner.CustomerID == nil && owner.SessionID != "" {
+		sessionID := owner.SessionID
+		response.SessionID = &sessionID
+	}
+	if cart == nil {
+		return response
+	}
+
+	response.ID = &cart.ID
+	response.UpdatedAt = &cart.UpdatedAt
+
+	for _, item := range cart.Items {
+		line := uc.revalidateItem(ctx, storefront, item)
+		response.Items = append(response.Items, line)
+		if len(line.Issues) > 0 {
+			response.HasIssues = true
+		}
+		if line.Available {
+			response.ItemCount += line.Quantity
+			response.Subtotal = response.Subtotal.Add(line.LineTotal)
+		}
+	}
+
+	return response
+}
+
+// revalidateItem reports the current price and availability of a cart item
+func (uc *cartUseCase) revalidateItem(ctx context.Context, storefront *entity.Storefront, item *entity.CartItem) *dto.CartItemResponse {
+	line := &dto.CartItemResponse{
+		ID:         item.ID,
+		ProductID:  item.ProductID,
+		VariantID:  item.ProductVariantID,
+		Quantity:   item.Quantity,
+		UnitPrice:  item.UnitPrice,
+		AddedPrice: item.UnitPrice,
+		LineTotal:  decimal.Zero,
+	}
+
+	product, variant, err := loadStorefrontProduct(ctx, uc.productRepo, uc.variantRepo, storefront.SellerID, item.ProductID, item.ProductVariantID)
+	if err != nil {
+		line.Issues = append(line.Issues, dto.CartIssueUnavailable)
+		return line
+	}
+
+	line.ProductName = product.Name
+	line.SKU = product.SKU
+	line.UnitPrice = effectiveUnitPrice(product, variant)
+	if variant != nil {
+		line.VariantName = optionalString(variant.VariantName)
+		line.ImageURL = variant.ImageURL
+		if variant.VariantSKU != nil && *variant.VariantSKU != "" {
+			line.SKU = *variant.VariantSKU
+		}
+	}
+	line.Available = true
+
+	if !line.UnitPrice.Equal(item.UnitPrice) {
+		line.Issues = append(line.Issues, dto.CartIssuePriceChanged)
+	}
+
+	if product.TrackInventory {
+		stock := availableStock(product, variant)
+		line.AvailableStock = &stock
+		switch {
+		case stock <= 0:
+			line.Available = false
+			line.Issues = append(line.Issues, dto.CartIssueOutOfStock)
+		case stock < item.Quantity:
+			line.Issues = append(line.Issues, dto.CartIssueInsufficientStock)
+		}
+	}
+
+	if line.Available {
+		line.LineTotal = line.UnitPrice.Mul(decimal.NewFromInt(int64(item.Quantity)))
+	}
+
+	return line
+}
+
+// availableStock returns the stock of the variant, or of the product when no variant is given
+func availableStock(product *entity.Product, variant *entity.ProductVariant) int {
+	if variant != nil {
+		return variant.StockQuantity
+	}
+	return product.StockQuantity
+}
+
+// checkCartStock rejects cart quantities above the available stock
+func checkCartStock(product *entity.Product, variant *entity.ProductVariant, quantity int) error {
+	if !product.TrackInventory {
+		return nil
+	}
+
+	stock := availableStock(product, variant)
+	if quantity > stock {
+		return fmt.Errorf("insufficient stock: available=%d, requested=%d", stock, quantity)
+	}
+	return nil
+}
@@ -0,0 +1,516 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/database"
+)
+
+// reservationReleaseBatchSize limits how many orders one sweep releases
+const reservationReleaseBatchSize = 100
+
+// reservationExpiredReason is recorded on orders cancelled because payment never arrived
+const reservationExpiredReason = "Payment not received in time"
+
+// CheckoutUseCase defines the interface for storefront checkout
+type CheckoutUseCase interface {
+	// Checkout validates the owner's cart, reserves stock and places a
+	// pending order. The cart is emptied once the order is placed.
+	Checkout(ctx context.Context, owner CartOwner, req *dto.CheckoutRequest) (*dto.CheckoutResponse, error)
+
+	// ReleaseExpiredReservations settles reservations whose payment window has
+	// passed: unpaid pending orders are cancelled and their stock returned,
+	// orders that went ahead keep their stock
+	ReleaseExpiredReservations(ctx context.Context) (int, error)
+}
+
+// checkoutUseCase implements the CheckoutUseCase interface
+type checkoutUseCase struct {
+	db              *sqlx.DB
+	orderRepo       repository.OrderRepository
+	cartRepo        repository.CartRepository
+	reservationRepo repository.StockReservationRepository
+	storefrontRepo  repository.StorefrontRepository
+	customerRepo    repository.CustomerRepository
+	addressRepo     repository.CustomerAddressRepository
+	productRepo     repository.ProductRepository
+	variantRepo     repository.ProductVariantRepository
+	productUseCase  *ProductUseCase
+	stock           *orderStock
+	reservationTTL  time.Duration
+	logger          *slog.Logger
+}
+
+// NewCheckoutUseCase creates a new checkout use case
+func NewCheckoutUseCase(
+	db *sqlx.DB,
+	orderRepo repository.OrderRepository,
+	cartRepo repository.CartRepository,
+	reservationRepo repository.StockReservationRepository,
+	storefrontRepo repository.StorefrontRepository,
+	customerRepo repository.CustomerRepository,
+	addressRepo repository.CustomerAddressRepository,
+	productRepo repository.ProductRepository,
+	variantRepo repository.ProductVariantRepository,
+	productUseCase *ProductUseCase,
+	reservationTTL time.Duration,
+	logger *slog.Logger,
+) CheckoutUseCase {
+	return &checkoutUseCase{
+		db:              db,
+		orderRepo:       orderRepo,
+		cartRepo:        cartRepo,
+		reservationRepo: reservationRepo,
+		storefrontRepo:  storefrontRepo,
+		customerRepo:    customerRepo,
+		addressRepo:     addressRepo,
+		productRepo:     productRepo,
+		variantRepo:     variantRepo,
+		productUseCase:  productUseCase,
+		stock:           newOrderStock(reservationRepo, productUseCase),
+		reservationTTL:  reservationTTL,
+		logger:          logger,
+	}
+}
+
+// checkoutLine is a validated cart line ready to be reserved
+type checkoutLine struct {
+	product  *entity.Product
+	variant  *entity.ProductVariant
+	quantity int
+}
+
+// Checkout validates the owner's cart, reserves stock and places a pending order
+func (uc *checkoutUseCase) Checkout(ctx context.Context, owner CartOwner, req *dto.CheckoutRequest) (*dto.CheckoutResponse, error) {
+	storefrontID, customerID := owner.StorefrontID, owner.CustomerID
+	storefront, err := uc.storefrontRepo.GetByID(ctx, storefrontID)
+	if err != nil || storefront == nil {
+		return nil, fmt.Errorf("storefront not found")
+	}
+	if !storefront.IsActive() {
+		return nil, fmt.Errorf("storefront is not accepting orders")
+	}
+
+	settings := storefront.Settings
+	if customerID == nil {
+		if !settings.EnableGuestCheckout {
+			return nil, fmt.Errorf("guest checkout is not enabled for this storefront")
+		}
+		if req.CustomerEmail == "" {
+			return nil, fmt.Errorf("customer email is required for guest checkout")
+		}
+	}
+
+	if !settings.AllowsPaymentMethod(req.PaymentMethod) {
+		return nil, fmt.Errorf("payment method %s is not allowed", req.PaymentMethod)
+	}
+
+	cart, err := uc.findCart(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := uc.buildLines(ctx, storefront, cart.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	order := entity.NewOrder(storefront.SellerID, &storefront.ID, settings.Currency)
+	order.CustomerID = customerID
+	order.CustomerEmail = optionalString(req.CustomerEmail)
+	order.CustomerPhone = optionalString(req.CustomerPhone)
+	order.PaymentMethod = optionalString(req.PaymentMethod)
+	order.ShippingMethod = optionalString(req.ShippingMethod)
+	order.Notes = optionalString(req.Notes)
+
+	if customerID != nil {
+		if err := uc.applyCustomer(ctx, order, storefront.ID, *customerID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.applyAddresses(ctx, order, customerID, req); err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		order.Items = append(order.Items, newCheckoutOrderItem(order.ID, line))
+	}
+
+	if settings.TaxSettings.EnableTax {
+		order.ApplyTax(decimal.NewFromFloat(settings.TaxSettings.TaxRate), settings.TaxSettings.TaxInclusive)
+	} else {
+		order.CalculateTotals()
+	}
+
+	if err := settings.CheckOrderAmount(order.TotalAmount); err != nil {
+		return nil, err
+	}
+
+	orderNumber, err := uc.orderRepo.GenerateOrderNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate order number: %w", err)
+	}
+	order.OrderNumber = orderNumber
+
+	if err := order.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid order: %w", err)
+	}
+
+	expiresAt := time.Now().Add(uc.reservationTTL)
+	err = database.WithTransaction(ctx, uc.db, func(txCtx context.Context, _ *sqlx.Tx) error {
+		if err := uc.orderRepo.Create(txCtx, order); err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+
+		for _, line := range lines {
+			if err := uc.reserve(txCtx, order, line); err != nil {
+				return err
+			}
+		}
+
+		if err := uc.cartRepo.Delete(txCtx, cart.ID); err != nil {
+			return fmt.Errorf("failed to empty cart: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		uc.logger.Error("Checkout failed", "error", err, "storefront_id", storefrontID, "customer_id", customerID)
+		return nil, err
+	}
+
+	uc.logger.Info("Order placed via checkout",
+		"order_id", order.ID,
+		"order_number", order.OrderNumber,
+		"storefront_id", storefrontID,
+		"total", order.TotalAmount.String(),
+		"reservation_expires_at", expiresAt)
+
+	order.ComputeFields()
+	return &dto.CheckoutResponse{
+		Order:                dto.ConvertOrderToCustomerResponse(order),
+		ReservationExpiresAt: expiresAt,
+	}, nil
+}
+
+// ReleaseExpiredReservations settles reservations whose payment window has
+// passed: unpaid pending orders are cancelled and their stock returned,
+// orders that went ahead keep their stock
+func (uc *checkoutUseCase) ReleaseExpiredReservations(ctx context.Context) (int, error) {
+	orderIDs, err := uc.reservationRepo.GetExpiredOrderIDs(ctx, time.Now(), reservationReleaseBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get expired reservations: %w", err)
+	}
+
+	released := 0
+	for _, orderID := range orderIDs {
+		err := database.WithTransaction(ctx, uc.db, func(txCtx context.Context, _ *sqlx.Tx) error {
+			return uc.releaseOrderReservations(txCtx, orderID)
+		})
+		if err != nil {
+			uc.logger.Error("Failed to release expired reservations", "error", err, "order_id", orderID)
+			continue
+		}
+		released++
+	}
+
+	if released > 0 {
+		uc.logger.Info("Released expired stock reservations", "orders", released)
+	}
+
+	return released, nil
+}
+
+// releaseOrderReservations settles the active reservations of one order by
+// its status: orders that left pending (or already hold the customer's money)
+// keep their stock, pending orders are cancelled and their stock goes back to
+// inventory in the same transaction. The order stays locked until the
+// transaction ends, so a payment callback arriving meanwhile waits instead of
+// landing on a cancelled order.
+func (uc *checkoutUseCase) releaseOrderReservations(ctx context.Context, orderID uuid.UUID) error {
+	order, err := uc.orderRepo.LockByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	switch {
+	case order == nil || order.Status == entity.OrderStatusCancelled:
+		return uc.stock.release(ctx, orderID, reservationExpiredReason)
+	case order.Status != entity.OrderStatusPending,
+		order.PaymentStatus == entity.PaymentStatusPaid,
+		order.PaymentStatus == entity.PaymentStatusPartiallyPaid:
+		return uc.stock.commit(ctx, orderID)
+	}
+
+	history, err := order.Cancel(nil, reservationExpiredReason)
+	if err != nil {
+		return err
+	}
+	if err := uc.orderRepo.UpdateStatus(ctx, order, history); err != nil {
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+	if err := uc.stock.release(ctx, orderID, reservationExpiredReason); err != nil {
+		return err
+	}
+
+	uc.logger.Info("Cancelled unpaid order", "order_id", order.ID, "order_number", order.OrderNumber)
+	return nil
+}
+
+// findCart loads the cart being checked out. Guests are identified by their
+// cart session only; without one there is nothing to check out.
+func (uc *checkoutUseCase) findCart(ctx context.Context, owner CartOwner) (*entity.Cart, error) {
+	var (
+		cart *entity.Cart
+		err  error
+	)
+	switch {
+	case owner.CustomerID != nil:
+		cart, err = uc.cartRepo.GetByCustomerID(ctx, owner.StorefrontID, *owner.CustomerID)
+	case owner.SessionID != "":
+		cart, err = uc.cartRepo.GetBySessionID(ctx, owner.StorefrontID, owner.SessionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+	if cart == nil || len(cart.Items) == 0 {
+		return nil, fmt.Errorf("cart is empty")
+	}
+	return cart, nil
+}
+
+// buildLines merges duplicate cart items and validates each product and
+// variant against the storefront's catalogue
+func (uc *checkoutUseCase) buildLines(ctx context.Context, storefront *entity.Storefront, items []*entity.CartItem) ([]*checkoutLine, error) {
+	lines := make([]*checkoutLine, 0, len(items))
+	byKey := make(map[string]*checkoutLine, len(items))
+
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("quantity must be positive")
+		}
+
+		key := item.ProductID.String()
+		if item.ProductVariantID != nil {
+			key += ":" + item.ProductVariantID.String()
+		}
+		if line, ok := byKey[key]; ok {
+			line.quantity += item.Quantity
+			continue
+		}
+
+		product, variant, err := loadStorefrontProduct(ctx, uc.productRepo, uc.variantRepo, storefront.SellerID, item.ProductID, item.ProductVariantID)
+		if err != nil {
+			return nil, err
+		}
+
+		line := &checkoutLine{product: product, variant: variant, quantity: item.Quantity}
+		byKey[key] = line
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// reserve deducts stock for a line and records the reservation so it can be
+// released if the order is not paid in time
+func (uc *checkoutUseCase) reserve(ctx context.Context, order *entity.Order, line *checkoutLine) error {
+	req := StockReservationRequest{
+		ProductID:  line.product.ID,
+		Quantity:   line.quantity,
+		OrderID:    &order.ID,
+		ReservedBy: order.CreatedBy,
+	}
+	if line.variant != nil {
+		req.VariantID = &line.variant.ID
+	}
+
+	if err := uc.productUseCase.ReserveStock(ctx, req); err != nil {
+		return fmt.Errorf("%s: %w", line.product.Name, err)
+	}
+
+	// Products without inventory tracking have nothing to give back
+	if !line.product.TrackInventory {
+		return nil
+	}
+
+	reservation := entity.NewStockReservation(order.ID, line.product.ID, req.VariantID, line.quantity, uc.reservationTTL)
+	return uc.reservationRepo.Create(ctx, reservation)
+}
+
+// applyCustomer fills in the contact details of a signed-in customer when the
+// request does not override them
+func (uc *checkoutUseCase) applyCustomer(ctx context.Context, order *entity.Order, storefrontID, customerID uuid.UUID) error {
+	customer, err := uc.customerRepo.GetByID(ctx, storefrontID, customerID)
+	if err != nil || customer == nil {
+		return fmt.Errorf("customer not found")
+	}
+
+	if order.CustomerEmail == nil {
+		order.CustomerEmail = customer.Email
+	}
+	if order.CustomerPhone == nil {
+		order.CustomerPhone = customer.Phone
+	}
+	return nil
+}
+
+// applyAddresses resolves the shipping address from the customer's address
+// book or the request and defaults the billing address to it
+func (uc *checkoutUseCase) applyAddresses(ctx context.Context, order *entity.Order, customerID *uuid.UUID, req *dto.CheckoutRequest) error {
+	shipping := req.ShippingAddress
+
+	if req.ShippingAddressID != nil && *req.ShippingAddressID != "" {
+		if customerID == nil {
+			return fmt.Errorf("saved addresses require a signed-in customer")
+		}
+
+		addressID, err := uuid.Parse(*req.ShippingAddressID)
+		if err != nil {
+			return fmt.Errorf("invalid shipping_address_id: %w", err)
+		}
+
+		address, err := uc.addressRepo.GetByID(ctx, addressID)
+		if err != nil || address == nil || address.CustomerID != *customerID {
+			return fmt.Errorf("shipping address not found")
+		}
+		shipping = orderAddressFromCustomerAddress(address)
+	}
+
+	if shipping == nil {
+		return fmt.Errorf("shipping address is required")
+	}
+
+	billing := req.BillingAddress
+	if billing == nil {
+		billing = shipping
+	}
+
+	applyShippingAddress(order, shipping)
+	applyBillingAddress(order, billing)
+	return nil
+}
+
+// newCheckoutOrderItem snapshots the product and variant onto an order item
+func newCheckoutOrderItem(orderID uuid.UUID, line *checkoutLine) *entity.OrderItem {
+	product := line.product
+
+	price := effectiveUnitPrice(product, line.variant)
+	sku := product.SKU
+	weight := product.Weight
+	var variantName, imageURL *string
+
+	if v := line.variant; v != nil {
+		if v.VariantSKU != nil && *v.VariantSKU != "" {
+			sku = *v.VariantSKU
+		}
+		if v.Weight != nil {
+			weight = v.Weight
+		}
+		variantName = optionalString(v.VariantName)
+		imageURL = v.ImageURL
+	}
+
+	item := entity.NewOrderItem(orderID, product.Name, price, line.quantity)
+	item.ProductID = &product.ID
+	item.ProductSKU = optionalString(sku)
+	item.ProductWeight = weight
+	item.ProductImageURL = imageURL
+	item.VariantName = variantName
+	if line.variant != nil {
+		item.ProductVariantID = &line.variant.ID
+	}
+	return item
+}
+
+// parseProductRef parses the product and optional variant IDs of a request item
+func parseProductRef(productIDStr string, variantIDStr *string) (uuid.UUID, *uuid.UUID, error) {
+	productID, err := uuid.Parse(productIDStr)
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("invalid product_id: %w", err)
+	}
+
+	if variantIDStr == nil || *variantIDStr == "" {
+		return productID, nil, nil
+	}
+
+	variantID, err := uuid.Parse(*variantIDStr)
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("invalid variant_id: %w", err)
+	}
+	return productID, &variantID, nil
+}
+
+// loadStorefrontProduct loads a product sold by the seller and, when given,
+// one of its variants, rejecting anything that is not available for sale
+func loadStorefrontProduct(
+	ctx context.Context,
+	productRepo repository.ProductRepository,
+	variantRepo repository.ProductVariantRepository,
+	sellerID, productID uuid.UUID,
+	variantID *uuid.UUID,
+) (*entity.Product, *entity.ProductVariant, error) {
+	product, err := productRepo.GetByID(ctx, productID, nil)
+	if err != nil || product == nil || product.CreatedBy != sellerID {
+		return nil, nil, fmt.Errorf("product %s not found", productID)
+	}
+	if product.Status != entity.ProductStatusActive {
+		return nil, nil, fmt.Errorf("product %s is not available", product.Name)
+	}
+
+	if variantID == nil {
+		return product, nil, nil
+	}
+
+	variant, err := variantRepo.GetByID(ctx, *variantID, nil)
+	if err != nil || variant == nil || variant.ProductID != product.ID {
+		return nil, nil, fmt.Errorf("variant %s not found", *variantID)
+	}
+	if !variant.IsActive {
+		return nil, nil, fmt.Errorf("variant %s is not available", variant.VariantName)
+	}
+
+	return product, variant, nil
+}
+
+// effectiveUnitPrice returns the variant's price override when it has one,
+// otherwise the product's current selling price
+func effectiveUnitPrice(product *entity.Product, variant *entity.ProductVariant) decimal.Decimal {
+	if variant != nil && variant.Price.IsPositive() {
+		return variant.Price
+	}
+	return product.GetEffectivePrice()
+}
+
+// orderAddressFromCustomerAddress converts a saved customer address to an order address
+func orderAddressFromCustomerAddress(address *entity.CustomerAddress) *dto.OrderAddressRequest {
+	deref := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+
+	return &dto.OrderAddressRequest{
+		FirstName:     deref(address.FirstName),
+		LastName:      deref(address.LastName),
+		Company:       deref(address.Company),
+		AddressLine1:  address.AddressLine1,
+		AddressLine2:  deref(address.AddressLine2),
+		City:          address.City,
+		StateProvince: deref(address.StateProvince),
+		PostalCode:    address.PostalCode,
+		Country:       address.Country,
+		Phone:         deref(address.Phone),
+	}
+}
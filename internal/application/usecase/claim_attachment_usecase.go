@@ -0,0 +1,389 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/pkg/clamav"
+	"github.com/kirimku/smartseller-backend/pkg/storage"
+)
+
+// ErrAttachmentUnavailable is returned when an attachment is downloaded
+// before it passed its virus scan
+var ErrAttachmentUnavailable = errors.New("attachment is not available for download until it passes the virus scan")
+
+const (
+	// claimAttachmentQuarantinePrefix is where infected attachments are moved
+	claimAttachmentQuarantinePrefix = "quarantine/"
+	// claimAttachmentScanTimeout bounds the scan of a single attachment
+	claimAttachmentScanTimeout = 5 * time.Minute
+	// claimAttachmentConcurrentScans caps the scans running after uploads
+	claimAttachmentConcurrentScans = 4
+	// claimAttachmentSweepBatch is the number of pending attachments a sweep rescans
+	claimAttachmentSweepBatch = 100
+)
+
+// AttachmentScanner scans attachment content for malware
+type AttachmentScanner interface {
+	Scan(ctx context.Context, r io.Reader) (*clamav.Result, error)
+}
+
+// ClaimAttachmentUpload is a file uploaded to a warranty claim
+type ClaimAttachmentUpload struct {
+	Filename       string
+	ContentType    string
+	Size           int64
+	AttachmentType string
+	Description    *string
+	Content        io.Reader
+}
+
+// ClaimAttachmentDownload is the content of an attachment that passed its scan.
+// The caller closes Content.
+type ClaimAttachmentDownload struct {
+	Filename string
+	MimeType string
+	Size     int64
+	Checksum string
+	Content  io.ReadCloser
+}
+
+// ClaimAttachmentUseCase stores warranty claim attachments and keeps them out
+// of reach until they pass a virus scan
+type ClaimAttachmentUseCase interface {
+	// ListAttachments lists the attachments of a storefront's claim
+	ListAttachments(ctx context.Context, storefrontID, claimID uuid.UUID) ([]*dto.ClaimAttachmentResponse, error)
+
+	// UploadAttachment stores a file and queues its virus scan
+	UploadAttachment(ctx context.Context, storefrontID, claimID, uploadedBy uuid.UUID, upload *ClaimAttachmentUpload) (*dto.ClaimAttachmentResponse, error)
+
+	// DownloadAttachment opens an attachment that passed its virus scan
+	DownloadAttachment(ctx context.Context, storefrontID, claimID, attachmentID uuid.UUID) (*ClaimAttachmentDownload, error)
+
+	// DeleteAttachment removes an attachment and its file
+	DeleteAttachment(ctx context.Context, storefrontID, claimID, attachmentID uuid.UUID) error
+
+	// ReviewAttachment approves or rejects an attachment
+	ReviewAttachment(ctx context.Context, storefrontID, claimID, attachmentID uuid.UUID, req *dto.AttachmentApprovalRequest) (*dto.ClaimAttachmentResponse, error)
+
+	// ScanPendingAttachments scans attachments whose scan has not completed,
+	// such as those uploaded while clamd was unreachable
+	ScanPendingAttachments(ctx context.Context) (int, error)
+}
+
+type claimAttachmentUseCase struct {
+	claimRepo repository.WarrantyClaimRepository
+	storage   storage.Storage
+	scanner   AttachmentScanner
+	scanSlots chan struct{}
+	logger    *slog.Logger
+}
+
+// NewClaimAttachmentUseCase creates a new claim attachment use case. Without a
+// scanner attachments stay pending and cannot be downloaded.
+func NewClaimAttachmentUseCase(
+	claimRepo repository.WarrantyClaimRepository,
+	store storage.Storage,
+	scanner AttachmentScanner,
+	logger *slog.Logger,
+) ClaimAttachmentUseCase {
+	if scanner == nil {
+		logger.Warn("No virus scanner configured, claim attachments will not be downloadable")
+	}
+
+	return &claimAttachmentUseCase{
+		claimRepo: claimRepo,
+		storage:   store,
+		scanner:   scanner,
+		scanSlots: make(chan struct{}, claimAttachmentConcurrentScans),
+		logger:    logger,
+	}
+}
+
+// ListAttachments lists the attachments of a storefront's claim
+func (uc *claimAttachmentUseCase) ListAttachments(ctx context.Context, storefrontID, claimID uuid.UUID) ([]*dto.ClaimAttachmentResponse, error) {
+	if _, err := uc.getClaim(ctx, storefrontID, claimID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := uc.claimRepo.GetClaimAttachments(ctx, claimID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get claim attachments: %w", err)
+	}
+
+	responses := dto.ConvertClaimAttachmentsToResponses(attachments)
+	if responses == nil {
+		responses = []*dto.ClaimAttachmentResponse{}
+	}
+	return responses, nil
+}
+
+// UploadAttachment stores a file and queues its virus scan. The SHA-256
+// checksum is computed while the file streams to storage.
+func (uc *claimAttachmentUseCase) UploadAttachment(ctx context.Context, storefrontID, claimID, uploadedBy uuid.UUID, upload *ClaimAttachmentUpload) (*dto.ClaimAttachmentResponse, error) {
+	if _, err := uc.getClaim(ctx, storefrontID, claimID); err != nil {
+		return nil, err
+	}
+
+	attachment := entity.NewClaimAttachment(claimID, uploadedBy, "", filepath.Base(upload.Filename), upload.Size)
+	if !attachment.IsValidFilename() {
+		return nil, fmt.Errorf("invalid file name: %s", upload.Filename)
+	}
+	attachment.Filename = attachment.ID.String() + strings.ToLower(filepath.Ext(attachment.OriginalFilename))
+	attachment.Description = upload.Description
+
+	// Browsers send application/octet-stream for types they do not know
+	if mediaType, _, err := mime.ParseMediaType(upload.ContentType); err == nil && mediaType != "application/octet-stream" {
+		attachment.MimeType = mediaType
+	} else {
+		attachment.SetMimeTypeFromExtension()
+	}
+	if upload.AttachmentType != "" {
+		attachment.AttachmentType = entity.AttachmentType(upload.AttachmentType)
+	} else {
+		attachment.DetectAttachmentType()
+	}
+
+	if err := attachment.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid attachment: %w", err)
+	}
+
+	attachment.FilePath = fmt.Sprintf("claims/%s/%s", claimID, attachment.Filename)
+	attachment.FileURL = fmt.Sprintf("/api/v1/admin/warranty/claims/%s/attachments/%s/download", claimID, attachment.ID)
+
+	hasher := sha256.New()
+	if err := uc.storage.Put(ctx, attachment.FilePath, io.TeeReader(upload.Content, hasher), upload.Size, attachment.MimeType); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+	attachment.SetChecksum(hex.EncodeToString(hasher.Sum(nil)))
+
+	if err := uc.claimRepo.AddClaimAttachment(ctx, attachment); err != nil {
+		if delErr := uc.storage.Delete(ctx, attachment.FilePath); delErr != nil {
+			uc.logger.Error("Failed to remove orphaned attachment file", "path", attachment.FilePath, "error", delErr)
+		}
+		return nil, fmt.Errorf("failed to add claim attachment: %w", err)
+	}
+
+	uc.scanAsync(attachment.ID)
+
+	attachment.ComputeFields()
+	return dto.ConvertClaimAttachmentToResponse(attachment), nil
+}
+
+// DownloadAttachment opens an attachment that passed its virus scan
+func (uc *claimAttachmentUseCase) DownloadAttachment(ctx context.Context, storefrontID, claimID, attachmentID uuid.UUID) (*ClaimAttachmentDownload, error) {
+	attachment, err := uc.getAttachment(ctx, storefrontID, claimID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if !attachment.CanDownload() {
+		return nil, ErrAttachmentUnavailable
+	}
+
+	content, err := uc.storage.Get(ctx, attachment.FilePath)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("attachment file not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+
+	download := &ClaimAttachmentDownload{
+		Filename: attachment.OriginalFilename,
+		MimeType: attachment.MimeType,
+		Size:     attachment.FileSize,
+		Content:  content,
+	}
+	if attachment.Checksum != nil {
+		download.Checksum = *attachment.Checksum
+	}
+	return download, nil
+}
+
+// DeleteAttachment removes an attachment and its file
+func (uc *claimAttachmentUseCase) DeleteAttachment(ctx context.Context, storefrontID, claimID, attachmentID uuid.UUID) error {
+	attachment, err := uc.getAttachment(ctx, storefrontID, claimID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.claimRepo.DeleteClaimAttachment(ctx, attachment.ID); err != nil {
+		return fmt.Errorf("failed to delete claim attachment: %w", err)
+	}
+
+	// The record is gone, so a leftover file is only wasted space
+	if err := uc.storage.Delete(ctx, attachment.FilePath); err != nil {
+		uc.logger.Error("Failed to delete attachment file", "attachment_id", attachment.ID, "path", attachment.FilePath, "error", err)
+	}
+	return nil
+}
+
+// ReviewAttachment approves or rejects an attachment. Only attachments that
+// passed their scan can be approved; rejected attachments can no longer be
+// downloaded.
+func (uc *claimAttachmentUseCase) ReviewAttachment(ctx context.Context, storefrontID, claimID, attachmentID uuid.UUID, req *dto.AttachmentApprovalRequest) (*dto.ClaimAttachmentResponse, error) {
+	attachment, err := uc.getAttachment(ctx, storefrontID, claimID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := ""
+	if req.Notes != nil {
+		notes = *req.Notes
+	}
+
+	switch req.Action {
+	case "approve":
+		if attachment.VirusScanStatus != entity.VirusScanStatusClean {
+			return nil, fmt.Errorf("attachment must pass the virus scan before it is approved")
+		}
+		attachment.MarkAsProcessed(strings.TrimSpace("Approved. " + notes))
+	case "reject":
+		attachment.IsProcessed = false
+		rejection := strings.TrimSpace("Rejected. " + notes)
+		attachment.ProcessingNotes = &rejection
+	default:
+		return nil, fmt.Errorf("invalid action: %s", req.Action)
+	}
+
+	if err := uc.claimRepo.UpdateClaimAttachment(ctx, attachment); err != nil {
+		return nil, fmt.Errorf("failed to update claim attachment: %w", err)
+	}
+
+	attachment.ComputeFields()
+	return dto.ConvertClaimAttachmentToResponse(attachment), nil
+}
+
+// ScanPendingAttachments scans attachments whose scan has not completed
+func (uc *claimAttachmentUseCase) ScanPendingAttachments(ctx context.Context) (int, error) {
+	if uc.scanner == nil {
+		return 0, nil
+	}
+
+	attachments, err := uc.claimRepo.GetAttachmentsByScanStatus(ctx, entity.VirusScanStatusPending, claimAttachmentSweepBatch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending attachments: %w", err)
+	}
+
+	scanned := 0
+	for _, attachment := range attachments {
+		if err := uc.scanAttachment(ctx, attachment); err != nil {
+			uc.logger.Warn("Failed to scan claim attachment", "attachment_id", attachment.ID, "error", err)
+			continue
+		}
+		scanned++
+	}
+	return scanned, nil
+}
+
+// scanAsync scans a freshly uploaded attachment in the background. If the
+// process stops first, the sweep picks the attachment up.
+func (uc *claimAttachmentUseCase) scanAsync(attachmentID uuid.UUID) {
+	if uc.scanner == nil {
+		return
+	}
+
+	go func() {
+		uc.scanSlots <- struct{}{}
+		defer func() { <-uc.scanSlots }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), claimAttachmentScanTimeout)
+		defer cancel()
+
+		attachment, err := uc.claimRepo.GetClaimAttachment(ctx, attachmentID)
+		if err != nil || attachment == nil {
+			return
+		}
+		if err := uc.scanAttachment(ctx, attachment); err != nil {
+			uc.logger.Warn("Failed to scan claim attachment, will retry", "attachment_id", attachmentID, "error", err)
+		}
+	}()
+}
+
+// scanAttachment scans a pending attachment and records the result. Infected
+// files are moved to quarantine. An error leaves the attachment pending so the
+// scan is retried.
+func (uc *claimAttachmentUseCase) scanAttachment(ctx context.Context, attachment *entity.ClaimAttachment) error {
+	if attachment.VirusScanStatus != entity.VirusScanStatusPending {
+		return nil
+	}
+
+	content, err := uc.storage.Get(ctx, attachment.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to open attachment: %w", err)
+	}
+	result, err := uc.scanner.Scan(ctx, content)
+	content.Close()
+
+	var notes string
+	switch {
+	case errors.Is(err, clamav.ErrScanRejected):
+		attachment.SetVirusScanResult(entity.VirusScanStatusFailed)
+		notes = "Virus scan failed: " + err.Error()
+	case err != nil:
+		return err
+	case result.Infected:
+		quarantinePath := claimAttachmentQuarantinePrefix + attachment.FilePath
+		if err := uc.storage.Move(ctx, attachment.FilePath, quarantinePath); err != nil {
+			return fmt.Errorf("failed to quarantine attachment: %w", err)
+		}
+		attachment.FilePath = quarantinePath
+		attachment.SetVirusScanResult(entity.VirusScanStatusInfected)
+		notes = "Quarantined: " + result.Signature + " detected"
+		uc.logger.Warn("Quarantined infected claim attachment",
+			"attachment_id", attachment.ID, "claim_id", attachment.ClaimID, "signature", result.Signature)
+	default:
+		attachment.SetVirusScanResult(entity.VirusScanStatusClean)
+		attachment.MarkAsProcessed("Virus scan passed")
+	}
+	if notes != "" {
+		attachment.ProcessingNotes = &notes
+	}
+
+	if err := uc.claimRepo.UpdateClaimAttachment(ctx, attachment); err != nil {
+		return fmt.Errorf("failed to record scan result: %w", err)
+	}
+	return nil
+}
+
+// getClaim returns a claim of the storefront
+func (uc *claimAttachmentUseCase) getClaim(ctx context.Context, storefrontID, claimID uuid.UUID) (*entity.WarrantyClaim, error) {
+	claim, err := uc.claimRepo.GetByID(ctx, claimID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warranty claim: %w", err)
+	}
+	if claim == nil || claim.StorefrontID != storefrontID {
+		return nil, fmt.Errorf("warranty claim not found")
+	}
+	return claim, nil
+}
+
+// getAttachment returns an attachment of a storefront's claim
+func (uc *claimAttachmentUseCase) getAttachment(ctx context.Context, storefrontID, claimID, attachmentID uuid.UUID) (*entity.ClaimAttachment, error) {
+	if _, err := uc.getClaim(ctx, storefrontID, claimID); err != nil {
+		return nil, err
+	}
+
+	attachment, err := uc.claimRepo.GetClaimAttachment(ctx, attachmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get claim attachment: %w", err)
+	}
+	if attachment == nil || attachment.ClaimID != claimID {
+		return nil, fmt.Errorf("attachment not found")
+	}
+	return attachment, nil
+}
@@ -0,0 +1,416 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/webhook"
+)
+
+// courierReturnedReason is recorded on orders the courier brought back to the seller
+const courierReturnedReason = "Returned to sender by courier"
+
+const (
+	// courierWebhookRetryBatchSize caps the events retried in one sweep
+	courierWebhookRetryBatchSize = 50
+
+	// courierWebhookRetryLease keeps a claimed event away from other workers while it is retried
+	courierWebhookRetryLease = 5 * time.Minute
+)
+
+// CourierWebhookUseCase defines the interface for courier tracking webhooks
+type CourierWebhookUseCase interface {
+	// HandleWebhook stores a raw courier delivery, validates its signature,
+	// records the tracking updates and applies them to orders and warranty claims
+	HandleWebhook(ctx context.Context, courierCode string, payload []byte, headers map[string]string, signature, remoteIP string) (*dto.CourierWebhookResponse, error)
+
+	// ReplayEvent processes a stored delivery again, e.g. after a status mapping fix
+	ReplayEvent(ctx context.Context, eventID uuid.UUID) (*dto.CourierWebhookResponse, error)
+
+	// RetryFailedEvents processes the failed deliveries whose retry is due and
+	// returns how many were retried
+	RetryFailedEvents(ctx context.Context) (int, error)
+
+	// ListEvents lists stored deliveries, newest first
+	ListEvents(ctx context.Context, filters *repository.CourierWebhookEventFilters) (*dto.CourierWebhookEventListResponse, error)
+
+	// GetEvent returns a stored delivery with its payload and headers
+	GetEvent(ctx context.Context, eventID uuid.UUID) (*dto.CourierWebhookEventResponse, error)
+}
+
+// courierWebhookUseCase implements the CourierWebhookUseCase interface
+type courierWebhookUseCase struct {
+	registry        *webhook.Registry
+	eventRepo       repository.CourierWebhookEventRepository
+	orderRepo       repository.OrderRepository
+	claimRepo       repository.WarrantyClaimRepository
+	trackingUseCase ShipmentTrackingUseCase
+	retryPolicy     entity.CourierWebhookRetryPolicy
+	logger          *slog.Logger
+}
+
+// NewCourierWebhookUseCase creates a new courier webhook use case
+func NewCourierWebhookUseCase(
+	registry *webhook.Registry,
+	eventRepo repository.CourierWebhookEventRepository,
+	orderRepo repository.OrderRepository,
+	claimRepo repository.WarrantyClaimRepository,
+	trackingUseCase ShipmentTrackingUseCase,
+	retryPolicy entity.CourierWebhookRetryPolicy,
+	logger *slog.Logger,
+) CourierWebhookUseCase {
+	return &courierWebhookUseCase{
+		registry:        registry,
+		eventRepo:       eventRepo,
+		orderRepo:       orderRepo,
+		claimRepo:       claimRepo,
+		trackingUseCase: trackingUseCase,
+		retryPolicy:     retryPolicy,
+		logger:          logger,
+	}
+}
+
+// HandleWebhook stores a raw courier delivery, validates its signature and
+// applies the resulting tracking updates
+func (uc *courierWebhookUseCase) HandleWebhook(ctx context.Context, courierCode string, payload []byte, headers map[string]string, signature, remoteIP string) (*dto.CourierWebhookResponse, error) {
+	handler, ok := uc.registry.Get(courierCode)
+	if !ok {
+		return nil, fmt.Errorf("courier %q is not supported", courierCode)
+	}
+
+	// Store the delivery before anything else so it can be replayed
+	event := entity.NewCourierWebhookEvent(handler.GetCourierCode(), payload, headers, signature, remoteIP)
+	if err := uc.eventRepo.Create(ctx, event); err != nil {
+		return nil, fmt.Errorf("failed to store webhook event: %w", err)
+	}
+
+	if err := handler.ValidateSignature(payload, signature); err != nil {
+		event.MarkRejected(err)
+		uc.saveEvent(ctx, event)
+		uc.logger.Warn("Rejected courier webhook", "courier", event.CourierCode, "event_id", event.ID, "remote_ip", remoteIP)
+		return nil, fmt.Errorf("signature validation failed: %w", err)
+	}
+	event.MarkSignatureValid()
+
+	return uc.process(ctx, handler, event)
+}
+
+// ReplayEvent processes a stored delivery again
+func (uc *courierWebhookUseCase) ReplayEvent(ctx context.Context, eventID uuid.UUID) (*dto.CourierWebhookResponse, error) {
+	event, err := uc.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook event: %w", err)
+	}
+	if event == nil {
+		return nil, fmt.Errorf("webhook event not found")
+	}
+	if !event.CanReplay() {
+		return nil, fmt.Errorf("webhook event with an invalid signature cannot be replayed")
+	}
+
+	handler, ok := uc.registry.Get(event.CourierCode)
+	if !ok {
+		return nil, fmt.Errorf("courier %q is not supported", event.CourierCode)
+	}
+
+	return uc.process(ctx, handler, event)
+}
+
+// RetryFailedEvents processes the failed deliveries whose retry is due
+func (uc *courierWebhookUseCase) RetryFailedEvents(ctx context.Context) (int, error) {
+	events, err := uc.eventRepo.ClaimDueForRetry(ctx, time.Now(), courierWebhookRetryLease, courierWebhookRetryBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get webhook events due for retry: %w", err)
+	}
+
+	for _, event := range events {
+		handler, ok := uc.registry.Get(event.CourierCode)
+		if !ok {
+			event.MarkFailed(fmt.Errorf("courier %q is not supported", event.CourierCode), uc.retryPolicy)
+			uc.saveEvent(ctx, event)
+			continue
+		}
+
+		// The outcome is recorded on the event, which is all a retry needs
+		if _, err := uc.process(ctx, handler, event); err == nil {
+			uc.logger.Info("Courier webhook retry succeeded", "courier", event.CourierCode, "event_id", event.ID, "attempts", event.Attempts)
+		}
+	}
+
+	return len(events), nil
+}
+
+// ListEvents lists stored deliveries, newest first
+func (uc *courierWebhookUseCase) ListEvents(ctx context.Context, filters *repository.CourierWebhookEventFilters) (*dto.CourierWebhookEventListResponse, error) {
+	if filters.Status != nil && !filters.Status.Valid() {
+		return nil, fmt.Errorf("invalid webhook event status: %s", *filters.Status)
+	}
+
+	events, err := uc.eventRepo.GetWithFilters(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook events: %w", err)
+	}
+
+	total, err := uc.eventRepo.Count(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count webhook events: %w", err)
+	}
+
+	response := &dto.CourierWebhookEventListResponse{
+		Events:     make([]dto.CourierWebhookEventResponse, 0, len(events)),
+		Pagination: buildOrderPagination(filters.Page, filters.PageSize, total),
+	}
+	for _, event := range events {
+		response.Events = append(response.Events, *dto.ConvertCourierWebhookEventToResponse(event, false))
+	}
+
+	return response, nil
+}
+
+// GetEvent returns a stored delivery with its payload and headers
+func (uc *courierWebhookUseCase) GetEvent(ctx context.Context, eventID uuid.UUID) (*dto.CourierWebhookEventResponse, error) {
+	event, err := uc.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook event: %w", err)
+	}
+	if event == nil {
+		return nil, fmt.Errorf("webhook event not found")
+	}
+
+	return dto.ConvertCourierWebhookEventToResponse(event, true), nil
+}
+
+// process parses a stored delivery and applies its tracking updates,
+// recording the outcome on the event
+func (uc *courierWebhookUseCase) process(ctx context.Context, handler webhook.WebhookHandler, event *entity.CourierWebhookEvent) (*dto.CourierWebhookResponse, error) {
+	updates, err := handler.HandleWebhook(ctx, []byte(event.Payload))
+	if err != nil {
+		uc.markFailed(ctx, event, err)
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+
+	response := &dto.CourierWebhookResponse{
+		EventID:      event.ID,
+		CourierCode:  event.CourierCode,
+		UpdatesCount: len(updates),
+	}
+
+	// Apply every update even if one fails; the event is replayed as a whole
+	var errs []error
+	for _, update := range updates {
+		if update == nil || update.TrackingNumber == "" {
+			continue
+		}
+		if update.CourierCode == "" {
+			update.CourierCode = event.CourierCode
+		}
+
+		var orderID, claimID *uuid.UUID
+
+		order, orderUpdated, err := uc.applyToOrder(ctx, update)
+		if err != nil {
+			errs = append(errs, err)
+		} else if orderUpdated {
+			response.OrdersUpdated++
+		}
+		if order != nil {
+			orderID = &order.ID
+		}
+
+		claim, claimUpdated, err := uc.applyToClaim(ctx, update)
+		if err != nil {
+			errs = append(errs, err)
+		} else if claimUpdated {
+			response.ClaimsUpdated++
+		}
+		if claim != nil {
+			claimID = &claim.ID
+		}
+
+		if _, err := uc.trackingUseCase.RecordUpdate(ctx, update, orderID, claimID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		err := errors.Join(errs...)
+		uc.markFailed(ctx, event, err)
+		return nil, fmt.Errorf("failed to apply tracking updates: %w", err)
+	}
+
+	event.MarkProcessed(len(updates))
+	uc.saveEvent(ctx, event)
+
+	uc.logger.Info("Courier webhook processed",
+		"courier", event.CourierCode,
+		"event_id", event.ID,
+		"updates", response.UpdatesCount,
+		"orders_updated", response.OrdersUpdated,
+		"claims_updated", response.ClaimsUpdated)
+
+	return response, nil
+}
+
+// applyToOrder moves the order shipped with the update's tracking number
+// forward and returns it. Updates that do not advance the order are ignored.
+func (uc *courierWebhookUseCase) applyToOrder(ctx context.Context, update *webhook.TrackingUpdate) (*entity.Order, bool, error) {
+	order, err := uc.orderRepo.GetByTrackingNumber(ctx, update.TrackingNumber)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get order for tracking number %s: %w", update.TrackingNumber, err)
+	}
+	if order == nil {
+		return nil, false, nil
+	}
+
+	var histories []*entity.OrderStatusHistory
+
+	// A label booked ahead of pickup: the first courier scan ships the order
+	if order.Status == entity.OrderStatusProcessing && update.Status.IsShipped() {
+		carrier := update.CourierCode
+		if order.ShippingCarrier != nil && *order.ShippingCarrier != "" {
+			carrier = *order.ShippingCarrier
+		}
+		history, err := order.Ship(nil, carrier, update.TrackingNumber)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to ship order %s: %w", order.OrderNumber, err)
+		}
+		histories = append(histories, history)
+	}
+
+	switch update.Status {
+	case webhook.TrackingStateDelivered:
+		if order.Status == entity.OrderStatusShipped {
+			history, err := order.MarkAsDelivered(nil)
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to mark order %s as delivered: %w", order.OrderNumber, err)
+			}
+			histories = append(histories, history)
+		}
+	case webhook.TrackingStateReturned:
+		if order.Status == entity.OrderStatusShipped || order.Status == entity.OrderStatusDelivered {
+			history, err := order.MarkAsReturned(nil, courierReturnedReason)
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to mark order %s as returned: %w", order.OrderNumber, err)
+			}
+			histories = append(histories, history)
+		}
+	}
+
+	for _, history := range histories {
+		if update.StatusText != "" {
+			notes := fmt.Sprintf("%s: %s", update.CourierCode, update.StatusText)
+			history.Notes = &notes
+		}
+		if err := uc.orderRepo.UpdateStatus(ctx, order, history); err != nil {
+			return nil, false, fmt.Errorf("failed to update order %s: %w", order.OrderNumber, err)
+		}
+	}
+
+	return order, len(histories) > 0, nil
+}
+
+// applyToClaim moves the warranty claim whose repaired or replacement item
+// travels under the update's tracking number forward and returns it
+func (uc *courierWebhookUseCase) applyToClaim(ctx context.Context, update *webhook.TrackingUpdate) (*entity.WarrantyClaim, bool, error) {
+	claim, err := uc.claimRepo.GetByTrackingNumber(ctx, update.TrackingNumber)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get warranty claim for tracking number %s: %w", update.TrackingNumber, err)
+	}
+	if claim == nil {
+		return nil, false, nil
+	}
+
+	changed := false
+
+	// The return label was booked before the courier picked the item up
+	if (claim.Status == entity.ClaimStatusRepaired || claim.Status == entity.ClaimStatusReplaced) && update.Status.IsShipped() {
+		provider := update.CourierCode
+		if claim.ShippingProvider != nil && *claim.ShippingProvider != "" {
+			provider = *claim.ShippingProvider
+		}
+		if err := claim.Ship(uuid.Nil, provider, update.TrackingNumber, claim.EstimatedDeliveryDate, claim.ShippingCost); err != nil {
+			return nil, false, fmt.Errorf("failed to ship warranty claim %s: %w", claim.ClaimNumber, err)
+		}
+		changed = true
+	}
+
+	if claim.Status == entity.ClaimStatusShipped {
+		if update.Status == webhook.TrackingStateDelivered {
+			if err := claim.MarkAsDelivered(uuid.Nil); err != nil {
+				return nil, false, fmt.Errorf("failed to mark warranty claim %s as delivered: %w", claim.ClaimNumber, err)
+			}
+			if !update.Timestamp.IsZero() {
+				deliveredAt := update.Timestamp
+				claim.ActualDeliveryDate = &deliveredAt
+			}
+			changed = true
+		} else if deliveryStatus, ok := claimDeliveryStatus(update.Status); ok && claim.DeliveryStatus != deliveryStatus {
+			claim.DeliveryStatus = deliveryStatus
+			changed = true
+		}
+	}
+
+	if !changed {
+		return claim, false, nil
+	}
+
+	// Courier updates are system changes, not made by a user
+	claim.StatusUpdatedBy = nil
+	if err := uc.claimRepo.Update(ctx, claim); err != nil {
+		return nil, false, fmt.Errorf("failed to update warranty claim %s: %w", claim.ClaimNumber, err)
+	}
+
+	return claim, true, nil
+}
+
+// markFailed records a failed processing attempt, scheduling a retry or
+// dead-lettering the event
+func (uc *courierWebhookUseCase) markFailed(ctx context.Context, event *entity.CourierWebhookEvent, err error) {
+	event.MarkFailed(err, uc.retryPolicy)
+	uc.saveEvent(ctx, event)
+
+	if event.IsDeadLettered() {
+		uc.logger.Error("Courier webhook dead-lettered", "courier", event.CourierCode, "event_id", event.ID, "attempts", event.Attempts, "error", err)
+		return
+	}
+	uc.logger.Warn("Courier webhook processing failed, retry scheduled",
+		"courier", event.CourierCode,
+		"event_id", event.ID,
+		"attempts", event.Attempts,
+		"next_retry_at", event.NextRetryAt,
+		"error", err)
+}
+
+// saveEvent persists the processing outcome of an event. Failures are only
+// logged because the outcome of the delivery itself is already decided.
+func (uc *courierWebhookUseCase) saveEvent(ctx context.Context, event *entity.CourierWebhookEvent) {
+	if err := uc.eventRepo.Update(ctx, event); err != nil {
+		uc.logger.Error("Failed to save courier webhook event", "event_id", event.ID, "error", err)
+	}
+}
+
+// claimDeliveryStatus maps an in-flight tracking state to a claim delivery status
+func claimDeliveryStatus(state webhook.TrackingState) (entity.DeliveryStatus, bool) {
+	switch state {
+	case webhook.TrackingStatePickedUp:
+		return entity.DeliveryStatusPickedUp, true
+	case webhook.TrackingStateInTransit:
+		return entity.DeliveryStatusInTransit, true
+	case webhook.TrackingStateOutForDelivery:
+		return entity.DeliveryStatusOutForDelivery, true
+	case webhook.TrackingStateDeliveryFailed:
+		return entity.DeliveryStatusFailedDelivery, true
+	case webhook.TrackingStateReturning, webhook.TrackingStateReturned:
+		return entity.DeliveryStatusReturned, true
+	default:
+		return "", false
+	}
+}
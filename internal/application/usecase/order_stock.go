@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+)
+
+// orderStock settles the stock reservations held by an order. The checkout
+// sweeper and order cancellation share it, so stock moves the same way on
+// every path. Callers run it inside the transaction that changes the order.
+type orderStock struct {
+	reservationRepo repository.StockReservationRepository
+	productUseCase  *ProductUseCase
+}
+
+// newOrderStock creates the order stock settler
+func newOrderStock(reservationRepo repository.StockReservationRepository, productUseCase *ProductUseCase) *orderStock {
+	return &orderStock{
+		reservationRepo: reservationRepo,
+		productUseCase:  productUseCase,
+	}
+}
+
+// commit marks the order's active reservations as consumed by the order
+func (s *orderStock) commit(ctx context.Context, orderID uuid.UUID) error {
+	reservations, err := s.reservationRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, reservation := range reservations {
+		if !reservation.IsActive() {
+			continue
+		}
+		if err := reservation.Commit(); err != nil {
+			return err
+		}
+		if err := s.reservationRepo.Update(ctx, reservation); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// release returns the order's stock to inventory: active reservations are
+// released and stock already committed to the order is restocked
+func (s *orderStock) release(ctx context.Context, orderID uuid.UUID, reason string) error {
+	reservations, err := s.reservationRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, reservation := range reservations {
+		if reservation.Status == entity.StockReservationStatusReleased {
+			continue
+		}
+		if err := s.restock(ctx, reservation, reason); err != nil {
+			return err
+		}
+		if err := reservation.Release(reason); err != nil {
+			return err
+		}
+		if err := s.reservationRepo.Update(ctx, reservation); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restock returns the reserved quantity to the product or variant
+func (s *orderStock) restock(ctx context.Context, reservation *entity.StockReservation, reason string) error {
+	if reservation.ProductVariantID != nil {
+		return s.productUseCase.ReleaseVariantStock(ctx, *reservation.ProductVariantID, reservation.Quantity, reason)
+	}
+	return s.productUseCase.ReleaseStock(ctx, reservation.ProductID, reservation.Quantity, reason)
+}
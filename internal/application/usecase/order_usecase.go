@@ -0,0 +1,557 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/database"
+)
+
+// OrderUseCase defines the interface for order business logic
+type OrderUseCase interface {
+	// CreateOrder creates an order manually on the storefront on behalf of a customer
+	CreateOrder(ctx context.Context, storefrontID uuid.UUID, req *dto.CreateOrderRequest, createdBy uuid.UUID) (*dto.OrderResponse, error)
+
+	// GetOrder retrieves an order of the storefront by ID
+	GetOrder(ctx context.Context, storefrontID, orderID uuid.UUID) (*dto.OrderResponse, error)
+
+	// ListOrders retrieves the storefront's orders with filters and pagination
+	ListOrders(ctx context.Context, storefrontID uuid.UUID, filters *repository.OrderFilters) (*dto.OrderListResponse, error)
+
+	// UpdateOrderStatus moves an order of the storefront through its lifecycle
+	UpdateOrderStatus(ctx context.Context, storefrontID, orderID uuid.UUID, req *dto.UpdateOrderStatusRequest, updatedBy uuid.UUID) (*dto.OrderResponse, error)
+
+	// UpdatePaymentStatus updates the payment status of an order of the storefront
+	UpdatePaymentStatus(ctx context.Context, storefrontID, orderID uuid.UUID, req *dto.UpdateOrderPaymentRequest) (*dto.OrderResponse, error)
+
+	// CancelOrder cancels an order of the storefront on behalf of the seller
+	CancelOrder(ctx context.Context, storefrontID, orderID uuid.UUID, req *dto.CancelOrderRequest, cancelledBy uuid.UUID) (*dto.OrderResponse, error)
+
+	// GetOrderHistory retrieves the status history of an order of the storefront
+	GetOrderHistory(ctx context.Context, storefrontID, orderID uuid.UUID) ([]*dto.OrderStatusHistoryResponse, error)
+
+	// GetOrderStatistics retrieves order statistics of the storefront
+	GetOrderStatistics(ctx context.Context, storefrontID uuid.UUID, filters *repository.OrderFilters) (*dto.OrderStatisticsResponse, error)
+
+	// ListCustomerOrders retrieves the orders a customer placed on a storefront
+	ListCustomerOrders(ctx context.Context, storefrontID, customerID uuid.UUID, status *entity.OrderStatus, page, pageSize int) (*dto.OrderListResponse, error)
+
+	// GetCustomerOrder retrieves a single order owned by the customer
+	GetCustomerOrder(ctx context.Context, storefrontID, customerID, orderID uuid.UUID) (*dto.OrderResponse, error)
+
+	// GetCustomerOrderStatus retrieves the tracking status of an order owned by the customer
+	GetCustomerOrderStatus(ctx context.Context, storefrontID, customerID, orderID uuid.UUID) (*dto.OrderTrackingResponse, error)
+
+	// CancelCustomerOrder cancels an order owned by the customer
+	CancelCustomerOrder(ctx context.Context, storefrontID, customerID, orderID uuid.UUID, reason string) (*dto.OrderResponse, error)
+}
+
+// orderUseCase implements the OrderUseCase interface
+type orderUseCase struct {
+	db        *sqlx.DB
+	orderRepo repository.OrderRepository
+	stock     *orderStock
+	logger    *slog.Logger
+}
+
+// NewOrderUseCase creates a new order use case
+func NewOrderUseCase(
+	db *sqlx.DB,
+	orderRepo repository.OrderRepository,
+	reservationRepo repository.StockReservationRepository,
+	productUseCase *ProductUseCase,
+	logger *slog.Logger,
+) OrderUseCase {
+	return &orderUseCase{
+		db:        db,
+		orderRepo: orderRepo,
+		stock:     newOrderStock(reservationRepo, productUseCase),
+		logger:    logger,
+	}
+}
+
+// CreateOrder creates an order manually on the storefront on behalf of a customer
+func (uc *orderUseCase) CreateOrder(ctx context.Context, storefrontID uuid.UUID, req *dto.CreateOrderRequest, createdBy uuid.UUID) (*dto.OrderResponse, error) {
+	order := entity.NewOrder(createdBy, &storefrontID, req.Currency)
+
+	if req.CustomerID != nil && *req.CustomerID != "" {
+		customerID, err := uuid.Parse(*req.CustomerID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid customer_id: %w", err)
+		}
+		order.CustomerID = &customerID
+	}
+	order.CustomerEmail = optionalString(req.CustomerEmail)
+	order.CustomerPhone = optionalString(req.CustomerPhone)
+
+	order.TaxAmount = req.TaxAmount
+	order.ShippingAmount = req.ShippingAmount
+	order.DiscountAmount = req.DiscountAmount
+	order.PaymentMethod = optionalString(req.PaymentMethod)
+	order.ShippingMethod = optionalString(req.ShippingMethod)
+	order.Notes = optionalString(req.Notes)
+	order.InternalNotes = optionalString(req.InternalNotes)
+	order.Tags = req.Tags
+	order.ChannelReference = optionalString(req.ChannelReference)
+	if req.Channel != "" {
+		order.Channel = entity.OrderChannel(req.Channel)
+	}
+
+	applyBillingAddress(order, req.BillingAddress)
+	applyShippingAddress(order, req.ShippingAddress)
+
+	for _, itemReq := range req.Items {
+		item := entity.NewOrderItem(order.ID, itemReq.ProductName, itemReq.UnitPrice, itemReq.Quantity)
+		if itemReq.ProductID != nil && *itemReq.ProductID != "" {
+			productID, err := uuid.Parse(*itemReq.ProductID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid product_id: %w", err)
+			}
+			item.ProductID = &productID
+		}
+		if itemReq.ProductVariantID != nil && *itemReq.ProductVariantID != "" {
+			variantID, err := uuid.Parse(*itemReq.ProductVariantID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid product_variant_id: %w", err)
+			}
+			item.ProductVariantID = &variantID
+		}
+		item.ProductSKU = optionalString(itemReq.ProductSKU)
+		item.VariantName = optionalString(itemReq.VariantName)
+		item.ProductWeight = itemReq.ProductWeight
+		item.ProductImageURL = optionalString(itemReq.ProductImageURL)
+		order.Items = append(order.Items, item)
+	}
+
+	order.CalculateTotals()
+
+	orderNumber, err := uc.orderRepo.GenerateOrderNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate order number: %w", err)
+	}
+	order.OrderNumber = orderNumber
+
+	if err := order.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid order: %w", err)
+	}
+
+	if err := uc.orderRepo.Create(ctx, order); err != nil {
+		uc.logger.Error("Failed to create order", "error", err, "created_by", createdBy)
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+
+	uc.logger.Info("Order created", "order_id", order.ID, "order_number", order.OrderNumber, "total", order.TotalAmount.String())
+
+	order.ComputeFields()
+	return dto.ConvertOrderToResponse(order), nil
+}
+
+// GetOrder retrieves an order of the storefront by ID
+func (uc *orderUseCase) GetOrder(ctx context.Context, storefrontID, orderID uuid.UUID) (*dto.OrderResponse, error) {
+	order, err := uc.getStorefrontOrder(ctx, storefrontID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ConvertOrderToResponse(order), nil
+}
+
+// ListOrders retrieves the storefront's orders with filters and pagination
+func (uc *orderUseCase) ListOrders(ctx context.Context, storefrontID uuid.UUID, filters *repository.OrderFilters) (*dto.OrderListResponse, error) {
+	filters.StorefrontID = &storefrontID
+
+	orders, err := uc.orderRepo.GetWithFilters(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	total, err := uc.orderRepo.Count(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	response := &dto.OrderListResponse{
+		Orders:     make([]dto.OrderResponse, 0, len(orders)),
+		Pagination: buildOrderPagination(filters.Page, filters.PageSize, total),
+	}
+	for _, order := range orders {
+		response.Orders = append(response.Orders, *dto.ConvertOrderToResponse(order))
+	}
+
+	return response, nil
+}
+
+// UpdateOrderStatus moves an order of the storefront through its lifecycle
+func (uc *orderUseCase) UpdateOrderStatus(ctx context.Context, storefrontID, orderID uuid.UUID, req *dto.UpdateOrderStatusRequest, updatedBy uuid.UUID) (*dto.OrderResponse, error) {
+	order, err := uc.getStorefrontOrder(ctx, storefrontID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cancelling also returns the order's stock
+	if entity.OrderStatus(req.Status) == entity.OrderStatusCancelled {
+		if err := uc.cancel(ctx, order, &updatedBy, req.Reason); err != nil {
+			return nil, err
+		}
+		return dto.ConvertOrderToResponse(order), nil
+	}
+
+	var history *entity.OrderStatusHistory
+	switch entity.OrderStatus(req.Status) {
+	case entity.OrderStatusConfirmed:
+		history, err = order.Confirm(&updatedBy)
+	case entity.OrderStatusProcessing:
+		history, err = order.StartProcessing(&updatedBy)
+	case entity.OrderStatusShipped:
+		history, err = order.Ship(&updatedBy, req.Carrier, req.TrackingNumber)
+	case entity.OrderStatusDelivered:
+		history, err = order.MarkAsDelivered(&updatedBy)
+	case entity.OrderStatusReturned:
+		history, err = order.MarkAsReturned(&updatedBy, req.Reason)
+	case entity.OrderStatusRefunded:
+		history, err = order.Refund(&updatedBy, req.Reason)
+	default:
+		return nil, fmt.Errorf("invalid status: %s", req.Status)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Reason != "" && history.Reason == nil {
+		history.Reason = &req.Reason
+	}
+
+	if err := uc.orderRepo.UpdateStatus(ctx, order, history); err != nil {
+		uc.logger.Error("Failed to update order status", "error", err, "order_id", orderID, "status", req.Status)
+		return nil, fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	uc.logger.Info("Order status updated", "order_id", orderID, "status", order.Status, "updated_by", updatedBy)
+
+	order.ComputeFields()
+	return dto.ConvertOrderToResponse(order), nil
+}
+
+// UpdatePaymentStatus updates the payment status of an order of the storefront
+func (uc *orderUseCase) UpdatePaymentStatus(ctx context.Context, storefrontID, orderID uuid.UUID, req *dto.UpdateOrderPaymentRequest) (*dto.OrderResponse, error) {
+	paymentStatus := entity.PaymentStatus(req.PaymentStatus)
+	if !paymentStatus.Valid() {
+		return nil, fmt.Errorf("invalid payment status: %s", req.PaymentStatus)
+	}
+
+	order, err := uc.getStorefrontOrder(ctx, storefrontID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keeping the status only updates the method or reference
+	if paymentStatus != order.PaymentStatus && !order.PaymentStatus.CanTransitionTo(paymentStatus) {
+		return nil, fmt.Errorf("payment cannot transition from %s to %s", order.PaymentStatus, paymentStatus)
+	}
+
+	method := optionalString(req.PaymentMethod)
+	reference := optionalString(req.PaymentReference)
+	updated, err := uc.orderRepo.UpdatePaymentIfStatus(ctx, orderID, order.PaymentStatus, paymentStatus, method, reference)
+	if err != nil {
+		uc.logger.Error("Failed to update order payment", "error", err, "order_id", orderID)
+		return nil, fmt.Errorf("failed to update payment status: %w", err)
+	}
+	if !updated {
+		return nil, fmt.Errorf("payment cannot transition from %s to %s: payment status changed concurrently", order.PaymentStatus, paymentStatus)
+	}
+
+	order.PaymentStatus = paymentStatus
+	if method != nil {
+		order.PaymentMethod = method
+	}
+	if reference != nil {
+		order.PaymentReference = reference
+	}
+
+	uc.logger.Info("Order payment updated", "order_id", orderID, "payment_status", paymentStatus)
+
+	order.ComputeFields()
+	return dto.ConvertOrderToResponse(order), nil
+}
+
+// CancelOrder cancels an order of the storefront on behalf of the seller
+func (uc *orderUseCase) CancelOrder(ctx context.Context, storefrontID, orderID uuid.UUID, req *dto.CancelOrderRequest, cancelledBy uuid.UUID) (*dto.OrderResponse, error) {
+	order, err := uc.getStorefrontOrder(ctx, storefrontID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.cancel(ctx, order, &cancelledBy, req.Reason); err != nil {
+		return nil, err
+	}
+
+	return dto.ConvertOrderToResponse(order), nil
+}
+
+// GetOrderHistory retrieves the status history of an order of the storefront
+func (uc *orderUseCase) GetOrderHistory(ctx context.Context, storefrontID, orderID uuid.UUID) ([]*dto.OrderStatusHistoryResponse, error) {
+	if _, err := uc.getStorefrontOrder(ctx, storefrontID, orderID); err != nil {
+		return nil, err
+	}
+
+	return uc.getHistory(ctx, orderID)
+}
+
+// GetOrderStatistics retrieves order statistics of the storefront
+func (uc *orderUseCase) GetOrderStatistics(ctx context.Context, storefrontID uuid.UUID, filters *repository.OrderFilters) (*dto.OrderStatisticsResponse, error) {
+	filters.StorefrontID = &storefrontID
+
+	stats, err := uc.orderRepo.GetOrderStatistics(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order statistics: %w", err)
+	}
+
+	return dto.ConvertOrderStatisticsToResponse(stats), nil
+}
+
+// ListCustomerOrders retrieves the orders a customer placed on a storefront
+func (uc *orderUseCase) ListCustomerOrders(ctx context.Context, storefrontID, customerID uuid.UUID, status *entity.OrderStatus, page, pageSize int) (*dto.OrderListResponse, error) {
+	filters := &repository.OrderFilters{
+		StorefrontID: &storefrontID,
+		CustomerID:   &customerID,
+		Status:       status,
+		Page:         page,
+		PageSize:     pageSize,
+		IncludeItems: true,
+	}
+
+	orders, err := uc.orderRepo.GetWithFilters(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customer orders: %w", err)
+	}
+
+	total, err := uc.orderRepo.Count(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count customer orders: %w", err)
+	}
+
+	response := &dto.OrderListResponse{
+		Orders:     make([]dto.OrderResponse, 0, len(orders)),
+		Pagination: buildOrderPagination(page, pageSize, total),
+	}
+	for _, order := range orders {
+		response.Orders = append(response.Orders, *dto.ConvertOrderToCustomerResponse(order))
+	}
+
+	return response, nil
+}
+
+// GetCustomerOrder retrieves a single order owned by the customer
+func (uc *orderUseCase) GetCustomerOrder(ctx context.Context, storefrontID, customerID, orderID uuid.UUID) (*dto.OrderResponse, error) {
+	order, err := uc.getCustomerOrder(ctx, storefrontID, customerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ConvertOrderToCustomerResponse(order), nil
+}
+
+// GetCustomerOrderStatus retrieves the tracking status of an order owned by the customer
+func (uc *orderUseCase) GetCustomerOrderStatus(ctx context.Context, storefrontID, customerID, orderID uuid.UUID) (*dto.OrderTrackingResponse, error) {
+	order, err := uc.getCustomerOrder(ctx, storefrontID, customerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := uc.getHistory(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.OrderTrackingResponse{
+		OrderNumber:            order.OrderNumber,
+		Status:                 order.Status.String(),
+		PaymentStatus:          string(order.PaymentStatus),
+		ShippingCarrier:        order.ShippingCarrier,
+		ShippingTrackingNumber: order.ShippingTrackingNumber,
+		ShippedAt:              order.ShippedAt,
+		DeliveredAt:            order.DeliveredAt,
+		History:                make([]dto.OrderStatusHistoryResponse, 0, len(history)),
+	}
+	for _, h := range history {
+		// Customers see what happened, not which staff member did it
+		h.ChangedBy = nil
+		response.History = append(response.History, *h)
+	}
+
+	return response, nil
+}
+
+// CancelCustomerOrder cancels an order owned by the customer
+func (uc *orderUseCase) CancelCustomerOrder(ctx context.Context, storefrontID, customerID, orderID uuid.UUID, reason string) (*dto.OrderResponse, error) {
+	order, err := uc.getCustomerOrder(ctx, storefrontID, customerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Customers may only cancel before the seller starts working on the order
+	if order.Status != entity.OrderStatusPending && order.Status != entity.OrderStatusConfirmed {
+		return nil, fmt.Errorf("order can no longer be cancelled, current status: %s", order.Status)
+	}
+
+	if reason == "" {
+		reason = "Cancelled by customer"
+	}
+	if err := uc.cancel(ctx, order, nil, reason); err != nil {
+		return nil, err
+	}
+
+	return dto.ConvertOrderToCustomerResponse(order), nil
+}
+
+// getOrder loads an order and fails with a not found error when it doesn't exist
+func (uc *orderUseCase) getOrder(ctx context.Context, orderID uuid.UUID) (*entity.Order, error) {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order == nil {
+		return nil, fmt.Errorf("order not found")
+	}
+
+	return order, nil
+}
+
+// getStorefrontOrder loads an order and checks that it was placed on the given
+// storefront. Other storefronts' orders are reported as not found.
+func (uc *orderUseCase) getStorefrontOrder(ctx context.Context, storefrontID, orderID uuid.UUID) (*entity.Order, error) {
+	order, err := uc.getOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.StorefrontID == nil || *order.StorefrontID != storefrontID {
+		return nil, fmt.Errorf("order not found")
+	}
+
+	return order, nil
+}
+
+// getCustomerOrder loads an order and checks that the customer placed it on the given storefront.
+// Orders owned by someone else are reported as not found so their existence isn't leaked.
+func (uc *orderUseCase) getCustomerOrder(ctx context.Context, storefrontID, customerID, orderID uuid.UUID) (*entity.Order, error) {
+	order, err := uc.getOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !order.BelongsToCustomer(customerID) || order.StorefrontID == nil || *order.StorefrontID != storefrontID {
+		uc.logger.Warn("Customer attempted to access another customer's order",
+			"order_id", orderID, "customer_id", customerID, "storefront_id", storefrontID)
+		return nil, fmt.Errorf("order not found")
+	}
+
+	return order, nil
+}
+
+// cancel cancels the order and returns its reserved or committed stock to
+// inventory in the same transaction
+func (uc *orderUseCase) cancel(ctx context.Context, order *entity.Order, cancelledBy *uuid.UUID, reason string) error {
+	history, err := order.Cancel(cancelledBy, reason)
+	if err != nil {
+		return err
+	}
+
+	err = database.WithTransaction(ctx, uc.db, func(txCtx context.Context, _ *sqlx.Tx) error {
+		if err := uc.orderRepo.UpdateStatus(txCtx, order, history); err != nil {
+			return err
+		}
+		return uc.stock.release(txCtx, order.ID, reason)
+	})
+	if err != nil {
+		uc.logger.Error("Failed to cancel order", "error", err, "order_id", order.ID)
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+
+	uc.logger.Info("Order cancelled", "order_id", order.ID, "reason", reason)
+
+	order.ComputeFields()
+	return nil
+}
+
+// getHistory loads the status history of an order as DTOs
+func (uc *orderUseCase) getHistory(ctx context.Context, orderID uuid.UUID) ([]*dto.OrderStatusHistoryResponse, error) {
+	history, err := uc.orderRepo.GetStatusHistory(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order history: %w", err)
+	}
+
+	responses := make([]*dto.OrderStatusHistoryResponse, 0, len(history))
+	for _, h := range history {
+		responses = append(responses, dto.ConvertOrderStatusHistoryToResponse(h))
+	}
+
+	return responses, nil
+}
+
+// buildOrderPagination builds the pagination block for order list responses
+func buildOrderPagination(page, pageSize, total int) dto.PaginationResponse {
+	totalPages := 0
+	if pageSize > 0 {
+		totalPages = (total + pageSize - 1) / pageSize
+	}
+
+	return dto.PaginationResponse{
+		Page:       page,
+		Limit:      pageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}
+}
+
+// applyBillingAddress copies a billing address request onto the order
+func applyBillingAddress(order *entity.Order, addr *dto.OrderAddressRequest) {
+	if addr == nil {
+		return
+	}
+	order.BillingFirstName = optionalString(addr.FirstName)
+	order.BillingLastName = optionalString(addr.LastName)
+	order.BillingCompany = optionalString(addr.Company)
+	order.BillingAddressLine1 = optionalString(addr.AddressLine1)
+	order.BillingAddressLine2 = optionalString(addr.AddressLine2)
+	order.BillingCity = optionalString(addr.City)
+	order.BillingStateProvince = optionalString(addr.StateProvince)
+	order.BillingPostalCode = optionalString(addr.PostalCode)
+	order.BillingCountry = optionalString(addr.Country)
+	order.BillingPhone = optionalString(addr.Phone)
+}
+
+// applyShippingAddress copies a shipping address request onto the order
+func applyShippingAddress(order *entity.Order, addr *dto.OrderAddressRequest) {
+	if addr == nil {
+		return
+	}
+	order.ShippingFirstName = optionalString(addr.FirstName)
+	order.ShippingLastName = optionalString(addr.LastName)
+	order.ShippingCompany = optionalString(addr.Company)
+	order.ShippingAddressLine1 = optionalString(addr.AddressLine1)
+	order.ShippingAddressLine2 = optionalString(addr.AddressLine2)
+	order.ShippingCity = optionalString(addr.City)
+	order.ShippingStateProvince = optionalString(addr.StateProvince)
+	order.ShippingPostalCode = optionalString(addr.PostalCode)
+	order.ShippingCountry = optionalString(addr.Country)
+	order.ShippingPhone = optionalString(addr.Phone)
+}
+
+// optionalString returns nil for blank strings so optional columns stay NULL
+func optionalString(s string) *string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,357 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/database"
+	"github.com/kirimku/smartseller-backend/pkg/payment"
+)
+
+// paymentMethodEWallet is the checkout payment method paid with an e-wallet
+// charge; every other method is paid through a hosted invoice
+const paymentMethodEWallet = "ewallet"
+
+// PaymentUseCase defines the interface for collecting order payments through
+// payment gateways
+type PaymentUseCase interface {
+	// CreateOrderPayment creates an invoice or e-wallet charge for a pending
+	// order. customerID is nil for guests, who confirm the order's email instead.
+	CreateOrderPayment(ctx context.Context, storefrontID uuid.UUID, customerID *uuid.UUID, req *dto.OrderPaymentRequest) (*dto.OrderPaymentResponse, error)
+
+	// HandleCallback verifies a gateway callback and applies its outcome to the
+	// order. Repeated and out-of-date callbacks change nothing.
+	HandleCallback(ctx context.Context, gateway string, header http.Header, payload []byte) (*dto.PaymentCallbackResponse, error)
+}
+
+// paymentUseCase implements the PaymentUseCase interface
+type paymentUseCase struct {
+	db                    *sqlx.DB
+	orderRepo             repository.OrderRepository
+	storefrontRepo        repository.StorefrontRepository
+	gateways              map[string]payment.PaymentGateway
+	defaultGateway        string
+	invoiceDuration       time.Duration
+	defaultEWalletChannel string
+	logger                *slog.Logger
+}
+
+// NewPaymentUseCase creates a new payment use case. New payments go to the
+// gateway the storefront picked for the order's payment method, or the default
+// gateway; callbacks are accepted from any of the gateways.
+func NewPaymentUseCase(
+	db *sqlx.DB,
+	orderRepo repository.OrderRepository,
+	storefrontRepo repository.StorefrontRepository,
+	gateways []payment.PaymentGateway,
+	defaultGateway string,
+	invoiceDuration time.Duration,
+	defaultEWalletChannel string,
+	logger *slog.Logger,
+) PaymentUseCase {
+	byName := make(map[string]payment.PaymentGateway, len(gateways))
+	for _, gateway := range gateways {
+		byName[gateway.Name()] = gateway
+	}
+
+	return &paymentUseCase{
+		db:                    db,
+		orderRepo:             orderRepo,
+		storefrontRepo:        storefrontRepo,
+		gateways:              byName,
+		defaultGateway:        strings.ToLower(defaultGateway),
+		invoiceDuration:       invoiceDuration,
+		defaultEWalletChannel: defaultEWalletChannel,
+		logger:                logger,
+	}
+}
+
+// CreateOrderPayment creates an invoice or e-wallet charge for a pending order
+// and records the gateway's reference on it
+func (uc *paymentUseCase) CreateOrderPayment(ctx context.Context, storefrontID uuid.UUID, customerID *uuid.UUID, req *dto.OrderPaymentRequest) (*dto.OrderPaymentResponse, error) {
+	orderID, err := uuid.Parse(req.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID")
+	}
+
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order == nil || !canPayOrder(order, storefrontID, customerID, req.CustomerEmail) {
+		return nil, fmt.Errorf("order not found")
+	}
+	if err := order.CanStartPayment(); err != nil {
+		return nil, err
+	}
+
+	gateway, err := uc.orderGateway(ctx, storefrontID, order)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := order.Currency
+	if currency == "" {
+		currency = "IDR"
+	}
+	// A partly paid order is charged only what is still due
+	amount := order.AmountDue()
+
+	var charge *payment.Charge
+	if order.PaymentMethod != nil && strings.EqualFold(*order.PaymentMethod, paymentMethodEWallet) {
+		channel := req.EWalletChannel
+		if channel == "" {
+			channel = uc.defaultEWalletChannel
+		}
+		charge, err = gateway.CreateEWalletCharge(ctx, &payment.EWalletChargeRequest{
+			ExternalID:         order.ID.String(),
+			Amount:             amount,
+			Currency:           currency,
+			Channel:            channel,
+			MobileNumber:       req.MobileNumber,
+			SuccessRedirectURL: req.SuccessRedirectURL,
+			FailureRedirectURL: req.FailureRedirectURL,
+		})
+	} else {
+		invoice := &payment.InvoiceRequest{
+			ExternalID:         order.ID.String(),
+			Amount:             amount,
+			Currency:           currency,
+			Description:        fmt.Sprintf("Order %s", order.OrderNumber),
+			Duration:           uc.invoiceDuration,
+			SuccessRedirectURL: req.SuccessRedirectURL,
+			FailureRedirectURL: req.FailureRedirectURL,
+		}
+		if order.CustomerEmail != nil {
+			invoice.PayerEmail = *order.CustomerEmail
+		}
+		charge, err = gateway.CreateInvoice(ctx, invoice)
+	}
+	if err != nil {
+		uc.logger.Error("Failed to create payment", "error", err, "order_id", order.ID, "gateway", gateway.Name())
+		return nil, fmt.Errorf("failed to create payment: %w", err)
+	}
+
+	// Money already received keeps the order partly paid while the rest is due
+	status := entity.PaymentStatusPending
+	if order.PaymentStatus == entity.PaymentStatusPartiallyPaid {
+		status = entity.PaymentStatusPartiallyPaid
+	}
+
+	// A callback for an earlier charge may have settled the order meanwhile
+	updated, err := uc.orderRepo.UpdatePaymentIfStatus(ctx, order.ID, order.PaymentStatus, status, nil, &charge.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record payment: %w", err)
+	}
+	if !updated {
+		return nil, fmt.Errorf("order payment is no longer pending")
+	}
+
+	uc.logger.Info("Payment created", "order_id", order.ID, "gateway", gateway.Name(), "payment_reference", charge.ID)
+
+	return &dto.OrderPaymentResponse{
+		OrderID:           order.ID.String(),
+		OrderNumber:       order.OrderNumber,
+		Gateway:           gateway.Name(),
+		PaymentReference:  charge.ID,
+		PaymentStatus:     string(status),
+		Amount:            amount,
+		Currency:          currency,
+		CheckoutURL:       charge.CheckoutURL,
+		MobileDeeplinkURL: charge.MobileDeeplinkURL,
+		ExpiresAt:         charge.ExpiresAt,
+	}, nil
+}
+
+// HandleCallback verifies a gateway callback and applies its outcome to the order
+func (uc *paymentUseCase) HandleCallback(ctx context.Context, gatewayName string, header http.Header, payload []byte) (*dto.PaymentCallbackResponse, error) {
+	gateway, ok := uc.gateways[strings.ToLower(gatewayName)]
+	if !ok {
+		return nil, fmt.Errorf("payment gateway %s is not supported", gatewayName)
+	}
+
+	event, err := gateway.ParseCallback(ctx, header, payload)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidCallback) {
+			uc.logger.Warn("Rejected payment callback", "gateway", gateway.Name())
+			return nil, fmt.Errorf("callback verification failed")
+		}
+		return nil, fmt.Errorf("invalid %s callback: %w", gateway.Name(), err)
+	}
+
+	found, err := uc.callbackOrder(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	// Callbacks for an order are applied one at a time, so the amounts of
+	// concurrent partial payments add up
+	var response *dto.PaymentCallbackResponse
+	err = database.WithTransaction(ctx, uc.db, func(txCtx context.Context, _ *sqlx.Tx) error {
+		response, err = uc.applyCallback(txCtx, gateway, found.ID, event)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// applyCallback moves the payment of the order, locked by the caller's
+// transaction, to the status a callback reports
+func (uc *paymentUseCase) applyCallback(ctx context.Context, gateway payment.PaymentGateway, orderID uuid.UUID, event *payment.CallbackEvent) (*dto.PaymentCallbackResponse, error) {
+	order, err := uc.orderRepo.LockByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order == nil {
+		return nil, fmt.Errorf("order not found for payment %s", event.ChargeID)
+	}
+
+	response := &dto.PaymentCallbackResponse{
+		OrderID:       order.ID.String(),
+		PaymentStatus: string(order.PaymentStatus),
+	}
+
+	if event.Status != payment.ChargeStatusPaid && event.Status != payment.ChargeStatusExpired && event.Status != payment.ChargeStatusFailed {
+		return response, nil
+	}
+	// Callbacks are bound to the order by the charge ID every gateway
+	// authenticates or, for gateways that authenticate the whole payload, by
+	// the order ID our charges carry as their external ID. The latter covers
+	// earlier charges of the order, e.g. an invoice paid in a second tab after
+	// a retry, so their money still counts towards the order.
+	current := order.PaymentReference != nil && *order.PaymentReference == event.ChargeID
+	if !current && event.ExternalID != order.ID.String() {
+		if event.Status == payment.ChargeStatusPaid {
+			uc.logger.Warn("Payment received for a charge not bound to the order",
+				"order_id", order.ID, "gateway", gateway.Name(), "payment_reference", event.ChargeID, "amount", event.PaidAmount)
+		}
+		return response, nil
+	}
+
+	var newStatus entity.PaymentStatus
+	switch event.Status {
+	case payment.ChargeStatusPaid:
+		paidAmount, err := uc.orderRepo.RecordPayment(ctx, order.ID, event.ChargeID, event.PaidAmount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record order payment: %w", err)
+		}
+		newStatus = order.PaymentStatusFor(paidAmount)
+
+		if paidAmount.GreaterThan(order.TotalAmount) && !paidAmount.Equal(order.PaidAmount) {
+			uc.logger.Warn("Order overpaid", "order_id", order.ID, "gateway", gateway.Name(),
+				"payment_reference", event.ChargeID, "paid_amount", paidAmount, "total", order.TotalAmount)
+		}
+
+		// A further payment that still leaves part of the order due only
+		// raises the paid amount
+		if order.PaymentStatus == entity.PaymentStatusPartiallyPaid && newStatus == entity.PaymentStatusPartiallyPaid {
+			response.Applied = !paidAmount.Equal(order.PaidAmount)
+			if response.Applied {
+				uc.logger.Info("Partial payment recorded",
+					"order_id", order.ID, "gateway", gateway.Name(), "payment_reference", event.ChargeID, "paid_amount", paidAmount)
+			}
+			return response, nil
+		}
+	default:
+		// Only the charge the customer is paying with can fail the order; an
+		// earlier charge expiring after a retry changes nothing
+		if !current {
+			return response, nil
+		}
+		newStatus = entity.PaymentStatusFailed
+	}
+
+	if !order.PaymentStatus.CanTransitionTo(newStatus) {
+		return response, nil
+	}
+
+	updated, err := uc.orderRepo.UpdatePaymentIfStatus(ctx, order.ID, order.PaymentStatus, newStatus, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update order payment: %w", err)
+	}
+	if !updated {
+		// Another delivery of the callback got there first
+		return response, nil
+	}
+
+	if order.Status == entity.OrderStatusCancelled && newStatus == entity.PaymentStatusPaid {
+		uc.logger.Warn("Payment received for cancelled order", "order_id", order.ID, "order_number", order.OrderNumber)
+	}
+	uc.logger.Info("Order payment updated from callback",
+		"order_id", order.ID, "gateway", gateway.Name(), "payment_reference", event.ChargeID,
+		"from", order.PaymentStatus, "payment_status", newStatus)
+
+	response.PaymentStatus = string(newStatus)
+	response.Applied = true
+	return response, nil
+}
+
+// orderGateway returns the gateway the storefront collects the order's payment
+// method through
+func (uc *paymentUseCase) orderGateway(ctx context.Context, storefrontID uuid.UUID, order *entity.Order) (payment.PaymentGateway, error) {
+	storefront, err := uc.storefrontRepo.GetByID(ctx, storefrontID)
+	if err != nil || storefront == nil {
+		return nil, fmt.Errorf("storefront not found")
+	}
+
+	name := uc.defaultGateway
+	if order.PaymentMethod != nil {
+		if picked := storefront.Settings.PaymentGatewayFor(*order.PaymentMethod); picked != "" {
+			name = picked
+		}
+	}
+
+	gateway, ok := uc.gateways[name]
+	if !ok {
+		return nil, fmt.Errorf("failed to create payment: gateway %s is not configured", name)
+	}
+	return gateway, nil
+}
+
+// callbackOrder finds the order a callback is about. Our charges carry the
+// order ID as their external ID; the gateway reference covers callbacks that
+// do not sign it.
+func (uc *paymentUseCase) callbackOrder(ctx context.Context, event *payment.CallbackEvent) (*entity.Order, error) {
+	var (
+		order *entity.Order
+		err   error
+	)
+	if orderID, parseErr := uuid.Parse(event.ExternalID); parseErr == nil {
+		order, err = uc.orderRepo.GetByID(ctx, orderID)
+	} else if event.ChargeID != "" {
+		order, err = uc.orderRepo.GetByPaymentReference(ctx, event.ChargeID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order == nil {
+		return nil, fmt.Errorf("order not found for payment %s", event.ChargeID)
+	}
+	return order, nil
+}
+
+// canPayOrder checks that the order was placed on the storefront by the
+// customer, or for guest orders that the guest knows the order's email
+func canPayOrder(order *entity.Order, storefrontID uuid.UUID, customerID *uuid.UUID, email string) bool {
+	if order.StorefrontID == nil || *order.StorefrontID != storefrontID {
+		return false
+	}
+	if customerID != nil {
+		return order.BelongsToCustomer(*customerID)
+	}
+	return order.CustomerID == nil && order.CustomerEmail != nil && email != "" &&
+		strings.EqualFold(*order.CustomerEmail, strings.TrimSpace(email))
+}
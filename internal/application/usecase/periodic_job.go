@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// PeriodicJob runs a background sweep on a fixed interval until it is stopped
+type PeriodicJob struct {
+	name     string
+	interval time.Duration
+	timeout  time.Duration
+	run      func(ctx context.Context) (int, error)
+	logger   *slog.Logger
+}
+
+// NewPeriodicJob creates a job that calls run every interval, each call
+// bounded by timeout. run reports how many items the sweep handled.
+func NewPeriodicJob(name string, interval, timeout time.Duration, run func(ctx context.Context) (int, error), logger *slog.Logger) *PeriodicJob {
+	return &PeriodicJob{
+		name:     name,
+		interval: interval,
+		timeout:  timeout,
+		run:      run,
+		logger:   logger,
+	}
+}
+
+// Start runs the job in the background. The returned function stops it and
+// waits for a sweep in progress to finish; calling it again does nothing.
+func (j *PeriodicJob) Start() (stop func()) {
+	stopChan := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				j.sweep()
+			case <-stopChan:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stopChan)
+			<-done
+		})
+	}
+}
+
+// sweep runs the job once
+func (j *PeriodicJob) sweep() {
+	ctx, cancel := context.WithTimeout(context.Background(), j.timeout)
+	defer cancel()
+
+	count, err := j.run(ctx)
+	if err != nil {
+		j.logger.Error("Background job failed", "job", j.name, "error", err)
+		return
+	}
+	if count > 0 {
+		j.logger.Info("Background job completed", "job", j.name, "count", count)
+	}
+}
+
+// NewReservationReleaser creates the job releasing stock reserved by orders
+// that were not paid within the reservation window
+func NewReservationReleaser(checkoutUseCase CheckoutUseCase, interval time.Duration, logger *slog.Logger) *PeriodicJob {
+	if interval == 0 {
+		interval = time.Minute
+	}
+	return NewPeriodicJob("stock reservation release", interval, interval, checkoutUseCase.ReleaseExpiredReservations, logger)
+}
+
+// NewCourierWebhookRetrier creates the job retrying courier webhook deliveries
+// whose processing failed. Each sweep is bounded by the retry lease, so that
+// events are not picked up again while they are still being processed.
+func NewCourierWebhookRetrier(courierWebhookUseCase CourierWebhookUseCase, interval time.Duration, logger *slog.Logger) *PeriodicJob {
+	if interval == 0 {
+		interval = time.Minute
+	}
+	return NewPeriodicJob("courier webhook retry", interval, courierWebhookRetryLease, courierWebhookUseCase.RetryFailedEvents, logger)
+}
+
+// claimSLASweepTimeout bounds a single SLA sweep
+const claimSLASweepTimeout = time.Minute
+
+// NewClaimSLAMonitor creates the job escalating warranty claims that are at
+// risk of breaching their processing SLA
+func NewClaimSLAMonitor(claimSLAUseCase WarrantyClaimSLAUseCase, interval time.Duration, logger *slog.Logger) *PeriodicJob {
+	if interval == 0 {
+		interval = 5 * time.Minute
+	}
+	return NewPeriodicJob("claim SLA escalation", interval, claimSLASweepTimeout, claimSLAUseCase.EscalateClaims, logger)
+}
+
+// attachmentScanSweepTimeout bounds a single rescan sweep
+const attachmentScanSweepTimeout = 15 * time.Minute
+
+// NewAttachmentScanMonitor creates the job rescanning claim attachments whose
+// virus scan has not completed, such as those uploaded while clamd was down
+func NewAttachmentScanMonitor(attachmentUseCase ClaimAttachmentUseCase, interval time.Duration, logger *slog.Logger) *PeriodicJob {
+	if interval == 0 {
+		interval = 5 * time.Minute
+	}
+	return NewPeriodicJob("claim attachment rescan", interval, attachmentScanSweepTimeout, attachmentUseCase.ScanPendingAttachments, logger)
+}
@@ -21,6 +21,7 @@ type StockUpdateRequest struct {
 // StockReservationRequest represents a stock reservation request
 type StockReservationRequest struct {
 	ProductID  uuid.UUID  `json:"product_id" validate:"required"`
+	VariantID  *uuid.UUID `json:"variant_id" validate:"omitempty"`
 	Quantity   int        `json:"quantity" validate:"required,min=1"`
 	OrderID    *uuid.UUID `json:"order_id" validate:"omitempty"`
 	ReservedBy uuid.UUID  `json:"reserved_by" validate:"required"`
@@ -168,6 +169,11 @@ func (uc *ProductUseCase) ReserveStock(ctx context.Context, req StockReservation
 		return nil // No need to reserve stock for products that don't track inventory
 	}
 
+	// Variants carry their own stock
+	if req.VariantID != nil {
+		return uc.reserveVariantStock(ctx, product, req)
+	}
+
 	// Check if sufficient stock is available
 	if product.StockQuantity < req.Quantity {
 		uc.logger.Warn("Insufficient stock for reservation",
@@ -193,8 +199,50 @@ func (uc *ProductUseCase) ReserveStock(ctx context.Context, req StockReservation
 		"quantity", req.Quantity,
 		"order_id", req.OrderID)
 
-	// TODO: Store reservation record for tracking and expiration
-	// This would require a separate reservations table/repository
+	// Reservation records used for expiry are kept by the checkout use case
+
+	return nil
+}
+
+// reserveVariantStock deducts reserved stock from a product variant
+func (uc *ProductUseCase) reserveVariantStock(ctx context.Context, product *entity.Product, req StockReservationRequest) error {
+	variant, err := uc.variantRepo.GetByID(ctx, *req.VariantID, nil)
+	if err != nil {
+		uc.logger.Error("Variant not found for stock reservation",
+			"variant_id", req.VariantID,
+			"error", err)
+		return fmt.Errorf("variant not found: %w", err)
+	}
+
+	if variant == nil || variant.ProductID != product.ID {
+		return fmt.Errorf("variant not found")
+	}
+
+	if !variant.IsActive {
+		return fmt.Errorf("variant %s is not available", variant.VariantName)
+	}
+
+	if variant.StockQuantity < req.Quantity {
+		uc.logger.Warn("Insufficient variant stock for reservation",
+			"variant_id", variant.ID,
+			"available", variant.StockQuantity,
+			"requested", req.Quantity)
+		return fmt.Errorf("insufficient stock: available=%d, requested=%d", variant.StockQuantity, req.Quantity)
+	}
+
+	if err := uc.variantRepo.DeductStock(ctx, variant.ID, req.Quantity); err != nil {
+		uc.logger.Error("Failed to deduct variant stock for reservation",
+			"variant_id", variant.ID,
+			"quantity", req.Quantity,
+			"error", err)
+		return fmt.Errorf("failed to reserve stock: %w", err)
+	}
+
+	uc.logger.Info("Variant stock reserved successfully",
+		"product_id", product.ID,
+		"variant_id", variant.ID,
+		"quantity", req.Quantity,
+		"order_id", req.OrderID)
 
 	return nil
 }
@@ -244,6 +292,32 @@ func (uc *ProductUseCase) ReleaseStock(ctx context.Context, productID uuid.UUID,
 	return nil
 }
 
+// ReleaseVariantStock releases reserved stock of a product variant
+func (uc *ProductUseCase) ReleaseVariantStock(ctx context.Context, variantID uuid.UUID, quantity int, reason string) error {
+	if variantID == uuid.Nil {
+		return fmt.Errorf("variant ID cannot be empty")
+	}
+
+	if quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+
+	if err := uc.variantRepo.RestockInventory(ctx, variantID, quantity); err != nil {
+		uc.logger.Error("Failed to release variant stock",
+			"variant_id", variantID,
+			"quantity", quantity,
+			"error", err)
+		return fmt.Errorf("failed to release stock: %w", err)
+	}
+
+	uc.logger.Info("Variant stock released successfully",
+		"variant_id", variantID,
+		"quantity", quantity,
+		"reason", reason)
+
+	return nil
+}
+
 // BulkStockUpdateRequest represents a bulk stock update request
 type BulkStockUpdateRequest struct {
 	Updates []StockUpdateRequest `json:"updates" validate:"required,min=1,dive"`
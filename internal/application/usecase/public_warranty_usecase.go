@@ -0,0 +1,308 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	domainerrors "github.com/kirimku/smartseller-backend/internal/domain/errors"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+)
+
+// publicWarrantyLookupLimit caps the warranties a customer lookup returns
+const publicWarrantyLookupLimit = 50
+
+// PublicWarrantyUseCase answers warranty questions from customers holding a
+// barcode, without authentication. A nil storefront ID searches every
+// storefront; otherwise barcodes of other storefronts are not found. Customer
+// data in the output is always masked.
+type PublicWarrantyUseCase interface {
+	// ValidateWarranty validates a barcode, optionally against the product SKU it was issued for
+	ValidateWarranty(ctx context.Context, storefrontID *uuid.UUID, req *dto.PublicWarrantyValidationRequest) (*dto.PublicWarrantyValidationResponse, error)
+
+	// GetWarranty retrieves the warranty behind a barcode
+	GetWarranty(ctx context.Context, storefrontID *uuid.UUID, barcodeNumber string) (*dto.PublicWarrantyValidationResponse, error)
+
+	// LookupWarranties retrieves the warranties a customer registered for a product
+	LookupWarranties(ctx context.Context, storefrontID *uuid.UUID, req *dto.PublicWarrantyLookupRequest) (*dto.PublicWarrantyLookupResponse, error)
+
+	// GetProductInfo retrieves a product by barcode, ID or SKU
+	GetProductInfo(ctx context.Context, storefrontID *uuid.UUID, req *dto.PublicProductInfoRequest) (*dto.PublicProductInfoResponse, error)
+
+	// GetProductByBarcode retrieves the product a barcode was issued for
+	GetProductByBarcode(ctx context.Context, storefrontID *uuid.UUID, barcodeNumber string) (*dto.PublicProductInfoResponse, error)
+
+	// CheckCoverage checks whether a barcode's warranty covers an issue
+	CheckCoverage(ctx context.Context, storefrontID *uuid.UUID, req *dto.PublicWarrantyCoverageCheckRequest) (*dto.PublicWarrantyCoverageCheckResponse, error)
+}
+
+// publicWarrantyUseCase implements the PublicWarrantyUseCase interface
+type publicWarrantyUseCase struct {
+	barcodeRepo  repository.WarrantyBarcodeRepository
+	productRepo  repository.ProductRepository
+	customerRepo repository.CustomerRepository
+	logger       *slog.Logger
+}
+
+// NewPublicWarrantyUseCase creates a new public warranty use case
+func NewPublicWarrantyUseCase(
+	barcodeRepo repository.WarrantyBarcodeRepository,
+	productRepo repository.ProductRepository,
+	customerRepo repository.CustomerRepository,
+	logger *slog.Logger,
+) PublicWarrantyUseCase {
+	return &publicWarrantyUseCase{
+		barcodeRepo:  barcodeRepo,
+		productRepo:  productRepo,
+		customerRepo: customerRepo,
+		logger:       logger,
+	}
+}
+
+// ValidateWarranty validates a barcode, optionally against the product SKU it was issued for
+func (uc *publicWarrantyUseCase) ValidateWarranty(ctx context.Context, storefrontID *uuid.UUID, req *dto.PublicWarrantyValidationRequest) (*dto.PublicWarrantyValidationResponse, error) {
+	barcode, err := uc.findBarcode(ctx, storefrontID, req.BarcodeValue)
+	if err != nil {
+		return nil, err
+	}
+
+	product := uc.getProduct(ctx, barcode.ProductID)
+	// A barcode checked against another product is not confirmed to exist
+	if sku := strings.TrimSpace(req.ProductSKU); sku != "" && (product == nil || !strings.EqualFold(product.SKU, sku)) {
+		return nil, fmt.Errorf("warranty not found")
+	}
+
+	return dto.ConvertWarrantyBarcodeToPublicValidationResponse(barcode, product, uc.getOwner(ctx, barcode)), nil
+}
+
+// GetWarranty retrieves the warranty behind a barcode
+func (uc *publicWarrantyUseCase) GetWarranty(ctx context.Context, storefrontID *uuid.UUID, barcodeNumber string) (*dto.PublicWarrantyValidationResponse, error) {
+	barcode, err := uc.findBarcode(ctx, storefrontID, barcodeNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ConvertWarrantyBarcodeToPublicValidationResponse(barcode, uc.getProduct(ctx, barcode.ProductID), uc.getOwner(ctx, barcode)), nil
+}
+
+// LookupWarranties retrieves the warranties a customer registered for a
+// product. Customers are per storefront, so the lookup needs one.
+func (uc *publicWarrantyUseCase) LookupWarranties(ctx context.Context, storefrontID *uuid.UUID, req *dto.PublicWarrantyLookupRequest) (*dto.PublicWarrantyLookupResponse, error) {
+	if storefrontID == nil {
+		return nil, fmt.Errorf("storefront is required for warranty lookups")
+	}
+	sku := strings.TrimSpace(req.ProductSKU)
+	if sku == "" {
+		return nil, fmt.Errorf("product SKU is required")
+	}
+	email := strings.ToLower(strings.TrimSpace(req.CustomerEmail))
+	if email == "" {
+		return nil, fmt.Errorf("customer email is required")
+	}
+	var purchaseDate *time.Time
+	if req.PurchaseDate != "" {
+		date, err := time.Parse("2006-01-02", req.PurchaseDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid purchase date: must be YYYY-MM-DD")
+		}
+		purchaseDate = &date
+	}
+
+	customer, err := uc.customerRepo.GetByEmail(ctx, *storefrontID, email)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrCustomerNotFound) {
+			return nil, fmt.Errorf("warranty not found")
+		}
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	barcodes, err := uc.barcodeRepo.GetWithFilters(ctx, &repository.WarrantyBarcodeFilters{
+		StorefrontID: storefrontID,
+		CustomerID:   &customer.ID,
+		PageSize:     publicWarrantyLookupLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warranties: %w", err)
+	}
+
+	productIDs := make([]uuid.UUID, 0, len(barcodes))
+	for _, barcode := range barcodes {
+		productIDs = append(productIDs, barcode.ProductID)
+	}
+	products := make(map[uuid.UUID]*entity.Product)
+	if len(productIDs) > 0 {
+		found, err := uc.productRepo.GetByIDs(ctx, productIDs, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get products: %w", err)
+		}
+		for _, product := range found {
+			products[product.ID] = product
+		}
+	}
+
+	var product *entity.Product
+	matches := make([]*entity.WarrantyBarcode, 0, len(barcodes))
+	for _, barcode := range barcodes {
+		p := products[barcode.ProductID]
+		if p == nil || !strings.EqualFold(p.SKU, sku) {
+			continue
+		}
+		if purchaseDate != nil && (barcode.PurchaseDate == nil || barcode.PurchaseDate.Format("2006-01-02") != req.PurchaseDate) {
+			continue
+		}
+		product = p
+		matches = append(matches, barcode)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("warranty not found")
+	}
+
+	return dto.ConvertWarrantyBarcodesToPublicLookupResponse(matches, product), nil
+}
+
+// GetProductInfo retrieves a product by barcode, ID or SKU. Products looked
+// up without a barcode must be active.
+func (uc *publicWarrantyUseCase) GetProductInfo(ctx context.Context, storefrontID *uuid.UUID, req *dto.PublicProductInfoRequest) (*dto.PublicProductInfoResponse, error) {
+	if req.BarcodeValue != "" {
+		return uc.GetProductByBarcode(ctx, storefrontID, req.BarcodeValue)
+	}
+
+	var product *entity.Product
+	switch {
+	case req.ProductID != "":
+		productID, err := uuid.Parse(req.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid product ID: %w", err)
+		}
+		found, err := uc.productRepo.GetByIDs(ctx, []uuid.UUID{productID}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get product: %w", err)
+		}
+		if len(found) > 0 {
+			product = found[0]
+		}
+	case strings.TrimSpace(req.ProductSKU) != "":
+		// The repository reports an unknown SKU as an error as well
+		found, err := uc.productRepo.GetBySKU(ctx, strings.TrimSpace(req.ProductSKU), nil)
+		if err != nil {
+			uc.logger.Debug("Product SKU lookup failed", slog.String("sku", req.ProductSKU), slog.String("error", err.Error()))
+		}
+		product = found
+	default:
+		return nil, fmt.Errorf("barcode value, product ID or product SKU is required")
+	}
+
+	if product == nil || product.Status != entity.ProductStatusActive {
+		return nil, fmt.Errorf("product not found")
+	}
+
+	return dto.ConvertProductToPublicInfoResponse(product, nil), nil
+}
+
+// GetProductByBarcode retrieves the product a barcode was issued for
+func (uc *publicWarrantyUseCase) GetProductByBarcode(ctx context.Context, storefrontID *uuid.UUID, barcodeNumber string) (*dto.PublicProductInfoResponse, error) {
+	barcode, err := uc.findBarcode(ctx, storefrontID, barcodeNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	product := uc.getProduct(ctx, barcode.ProductID)
+	if product == nil {
+		return nil, fmt.Errorf("product not found")
+	}
+
+	return dto.ConvertProductToPublicInfoResponse(product, barcode), nil
+}
+
+// CheckCoverage checks whether a barcode's warranty covers an issue. Only
+// an activated warranty before its expiry covers anything, and never the
+// excluded issue types.
+func (uc *publicWarrantyUseCase) CheckCoverage(ctx context.Context, storefrontID *uuid.UUID, req *dto.PublicWarrantyCoverageCheckRequest) (*dto.PublicWarrantyCoverageCheckResponse, error) {
+	issueType := strings.ToLower(strings.TrimSpace(req.IssueType))
+	if issueType == "" {
+		return nil, fmt.Errorf("issue type is required")
+	}
+
+	barcode, err := uc.findBarcode(ctx, storefrontID, req.BarcodeValue)
+	if err != nil {
+		return nil, err
+	}
+
+	covered, reason := true, ""
+	switch {
+	case !barcode.IsValidForClaim():
+		covered, reason = false, dto.PublicWarrantyStatusMessage(barcode)
+		if barcode.ExpiryDate != nil && barcode.Status == entity.BarcodeStatusExpired {
+			reason = fmt.Sprintf("Warranty expired on %s", barcode.ExpiryDate.Format("2006-01-02"))
+		}
+	case isExcludedIssue(issueType):
+		covered, reason = false, "This issue is not covered under your warranty"
+	}
+
+	return dto.ConvertToCoverageCheckResponse(barcode, req.IssueType, req.IssueCategory, req.Description, covered, reason), nil
+}
+
+// findBarcode retrieves a barcode of the storefront, if any, by its number
+func (uc *publicWarrantyUseCase) findBarcode(ctx context.Context, storefrontID *uuid.UUID, barcodeNumber string) (*entity.WarrantyBarcode, error) {
+	barcodeNumber = strings.ToUpper(strings.TrimSpace(barcodeNumber))
+	if barcodeNumber == "" {
+		return nil, fmt.Errorf("barcode value is required")
+	}
+
+	barcode, err := uc.barcodeRepo.GetByBarcodeNumber(ctx, barcodeNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warranty barcode: %w", err)
+	}
+	if barcode == nil || (storefrontID != nil && barcode.StorefrontID != *storefrontID) {
+		return nil, fmt.Errorf("warranty not found")
+	}
+
+	return barcode, nil
+}
+
+// getProduct retrieves the product of a barcode. The warranty is still
+// answered when its product is gone, so failures only leave it out.
+func (uc *publicWarrantyUseCase) getProduct(ctx context.Context, productID uuid.UUID) *entity.Product {
+	products, err := uc.productRepo.GetByIDs(ctx, []uuid.UUID{productID}, nil)
+	if err != nil {
+		uc.logger.Warn("Failed to get warranty product", slog.String("product_id", productID.String()), slog.String("error", err.Error()))
+		return nil
+	}
+	if len(products) == 0 {
+		return nil
+	}
+	return products[0]
+}
+
+// getOwner retrieves the customer a barcode is registered to, if any
+func (uc *publicWarrantyUseCase) getOwner(ctx context.Context, barcode *entity.WarrantyBarcode) *entity.Customer {
+	if barcode.CustomerID == nil {
+		return nil
+	}
+
+	customer, err := uc.customerRepo.GetByID(ctx, barcode.StorefrontID, *barcode.CustomerID)
+	if err != nil {
+		if !errors.Is(err, domainerrors.ErrCustomerNotFound) {
+			uc.logger.Warn("Failed to get warranty owner", slog.String("barcode_id", barcode.ID.String()), slog.String("error", err.Error()))
+		}
+		return nil
+	}
+	return customer
+}
+
+// isExcludedIssue reports whether no warranty covers an issue type
+func isExcludedIssue(issueType string) bool {
+	for _, excluded := range dto.PublicWarrantyExcludedIssues {
+		if issueType == excluded {
+			return true
+		}
+	}
+	return false
+}
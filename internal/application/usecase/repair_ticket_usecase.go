@@ -0,0 +1,1077 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/database"
+)
+
+// RepairTicketUseCase runs the repair workflow of warranty claims. Each step
+// moves the claim along with its repair ticket: creating a ticket assigns the
+// claim, starting it puts the claim in repair, and a repair that passes its
+// quality check completes the claim's repair with the ticket's labor and
+// parts cost. Parts from the spare parts inventory are reserved when the
+// repair starts and consumed when it completes.
+type RepairTicketUseCase interface {
+	// CreateRepairTicket opens a repair ticket for a storefront's claim
+	CreateRepairTicket(ctx context.Context, storefrontID, claimID, createdBy uuid.UUID, req *dto.RepairTicketCreateRequest) (*dto.RepairTicketResponse, error)
+
+	// ListClaimRepairTickets lists the repair tickets of a storefront's claim
+	ListClaimRepairTickets(ctx context.Context, storefrontID, claimID uuid.UUID) ([]dto.RepairTicketResponse, error)
+
+	// ListRepairTickets retrieves a page of a storefront's repair tickets with filters
+	ListRepairTickets(ctx context.Context, filters *repository.RepairTicketFilters) (*dto.RepairTicketListResponse, error)
+
+	// GetRepairTicket retrieves a repair ticket of a storefront
+	GetRepairTicket(ctx context.Context, storefrontID, ticketID uuid.UUID) (*dto.RepairTicketResponse, error)
+
+	// UpdateRepairTicket updates the work order of a repair ticket
+	UpdateRepairTicket(ctx context.Context, storefrontID, ticketID uuid.UUID, req *dto.RepairTicketUpdateRequest) (*dto.RepairTicketResponse, error)
+
+	// AssignTechnician hands a repair that has not started to another technician
+	AssignTechnician(ctx context.Context, storefrontID, ticketID, assignedBy uuid.UUID, req *dto.RepairTicketAssignmentRequest) (*dto.RepairTicketResponse, error)
+
+	// StartRepair records the diagnosis and starts the repair work
+	StartRepair(ctx context.Context, storefrontID, ticketID, startedBy uuid.UUID, req *dto.RepairTicketStartRequest) (*dto.RepairTicketResponse, error)
+
+	// AddPartUsage records a part bought outside the spare parts inventory
+	AddPartUsage(ctx context.Context, storefrontID, ticketID uuid.UUID, req *dto.RepairTicketPartRequest) (*dto.RepairTicketResponse, error)
+
+	// RequestSparePart requests a part from the spare parts inventory
+	RequestSparePart(ctx context.Context, storefrontID, ticketID uuid.UUID, req *dto.RepairTicketSparePartRequest) (*dto.RepairTicketResponse, error)
+
+	// MarkWaitingForParts pauses the repair until parts arrive
+	MarkWaitingForParts(ctx context.Context, storefrontID, ticketID uuid.UUID, req *dto.RepairTicketWaitingPartsRequest) (*dto.RepairTicketResponse, error)
+
+	// ResumeRepair resumes a repair that was waiting for parts
+	ResumeRepair(ctx context.Context, storefrontID, ticketID uuid.UUID) (*dto.RepairTicketResponse, error)
+
+	// CompleteRepair records the work done and the quality check result
+	CompleteRepair(ctx context.Context, storefrontID, ticketID, completedBy uuid.UUID, req *dto.RepairTicketCompletionRequest) (*dto.RepairTicketResponse, error)
+
+	// QualityCheck records a supervisor's quality check of a completed repair
+	QualityCheck(ctx context.Context, storefrontID, ticketID, checkedBy uuid.UUID, req *dto.RepairTicketQualityCheckRequest) (*dto.RepairTicketResponse, error)
+
+	// CancelRepairTicket cancels a repair ticket that has not completed
+	CancelRepairTicket(ctx context.Context, storefrontID, ticketID uuid.UUID, req *dto.RepairTicketCancelRequest) (*dto.RepairTicketResponse, error)
+
+	// GetRepairStatistics aggregates turnaround, cost and quality check pass
+	// rate, overall and per technician
+	GetRepairStatistics(ctx context.Context, storefrontID uuid.UUID, startDate, endDate *time.Time) (*dto.RepairTicketStatisticsResponse, error)
+}
+
+// repairTicketUseCase implements the RepairTicketUseCase interface
+type repairTicketUseCase struct {
+	db          *sqlx.DB
+	claimRepo   repository.WarrantyClaimRepository
+	partRepo    repository.SparePartRepository
+	technicians TechnicianUseCase
+	logger      *slog.Logger
+}
+
+// NewRepairTicketUseCase creates a new repair ticket use case
+func NewRepairTicketUseCase(
+	db *sqlx.DB,
+	claimRepo repository.WarrantyClaimRepository,
+	partRepo repository.SparePartRepository,
+	technicians TechnicianUseCase,
+	logger *slog.Logger,
+) RepairTicketUseCase {
+	return &repairTicketUseCase{
+		db:          db,
+		claimRepo:   claimRepo,
+		partRepo:    partRepo,
+		technicians: technicians,
+		logger:      logger,
+	}
+}
+
+// CreateRepairTicket opens a repair ticket for a storefront's claim. The
+// technician defaults to the one assigned to the claim, or else to the one
+// the assignment strategy picks, and a validated claim is assigned to the
+// ticket's technician.
+func (uc *repairTicketUseCase) CreateRepairTicket(ctx context.Context, storefrontID, claimID, createdBy uuid.UUID, req *dto.RepairTicketCreateRequest) (*dto.RepairTicketResponse, error) {
+	claim, err := uc.getClaim(ctx, storefrontID, claimID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch claim.Status {
+	case entity.ClaimStatusValidated, entity.ClaimStatusAssigned, entity.ClaimStatusInRepair:
+	default:
+		return nil, fmt.Errorf("invalid claim status for a repair ticket: %s", claim.Status)
+	}
+
+	tickets, err := uc.claimRepo.GetRepairTickets(ctx, claimID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repair tickets: %w", err)
+	}
+	for _, ticket := range tickets {
+		if !isRepairTicketClosed(ticket) {
+			return nil, fmt.Errorf("invalid request: claim already has open repair ticket %s", ticket.TicketNumber)
+		}
+	}
+
+	var technicianID uuid.UUID
+	switch {
+	case req.TechnicianID != "":
+		if technicianID, err = uc.getTechnician(ctx, storefrontID, req.TechnicianID); err != nil {
+			return nil, err
+		}
+	case claim.AssignedTechnicianID != nil:
+		technicianID = *claim.AssignedTechnicianID
+	default:
+		selected, err := uc.technicians.SelectTechnician(ctx, claim)
+		if err != nil {
+			return nil, err
+		}
+		if selected == nil {
+			return nil, fmt.Errorf("technician_id is required when the claim has no assigned technician and none is available")
+		}
+		technicianID = selected.UserID
+	}
+
+	if len(strings.TrimSpace(req.Description)) < 10 {
+		return nil, fmt.Errorf("description must be at least 10 characters")
+	}
+
+	ticket := entity.NewRepairTicket(claimID, technicianID, req.TargetCompletionDate)
+	ticket.StorefrontID = claim.StorefrontID
+	ticket.ClaimNumber = claim.ClaimNumber
+	ticket.CreatedBy = &createdBy
+	ticket.Description = &req.Description
+	ticket.EstimatedHours = req.EstimatedHours
+	ticket.HourlyRate = req.HourlyRate
+	if req.Priority != "" {
+		ticket.Priority = entity.ClaimPriority(req.Priority)
+	}
+	if len(req.RequiredParts) > 0 {
+		ticket.RequiredParts = pq.StringArray(req.RequiredParts)
+	}
+	if req.SpecialInstructions != "" {
+		ticket.SpecialInstructions = &req.SpecialInstructions
+	}
+	if err := validateRepairTicketRates(ticket); err != nil {
+		return nil, err
+	}
+	if err := ticket.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid repair ticket: %w", err)
+	}
+
+	reservations := make([]*entity.SparePartReservation, 0, len(req.SpareParts))
+	for i := range req.SpareParts {
+		reservation, err := uc.newReservation(ctx, storefrontID, ticket.ID, &req.SpareParts[i])
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	ticket.TicketNumber, err = uc.claimRepo.GenerateRepairTicketNumber(ctx, storefrontID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate repair ticket number: %w", err)
+	}
+
+	claimChanged := false
+	if claim.Status == entity.ClaimStatusValidated ||
+		(claim.Status == entity.ClaimStatusAssigned && (claim.AssignedTechnicianID == nil || *claim.AssignedTechnicianID != technicianID)) {
+		if err := claim.AssignTechnician(technicianID, createdBy, req.TargetCompletionDate); err != nil {
+			return nil, fmt.Errorf("invalid claim state: %w", err)
+		}
+		claimChanged = true
+	}
+
+	err = database.WithTransaction(ctx, uc.db, func(txCtx context.Context, _ *sqlx.Tx) error {
+		if err := uc.claimRepo.CreateRepairTicket(txCtx, ticket); err != nil {
+			return err
+		}
+		for _, reservation := range reservations {
+			if err := uc.partRepo.CreateReservation(txCtx, reservation); err != nil {
+				return err
+			}
+		}
+		if claimChanged {
+			return uc.claimRepo.Update(txCtx, claim)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repair ticket: %w", err)
+	}
+
+	uc.logger.Info("Repair ticket created",
+		slog.String("ticket_number", ticket.TicketNumber),
+		slog.String("claim_id", claimID.String()),
+		slog.String("technician_id", technicianID.String()))
+
+	return uc.reload(ctx, ticket)
+}
+
+// ListClaimRepairTickets lists the repair tickets of a storefront's claim
+func (uc *repairTicketUseCase) ListClaimRepairTickets(ctx context.Context, storefrontID, claimID uuid.UUID) ([]dto.RepairTicketResponse, error) {
+	if _, err := uc.getClaim(ctx, storefrontID, claimID); err != nil {
+		return nil, err
+	}
+
+	tickets, err := uc.claimRepo.GetRepairTickets(ctx, claimID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repair tickets: %w", err)
+	}
+
+	return dto.ConvertRepairTicketsToResponses(tickets), nil
+}
+
+// ListRepairTickets retrieves a page of a storefront's repair tickets with filters
+func (uc *repairTicketUseCase) ListRepairTickets(ctx context.Context, filters *repository.RepairTicketFilters) (*dto.RepairTicketListResponse, error) {
+	for _, status := range filters.Statuses {
+		if !status.Valid() {
+			return nil, fmt.Errorf("invalid repair status: %s", status)
+		}
+	}
+	for _, priority := range filters.Priorities {
+		if !priority.Valid() {
+			return nil, fmt.Errorf("invalid priority: %s", priority)
+		}
+	}
+
+	tickets, err := uc.claimRepo.GetRepairTicketsWithFilters(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repair tickets: %w", err)
+	}
+
+	total, err := uc.claimRepo.CountRepairTickets(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count repair tickets: %w", err)
+	}
+
+	return &dto.RepairTicketListResponse{
+		Tickets:    dto.ConvertRepairTicketsToResponses(tickets),
+		Pagination: buildOrderPagination(filters.Page, filters.PageSize, total),
+	}, nil
+}
+
+// GetRepairTicket retrieves a repair ticket of a storefront
+func (uc *repairTicketUseCase) GetRepairTicket(ctx context.Context, storefrontID, ticketID uuid.UUID) (*dto.RepairTicketResponse, error) {
+	ticket, err := uc.getTicket(ctx, storefrontID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.toResponse(ctx, ticket)
+}
+
+// UpdateRepairTicket updates the work order of a repair ticket that is still open
+func (uc *repairTicketUseCase) UpdateRepairTicket(ctx context.Context, storefrontID, ticketID uuid.UUID, req *dto.RepairTicketUpdateRequest) (*dto.RepairTicketResponse, error) {
+	ticket, err := uc.getTicket(ctx, storefrontID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if isRepairTicketClosed(ticket) {
+		return nil, fmt.Errorf("invalid request: cannot update a %s repair ticket", ticket.Status)
+	}
+
+	if req.Priority != nil {
+		ticket.Priority = entity.ClaimPriority(*req.Priority)
+	}
+	if req.EstimatedHours != nil {
+		ticket.EstimatedHours = req.EstimatedHours
+	}
+	if req.HourlyRate != nil {
+		ticket.HourlyRate = req.HourlyRate
+		ticket.CalculateLaborCost()
+	}
+	if req.TargetCompletionDate != nil {
+		ticket.TargetCompletionDate = req.TargetCompletionDate
+	}
+	if req.Description != nil {
+		if len(strings.TrimSpace(*req.Description)) < 10 {
+			return nil, fmt.Errorf("description must be at least 10 characters")
+		}
+		ticket.Description = req.Description
+	}
+	if req.RequiredParts != nil {
+		ticket.RequiredParts = pq.StringArray(req.RequiredParts)
+	}
+	if req.SpecialInstructions != nil {
+		ticket.SpecialInstructions = req.SpecialInstructions
+	}
+	if req.TechnicianNotes != nil {
+		ticket.TechnicianNotes = req.TechnicianNotes
+	}
+	if err := validateRepairTicketRates(ticket); err != nil {
+		return nil, err
+	}
+	if err := ticket.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid repair ticket: %w", err)
+	}
+
+	if err := uc.claimRepo.UpdateRepairTicket(ctx, ticket); err != nil {
+		return nil, fmt.Errorf("failed to update repair ticket: %w", err)
+	}
+
+	return uc.reload(ctx, ticket)
+}
+
+// AssignTechnician hands a repair that has not started to another technician.
+// The claim follows the ticket's technician.
+func (uc *repairTicketUseCase) AssignTechnician(ctx context.Context, storefrontID, ticketID, assignedBy uuid.UUID, req *dto.RepairTicketAssignmentRequest) (*dto.RepairTicketResponse, error) {
+	technicianID, err := uc.getTechnician(ctx, storefrontID, req.TechnicianID)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket, err := uc.getTicket(ctx, storefrontID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	claim, err := uc.getClaim(ctx, storefrontID, ticket.ClaimID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ticket.Reassign(technicianID, req.TargetCompletionDate); err != nil {
+		return nil, fmt.Errorf("invalid ticket state: %w", err)
+	}
+	if req.Notes != "" {
+		ticket.SupervisorNotes = &req.Notes
+	}
+
+	switch claim.Status {
+	case entity.ClaimStatusValidated, entity.ClaimStatusAssigned:
+		if err := claim.AssignTechnician(technicianID, assignedBy, ticket.TargetCompletionDate); err != nil {
+			return nil, fmt.Errorf("invalid claim state: %w", err)
+		}
+	default:
+		claim.AssignedTechnicianID = &technicianID
+	}
+
+	if err := uc.saveTicketAndClaim(ctx, ticket, claim, nil); err != nil {
+		return nil, err
+	}
+
+	return uc.reload(ctx, ticket)
+}
+
+// StartRepair records the diagnosis and starts the repair work, putting an
+// assigned claim in repair. The ticket's spare parts are reserved, and the
+// repair waits for parts if any is out of stock.
+func (uc *repairTicketUseCase) StartRepair(ctx context.Context, storefrontID, ticketID, startedBy uuid.UUID, req *dto.RepairTicketStartRequest) (*dto.RepairTicketResponse, error) {
+	if len(strings.TrimSpace(req.Diagnosis)) < 10 {
+		return nil, fmt.Errorf("diagnosis must be at least 10 characters")
+	}
+
+	ticket, err := uc.getTicket(ctx, storefrontID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	claim, err := uc.getClaim(ctx, storefrontID, ticket.ClaimID)
+	if err != nil {
+		return nil, err
+	}
+
+	reservations, err := uc.partRepo.GetReservationsByTicketID(ctx, ticket.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spare part reservations: %w", err)
+	}
+
+	if err := ticket.Start(req.Diagnosis); err != nil {
+		return nil, fmt.Errorf("invalid ticket state: %w", err)
+	}
+
+	// A claim is already in repair when an earlier ticket failed its quality check
+	if claim.Status != entity.ClaimStatusInRepair {
+		if err := claim.StartRepair(startedBy); err != nil {
+			return nil, fmt.Errorf("invalid claim state: %w", err)
+		}
+	}
+
+	err = uc.saveTicketAndClaim(ctx, ticket, claim, func(txCtx context.Context) error {
+		return uc.reservePartsOrWait(txCtx, ticket, reservations)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.reload(ctx, ticket)
+}
+
+// AddPartUsage records a part bought outside the spare parts inventory
+func (uc *repairTicketUseCase) AddPartUsage(ctx context.Context, storefrontID, ticketID uuid.UUID, req *dto.RepairTicketPartRequest) (*dto.RepairTicketResponse, error) {
+	if req.PartNumber == "" || req.PartName == "" {
+		return nil, fmt.Errorf("part_number and part_name are required")
+	}
+	if req.Quantity < 1 {
+		return nil, fmt.Errorf("quantity must be at least 1")
+	}
+	if req.UnitCost.IsNegative() {
+		return nil, fmt.Errorf("unit_cost must not be negative")
+	}
+
+	ticket, err := uc.getTicket(ctx, storefrontID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.Status != entity.RepairStatusInProgress && ticket.Status != entity.RepairStatusWaitingParts {
+		return nil, fmt.Errorf("invalid ticket state: can only add parts to repairs in progress, current status: %s", ticket.Status)
+	}
+
+	ticket.AddPartUsage(req.PartNumber, req.PartName, req.Quantity, req.UnitCost)
+	part := &ticket.PartsUsed[len(ticket.PartsUsed)-1]
+	part.Description = req.Description
+	part.Supplier = req.Supplier
+
+	if err := uc.claimRepo.UpdateRepairTicket(ctx, ticket); err != nil {
+		return nil, fmt.Errorf("failed to update repair ticket: %w", err)
+	}
+
+	return uc.reload(ctx, ticket)
+}
+
+// RequestSparePart requests a part from the spare parts inventory. The part is
+// reserved right away for a repair that has started, which waits for parts if
+// it is out of stock; otherwise it is reserved when the repair starts.
+func (uc *repairTicketUseCase) RequestSparePart(ctx context.Context, storefrontID, ticketID uuid.UUID, req *dto.RepairTicketSparePartRequest) (*dto.RepairTicketResponse, error) {
+	ticket, err := uc.getTicket(ctx, storefrontID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	switch ticket.Status {
+	case entity.RepairStatusAssigned, entity.RepairStatusInProgress, entity.RepairStatusWaitingParts:
+	default:
+		return nil, fmt.Errorf("invalid ticket state: cannot request parts for a %s repair", ticket.Status)
+	}
+
+	reservation, err := uc.newReservation(ctx, storefrontID, ticket.ID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.saveTicketAndClaim(ctx, ticket, nil, func(txCtx context.Context) error {
+		if err := uc.partRepo.CreateReservation(txCtx, reservation); err != nil {
+			return err
+		}
+		if ticket.Status == entity.RepairStatusAssigned {
+			return nil
+		}
+		return uc.reservePartsOrWait(txCtx, ticket, []*entity.SparePartReservation{reservation})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.reload(ctx, ticket)
+}
+
+// MarkWaitingForParts pauses the repair until parts arrive
+func (uc *repairTicketUseCase) MarkWaitingForParts(ctx context.Context, storefrontID, ticketID uuid.UUID, req *dto.RepairTicketWaitingPartsRequest) (*dto.RepairTicketResponse, error) {
+	ticket, err := uc.getTicket(ctx, storefrontID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ticket.MarkWaitingForParts(req.Notes); err != nil {
+		return nil, fmt.Errorf("invalid ticket state: %w", err)
+	}
+
+	if err := uc.claimRepo.UpdateRepairTicket(ctx, ticket); err != nil {
+		return nil, fmt.Errorf("failed to update repair ticket: %w", err)
+	}
+
+	return uc.reload(ctx, ticket)
+}
+
+// ResumeRepair resumes a repair that was waiting for parts, once every
+// spare part it needs can be reserved
+func (uc *repairTicketUseCase) ResumeRepair(ctx context.Context, storefrontID, ticketID uuid.UUID) (*dto.RepairTicketResponse, error) {
+	ticket, err := uc.getTicket(ctx, storefrontID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	reservations, err := uc.partRepo.GetReservationsByTicketID(ctx, ticket.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spare part reservations: %w", err)
+	}
+	shortages, err := uc.partShortages(ctx, reservations)
+	if err != nil {
+		return nil, err
+	}
+	if len(shortages) > 0 {
+		return nil, fmt.Errorf("invalid ticket state: parts still out of stock: %s", strings.Join(shortages, ", "))
+	}
+
+	if err := ticket.ResumeRepair(); err != nil {
+		return nil, fmt.Errorf("invalid ticket state: %w", err)
+	}
+
+	err = uc.saveTicketAndClaim(ctx, ticket, nil, func(txCtx context.Context) error {
+		shortages, err := uc.reservePendingParts(txCtx, reservations)
+		if err != nil {
+			return err
+		}
+		if len(shortages) > 0 {
+			return fmt.Errorf("parts went out of stock: %s", strings.Join(shortages, ", "))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.reload(ctx, ticket)
+}
+
+// CompleteRepair records the work done and the quality check result. The
+// reserved spare parts are consumed and recorded as parts used at their
+// inventory cost. A passed check completes the claim's repair with the
+// ticket's labor and parts cost; a failed one leaves the claim in repair for
+// another ticket.
+func (uc *repairTicketUseCase) CompleteRepair(ctx context.Context, storefrontID, ticketID, completedBy uuid.UUID, req *dto.RepairTicketCompletionRequest) (*dto.RepairTicketResponse, error) {
+	if req.QualityCheckPassed == nil {
+		return nil, fmt.Errorf("quality_check_passed is required")
+	}
+	if !req.LaborHours.IsPositive() {
+		return nil, fmt.Errorf("labor_hours must be greater than zero")
+	}
+	if len(strings.TrimSpace(req.RepairNotes)) < 10 {
+		return nil, fmt.Errorf("repair_notes must be at least 10 characters")
+	}
+	for _, test := range req.TestResults {
+		if test.TestName == "" {
+			return nil, fmt.Errorf("test_name is required for test results")
+		}
+		switch test.Result {
+		case "passed", "failed", "warning":
+		default:
+			return nil, fmt.Errorf("invalid test result: %s", test.Result)
+		}
+	}
+
+	ticket, err := uc.getTicket(ctx, storefrontID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	claim, err := uc.getClaim(ctx, storefrontID, ticket.ClaimID)
+	if err != nil {
+		return nil, err
+	}
+	if *req.QualityCheckPassed && claim.Status != entity.ClaimStatusInRepair {
+		return nil, fmt.Errorf("invalid claim state: can only complete repairs of claims in repair, current status: %s", claim.Status)
+	}
+
+	ticket.LaborHours = req.LaborHours
+	if req.HourlyRate != nil {
+		ticket.HourlyRate = req.HourlyRate
+	}
+	for _, step := range req.RepairSteps {
+		ticket.AddRepairStep(step)
+	}
+	for _, test := range req.TestResults {
+		ticket.AddTestResult(test.TestName, test.Result, test.Description, &completedBy)
+	}
+	ticket.TechnicianNotes = &req.RepairNotes
+	if err := validateRepairTicketRates(ticket); err != nil {
+		return nil, err
+	}
+
+	reservations, err := uc.partRepo.GetReservationsByTicketID(ctx, ticket.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spare part reservations: %w", err)
+	}
+	if ticket.Status == entity.RepairStatusInProgress {
+		for _, reservation := range reservations {
+			if reservation.Status != entity.SparePartReservationStatusReserved {
+				continue
+			}
+			part, err := uc.partRepo.GetByID(ctx, reservation.SparePartID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get spare part: %w", err)
+			}
+			if part == nil {
+				return nil, fmt.Errorf("spare part %s not found", reservation.PartNumber)
+			}
+			reservation.UnitCost = &part.UnitCost
+			ticket.AddPartUsage(part.PartNumber, part.Name, reservation.Quantity, part.UnitCost)
+		}
+	}
+
+	var qualityNotes *string
+	if req.QualityNotes != "" {
+		qualityNotes = &req.QualityNotes
+	}
+	if err := ticket.Complete(*req.QualityCheckPassed, qualityNotes); err != nil {
+		return nil, fmt.Errorf("invalid ticket state: %w", err)
+	}
+	ticket.QualityCheckedBy = &completedBy
+	ticket.QualityCheckedAt = ticket.ActualCompletionDate
+
+	if *req.QualityCheckPassed {
+		if err := claim.CompleteRepairFromTicket(completedBy, req.RepairNotes, ticket); err != nil {
+			return nil, fmt.Errorf("invalid claim state: %w", err)
+		}
+	} else {
+		claim = nil
+	}
+
+	err = uc.saveTicketAndClaim(ctx, ticket, claim, func(txCtx context.Context) error {
+		return uc.consumeParts(txCtx, reservations)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info("Repair ticket completed",
+		slog.String("ticket_number", ticket.TicketNumber),
+		slog.Bool("quality_check_passed", *req.QualityCheckPassed),
+		slog.String("total_cost", ticket.TotalCost.String()))
+
+	return uc.reload(ctx, ticket)
+}
+
+// QualityCheck records a supervisor's quality check of a completed repair.
+// Approving a repair that failed its first check completes the claim's repair;
+// a repair the claim was already completed with cannot be rejected.
+func (uc *repairTicketUseCase) QualityCheck(ctx context.Context, storefrontID, ticketID, checkedBy uuid.UUID, req *dto.RepairTicketQualityCheckRequest) (*dto.RepairTicketResponse, error) {
+	var passed bool
+	switch req.Action {
+	case "approve":
+		passed = true
+	case "reject":
+	default:
+		return nil, fmt.Errorf("invalid action: %s", req.Action)
+	}
+	if len(strings.TrimSpace(req.Notes)) < 10 {
+		return nil, fmt.Errorf("notes must be at least 10 characters")
+	}
+
+	ticket, err := uc.getTicket(ctx, storefrontID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	claim, err := uc.getClaim(ctx, storefrontID, ticket.ClaimID)
+	if err != nil {
+		return nil, err
+	}
+
+	previouslyPassed := ticket.QualityCheckPassed != nil && *ticket.QualityCheckPassed
+	if !passed && previouslyPassed && claim.Status != entity.ClaimStatusInRepair {
+		return nil, fmt.Errorf("invalid request: the claim was already repaired with this ticket, current status: %s", claim.Status)
+	}
+
+	if err := ticket.ReviewQualityCheck(passed, checkedBy, req.Notes); err != nil {
+		return nil, fmt.Errorf("invalid ticket state: %w", err)
+	}
+
+	if passed && claim.Status == entity.ClaimStatusInRepair {
+		if err := claim.CompleteRepairFromTicket(checkedBy, getRepairNotes(ticket), ticket); err != nil {
+			return nil, fmt.Errorf("invalid claim state: %w", err)
+		}
+	} else {
+		claim = nil
+	}
+
+	if err := uc.saveTicketAndClaim(ctx, ticket, claim, nil); err != nil {
+		return nil, err
+	}
+
+	return uc.reload(ctx, ticket)
+}
+
+// CancelRepairTicket cancels a repair ticket that has not completed, returning
+// its reserved spare parts to inventory. The claim keeps its status so another
+// ticket can be opened.
+func (uc *repairTicketUseCase) CancelRepairTicket(ctx context.Context, storefrontID, ticketID uuid.UUID, req *dto.RepairTicketCancelRequest) (*dto.RepairTicketResponse, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+
+	ticket, err := uc.getTicket(ctx, storefrontID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if isRepairTicketClosed(ticket) {
+		return nil, fmt.Errorf("invalid ticket state: cannot cancel a %s repair ticket", ticket.Status)
+	}
+
+	reservations, err := uc.partRepo.GetReservationsByTicketID(ctx, ticket.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spare part reservations: %w", err)
+	}
+
+	if err := ticket.Cancel(req.Reason); err != nil {
+		return nil, fmt.Errorf("invalid ticket state: %w", err)
+	}
+
+	err = uc.saveTicketAndClaim(ctx, ticket, nil, func(txCtx context.Context) error {
+		return uc.releaseParts(txCtx, reservations)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.reload(ctx, ticket)
+}
+
+// GetRepairStatistics aggregates turnaround, cost and quality check pass rate,
+// overall and per technician
+func (uc *repairTicketUseCase) GetRepairStatistics(ctx context.Context, storefrontID uuid.UUID, startDate, endDate *time.Time) (*dto.RepairTicketStatisticsResponse, error) {
+	// Set default date range if not provided
+	if startDate == nil {
+		start := time.Now().AddDate(0, -1, 0) // Last month
+		startDate = &start
+	}
+	if endDate == nil {
+		end := time.Now()
+		endDate = &end
+	}
+	if endDate.Before(*startDate) {
+		return nil, fmt.Errorf("invalid date range: end date is before start date")
+	}
+
+	stats, err := uc.claimRepo.GetRepairTicketStatistics(ctx, storefrontID, *startDate, *endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repair statistics: %w", err)
+	}
+
+	totalLaborCost := decimal.NewFromFloat(stats.TotalLaborCost).Round(2)
+	totalPartsCost := decimal.NewFromFloat(stats.TotalPartsCost).Round(2)
+	response := &dto.RepairTicketStatisticsResponse{
+		TotalTickets:           int(stats.TotalTickets),
+		CompletedTickets:       int(stats.CompletedTickets),
+		TicketsByStatus:        convertInt64MapToIntMap(stats.TicketsByStatus),
+		AverageTurnaroundHours: decimal.NewFromFloat(stats.AverageTurnaroundHours).Round(1),
+		TotalLaborCost:         totalLaborCost,
+		TotalPartsCost:         totalPartsCost,
+		TotalRepairCost:        totalLaborCost.Add(totalPartsCost),
+		AveragePartsCost:       decimal.Zero,
+		QualityPassRate:        percentage(stats.QualityPassedTickets, stats.QualityCheckedTickets),
+		Technicians:            make([]dto.TechnicianStatsResponse, 0, len(stats.Technicians)),
+		PeriodStart:            *startDate,
+		PeriodEnd:              *endDate,
+	}
+	if stats.CompletedTickets > 0 {
+		response.AveragePartsCost = totalPartsCost.Div(decimal.NewFromInt(stats.CompletedTickets)).Round(2)
+	}
+
+	for _, technician := range stats.Technicians {
+		response.Technicians = append(response.Technicians, dto.TechnicianStatsResponse{
+			TechnicianID:           technician.TechnicianID.String(),
+			TechnicianName:         technician.TechnicianName,
+			AssignedTickets:        int(technician.AssignedTickets),
+			CompletedTickets:       int(technician.CompletedTickets),
+			AverageTurnaroundHours: decimal.NewFromFloat(technician.AverageTurnaroundHours).Round(1),
+			TotalLaborCost:         decimal.NewFromFloat(technician.TotalLaborCost).Round(2),
+			TotalPartsCost:         decimal.NewFromFloat(technician.TotalPartsCost).Round(2),
+			QualityPassRate:        percentage(technician.QualityPassedTickets, technician.QualityCheckedTickets),
+		})
+	}
+
+	return response, nil
+}
+
+// saveTicketAndClaim stores a ticket together with the claim it moved, if any,
+// after running the ticket's stock changes in the same transaction
+func (uc *repairTicketUseCase) saveTicketAndClaim(ctx context.Context, ticket *entity.RepairTicket, claim *entity.WarrantyClaim, stock func(context.Context) error) error {
+	err := database.WithTransaction(ctx, uc.db, func(txCtx context.Context, _ *sqlx.Tx) error {
+		if stock != nil {
+			if err := stock(txCtx); err != nil {
+				return err
+			}
+		}
+		if err := uc.claimRepo.UpdateRepairTicket(txCtx, ticket); err != nil {
+			return err
+		}
+		if claim != nil {
+			return uc.claimRepo.Update(txCtx, claim)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update repair ticket: %w", err)
+	}
+	return nil
+}
+
+// reload returns the stored ticket, with the generated costs, joined names
+// and spare parts
+func (uc *repairTicketUseCase) reload(ctx context.Context, ticket *entity.RepairTicket) (*dto.RepairTicketResponse, error) {
+	stored, err := uc.claimRepo.GetRepairTicketByID(ctx, ticket.ID)
+	if err != nil || stored == nil {
+		uc.logger.Warn("Failed to reload repair ticket", slog.String("ticket_id", ticket.ID.String()))
+		ticket.ComputeFields()
+		stored = ticket
+	}
+	return uc.toResponse(ctx, stored)
+}
+
+// toResponse converts a ticket to its response with its spare parts
+func (uc *repairTicketUseCase) toResponse(ctx context.Context, ticket *entity.RepairTicket) (*dto.RepairTicketResponse, error) {
+	reservations, err := uc.partRepo.GetReservationsByTicketID(ctx, ticket.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spare part reservations: %w", err)
+	}
+
+	response := dto.ConvertRepairTicketToResponse(ticket)
+	response.SpareParts = dto.ConvertSparePartReservationsToResponses(reservations)
+	return response, nil
+}
+
+// newReservation resolves a requested spare part of the storefront to a
+// pending reservation for a ticket
+func (uc *repairTicketUseCase) newReservation(ctx context.Context, storefrontID, ticketID uuid.UUID, req *dto.RepairTicketSparePartRequest) (*entity.SparePartReservation, error) {
+	if req.Quantity < 1 {
+		return nil, fmt.Errorf("spare part quantity must be at least 1")
+	}
+
+	var part *entity.SparePart
+	var err error
+	switch {
+	case req.SparePartID != "":
+		partID, parseErr := uuid.Parse(req.SparePartID)
+		if parseErr != nil {
+			return nil, fmt.Errorf("invalid spare part ID: %w", parseErr)
+		}
+		part, err = uc.partRepo.GetByID(ctx, partID)
+	case req.PartNumber != "":
+		part, err = uc.partRepo.GetByPartNumber(ctx, storefrontID, req.PartNumber)
+	default:
+		return nil, fmt.Errorf("spare_part_id or part_number is required")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spare part: %w", err)
+	}
+	if part == nil || part.StorefrontID != storefrontID {
+		return nil, fmt.Errorf("spare part not found")
+	}
+	if !part.IsActive {
+		return nil, fmt.Errorf("invalid request: spare part %s is inactive", part.PartNumber)
+	}
+
+	return entity.NewSparePartReservation(part, ticketID, req.Quantity), nil
+}
+
+// reservePartsOrWait reserves a started repair's pending spare parts and puts
+// the repair on hold for the parts that are out of stock
+func (uc *repairTicketUseCase) reservePartsOrWait(ctx context.Context, ticket *entity.RepairTicket, reservations []*entity.SparePartReservation) error {
+	shortages, err := uc.reservePendingParts(ctx, reservations)
+	if err != nil {
+		return err
+	}
+	if len(shortages) == 0 || ticket.Status != entity.RepairStatusInProgress {
+		return nil
+	}
+
+	uc.logger.Info("Repair waiting for spare parts",
+		slog.String("ticket_number", ticket.TicketNumber),
+		slog.String("parts", strings.Join(shortages, ", ")))
+	return ticket.MarkWaitingForParts("Waiting for parts: " + strings.Join(shortages, ", "))
+}
+
+// reservePendingParts holds stock for pending reservations, returning the
+// parts that are out of stock
+func (uc *repairTicketUseCase) reservePendingParts(ctx context.Context, reservations []*entity.SparePartReservation) ([]string, error) {
+	var shortages []string
+	for _, reservation := range reservations {
+		if reservation.Status != entity.SparePartReservationStatusPending {
+			continue
+		}
+
+		reserved, err := uc.partRepo.Reserve(ctx, reservation.SparePartID, reservation.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		if !reserved {
+			shortage, err := uc.describeShortage(ctx, reservation)
+			if err != nil {
+				return nil, err
+			}
+			shortages = append(shortages, shortage)
+			continue
+		}
+
+		if err := reservation.Reserve(); err != nil {
+			return nil, err
+		}
+		if err := uc.partRepo.UpdateReservation(ctx, reservation); err != nil {
+			return nil, err
+		}
+	}
+	return shortages, nil
+}
+
+// partShortages returns the pending reservations that cannot be reserved now
+func (uc *repairTicketUseCase) partShortages(ctx context.Context, reservations []*entity.SparePartReservation) ([]string, error) {
+	var shortages []string
+	for _, reservation := range reservations {
+		if reservation.Status != entity.SparePartReservationStatusPending {
+			continue
+		}
+		part, err := uc.partRepo.GetByID(ctx, reservation.SparePartID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get spare part: %w", err)
+		}
+		if part == nil || part.QuantityAvailable() < reservation.Quantity {
+			shortage, err := uc.describeShortage(ctx, reservation)
+			if err != nil {
+				return nil, err
+			}
+			shortages = append(shortages, shortage)
+		}
+	}
+	return shortages, nil
+}
+
+// describeShortage describes a reservation that is short of stock
+func (uc *repairTicketUseCase) describeShortage(ctx context.Context, reservation *entity.SparePartReservation) (string, error) {
+	part, err := uc.partRepo.GetByID(ctx, reservation.SparePartID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get spare part: %w", err)
+	}
+	available := 0
+	if part != nil {
+		available = part.QuantityAvailable()
+	}
+	return fmt.Sprintf("%s (need %d, available %d)", reservation.PartNumber, reservation.Quantity, available), nil
+}
+
+// consumeParts takes the reserved spare parts out of stock at the unit cost
+// recorded on the reservation and gives up the parts never reserved
+func (uc *repairTicketUseCase) consumeParts(ctx context.Context, reservations []*entity.SparePartReservation) error {
+	for _, reservation := range reservations {
+		switch reservation.Status {
+		case entity.SparePartReservationStatusReserved:
+			part, err := uc.partRepo.Consume(ctx, reservation.SparePartID, reservation.Quantity)
+			if err != nil {
+				return err
+			}
+			unitCost := part.UnitCost
+			if reservation.UnitCost != nil {
+				unitCost = *reservation.UnitCost
+			}
+			if err := reservation.Consume(unitCost); err != nil {
+				return err
+			}
+			if part.NeedsReorder() {
+				logSparePartReorder(uc.logger, part)
+			}
+		case entity.SparePartReservationStatusPending:
+			if err := reservation.Release(); err != nil {
+				return err
+			}
+		default:
+			continue
+		}
+
+		if err := uc.partRepo.UpdateReservation(ctx, reservation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseParts returns the stock held by open reservations to inventory
+func (uc *repairTicketUseCase) releaseParts(ctx context.Context, reservations []*entity.SparePartReservation) error {
+	for _, reservation := range reservations {
+		if !reservation.IsOpen() {
+			continue
+		}
+		if reservation.Status == entity.SparePartReservationStatusReserved {
+			if err := uc.partRepo.Unreserve(ctx, reservation.SparePartID, reservation.Quantity); err != nil {
+				return err
+			}
+		}
+		if err := reservation.Release(); err != nil {
+			return err
+		}
+		if err := uc.partRepo.UpdateReservation(ctx, reservation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getClaim returns a claim of the storefront
+func (uc *repairTicketUseCase) getClaim(ctx context.Context, storefrontID, claimID uuid.UUID) (*entity.WarrantyClaim, error) {
+	claim, err := uc.claimRepo.GetByID(ctx, claimID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warranty claim: %w", err)
+	}
+	if claim == nil || claim.StorefrontID != storefrontID {
+		return nil, fmt.Errorf("warranty claim not found")
+	}
+	return claim, nil
+}
+
+// getTicket returns a repair ticket of the storefront
+func (uc *repairTicketUseCase) getTicket(ctx context.Context, storefrontID, ticketID uuid.UUID) (*entity.RepairTicket, error) {
+	ticket, err := uc.claimRepo.GetRepairTicketByID(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repair ticket: %w", err)
+	}
+	if ticket == nil || ticket.StorefrontID != storefrontID {
+		return nil, fmt.Errorf("repair ticket not found")
+	}
+	return ticket, nil
+}
+
+// getTechnician parses a technician ID and checks the user is an active
+// technician of the storefront
+func (uc *repairTicketUseCase) getTechnician(ctx context.Context, storefrontID uuid.UUID, id string) (uuid.UUID, error) {
+	technicianID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid technician ID: %w", err)
+	}
+
+	if err := uc.technicians.CheckAssignable(ctx, storefrontID, technicianID); err != nil {
+		return uuid.Nil, err
+	}
+	return technicianID, nil
+}
+
+// validateRepairTicketRates rejects negative estimates and rates
+func validateRepairTicketRates(ticket *entity.RepairTicket) error {
+	if ticket.EstimatedHours != nil && !ticket.EstimatedHours.IsPositive() {
+		return fmt.Errorf("estimated_hours must be greater than zero")
+	}
+	if ticket.HourlyRate != nil && ticket.HourlyRate.IsNegative() {
+		return fmt.Errorf("hourly_rate must not be negative")
+	}
+	return nil
+}
+
+// isRepairTicketClosed reports whether a ticket reached a terminal status
+func isRepairTicketClosed(ticket *entity.RepairTicket) bool {
+	switch ticket.Status {
+	case entity.RepairStatusCompleted, entity.RepairStatusFailed, entity.RepairStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// getRepairNotes returns the technician's notes on a repair
+func getRepairNotes(ticket *entity.RepairTicket) string {
+	if ticket.TechnicianNotes != nil {
+		return *ticket.TechnicianNotes
+	}
+	return ""
+}
+
+// percentage returns part as a percentage of total, rounded to one decimal
+func percentage(part, total int64) decimal.Decimal {
+	if total == 0 {
+		return decimal.Zero
+	}
+	return decimal.NewFromInt(part).Mul(decimal.NewFromInt(100)).Div(decimal.NewFromInt(total)).Round(1)
+}
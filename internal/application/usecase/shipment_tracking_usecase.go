@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/database"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/webhook"
+)
+
+// ShipmentTrackingUseCase defines the interface for the shipment tracking store
+type ShipmentTrackingUseCase interface {
+	// RecordUpdate stores a courier tracking update in the shipment history and
+	// links the shipment to the order or warranty claim it carries
+	RecordUpdate(ctx context.Context, update *webhook.TrackingUpdate, orderID, claimID *uuid.UUID) (*entity.Shipment, error)
+
+	// GetClaimTracking returns the shipment timeline of a customer's warranty claim
+	GetClaimTracking(ctx context.Context, customerID, claimID uuid.UUID) (*model.TrackingInfo, error)
+}
+
+// shipmentTrackingUseCase implements the ShipmentTrackingUseCase interface
+type shipmentTrackingUseCase struct {
+	db           *sqlx.DB
+	shipmentRepo repository.ShipmentRepository
+	claimRepo    repository.WarrantyClaimRepository
+	logger       *slog.Logger
+}
+
+// NewShipmentTrackingUseCase creates a new shipment tracking use case
+func NewShipmentTrackingUseCase(
+	db *sqlx.DB,
+	shipmentRepo repository.ShipmentRepository,
+	claimRepo repository.WarrantyClaimRepository,
+	logger *slog.Logger,
+) ShipmentTrackingUseCase {
+	return &shipmentTrackingUseCase{
+		db:           db,
+		shipmentRepo: shipmentRepo,
+		claimRepo:    claimRepo,
+		logger:       logger,
+	}
+}
+
+// RecordUpdate stores a courier tracking update in the shipment history.
+// Events the courier resends are recorded once.
+func (uc *shipmentTrackingUseCase) RecordUpdate(ctx context.Context, update *webhook.TrackingUpdate, orderID, claimID *uuid.UUID) (*entity.Shipment, error) {
+	if update.CourierCode == "" || update.TrackingNumber == "" {
+		return nil, fmt.Errorf("courier code and tracking number are required")
+	}
+
+	var shipment *entity.Shipment
+	err := database.WithTransaction(ctx, uc.db, func(txCtx context.Context, _ *sqlx.Tx) error {
+		var err error
+		shipment, err = uc.getOrCreateShipment(txCtx, update.CourierCode, update.TrackingNumber)
+		if err != nil {
+			return err
+		}
+
+		changed := false
+		if orderID != nil && (shipment.OrderID == nil || *shipment.OrderID != *orderID) {
+			shipment.OrderID = orderID
+			changed = true
+		}
+		if claimID != nil && (shipment.WarrantyClaimID == nil || *shipment.WarrantyClaimID != *claimID) {
+			shipment.WarrantyClaimID = claimID
+			changed = true
+		}
+
+		status := entity.ShipmentStatus(update.Status)
+		if !status.Valid() {
+			status = entity.ShipmentStatusUnknown
+		}
+		occurredAt := update.Timestamp
+		if occurredAt.IsZero() {
+			occurredAt = time.Now()
+		}
+
+		event := entity.NewShipmentTrackingEvent(shipment.ID, status, update.RawStatus, update.StatusText, update.Location, occurredAt)
+		inserted, err := uc.shipmentRepo.AddEvent(txCtx, event)
+		if err != nil {
+			return err
+		}
+		if inserted && shipment.ApplyEvent(event) {
+			changed = true
+		}
+
+		if !changed {
+			return nil
+		}
+		shipment.UpdatedAt = time.Now()
+		return uc.shipmentRepo.Update(txCtx, shipment)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record tracking update for %s: %w", update.TrackingNumber, err)
+	}
+
+	return shipment, nil
+}
+
+// getOrCreateShipment loads the shipment for a tracking number, creating it
+// on the first event. Another delivery may create it concurrently, so the
+// row is read back after the insert.
+func (uc *shipmentTrackingUseCase) getOrCreateShipment(ctx context.Context, courierCode, trackingNumber string) (*entity.Shipment, error) {
+	shipment, err := uc.shipmentRepo.GetByTrackingNumber(ctx, courierCode, trackingNumber)
+	if err != nil || shipment != nil {
+		return shipment, err
+	}
+
+	if err := uc.shipmentRepo.Create(ctx, entity.NewShipment(courierCode, trackingNumber)); err != nil {
+		return nil, err
+	}
+
+	shipment, err = uc.shipmentRepo.GetByTrackingNumber(ctx, courierCode, trackingNumber)
+	if err != nil {
+		return nil, err
+	}
+	if shipment == nil {
+		return nil, fmt.Errorf("shipment %s was not created", trackingNumber)
+	}
+	return shipment, nil
+}
+
+// GetClaimTracking returns the shipment timeline of a customer's warranty claim
+func (uc *shipmentTrackingUseCase) GetClaimTracking(ctx context.Context, customerID, claimID uuid.UUID) (*model.TrackingInfo, error) {
+	claim, err := uc.claimRepo.GetByID(ctx, claimID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warranty claim: %w", err)
+	}
+	if claim == nil || claim.CustomerID != customerID {
+		return nil, fmt.Errorf("warranty claim not found")
+	}
+
+	shipment, err := uc.shipmentRepo.GetByWarrantyClaimID(ctx, claimID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipment: %w", err)
+	}
+	if shipment == nil {
+		return nil, fmt.Errorf("shipment not found for this claim")
+	}
+
+	events, err := uc.shipmentRepo.GetEvents(ctx, shipment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipment events: %w", err)
+	}
+	shipment.Events = events
+
+	info := shipmentTrackingInfo(shipment)
+	info.EstimatedDelivery = claim.EstimatedDeliveryDate
+	return info, nil
+}
+
+// shipmentTrackingInfo converts a shipment and its events to tracking info
+// with the newest event first
+func shipmentTrackingInfo(shipment *entity.Shipment) *model.TrackingInfo {
+	info := &model.TrackingInfo{
+		TrackingNumber: shipment.TrackingNumber,
+		Status:         shipment.Status.String(),
+		Courier:        shipment.CourierCode,
+		ActualDelivery: shipment.DeliveredAt,
+		History:        make([]model.TrackingStep, 0, len(shipment.Events)),
+	}
+	if shipment.StatusText != nil {
+		info.StatusText = *shipment.StatusText
+	}
+	if shipment.Location != nil {
+		info.Location = *shipment.Location
+	}
+	if shipment.LastEventAt != nil {
+		info.LastUpdate = *shipment.LastEventAt
+	} else {
+		info.LastUpdate = shipment.UpdatedAt
+	}
+
+	for i := len(shipment.Events) - 1; i >= 0; i-- {
+		event := shipment.Events[i]
+		step := model.TrackingStep{
+			Timestamp: event.OccurredAt,
+			Status:    event.Status.String(),
+		}
+		if event.Location != nil {
+			step.Location = *event.Location
+		}
+		if event.Description != nil {
+			step.Description = *event.Description
+		}
+		info.History = append(info.History, step)
+	}
+
+	return info
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/courier"
+)
+
+// ShippingRateUseCase defines the interface for quoting shipping options at checkout
+type ShippingRateUseCase interface {
+	// QuoteRates weighs the cart and quotes every courier from the storefront's
+	// shipping origin to the address, cheapest option first. Couriers that fail
+	// are left out as long as another one answers.
+	QuoteRates(ctx context.Context, storefrontID uuid.UUID, req *dto.ShippingRateRequest) (*dto.ShippingRateResponse, error)
+}
+
+// shippingRateUseCase implements the ShippingRateUseCase interface
+type shippingRateUseCase struct {
+	storefrontRepo repository.StorefrontRepository
+	productRepo    repository.ProductRepository
+	variantRepo    repository.ProductVariantRepository
+	quoters        []courier.RateQuoter
+	logger         *slog.Logger
+}
+
+// NewShippingRateUseCase creates a new shipping rate use case
+func NewShippingRateUseCase(
+	storefrontRepo repository.StorefrontRepository,
+	productRepo repository.ProductRepository,
+	variantRepo repository.ProductVariantRepository,
+	quoters []courier.RateQuoter,
+	logger *slog.Logger,
+) ShippingRateUseCase {
+	return &shippingRateUseCase{
+		storefrontRepo: storefrontRepo,
+		productRepo:    productRepo,
+		variantRepo:    variantRepo,
+		quoters:        quoters,
+		logger:         logger,
+	}
+}
+
+// QuoteRates quotes the shipping options of a cart to an address
+func (uc *shippingRateUseCase) QuoteRates(ctx context.Context, storefrontID uuid.UUID, req *dto.ShippingRateRequest) (*dto.ShippingRateResponse, error) {
+	storefront, err := uc.storefrontRepo.GetByID(ctx, storefrontID)
+	if err != nil || storefront == nil {
+		return nil, fmt.Errorf("storefront not found")
+	}
+	if !storefront.IsActive() {
+		return nil, fmt.Errorf("storefront is not accepting orders")
+	}
+
+	origin := storefront.Settings.ShippingOrigin
+	if origin.District == "" {
+		return nil, fmt.Errorf("storefront shipping origin is not configured")
+	}
+
+	weight, err := uc.cartWeight(ctx, storefront, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	rateReq := &courier.RateRequest{
+		Origin:      courier.Area{Province: origin.Province, City: origin.City, District: origin.District},
+		Destination: courier.Area{Province: req.Province, City: req.City, District: req.District},
+		Weight:      weight,
+	}
+
+	rates, err := uc.quoteAll(ctx, rateReq)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(rates, func(i, j int) bool {
+		if rates[i].Price != rates[j].Price {
+			return rates[i].Price < rates[j].Price
+		}
+		if rates[i].CourierID != rates[j].CourierID {
+			return rates[i].CourierID < rates[j].CourierID
+		}
+		return rates[i].ServiceID < rates[j].ServiceID
+	})
+
+	return &dto.ShippingRateResponse{Weight: weight, Rates: rates}, nil
+}
+
+// quoteAll asks every courier at once and merges their rates
+func (uc *shippingRateUseCase) quoteAll(ctx context.Context, req *courier.RateRequest) ([]model.ShippingRate, error) {
+	results := make([][]model.ShippingRate, len(uc.quoters))
+	errs := make([]error, len(uc.quoters))
+
+	var wg sync.WaitGroup
+	for i, quoter := range uc.quoters {
+		wg.Add(1)
+		go func(i int, quoter courier.RateQuoter) {
+			defer wg.Done()
+			results[i], errs[i] = quoter.QuoteRates(ctx, req)
+		}(i, quoter)
+	}
+	wg.Wait()
+
+	var (
+		rates  []model.ShippingRate
+		failed int
+	)
+	for i, quoter := range uc.quoters {
+		switch {
+		case errors.Is(errs[i], courier.ErrAreaNotCovered):
+			uc.logger.Debug("Courier does not serve route", "courier", quoter.CourierID(), "error", errs[i])
+		case errs[i] != nil:
+			failed++
+			uc.logger.Warn("Failed to quote shipping rates", "courier", quoter.CourierID(), "error", errs[i])
+		default:
+			rates = append(rates, results[i]...)
+		}
+	}
+
+	if len(rates) == 0 {
+		if failed > 0 {
+			return nil, fmt.Errorf("failed to quote shipping rates")
+		}
+		return nil, fmt.Errorf("shipping is not available to this address")
+	}
+	return rates, nil
+}
+
+// cartWeight sums the weight of the cart's items in kilograms. Variants with
+// their own weight override the product's; items without one weigh nothing.
+func (uc *shippingRateUseCase) cartWeight(ctx context.Context, storefront *entity.Storefront, items []dto.CheckoutItemRequest) (decimal.Decimal, error) {
+	if len(items) == 0 {
+		return decimal.Zero, fmt.Errorf("at least one item is required")
+	}
+
+	total := decimal.Zero
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return decimal.Zero, fmt.Errorf("quantity must be positive")
+		}
+
+		productID, variantID, err := parseProductRef(item.ProductID, item.VariantID)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		product, variant, err := loadStorefrontProduct(ctx, uc.productRepo, uc.variantRepo, storefront.SellerID, productID, variantID)
+		if err != nil {
+			return decimal.Zero, err
+		}
+
+		weight := product.Weight
+		if variant != nil && variant.Weight != nil {
+			weight = variant.Weight
+		}
+		if weight != nil {
+			total = total.Add(weight.Mul(decimal.NewFromInt(int64(item.Quantity))))
+		}
+	}
+
+	return total, nil
+}
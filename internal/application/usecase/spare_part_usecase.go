@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+)
+
+// SparePartUseCase manages the spare parts inventory of a storefront's
+// service centre. Repair tickets reserve and consume the stock.
+type SparePartUseCase interface {
+	// CreateSparePart adds a part to the inventory
+	CreateSparePart(ctx context.Context, storefrontID uuid.UUID, req *dto.SparePartCreateRequest) (*dto.SparePartResponse, error)
+
+	// ListSpareParts retrieves a page of spare parts with filters
+	ListSpareParts(ctx context.Context, filters *repository.SparePartFilters) (*dto.SparePartListResponse, error)
+
+	// GetSparePart retrieves a spare part of a storefront
+	GetSparePart(ctx context.Context, storefrontID, partID uuid.UUID) (*dto.SparePartResponse, error)
+
+	// UpdateSparePart updates the catalogue details of a spare part
+	UpdateSparePart(ctx context.Context, storefrontID, partID uuid.UUID, req *dto.SparePartUpdateRequest) (*dto.SparePartResponse, error)
+
+	// DeleteSparePart removes a spare part that no repair holds stock of
+	DeleteSparePart(ctx context.Context, storefrontID, partID uuid.UUID) error
+
+	// AdjustStock records stock received or written off
+	AdjustStock(ctx context.Context, storefrontID, partID, adjustedBy uuid.UUID, req *dto.SparePartStockAdjustmentRequest) (*dto.SparePartResponse, error)
+}
+
+// sparePartUseCase implements the SparePartUseCase interface
+type sparePartUseCase struct {
+	partRepo repository.SparePartRepository
+	logger   *slog.Logger
+}
+
+// NewSparePartUseCase creates a new spare part use case
+func NewSparePartUseCase(partRepo repository.SparePartRepository, logger *slog.Logger) SparePartUseCase {
+	return &sparePartUseCase{
+		partRepo: partRepo,
+		logger:   logger,
+	}
+}
+
+// CreateSparePart adds a part to the inventory
+func (uc *sparePartUseCase) CreateSparePart(ctx context.Context, storefrontID uuid.UUID, req *dto.SparePartCreateRequest) (*dto.SparePartResponse, error) {
+	part := entity.NewSparePart(storefrontID, req.PartNumber, req.Name, req.UnitCost)
+	part.Description = req.Description
+	part.Supplier = req.Supplier
+	part.QuantityOnHand = req.QuantityOnHand
+	part.ReorderThreshold = req.ReorderThreshold
+	part.ReorderQuantity = req.ReorderQuantity
+	if err := part.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid spare part: %w", err)
+	}
+
+	if err := uc.checkPartNumberFree(ctx, storefrontID, part.PartNumber, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	if err := uc.partRepo.Create(ctx, part); err != nil {
+		return nil, fmt.Errorf("failed to create spare part: %w", err)
+	}
+
+	uc.logger.Info("Spare part created",
+		slog.String("storefront_id", storefrontID.String()),
+		slog.String("part_number", part.PartNumber),
+		slog.Int("quantity_on_hand", part.QuantityOnHand))
+
+	return dto.ConvertSparePartToResponse(part), nil
+}
+
+// ListSpareParts retrieves a page of spare parts with filters
+func (uc *sparePartUseCase) ListSpareParts(ctx context.Context, filters *repository.SparePartFilters) (*dto.SparePartListResponse, error) {
+	parts, err := uc.partRepo.List(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spare parts: %w", err)
+	}
+
+	total, err := uc.partRepo.Count(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count spare parts: %w", err)
+	}
+
+	return &dto.SparePartListResponse{
+		Parts:      dto.ConvertSparePartsToResponses(parts),
+		Pagination: buildOrderPagination(filters.Page, filters.PageSize, total),
+	}, nil
+}
+
+// GetSparePart retrieves a spare part of a storefront
+func (uc *sparePartUseCase) GetSparePart(ctx context.Context, storefrontID, partID uuid.UUID) (*dto.SparePartResponse, error) {
+	part, err := uc.getPart(ctx, storefrontID, partID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ConvertSparePartToResponse(part), nil
+}
+
+// UpdateSparePart updates the catalogue details of a spare part
+func (uc *sparePartUseCase) UpdateSparePart(ctx context.Context, storefrontID, partID uuid.UUID, req *dto.SparePartUpdateRequest) (*dto.SparePartResponse, error) {
+	part, err := uc.getPart(ctx, storefrontID, partID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.PartNumber != nil {
+		partNumber := strings.TrimSpace(*req.PartNumber)
+		if partNumber != part.PartNumber {
+			if err := uc.checkPartNumberFree(ctx, storefrontID, partNumber, part.ID); err != nil {
+				return nil, err
+			}
+		}
+		part.PartNumber = partNumber
+	}
+	if req.Name != nil {
+		part.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		part.Description = req.Description
+	}
+	if req.Supplier != nil {
+		part.Supplier = req.Supplier
+	}
+	if req.UnitCost != nil {
+		part.UnitCost = *req.UnitCost
+	}
+	if req.ReorderThreshold != nil {
+		part.ReorderThreshold = *req.ReorderThreshold
+	}
+	if req.ReorderQuantity != nil {
+		part.ReorderQuantity = *req.ReorderQuantity
+	}
+	if req.IsActive != nil {
+		part.IsActive = *req.IsActive
+	}
+	part.UpdatedAt = time.Now()
+	if err := part.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid spare part: %w", err)
+	}
+
+	if err := uc.partRepo.Update(ctx, part); err != nil {
+		return nil, fmt.Errorf("failed to update spare part: %w", err)
+	}
+
+	return dto.ConvertSparePartToResponse(part), nil
+}
+
+// DeleteSparePart removes a spare part that no repair holds stock of
+func (uc *sparePartUseCase) DeleteSparePart(ctx context.Context, storefrontID, partID uuid.UUID) error {
+	part, err := uc.getPart(ctx, storefrontID, partID)
+	if err != nil {
+		return err
+	}
+	if part.QuantityReserved > 0 {
+		return fmt.Errorf("invalid request: %d of spare part %s are reserved for repairs", part.QuantityReserved, part.PartNumber)
+	}
+
+	if err := uc.partRepo.Delete(ctx, part.ID); err != nil {
+		return fmt.Errorf("failed to delete spare part: %w", err)
+	}
+
+	return nil
+}
+
+// AdjustStock records stock received or written off. Stock that is reserved
+// for repairs cannot be written off.
+func (uc *sparePartUseCase) AdjustStock(ctx context.Context, storefrontID, partID, adjustedBy uuid.UUID, req *dto.SparePartStockAdjustmentRequest) (*dto.SparePartResponse, error) {
+	if req.Quantity == 0 {
+		return nil, fmt.Errorf("quantity must not be zero")
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+	if req.UnitCost != nil {
+		if req.Quantity < 0 {
+			return nil, fmt.Errorf("invalid request: unit_cost only applies to received stock")
+		}
+		if req.UnitCost.IsNegative() {
+			return nil, fmt.Errorf("unit_cost must not be negative")
+		}
+	}
+
+	part, err := uc.getPart(ctx, storefrontID, partID)
+	if err != nil {
+		return nil, err
+	}
+	if part.QuantityOnHand+req.Quantity < part.QuantityReserved {
+		return nil, fmt.Errorf("invalid request: only %d of spare part %s are not reserved", part.QuantityAvailable(), part.PartNumber)
+	}
+
+	part, err = uc.partRepo.AdjustStock(ctx, part.ID, req.Quantity, req.UnitCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to adjust spare part stock: %w", err)
+	}
+
+	uc.logger.Info("Spare part stock adjusted",
+		slog.String("part_number", part.PartNumber),
+		slog.Int("quantity", req.Quantity),
+		slog.Int("quantity_on_hand", part.QuantityOnHand),
+		slog.String("reason", req.Reason),
+		slog.String("adjusted_by", adjustedBy.String()))
+	if part.NeedsReorder() {
+		logSparePartReorder(uc.logger, part)
+	}
+
+	return dto.ConvertSparePartToResponse(part), nil
+}
+
+// getPart returns a spare part of the storefront
+func (uc *sparePartUseCase) getPart(ctx context.Context, storefrontID, partID uuid.UUID) (*entity.SparePart, error) {
+	part, err := uc.partRepo.GetByID(ctx, partID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spare part: %w", err)
+	}
+	if part == nil || part.StorefrontID != storefrontID {
+		return nil, fmt.Errorf("spare part not found")
+	}
+	return part, nil
+}
+
+// checkPartNumberFree rejects a part number another part of the storefront uses
+func (uc *sparePartUseCase) checkPartNumberFree(ctx context.Context, storefrontID uuid.UUID, partNumber string, partID uuid.UUID) error {
+	existing, err := uc.partRepo.GetByPartNumber(ctx, storefrontID, partNumber)
+	if err != nil {
+		return fmt.Errorf("failed to check part number: %w", err)
+	}
+	if existing != nil && existing.ID != partID {
+		return fmt.Errorf("spare part %s already exists", partNumber)
+	}
+	return nil
+}
+
+// logSparePartReorder warns that a spare part has dropped to its reorder threshold
+func logSparePartReorder(logger *slog.Logger, part *entity.SparePart) {
+	logger.Warn("Spare part needs reordering",
+		slog.String("storefront_id", part.StorefrontID.String()),
+		slog.String("part_number", part.PartNumber),
+		slog.Int("quantity_available", part.QuantityAvailable()),
+		slog.Int("reorder_threshold", part.ReorderThreshold),
+		slog.Int("reorder_quantity", part.ReorderQuantity))
+}
@@ -0,0 +1,432 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/database"
+)
+
+// TechnicianUseCase manages the repair roster of a storefront and picks the
+// technician for claims that need one
+type TechnicianUseCase interface {
+	// CreateTechnician adds a seller user to the repair roster
+	CreateTechnician(ctx context.Context, storefrontID uuid.UUID, req *dto.TechnicianCreateRequest) (*dto.TechnicianResponse, error)
+
+	// ListTechnicians retrieves the repair roster
+	ListTechnicians(ctx context.Context, storefrontID uuid.UUID, isActive *bool) ([]dto.TechnicianResponse, error)
+
+	// GetTechnician retrieves a technician of a storefront
+	GetTechnician(ctx context.Context, storefrontID, technicianID uuid.UUID) (*dto.TechnicianResponse, error)
+
+	// UpdateTechnician updates the capacity, hours and skills of a technician
+	UpdateTechnician(ctx context.Context, storefrontID, technicianID uuid.UUID, req *dto.TechnicianUpdateRequest) (*dto.TechnicianResponse, error)
+
+	// DeleteTechnician removes a technician with no active claims from the roster
+	DeleteTechnician(ctx context.Context, storefrontID, technicianID uuid.UUID) error
+
+	// GetWorkload retrieves the work assigned to each technician
+	GetWorkload(ctx context.Context, storefrontID uuid.UUID) (*dto.TechnicianWorkloadSummaryResponse, error)
+
+	// SelectTechnician picks the technician for a claim, or returns nil when
+	// no active technician skilled in the product has capacity left
+	SelectTechnician(ctx context.Context, claim *entity.WarrantyClaim) (*entity.TechnicianWorkload, error)
+
+	// CheckAssignable checks that a user is an active technician of the storefront
+	CheckAssignable(ctx context.Context, storefrontID, userID uuid.UUID) error
+}
+
+// technicianUseCase implements the TechnicianUseCase interface
+type technicianUseCase struct {
+	db             *sqlx.DB
+	technicianRepo repository.TechnicianRepository
+	claimRepo      repository.WarrantyClaimRepository
+	storefrontRepo repository.StorefrontRepository
+	userRepo       repository.UserRepository
+	productRepo    repository.ProductRepository
+	categoryRepo   repository.ProductCategoryRepository
+	strategy       entity.TechnicianAssignmentStrategy
+	logger         *slog.Logger
+}
+
+// NewTechnicianUseCase creates a new technician use case. Claims are assigned
+// with the least-loaded strategy.
+func NewTechnicianUseCase(
+	db *sqlx.DB,
+	technicianRepo repository.TechnicianRepository,
+	claimRepo repository.WarrantyClaimRepository,
+	storefrontRepo repository.StorefrontRepository,
+	userRepo repository.UserRepository,
+	productRepo repository.ProductRepository,
+	categoryRepo repository.ProductCategoryRepository,
+	logger *slog.Logger,
+) TechnicianUseCase {
+	return &technicianUseCase{
+		db:             db,
+		technicianRepo: technicianRepo,
+		claimRepo:      claimRepo,
+		storefrontRepo: storefrontRepo,
+		userRepo:       userRepo,
+		productRepo:    productRepo,
+		categoryRepo:   categoryRepo,
+		strategy:       entity.LeastLoadedAssignment{},
+		logger:         logger,
+	}
+}
+
+// CreateTechnician adds a seller user to the repair roster. Only users of
+// the storefront's seller can join it; sellers have no staff accounts yet, so
+// that is the seller's own account. Other users are reported as not found.
+func (uc *technicianUseCase) CreateTechnician(ctx context.Context, storefrontID uuid.UUID, req *dto.TechnicianCreateRequest) (*dto.TechnicianResponse, error) {
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	sellerID, err := uc.getSellerID(ctx, storefrontID)
+	if err != nil {
+		return nil, err
+	}
+	if userID != sellerID {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	user, err := uc.userRepo.GetUserByID(userID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	existing, err := uc.technicianRepo.GetByUserID(ctx, storefrontID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check technician: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("technician %s already exists", user.Name)
+	}
+
+	technician := entity.NewTechnician(storefrontID, userID)
+	technician.Name = user.Name
+	technician.Email = user.Email
+	if req.MaxConcurrentTickets != nil {
+		technician.MaxConcurrentTickets = *req.MaxConcurrentTickets
+	}
+	if req.WorkingHours != nil {
+		technician.WorkingHours = dto.ConvertWorkShiftsToEntity(req.WorkingHours)
+	}
+	if tz := strings.TrimSpace(req.Timezone); tz != "" {
+		technician.Timezone = tz
+	}
+	if technician.Skills, err = uc.resolveSkills(ctx, sellerID, req.SkillCategoryIDs); err != nil {
+		return nil, err
+	}
+	if err := technician.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid technician: %w", err)
+	}
+
+	err = database.WithTransaction(ctx, uc.db, func(txCtx context.Context, _ *sqlx.Tx) error {
+		return uc.technicianRepo.Create(txCtx, technician)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create technician: %w", err)
+	}
+
+	uc.logger.Info("Technician added to repair roster",
+		slog.String("storefront_id", storefrontID.String()),
+		slog.String("user_id", userID.String()),
+		slog.Int("skills", len(technician.Skills)))
+
+	return dto.ConvertTechnicianToResponse(technician), nil
+}
+
+// ListTechnicians retrieves the repair roster
+func (uc *technicianUseCase) ListTechnicians(ctx context.Context, storefrontID uuid.UUID, isActive *bool) ([]dto.TechnicianResponse, error) {
+	workloads, err := uc.technicianRepo.ListWorkloads(ctx, &repository.TechnicianFilters{
+		StorefrontID: storefrontID,
+		IsActive:     isActive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list technicians: %w", err)
+	}
+
+	responses := make([]dto.TechnicianResponse, 0, len(workloads))
+	for _, workload := range workloads {
+		responses = append(responses, *dto.ConvertTechnicianToResponse(&workload.Technician))
+	}
+	return responses, nil
+}
+
+// GetTechnician retrieves a technician of a storefront
+func (uc *technicianUseCase) GetTechnician(ctx context.Context, storefrontID, technicianID uuid.UUID) (*dto.TechnicianResponse, error) {
+	technician, err := uc.getTechnician(ctx, storefrontID, technicianID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ConvertTechnicianToResponse(technician), nil
+}
+
+// UpdateTechnician updates the capacity, hours and skills of a technician.
+// Lowering the capacity below the current load keeps the claims assigned;
+// the technician just gets no new ones until the load drops.
+func (uc *technicianUseCase) UpdateTechnician(ctx context.Context, storefrontID, technicianID uuid.UUID, req *dto.TechnicianUpdateRequest) (*dto.TechnicianResponse, error) {
+	technician, err := uc.getTechnician(ctx, storefrontID, technicianID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MaxConcurrentTickets != nil {
+		technician.MaxConcurrentTickets = *req.MaxConcurrentTickets
+	}
+	if req.WorkingHours != nil {
+		technician.WorkingHours = dto.ConvertWorkShiftsToEntity(req.WorkingHours)
+	}
+	if req.Timezone != nil {
+		technician.Timezone = strings.TrimSpace(*req.Timezone)
+	}
+	if req.SkillCategoryIDs != nil {
+		sellerID, err := uc.getSellerID(ctx, storefrontID)
+		if err != nil {
+			return nil, err
+		}
+		if technician.Skills, err = uc.resolveSkills(ctx, sellerID, req.SkillCategoryIDs); err != nil {
+			return nil, err
+		}
+	}
+	if req.IsActive != nil {
+		technician.IsActive = *req.IsActive
+	}
+	technician.UpdatedAt = time.Now()
+	if err := technician.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid technician: %w", err)
+	}
+
+	err = database.WithTransaction(ctx, uc.db, func(txCtx context.Context, _ *sqlx.Tx) error {
+		return uc.technicianRepo.Update(txCtx, technician)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update technician: %w", err)
+	}
+
+	return dto.ConvertTechnicianToResponse(technician), nil
+}
+
+// DeleteTechnician removes a technician with no active claims from the roster
+func (uc *technicianUseCase) DeleteTechnician(ctx context.Context, storefrontID, technicianID uuid.UUID) error {
+	technician, err := uc.getTechnician(ctx, storefrontID, technicianID)
+	if err != nil {
+		return err
+	}
+
+	workload, err := uc.getWorkload(ctx, technician)
+	if err != nil {
+		return err
+	}
+	if workload != nil && workload.ActiveClaims > 0 {
+		return fmt.Errorf("invalid request: technician %s has %d active claims; reassign them first", technician.Name, workload.ActiveClaims)
+	}
+
+	if err := uc.technicianRepo.Delete(ctx, technician.ID); err != nil {
+		return fmt.Errorf("failed to delete technician: %w", err)
+	}
+
+	return nil
+}
+
+// GetWorkload retrieves the work assigned to each technician, with the
+// validated claims still waiting for one
+func (uc *technicianUseCase) GetWorkload(ctx context.Context, storefrontID uuid.UUID) (*dto.TechnicianWorkloadSummaryResponse, error) {
+	workloads, err := uc.technicianRepo.ListWorkloads(ctx, &repository.TechnicianFilters{StorefrontID: storefrontID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list technician workloads: %w", err)
+	}
+
+	validated := entity.ClaimStatusValidated
+	unassigned, err := uc.claimRepo.Count(ctx, &repository.WarrantyClaimFilters{
+		StorefrontID: &storefrontID,
+		Status:       &validated,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unassigned claims: %w", err)
+	}
+
+	now := time.Now()
+	response := &dto.TechnicianWorkloadSummaryResponse{
+		Technicians:      make([]dto.TechnicianWorkloadResponse, 0, len(workloads)),
+		UnassignedClaims: unassigned,
+	}
+	for _, workload := range workloads {
+		item := dto.ConvertTechnicianWorkloadToResponse(workload, now)
+		response.Technicians = append(response.Technicians, *item)
+		response.TotalActiveClaims += workload.ActiveClaims
+		if !workload.IsActive {
+			continue
+		}
+		response.ActiveTechnicians++
+		response.TotalCapacity += workload.MaxConcurrentTickets
+		if item.OnShift {
+			response.OnShiftTechnicians++
+		}
+	}
+
+	return response, nil
+}
+
+// SelectTechnician picks the technician for a claim among the active
+// technicians skilled in the claimed product's category. A product without
+// a category can go to any active technician.
+func (uc *technicianUseCase) SelectTechnician(ctx context.Context, claim *entity.WarrantyClaim) (*entity.TechnicianWorkload, error) {
+	product, err := uc.productRepo.GetByID(ctx, claim.ProductID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get claimed product: %w", err)
+	}
+
+	active := true
+	filters := &repository.TechnicianFilters{StorefrontID: claim.StorefrontID, IsActive: &active}
+	if product != nil {
+		filters.SkillCategoryID = product.CategoryID
+	}
+
+	candidates, err := uc.technicianRepo.ListWorkloads(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list technician candidates: %w", err)
+	}
+
+	selected := uc.strategy.SelectTechnician(candidates, time.Now())
+	if selected == nil {
+		uc.logger.Warn("No technician available for claim",
+			slog.String("claim_id", claim.ID.String()),
+			slog.String("storefront_id", claim.StorefrontID.String()),
+			slog.Int("candidates", len(candidates)))
+		return nil, nil
+	}
+
+	uc.logger.Info("Technician selected for claim",
+		slog.String("claim_id", claim.ID.String()),
+		slog.String("technician_id", selected.UserID.String()),
+		slog.Int("active_claims", selected.ActiveClaims),
+		slog.Int("max_concurrent_tickets", selected.MaxConcurrentTickets))
+
+	return selected, nil
+}
+
+// CheckAssignable checks that a user is an active technician of the
+// storefront. Supervisors may assign past a technician's capacity.
+func (uc *technicianUseCase) CheckAssignable(ctx context.Context, storefrontID, userID uuid.UUID) error {
+	technician, err := uc.technicianRepo.GetByUserID(ctx, storefrontID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get technician: %w", err)
+	}
+	if technician == nil {
+		return fmt.Errorf("technician not found")
+	}
+	if !technician.IsActive {
+		return fmt.Errorf("invalid request: technician %s is inactive", technician.Name)
+	}
+	return nil
+}
+
+// getTechnician returns a technician of the storefront
+func (uc *technicianUseCase) getTechnician(ctx context.Context, storefrontID, technicianID uuid.UUID) (*entity.Technician, error) {
+	technician, err := uc.technicianRepo.GetByID(ctx, technicianID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get technician: %w", err)
+	}
+	if technician == nil || technician.StorefrontID != storefrontID {
+		return nil, fmt.Errorf("technician not found")
+	}
+	return technician, nil
+}
+
+// getWorkload returns the workload of a technician
+func (uc *technicianUseCase) getWorkload(ctx context.Context, technician *entity.Technician) (*entity.TechnicianWorkload, error) {
+	workloads, err := uc.technicianRepo.ListWorkloads(ctx, &repository.TechnicianFilters{StorefrontID: technician.StorefrontID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get technician workload: %w", err)
+	}
+	for _, workload := range workloads {
+		if workload.ID == technician.ID {
+			return workload, nil
+		}
+	}
+	return nil, nil
+}
+
+// getSellerID returns the seller owning a storefront
+func (uc *technicianUseCase) getSellerID(ctx context.Context, storefrontID uuid.UUID) (uuid.UUID, error) {
+	storefront, err := uc.storefrontRepo.GetByID(ctx, storefrontID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get storefront: %w", err)
+	}
+	if storefront == nil {
+		return uuid.Nil, fmt.Errorf("storefront not found")
+	}
+	return storefront.SellerID, nil
+}
+
+// resolveSkills parses skill category IDs and checks the categories are part
+// of the seller's catalog. Categories are shared by all sellers, so one
+// belongs to the seller when it or a subcategory holds a product of theirs.
+func (uc *technicianUseCase) resolveSkills(ctx context.Context, sellerID uuid.UUID, categoryIDs []string) ([]entity.TechnicianSkill, error) {
+	skills := make([]entity.TechnicianSkill, 0, len(categoryIDs))
+	seen := make(map[uuid.UUID]bool, len(categoryIDs))
+	for _, id := range categoryIDs {
+		categoryID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid skill category ID: %s", id)
+		}
+		if seen[categoryID] {
+			continue
+		}
+		seen[categoryID] = true
+
+		category, err := uc.categoryRepo.GetByID(ctx, categoryID, nil)
+		if err != nil || category == nil {
+			return nil, fmt.Errorf("product category %s not found", categoryID)
+		}
+
+		inCatalog, err := uc.sellerHasProductsIn(ctx, sellerID, categoryID)
+		if err != nil {
+			return nil, err
+		}
+		if !inCatalog {
+			return nil, fmt.Errorf("product category %s not found", categoryID)
+		}
+		skills = append(skills, entity.TechnicianSkill{CategoryID: categoryID, CategoryName: category.Name})
+	}
+	return skills, nil
+}
+
+// sellerHasProductsIn reports whether the seller has products in a category
+// or any of its subcategories
+func (uc *technicianUseCase) sellerHasProductsIn(ctx context.Context, sellerID, categoryID uuid.UUID) (bool, error) {
+	children, err := uc.categoryRepo.GetAllChildren(ctx, categoryID, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to get subcategories: %w", err)
+	}
+
+	categoryIDs := make([]uuid.UUID, 0, len(children)+1)
+	categoryIDs = append(categoryIDs, categoryID)
+	for _, child := range children {
+		categoryIDs = append(categoryIDs, child.ID)
+	}
+
+	count, err := uc.productRepo.Count(ctx, &repository.ProductFilter{
+		CategoryIDs: categoryIDs,
+		CreatedBy:   &sellerID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to count seller products: %w", err)
+	}
+	return count > 0, nil
+}
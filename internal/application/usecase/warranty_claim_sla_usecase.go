@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/pkg/email"
+	"github.com/kirimku/smartseller-backend/pkg/telegram"
+)
+
+// claimSLASweepBatch caps the claims handled by a single SLA sweep
+const claimSLASweepBatch = 100
+
+// WarrantyClaimSLAUseCase escalates warranty claims that are about to miss
+// their processing SLA
+type WarrantyClaimSLAUseCase interface {
+	// EscalateClaims alerts the team and the seller about claims whose SLA is
+	// at risk or breached, and records breaches. Returns the number of claims
+	// escalated.
+	EscalateClaims(ctx context.Context) (int, error)
+}
+
+// warrantyClaimSLAUseCase implements the WarrantyClaimSLAUseCase interface
+type warrantyClaimSLAUseCase struct {
+	claimRepo      repository.WarrantyClaimRepository
+	storefrontRepo repository.StorefrontRepository
+	userRepo       repository.UserRepository
+	emailSender    email.EmailSender
+	alertManager   *telegram.TelegramAlertManager
+	logger         *slog.Logger
+}
+
+// NewWarrantyClaimSLAUseCase creates a new warranty claim SLA use case
+func NewWarrantyClaimSLAUseCase(
+	claimRepo repository.WarrantyClaimRepository,
+	storefrontRepo repository.StorefrontRepository,
+	userRepo repository.UserRepository,
+	emailSender email.EmailSender,
+	alertManager *telegram.TelegramAlertManager,
+	logger *slog.Logger,
+) WarrantyClaimSLAUseCase {
+	return &warrantyClaimSLAUseCase{
+		claimRepo:      claimRepo,
+		storefrontRepo: storefrontRepo,
+		userRepo:       userRepo,
+		emailSender:    emailSender,
+		alertManager:   alertManager,
+		logger:         logger,
+	}
+}
+
+// EscalateClaims escalates each at-risk claim once, through Telegram and an
+// email to the seller. A claim that breaches after it was escalated only
+// raises a Telegram alert.
+func (uc *warrantyClaimSLAUseCase) EscalateClaims(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	claims, err := uc.claimRepo.GetSLAEscalationCandidates(ctx, now, claimSLASweepBatch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get SLA escalation candidates: %w", err)
+	}
+
+	escalated := 0
+	for _, claim := range claims {
+		// Each alert goes out only from the worker whose conditional update
+		// recorded it, so replicas sweeping the same claim do not repeat it
+		breached, err := uc.claimBreach(ctx, claim, now)
+		if err != nil {
+			uc.logger.Error("Failed to record claim SLA breach", "claim_id", claim.ID, "error", err)
+			continue
+		}
+
+		escalate := false
+		if claim.NeedsSLAEscalation(now) {
+			escalate, err = uc.claimRepo.ClaimSLAEscalation(ctx, claim, now)
+			if err != nil {
+				uc.logger.Error("Failed to record claim SLA escalation", "claim_id", claim.ID, "error", err)
+				continue
+			}
+			if escalate {
+				claim.SLAEscalatedAt = &now
+			}
+		}
+
+		if !escalate && !breached {
+			continue
+		}
+
+		uc.sendTelegramAlert(claim, now)
+		if escalate {
+			uc.sendSellerEmail(ctx, claim, now)
+			escalated++
+		}
+	}
+
+	return escalated, nil
+}
+
+// claimBreach records the breach of a claim whose due-by has passed. Returns
+// false when there is nothing new to record or another worker recorded it.
+func (uc *warrantyClaimSLAUseCase) claimBreach(ctx context.Context, claim *entity.WarrantyClaim, now time.Time) (bool, error) {
+	breachedAt := claim.SLABreachedAt
+	if !claim.MarkSLABreached(now) {
+		return false, nil
+	}
+
+	claimed, err := uc.claimRepo.ClaimSLABreach(ctx, claim, now)
+	if err != nil || !claimed {
+		claim.SLABreachedAt = breachedAt
+		return false, err
+	}
+	return true, nil
+}
+
+// sendTelegramAlert alerts the operations chat about a claim
+func (uc *warrantyClaimSLAUseCase) sendTelegramAlert(claim *entity.WarrantyClaim, now time.Time) {
+	title, emoji, level := "Warranty Claim SLA At Risk", "⏰", telegram.AlertLevelWarning
+	if claim.SLAState(now) == entity.ClaimSLAStateBreached {
+		title, emoji, level = "Warranty Claim SLA Breached", "🚨", telegram.AlertLevelCritical
+	}
+
+	uc.alertManager.SendCustomAlert(title, emoji, level, "warranty_claim_sla",
+		fmt.Sprintf("Claim %s is due by %s", claim.ClaimNumber, claim.SLADueAt.Format(time.RFC3339)),
+		map[string]interface{}{
+			"claim_id":      claim.ID.String(),
+			"storefront_id": claim.StorefrontID.String(),
+			"status":        claim.Status.String(),
+			"priority":      string(claim.Priority),
+		},
+	)
+}
+
+// sendSellerEmail emails the storefront's business address, or the seller
+// when the storefront has none
+func (uc *warrantyClaimSLAUseCase) sendSellerEmail(ctx context.Context, claim *entity.WarrantyClaim, now time.Time) {
+	if uc.emailSender == nil {
+		return
+	}
+
+	recipient, err := uc.sellerEmail(ctx, claim.StorefrontID)
+	if err != nil {
+		uc.logger.Error("Failed to find claim SLA escalation recipient", "claim_id", claim.ID, "error", err)
+		return
+	}
+	if recipient == "" {
+		uc.logger.Warn("No email address for claim SLA escalation", "claim_id", claim.ID, "storefront_id", claim.StorefrontID)
+		return
+	}
+
+	subject := fmt.Sprintf("Warranty claim %s is close to its SLA", claim.ClaimNumber)
+	timing := fmt.Sprintf("is due by %s (%s left)", claim.SLADueAt.Format("02 Jan 2006 15:04 MST"), claim.SLADueAt.Sub(now).Round(time.Minute))
+	if claim.SLAState(now) == entity.ClaimSLAStateBreached {
+		subject = fmt.Sprintf("Warranty claim %s has missed its SLA", claim.ClaimNumber)
+		timing = fmt.Sprintf("was due by %s", claim.SLADueAt.Format("02 Jan 2006 15:04 MST"))
+	}
+
+	htmlBody := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+    <p>Warranty claim <strong>%s</strong> from %s %s.</p>
+    <ul>
+        <li>Status: %s</li>
+        <li>Priority: %s</li>
+        <li>Issue: %s</li>
+    </ul>
+    <p>Please review the claim in your SmartSeller dashboard.</p>
+</body>
+</html>`,
+		html.EscapeString(claim.ClaimNumber),
+		html.EscapeString(claim.CustomerName),
+		timing,
+		html.EscapeString(claim.GetDisplayStatus()),
+		html.EscapeString(string(claim.Priority)),
+		html.EscapeString(claim.IssueDescription),
+	)
+
+	if err := uc.emailSender.SendEmail(recipient, subject, htmlBody); err != nil {
+		uc.logger.Error("Failed to send claim SLA escalation email", "claim_id", claim.ID, "error", err)
+	}
+}
+
+// sellerEmail returns the address that receives a storefront's escalations
+func (uc *warrantyClaimSLAUseCase) sellerEmail(ctx context.Context, storefrontID uuid.UUID) (string, error) {
+	storefront, err := uc.storefrontRepo.GetByID(ctx, storefrontID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get storefront: %w", err)
+	}
+	if storefront == nil {
+		return "", fmt.Errorf("storefront %s not found", storefrontID)
+	}
+	if storefront.BusinessEmail != nil && *storefront.BusinessEmail != "" {
+		return *storefront.BusinessEmail, nil
+	}
+
+	seller, err := uc.userRepo.GetUserByID(storefront.SellerID.String())
+	if err != nil {
+		return "", fmt.Errorf("failed to get seller: %w", err)
+	}
+	if seller == nil {
+		return "", nil
+	}
+	return seller.Email, nil
+}
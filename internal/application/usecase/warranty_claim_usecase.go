@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,14 +23,19 @@ type WarrantyClaimUseCase interface {
 	// GetClaimByNumber retrieves a warranty claim by claim number
 	GetClaimByNumber(ctx context.Context, claimNumber string) (*dto.WarrantyClaimResponse, error)
 
-	// ListClaims retrieves warranty claims with filters
-	ListClaims(ctx context.Context, filters *repository.WarrantyClaimFilters) ([]*dto.WarrantyClaimResponse, error)
+	// ListClaims retrieves a page of warranty claims with filters
+	ListClaims(ctx context.Context, filters *repository.WarrantyClaimFilters) (*dto.WarrantyClaimListResponse, error)
 
-	// ValidateClaim validates a warranty claim
-	ValidateClaim(ctx context.Context, claimID uuid.UUID, req *dto.WarrantyClaimValidationRequest, validatedBy uuid.UUID) (*dto.WarrantyClaimResponse, error)
+	// ValidateClaim validates a storefront's warranty claim; a validated claim
+	// is assigned to a technician when one is available
+	ValidateClaim(ctx context.Context, storefrontID, claimID uuid.UUID, req *dto.WarrantyClaimValidationRequest, validatedBy uuid.UUID) (*dto.WarrantyClaimResponse, error)
 
-	// AssignTechnician assigns a technician to a warranty claim
-	AssignTechnician(ctx context.Context, claimID uuid.UUID, req *dto.WarrantyClaimAssignmentRequest, assignedBy uuid.UUID) (*dto.WarrantyClaimResponse, error)
+	// AssignTechnician assigns a technician of the roster to a storefront's warranty claim
+	AssignTechnician(ctx context.Context, storefrontID, claimID uuid.UUID, req *dto.WarrantyClaimAssignmentRequest, assignedBy uuid.UUID) (*dto.WarrantyClaimResponse, error)
+
+	// AutoAssignTechnician assigns a validated claim to the technician picked
+	// by the assignment strategy
+	AutoAssignTechnician(ctx context.Context, storefrontID, claimID, assignedBy uuid.UUID) (*dto.WarrantyClaimResponse, error)
 
 	// UpdateClaimStatus updates the status of a warranty claim
 	UpdateClaimStatus(ctx context.Context, claimID uuid.UUID, req *dto.WarrantyClaimStatusUpdateRequest, updatedBy uuid.UUID) (*dto.WarrantyClaimResponse, error)
@@ -52,18 +58,27 @@ type WarrantyClaimUseCase interface {
 
 // warrantyClaimUseCase implements the WarrantyClaimUseCase interface
 type warrantyClaimUseCase struct {
-	claimRepo   repository.WarrantyClaimRepository
-	barcodeRepo repository.WarrantyBarcodeRepository
+	claimRepo     repository.WarrantyClaimRepository
+	barcodeRepo   repository.WarrantyBarcodeRepository
+	policyUseCase WarrantyPolicyUseCase
+	technicians   TechnicianUseCase
+	slaConfig     entity.ClaimSLAConfig
 }
 
 // NewWarrantyClaimUseCase creates a new warranty claim use case
 func NewWarrantyClaimUseCase(
 	claimRepo repository.WarrantyClaimRepository,
 	barcodeRepo repository.WarrantyBarcodeRepository,
+	policyUseCase WarrantyPolicyUseCase,
+	technicians TechnicianUseCase,
+	slaConfig entity.ClaimSLAConfig,
 ) WarrantyClaimUseCase {
 	return &warrantyClaimUseCase{
-		claimRepo:   claimRepo,
-		barcodeRepo: barcodeRepo,
+		claimRepo:     claimRepo,
+		barcodeRepo:   barcodeRepo,
+		policyUseCase: policyUseCase,
+		technicians:   technicians,
+		slaConfig:     slaConfig,
 	}
 }
 
@@ -101,6 +116,21 @@ func (uc *warrantyClaimUseCase) SubmitClaim(ctx context.Context, req *dto.Warran
 		}
 	}
 
+	// Apply the claim rules of the warranty policy. A receipt number is enough
+	// to submit; the receipt itself is checked when the claim is validated.
+	policy, err := uc.policyForBarcode(ctx, barcode)
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		if countClaimsTowardLimit(existingClaims, uuid.Nil) >= policy.MaxClaimsPerProduct {
+			return nil, fmt.Errorf("claim not allowed: the warranty policy allows at most %d claims per product", policy.MaxClaimsPerProduct)
+		}
+		if policy.RequiresPurchaseReceipt && barcode.PurchaseInvoice == nil && barcode.ProofOfPurchasePath == nil && strings.TrimSpace(req.ReceiptNumber) == "" {
+			return nil, fmt.Errorf("receipt number is required by the warranty policy")
+		}
+	}
+
 	// Generate claim number
 	claimNumber, err := uc.claimRepo.GenerateClaimNumber(ctx, barcode.StorefrontID)
 	if err != nil {
@@ -129,6 +159,13 @@ func (uc *warrantyClaimUseCase) SubmitClaim(ctx context.Context, req *dto.Warran
 	}
 	claim.PickupAddress = pickupAddress
 
+	// Start the processing SLA of the warranty policy
+	slaHours := uc.slaConfig.DefaultHours
+	if policy != nil {
+		slaHours = policy.ClaimProcessingSLAHours
+	}
+	claim.StartSLA(slaHours, uc.slaConfig.AtRiskRatio)
+
 	// Create the claim
 	err = uc.claimRepo.Create(ctx, claim)
 	if err != nil {
@@ -190,42 +227,68 @@ func (uc *warrantyClaimUseCase) GetClaimByNumber(ctx context.Context, claimNumbe
 	return response, nil
 }
 
-// ListClaims retrieves warranty claims with filters
-func (uc *warrantyClaimUseCase) ListClaims(ctx context.Context, filters *repository.WarrantyClaimFilters) ([]*dto.WarrantyClaimResponse, error) {
+// ListClaims retrieves a page of warranty claims with filters
+func (uc *warrantyClaimUseCase) ListClaims(ctx context.Context, filters *repository.WarrantyClaimFilters) (*dto.WarrantyClaimListResponse, error) {
+	if filters.SLAState != nil && !filters.SLAState.Valid() {
+		return nil, fmt.Errorf("invalid SLA state: %s", *filters.SLAState)
+	}
+
 	claims, err := uc.claimRepo.GetWithFilters(ctx, filters)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get warranty claims: %w", err)
 	}
 
-	responses := dto.ConvertWarrantyClaimsToResponses(claims)
+	total, err := uc.claimRepo.Count(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count warranty claims: %w", err)
+	}
+
+	response := &dto.WarrantyClaimListResponse{
+		Claims:     make([]dto.WarrantyClaimResponse, 0, len(claims)),
+		Pagination: buildOrderPagination(filters.Page, filters.PageSize, total),
+		Filters: dto.ClaimFiltersResponse{
+			AvailableStatuses:   []string{"pending", "validated", "rejected", "assigned", "in_repair", "repaired", "replaced", "shipped", "delivered", "completed", "cancelled", "disputed"},
+			AvailableSeverities: []string{"low", "medium", "high", "critical"},
+			AvailablePriorities: []string{"low", "normal", "high", "urgent"},
+			AvailableCategories: []string{"hardware", "software", "performance", "defect", "damage", "other"},
+			AvailableSLAStates:  []string{"on_track", "at_risk", "breached", "paused", "met"},
+		},
+	}
 
 	// Enrich with barcode information if needed
-	for i, claim := range claims {
+	for _, claim := range claims {
+		claimResponse := dto.ConvertWarrantyClaimToResponse(claim)
 		barcode, err := uc.barcodeRepo.GetByID(ctx, claim.BarcodeID)
 		if err == nil && barcode != nil {
-			responses[i].BarcodeValue = barcode.BarcodeNumber
+			claimResponse.BarcodeValue = barcode.BarcodeNumber
 		}
+		response.Claims = append(response.Claims, *claimResponse)
 	}
 
-	return responses, nil
+	return response, nil
 }
 
-// ValidateClaim validates a warranty claim
-func (uc *warrantyClaimUseCase) ValidateClaim(ctx context.Context, claimID uuid.UUID, req *dto.WarrantyClaimValidationRequest, validatedBy uuid.UUID) (*dto.WarrantyClaimResponse, error) {
-	claim, err := uc.claimRepo.GetByID(ctx, claimID)
+// ValidateClaim validates a storefront's warranty claim. A validated claim
+// is assigned right away to the technician the assignment strategy picks;
+// it stays validated when no technician is available.
+func (uc *warrantyClaimUseCase) ValidateClaim(ctx context.Context, storefrontID, claimID uuid.UUID, req *dto.WarrantyClaimValidationRequest, validatedBy uuid.UUID) (*dto.WarrantyClaimResponse, error) {
+	claim, err := uc.getStorefrontClaim(ctx, storefrontID, claimID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get warranty claim: %w", err)
-	}
-	if claim == nil {
-		return nil, fmt.Errorf("warranty claim not found")
+		return nil, err
 	}
 
 	switch req.Action {
 	case "validate":
+		if err := uc.checkClaimPolicy(ctx, claim); err != nil {
+			return nil, err
+		}
 		err = claim.ValidateForSubmission(validatedBy, req.Notes)
 		if err != nil {
 			return nil, fmt.Errorf("failed to validate claim: %w", err)
 		}
+		if _, err := uc.autoAssign(ctx, claim, validatedBy, req.EstimatedCompletionDate); err != nil {
+			return nil, err
+		}
 
 	case "reject":
 		err = claim.Reject(validatedBy, req.RejectionReason)
@@ -242,7 +305,9 @@ func (uc *warrantyClaimUseCase) ValidateClaim(ctx context.Context, claimID uuid.
 			note := fmt.Sprintf("Additional information requested: %s", req.RequestedInfo)
 			claim.AdminNotes = &note
 		}
-		// Don't change status for info requests
+		// Don't change status for info requests, but stop the SLA clock
+		// until the customer responds
+		claim.PauseSLA(time.Now())
 
 	default:
 		return nil, fmt.Errorf("invalid validation action: %s", req.Action)
@@ -268,29 +333,31 @@ func (uc *warrantyClaimUseCase) ValidateClaim(ctx context.Context, claimID uuid.
 	return response, nil
 }
 
-// AssignTechnician assigns a technician to a warranty claim
-func (uc *warrantyClaimUseCase) AssignTechnician(ctx context.Context, claimID uuid.UUID, req *dto.WarrantyClaimAssignmentRequest, assignedBy uuid.UUID) (*dto.WarrantyClaimResponse, error) {
-	claim, err := uc.claimRepo.GetByID(ctx, claimID)
+// AssignTechnician assigns a technician of the roster to a storefront's
+// warranty claim
+func (uc *warrantyClaimUseCase) AssignTechnician(ctx context.Context, storefrontID, claimID uuid.UUID, req *dto.WarrantyClaimAssignmentRequest, assignedBy uuid.UUID) (*dto.WarrantyClaimResponse, error) {
+	claim, err := uc.getStorefrontClaim(ctx, storefrontID, claimID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get warranty claim: %w", err)
-	}
-	if claim == nil {
-		return nil, fmt.Errorf("warranty claim not found")
+		return nil, err
 	}
 
 	technicianID, err := uuid.Parse(req.TechnicianID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid technician ID: %w", err)
 	}
+	if err := uc.technicians.CheckAssignable(ctx, storefrontID, technicianID); err != nil {
+		return nil, err
+	}
 
 	err = claim.AssignTechnician(technicianID, assignedBy, req.EstimatedCompletionDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to assign technician: %w", err)
 	}
 
-	// Set priority if provided
-	if req.Priority != "" {
+	// Set priority if provided; the SLA budget depends on it
+	if req.Priority != "" && entity.ClaimPriority(req.Priority) != claim.Priority {
 		claim.Priority = entity.ClaimPriority(req.Priority)
+		claim.RecalculateSLA(uc.slaConfig.AtRiskRatio)
 	}
 
 	// Add notes if provided
@@ -318,6 +385,73 @@ func (uc *warrantyClaimUseCase) AssignTechnician(ctx context.Context, claimID uu
 	return response, nil
 }
 
+// AutoAssignTechnician assigns a validated claim to the technician picked by
+// the assignment strategy, for claims validated while nobody was available
+func (uc *warrantyClaimUseCase) AutoAssignTechnician(ctx context.Context, storefrontID, claimID, assignedBy uuid.UUID) (*dto.WarrantyClaimResponse, error) {
+	claim, err := uc.getStorefrontClaim(ctx, storefrontID, claimID)
+	if err != nil {
+		return nil, err
+	}
+	if claim.Status != entity.ClaimStatusValidated {
+		return nil, fmt.Errorf("invalid request: only validated claims are assigned automatically, current status: %s", claim.Status)
+	}
+
+	assigned, err := uc.autoAssign(ctx, claim, assignedBy, claim.EstimatedCompletionDate)
+	if err != nil {
+		return nil, err
+	}
+	if !assigned {
+		return nil, fmt.Errorf("invalid request: no active technician skilled in the product has capacity left")
+	}
+
+	err = uc.claimRepo.Update(ctx, claim)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update warranty claim: %w", err)
+	}
+
+	// Get barcode information
+	barcode, err := uc.barcodeRepo.GetByID(ctx, claim.BarcodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get barcode information: %w", err)
+	}
+
+	response := dto.ConvertWarrantyClaimToResponse(claim)
+	if barcode != nil {
+		response.BarcodeValue = barcode.BarcodeNumber
+	}
+
+	return response, nil
+}
+
+// autoAssign assigns a validated claim to the technician the strategy picks
+// and reports whether one was available
+func (uc *warrantyClaimUseCase) autoAssign(ctx context.Context, claim *entity.WarrantyClaim, assignedBy uuid.UUID, estimatedCompletionDate *time.Time) (bool, error) {
+	technician, err := uc.technicians.SelectTechnician(ctx, claim)
+	if err != nil {
+		return false, err
+	}
+	if technician == nil {
+		return false, nil
+	}
+
+	if err := claim.AssignTechnician(technician.UserID, assignedBy, estimatedCompletionDate); err != nil {
+		return false, fmt.Errorf("failed to assign technician: %w", err)
+	}
+	return true, nil
+}
+
+// getStorefrontClaim returns a warranty claim of the storefront
+func (uc *warrantyClaimUseCase) getStorefrontClaim(ctx context.Context, storefrontID, claimID uuid.UUID) (*entity.WarrantyClaim, error) {
+	claim, err := uc.claimRepo.GetByID(ctx, claimID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warranty claim: %w", err)
+	}
+	if claim == nil || claim.StorefrontID != storefrontID {
+		return nil, fmt.Errorf("warranty claim not found")
+	}
+	return claim, nil
+}
+
 // UpdateClaimStatus updates the status of a warranty claim
 func (uc *warrantyClaimUseCase) UpdateClaimStatus(ctx context.Context, claimID uuid.UUID, req *dto.WarrantyClaimStatusUpdateRequest, updatedBy uuid.UUID) (*dto.WarrantyClaimResponse, error) {
 	claim, err := uc.claimRepo.GetByID(ctx, claimID)
@@ -396,6 +530,9 @@ func (uc *warrantyClaimUseCase) GetClaimStatistics(ctx context.Context, storefro
 		"claims_this_month":        0, // Would need additional calculation
 		"claims_last_month":        0, // Would need additional calculation
 		"growth_rate":             0.0, // Would need additional calculation
+		"sla_at_risk_claims":       int(stats.SLAAtRiskClaims),
+		"sla_breached_claims":      int(stats.SLABreachedClaims),
+		"sla_compliance_rate":      stats.SLAComplianceRate,
 	}
 
 	response := dto.ConvertWarrantyClaimStatsToResponse(statsMap)
@@ -501,6 +638,80 @@ func (uc *warrantyClaimUseCase) AddClaimAttachment(ctx context.Context, claimID
 		return nil, fmt.Errorf("failed to add claim attachment: %w", err)
 	}
 
+	// The customer responded to an information request, so restart the SLA clock
+	if claim.SLAPausedAt != nil {
+		claim.ResumeSLA(attachment.UploadedAt)
+		if err := uc.claimRepo.UpdateSLA(ctx, claim); err != nil {
+			return nil, fmt.Errorf("failed to resume claim SLA: %w", err)
+		}
+	}
+
 	response := dto.ConvertClaimAttachmentToResponse(attachment)
 	return response, nil
-}
\ No newline at end of file
+}
+
+// policyForBarcode resolves the warranty policy in effect when the barcode's
+// warranty started. Returns nil when the storefront has no applicable policy.
+func (uc *warrantyClaimUseCase) policyForBarcode(ctx context.Context, barcode *entity.WarrantyBarcode) (*entity.WarrantyPolicyTemplate, error) {
+	startedAt := barcode.CreatedAt
+	if barcode.ActivatedAt != nil {
+		startedAt = *barcode.ActivatedAt
+	}
+	return uc.policyUseCase.ResolvePolicy(ctx, barcode.StorefrontID, barcode.ProductID, startedAt)
+}
+
+// checkClaimPolicy enforces the claim limit and receipt requirement of the
+// warranty policy before a claim is accepted. The purchase invoice recorded
+// at activation, the proof of purchase uploaded at registration or a receipt
+// or invoice attached to the claim counts as a receipt.
+func (uc *warrantyClaimUseCase) checkClaimPolicy(ctx context.Context, claim *entity.WarrantyClaim) error {
+	barcode, err := uc.barcodeRepo.GetByID(ctx, claim.BarcodeID)
+	if err != nil {
+		return fmt.Errorf("failed to get barcode information: %w", err)
+	}
+	if barcode == nil {
+		return fmt.Errorf("warranty barcode not found")
+	}
+
+	policy, err := uc.policyForBarcode(ctx, barcode)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return nil
+	}
+
+	claims, err := uc.claimRepo.GetByBarcodeID(ctx, barcode.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check existing claims: %w", err)
+	}
+	if countClaimsTowardLimit(claims, claim.ID) >= policy.MaxClaimsPerProduct {
+		return fmt.Errorf("claim not allowed: the warranty policy allows at most %d claims per product", policy.MaxClaimsPerProduct)
+	}
+
+	if !policy.RequiresPurchaseReceipt || barcode.PurchaseInvoice != nil || barcode.ProofOfPurchasePath != nil {
+		return nil
+	}
+	attachments, err := uc.claimRepo.GetClaimAttachments(ctx, claim.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get claim attachments: %w", err)
+	}
+	for _, attachment := range attachments {
+		if attachment.AttachmentType == entity.AttachmentTypeReceipt || attachment.AttachmentType == entity.AttachmentTypeInvoice {
+			return nil
+		}
+	}
+	return fmt.Errorf("purchase receipt is required by the warranty policy: attach a receipt or invoice to the claim")
+}
+
+// countClaimsTowardLimit counts the claims that use up the policy's claim
+// allowance, leaving out the claim being checked
+func countClaimsTowardLimit(claims []*entity.WarrantyClaim, exclude uuid.UUID) int {
+	count := 0
+	for _, claim := range claims {
+		if claim.ID != exclude && entity.CountsTowardClaimLimit(claim) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,296 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+)
+
+// WarrantyPolicyUseCase defines the interface for warranty policy templates
+type WarrantyPolicyUseCase interface {
+	// CreatePolicy creates a warranty policy for a storefront
+	CreatePolicy(ctx context.Context, storefrontID, createdBy uuid.UUID, req *dto.CreateWarrantyPolicyRequest) (*dto.WarrantyPolicyResponse, error)
+
+	// GetPolicy retrieves a warranty policy of a storefront
+	GetPolicy(ctx context.Context, storefrontID, policyID uuid.UUID) (*dto.WarrantyPolicyResponse, error)
+
+	// ListPolicies lists the warranty policies of a storefront, newest first
+	ListPolicies(ctx context.Context, filters *repository.WarrantyPolicyFilters) (*dto.WarrantyPolicyListResponse, error)
+
+	// UpdatePolicy updates the terms, claim rules or effective dates of a warranty policy
+	UpdatePolicy(ctx context.Context, storefrontID, policyID uuid.UUID, req *dto.UpdateWarrantyPolicyRequest) (*dto.WarrantyPolicyResponse, error)
+
+	// DeletePolicy deletes a warranty policy
+	DeletePolicy(ctx context.Context, storefrontID, policyID uuid.UUID) error
+
+	// ResolvePolicy returns the most specific policy in effect on the given
+	// day for a product: product, then category, then storefront default.
+	// Returns nil when no policy applies.
+	ResolvePolicy(ctx context.Context, storefrontID, productID uuid.UUID, on time.Time) (*entity.WarrantyPolicyTemplate, error)
+}
+
+// warrantyPolicyUseCase implements the WarrantyPolicyUseCase interface
+type warrantyPolicyUseCase struct {
+	policyRepo   repository.WarrantyPolicyRepository
+	productRepo  repository.ProductRepository
+	categoryRepo repository.ProductCategoryRepository
+	logger       *slog.Logger
+}
+
+// NewWarrantyPolicyUseCase creates a new warranty policy use case
+func NewWarrantyPolicyUseCase(
+	policyRepo repository.WarrantyPolicyRepository,
+	productRepo repository.ProductRepository,
+	categoryRepo repository.ProductCategoryRepository,
+	logger *slog.Logger,
+) WarrantyPolicyUseCase {
+	return &warrantyPolicyUseCase{
+		policyRepo:   policyRepo,
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		logger:       logger,
+	}
+}
+
+// CreatePolicy creates a warranty policy for a storefront
+func (uc *warrantyPolicyUseCase) CreatePolicy(ctx context.Context, storefrontID, createdBy uuid.UUID, req *dto.CreateWarrantyPolicyRequest) (*dto.WarrantyPolicyResponse, error) {
+	policy := entity.NewWarrantyPolicyTemplate(storefrontID, req.Name, req.WarrantyPeriodMonths, req.TermsAndConditions, createdBy)
+	policy.Description = req.Description
+	policy.ProductID = req.ProductID
+	policy.ProductCategoryID = req.ProductCategoryID
+	policy.IsDefault = req.IsDefault
+	policy.Exclusions = req.Exclusions
+	policy.CoverageLimitations = req.CoverageLimitations
+	if req.CoverageType != "" {
+		policy.CoverageType = entity.CoverageType(req.CoverageType)
+	}
+	if req.RequiresPurchaseReceipt != nil {
+		policy.RequiresPurchaseReceipt = *req.RequiresPurchaseReceipt
+	}
+	if req.MaxClaimsPerProduct != nil {
+		policy.MaxClaimsPerProduct = *req.MaxClaimsPerProduct
+	}
+	if req.ClaimProcessingSLAHours != nil {
+		policy.ClaimProcessingSLAHours = *req.ClaimProcessingSLAHours
+	}
+	if req.IsActive != nil {
+		policy.IsActive = *req.IsActive
+	}
+	if req.EffectiveFrom != "" {
+		from, err := parsePolicyDate("effective_from", req.EffectiveFrom)
+		if err != nil {
+			return nil, err
+		}
+		policy.EffectiveFrom = from
+	}
+	if req.EffectiveUntil != nil && *req.EffectiveUntil != "" {
+		until, err := parsePolicyDate("effective_until", *req.EffectiveUntil)
+		if err != nil {
+			return nil, err
+		}
+		policy.EffectiveUntil = &until
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid warranty policy: %w", err)
+	}
+	if err := uc.checkApplicability(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	if err := uc.policyRepo.Create(ctx, policy); err != nil {
+		return nil, fmt.Errorf("failed to create warranty policy: %w", err)
+	}
+
+	uc.logger.Info("Warranty policy created",
+		"policy_id", policy.ID,
+		"storefront_id", storefrontID,
+		"scope", policy.Scope(),
+		"created_by", createdBy,
+	)
+
+	return dto.ConvertWarrantyPolicyToResponse(policy), nil
+}
+
+// GetPolicy retrieves a warranty policy of a storefront
+func (uc *warrantyPolicyUseCase) GetPolicy(ctx context.Context, storefrontID, policyID uuid.UUID) (*dto.WarrantyPolicyResponse, error) {
+	policy, err := uc.getPolicy(ctx, storefrontID, policyID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ConvertWarrantyPolicyToResponse(policy), nil
+}
+
+// ListPolicies lists the warranty policies of a storefront, newest first
+func (uc *warrantyPolicyUseCase) ListPolicies(ctx context.Context, filters *repository.WarrantyPolicyFilters) (*dto.WarrantyPolicyListResponse, error) {
+	if filters.CoverageType != nil && !filters.CoverageType.Valid() {
+		return nil, fmt.Errorf("invalid coverage type: %s", *filters.CoverageType)
+	}
+
+	policies, err := uc.policyRepo.List(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list warranty policies: %w", err)
+	}
+
+	total, err := uc.policyRepo.Count(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count warranty policies: %w", err)
+	}
+
+	response := &dto.WarrantyPolicyListResponse{
+		Policies:   make([]dto.WarrantyPolicyResponse, 0, len(policies)),
+		Pagination: buildOrderPagination(filters.Page, filters.PageSize, total),
+	}
+	for _, policy := range policies {
+		response.Policies = append(response.Policies, *dto.ConvertWarrantyPolicyToResponse(policy))
+	}
+
+	return response, nil
+}
+
+// UpdatePolicy updates the terms, claim rules or effective dates of a warranty policy
+func (uc *warrantyPolicyUseCase) UpdatePolicy(ctx context.Context, storefrontID, policyID uuid.UUID, req *dto.UpdateWarrantyPolicyRequest) (*dto.WarrantyPolicyResponse, error) {
+	policy, err := uc.getPolicy(ctx, storefrontID, policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		policy.Name = *req.Name
+	}
+	if req.Description != nil {
+		policy.Description = req.Description
+	}
+	if req.WarrantyPeriodMonths != nil {
+		policy.WarrantyPeriodMonths = *req.WarrantyPeriodMonths
+	}
+	if req.CoverageType != nil {
+		policy.CoverageType = entity.CoverageType(*req.CoverageType)
+	}
+	if req.TermsAndConditions != nil {
+		policy.TermsAndConditions = *req.TermsAndConditions
+	}
+	if req.Exclusions != nil {
+		policy.Exclusions = req.Exclusions
+	}
+	if req.CoverageLimitations != nil {
+		policy.CoverageLimitations = req.CoverageLimitations
+	}
+	if req.RequiresPurchaseReceipt != nil {
+		policy.RequiresPurchaseReceipt = *req.RequiresPurchaseReceipt
+	}
+	if req.MaxClaimsPerProduct != nil {
+		policy.MaxClaimsPerProduct = *req.MaxClaimsPerProduct
+	}
+	if req.ClaimProcessingSLAHours != nil {
+		policy.ClaimProcessingSLAHours = *req.ClaimProcessingSLAHours
+	}
+	if req.IsActive != nil {
+		policy.IsActive = *req.IsActive
+	}
+	if req.EffectiveFrom != nil {
+		from, err := parsePolicyDate("effective_from", *req.EffectiveFrom)
+		if err != nil {
+			return nil, err
+		}
+		policy.EffectiveFrom = from
+	}
+	if req.EffectiveUntil != nil {
+		if *req.EffectiveUntil == "" {
+			policy.EffectiveUntil = nil
+		} else {
+			until, err := parsePolicyDate("effective_until", *req.EffectiveUntil)
+			if err != nil {
+				return nil, err
+			}
+			policy.EffectiveUntil = &until
+		}
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid warranty policy: %w", err)
+	}
+
+	if err := uc.policyRepo.Update(ctx, policy); err != nil {
+		return nil, fmt.Errorf("failed to update warranty policy: %w", err)
+	}
+
+	uc.logger.Info("Warranty policy updated", "policy_id", policy.ID, "storefront_id", storefrontID)
+
+	return dto.ConvertWarrantyPolicyToResponse(policy), nil
+}
+
+// DeletePolicy deletes a warranty policy
+func (uc *warrantyPolicyUseCase) DeletePolicy(ctx context.Context, storefrontID, policyID uuid.UUID) error {
+	if _, err := uc.getPolicy(ctx, storefrontID, policyID); err != nil {
+		return err
+	}
+
+	if err := uc.policyRepo.Delete(ctx, storefrontID, policyID); err != nil {
+		return fmt.Errorf("failed to delete warranty policy: %w", err)
+	}
+
+	uc.logger.Info("Warranty policy deleted", "policy_id", policyID, "storefront_id", storefrontID)
+	return nil
+}
+
+// ResolvePolicy returns the most specific policy in effect on the given day
+// for a product. Returns nil when no policy applies.
+func (uc *warrantyPolicyUseCase) ResolvePolicy(ctx context.Context, storefrontID, productID uuid.UUID, on time.Time) (*entity.WarrantyPolicyTemplate, error) {
+	product, err := uc.productRepo.GetByID(ctx, productID, nil)
+	if err != nil || product == nil {
+		return nil, fmt.Errorf("product %s not found", productID)
+	}
+
+	policies, err := uc.policyRepo.GetApplicable(ctx, storefrontID, productID, product.CategoryID, on)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warranty policies: %w", err)
+	}
+
+	return entity.SelectWarrantyPolicy(policies, productID, product.CategoryID, on), nil
+}
+
+// getPolicy loads a policy of the storefront
+func (uc *warrantyPolicyUseCase) getPolicy(ctx context.Context, storefrontID, policyID uuid.UUID) (*entity.WarrantyPolicyTemplate, error) {
+	policy, err := uc.policyRepo.GetByID(ctx, storefrontID, policyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warranty policy: %w", err)
+	}
+	if policy == nil {
+		return nil, fmt.Errorf("warranty policy not found")
+	}
+	return policy, nil
+}
+
+// checkApplicability verifies that the product or category a policy applies to exists
+func (uc *warrantyPolicyUseCase) checkApplicability(ctx context.Context, policy *entity.WarrantyPolicyTemplate) error {
+	switch policy.Scope() {
+	case entity.PolicyScopeProduct:
+		product, err := uc.productRepo.GetByID(ctx, *policy.ProductID, nil)
+		if err != nil || product == nil {
+			return fmt.Errorf("product %s not found", *policy.ProductID)
+		}
+	case entity.PolicyScopeCategory:
+		category, err := uc.categoryRepo.GetByID(ctx, *policy.ProductCategoryID, nil)
+		if err != nil || category == nil {
+			return fmt.Errorf("product category %s not found", *policy.ProductCategoryID)
+		}
+	}
+	return nil
+}
+
+// parsePolicyDate parses a YYYY-MM-DD policy date
+func parsePolicyDate(field, value string) (time.Time, error) {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: expected YYYY-MM-DD", field)
+	}
+	return date, nil
+}
@@ -0,0 +1,430 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	domainerrors "github.com/kirimku/smartseller-backend/internal/domain/errors"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/pkg/email"
+	"github.com/kirimku/smartseller-backend/pkg/storage"
+)
+
+const (
+	// warrantyReceiptPrefix is where proofs of purchase are stored
+	warrantyReceiptPrefix = "warranty-receipts/"
+	// warrantyReceiptScanTimeout bounds the scan of a proof of purchase
+	warrantyReceiptScanTimeout = time.Minute
+	// MaxWarrantyReceiptSize caps the proof of purchase uploaded at registration
+	MaxWarrantyReceiptSize = entity.MaxFileSizeDocument
+)
+
+// warrantyReceiptExtensions maps the accepted proof of purchase types to the
+// extension they are stored with
+var warrantyReceiptExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// WarrantyReceiptUpload is a proof of purchase uploaded with a warranty registration
+type WarrantyReceiptUpload struct {
+	Filename string
+	Size     int64
+	Content  io.Reader
+}
+
+// WarrantyRegistrationUseCase lets customers register the warranty of a
+// product they bought by activating its distributed barcode
+type WarrantyRegistrationUseCase interface {
+	// RegisterWarranty activates a distributed barcode of the storefront for
+	// the signed-in customer who bought the product. The receipt is required
+	// when the warranty policy asks for one.
+	RegisterWarranty(ctx context.Context, storefrontID, customerID uuid.UUID, req *dto.CustomerWarrantyRegistrationRequest, receipt *WarrantyReceiptUpload) (*dto.CustomerWarrantyRegistrationResponse, error)
+
+	// ScanWarranty looks up a scanned barcode or warranty QR code for a
+	// signed-in customer. Warranties registered to other customers are not found.
+	ScanWarranty(ctx context.Context, storefrontID, customerID uuid.UUID, req *dto.MobileWarrantyScanRequest) (*dto.MobileWarrantyScanResponse, error)
+}
+
+type warrantyRegistrationUseCase struct {
+	barcodeRepo      repository.WarrantyBarcodeRepository
+	productRepo      repository.ProductRepository
+	customerRepo     repository.CustomerRepository
+	policyUseCase    WarrantyPolicyUseCase
+	storage          storage.Storage
+	scanner          AttachmentScanner
+	emailSender      email.EmailSender
+	activationWindow time.Duration
+	logger           *slog.Logger
+}
+
+// NewWarrantyRegistrationUseCase creates a new warranty registration use case.
+// Distributed barcodes can be registered until the activation window since
+// their distribution has passed; a zero window keeps registration open.
+// Without a scanner proofs of purchase are stored unscanned.
+func NewWarrantyRegistrationUseCase(
+	barcodeRepo repository.WarrantyBarcodeRepository,
+	productRepo repository.ProductRepository,
+	customerRepo repository.CustomerRepository,
+	policyUseCase WarrantyPolicyUseCase,
+	store storage.Storage,
+	scanner AttachmentScanner,
+	emailSender email.EmailSender,
+	activationWindow time.Duration,
+	logger *slog.Logger,
+) WarrantyRegistrationUseCase {
+	if scanner == nil {
+		logger.Warn("No virus scanner configured, warranty proofs of purchase will be stored unscanned")
+	}
+
+	return &warrantyRegistrationUseCase{
+		barcodeRepo:      barcodeRepo,
+		productRepo:      productRepo,
+		customerRepo:     customerRepo,
+		policyUseCase:    policyUseCase,
+		storage:          store,
+		scanner:          scanner,
+		emailSender:      emailSender,
+		activationWindow: activationWindow,
+		logger:           logger,
+	}
+}
+
+// RegisterWarranty activates a distributed barcode for the signed-in customer
+// and emails them the warranty certificate. Registering through the customer's
+// own session keeps anyone from binding warranties to another account.
+func (uc *warrantyRegistrationUseCase) RegisterWarranty(ctx context.Context, storefrontID, customerID uuid.UUID, req *dto.CustomerWarrantyRegistrationRequest, receipt *WarrantyReceiptUpload) (*dto.CustomerWarrantyRegistrationResponse, error) {
+	barcode, err := uc.findBarcode(ctx, &storefrontID, req.BarcodeValue)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := uc.getProduct(ctx, barcode.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	// A barcode registered for another product is not confirmed to exist
+	if !strings.EqualFold(product.SKU, strings.TrimSpace(req.ProductSKU)) {
+		return nil, fmt.Errorf("warranty not found")
+	}
+
+	if barcode.Status == entity.BarcodeStatusActivated || barcode.Status == entity.BarcodeStatusUsed {
+		return nil, fmt.Errorf("a warranty registration already exists for barcode %s", barcode.BarcodeNumber)
+	}
+
+	now := time.Now()
+	if err := barcode.CheckRegistration(req.PurchaseDate, uc.activationWindow, now); err != nil {
+		return nil, err
+	}
+
+	policy, err := uc.policyUseCase.ResolvePolicy(ctx, barcode.StorefrontID, barcode.ProductID, now)
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil && policy.RequiresPurchaseReceipt && receipt == nil {
+		return nil, fmt.Errorf("proof of purchase upload is required by the warranty policy")
+	}
+
+	customer, err := uc.customerRepo.GetByID(ctx, storefrontID, customerID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrCustomerNotFound) {
+			return nil, fmt.Errorf("customer not found")
+		}
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	if err := barcode.Register(customer.ID, req.PurchaseDate, req.RetailerName, req.InvoiceNumber, uc.activationWindow, now); err != nil {
+		return nil, err
+	}
+
+	if receipt != nil {
+		receiptPath, err := uc.storeReceipt(ctx, barcode.ID, receipt)
+		if err != nil {
+			return nil, err
+		}
+		barcode.ProofOfPurchasePath = &receiptPath
+	}
+
+	registered, err := uc.barcodeRepo.Register(ctx, barcode)
+	if err == nil && !registered {
+		err = fmt.Errorf("a warranty registration already exists for barcode %s", barcode.BarcodeNumber)
+	}
+	if err != nil {
+		if barcode.ProofOfPurchasePath != nil {
+			if delErr := uc.storage.Delete(ctx, *barcode.ProofOfPurchasePath); delErr != nil {
+				uc.logger.Error("Failed to remove orphaned proof of purchase", "path", *barcode.ProofOfPurchasePath, "error", delErr)
+			}
+		}
+		return nil, err
+	}
+
+	uc.sendCertificate(barcode, product, customer)
+
+	response := dto.ConvertToCustomerWarrantyRegistrationResponse(barcode, product, customer.ID, customerRegistrationInfo(customer))
+	return &response, nil
+}
+
+// ScanWarranty looks up a scanned barcode or warranty QR code and offers the
+// actions its status allows
+func (uc *warrantyRegistrationUseCase) ScanWarranty(ctx context.Context, storefrontID, customerID uuid.UUID, req *dto.MobileWarrantyScanRequest) (*dto.MobileWarrantyScanResponse, error) {
+	barcode, err := uc.findBarcode(ctx, &storefrontID, scannedBarcodeNumber(req.ScannedData))
+	if err != nil {
+		return nil, err
+	}
+	if barcode.CustomerID != nil && *barcode.CustomerID != customerID {
+		return nil, fmt.Errorf("warranty not found")
+	}
+
+	product, err := uc.getProduct(ctx, barcode.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	barcode.ComputeFields()
+	info := &dto.MobileWarrantyInfo{
+		ID:            barcode.ID.String(),
+		ProductName:   product.Name,
+		ProductModel:  product.SKU,
+		SerialNumber:  barcode.BarcodeNumber,
+		Status:        barcode.Status.String(),
+		IsValid:       barcode.IsValidForClaim(),
+		WarrantyTerms: fmt.Sprintf("%d-month warranty", barcode.WarrantyPeriodMonths),
+	}
+	if barcode.PurchaseDate != nil {
+		info.PurchaseDate = *barcode.PurchaseDate
+	}
+	if barcode.ExpiryDate != nil {
+		info.ExpiryDate = *barcode.ExpiryDate
+	}
+	if barcode.DaysRemaining != nil {
+		info.DaysRemaining = *barcode.DaysRemaining
+	}
+	if barcode.PurchaseLocation != nil {
+		info.Retailer = *barcode.PurchaseLocation
+	}
+
+	return &dto.MobileWarrantyScanResponse{
+		Success:     true,
+		ScanType:    req.ScanType,
+		ScannedData: req.ScannedData,
+		Warranty:    info,
+		Actions:     uc.scanActions(barcode, time.Now()),
+		Metadata: map[string]interface{}{
+			"scan_timestamp": time.Now(),
+			"app_version":    req.DeviceInfo.AppVersion,
+		},
+	}, nil
+}
+
+// scanActions lists what a customer can do with a scanned warranty
+func (uc *warrantyRegistrationUseCase) scanActions(barcode *entity.WarrantyBarcode, now time.Time) []dto.MobileWarrantyAction {
+	var actions []dto.MobileWarrantyAction
+	switch barcode.Status {
+	case entity.BarcodeStatusDistributed:
+		deadline := barcode.RegistrationDeadline(uc.activationWindow)
+		actions = append(actions, dto.MobileWarrantyAction{
+			Type:        "register_warranty",
+			Label:       "Register Warranty",
+			Description: "Activate the warranty of your product",
+			Enabled:     deadline == nil || !now.After(*deadline),
+		})
+	case entity.BarcodeStatusActivated, entity.BarcodeStatusUsed, entity.BarcodeStatusExpired:
+		actions = append(actions,
+			dto.MobileWarrantyAction{
+				Type:        "view_details",
+				Label:       "View Full Details",
+				Description: "See complete warranty information",
+				Enabled:     true,
+			},
+			dto.MobileWarrantyAction{
+				Type:        "file_claim",
+				Label:       "File Warranty Claim",
+				Description: "Report an issue with your product",
+				Enabled:     barcode.IsValidForClaim(),
+			},
+		)
+	}
+
+	return append(actions, dto.MobileWarrantyAction{
+		Type:        "contact_support",
+		Label:       "Contact Support",
+		Description: "Get help from our support team",
+		Enabled:     true,
+	})
+}
+
+// findBarcode retrieves a barcode of the storefront, if any, by its number.
+// The number must be in the format barcodes are generated with.
+func (uc *warrantyRegistrationUseCase) findBarcode(ctx context.Context, storefrontID *uuid.UUID, barcodeNumber string) (*entity.WarrantyBarcode, error) {
+	candidate := entity.WarrantyBarcode{BarcodeNumber: strings.ToUpper(strings.TrimSpace(barcodeNumber))}
+	if err := candidate.ValidateBarcodeFormat(); err != nil {
+		return nil, err
+	}
+
+	barcode, err := uc.barcodeRepo.GetByBarcodeNumber(ctx, candidate.BarcodeNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warranty barcode: %w", err)
+	}
+	if barcode == nil || (storefrontID != nil && barcode.StorefrontID != *storefrontID) {
+		return nil, fmt.Errorf("warranty not found")
+	}
+
+	return barcode, nil
+}
+
+// getProduct retrieves the product a barcode was issued for
+func (uc *warrantyRegistrationUseCase) getProduct(ctx context.Context, productID uuid.UUID) (*entity.Product, error) {
+	products, err := uc.productRepo.GetByIDs(ctx, []uuid.UUID{productID}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warranty product: %w", err)
+	}
+	if len(products) == 0 {
+		return nil, fmt.Errorf("warranty product not found")
+	}
+	return products[0], nil
+}
+
+// customerRegistrationInfo returns the contact details a registration is
+// confirmed with
+func customerRegistrationInfo(customer *entity.Customer) dto.CustomerRegistrationInfo {
+	var info dto.CustomerRegistrationInfo
+	if customer.FirstName != nil {
+		info.FirstName = *customer.FirstName
+	}
+	if customer.LastName != nil {
+		info.LastName = *customer.LastName
+	}
+	if customer.Email != nil {
+		info.Email = *customer.Email
+	}
+	if customer.Phone != nil {
+		info.PhoneNumber = *customer.Phone
+	}
+	info.DateOfBirth = customer.DateOfBirth
+	return info
+}
+
+// storeReceipt checks, scans and stores a proof of purchase and returns its
+// storage key. The content type is sniffed rather than trusted.
+func (uc *warrantyRegistrationUseCase) storeReceipt(ctx context.Context, barcodeID uuid.UUID, receipt *WarrantyReceiptUpload) (string, error) {
+	if receipt.Size > MaxWarrantyReceiptSize {
+		return "", fmt.Errorf("proof of purchase must not exceed %d MB", MaxWarrantyReceiptSize/(1024*1024))
+	}
+	content, err := io.ReadAll(io.LimitReader(receipt.Content, MaxWarrantyReceiptSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read proof of purchase: %w", err)
+	}
+	if len(content) > MaxWarrantyReceiptSize {
+		return "", fmt.Errorf("proof of purchase must not exceed %d MB", MaxWarrantyReceiptSize/(1024*1024))
+	}
+	if len(content) == 0 {
+		return "", fmt.Errorf("proof of purchase must not be empty")
+	}
+
+	contentType := http.DetectContentType(content)
+	ext, ok := warrantyReceiptExtensions[contentType]
+	if !ok {
+		return "", fmt.Errorf("invalid proof of purchase: %s files are not accepted, upload a JPEG, PNG, WebP or PDF", contentType)
+	}
+
+	if uc.scanner != nil {
+		scanCtx, cancel := context.WithTimeout(ctx, warrantyReceiptScanTimeout)
+		result, err := uc.scanner.Scan(scanCtx, bytes.NewReader(content))
+		cancel()
+		if err != nil {
+			return "", fmt.Errorf("failed to scan proof of purchase: %w", err)
+		}
+		if result.Infected {
+			uc.logger.Warn("Rejected infected proof of purchase", "barcode_id", barcodeID, "filename", receipt.Filename, "signature", result.Signature)
+			return "", fmt.Errorf("invalid proof of purchase: the file did not pass the virus scan")
+		}
+	}
+
+	key := fmt.Sprintf("%s%s/%s%s", warrantyReceiptPrefix, barcodeID, uuid.New(), ext)
+	if err := uc.storage.Put(ctx, key, bytes.NewReader(content), int64(len(content)), contentType); err != nil {
+		return "", fmt.Errorf("failed to store proof of purchase: %w", err)
+	}
+	return key, nil
+}
+
+// sendCertificate emails the warranty certificate to the customer. The
+// registration stands when the email cannot be sent.
+func (uc *warrantyRegistrationUseCase) sendCertificate(barcode *entity.WarrantyBarcode, product *entity.Product, customer *entity.Customer) {
+	if uc.emailSender == nil || customer.Email == nil {
+		return
+	}
+
+	name := customer.GetFullName()
+	expiry := "-"
+	if barcode.ExpiryDate != nil {
+		expiry = barcode.ExpiryDate.Format("02 Jan 2006")
+	}
+	retailer := "-"
+	if barcode.PurchaseLocation != nil {
+		retailer = *barcode.PurchaseLocation
+	}
+
+	subject := fmt.Sprintf("Your warranty certificate for %s", product.Name)
+	htmlBody := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+    <p>Hi %s,</p>
+    <p>Your warranty is registered. Keep this certificate for your records.</p>
+    <div style="border: 2px solid #333; padding: 16px; max-width: 480px;">
+        <h2 style="margin-top: 0;">Warranty Certificate</h2>
+        <table>
+            <tr><td>Warranty number</td><td><strong>%s</strong></td></tr>
+            <tr><td>Product</td><td>%s (%s)</td></tr>
+            <tr><td>Registered to</td><td>%s</td></tr>
+            <tr><td>Purchased from</td><td>%s</td></tr>
+            <tr><td>Purchase date</td><td>%s</td></tr>
+            <tr><td>Warranty period</td><td>%d months</td></tr>
+            <tr><td>Valid until</td><td>%s</td></tr>
+        </table>
+    </div>
+    <p>To file a warranty claim, visit <a href="%s">%s</a>.</p>
+</body>
+</html>`,
+		html.EscapeString(name),
+		html.EscapeString(barcode.BarcodeNumber),
+		html.EscapeString(product.Name),
+		html.EscapeString(product.SKU),
+		html.EscapeString(name),
+		html.EscapeString(retailer),
+		barcode.PurchaseDate.Format("02 Jan 2006"),
+		barcode.WarrantyPeriodMonths,
+		expiry,
+		html.EscapeString(barcode.QRCodeData),
+		html.EscapeString(barcode.QRCodeData),
+	)
+
+	if err := uc.emailSender.SendEmail(*customer.Email, subject, htmlBody); err != nil {
+		uc.logger.Error("Failed to send warranty certificate email", "barcode_id", barcode.ID, "customer_id", customer.ID, "error", err)
+	}
+}
+
+// scannedBarcodeNumber extracts the barcode number from scanned data, which
+// is either the number itself or the warranty URL encoded in its QR code
+func scannedBarcodeNumber(scanned string) string {
+	scanned = strings.TrimSpace(scanned)
+	if u, err := url.Parse(scanned); err == nil && u.Scheme != "" && u.Host != "" {
+		return path.Base(u.Path)
+	}
+	return scanned
+}
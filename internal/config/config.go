@@ -32,6 +32,9 @@ type Config struct {
 	BaseURL        string
 	FrontendURL    string
 	AllowedOrigins []string
+	// TrustedProxies lists the proxy addresses or CIDRs whose forwarding
+	// headers are believed when resolving the client IP
+	TrustedProxies []string
 	Environment    string
 	Version        string
 	RateLimit      struct {
@@ -50,6 +53,8 @@ type Config struct {
 		XenditApiKey      string
 		XenditBaseURL     string
 		XenditCallbackURL string
+		// XenditCallbackToken verifies Xendit callbacks; they are rejected while it is empty
+		XenditCallbackToken string
 		// DurianPay configuration
 		DurianPay DurianPayConfig
 		// Legacy DurianPay fields for backward compatibility
@@ -63,6 +68,93 @@ type Config struct {
 		InvoiceExpiryHours    int
 		DefaultEWalletChannel string
 	}
+	// Checkout configuration
+	Checkout struct {
+		ReservationTTL           time.Duration // How long stock stays reserved for an unpaid order
+		ReservationSweepInterval time.Duration // How often expired reservations are released
+		ShippingRateCacheTTL     time.Duration // How long courier rate quotes are reused for the same route and weight
+	}
+	// Courier webhook signing secrets. An empty secret disables signature validation.
+	// Failed deliveries are retried with exponential backoff until they are dead-lettered.
+	CourierWebhooks struct {
+		JNESecret          string
+		SiCepatSecret      string
+		NinjaVanSecret     string
+		RetryMaxAttempts   int
+		RetryBaseDelay     time.Duration
+		RetryMaxDelay      time.Duration
+		RetrySweepInterval time.Duration
+	}
+	// Warranty claim SLA. Claims without a warranty policy get the default SLA;
+	// claims that used up the at-risk share of their SLA are escalated.
+	ClaimSLA struct {
+		DefaultHours  int
+		AtRiskRatio   float64
+		SweepInterval time.Duration
+	}
+	// Object storage for uploaded files, on local disk or in an S3-compatible
+	// bucket
+	Storage struct {
+		Driver            string
+		LocalPath         string
+		S3Endpoint        string
+		S3Region          string
+		S3Bucket          string
+		S3AccessKeyID     string
+		S3SecretAccessKey string
+		S3PathStyle       bool
+	}
+	// clamd scanning claim attachments. Without an address attachments stay
+	// pending and cannot be downloaded. Attachments whose scan could not run
+	// are retried every sweep interval.
+	ClamAV struct {
+		Address       string
+		Timeout       time.Duration
+		SweepInterval time.Duration
+	}
+	// Customer sessions. Logging in beyond MaxSessions revokes the least
+	// recently used session.
+	CustomerSessions struct {
+		MaxSessions int
+	}
+	// Default CAPTCHA for customer endpoints. Storefronts can set their own
+	// provider and site secret in storefront_configs.
+	Captcha struct {
+		Provider  string
+		SecretKey string
+		Mode      string
+		MinScore  float64
+	}
+	// Default rate limit per client on customer endpoints. Login, registration
+	// and 2FA have tighter limits; storefronts can override any of them in
+	// storefront_configs.
+	CustomerRateLimit struct {
+		Limit         int
+		WindowSeconds int
+	}
+	// Customer TOTP. The key seals stored secrets; the issuer names the
+	// account in authenticator apps when the storefront has no name.
+	CustomerTwoFactor struct {
+		EncryptionKey string
+		Issuer        string
+	}
+	// Public warranty lookups. An IP asking for this many unknown barcodes
+	// within the window is blocked for the window, to stop barcode guessing.
+	WarrantyLookup struct {
+		MaxMisses  int
+		MissWindow time.Duration
+	}
+	// Customer warranty registration. A distributed barcode can be registered
+	// until this long after it was distributed.
+	WarrantyRegistration struct {
+		ActivationWindow time.Duration
+	}
+	// Redis connection shared by API replicas. An empty URL keeps caches in memory.
+	Redis struct {
+		URL      string
+		Password string
+		DB       int
+	}
 	// Location data structures
 	LocationData struct {
 		POSTCODES        map[string]map[string]map[string][]string
@@ -364,6 +456,15 @@ func LoadConfig() error {
 		AppConfig.AllowedOrigins = strings.Split(allowedOrigins, ",")
 	}
 
+	// Configure the proxies trusted to report the client IP. Without any,
+	// X-Forwarded-For is ignored and the client IP is the peer address.
+	AppConfig.TrustedProxies = nil
+	for _, proxy := range getEnvAsSlice("TRUSTED_PROXIES", ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			AppConfig.TrustedProxies = append(AppConfig.TrustedProxies, proxy)
+		}
+	}
+
 	// Configure rate limiter
 	requestsPerSecond := os.Getenv("RATE_LIMIT_PER_SECOND")
 	if requestsPerSecond == "" {
@@ -467,6 +568,7 @@ func LoadConfig() error {
 	AppConfig.Payment.XenditApiKey = getEnvWithDefault("XENDIT_API_KEY", "")
 	AppConfig.Payment.XenditBaseURL = getEnvWithDefault("XENDIT_BASE_URL", "https://api.xendit.co")
 	AppConfig.Payment.XenditCallbackURL = getEnvWithDefault("XENDIT_CALLBACK_URL", fmt.Sprintf("%s/webhooks/payments/xendit", AppConfig.BaseURL))
+	AppConfig.Payment.XenditCallbackToken = getEnvWithDefault("XENDIT_CALLBACK_TOKEN", "")
 
 	// Configure DurianPay settings
 	AppConfig.Payment.DurianPay.APIKey = getEnvWithDefault("DURIANPAY_API_KEY", "")
@@ -505,6 +607,57 @@ func LoadConfig() error {
 	AppConfig.Payment.InvoiceExpiryHours = getEnvAsInt("INVOICE_EXPIRY_HOURS", 24)
 	AppConfig.Payment.DefaultEWalletChannel = getEnvWithDefault("DEFAULT_EWALLET_CHANNEL", "SHOPEEPAY")
 
+	// Configure checkout stock reservations
+	AppConfig.Checkout.ReservationTTL = getEnvAsDuration("CHECKOUT_RESERVATION_TTL", 60*time.Minute)
+	AppConfig.Checkout.ReservationSweepInterval = getEnvAsDuration("CHECKOUT_RESERVATION_SWEEP_INTERVAL", time.Minute)
+	AppConfig.Checkout.ShippingRateCacheTTL = getEnvAsDuration("SHIPPING_RATE_CACHE_TTL", 30*time.Minute)
+
+	// Configure courier webhook secrets
+	AppConfig.CourierWebhooks.JNESecret = getEnvWithDefault("JNE_WEBHOOK_SECRET", "")
+	AppConfig.CourierWebhooks.SiCepatSecret = getEnvWithDefault("SICEPAT_WEBHOOK_SECRET", "")
+	AppConfig.CourierWebhooks.NinjaVanSecret = getEnvWithDefault("NINJAVAN_WEBHOOK_SECRET", "")
+	AppConfig.CourierWebhooks.RetryMaxAttempts = getEnvAsInt("COURIER_WEBHOOK_RETRY_MAX_ATTEMPTS", 8)
+	AppConfig.CourierWebhooks.RetryBaseDelay = getEnvAsDuration("COURIER_WEBHOOK_RETRY_BASE_DELAY", time.Minute)
+	AppConfig.CourierWebhooks.RetryMaxDelay = getEnvAsDuration("COURIER_WEBHOOK_RETRY_MAX_DELAY", 6*time.Hour)
+	AppConfig.CourierWebhooks.RetrySweepInterval = getEnvAsDuration("COURIER_WEBHOOK_RETRY_SWEEP_INTERVAL", time.Minute)
+
+	// Configure warranty claim SLA tracking
+	AppConfig.ClaimSLA.DefaultHours = getEnvAsInt("CLAIM_SLA_DEFAULT_HOURS", 72)
+	AppConfig.ClaimSLA.AtRiskRatio = getEnvAsFloat("CLAIM_SLA_AT_RISK_RATIO", 0.8)
+	AppConfig.ClaimSLA.SweepInterval = getEnvAsDuration("CLAIM_SLA_SWEEP_INTERVAL", 5*time.Minute)
+
+	// Configure attachment storage and virus scanning
+	AppConfig.Storage.Driver = getEnvWithDefault("STORAGE_DRIVER", "local")
+	AppConfig.Storage.LocalPath = getEnvWithDefault("STORAGE_LOCAL_PATH", "./uploads")
+	AppConfig.Storage.S3Endpoint = getEnvWithDefault("STORAGE_S3_ENDPOINT", "")
+	AppConfig.Storage.S3Region = getEnvWithDefault("STORAGE_S3_REGION", "ap-southeast-1")
+	AppConfig.Storage.S3Bucket = getEnvWithDefault("STORAGE_S3_BUCKET", "")
+	AppConfig.Storage.S3AccessKeyID = getEnvWithDefault("STORAGE_S3_ACCESS_KEY_ID", "")
+	AppConfig.Storage.S3SecretAccessKey = getEnvWithDefault("STORAGE_S3_SECRET_ACCESS_KEY", "")
+	AppConfig.Storage.S3PathStyle = getEnvAsBool("STORAGE_S3_PATH_STYLE", false)
+	AppConfig.ClamAV.Address = getEnvWithDefault("CLAMAV_ADDRESS", "")
+	AppConfig.ClamAV.Timeout = getEnvAsDuration("CLAMAV_TIMEOUT", 2*time.Minute)
+	AppConfig.ClamAV.SweepInterval = getEnvAsDuration("CLAMAV_SWEEP_INTERVAL", 5*time.Minute)
+
+	// Configure customer sessions
+	AppConfig.CustomerSessions.MaxSessions = getEnvAsInt("CUSTOMER_MAX_SESSIONS", 5)
+	AppConfig.Captcha.Provider = getEnvWithDefault("CAPTCHA_PROVIDER", "")
+	AppConfig.Captcha.SecretKey = getEnvWithDefault("CAPTCHA_SECRET_KEY", "")
+	AppConfig.Captcha.Mode = getEnvWithDefault("CAPTCHA_MODE", "adaptive")
+	AppConfig.Captcha.MinScore = getEnvAsFloat("CAPTCHA_MIN_SCORE", 0.5)
+	AppConfig.CustomerRateLimit.Limit = getEnvAsInt("CUSTOMER_RATE_LIMIT", 60)
+	AppConfig.CustomerRateLimit.WindowSeconds = getEnvAsInt("CUSTOMER_RATE_LIMIT_WINDOW_SECONDS", 60)
+	AppConfig.CustomerTwoFactor.EncryptionKey = getEnvWithDefault("CUSTOMER_TWO_FACTOR_KEY", os.Getenv("SESSION_KEY"))
+	AppConfig.CustomerTwoFactor.Issuer = getEnvWithDefault("CUSTOMER_TWO_FACTOR_ISSUER", "SmartSeller")
+	AppConfig.WarrantyLookup.MaxMisses = getEnvAsInt("WARRANTY_LOOKUP_MAX_MISSES", 10)
+	AppConfig.WarrantyLookup.MissWindow = getEnvAsDuration("WARRANTY_LOOKUP_MISS_WINDOW", 15*time.Minute)
+	AppConfig.WarrantyRegistration.ActivationWindow = getEnvAsDuration("WARRANTY_ACTIVATION_WINDOW", 365*24*time.Hour)
+
+	// Configure Redis
+	AppConfig.Redis.URL = getEnvWithDefault("REDIS_URL", "")
+	AppConfig.Redis.Password = getEnvWithDefault("REDIS_PASSWORD", "")
+	AppConfig.Redis.DB = getEnvAsInt("REDIS_DB", 0)
+
 	// Configure app-specific settings
 	AppConfig.App.WeightDiscrepancyThreshold = getEnvAsFloat("WEIGHT_DISCREPANCY_THRESHOLD", 0.1) // Default 0.1 kg
 	AppConfig.App.FeeDiscrepancyThreshold = getEnvAsFloat("FEE_DISCREPANCY_THRESHOLD", 1000.0)    // Default 1000 currency units
@@ -855,5 +1008,16 @@ func (c *Config) ValidateDurianPayConfig() error {
 	if c.Payment.DurianPay.Environment != "sandbox" && c.Payment.DurianPay.Environment != "production" {
 		return fmt.Errorf("DURIANPAY_ENVIRONMENT must be either 'sandbox' or 'production'")
 	}
+	if c.Payment.DurianPay.EnableSNAP {
+		if c.Payment.DurianPay.ClientKey == "" {
+			return fmt.Errorf("DURIANPAY_CLIENT_KEY is required when SNAP is enabled")
+		}
+		if c.Payment.DurianPay.MerchantID == "" {
+			return fmt.Errorf("DURIANPAY_MERCHANT_ID is required when SNAP is enabled")
+		}
+		if c.Payment.DurianPay.PrivateKey == "" {
+			return fmt.Errorf("DURIANPAY_PRIVATE_KEY is required when SNAP is enabled")
+		}
+	}
 	return nil
 }
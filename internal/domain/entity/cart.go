@@ -0,0 +1,176 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// MaxCartItemQuantity caps the quantity of a single cart line
+const MaxCartItemQuantity = 999
+
+// Cart represents a storefront shopping cart owned by a customer or, for
+// guests, by an anonymous session
+type Cart struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	StorefrontID uuid.UUID  `json:"storefront_id" db:"storefront_id"`
+	CustomerID   *uuid.UUID `json:"customer_id" db:"customer_id"`
+	SessionID    *string    `json:"session_id" db:"session_id"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Relationships
+	Items []*CartItem `json:"items,omitempty" db:"-"`
+}
+
+// CartItem represents a product (and optionally a variant) in a cart
+type CartItem struct {
+	ID               uuid.UUID       `json:"id" db:"id"`
+	CartID           uuid.UUID       `json:"cart_id" db:"cart_id"`
+	ProductID        uuid.UUID       `json:"product_id" db:"product_id"`
+	ProductVariantID *uuid.UUID      `json:"product_variant_id" db:"product_variant_id"`
+	Quantity         int             `json:"quantity" db:"quantity"`
+	UnitPrice        decimal.Decimal `json:"unit_price" db:"unit_price"` // Price when the item was added
+	CreatedAt        time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// NewCustomerCart creates an empty cart for a signed-in customer
+func NewCustomerCart(storefrontID, customerID uuid.UUID) *Cart {
+	now := time.Now()
+	return &Cart{
+		ID:           uuid.New(),
+		StorefrontID: storefrontID,
+		CustomerID:   &customerID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// NewGuestCart creates an empty cart for an anonymous session
+func NewGuestCart(storefrontID uuid.UUID, sessionID string) *Cart {
+	now := time.Now()
+	return &Cart{
+		ID:           uuid.New(),
+		StorefrontID: storefrontID,
+		SessionID:    &sessionID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// Validate validates the cart
+func (c *Cart) Validate() error {
+	if c.StorefrontID == uuid.Nil {
+		return fmt.Errorf("storefront ID is required")
+	}
+	if c.CustomerID == nil && (c.SessionID == nil || *c.SessionID == "") {
+		return fmt.Errorf("customer ID or session ID is required")
+	}
+	return nil
+}
+
+// IsGuest returns true if the cart belongs to an anonymous session
+func (c *Cart) IsGuest() bool {
+	return c.CustomerID == nil
+}
+
+// FindItem returns the line for the given product and variant, if any
+func (c *Cart) FindItem(productID uuid.UUID, variantID *uuid.UUID) *CartItem {
+	for _, item := range c.Items {
+		if item.ProductID == productID && sameVariant(item.ProductVariantID, variantID) {
+			return item
+		}
+	}
+	return nil
+}
+
+// GetItem returns the line with the given ID, if any
+func (c *Cart) GetItem(itemID uuid.UUID) *CartItem {
+	for _, item := range c.Items {
+		if item.ID == itemID {
+			return item
+		}
+	}
+	return nil
+}
+
+// AddItem adds a quantity of a product to the cart, increasing the existing
+// line when the product is already in the cart, and returns the changed line
+func (c *Cart) AddItem(productID uuid.UUID, variantID *uuid.UUID, quantity int, unitPrice decimal.Decimal) (*CartItem, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("quantity must be positive")
+	}
+
+	if item := c.FindItem(productID, variantID); item != nil {
+		if err := item.SetQuantity(item.Quantity+quantity, unitPrice); err != nil {
+			return nil, err
+		}
+		c.UpdatedAt = item.UpdatedAt
+		return item, nil
+	}
+
+	if quantity > MaxCartItemQuantity {
+		return nil, fmt.Errorf("quantity must not exceed %d", MaxCartItemQuantity)
+	}
+
+	now := time.Now()
+	item := &CartItem{
+		ID:               uuid.New(),
+		CartID:           c.ID,
+		ProductID:        productID,
+		ProductVariantID: variantID,
+		Quantity:         quantity,
+		UnitPrice:        unitPrice,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	c.Items = append(c.Items, item)
+	c.UpdatedAt = now
+	return item, nil
+}
+
+// RemoveItem removes a line from the cart
+func (c *Cart) RemoveItem(itemID uuid.UUID) bool {
+	for i, item := range c.Items {
+		if item.ID == itemID {
+			c.Items = append(c.Items[:i], c.Items[i+1:]...)
+			c.UpdatedAt = time.Now()
+			return true
+		}
+	}
+	return false
+}
+
+// ItemCount returns the total quantity of all lines
+func (c *Cart) ItemCount() int {
+	count := 0
+	for _, item := range c.Items {
+		count += item.Quantity
+	}
+	return count
+}
+
+// SetQuantity changes the quantity of the line and refreshes its price
+func (i *CartItem) SetQuantity(quantity int, unitPrice decimal.Decimal) error {
+	if quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+	if quantity > MaxCartItemQuantity {
+		return fmt.Errorf("quantity must not exceed %d", MaxCartItemQuantity)
+	}
+	i.Quantity = quantity
+	i.UnitPrice = unitPrice
+	i.UpdatedAt = time.Now()
+	return nil
+}
+
+// sameVariant compares two optional variant IDs
+func sameVariant(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestCartAddItemMergesLines(t *testing.T) {
+	cart := NewGuestCart(uuid.New(), "session-1")
+	productID := uuid.New()
+	variantID := uuid.New()
+
+	if _, err := cart.AddItem(productID, nil, 1, decimal.NewFromInt(100000)); err != nil {
+		t.Fatalf("AddItem failed: %v", err)
+	}
+	if _, err := cart.AddItem(productID, &variantID, 2, decimal.NewFromInt(120000)); err != nil {
+		t.Fatalf("AddItem with variant failed: %v", err)
+	}
+
+	item, err := cart.AddItem(productID, nil, 2, decimal.NewFromInt(95000))
+	if err != nil {
+		t.Fatalf("AddItem failed: %v", err)
+	}
+	if len(cart.Items) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(cart.Items))
+	}
+	if item.Quantity != 3 || !item.UnitPrice.Equal(decimal.NewFromInt(95000)) {
+		t.Errorf("Expected merged line with quantity 3 at 95000, got %d at %s", item.Quantity, item.UnitPrice)
+	}
+	if cart.ItemCount() != 5 {
+		t.Errorf("Expected item count 5, got %d", cart.ItemCount())
+	}
+
+	if _, err := cart.AddItem(productID, nil, MaxCartItemQuantity, decimal.NewFromInt(95000)); err == nil {
+		t.Error("Expected AddItem above the line limit to fail")
+	}
+
+	if !cart.RemoveItem(item.ID) || cart.FindItem(productID, nil) != nil {
+		t.Error("Expected line to be removed")
+	}
+}
@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// CourierBookingStatus represents the state of a courier booking
+type CourierBookingStatus string
+
+const (
+	CourierBookingStatusBooked    CourierBookingStatus = "booked"    // AWB issued, waiting for pickup or drop-off
+	CourierBookingStatusCancelled CourierBookingStatus = "cancelled" // Cancelled with the courier
+)
+
+// Valid validates the courier booking status
+func (s CourierBookingStatus) Valid() bool {
+	switch s {
+	case CourierBookingStatusBooked, CourierBookingStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of CourierBookingStatus
+func (s CourierBookingStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for database storage
+func (s CourierBookingStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (s *CourierBookingStatus) Scan(value interface{}) error {
+	if value == nil {
+		*s = CourierBookingStatusBooked
+		return nil
+	}
+	switch v := value.(type) {
+	case string:
+		*s = CourierBookingStatus(v)
+		return nil
+	case []byte:
+		*s = CourierBookingStatus(v)
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into CourierBookingStatus", value)
+}
+
+// CourierBooking is an order's parcel booked with a courier. It holds the AWB
+// the courier issued and, unless the seller drops the parcel off, the slot
+// the courier collects it in. An order has at most one active booking.
+type CourierBooking struct {
+	ID             uuid.UUID            `json:"id" db:"id"`
+	OrderID        uuid.UUID            `json:"order_id" db:"order_id"`
+	CourierCode    string               `json:"courier_code" db:"courier_code"`
+	ServiceType    string               `json:"service_type" db:"service_type"`
+	BookingCode    *string              `json:"booking_code,omitempty" db:"booking_code"`
+	TrackingNumber string               `json:"tracking_number" db:"tracking_number"`
+	Weight         *decimal.Decimal     `json:"weight,omitempty" db:"weight"` // kg, as booked with the courier
+	Status         CourierBookingStatus `json:"status" db:"status"`
+	PickupStart    *time.Time           `json:"pickup_start,omitempty" db:"pickup_start"`
+	PickupEnd      *time.Time           `json:"pickup_end,omitempty" db:"pickup_end"`
+	BookedBy       *uuid.UUID           `json:"booked_by,omitempty" db:"booked_by"`
+	CreatedAt      time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// NewCourierBooking creates an active booking of an order's parcel
+func NewCourierBooking(orderID uuid.UUID, courierCode, serviceType, trackingNumber string) *CourierBooking {
+	now := time.Now()
+	return &CourierBooking{
+		ID:             uuid.New(),
+		OrderID:        orderID,
+		CourierCode:    courierCode,
+		ServiceType:    serviceType,
+		TrackingNumber: trackingNumber,
+		Status:         CourierBookingStatusBooked,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// SchedulePickup sets the slot the courier collects the parcel in
+func (b *CourierBooking) SchedulePickup(start, end time.Time) error {
+	if !end.After(start) {
+		return fmt.Errorf("pickup must end after it starts")
+	}
+	b.PickupStart = &start
+	b.PickupEnd = &end
+	return nil
+}
+
+// IsPickup reports whether the courier collects the parcel, rather than the
+// seller dropping it off
+func (b *CourierBooking) IsPickup() bool {
+	return b.PickupStart != nil
+}
+
+// Validate validates the courier booking
+func (b *CourierBooking) Validate() error {
+	if b.OrderID == uuid.Nil {
+		return fmt.Errorf("order ID is required")
+	}
+	if b.CourierCode == "" {
+		return fmt.Errorf("courier code is required")
+	}
+	if b.TrackingNumber == "" {
+		return fmt.Errorf("tracking number is required")
+	}
+	if !b.Status.Valid() {
+		return fmt.Errorf("invalid booking status: %s", b.Status)
+	}
+	if (b.PickupStart == nil) != (b.PickupEnd == nil) {
+		return fmt.Errorf("pickup needs both a start and an end")
+	}
+	if b.PickupStart != nil && !b.PickupEnd.After(*b.PickupStart) {
+		return fmt.Errorf("pickup must end after it starts")
+	}
+	return nil
+}
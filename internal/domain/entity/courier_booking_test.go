@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCourierBookingValidate(t *testing.T) {
+	booking := NewCourierBooking(uuid.New(), "jnt", "EZ", "JO0027364815")
+	if err := booking.Validate(); err != nil {
+		t.Fatalf("Expected a drop-off booking to be valid, got %v", err)
+	}
+	if booking.IsPickup() {
+		t.Error("Expected a booking without a slot to be a drop-off")
+	}
+
+	start := time.Date(2026, 10, 17, 9, 0, 0, 0, time.FixedZone("WIB", 7*3600))
+	if err := booking.SchedulePickup(start, start); err == nil {
+		t.Error("Expected an empty pickup slot to be rejected")
+	}
+	if err := booking.SchedulePickup(start, start.Add(3*time.Hour)); err != nil {
+		t.Fatalf("Expected pickup slot to be accepted, got %v", err)
+	}
+	if !booking.IsPickup() || booking.Validate() != nil {
+		t.Error("Expected a valid pickup booking")
+	}
+
+	booking.PickupEnd = nil
+	if err := booking.Validate(); err == nil {
+		t.Error("Expected a pickup without an end to fail validation")
+	}
+
+	missing := NewCourierBooking(uuid.New(), "sicepat", "REG", "")
+	if err := missing.Validate(); err == nil {
+		t.Error("Expected a booking without a tracking number to fail validation")
+	}
+}
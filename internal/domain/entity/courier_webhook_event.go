@@ -0,0 +1,236 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CourierWebhookEventStatus represents the processing state of a courier webhook delivery
+type CourierWebhookEventStatus string
+
+const (
+	CourierWebhookEventStatusReceived     CourierWebhookEventStatus = "received"      // Stored, not processed yet
+	CourierWebhookEventStatusProcessed    CourierWebhookEventStatus = "processed"     // Tracking updates applied
+	CourierWebhookEventStatusFailed       CourierWebhookEventStatus = "failed"        // Parsing or applying failed, retry scheduled
+	CourierWebhookEventStatusDeadLettered CourierWebhookEventStatus = "dead_lettered" // Retries exhausted, waiting for a manual replay
+	CourierWebhookEventStatusRejected     CourierWebhookEventStatus = "rejected"      // Signature check failed
+)
+
+// Valid validates the courier webhook event status
+func (s CourierWebhookEventStatus) Valid() bool {
+	switch s {
+	case CourierWebhookEventStatusReceived, CourierWebhookEventStatusProcessed,
+		CourierWebhookEventStatusFailed, CourierWebhookEventStatusDeadLettered,
+		CourierWebhookEventStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of CourierWebhookEventStatus
+func (s CourierWebhookEventStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for database storage
+func (s CourierWebhookEventStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (s *CourierWebhookEventStatus) Scan(value interface{}) error {
+	if value == nil {
+		*s = CourierWebhookEventStatusReceived
+		return nil
+	}
+	switch v := value.(type) {
+	case string:
+		*s = CourierWebhookEventStatus(v)
+		return nil
+	case []byte:
+		*s = CourierWebhookEventStatus(v)
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into CourierWebhookEventStatus", value)
+}
+
+// WebhookHeaders holds the HTTP headers of a webhook delivery stored as JSONB
+type WebhookHeaders map[string]string
+
+// Value implements the driver.Valuer interface for database storage
+func (h WebhookHeaders) Value() (driver.Value, error) {
+	if h == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(h)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (h *WebhookHeaders) Scan(value interface{}) error {
+	if value == nil {
+		*h = WebhookHeaders{}
+		return nil
+	}
+
+	var b []byte
+	switch v := value.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into WebhookHeaders", value)
+	}
+
+	return json.Unmarshal(b, h)
+}
+
+// CourierWebhookRetryPolicy controls how failed deliveries are retried
+type CourierWebhookRetryPolicy struct {
+	MaxAttempts int           // Processing attempts before the event is dead-lettered
+	BaseDelay   time.Duration // Delay before the first retry, doubled for every further attempt
+	MaxDelay    time.Duration // Upper bound for the delay between retries
+}
+
+// Backoff returns the delay before the next retry after the given number of attempts
+func (p CourierWebhookRetryPolicy) Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// CourierWebhookEvent is a raw tracking push from a courier, kept for audit, retry and replay
+type CourierWebhookEvent struct {
+	ID             uuid.UUID                 `json:"id" db:"id"`
+	CourierCode    string                    `json:"courier_code" db:"courier_code"`
+	Payload        string                    `json:"payload" db:"payload"`
+	Headers        WebhookHeaders            `json:"headers" db:"headers"`
+	Signature      *string                   `json:"signature,omitempty" db:"signature"`
+	SignatureValid *bool                     `json:"signature_valid,omitempty" db:"signature_valid"`
+	RemoteIP       *string                   `json:"remote_ip,omitempty" db:"remote_ip"`
+	Status         CourierWebhookEventStatus `json:"status" db:"status"`
+	ErrorMessage   *string                   `json:"error_message,omitempty" db:"error_message"`
+	UpdatesCount   int                       `json:"updates_count" db:"updates_count"`
+	Attempts       int                       `json:"attempts" db:"attempts"`
+	LastAttemptAt  *time.Time                `json:"last_attempt_at,omitempty" db:"last_attempt_at"`
+	NextRetryAt    *time.Time                `json:"next_retry_at,omitempty" db:"next_retry_at"`
+	ProcessedAt    *time.Time                `json:"processed_at,omitempty" db:"processed_at"`
+	ReceivedAt     time.Time                 `json:"received_at" db:"received_at"`
+	CreatedAt      time.Time                 `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time                 `json:"updated_at" db:"updated_at"`
+}
+
+// NewCourierWebhookEvent creates a received event for a raw courier delivery
+func NewCourierWebhookEvent(courierCode string, payload []byte, headers map[string]string, signature, remoteIP string) *CourierWebhookEvent {
+	now := time.Now()
+	event := &CourierWebhookEvent{
+		ID:          uuid.New(),
+		CourierCode: courierCode,
+		Payload:     string(payload),
+		Headers:     WebhookHeaders(headers),
+		Status:      CourierWebhookEventStatusReceived,
+		ReceivedAt:  now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if event.Headers == nil {
+		event.Headers = WebhookHeaders{}
+	}
+	if signature != "" {
+		event.Signature = &signature
+	}
+	if remoteIP != "" {
+		event.RemoteIP = &remoteIP
+	}
+	return event
+}
+
+// Validate validates the courier webhook event
+func (e *CourierWebhookEvent) Validate() error {
+	if e.CourierCode == "" {
+		return fmt.Errorf("courier code is required")
+	}
+	if !e.Status.Valid() {
+		return fmt.Errorf("invalid courier webhook event status: %s", e.Status)
+	}
+	return nil
+}
+
+// CanReplay returns true if the event passed signature validation and may be processed again
+func (e *CourierWebhookEvent) CanReplay() bool {
+	return e.Status != CourierWebhookEventStatusRejected
+}
+
+// IsDeadLettered returns true if automatic retries gave up on the event
+func (e *CourierWebhookEvent) IsDeadLettered() bool {
+	return e.Status == CourierWebhookEventStatusDeadLettered
+}
+
+// MarkSignatureValid records that the delivery passed signature validation
+func (e *CourierWebhookEvent) MarkSignatureValid() {
+	valid := true
+	e.SignatureValid = &valid
+}
+
+// MarkProcessed records a successful processing attempt
+func (e *CourierWebhookEvent) MarkProcessed(updatesCount int) {
+	now := time.Now()
+	e.Status = CourierWebhookEventStatusProcessed
+	e.UpdatesCount = updatesCount
+	e.ErrorMessage = nil
+	e.Attempts++
+	e.LastAttemptAt = &now
+	e.NextRetryAt = nil
+	e.ProcessedAt = &now
+	e.UpdatedAt = now
+}
+
+// MarkFailed records a failed processing attempt and schedules the next retry
+// with exponential backoff. Once the policy's attempts are used up the event
+// is dead-lettered instead.
+func (e *CourierWebhookEvent) MarkFailed(err error, policy CourierWebhookRetryPolicy) {
+	now := time.Now()
+	message := err.Error()
+	e.ErrorMessage = &message
+	e.Attempts++
+	e.LastAttemptAt = &now
+	e.UpdatedAt = now
+
+	if e.Attempts >= policy.MaxAttempts {
+		e.Status = CourierWebhookEventStatusDeadLettered
+		e.NextRetryAt = nil
+		return
+	}
+
+	nextRetryAt := now.Add(policy.Backoff(e.Attempts))
+	e.Status = CourierWebhookEventStatusFailed
+	e.NextRetryAt = &nextRetryAt
+}
+
+// MarkRejected records that the delivery failed signature validation
+func (e *CourierWebhookEvent) MarkRejected(err error) {
+	message := err.Error()
+	valid := false
+	e.Status = CourierWebhookEventStatusRejected
+	e.SignatureValid = &valid
+	e.ErrorMessage = &message
+	e.NextRetryAt = nil
+	e.UpdatedAt = time.Now()
+}
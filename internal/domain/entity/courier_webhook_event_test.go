@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCourierWebhookRetryPolicyBackoff(t *testing.T) {
+	policy := CourierWebhookRetryPolicy{MaxAttempts: 8, BaseDelay: time.Minute, MaxDelay: 10 * time.Minute}
+
+	expected := map[int]time.Duration{
+		1: time.Minute,
+		2: 2 * time.Minute,
+		3: 4 * time.Minute,
+		4: 8 * time.Minute,
+		5: 10 * time.Minute,
+		9: 10 * time.Minute,
+	}
+	for attempts, want := range expected {
+		if got := policy.Backoff(attempts); got != want {
+			t.Errorf("Backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestCourierWebhookEventMarkFailed(t *testing.T) {
+	policy := CourierWebhookRetryPolicy{MaxAttempts: 2, BaseDelay: time.Minute, MaxDelay: time.Hour}
+	event := NewCourierWebhookEvent("jne", []byte(`{}`), nil, "", "")
+
+	event.MarkFailed(errors.New("unknown status"), policy)
+	if event.Status != CourierWebhookEventStatusFailed || event.NextRetryAt == nil {
+		t.Fatalf("Expected a scheduled retry after the first failure, got %s", event.Status)
+	}
+
+	event.MarkFailed(errors.New("unknown status"), policy)
+	if !event.IsDeadLettered() || event.NextRetryAt != nil {
+		t.Fatalf("Expected the event to be dead-lettered, got %s", event.Status)
+	}
+	if !event.CanReplay() {
+		t.Error("Expected a dead-lettered event to be replayable")
+	}
+
+	event.MarkProcessed(1)
+	if event.Status != CourierWebhookEventStatusProcessed || event.ErrorMessage != nil {
+		t.Errorf("Expected a replayed event to be processed, got %s", event.Status)
+	}
+}
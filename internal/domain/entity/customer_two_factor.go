@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	// CustomerTwoFactorMaxAttempts is the number of wrong codes accepted before
+	// verification is locked
+	CustomerTwoFactorMaxAttempts = 5
+	// CustomerTwoFactorLockout is how long verification stays locked
+	CustomerTwoFactorLockout = 15 * time.Minute
+	// CustomerRecoveryCodeCount is the number of recovery codes issued at enrollment
+	CustomerRecoveryCodeCount = 10
+)
+
+// CustomerTwoFactor holds a customer's TOTP enrollment
+type CustomerTwoFactor struct {
+	CustomerID   uuid.UUID `json:"customer_id" db:"customer_id"`
+	StorefrontID uuid.UUID `json:"storefront_id" db:"storefront_id"`
+
+	// Sealed TOTP secret and digests of the unused recovery codes
+	Secret             string         `json:"-" db:"secret"`
+	RecoveryCodeHashes pq.StringArray `json:"-" db:"recovery_code_hashes"`
+
+	// Replay and brute force protection
+	LastUsedStep   int64      `json:"-" db:"last_used_step"`
+	FailedAttempts int        `json:"-" db:"failed_attempts"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty" db:"locked_until"`
+
+	EnabledAt *time.Time `json:"enabled_at,omitempty" db:"enabled_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsEnabled reports whether enrollment was confirmed with a first code
+func (tf *CustomerTwoFactor) IsEnabled() bool {
+	return tf.EnabledAt != nil
+}
+
+// IsLocked reports whether too many wrong codes were entered recently
+func (tf *CustomerTwoFactor) IsLocked(now time.Time) bool {
+	return tf.LockedUntil != nil && now.Before(*tf.LockedUntil)
+}
+
+// RecordFailure counts a wrong code and locks verification once the
+// customer runs out of attempts
+func (tf *CustomerTwoFactor) RecordFailure(now time.Time) {
+	tf.FailedAttempts++
+	if tf.FailedAttempts >= CustomerTwoFactorMaxAttempts {
+		lockedUntil := now.Add(CustomerTwoFactorLockout)
+		tf.LockedUntil = &lockedUntil
+		tf.FailedAttempts = 0
+	}
+}
+
+// RecordSuccess clears the failed attempts after a correct code
+func (tf *CustomerTwoFactor) RecordSuccess() {
+	tf.FailedAttempts = 0
+	tf.LockedUntil = nil
+}
+
+// SetRecoveryCodes replaces the recovery codes with digests of codes
+func (tf *CustomerTwoFactor) SetRecoveryCodes(codes []string) {
+	hashes := make(pq.StringArray, len(codes))
+	for i, code := range codes {
+		hashes[i] = HashRecoveryCode(code)
+	}
+	tf.RecoveryCodeHashes = hashes
+}
+
+// UseRecoveryCode consumes a recovery code. Returns false when the code is
+// unknown or was already used.
+func (tf *CustomerTwoFactor) UseRecoveryCode(code string) bool {
+	hash := HashRecoveryCode(code)
+	for i, stored := range tf.RecoveryCodeHashes {
+		if subtle.ConstantTimeCompare([]byte(stored), []byte(hash)) == 1 {
+			tf.RecoveryCodeHashes = append(tf.RecoveryCodeHashes[:i], tf.RecoveryCodeHashes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// HashRecoveryCode returns the stored digest of a recovery code. Codes are
+// compared without case, spaces or dashes.
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCustomerTwoFactorRecoveryCodesAreSingleUse(t *testing.T) {
+	tf := &CustomerTwoFactor{}
+	tf.SetRecoveryCodes([]string{"ABCD-EFGH", "IJKL-MNOP"})
+
+	if !tf.UseRecoveryCode("abcd efgh") {
+		t.Fatal("expected recovery code to match regardless of case and separators")
+	}
+	if tf.UseRecoveryCode("ABCD-EFGH") {
+		t.Error("recovery code accepted twice")
+	}
+	if tf.UseRecoveryCode("QRST-UVWX") {
+		t.Error("unknown recovery code accepted")
+	}
+	if len(tf.RecoveryCodeHashes) != 1 {
+		t.Errorf("expected 1 recovery code left, got %d", len(tf.RecoveryCodeHashes))
+	}
+}
+
+func TestCustomerTwoFactorLocksAfterFailedAttempts(t *testing.T) {
+	tf := &CustomerTwoFactor{}
+	now := time.Now()
+
+	for i := 0; i < CustomerTwoFactorMaxAttempts-1; i++ {
+		tf.RecordFailure(now)
+	}
+	if tf.IsLocked(now) {
+		t.Fatal("locked before running out of attempts")
+	}
+
+	tf.RecordFailure(now)
+	if !tf.IsLocked(now) {
+		t.Fatal("expected lock after the last attempt")
+	}
+	if tf.IsLocked(now.Add(CustomerTwoFactorLockout)) {
+		t.Error("lock outlived the lockout period")
+	}
+
+	tf.RecordSuccess()
+	if tf.IsLocked(now) || tf.FailedAttempts != 0 {
+		t.Error("success should clear the lock and attempts")
+	}
+}
@@ -0,0 +1,625 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+// OrderStatus represents the fulfilment status of an order
+type OrderStatus string
+
+const (
+	OrderStatusPending    OrderStatus = "pending"    // Placed, awaiting confirmation
+	OrderStatusConfirmed  OrderStatus = "confirmed"  // Accepted by the seller
+	OrderStatusProcessing OrderStatus = "processing" // Being packed
+	OrderStatusShipped    OrderStatus = "shipped"    // Handed over to the courier
+	OrderStatusDelivered  OrderStatus = "delivered"  // Received by the customer
+	OrderStatusCancelled  OrderStatus = "cancelled"  // Cancelled before shipping
+	OrderStatusRefunded   OrderStatus = "refunded"   // Money returned to the customer
+	OrderStatusReturned   OrderStatus = "returned"   // Goods returned to the seller
+)
+
+// Valid validates the order status
+func (s OrderStatus) Valid() bool {
+	switch s {
+	case OrderStatusPending, OrderStatusConfirmed, OrderStatusProcessing, OrderStatusShipped,
+		OrderStatusDelivered, OrderStatusCancelled, OrderStatusRefunded, OrderStatusReturned:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of OrderStatus
+func (s OrderStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for database storage
+func (s OrderStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (s *OrderStatus) Scan(value interface{}) error {
+	if value == nil {
+		*s = OrderStatusPending
+		return nil
+	}
+	switch v := value.(type) {
+	case string:
+		*s = OrderStatus(v)
+		return nil
+	case []byte:
+		*s = OrderStatus(v)
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into OrderStatus", value)
+}
+
+// PaymentStatus represents the payment status of an order
+type PaymentStatus string
+
+const (
+	PaymentStatusPending       PaymentStatus = "pending"
+	PaymentStatusPaid          PaymentStatus = "paid"
+	PaymentStatusPartiallyPaid PaymentStatus = "partially_paid"
+	PaymentStatusRefunded      PaymentStatus = "refunded"
+	PaymentStatusFailed        PaymentStatus = "failed"
+)
+
+// Valid validates the payment status
+func (ps PaymentStatus) Valid() bool {
+	switch ps {
+	case PaymentStatusPending, PaymentStatusPaid, PaymentStatusPartiallyPaid,
+		PaymentStatusRefunded, PaymentStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanTransitionTo checks if a payment can move to the specified status.
+// Settled payments are never reopened; a failed payment can be retried or
+// still be paid late.
+func (ps PaymentStatus) CanTransitionTo(newStatus PaymentStatus) bool {
+	switch ps {
+	case PaymentStatusPending:
+		return newStatus == PaymentStatusPaid || newStatus == PaymentStatusPartiallyPaid || newStatus == PaymentStatusFailed
+	case PaymentStatusFailed:
+		return newStatus == PaymentStatusPending || newStatus == PaymentStatusPaid || newStatus == PaymentStatusPartiallyPaid
+	case PaymentStatusPartiallyPaid:
+		return newStatus == PaymentStatusPaid || newStatus == PaymentStatusRefunded
+	case PaymentStatusPaid:
+		return newStatus == PaymentStatusRefunded
+	default:
+		return false
+	}
+}
+
+// OrderChannel represents where an order was placed
+type OrderChannel string
+
+const (
+	OrderChannelDirect      OrderChannel = "direct"
+	OrderChannelMarketplace OrderChannel = "marketplace"
+	OrderChannelSocial      OrderChannel = "social"
+	OrderChannelMobileApp   OrderChannel = "mobile_app"
+	OrderChannelPOS         OrderChannel = "pos"
+	OrderChannelAPI         OrderChannel = "api"
+)
+
+// Valid validates the order channel
+func (oc OrderChannel) Valid() bool {
+	switch oc {
+	case OrderChannelDirect, OrderChannelMarketplace, OrderChannelSocial,
+		OrderChannelMobileApp, OrderChannelPOS, OrderChannelAPI:
+		return true
+	default:
+		return false
+	}
+}
+
+// Order represents a customer order
+type Order struct {
+	// Primary identification
+	ID          uuid.UUID `json:"id" db:"id"`
+	OrderNumber string    `json:"order_number" db:"order_number"`
+
+	// Ownership
+	StorefrontID *uuid.UUID `json:"storefront_id,omitempty" db:"storefront_id"`
+	CreatedBy    uuid.UUID  `json:"created_by" db:"created_by"`
+
+	// Customer information
+	CustomerID    *uuid.UUID `json:"customer_id,omitempty" db:"customer_id"`
+	CustomerEmail *string    `json:"customer_email,omitempty" db:"customer_email"`
+	CustomerPhone *string    `json:"customer_phone,omitempty" db:"customer_phone"`
+
+	// Status
+	Status OrderStatus `json:"status" db:"status"`
+
+	// Financial information
+	Subtotal       decimal.Decimal `json:"subtotal" db:"subtotal"`
+	TaxAmount      decimal.Decimal `json:"tax_amount" db:"tax_amount"`
+	ShippingAmount decimal.Decimal `json:"shipping_amount" db:"shipping_amount"`
+	DiscountAmount decimal.Decimal `json:"discount_amount" db:"discount_amount"`
+	TotalAmount    decimal.Decimal `json:"total_amount" db:"total_amount"`
+	Currency       string          `json:"currency" db:"currency"`
+
+	// Payment information
+	PaymentStatus    PaymentStatus   `json:"payment_status" db:"payment_status"`
+	PaymentMethod    *string         `json:"payment_method,omitempty" db:"payment_method"`
+	PaymentReference *string         `json:"payment_reference,omitempty" db:"payment_reference"`
+	PaidAmount       decimal.Decimal `json:"paid_amount" db:"paid_amount"`
+
+	// Shipping information
+	ShippingMethod         *string `json:"shipping_method,omitempty" db:"shipping_method"`
+	ShippingTrackingNumber *string `json:"shipping_tracking_number,omitempty" db:"shipping_tracking_number"`
+	ShippingCarrier        *string `json:"shipping_carrier,omitempty" db:"shipping_carrier"`
+
+	// Billing address
+	BillingFirstName     *string `json:"billing_first_name,omitempty" db:"billing_first_name"`
+	BillingLastName      *string `json:"billing_last_name,omitempty" db:"billing_last_name"`
+	BillingCompany       *string `json:"billing_company,omitempty" db:"billing_company"`
+	BillingAddressLine1  *string `json:"billing_address_line_1,omitempty" db:"billing_address_line_1"`
+	BillingAddressLine2  *string `json:"billing_address_line_2,omitempty" db:"billing_address_line_2"`
+	BillingCity          *string `json:"billing_city,omitempty" db:"billing_city"`
+	BillingStateProvince *string `json:"billing_state_province,omitempty" db:"billing_state_province"`
+	BillingPostalCode    *string `json:"billing_postal_code,omitempty" db:"billing_postal_code"`
+	BillingCountry       *string `json:"billing_country,omitempty" db:"billing_country"`
+	BillingPhone         *string `json:"billing_phone,omitempty" db:"billing_phone"`
+
+	// Shipping address
+	ShippingFirstName     *string `json:"shipping_first_name,omitempty" db:"shipping_first_name"`
+	ShippingLastName      *string `json:"shipping_last_name,omitempty" db:"shipping_last_name"`
+	ShippingCompany       *string `json:"shipping_company,omitempty" db:"shipping_company"`
+	ShippingAddressLine1  *string `json:"shipping_address_line_1,omitempty" db:"shipping_address_line_1"`
+	ShippingAddressLine2  *string `json:"shipping_address_line_2,omitempty" db:"shipping_address_line_2"`
+	ShippingCity          *string `json:"shipping_city,omitempty" db:"shipping_city"`
+	ShippingStateProvince *string `json:"shipping_state_province,omitempty" db:"shipping_state_province"`
+	ShippingPostalCode    *string `json:"shipping_postal_code,omitempty" db:"shipping_postal_code"`
+	ShippingCountry       *string `json:"shipping_country,omitempty" db:"shipping_country"`
+	ShippingPhone         *string `json:"shipping_phone,omitempty" db:"shipping_phone"`
+
+	// Metadata
+	Notes         *string        `json:"notes,omitempty" db:"notes"`
+	InternalNotes *string        `json:"internal_notes,omitempty" db:"internal_notes"`
+	Tags          pq.StringArray `json:"tags,omitempty" db:"tags"`
+
+	// Channel information
+	Channel          OrderChannel `json:"channel" db:"channel"`
+	ChannelReference *string      `json:"channel_reference,omitempty" db:"channel_reference"`
+
+	// Important dates
+	OrderDate   time.Time  `json:"order_date" db:"order_date"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty" db:"confirmed_at"`
+	ShippedAt   *time.Time `json:"shipped_at,omitempty" db:"shipped_at"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty" db:"delivered_at"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty" db:"cancelled_at"`
+
+	// Timestamps
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+
+	// Relations (not stored in the orders table)
+	Items []*OrderItem `json:"items,omitempty" db:"-"`
+
+	// Computed fields (not stored in database)
+	ItemCount   int      `json:"item_count" db:"-"`
+	CanCancel   bool     `json:"can_cancel" db:"-"`
+	NextActions []string `json:"next_actions" db:"-"`
+}
+
+// OrderItem represents a line item snapshot taken when the order was placed
+type OrderItem struct {
+	ID               uuid.UUID        `json:"id" db:"id"`
+	OrderID          uuid.UUID        `json:"order_id" db:"order_id"`
+	ProductID        *uuid.UUID       `json:"product_id,omitempty" db:"product_id"`
+	ProductVariantID *uuid.UUID       `json:"product_variant_id,omitempty" db:"product_variant_id"`
+	ProductName      string           `json:"product_name" db:"product_name"`
+	ProductSKU       *string          `json:"product_sku,omitempty" db:"product_sku"`
+	VariantName      *string          `json:"variant_name,omitempty" db:"variant_name"`
+	UnitPrice        decimal.Decimal  `json:"unit_price" db:"unit_price"`
+	Quantity         int              `json:"quantity" db:"quantity"`
+	TotalPrice       decimal.Decimal  `json:"total_price" db:"total_price"`
+	ProductWeight    *decimal.Decimal `json:"product_weight,omitempty" db:"product_weight"`
+	ProductImageURL  *string          `json:"product_image_url,omitempty" db:"product_image_url"`
+	CreatedAt        time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// OrderStatusHistory represents a single status change of an order
+type OrderStatusHistory struct {
+	ID         uuid.UUID    `json:"id" db:"id"`
+	OrderID    uuid.UUID    `json:"order_id" db:"order_id"`
+	FromStatus *OrderStatus `json:"from_status,omitempty" db:"from_status"`
+	ToStatus   OrderStatus  `json:"to_status" db:"to_status"`
+	Reason     *string      `json:"reason,omitempty" db:"reason"`
+	Notes      *string      `json:"notes,omitempty" db:"notes"`
+	ChangedBy  *uuid.UUID   `json:"changed_by,omitempty" db:"changed_by"`
+	ChangedAt  time.Time    `json:"changed_at" db:"changed_at"`
+}
+
+// NewOrder creates a new order with default values
+func NewOrder(createdBy uuid.UUID, storefrontID *uuid.UUID, currency string) *Order {
+	now := time.Now()
+	if currency == "" {
+		currency = "IDR"
+	}
+	return &Order{
+		ID:             uuid.New(),
+		StorefrontID:   storefrontID,
+		CreatedBy:      createdBy,
+		Status:         OrderStatusPending,
+		PaymentStatus:  PaymentStatusPending,
+		Channel:        OrderChannelDirect,
+		Currency:       currency,
+		Subtotal:       decimal.Zero,
+		TaxAmount:      decimal.Zero,
+		ShippingAmount: decimal.Zero,
+		DiscountAmount: decimal.Zero,
+		TotalAmount:    decimal.Zero,
+		OrderDate:      now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// NewOrderItem creates a new order item snapshot
+func NewOrderItem(orderID uuid.UUID, productName string, unitPrice decimal.Decimal, quantity int) *OrderItem {
+	now := time.Now()
+	return &OrderItem{
+		ID:          uuid.New(),
+		OrderID:     orderID,
+		ProductName: productName,
+		UnitPrice:   unitPrice,
+		Quantity:    quantity,
+		TotalPrice:  unitPrice.Mul(decimal.NewFromInt(int64(quantity))),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Validate performs validation of the order item
+func (oi *OrderItem) Validate() error {
+	if oi.ProductName == "" {
+		return fmt.Errorf("product_name is required")
+	}
+	if oi.Quantity <= 0 {
+		return fmt.Errorf("quantity must be greater than 0")
+	}
+	if oi.UnitPrice.IsNegative() {
+		return fmt.Errorf("unit_price cannot be negative")
+	}
+	return nil
+}
+
+// Validate performs comprehensive validation of the order
+func (o *Order) Validate() error {
+	if o.OrderNumber == "" {
+		return fmt.Errorf("order_number is required")
+	}
+	if len(o.OrderNumber) > 50 {
+		return fmt.Errorf("order_number cannot exceed 50 characters")
+	}
+	if o.CreatedBy == uuid.Nil {
+		return fmt.Errorf("created_by is required")
+	}
+	if o.CustomerID == nil && (o.CustomerEmail == nil || *o.CustomerEmail == "") {
+		return fmt.Errorf("customer_id or customer_email is required")
+	}
+
+	if !o.Status.Valid() {
+		return fmt.Errorf("invalid order status: %s", o.Status)
+	}
+	if !o.PaymentStatus.Valid() {
+		return fmt.Errorf("invalid payment status: %s", o.PaymentStatus)
+	}
+	if !o.Channel.Valid() {
+		return fmt.Errorf("invalid order channel: %s", o.Channel)
+	}
+	if len(o.Currency) != 3 {
+		return fmt.Errorf("currency must be a 3-letter code")
+	}
+
+	if o.Subtotal.IsNegative() {
+		return fmt.Errorf("subtotal cannot be negative")
+	}
+	if o.TaxAmount.IsNegative() {
+		return fmt.Errorf("tax_amount cannot be negative")
+	}
+	if o.ShippingAmount.IsNegative() {
+		return fmt.Errorf("shipping_amount cannot be negative")
+	}
+	if o.DiscountAmount.IsNegative() {
+		return fmt.Errorf("discount_amount cannot be negative")
+	}
+	if o.TotalAmount.IsNegative() {
+		return fmt.Errorf("total_amount cannot be negative")
+	}
+
+	for i, item := range o.Items {
+		if err := item.Validate(); err != nil {
+			return fmt.Errorf("item %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// CalculateTotals recalculates the subtotal and total from the order items.
+// TaxAmount is treated as exclusive and added on top; use ApplyTax to derive
+// the tax from a storefront's tax settings.
+func (o *Order) CalculateTotals() {
+	subtotal := decimal.Zero
+	for _, item := range o.Items {
+		item.TotalPrice = item.UnitPrice.Mul(decimal.NewFromInt(int64(item.Quantity)))
+		subtotal = subtotal.Add(item.TotalPrice)
+	}
+	o.Subtotal = subtotal
+
+	total := subtotal.Add(o.TaxAmount).Add(o.ShippingAmount).Sub(o.DiscountAmount)
+	if total.IsNegative() {
+		total = decimal.Zero
+	}
+	o.TotalAmount = total
+}
+
+// ApplyTax recalculates the totals and sets the tax for the given rate, where
+// ratePercent is a percentage (11 means 11%). With inclusive pricing the tax is
+// the portion already contained in the discounted subtotal and the total is
+// left unchanged; otherwise the tax is added on top of the total.
+func (o *Order) ApplyTax(ratePercent decimal.Decimal, inclusive bool) {
+	o.TaxAmount = decimal.Zero
+	o.CalculateTotals()
+
+	if !ratePercent.IsPositive() {
+		return
+	}
+
+	taxable := o.Subtotal.Sub(o.DiscountAmount)
+	if !taxable.IsPositive() {
+		return
+	}
+
+	rate := ratePercent.Div(decimal.NewFromInt(100))
+	if inclusive {
+		o.TaxAmount = taxable.Sub(taxable.Div(decimal.NewFromInt(1).Add(rate))).Round(2)
+		return
+	}
+
+	o.TaxAmount = taxable.Mul(rate).Round(2)
+	o.TotalAmount = o.TotalAmount.Add(o.TaxAmount)
+}
+
+// CanTransitionTo checks if the order can transition to the specified status
+func (o *Order) CanTransitionTo(newStatus OrderStatus) bool {
+	switch o.Status {
+	case OrderStatusPending:
+		return newStatus == OrderStatusConfirmed || newStatus == OrderStatusCancelled
+	case OrderStatusConfirmed:
+		return newStatus == OrderStatusProcessing || newStatus == OrderStatusCancelled
+	case OrderStatusProcessing:
+		return newStatus == OrderStatusShipped || newStatus == OrderStatusCancelled
+	case OrderStatusShipped:
+		return newStatus == OrderStatusDelivered || newStatus == OrderStatusReturned
+	case OrderStatusDelivered:
+		return newStatus == OrderStatusReturned || newStatus == OrderStatusRefunded
+	case OrderStatusCancelled:
+		// Paid orders that get cancelled still need their money back
+		return newStatus == OrderStatusRefunded && o.PaymentStatus == PaymentStatusPaid
+	case OrderStatusReturned:
+		return newStatus == OrderStatusRefunded
+	case OrderStatusRefunded:
+		return false // Terminal status
+	default:
+		return false
+	}
+}
+
+// UpdateStatus updates the order status with validation and returns the
+// history entry describing the change
+func (o *Order) UpdateStatus(newStatus OrderStatus, changedBy *uuid.UUID, reason string) (*OrderStatusHistory, error) {
+	if !newStatus.Valid() {
+		return nil, fmt.Errorf("invalid status: %s", newStatus)
+	}
+
+	if !o.CanTransitionTo(newStatus) {
+		return nil, fmt.Errorf("cannot transition from %s to %s", o.Status, newStatus)
+	}
+
+	previousStatus := o.Status
+	now := time.Now()
+
+	o.Status = newStatus
+	o.UpdatedAt = now
+
+	switch newStatus {
+	case OrderStatusConfirmed:
+		o.ConfirmedAt = &now
+	case OrderStatusShipped:
+		o.ShippedAt = &now
+	case OrderStatusDelivered:
+		o.DeliveredAt = &now
+	case OrderStatusCancelled:
+		o.CancelledAt = &now
+	case OrderStatusRefunded:
+		o.PaymentStatus = PaymentStatusRefunded
+	}
+
+	history := &OrderStatusHistory{
+		ID:         uuid.New(),
+		OrderID:    o.ID,
+		FromStatus: &previousStatus,
+		ToStatus:   newStatus,
+		ChangedBy:  changedBy,
+		ChangedAt:  now,
+	}
+	if reason != "" {
+		history.Reason = &reason
+	}
+
+	return history, nil
+}
+
+// Confirm confirms a pending order
+func (o *Order) Confirm(confirmedBy *uuid.UUID) (*OrderStatusHistory, error) {
+	if o.Status != OrderStatusPending {
+		return nil, fmt.Errorf("can only confirm pending orders, current status: %s", o.Status)
+	}
+
+	return o.UpdateStatus(OrderStatusConfirmed, confirmedBy, "")
+}
+
+// StartProcessing moves a confirmed order into processing
+func (o *Order) StartProcessing(updatedBy *uuid.UUID) (*OrderStatusHistory, error) {
+	if o.Status != OrderStatusConfirmed {
+		return nil, fmt.Errorf("can only process confirmed orders, current status: %s", o.Status)
+	}
+
+	return o.UpdateStatus(OrderStatusProcessing, updatedBy, "")
+}
+
+// Ship marks the order as handed over to a courier
+func (o *Order) Ship(updatedBy *uuid.UUID, carrier, trackingNumber string) (*OrderStatusHistory, error) {
+	if o.Status != OrderStatusProcessing {
+		return nil, fmt.Errorf("can only ship processing orders, current status: %s", o.Status)
+	}
+	if trackingNumber == "" {
+		return nil, fmt.Errorf("tracking number is required to ship an order")
+	}
+
+	o.ShippingCarrier = &carrier
+	o.ShippingTrackingNumber = &trackingNumber
+
+	return o.UpdateStatus(OrderStatusShipped, updatedBy, "")
+}
+
+// MarkAsDelivered marks a shipped order as delivered
+func (o *Order) MarkAsDelivered(updatedBy *uuid.UUID) (*OrderStatusHistory, error) {
+	if o.Status != OrderStatusShipped {
+		return nil, fmt.Errorf("can only mark as delivered for shipped orders, current status: %s", o.Status)
+	}
+
+	return o.UpdateStatus(OrderStatusDelivered, updatedBy, "")
+}
+
+// Cancel cancels an order that has not been shipped yet
+func (o *Order) Cancel(cancelledBy *uuid.UUID, reason string) (*OrderStatusHistory, error) {
+	if o.Status != OrderStatusPending && o.Status != OrderStatusConfirmed && o.Status != OrderStatusProcessing {
+		return nil, fmt.Errorf("cannot cancel order with status: %s", o.Status)
+	}
+
+	return o.UpdateStatus(OrderStatusCancelled, cancelledBy, reason)
+}
+
+// MarkAsReturned marks a shipped or delivered order as returned
+func (o *Order) MarkAsReturned(updatedBy *uuid.UUID, reason string) (*OrderStatusHistory, error) {
+	if o.Status != OrderStatusShipped && o.Status != OrderStatusDelivered {
+		return nil, fmt.Errorf("can only return shipped or delivered orders, current status: %s", o.Status)
+	}
+
+	return o.UpdateStatus(OrderStatusReturned, updatedBy, reason)
+}
+
+// Refund refunds a delivered, returned or paid-and-cancelled order
+func (o *Order) Refund(updatedBy *uuid.UUID, reason string) (*OrderStatusHistory, error) {
+	if !o.CanTransitionTo(OrderStatusRefunded) {
+		return nil, fmt.Errorf("cannot refund order with status: %s", o.Status)
+	}
+
+	return o.UpdateStatus(OrderStatusRefunded, updatedBy, reason)
+}
+
+// IsTerminalStatus checks if the current status is terminal
+func (o *Order) IsTerminalStatus() bool {
+	return o.Status == OrderStatusRefunded ||
+		(o.Status == OrderStatusCancelled && o.PaymentStatus != PaymentStatusPaid)
+}
+
+// CanStartPayment checks that the customer can be asked to pay for the order:
+// it must still be pending and not fully paid. Partly paid orders are paid
+// off with a charge for the amount due.
+func (o *Order) CanStartPayment() error {
+	if o.Status != OrderStatusPending {
+		return fmt.Errorf("order with status %s cannot be paid", o.Status)
+	}
+	if o.PaymentStatus != PaymentStatusPending && o.PaymentStatus != PaymentStatusFailed &&
+		o.PaymentStatus != PaymentStatusPartiallyPaid {
+		return fmt.Errorf("order payment is already %s", o.PaymentStatus)
+	}
+	if !o.TotalAmount.IsPositive() {
+		return fmt.Errorf("order total must be positive to be paid")
+	}
+	if !o.AmountDue().IsPositive() {
+		return fmt.Errorf("order has nothing left to pay")
+	}
+	return nil
+}
+
+// AmountDue returns the part of the order total not received yet
+func (o *Order) AmountDue() decimal.Decimal {
+	return o.TotalAmount.Sub(o.PaidAmount)
+}
+
+// PaymentStatusFor returns the payment status the total amount received for
+// the order, across all of its charges, leaves it in
+func (o *Order) PaymentStatusFor(paidAmount decimal.Decimal) PaymentStatus {
+	if paidAmount.LessThan(o.TotalAmount) {
+		return PaymentStatusPartiallyPaid
+	}
+	return PaymentStatusPaid
+}
+
+// BelongsToCustomer checks whether the order was placed by the given customer
+func (o *Order) BelongsToCustomer(customerID uuid.UUID) bool {
+	return o.CustomerID != nil && *o.CustomerID == customerID
+}
+
+// ComputeFields calculates computed fields
+func (o *Order) ComputeFields() {
+	o.ItemCount = 0
+	for _, item := range o.Items {
+		o.ItemCount += item.Quantity
+	}
+
+	o.CanCancel = o.Status == OrderStatusPending || o.Status == OrderStatusConfirmed || o.Status == OrderStatusProcessing
+	o.NextActions = o.getNextActions()
+}
+
+// getNextActions returns possible next actions based on current status
+func (o *Order) getNextActions() []string {
+	switch o.Status {
+	case OrderStatusPending:
+		return []string{"confirm", "cancel"}
+	case OrderStatusConfirmed:
+		return []string{"process", "cancel"}
+	case OrderStatusProcessing:
+		return []string{"ship", "cancel"}
+	case OrderStatusShipped:
+		return []string{"mark_delivered", "mark_returned"}
+	case OrderStatusDelivered:
+		return []string{"mark_returned", "refund"}
+	case OrderStatusReturned:
+		return []string{"refund"}
+	case OrderStatusCancelled:
+		if o.PaymentStatus == PaymentStatusPaid {
+			return []string{"refund"}
+		}
+		return []string{}
+	default:
+		return []string{}
+	}
+}
+
+// String returns a string representation of the order
+func (o *Order) String() string {
+	return fmt.Sprintf("Order{ID: %s, Number: %s, Status: %s, Total: %s %s}",
+		o.ID.String(), o.OrderNumber, o.Status, o.TotalAmount.String(), o.Currency)
+}
@@ -0,0 +1,191 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func newTestOrder() *Order {
+	order := NewOrder(uuid.New(), nil, "")
+	order.OrderNumber = "ORD-20240101-000001"
+	email := "budi@example.com"
+	order.CustomerEmail = &email
+	return order
+}
+
+func TestOrderLifecycle(t *testing.T) {
+	order := newTestOrder()
+	actor := uuid.New()
+
+	if _, err := order.Confirm(&actor); err != nil {
+		t.Fatalf("Confirm failed: %v", err)
+	}
+	if order.ConfirmedAt == nil {
+		t.Error("Expected confirmed_at to be set")
+	}
+
+	if _, err := order.StartProcessing(&actor); err != nil {
+		t.Fatalf("StartProcessing failed: %v", err)
+	}
+
+	if _, err := order.Ship(&actor, "JNE", ""); err == nil {
+		t.Error("Expected Ship without tracking number to fail")
+	}
+
+	history, err := order.Ship(&actor, "JNE", "JNE123")
+	if err != nil {
+		t.Fatalf("Ship failed: %v", err)
+	}
+	if *history.FromStatus != OrderStatusProcessing || history.ToStatus != OrderStatusShipped {
+		t.Errorf("Unexpected history entry %s -> %s", *history.FromStatus, history.ToStatus)
+	}
+	if order.ShippingTrackingNumber == nil || *order.ShippingTrackingNumber != "JNE123" {
+		t.Error("Expected tracking number to be set")
+	}
+
+	if _, err := order.Cancel(&actor, "too late"); err == nil {
+		t.Error("Expected Cancel of a shipped order to fail")
+	}
+
+	if _, err := order.MarkAsDelivered(&actor); err != nil {
+		t.Fatalf("MarkAsDelivered failed: %v", err)
+	}
+	if order.DeliveredAt == nil {
+		t.Error("Expected delivered_at to be set")
+	}
+
+	if _, err := order.Refund(&actor, "damaged"); err != nil {
+		t.Fatalf("Refund failed: %v", err)
+	}
+	if order.PaymentStatus != PaymentStatusRefunded {
+		t.Errorf("Expected payment status refunded, got %s", order.PaymentStatus)
+	}
+	if !order.IsTerminalStatus() {
+		t.Error("Expected refunded order to be terminal")
+	}
+}
+
+func TestOrderCancelledRefundRequiresPayment(t *testing.T) {
+	order := newTestOrder()
+
+	if _, err := order.Cancel(nil, "changed mind"); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	if order.CanTransitionTo(OrderStatusRefunded) {
+		t.Error("Expected unpaid cancelled order not to be refundable")
+	}
+
+	order.PaymentStatus = PaymentStatusPaid
+	if !order.CanTransitionTo(OrderStatusRefunded) {
+		t.Error("Expected paid cancelled order to be refundable")
+	}
+}
+
+func TestPaymentStatusTransitions(t *testing.T) {
+	cases := []struct {
+		from, to PaymentStatus
+		want     bool
+	}{
+		{PaymentStatusPending, PaymentStatusPaid, true},
+		{PaymentStatusPending, PaymentStatusFailed, true},
+		{PaymentStatusFailed, PaymentStatusPending, true},
+		{PaymentStatusFailed, PaymentStatusPaid, true},
+		{PaymentStatusPartiallyPaid, PaymentStatusPaid, true},
+		{PaymentStatusPaid, PaymentStatusFailed, false},
+		{PaymentStatusPaid, PaymentStatusPending, false},
+		{PaymentStatusPaid, PaymentStatusPaid, false},
+		{PaymentStatusRefunded, PaymentStatusPaid, false},
+	}
+	for _, tc := range cases {
+		if got := tc.from.CanTransitionTo(tc.to); got != tc.want {
+			t.Errorf("%s -> %s: got %v, want %v", tc.from, tc.to, got, tc.want)
+		}
+	}
+}
+
+func TestOrderPaymentStart(t *testing.T) {
+	order := newTestOrder()
+	order.TotalAmount = decimal.NewFromInt(100000)
+
+	if err := order.CanStartPayment(); err != nil {
+		t.Fatalf("Expected pending order to be payable: %v", err)
+	}
+	if got := order.PaymentStatusFor(decimal.NewFromInt(40000)); got != PaymentStatusPartiallyPaid {
+		t.Errorf("Expected partial amount to leave order partially paid, got %s", got)
+	}
+	if got := order.PaymentStatusFor(decimal.NewFromInt(100000)); got != PaymentStatusPaid {
+		t.Errorf("Expected full amount to leave order paid, got %s", got)
+	}
+
+	// A partly paid order is paid off with a charge for the rest
+	order.PaymentStatus = PaymentStatusPartiallyPaid
+	order.PaidAmount = decimal.NewFromInt(40000)
+	if err := order.CanStartPayment(); err != nil {
+		t.Fatalf("Expected partially paid order to be payable: %v", err)
+	}
+	if due := order.AmountDue(); !due.Equal(decimal.NewFromInt(60000)) {
+		t.Errorf("Expected 60000 due, got %s", due)
+	}
+
+	order.PaymentStatus = PaymentStatusPaid
+	order.PaidAmount = order.TotalAmount
+	if err := order.CanStartPayment(); err == nil {
+		t.Error("Expected paid order not to be payable again")
+	}
+
+	order.PaymentStatus = PaymentStatusFailed
+	if _, err := order.Cancel(nil, "changed mind"); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	if err := order.CanStartPayment(); err == nil {
+		t.Error("Expected cancelled order not to be payable")
+	}
+}
+
+func TestOrderCalculateTotals(t *testing.T) {
+	order := newTestOrder()
+	order.Items = []*OrderItem{
+		NewOrderItem(order.ID, "Earbuds", decimal.NewFromInt(150000), 2),
+		NewOrderItem(order.ID, "Case", decimal.NewFromInt(50000), 1),
+	}
+	order.ShippingAmount = decimal.NewFromInt(15000)
+	order.DiscountAmount = decimal.NewFromInt(5000)
+
+	order.CalculateTotals()
+
+	if !order.Subtotal.Equal(decimal.NewFromInt(350000)) {
+		t.Errorf("Expected subtotal 350000, got %s", order.Subtotal)
+	}
+	if !order.TotalAmount.Equal(decimal.NewFromInt(360000)) {
+		t.Errorf("Expected total 360000, got %s", order.TotalAmount)
+	}
+	if err := order.Validate(); err != nil {
+		t.Errorf("Expected order to be valid, got %v", err)
+	}
+}
+
+func TestOrderApplyTax(t *testing.T) {
+	order := newTestOrder()
+	order.Items = []*OrderItem{
+		NewOrderItem(order.ID, "Earbuds", decimal.NewFromInt(111000), 1),
+	}
+	order.ShippingAmount = decimal.NewFromInt(10000)
+
+	order.ApplyTax(decimal.NewFromInt(11), false)
+	if !order.TaxAmount.Equal(decimal.NewFromInt(12210)) {
+		t.Errorf("Expected exclusive tax 12210, got %s", order.TaxAmount)
+	}
+	if !order.TotalAmount.Equal(decimal.NewFromInt(133210)) {
+		t.Errorf("Expected total 133210, got %s", order.TotalAmount)
+	}
+
+	order.ApplyTax(decimal.NewFromInt(11), true)
+	if !order.TaxAmount.Equal(decimal.NewFromInt(11000)) {
+		t.Errorf("Expected inclusive tax 11000, got %s", order.TaxAmount)
+	}
+	if !order.TotalAmount.Equal(decimal.NewFromInt(121000)) {
+		t.Errorf("Expected total 121000, got %s", order.TotalAmount)
+	}
+}
@@ -2,10 +2,12 @@ package entity
 
 import (
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/shopspring/decimal"
 )
 
@@ -66,6 +68,37 @@ type PartUsage struct {
 	Supplier    *string         `json:"supplier,omitempty"`
 }
 
+// PartUsages is the list of parts used in a repair, stored as JSONB
+type PartUsages []PartUsage
+
+// Value implements the driver.Valuer interface for database storage
+func (p PartUsages) Value() (driver.Value, error) {
+	if p == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(p)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (p *PartUsages) Scan(value interface{}) error {
+	*p = PartUsages{}
+	if value == nil {
+		return nil
+	}
+
+	var b []byte
+	switch v := value.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into PartUsages", value)
+	}
+
+	return json.Unmarshal(b, p)
+}
+
 // TestResult represents test results after repair
 type TestResult struct {
 	TestName    string     `json:"test_name"`
@@ -75,11 +108,54 @@ type TestResult struct {
 	TestedBy    *uuid.UUID `json:"tested_by,omitempty"`
 }
 
+// TestResults is the list of tests run after a repair, stored as JSONB
+type TestResults []TestResult
+
+// Value implements the driver.Valuer interface for database storage
+func (t TestResults) Value() (driver.Value, error) {
+	if t == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(t)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (t *TestResults) Scan(value interface{}) error {
+	*t = TestResults{}
+	if value == nil {
+		return nil
+	}
+
+	var b []byte
+	switch v := value.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into TestResults", value)
+	}
+
+	// Tickets created before test results were a list default to an empty object
+	if len(b) == 0 || b[0] != '[' {
+		return nil
+	}
+	return json.Unmarshal(b, t)
+}
+
 // RepairTicket represents a detailed repair workflow ticket
 type RepairTicket struct {
 	// Primary identification
-	ID      uuid.UUID `json:"id" db:"id"`
-	ClaimID uuid.UUID `json:"claim_id" db:"claim_id"`
+	ID           uuid.UUID `json:"id" db:"id"`
+	TicketNumber string    `json:"ticket_number" db:"ticket_number"`
+	ClaimID      uuid.UUID `json:"claim_id" db:"claim_id"`
+
+	// Work order
+	Priority            ClaimPriority    `json:"priority" db:"priority"`
+	EstimatedHours      *decimal.Decimal `json:"estimated_hours,omitempty" db:"estimated_hours"`
+	Description         *string          `json:"description,omitempty" db:"description"`
+	RequiredParts       pq.StringArray   `json:"required_parts,omitempty" db:"required_parts"`
+	SpecialInstructions *string          `json:"special_instructions,omitempty" db:"special_instructions"`
 
 	// Technician assignment
 	TechnicianID uuid.UUID `json:"technician_id" db:"technician_id"`
@@ -94,11 +170,11 @@ type RepairTicket struct {
 	Status RepairStatus `json:"status" db:"status"`
 
 	// Technical details
-	Diagnosis   string   `json:"diagnosis" db:"diagnosis"`
-	RepairSteps []string `json:"repair_steps,omitempty" db:"repair_steps"`
+	Diagnosis   string         `json:"diagnosis" db:"diagnosis"`
+	RepairSteps pq.StringArray `json:"repair_steps,omitempty" db:"repair_steps"`
 
 	// Parts and labor
-	PartsUsed  []PartUsage      `json:"parts_used,omitempty" db:"parts_used"`
+	PartsUsed  PartUsages       `json:"parts_used,omitempty" db:"parts_used"`
 	LaborHours decimal.Decimal  `json:"labor_hours" db:"labor_hours"`
 	HourlyRate *decimal.Decimal `json:"hourly_rate,omitempty" db:"hourly_rate"`
 	PartsCost  decimal.Decimal  `json:"parts_cost" db:"parts_cost"`
@@ -106,22 +182,30 @@ type RepairTicket struct {
 	TotalCost  decimal.Decimal  `json:"total_cost" db:"total_cost"`
 
 	// Quality assurance
-	QualityCheckPassed *bool        `json:"quality_check_passed,omitempty" db:"quality_check_passed"`
-	QualityNotes       *string      `json:"quality_notes,omitempty" db:"quality_notes"`
-	TestResults        []TestResult `json:"test_results,omitempty" db:"test_results"`
+	QualityCheckPassed *bool       `json:"quality_check_passed,omitempty" db:"quality_check_passed"`
+	QualityNotes       *string     `json:"quality_notes,omitempty" db:"quality_notes"`
+	QualityCheckedBy   *uuid.UUID  `json:"quality_checked_by,omitempty" db:"quality_checked_by"`
+	QualityCheckedAt   *time.Time  `json:"quality_checked_at,omitempty" db:"quality_checked_at"`
+	TestResults        TestResults `json:"test_results,omitempty" db:"test_results"`
 
 	// Documentation
-	BeforePhotos  []string `json:"before_photos,omitempty" db:"before_photos"`
-	AfterPhotos   []string `json:"after_photos,omitempty" db:"after_photos"`
-	ProcessPhotos []string `json:"process_photos,omitempty" db:"process_photos"`
+	BeforePhotos  pq.StringArray `json:"before_photos,omitempty" db:"before_photos"`
+	AfterPhotos   pq.StringArray `json:"after_photos,omitempty" db:"after_photos"`
+	ProcessPhotos pq.StringArray `json:"process_photos,omitempty" db:"process_photos"`
 
 	// Technical notes
 	TechnicianNotes *string `json:"technician_notes,omitempty" db:"technician_notes"`
 	SupervisorNotes *string `json:"supervisor_notes,omitempty" db:"supervisor_notes"`
 
 	// Timestamps
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Joined from the claim and the technician (read only)
+	StorefrontID   uuid.UUID `json:"storefront_id" db:"storefront_id"`
+	ClaimNumber    string    `json:"claim_number" db:"claim_number"`
+	TechnicianName *string   `json:"technician_name,omitempty" db:"technician_name"`
 
 	// Computed fields (not stored in database)
 	Duration        string   `json:"duration" db:"-"`
@@ -140,16 +224,18 @@ func NewRepairTicket(claimID, technicianID uuid.UUID, targetCompletion *time.Tim
 		AssignedAt:           now,
 		TargetCompletionDate: targetCompletion,
 		Status:               RepairStatusAssigned,
+		Priority:             ClaimPriorityNormal,
 		LaborHours:           decimal.Zero,
 		PartsCost:            decimal.Zero,
 		LaborCost:            decimal.Zero,
 		TotalCost:            decimal.Zero,
-		PartsUsed:            []PartUsage{},
-		TestResults:          []TestResult{},
-		RepairSteps:          []string{},
-		BeforePhotos:         []string{},
-		AfterPhotos:          []string{},
-		ProcessPhotos:        []string{},
+		PartsUsed:            PartUsages{},
+		TestResults:          TestResults{},
+		RepairSteps:          pq.StringArray{},
+		RequiredParts:        pq.StringArray{},
+		BeforePhotos:         pq.StringArray{},
+		AfterPhotos:          pq.StringArray{},
+		ProcessPhotos:        pq.StringArray{},
 		CreatedAt:            now,
 		UpdatedAt:            now,
 	}
@@ -169,6 +255,9 @@ func (rt *RepairTicket) Validate() error {
 	if !rt.Status.Valid() {
 		return fmt.Errorf("invalid repair status: %s", rt.Status)
 	}
+	if !rt.Priority.Valid() {
+		return fmt.Errorf("invalid repair priority: %s", rt.Priority)
+	}
 
 	// Validate diagnosis for non-assigned tickets
 	if rt.Status != RepairStatusAssigned && rt.Diagnosis == "" {
@@ -197,6 +286,23 @@ func (rt *RepairTicket) Validate() error {
 	return nil
 }
 
+// Reassign hands the repair to another technician before work has started
+func (rt *RepairTicket) Reassign(technicianID uuid.UUID, targetCompletion *time.Time) error {
+	if rt.Status != RepairStatusAssigned {
+		return fmt.Errorf("can only reassign repairs that have not started, current status: %s", rt.Status)
+	}
+
+	now := time.Now()
+	rt.TechnicianID = technicianID
+	rt.AssignedAt = now
+	if targetCompletion != nil {
+		rt.TargetCompletionDate = targetCompletion
+	}
+	rt.UpdatedAt = now
+
+	return nil
+}
+
 // Start starts the repair work
 func (rt *RepairTicket) Start(diagnosis string) error {
 	if rt.Status != RepairStatusAssigned {
@@ -339,6 +445,25 @@ func (rt *RepairTicket) Complete(qualityCheckPassed bool, qualityNotes *string)
 	return nil
 }
 
+// ReviewQualityCheck records a supervisor's quality check of a completed repair,
+// overriding the result recorded at completion
+func (rt *RepairTicket) ReviewQualityCheck(passed bool, reviewedBy uuid.UUID, notes string) error {
+	if rt.Status != RepairStatusCompleted {
+		return fmt.Errorf("can only quality check completed repairs, current status: %s", rt.Status)
+	}
+
+	now := time.Now()
+	rt.QualityCheckPassed = &passed
+	rt.QualityCheckedBy = &reviewedBy
+	rt.QualityCheckedAt = &now
+	if notes != "" {
+		rt.SupervisorNotes = &notes
+	}
+	rt.UpdatedAt = now
+
+	return nil
+}
+
 // MarkFailed marks the repair as failed
 func (rt *RepairTicket) MarkFailed(reason string) error {
 	if rt.Status == RepairStatusCompleted || rt.Status == RepairStatusFailed || rt.Status == RepairStatusCancelled {
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestRepairTicketCompletesClaimRepair(t *testing.T) {
+	claim := NewWarrantyClaim(uuid.New(), uuid.New(), uuid.New(), uuid.New())
+	claim.Status = ClaimStatusValidated
+	technician, supervisor := uuid.New(), uuid.New()
+
+	ticket := NewRepairTicket(claim.ID, technician, nil)
+	if err := claim.AssignTechnician(technician, supervisor, nil); err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+
+	other := uuid.New()
+	if err := ticket.Reassign(other, nil); err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+	if ticket.TechnicianID != other {
+		t.Errorf("technician = %s, want %s", ticket.TechnicianID, other)
+	}
+
+	if err := ticket.Start("Battery does not hold a charge"); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := claim.StartRepair(other); err != nil {
+		t.Fatalf("start claim repair: %v", err)
+	}
+	if err := ticket.Reassign(uuid.New(), nil); err == nil {
+		t.Error("reassigning a started repair should fail")
+	}
+
+	ticket.AddPartUsage("BAT-01", "Battery", 2, decimal.NewFromInt(150000))
+	rate := decimal.NewFromInt(50000)
+	ticket.HourlyRate = &rate
+	ticket.LaborHours = decimal.NewFromFloat(1.5)
+	if err := ticket.Complete(true, nil); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+
+	if err := claim.CompleteRepairFromTicket(other, "Replaced battery", ticket); err != nil {
+		t.Fatalf("complete claim repair: %v", err)
+	}
+	if claim.Status != ClaimStatusRepaired {
+		t.Errorf("claim status = %s, want repaired", claim.Status)
+	}
+	if !claim.RepairLaborCost.Equal(decimal.NewFromInt(75000)) {
+		t.Errorf("labor cost = %s, want 75000", claim.RepairLaborCost)
+	}
+	if !claim.RepairPartsCost.Equal(decimal.NewFromInt(300000)) {
+		t.Errorf("parts cost = %s, want 300000", claim.RepairPartsCost)
+	}
+	if !claim.RepairCost.Equal(decimal.NewFromInt(375000)) {
+		t.Errorf("repair cost = %s, want 375000", claim.RepairCost)
+	}
+
+	if err := ticket.ReviewQualityCheck(false, supervisor, "Case does not close"); err != nil {
+		t.Fatalf("review: %v", err)
+	}
+	if ticket.QualityCheckPassed == nil || *ticket.QualityCheckPassed {
+		t.Error("review should override the completion quality check")
+	}
+	if ticket.QualityCheckedBy == nil || *ticket.QualityCheckedBy != supervisor {
+		t.Errorf("quality checked by = %v, want %s", ticket.QualityCheckedBy, supervisor)
+	}
+}
+
+func TestRepairTicketReviewRequiresCompletion(t *testing.T) {
+	ticket := NewRepairTicket(uuid.New(), uuid.New(), nil)
+	if err := ticket.ReviewQualityCheck(true, uuid.New(), ""); err == nil {
+		t.Error("reviewing an assigned repair should fail")
+	}
+
+	ticket.Priority = ClaimPriority("whenever")
+	if err := ticket.Validate(); err == nil {
+		t.Error("invalid priority should fail validation")
+	}
+}
@@ -0,0 +1,217 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShipmentStatus represents the courier-independent state of a shipment
+type ShipmentStatus string
+
+const (
+	ShipmentStatusUnknown        ShipmentStatus = "unknown"
+	ShipmentStatusPickupPending  ShipmentStatus = "pickup_pending"   // Label booked, waiting for the courier
+	ShipmentStatusPickedUp       ShipmentStatus = "picked_up"        // Courier has the parcel
+	ShipmentStatusInTransit      ShipmentStatus = "in_transit"       // Moving between hubs
+	ShipmentStatusOutForDelivery ShipmentStatus = "out_for_delivery" // With the delivery driver
+	ShipmentStatusDelivered      ShipmentStatus = "delivered"        // Received by the recipient
+	ShipmentStatusDeliveryFailed ShipmentStatus = "delivery_failed"  // Delivery attempt failed
+	ShipmentStatusReturning      ShipmentStatus = "returning"        // On its way back to the sender
+	ShipmentStatusReturned       ShipmentStatus = "returned"         // Back with the sender
+	ShipmentStatusException      ShipmentStatus = "exception"        // Cancelled, lost or damaged
+)
+
+// Valid validates the shipment status
+func (s ShipmentStatus) Valid() bool {
+	switch s {
+	case ShipmentStatusUnknown, ShipmentStatusPickupPending, ShipmentStatusPickedUp, ShipmentStatusInTransit,
+		ShipmentStatusOutForDelivery, ShipmentStatusDelivered, ShipmentStatusDeliveryFailed,
+		ShipmentStatusReturning, ShipmentStatusReturned, ShipmentStatusException:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of ShipmentStatus
+func (s ShipmentStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for database storage
+func (s ShipmentStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (s *ShipmentStatus) Scan(value interface{}) error {
+	if value == nil {
+		*s = ShipmentStatusUnknown
+		return nil
+	}
+	switch v := value.(type) {
+	case string:
+		*s = ShipmentStatus(v)
+		return nil
+	case []byte:
+		*s = ShipmentStatus(v)
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into ShipmentStatus", value)
+}
+
+// Shipment is a parcel tracked by courier and tracking number. Its current
+// state is taken from the newest event in its history.
+type Shipment struct {
+	ID              uuid.UUID      `json:"id" db:"id"`
+	CourierCode     string         `json:"courier_code" db:"courier_code"`
+	TrackingNumber  string         `json:"tracking_number" db:"tracking_number"`
+	OrderID         *uuid.UUID     `json:"order_id,omitempty" db:"order_id"`
+	WarrantyClaimID *uuid.UUID     `json:"warranty_claim_id,omitempty" db:"warranty_claim_id"`
+	Status          ShipmentStatus `json:"status" db:"status"`
+	StatusText      *string        `json:"status_text,omitempty" db:"status_text"`
+	Location        *string        `json:"location,omitempty" db:"location"`
+	LastEventAt     *time.Time     `json:"last_event_at,omitempty" db:"last_event_at"`
+	DeliveredAt     *time.Time     `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
+
+	// Events is the tracking history, oldest first (not stored on the shipment row)
+	Events []*ShipmentTrackingEvent `json:"events,omitempty" db:"-"`
+}
+
+// ShipmentTrackingEvent is a single normalized tracking event of a shipment
+type ShipmentTrackingEvent struct {
+	ID          uuid.UUID      `json:"id" db:"id"`
+	ShipmentID  uuid.UUID      `json:"shipment_id" db:"shipment_id"`
+	Status      ShipmentStatus `json:"status" db:"status"`
+	RawStatus   *string        `json:"raw_status,omitempty" db:"raw_status"`
+	Description *string        `json:"description,omitempty" db:"description"`
+	Location    *string        `json:"location,omitempty" db:"location"`
+	OccurredAt  time.Time      `json:"occurred_at" db:"occurred_at"`
+	DedupKey    string         `json:"-" db:"dedup_key"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+}
+
+// NewShipment creates a shipment with no tracking history yet
+func NewShipment(courierCode, trackingNumber string) *Shipment {
+	now := time.Now()
+	return &Shipment{
+		ID:             uuid.New(),
+		CourierCode:    courierCode,
+		TrackingNumber: trackingNumber,
+		Status:         ShipmentStatusUnknown,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// Validate validates the shipment
+func (s *Shipment) Validate() error {
+	if s.CourierCode == "" {
+		return fmt.Errorf("courier code is required")
+	}
+	if s.TrackingNumber == "" {
+		return fmt.Errorf("tracking number is required")
+	}
+	if !s.Status.Valid() {
+		return fmt.Errorf("invalid shipment status: %s", s.Status)
+	}
+	return nil
+}
+
+// IsDelivered returns true once the recipient has received the parcel
+func (s *Shipment) IsDelivered() bool {
+	return s.DeliveredAt != nil
+}
+
+// ApplyEvent updates the current state from a newly recorded event. Events
+// older than the latest one only affect the delivery time. Returns true if
+// the shipment changed.
+func (s *Shipment) ApplyEvent(event *ShipmentTrackingEvent) bool {
+	changed := false
+
+	if s.LastEventAt == nil || !event.OccurredAt.Before(*s.LastEventAt) {
+		occurredAt := event.OccurredAt
+		s.Status = event.Status
+		s.StatusText = event.Description
+		s.Location = event.Location
+		s.LastEventAt = &occurredAt
+		changed = true
+	}
+
+	// The first delivery scan is the actual delivery time
+	if event.Status == ShipmentStatusDelivered && (s.DeliveredAt == nil || event.OccurredAt.Before(*s.DeliveredAt)) {
+		deliveredAt := event.OccurredAt
+		s.DeliveredAt = &deliveredAt
+		changed = true
+	}
+
+	if changed {
+		s.UpdatedAt = time.Now()
+	}
+	return changed
+}
+
+// NewShipmentTrackingEvent creates a tracking event for a shipment
+func NewShipmentTrackingEvent(shipmentID uuid.UUID, status ShipmentStatus, rawStatus, description, location string, occurredAt time.Time) *ShipmentTrackingEvent {
+	event := &ShipmentTrackingEvent{
+		ID:         uuid.New(),
+		ShipmentID: shipmentID,
+		Status:     status,
+		OccurredAt: occurredAt,
+		CreatedAt:  time.Now(),
+	}
+	if rawStatus != "" {
+		event.RawStatus = &rawStatus
+	}
+	if description != "" {
+		event.Description = &description
+	}
+	if location != "" {
+		event.Location = &location
+	}
+	event.DedupKey = event.computeDedupKey()
+	return event
+}
+
+// Validate validates the shipment event
+func (e *ShipmentTrackingEvent) Validate() error {
+	if e.ShipmentID == uuid.Nil {
+		return fmt.Errorf("shipment ID is required")
+	}
+	if !e.Status.Valid() {
+		return fmt.Errorf("invalid shipment status: %s", e.Status)
+	}
+	if e.OccurredAt.IsZero() {
+		return fmt.Errorf("occurred_at is required")
+	}
+	return nil
+}
+
+// computeDedupKey hashes the event content so a resent event maps to the same key
+func (e *ShipmentTrackingEvent) computeDedupKey() string {
+	parts := []string{
+		e.Status.String(),
+		strings.ToUpper(strings.TrimSpace(stringValue(e.RawStatus))),
+		strings.TrimSpace(stringValue(e.Description)),
+		strings.TrimSpace(stringValue(e.Location)),
+		e.OccurredAt.UTC().Format(time.RFC3339),
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// stringValue dereferences an optional string
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
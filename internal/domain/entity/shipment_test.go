@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShipmentApplyEvent(t *testing.T) {
+	shipment := NewShipment("jne", "JNE123")
+	base := time.Date(2024, 1, 20, 8, 0, 0, 0, time.UTC)
+
+	pickedUp := NewShipmentTrackingEvent(shipment.ID, ShipmentStatusPickedUp, "M01", "Picked up", "Jakarta", base)
+	delivered := NewShipmentTrackingEvent(shipment.ID, ShipmentStatusDelivered, "D01", "Delivered", "Bandung", base.Add(26*time.Hour))
+	inTransit := NewShipmentTrackingEvent(shipment.ID, ShipmentStatusInTransit, "T01", "In transit", "Cikampek", base.Add(4*time.Hour))
+
+	if !shipment.ApplyEvent(pickedUp) || shipment.Status != ShipmentStatusPickedUp {
+		t.Fatalf("Expected picked_up after first event, got %s", shipment.Status)
+	}
+	if !shipment.ApplyEvent(delivered) || !shipment.IsDelivered() {
+		t.Fatal("Expected shipment to be delivered")
+	}
+	if !shipment.DeliveredAt.Equal(base.Add(26 * time.Hour)) {
+		t.Errorf("Expected delivery time from the delivery event, got %v", shipment.DeliveredAt)
+	}
+
+	// A late-arriving older event is history only
+	if shipment.ApplyEvent(inTransit) {
+		t.Error("Expected an older event not to change the shipment")
+	}
+	if shipment.Status != ShipmentStatusDelivered || *shipment.Location != "Bandung" {
+		t.Errorf("Expected current state to stay delivered in Bandung, got %s in %s", shipment.Status, *shipment.Location)
+	}
+}
+
+func TestShipmentTrackingEventDedupKey(t *testing.T) {
+	shipment := NewShipment("sicepat", "SC123")
+	at := time.Date(2024, 1, 20, 15, 30, 0, 0, time.FixedZone("WIB", 7*3600))
+
+	first := NewShipmentTrackingEvent(shipment.ID, ShipmentStatusDelivered, "delivered", "Paket diterima", "Bandung", at)
+	resent := NewShipmentTrackingEvent(shipment.ID, ShipmentStatusDelivered, "DELIVERED ", "Paket diterima", "Bandung", at.UTC())
+	other := NewShipmentTrackingEvent(shipment.ID, ShipmentStatusDelivered, "DELIVERED", "Paket diterima", "Bandung", at.Add(time.Minute))
+
+	if first.DedupKey != resent.DedupKey {
+		t.Error("Expected a resent event to have the same dedup key")
+	}
+	if first.DedupKey == other.DedupKey {
+		t.Error("Expected events at different times to have different dedup keys")
+	}
+}
@@ -0,0 +1,228 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// SparePart is a part in a storefront's service centre inventory
+type SparePart struct {
+	ID               uuid.UUID       `json:"id" db:"id"`
+	StorefrontID     uuid.UUID       `json:"storefront_id" db:"storefront_id"`
+	PartNumber       string          `json:"part_number" db:"part_number"`
+	Name             string          `json:"name" db:"name"`
+	Description      *string         `json:"description" db:"description"`
+	Supplier         *string         `json:"supplier" db:"supplier"`
+	UnitCost         decimal.Decimal `json:"unit_cost" db:"unit_cost"`
+	QuantityOnHand   int             `json:"quantity_on_hand" db:"quantity_on_hand"`
+	QuantityReserved int             `json:"quantity_reserved" db:"quantity_reserved"`
+	ReorderThreshold int             `json:"reorder_threshold" db:"reorder_threshold"`
+	ReorderQuantity  int             `json:"reorder_quantity" db:"reorder_quantity"`
+	IsActive         bool            `json:"is_active" db:"is_active"`
+	CreatedAt        time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at" db:"updated_at"`
+	DeletedAt        *time.Time      `json:"deleted_at" db:"deleted_at"`
+}
+
+// NewSparePart creates an active spare part with no stock
+func NewSparePart(storefrontID uuid.UUID, partNumber, name string, unitCost decimal.Decimal) *SparePart {
+	now := time.Now()
+	return &SparePart{
+		ID:           uuid.New(),
+		StorefrontID: storefrontID,
+		PartNumber:   strings.TrimSpace(partNumber),
+		Name:         strings.TrimSpace(name),
+		UnitCost:     unitCost,
+		IsActive:     true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// Validate validates the spare part
+func (p *SparePart) Validate() error {
+	if p.StorefrontID == uuid.Nil {
+		return fmt.Errorf("storefront ID is required")
+	}
+	if p.PartNumber == "" {
+		return fmt.Errorf("part number is required")
+	}
+	if len(p.PartNumber) > 100 {
+		return fmt.Errorf("part number must not exceed 100 characters")
+	}
+	if p.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if p.UnitCost.IsNegative() {
+		return fmt.Errorf("unit cost must not be negative")
+	}
+	if p.QuantityOnHand < 0 || p.QuantityReserved < 0 {
+		return fmt.Errorf("quantities must not be negative")
+	}
+	if p.QuantityReserved > p.QuantityOnHand {
+		return fmt.Errorf("reserved quantity must not exceed quantity on hand")
+	}
+	if p.ReorderThreshold < 0 || p.ReorderQuantity < 0 {
+		return fmt.Errorf("reorder threshold and quantity must not be negative")
+	}
+	return nil
+}
+
+// QuantityAvailable returns the stock on hand that is not reserved
+func (p *SparePart) QuantityAvailable() int {
+	return p.QuantityOnHand - p.QuantityReserved
+}
+
+// NeedsReorder returns true once the available stock drops to the reorder threshold
+func (p *SparePart) NeedsReorder() bool {
+	return p.QuantityAvailable() <= p.ReorderThreshold
+}
+
+// SparePartReservationStatus represents the state of a part needed by a repair
+type SparePartReservationStatus string
+
+const (
+	SparePartReservationStatusPending  SparePartReservationStatus = "pending"  // Waiting for stock
+	SparePartReservationStatusReserved SparePartReservationStatus = "reserved" // Stock held for the repair
+	SparePartReservationStatusConsumed SparePartReservationStatus = "consumed" // Used by the completed repair
+	SparePartReservationStatusReleased SparePartReservationStatus = "released" // Returned to inventory
+)
+
+// Valid validates the spare part reservation status
+func (s SparePartReservationStatus) Valid() bool {
+	switch s {
+	case SparePartReservationStatusPending, SparePartReservationStatusReserved,
+		SparePartReservationStatusConsumed, SparePartReservationStatusReleased:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of SparePartReservationStatus
+func (s SparePartReservationStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for database storage
+func (s SparePartReservationStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (s *SparePartReservationStatus) Scan(value interface{}) error {
+	if value == nil {
+		*s = SparePartReservationStatusPending
+		return nil
+	}
+	switch v := value.(type) {
+	case string:
+		*s = SparePartReservationStatus(v)
+		return nil
+	case []byte:
+		*s = SparePartReservationStatus(v)
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into SparePartReservationStatus", value)
+}
+
+// SparePartReservation records a quantity of a spare part needed by a repair
+// ticket, from the moment it is required until the repair consumes it or the
+// stock is released
+type SparePartReservation struct {
+	ID             uuid.UUID                  `json:"id" db:"id"`
+	SparePartID    uuid.UUID                  `json:"spare_part_id" db:"spare_part_id"`
+	RepairTicketID uuid.UUID                  `json:"repair_ticket_id" db:"repair_ticket_id"`
+	Quantity       int                        `json:"quantity" db:"quantity"`
+	Status         SparePartReservationStatus `json:"status" db:"status"`
+	UnitCost       *decimal.Decimal           `json:"unit_cost" db:"unit_cost"`
+	ReservedAt     *time.Time                 `json:"reserved_at" db:"reserved_at"`
+	ConsumedAt     *time.Time                 `json:"consumed_at" db:"consumed_at"`
+	ReleasedAt     *time.Time                 `json:"released_at" db:"released_at"`
+	CreatedAt      time.Time                  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time                  `json:"updated_at" db:"updated_at"`
+
+	// Read-only fields joined from the spare part
+	PartNumber string `json:"part_number" db:"part_number"`
+	PartName   string `json:"part_name" db:"part_name"`
+}
+
+// NewSparePartReservation creates a pending reservation of a part for a repair ticket
+func NewSparePartReservation(part *SparePart, ticketID uuid.UUID, quantity int) *SparePartReservation {
+	now := time.Now()
+	return &SparePartReservation{
+		ID:             uuid.New(),
+		SparePartID:    part.ID,
+		RepairTicketID: ticketID,
+		Quantity:       quantity,
+		Status:         SparePartReservationStatusPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		PartNumber:     part.PartNumber,
+		PartName:       part.Name,
+	}
+}
+
+// Validate validates the spare part reservation
+func (r *SparePartReservation) Validate() error {
+	if r.SparePartID == uuid.Nil {
+		return fmt.Errorf("spare part ID is required")
+	}
+	if r.RepairTicketID == uuid.Nil {
+		return fmt.Errorf("repair ticket ID is required")
+	}
+	if r.Quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+	if !r.Status.Valid() {
+		return fmt.Errorf("invalid spare part reservation status: %s", r.Status)
+	}
+	return nil
+}
+
+// IsOpen returns true while the reservation is waiting for or holding stock
+func (r *SparePartReservation) IsOpen() bool {
+	return r.Status == SparePartReservationStatusPending || r.Status == SparePartReservationStatusReserved
+}
+
+// Reserve marks the stock as held for the repair
+func (r *SparePartReservation) Reserve() error {
+	if r.Status != SparePartReservationStatusPending {
+		return fmt.Errorf("cannot reserve %s spare part reservation", r.Status)
+	}
+	now := time.Now()
+	r.Status = SparePartReservationStatusReserved
+	r.ReservedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
+
+// Consume marks the held stock as used by the repair at the given unit cost
+func (r *SparePartReservation) Consume(unitCost decimal.Decimal) error {
+	if r.Status != SparePartReservationStatusReserved {
+		return fmt.Errorf("cannot consume %s spare part reservation", r.Status)
+	}
+	now := time.Now()
+	r.Status = SparePartReservationStatusConsumed
+	r.UnitCost = &unitCost
+	r.ConsumedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
+
+// Release gives up the reservation, returning any held stock to inventory
+func (r *SparePartReservation) Release() error {
+	if !r.IsOpen() {
+		return fmt.Errorf("cannot release %s spare part reservation", r.Status)
+	}
+	now := time.Now()
+	r.Status = SparePartReservationStatusReleased
+	r.ReleasedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
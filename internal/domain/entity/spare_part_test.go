@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestSparePartNeedsReorder(t *testing.T) {
+	part := NewSparePart(uuid.New(), "BAT-01", "Battery", decimal.NewFromInt(150000))
+	part.QuantityOnHand = 10
+	part.ReorderThreshold = 3
+	if err := part.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	if part.NeedsReorder() {
+		t.Error("part with 10 available should not need reordering")
+	}
+
+	part.QuantityReserved = 7
+	if got := part.QuantityAvailable(); got != 3 {
+		t.Errorf("available = %d, want 3", got)
+	}
+	if !part.NeedsReorder() {
+		t.Error("part at its reorder threshold should need reordering")
+	}
+
+	part.QuantityReserved = 11
+	if err := part.Validate(); err == nil {
+		t.Error("reserving more than on hand should fail validation")
+	}
+}
+
+func TestSparePartReservationLifecycle(t *testing.T) {
+	part := NewSparePart(uuid.New(), "BAT-01", "Battery", decimal.NewFromInt(150000))
+	reservation := NewSparePartReservation(part, uuid.New(), 2)
+	if err := reservation.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if reservation.PartNumber != "BAT-01" {
+		t.Errorf("part number = %s, want BAT-01", reservation.PartNumber)
+	}
+
+	if err := reservation.Consume(part.UnitCost); err == nil {
+		t.Error("consuming a pending reservation should fail")
+	}
+	if err := reservation.Reserve(); err != nil {
+		t.Fatalf("reserve: %v", err)
+	}
+	if err := reservation.Consume(part.UnitCost); err != nil {
+		t.Fatalf("consume: %v", err)
+	}
+	if reservation.IsOpen() {
+		t.Error("consumed reservation should not be open")
+	}
+	if reservation.UnitCost == nil || !reservation.UnitCost.Equal(part.UnitCost) {
+		t.Errorf("unit cost = %v, want %s", reservation.UnitCost, part.UnitCost)
+	}
+	if err := reservation.Release(); err == nil {
+		t.Error("releasing a consumed reservation should fail")
+	}
+
+	pending := NewSparePartReservation(part, uuid.New(), 1)
+	if err := pending.Release(); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if pending.Status != SparePartReservationStatusReleased {
+		t.Errorf("status = %s, want released", pending.Status)
+	}
+}
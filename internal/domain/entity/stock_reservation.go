@@ -0,0 +1,140 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockReservationStatus represents the state of a stock reservation
+type StockReservationStatus string
+
+const (
+	StockReservationStatusActive    StockReservationStatus = "active"    // Stock held for an unpaid order
+	StockReservationStatusCommitted StockReservationStatus = "committed" // Order paid, stock consumed
+	StockReservationStatusReleased  StockReservationStatus = "released"  // Stock returned to inventory
+)
+
+// Valid validates the stock reservation status
+func (s StockReservationStatus) Valid() bool {
+	switch s {
+	case StockReservationStatusActive, StockReservationStatusCommitted, StockReservationStatusReleased:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of StockReservationStatus
+func (s StockReservationStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for database storage
+func (s StockReservationStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (s *StockReservationStatus) Scan(value interface{}) error {
+	if value == nil {
+		*s = StockReservationStatusActive
+		return nil
+	}
+	switch v := value.(type) {
+	case string:
+		*s = StockReservationStatus(v)
+		return nil
+	case []byte:
+		*s = StockReservationStatus(v)
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into StockReservationStatus", value)
+}
+
+// StockReservation records stock deducted for an order until the order goes
+// ahead (committed) or is cancelled (released)
+type StockReservation struct {
+	ID               uuid.UUID              `json:"id" db:"id"`
+	OrderID          uuid.UUID              `json:"order_id" db:"order_id"`
+	ProductID        uuid.UUID              `json:"product_id" db:"product_id"`
+	ProductVariantID *uuid.UUID             `json:"product_variant_id" db:"product_variant_id"`
+	Quantity         int                    `json:"quantity" db:"quantity"`
+	Status           StockReservationStatus `json:"status" db:"status"`
+	ExpiresAt        time.Time              `json:"expires_at" db:"expires_at"`
+	ReleasedAt       *time.Time             `json:"released_at" db:"released_at"`
+	ReleaseReason    *string                `json:"release_reason" db:"release_reason"`
+	CreatedAt        time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at" db:"updated_at"`
+}
+
+// NewStockReservation creates an active reservation that expires after ttl
+func NewStockReservation(orderID, productID uuid.UUID, variantID *uuid.UUID, quantity int, ttl time.Duration) *StockReservation {
+	now := time.Now()
+	return &StockReservation{
+		ID:               uuid.New(),
+		OrderID:          orderID,
+		ProductID:        productID,
+		ProductVariantID: variantID,
+		Quantity:         quantity,
+		Status:           StockReservationStatusActive,
+		ExpiresAt:        now.Add(ttl),
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+}
+
+// Validate validates the stock reservation
+func (r *StockReservation) Validate() error {
+	if r.OrderID == uuid.Nil {
+		return fmt.Errorf("order ID is required")
+	}
+	if r.ProductID == uuid.Nil {
+		return fmt.Errorf("product ID is required")
+	}
+	if r.Quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+	if !r.Status.Valid() {
+		return fmt.Errorf("invalid stock reservation status: %s", r.Status)
+	}
+	return nil
+}
+
+// IsActive returns true if the reservation still holds stock
+func (r *StockReservation) IsActive() bool {
+	return r.Status == StockReservationStatusActive
+}
+
+// IsExpired returns true if an active reservation has passed its expiry time
+func (r *StockReservation) IsExpired() bool {
+	return r.IsActive() && time.Now().After(r.ExpiresAt)
+}
+
+// Commit marks the reserved stock as consumed by a paid order
+func (r *StockReservation) Commit() error {
+	if !r.IsActive() {
+		return fmt.Errorf("cannot commit %s stock reservation", r.Status)
+	}
+	r.Status = StockReservationStatusCommitted
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// Release marks the reserved stock as returned to inventory. Committed stock
+// is released too when its order is cancelled.
+func (r *StockReservation) Release(reason string) error {
+	if r.Status == StockReservationStatusReleased {
+		return fmt.Errorf("cannot release %s stock reservation", r.Status)
+	}
+	now := time.Now()
+	r.Status = StockReservationStatusReleased
+	r.ReleasedAt = &now
+	if reason != "" {
+		r.ReleaseReason = &reason
+	}
+	r.UpdatedAt = now
+	return nil
+}
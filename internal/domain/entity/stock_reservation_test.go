@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestStockReservationReleaseCommittedStock(t *testing.T) {
+	reservation := NewStockReservation(uuid.New(), uuid.New(), nil, 2, time.Hour)
+
+	if err := reservation.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if err := reservation.Commit(); err == nil {
+		t.Error("Expected committing a committed reservation to fail")
+	}
+
+	if err := reservation.Release("Cancelled by customer"); err != nil {
+		t.Fatalf("Release of committed stock failed: %v", err)
+	}
+	if reservation.Status != StockReservationStatusReleased || reservation.ReleasedAt == nil {
+		t.Errorf("Expected released reservation with a release time, got %s", reservation.Status)
+	}
+	if reservation.ReleaseReason == nil || *reservation.ReleaseReason != "Cancelled by customer" {
+		t.Errorf("Expected release reason to be recorded, got %v", reservation.ReleaseReason)
+	}
+
+	if err := reservation.Release("again"); err == nil {
+		t.Error("Expected releasing a released reservation to fail")
+	}
+	if err := reservation.Commit(); err == nil {
+		t.Error("Expected committing a released reservation to fail")
+	}
+}
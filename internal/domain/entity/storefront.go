@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 // StorefrontStatus represents the status of a storefront
@@ -35,14 +36,20 @@ type StorefrontSettings struct {
 	EnableGuestCheckout      bool     `json:"enable_guest_checkout"`
 	RequirePhoneVerification bool     `json:"require_phone_verification"`
 	RequireEmailVerification bool     `json:"require_email_verification"`
-	AllowedPaymentMethods    []string `json:"allowed_payment_methods"`
-	MinOrderAmount           *float64 `json:"min_order_amount"`
-	MaxOrderAmount           *float64 `json:"max_order_amount"`
+	RequireCustomerTwoFactor bool     `json:"require_customer_two_factor"` // Customers must pass TOTP before using their account
+	AllowedPaymentMethods    []string `json:"allowed_payment_methods"`     // Methods, optionally as gateway:method to pick the gateway collecting them
+	MinOrderAmount           *float64 `json:"min_order_amount"`            // Checked against the order total, tax included
+	MaxOrderAmount           *float64 `json:"max_order_amount"`            // Checked against the order total, tax included; zero means no limit
 	Currency                 string   `json:"currency"`
 	Timezone                 string   `json:"timezone"`
 	Language                 string   `json:"language"`
 	ShippingZones            []string `json:"shipping_zones"`
-	TaxSettings              struct {
+	ShippingOrigin           struct {
+		Province string `json:"province"`
+		City     string `json:"city"`
+		District string `json:"district"`
+	} `json:"shipping_origin"` // Where parcels are sent from, used to quote shipping rates
+	TaxSettings struct {
 		EnableTax    bool    `json:"enable_tax"`
 		TaxRate      float64 `json:"tax_rate"`
 		TaxInclusive bool    `json:"tax_inclusive"`
@@ -55,6 +62,59 @@ type StorefrontSettings struct {
 	} `json:"social_media"`
 }
 
+// AllowsPaymentMethod reports whether the storefront accepts the payment
+// method; an empty list allows every method
+func (s StorefrontSettings) AllowsPaymentMethod(method string) bool {
+	if len(s.AllowedPaymentMethods) == 0 {
+		return true
+	}
+	for _, entry := range s.AllowedPaymentMethods {
+		if _, allowed := splitPaymentMethod(entry); allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckOrderAmount checks an order total, tax included, against the
+// storefront's minimum and maximum order amounts
+func (s StorefrontSettings) CheckOrderAmount(total decimal.Decimal) error {
+	if s.MinOrderAmount != nil {
+		min := decimal.NewFromFloat(*s.MinOrderAmount)
+		if total.LessThan(min) {
+			return fmt.Errorf("order amount must be at least %s", min.String())
+		}
+	}
+	if s.MaxOrderAmount != nil && *s.MaxOrderAmount > 0 {
+		max := decimal.NewFromFloat(*s.MaxOrderAmount)
+		if total.GreaterThan(max) {
+			return fmt.Errorf("order amount must not exceed %s", max.String())
+		}
+	}
+	return nil
+}
+
+// PaymentGatewayFor returns the gateway the storefront collects the payment
+// method through, e.g. durianpay for a durianpay:ewallet entry. It is empty
+// when the storefront leaves the choice to the platform default.
+func (s StorefrontSettings) PaymentGatewayFor(method string) string {
+	for _, entry := range s.AllowedPaymentMethods {
+		if gateway, allowed := splitPaymentMethod(entry); allowed == method && gateway != "" {
+			return gateway
+		}
+	}
+	return ""
+}
+
+// splitPaymentMethod splits an allowed payment method entry into its gateway
+// and method
+func splitPaymentMethod(entry string) (gateway, method string) {
+	if i := strings.Index(entry, ":"); i >= 0 {
+		return strings.ToLower(strings.TrimSpace(entry[:i])), strings.TrimSpace(entry[i+1:])
+	}
+	return "", strings.TrimSpace(entry)
+}
+
 // Value implements driver.Valuer interface for database storage
 func (s StorefrontSettings) Value() (driver.Value, error) {
 	return json.Marshal(s)
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestStorefrontSettingsPaymentMethods(t *testing.T) {
+	settings := StorefrontSettings{AllowedPaymentMethods: []string{"bank_transfer", "durianpay:ewallet"}}
+
+	if !settings.AllowsPaymentMethod("bank_transfer") || !settings.AllowsPaymentMethod("ewallet") {
+		t.Error("Expected listed methods to be allowed with or without a gateway")
+	}
+	if settings.AllowsPaymentMethod("credit_card") {
+		t.Error("Expected unlisted method to be rejected")
+	}
+	if got := settings.PaymentGatewayFor("ewallet"); got != "durianpay" {
+		t.Errorf("Expected ewallet to go through durianpay, got %q", got)
+	}
+	if got := settings.PaymentGatewayFor("bank_transfer"); got != "" {
+		t.Errorf("Expected bank_transfer to use the default gateway, got %q", got)
+	}
+
+	if !(StorefrontSettings{}).AllowsPaymentMethod("credit_card") {
+		t.Error("Expected an empty list to allow every method")
+	}
+}
+
+func TestStorefrontSettingsCheckOrderAmount(t *testing.T) {
+	min, max := 50000.0, 1000000.0
+	settings := StorefrontSettings{MinOrderAmount: &min, MaxOrderAmount: &max}
+
+	tests := []struct {
+		name    string
+		total   decimal.Decimal
+		wantErr bool
+	}{
+		{name: "below minimum", total: decimal.NewFromInt(49999), wantErr: true},
+		{name: "at minimum", total: decimal.NewFromInt(50000)},
+		{name: "at maximum", total: decimal.NewFromInt(1000000)},
+		{name: "above maximum", total: decimal.NewFromInt(1000001), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := settings.CheckOrderAmount(tt.total)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckOrderAmount(%s) error = %v, wantErr %v", tt.total, err, tt.wantErr)
+			}
+		})
+	}
+
+	// Tax pushes a subtotal under the maximum over it
+	order := NewOrder(uuid.New(), nil, "IDR")
+	order.Items = []*OrderItem{NewOrderItem(order.ID, "Earbuds", decimal.NewFromInt(950000), 1)}
+	order.ApplyTax(decimal.NewFromInt(11), false)
+	if err := settings.CheckOrderAmount(order.TotalAmount); err == nil {
+		t.Errorf("Expected a total of %s including tax to exceed the maximum", order.TotalAmount)
+	}
+
+	zero := 0.0
+	if err := (StorefrontSettings{MaxOrderAmount: &zero}).CheckOrderAmount(decimal.NewFromInt(5000000)); err != nil {
+		t.Errorf("Expected a zero maximum to mean no limit, got %v", err)
+	}
+}
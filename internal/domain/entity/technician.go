@@ -0,0 +1,264 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTechnicianTimezone is the timezone of working hours when none is set
+const DefaultTechnicianTimezone = "Asia/Jakarta"
+
+// DefaultMaxConcurrentTickets is the capacity of a new technician
+const DefaultMaxConcurrentTickets = 5
+
+// WorkShift is a weekly working period of a technician. A shift that ends at
+// or before its start time runs past midnight into the next day.
+type WorkShift struct {
+	Day   time.Weekday `json:"day"`   // 0 = Sunday
+	Start string       `json:"start"` // HH:MM
+	End   string       `json:"end"`   // HH:MM
+}
+
+// WorkingHours is the weekly schedule of a technician. An empty schedule
+// means the technician takes work at any time.
+type WorkingHours []WorkShift
+
+// Validate validates the shifts of the schedule
+func (wh WorkingHours) Validate() error {
+	for _, shift := range wh {
+		if shift.Day < time.Sunday || shift.Day > time.Saturday {
+			return fmt.Errorf("invalid working hours day: %d", shift.Day)
+		}
+		if _, err := parseShiftClock(shift.Start); err != nil {
+			return fmt.Errorf("invalid working hours start %q: %w", shift.Start, err)
+		}
+		if _, err := parseShiftClock(shift.End); err != nil {
+			return fmt.Errorf("invalid working hours end %q: %w", shift.End, err)
+		}
+	}
+	return nil
+}
+
+// Covers reports whether a local time falls within one of the shifts
+func (wh WorkingHours) Covers(local time.Time) bool {
+	if len(wh) == 0 {
+		return true
+	}
+
+	minute := local.Hour()*60 + local.Minute()
+	yesterday := (local.Weekday() + 6) % 7
+	for _, shift := range wh {
+		start, errStart := parseShiftClock(shift.Start)
+		end, errEnd := parseShiftClock(shift.End)
+		if errStart != nil || errEnd != nil {
+			continue
+		}
+
+		if end > start {
+			if shift.Day == local.Weekday() && minute >= start && minute < end {
+				return true
+			}
+			continue
+		}
+
+		// Overnight shift: the evening of its day and the morning after
+		if shift.Day == local.Weekday() && minute >= start {
+			return true
+		}
+		if shift.Day == yesterday && minute < end {
+			return true
+		}
+	}
+	return false
+}
+
+// Value implements the driver.Valuer interface for database storage
+func (wh WorkingHours) Value() (driver.Value, error) {
+	if wh == nil {
+		return "[]", nil
+	}
+	return json.Marshal(wh)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (wh *WorkingHours) Scan(value interface{}) error {
+	if value == nil {
+		*wh = WorkingHours{}
+		return nil
+	}
+
+	var b []byte
+	switch v := value.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into WorkingHours", value)
+	}
+
+	return json.Unmarshal(b, wh)
+}
+
+// parseShiftClock parses HH:MM into minutes since midnight
+func parseShiftClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("must be HH:MM")
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// TechnicianSkill is a product category a technician repairs. A skill covers
+// the category's subcategories too.
+type TechnicianSkill struct {
+	CategoryID   uuid.UUID `json:"category_id" db:"category_id"`
+	CategoryName string    `json:"category_name" db:"category_name"`
+}
+
+// Technician is a seller user on a storefront's repair roster
+type Technician struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	StorefrontID uuid.UUID `json:"storefront_id" db:"storefront_id"`
+	UserID       uuid.UUID `json:"user_id" db:"user_id"`
+
+	// Capacity and availability
+	MaxConcurrentTickets int          `json:"max_concurrent_tickets" db:"max_concurrent_tickets"`
+	WorkingHours         WorkingHours `json:"working_hours" db:"working_hours"`
+	Timezone             string       `json:"timezone" db:"timezone"`
+	IsActive             bool         `json:"is_active" db:"is_active"`
+
+	// Timestamps
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+
+	// Read-only fields joined from the user
+	Name  string `json:"name" db:"name"`
+	Email string `json:"email" db:"email"`
+
+	// Skills are stored separately
+	Skills []TechnicianSkill `json:"skills" db:"-"`
+}
+
+// NewTechnician adds a user to a storefront's repair roster
+func NewTechnician(storefrontID, userID uuid.UUID) *Technician {
+	now := time.Now()
+	return &Technician{
+		ID:                   uuid.New(),
+		StorefrontID:         storefrontID,
+		UserID:               userID,
+		MaxConcurrentTickets: DefaultMaxConcurrentTickets,
+		WorkingHours:         WorkingHours{},
+		Timezone:             DefaultTechnicianTimezone,
+		IsActive:             true,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+		Skills:               []TechnicianSkill{},
+	}
+}
+
+// Validate validates the technician
+func (t *Technician) Validate() error {
+	if t.StorefrontID == uuid.Nil {
+		return fmt.Errorf("storefront ID is required")
+	}
+	if t.UserID == uuid.Nil {
+		return fmt.Errorf("user ID is required")
+	}
+	if t.MaxConcurrentTickets <= 0 {
+		return fmt.Errorf("max concurrent tickets must be positive")
+	}
+	if _, err := time.LoadLocation(t.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone: %s", t.Timezone)
+	}
+	return t.WorkingHours.Validate()
+}
+
+// SkillCategoryIDs returns the product categories the technician repairs
+func (t *Technician) SkillCategoryIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(t.Skills))
+	for _, skill := range t.Skills {
+		ids = append(ids, skill.CategoryID)
+	}
+	return ids
+}
+
+// IsOnShift reports whether the technician is working at the given time
+func (t *Technician) IsOnShift(at time.Time) bool {
+	location, err := time.LoadLocation(t.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	return t.WorkingHours.Covers(at.In(location))
+}
+
+// TechnicianWorkload is a technician with the repair work assigned to them
+type TechnicianWorkload struct {
+	Technician
+
+	// ActiveClaims counts claims assigned to the technician or in repair with
+	// them; it is the load measured against MaxConcurrentTickets
+	ActiveClaims int `json:"active_claims" db:"active_claims"`
+
+	// Open repair tickets of the technician, and those of them held up by parts
+	OpenTickets         int `json:"open_tickets" db:"open_tickets"`
+	WaitingPartsTickets int `json:"waiting_parts_tickets" db:"waiting_parts_tickets"`
+}
+
+// HasCapacity reports whether the technician can take another claim
+func (w *TechnicianWorkload) HasCapacity() bool {
+	return w.IsActive && w.ActiveClaims < w.MaxConcurrentTickets
+}
+
+// Utilization returns the share of the technician's capacity in use
+func (w *TechnicianWorkload) Utilization() float64 {
+	if w.MaxConcurrentTickets <= 0 {
+		return 1
+	}
+	return float64(w.ActiveClaims) / float64(w.MaxConcurrentTickets)
+}
+
+// TechnicianAssignmentStrategy picks the technician for a claim among the
+// candidates whose skills match the claimed product
+type TechnicianAssignmentStrategy interface {
+	SelectTechnician(candidates []*TechnicianWorkload, at time.Time) *TechnicianWorkload
+}
+
+// LeastLoadedAssignment picks the candidate with capacity left who uses the
+// smallest share of it, preferring technicians on shift. Ties go to the
+// technician with fewer active claims, then to the longest on the roster.
+type LeastLoadedAssignment struct{}
+
+// SelectTechnician implements TechnicianAssignmentStrategy
+func (LeastLoadedAssignment) SelectTechnician(candidates []*TechnicianWorkload, at time.Time) *TechnicianWorkload {
+	available := make([]*TechnicianWorkload, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.HasCapacity() {
+			available = append(available, candidate)
+		}
+	}
+	if len(available) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(available, func(i, j int) bool {
+		a, b := available[i], available[j]
+		if onShiftA, onShiftB := a.IsOnShift(at), b.IsOnShift(at); onShiftA != onShiftB {
+			return onShiftA
+		}
+		if a.Utilization() != b.Utilization() {
+			return a.Utilization() < b.Utilization()
+		}
+		if a.ActiveClaims != b.ActiveClaims {
+			return a.ActiveClaims < b.ActiveClaims
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return available[0]
+}
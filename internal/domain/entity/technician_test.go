@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestWorkingHoursCovers(t *testing.T) {
+	hours := WorkingHours{
+		{Day: time.Monday, Start: "09:00", End: "17:00"},
+		{Day: time.Friday, Start: "22:00", End: "06:00"},
+	}
+	if err := hours.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	// 2024-01-01 is a Monday
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, 1, day, hour, 30, 0, 0, time.UTC)
+	}
+	cases := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"monday morning", at(1, 9), true},
+		{"monday evening", at(1, 17), false},
+		{"tuesday", at(2, 10), false},
+		{"friday night", at(5, 23), true},
+		{"saturday early morning", at(6, 5), true},
+		{"saturday morning", at(6, 6), false},
+	}
+	for _, tc := range cases {
+		if got := hours.Covers(tc.at); got != tc.want {
+			t.Errorf("%s: covers = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	if !(WorkingHours{}).Covers(at(3, 3)) {
+		t.Error("an empty schedule should cover any time")
+	}
+	if err := (WorkingHours{{Day: time.Monday, Start: "9am", End: "17:00"}}).Validate(); err == nil {
+		t.Error("a start that is not HH:MM should fail validation")
+	}
+}
+
+func TestLeastLoadedAssignment(t *testing.T) {
+	storefrontID := uuid.New()
+	workload := func(active, max int, hours WorkingHours) *TechnicianWorkload {
+		technician := NewTechnician(storefrontID, uuid.New())
+		technician.MaxConcurrentTickets = max
+		technician.Timezone = "UTC"
+		technician.WorkingHours = hours
+		return &TechnicianWorkload{Technician: *technician, ActiveClaims: active}
+	}
+
+	// 2024-01-01 10:00 UTC is a Monday morning
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	weekdays := WorkingHours{{Day: time.Monday, Start: "08:00", End: "16:00"}}
+	nights := WorkingHours{{Day: time.Monday, Start: "20:00", End: "04:00"}}
+
+	full := workload(3, 3, weekdays)
+	busy := workload(3, 5, weekdays)
+	light := workload(4, 10, weekdays)
+	offShift := workload(0, 5, nights)
+
+	strategy := LeastLoadedAssignment{}
+	if got := strategy.SelectTechnician([]*TechnicianWorkload{full, busy, light, offShift}, now); got != light {
+		t.Errorf("selected %v, want the on-shift technician using the least of their capacity", got)
+	}
+	if got := strategy.SelectTechnician([]*TechnicianWorkload{full, offShift}, now); got != offShift {
+		t.Errorf("selected %v, want the off-shift technician when nobody on shift has capacity", got)
+	}
+
+	offShift.IsActive = false
+	if got := strategy.SelectTechnician([]*TechnicianWorkload{full, offShift}, now); got != nil {
+		t.Errorf("selected %v, want nobody when no active technician has capacity", got)
+	}
+}
@@ -163,6 +163,9 @@ type WarrantyBarcode struct {
 	PurchaseLocation *string    `json:"purchase_location,omitempty" db:"purchase_location"`
 	PurchaseInvoice  *string    `json:"purchase_invoice,omitempty" db:"purchase_invoice"`
 
+	// Storage key of the proof of purchase uploaded at registration
+	ProofOfPurchasePath *string `json:"proof_of_purchase_path,omitempty" db:"proof_of_purchase_path"`
+
 	// Status management
 	Status BarcodeStatus `json:"status" db:"status"`
 
@@ -340,6 +343,42 @@ func (wb *WarrantyBarcode) Activate(customerID uuid.UUID, purchaseDate time.Time
 	return nil
 }
 
+// Register activates a distributed barcode for the customer who bought the
+// product. Registration closes once the activation window since distribution
+// has passed; a zero window keeps it open.
+func (wb *WarrantyBarcode) Register(customerID uuid.UUID, purchaseDate time.Time, purchaseLocation, purchaseInvoice string, window time.Duration, now time.Time) error {
+	if err := wb.CheckRegistration(purchaseDate, window, now); err != nil {
+		return err
+	}
+
+	return wb.Activate(customerID, purchaseDate, purchaseLocation, purchaseInvoice)
+}
+
+// CheckRegistration returns why the barcode cannot be registered with the
+// given purchase date, if it cannot
+func (wb *WarrantyBarcode) CheckRegistration(purchaseDate time.Time, window time.Duration, now time.Time) error {
+	if wb.Status != BarcodeStatusDistributed || !wb.CanTransitionTo(BarcodeStatusActivated) {
+		return fmt.Errorf("barcode must be distributed before it is registered, current status: %s", wb.Status)
+	}
+	if purchaseDate.After(now) {
+		return fmt.Errorf("purchase date must not be in the future")
+	}
+	if deadline := wb.RegistrationDeadline(window); deadline != nil && now.After(*deadline) {
+		return fmt.Errorf("barcode must be registered by %s", deadline.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// RegistrationDeadline returns when registration closes for a barcode
+// distributed with the given activation window, or nil when it stays open
+func (wb *WarrantyBarcode) RegistrationDeadline(window time.Duration) *time.Time {
+	if window <= 0 || wb.DistributedAt == nil {
+		return nil
+	}
+	deadline := wb.DistributedAt.Add(window)
+	return &deadline
+}
+
 // MarkAsDistributed marks the barcode as distributed
 func (wb *WarrantyBarcode) MarkAsDistributed(distributedTo string, batchID *uuid.UUID, notes string) error {
 	if wb.Status != BarcodeStatusGenerated {
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestWarrantyBarcodeRegister(t *testing.T) {
+	distributedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := 90 * 24 * time.Hour
+	newBarcode := func(status BarcodeStatus) *WarrantyBarcode {
+		barcode := NewWarrantyBarcode(uuid.New(), uuid.New(), uuid.New(), 12)
+		barcode.Status = status
+		barcode.DistributedAt = &distributedAt
+		return barcode
+	}
+
+	customerID := uuid.New()
+	purchaseDate := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)
+
+	barcode := newBarcode(BarcodeStatusDistributed)
+	if err := barcode.Register(customerID, purchaseDate, "TechStore", "INV-1", window, now); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if barcode.Status != BarcodeStatusActivated {
+		t.Errorf("status = %s, want %s", barcode.Status, BarcodeStatusActivated)
+	}
+	if barcode.CustomerID == nil || *barcode.CustomerID != customerID {
+		t.Errorf("customer = %v, want %s", barcode.CustomerID, customerID)
+	}
+	if want := purchaseDate.AddDate(0, 12, 0); barcode.ExpiryDate == nil || !barcode.ExpiryDate.Equal(want) {
+		t.Errorf("expiry = %v, want %s", barcode.ExpiryDate, want)
+	}
+
+	cases := []struct {
+		name         string
+		status       BarcodeStatus
+		purchaseDate time.Time
+		now          time.Time
+	}{
+		{"generated barcode", BarcodeStatusGenerated, purchaseDate, now},
+		{"activated barcode", BarcodeStatusActivated, purchaseDate, now},
+		{"purchase in the future", BarcodeStatusDistributed, now.AddDate(0, 0, 1), now},
+		{"after the activation window", BarcodeStatusDistributed, purchaseDate, distributedAt.Add(window).Add(time.Hour)},
+	}
+	for _, tc := range cases {
+		if err := newBarcode(tc.status).Register(customerID, tc.purchaseDate, "", "", window, tc.now); err == nil {
+			t.Errorf("%s: register succeeded, want an error", tc.name)
+		}
+	}
+
+	if err := newBarcode(BarcodeStatusDistributed).Register(customerID, purchaseDate, "", "", 0, now.AddDate(5, 0, 0)); err != nil {
+		t.Errorf("register without a window: %v", err)
+	}
+}
@@ -264,6 +264,8 @@ type WarrantyClaim struct {
 
 	// Cost tracking
 	RepairCost      decimal.Decimal `json:"repair_cost" db:"repair_cost"`
+	RepairLaborCost decimal.Decimal `json:"repair_labor_cost" db:"repair_labor_cost"`
+	RepairPartsCost decimal.Decimal `json:"repair_parts_cost" db:"repair_parts_cost"`
 	ShippingCost    decimal.Decimal `json:"shipping_cost" db:"shipping_cost"`
 	ReplacementCost decimal.Decimal `json:"replacement_cost" db:"replacement_cost"`
 	TotalCost       decimal.Decimal `json:"total_cost" db:"total_cost"`
@@ -298,9 +300,20 @@ type WarrantyClaim struct {
 	CustomerFeedback           *string `json:"customer_feedback,omitempty" db:"customer_feedback"`
 	ProcessingTimeHours        *int    `json:"processing_time_hours,omitempty" db:"processing_time_hours"`
 
+	// SLA tracking
+	SLAPolicyHours   *int       `json:"sla_policy_hours,omitempty" db:"sla_policy_hours"`
+	SLADueAt         *time.Time `json:"sla_due_at,omitempty" db:"sla_due_at"`
+	SLAAtRiskAt      *time.Time `json:"sla_at_risk_at,omitempty" db:"sla_at_risk_at"`
+	SLAPausedAt      *time.Time `json:"sla_paused_at,omitempty" db:"sla_paused_at"`
+	SLAPausedSeconds int64      `json:"sla_paused_seconds" db:"sla_paused_seconds"`
+	SLAStoppedAt     *time.Time `json:"sla_stopped_at,omitempty" db:"sla_stopped_at"`
+	SLAEscalatedAt   *time.Time `json:"sla_escalated_at,omitempty" db:"sla_escalated_at"`
+	SLABreachedAt    *time.Time `json:"sla_breached_at,omitempty" db:"sla_breached_at"`
+
 	// Timestamps
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"-" db:"deleted_at"`
 
 	// Computed fields (not stored in database)
 	CanCancel      bool            `json:"can_cancel" db:"-"`
@@ -327,6 +340,8 @@ func NewWarrantyClaim(barcodeID, customerID, productID, storefrontID uuid.UUID)
 		Priority:        ClaimPriorityNormal,
 		DeliveryStatus:  DeliveryStatusNotShipped,
 		RepairCost:      decimal.Zero,
+		RepairLaborCost: decimal.Zero,
+		RepairPartsCost: decimal.Zero,
 		ShippingCost:    decimal.Zero,
 		ReplacementCost: decimal.Zero,
 		TotalCost:       decimal.Zero,
@@ -474,6 +489,14 @@ func (wc *WarrantyClaim) UpdateStatus(newStatus ClaimStatus, updatedBy *uuid.UUI
 		wc.ActualCompletionDate = &now
 	}
 
+	// Resolving the claim stops the SLA clock; any other move means the
+	// claim is being worked on again
+	if IsSLAResolvedStatus(newStatus) {
+		wc.StopSLA(wc.StatusUpdatedAt)
+	} else {
+		wc.ResumeSLA(wc.StatusUpdatedAt)
+	}
+
 	return nil
 }
 
@@ -540,6 +563,20 @@ func (wc *WarrantyClaim) CompleteRepair(updatedBy uuid.UUID, repairNotes string,
 	return wc.UpdateStatus(ClaimStatusRepaired, &updatedBy)
 }
 
+// CompleteRepairFromTicket completes the repair with the labor and parts cost
+// of the repair ticket that fixed the product
+func (wc *WarrantyClaim) CompleteRepairFromTicket(updatedBy uuid.UUID, repairNotes string, ticket *RepairTicket) error {
+	if err := wc.CompleteRepair(updatedBy, repairNotes, ticket.LaborCost.Add(ticket.PartsCost)); err != nil {
+		return err
+	}
+
+	wc.RepairLaborCost = ticket.LaborCost
+	wc.RepairPartsCost = ticket.PartsCost
+	wc.CalculateTotalCost()
+
+	return nil
+}
+
 // MarkAsReplaced marks the claim as replaced
 func (wc *WarrantyClaim) MarkAsReplaced(updatedBy uuid.UUID, replacementProductID uuid.UUID, replacementCost decimal.Decimal) error {
 	if wc.Status != ClaimStatusInRepair {
@@ -637,6 +674,8 @@ func (wc *WarrantyClaim) ComputeFields() {
 	// Set cost breakdown
 	wc.CostBreakdown = &CostBreakdown{
 		RepairCost:      wc.RepairCost,
+		LaborCost:       wc.RepairLaborCost,
+		PartsCost:       wc.RepairPartsCost,
 		ShippingCost:    wc.ShippingCost,
 		ReplacementCost: wc.ReplacementCost,
 		TotalCost:       wc.TotalCost,
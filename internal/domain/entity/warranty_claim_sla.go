@@ -0,0 +1,194 @@
+package entity
+
+import "time"
+
+// ClaimSLAState represents where a warranty claim stands against its processing SLA
+type ClaimSLAState string
+
+const (
+	ClaimSLAStateOnTrack  ClaimSLAState = "on_track" // Clock running, due-by not close
+	ClaimSLAStateAtRisk   ClaimSLAState = "at_risk"  // Clock running, breach imminent
+	ClaimSLAStateBreached ClaimSLAState = "breached" // Due-by passed before resolution
+	ClaimSLAStatePaused   ClaimSLAState = "paused"   // Waiting on the customer
+	ClaimSLAStateMet      ClaimSLAState = "met"      // Resolved before the due-by
+)
+
+// Valid validates the claim SLA state
+func (s ClaimSLAState) Valid() bool {
+	switch s {
+	case ClaimSLAStateOnTrack, ClaimSLAStateAtRisk, ClaimSLAStateBreached, ClaimSLAStatePaused, ClaimSLAStateMet:
+		return true
+	default:
+		return false
+	}
+}
+
+// ClaimSLAConfig holds the SLA settings applied to warranty claims
+type ClaimSLAConfig struct {
+	DefaultHours int     // SLA used when no warranty policy applies
+	AtRiskRatio  float64 // Share of the SLA budget used up before a claim is at risk
+}
+
+// claimSLAPriorityFactors scales the policy SLA by claim priority
+var claimSLAPriorityFactors = map[ClaimPriority]float64{
+	ClaimPriorityUrgent: 0.25,
+	ClaimPriorityHigh:   0.5,
+	ClaimPriorityNormal: 1,
+	ClaimPriorityLow:    1.5,
+}
+
+// ClaimSLABudget returns the processing time allowed for a claim of the
+// given priority under a policy SLA of slaHours
+func ClaimSLABudget(slaHours int, priority ClaimPriority) time.Duration {
+	factor, ok := claimSLAPriorityFactors[priority]
+	if !ok {
+		factor = 1
+	}
+	return time.Duration(float64(slaHours) * factor * float64(time.Hour))
+}
+
+// IsSLAResolvedStatus reports whether a claim in the status no longer counts
+// against its processing SLA
+func IsSLAResolvedStatus(status ClaimStatus) bool {
+	switch status {
+	case ClaimStatusRepaired, ClaimStatusReplaced, ClaimStatusShipped, ClaimStatusDelivered,
+		ClaimStatusCompleted, ClaimStatusRejected, ClaimStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// StartSLA starts tracking the claim against a policy SLA of slaHours
+func (wc *WarrantyClaim) StartSLA(slaHours int, atRiskRatio float64) {
+	wc.SLAPolicyHours = &slaHours
+	wc.RecalculateSLA(atRiskRatio)
+}
+
+// RecalculateSLA recomputes the due-by after the claim priority changed.
+// Time spent waiting on the customer is added on top of the budget.
+func (wc *WarrantyClaim) RecalculateSLA(atRiskRatio float64) {
+	if wc.SLAPolicyHours == nil || wc.SLAStoppedAt != nil {
+		return
+	}
+
+	budget := ClaimSLABudget(*wc.SLAPolicyHours, wc.Priority)
+	start := wc.ClaimDate.Add(time.Duration(wc.SLAPausedSeconds) * time.Second)
+	dueAt := start.Add(budget)
+	atRiskAt := start.Add(time.Duration(float64(budget) * atRiskRatio))
+	wc.SLADueAt = &dueAt
+	wc.SLAAtRiskAt = &atRiskAt
+
+	// A new due-by deserves a new warning
+	if wc.SLABreachedAt == nil {
+		wc.SLAEscalatedAt = nil
+	}
+}
+
+// PauseSLA stops the clock while the claim waits on the customer
+func (wc *WarrantyClaim) PauseSLA(at time.Time) {
+	if wc.SLADueAt == nil || wc.SLAPausedAt != nil || wc.SLAStoppedAt != nil {
+		return
+	}
+	if wc.SLABreachedAt == nil && !at.Before(*wc.SLADueAt) {
+		breachedAt := *wc.SLADueAt
+		wc.SLABreachedAt = &breachedAt
+	}
+	wc.SLAPausedAt = &at
+}
+
+// ResumeSLA restarts the clock and pushes the due-by back by the time spent
+// waiting on the customer
+func (wc *WarrantyClaim) ResumeSLA(at time.Time) {
+	if wc.SLAPausedAt == nil {
+		return
+	}
+
+	paused := at.Sub(*wc.SLAPausedAt)
+	if paused > 0 {
+		wc.SLAPausedSeconds += int64(paused / time.Second)
+		dueAt := wc.SLADueAt.Add(paused)
+		wc.SLADueAt = &dueAt
+		if wc.SLAAtRiskAt != nil {
+			atRiskAt := wc.SLAAtRiskAt.Add(paused)
+			wc.SLAAtRiskAt = &atRiskAt
+		}
+		if wc.SLABreachedAt == nil {
+			wc.SLAEscalatedAt = nil
+		}
+	}
+	wc.SLAPausedAt = nil
+}
+
+// StopSLA stops the clock for good once the claim is resolved, recording a
+// breach when the claim was resolved after its due-by
+func (wc *WarrantyClaim) StopSLA(at time.Time) {
+	if wc.SLADueAt == nil || wc.SLAStoppedAt != nil {
+		return
+	}
+	wc.ResumeSLA(at)
+	if wc.SLABreachedAt == nil && at.After(*wc.SLADueAt) {
+		breachedAt := *wc.SLADueAt
+		wc.SLABreachedAt = &breachedAt
+	}
+	wc.SLAStoppedAt = &at
+}
+
+// SLADueBy returns the due-by as of now. While the claim waits on the
+// customer the due-by keeps moving back. Returns nil for untracked claims.
+func (wc *WarrantyClaim) SLADueBy(now time.Time) *time.Time {
+	if wc.SLADueAt == nil {
+		return nil
+	}
+	dueAt := *wc.SLADueAt
+	if wc.SLAPausedAt != nil && now.After(*wc.SLAPausedAt) {
+		dueAt = dueAt.Add(now.Sub(*wc.SLAPausedAt))
+	}
+	return &dueAt
+}
+
+// SLAState returns where the claim stands against its SLA as of now.
+// Returns an empty state for untracked claims.
+func (wc *WarrantyClaim) SLAState(now time.Time) ClaimSLAState {
+	switch {
+	case wc.SLADueAt == nil:
+		return ""
+	case wc.SLAStoppedAt != nil:
+		if wc.SLABreachedAt != nil {
+			return ClaimSLAStateBreached
+		}
+		return ClaimSLAStateMet
+	case wc.SLABreachedAt != nil || !now.Before(*wc.SLADueAt) && wc.SLAPausedAt == nil:
+		return ClaimSLAStateBreached
+	case wc.SLAPausedAt != nil:
+		return ClaimSLAStatePaused
+	case wc.SLAAtRiskAt != nil && !now.Before(*wc.SLAAtRiskAt):
+		return ClaimSLAStateAtRisk
+	default:
+		return ClaimSLAStateOnTrack
+	}
+}
+
+// NeedsSLAEscalation reports whether the claim is at risk or breached and
+// nobody has been warned yet
+func (wc *WarrantyClaim) NeedsSLAEscalation(now time.Time) bool {
+	if wc.SLAEscalatedAt != nil || wc.SLAStoppedAt != nil {
+		return false
+	}
+	state := wc.SLAState(now)
+	return state == ClaimSLAStateAtRisk || state == ClaimSLAStateBreached
+}
+
+// MarkSLABreached records the breach of an open claim whose due-by has
+// passed. Returns false when there is nothing new to record.
+func (wc *WarrantyClaim) MarkSLABreached(now time.Time) bool {
+	if wc.SLADueAt == nil || wc.SLABreachedAt != nil || wc.SLAStoppedAt != nil || wc.SLAPausedAt != nil {
+		return false
+	}
+	if now.Before(*wc.SLADueAt) {
+		return false
+	}
+	breachedAt := *wc.SLADueAt
+	wc.SLABreachedAt = &breachedAt
+	return true
+}
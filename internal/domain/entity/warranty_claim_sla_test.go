@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestClaimSLABudget(t *testing.T) {
+	tests := []struct {
+		priority ClaimPriority
+		want     time.Duration
+	}{
+		{ClaimPriorityUrgent, 18 * time.Hour},
+		{ClaimPriorityHigh, 36 * time.Hour},
+		{ClaimPriorityNormal, 72 * time.Hour},
+		{ClaimPriorityLow, 108 * time.Hour},
+		{ClaimPriority("unknown"), 72 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := ClaimSLABudget(72, tt.priority); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.priority, got, tt.want)
+		}
+	}
+}
+
+func TestWarrantyClaimSLAClock(t *testing.T) {
+	claim := NewWarrantyClaim(uuid.New(), uuid.New(), uuid.New(), uuid.New())
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	claim.ClaimDate = start
+	claim.StartSLA(10, 0.8)
+
+	if !claim.SLADueAt.Equal(start.Add(10 * time.Hour)) {
+		t.Fatalf("due at %v, want %v", claim.SLADueAt, start.Add(10*time.Hour))
+	}
+	if got := claim.SLAState(start.Add(7 * time.Hour)); got != ClaimSLAStateOnTrack {
+		t.Errorf("after 7h: got %s, want on_track", got)
+	}
+	if got := claim.SLAState(start.Add(8 * time.Hour)); got != ClaimSLAStateAtRisk {
+		t.Errorf("after 8h: got %s, want at_risk", got)
+	}
+
+	// Two hours waiting on the customer push the due-by back
+	claim.PauseSLA(start.Add(2 * time.Hour))
+	if got := claim.SLAState(start.Add(11 * time.Hour)); got != ClaimSLAStatePaused {
+		t.Errorf("while paused: got %s, want paused", got)
+	}
+	if due := claim.SLADueBy(start.Add(3 * time.Hour)); !due.Equal(start.Add(11 * time.Hour)) {
+		t.Errorf("due-by while paused: got %v, want %v", due, start.Add(11*time.Hour))
+	}
+	claim.ResumeSLA(start.Add(4 * time.Hour))
+	if !claim.SLADueAt.Equal(start.Add(12*time.Hour)) || claim.SLAPausedSeconds != 7200 {
+		t.Fatalf("after resume: due %v, paused %ds", claim.SLADueAt, claim.SLAPausedSeconds)
+	}
+	if got := claim.SLAState(start.Add(9 * time.Hour)); got != ClaimSLAStateOnTrack {
+		t.Errorf("after resume at 9h: got %s, want on_track", got)
+	}
+
+	// Raising the priority keeps the paused time but halves the budget
+	claim.Priority = ClaimPriorityHigh
+	claim.RecalculateSLA(0.8)
+	if !claim.SLADueAt.Equal(start.Add(7 * time.Hour)) {
+		t.Errorf("high priority due at %v, want %v", claim.SLADueAt, start.Add(7*time.Hour))
+	}
+
+	if !claim.MarkSLABreached(start.Add(8 * time.Hour)) {
+		t.Fatal("expected breach to be recorded")
+	}
+	if claim.MarkSLABreached(start.Add(9 * time.Hour)) {
+		t.Error("breach recorded twice")
+	}
+
+	claim.StopSLA(start.Add(10 * time.Hour))
+	if got := claim.SLAState(start.Add(20 * time.Hour)); got != ClaimSLAStateBreached {
+		t.Errorf("resolved late: got %s, want breached", got)
+	}
+}
+
+func TestWarrantyClaimSLAStopsOnResolution(t *testing.T) {
+	claim := NewWarrantyClaim(uuid.New(), uuid.New(), uuid.New(), uuid.New())
+	claim.StartSLA(72, 0.8)
+	claim.PauseSLA(time.Now())
+
+	reviewer := uuid.New()
+	if err := claim.Reject(reviewer, "Out of warranty period"); err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+	if claim.SLAPausedAt != nil || claim.SLAStoppedAt == nil {
+		t.Fatalf("expected clock resumed and stopped, paused=%v stopped=%v", claim.SLAPausedAt, claim.SLAStoppedAt)
+	}
+	if got := claim.SLAState(time.Now().Add(100 * time.Hour)); got != ClaimSLAStateMet {
+		t.Errorf("got %s, want met", got)
+	}
+	if claim.NeedsSLAEscalation(time.Now().Add(100 * time.Hour)) {
+		t.Error("resolved claim should not escalate")
+	}
+}
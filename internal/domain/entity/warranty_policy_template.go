@@ -0,0 +1,245 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CoverageType represents what a warranty policy covers
+type CoverageType string
+
+const (
+	CoverageTypeManufacturerDefect CoverageType = "manufacturer_defect" // Defects in materials or workmanship
+	CoverageTypeFullCoverage       CoverageType = "full_coverage"       // Any failure in normal use
+	CoverageTypeLimitedWarranty    CoverageType = "limited_warranty"    // Listed components only
+	CoverageTypeExtendedWarranty   CoverageType = "extended_warranty"   // Purchased extension
+)
+
+// Valid validates the coverage type
+func (ct CoverageType) Valid() bool {
+	switch ct {
+	case CoverageTypeManufacturerDefect, CoverageTypeFullCoverage,
+		CoverageTypeLimitedWarranty, CoverageTypeExtendedWarranty:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of CoverageType
+func (ct CoverageType) String() string {
+	return string(ct)
+}
+
+// PolicyScope describes which products a warranty policy applies to
+type PolicyScope string
+
+const (
+	PolicyScopeProduct  PolicyScope = "product"  // A single product
+	PolicyScopeCategory PolicyScope = "category" // Every product in a category
+	PolicyScopeDefault  PolicyScope = "default"  // Every product in the storefront
+)
+
+// policyDateLayout is used to compare effective dates by calendar day
+const policyDateLayout = "2006-01-02"
+
+// WarrantyPolicyTemplate defines the warranty terms applied to a product, a
+// product category or, as the storefront default, to every product
+type WarrantyPolicyTemplate struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	Name         string    `json:"name" db:"name"`
+	Description  *string   `json:"description,omitempty" db:"description"`
+	StorefrontID uuid.UUID `json:"storefront_id" db:"storefront_id"`
+
+	// Applicability
+	ProductID         *uuid.UUID `json:"product_id,omitempty" db:"product_id"`
+	ProductCategoryID *uuid.UUID `json:"product_category_id,omitempty" db:"product_category_id"`
+	IsDefault         bool       `json:"is_default" db:"is_default"`
+
+	// Warranty terms
+	WarrantyPeriodMonths int          `json:"warranty_period_months" db:"warranty_period_months"`
+	CoverageType         CoverageType `json:"coverage_type" db:"coverage_type"`
+	TermsAndConditions   string       `json:"terms_and_conditions" db:"terms_and_conditions"`
+	Exclusions           *string      `json:"exclusions,omitempty" db:"exclusions"`
+	CoverageLimitations  *string      `json:"coverage_limitations,omitempty" db:"coverage_limitations"`
+
+	// Claim processing rules
+	RequiresPurchaseReceipt bool `json:"requires_purchase_receipt" db:"requires_purchase_receipt"`
+	MaxClaimsPerProduct     int  `json:"max_claims_per_product" db:"max_claims_per_product"`
+	ClaimProcessingSLAHours int  `json:"claim_processing_sla_hours" db:"claim_processing_sla_hours"`
+
+	// Status
+	IsActive       bool       `json:"is_active" db:"is_active"`
+	EffectiveFrom  time.Time  `json:"effective_from" db:"effective_from"`
+	EffectiveUntil *time.Time `json:"effective_until,omitempty" db:"effective_until"`
+
+	// Audit
+	CreatedBy uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// NewWarrantyPolicyTemplate creates a new warranty policy with the database
+// defaults, effective from today
+func NewWarrantyPolicyTemplate(storefrontID uuid.UUID, name string, warrantyPeriodMonths int, termsAndConditions string, createdBy uuid.UUID) *WarrantyPolicyTemplate {
+	now := time.Now()
+	return &WarrantyPolicyTemplate{
+		ID:                      uuid.New(),
+		Name:                    name,
+		StorefrontID:            storefrontID,
+		WarrantyPeriodMonths:    warrantyPeriodMonths,
+		CoverageType:            CoverageTypeManufacturerDefect,
+		TermsAndConditions:      termsAndConditions,
+		RequiresPurchaseReceipt: true,
+		MaxClaimsPerProduct:     3,
+		ClaimProcessingSLAHours: 72,
+		IsActive:                true,
+		EffectiveFrom:           time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		CreatedBy:               createdBy,
+		CreatedAt:               now,
+		UpdatedAt:               now,
+	}
+}
+
+// Validate performs validation of the warranty policy
+func (p *WarrantyPolicyTemplate) Validate() error {
+	if p.StorefrontID == uuid.Nil {
+		return fmt.Errorf("storefront_id is required")
+	}
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(p.Name) > 255 {
+		return fmt.Errorf("name must not exceed 255 characters")
+	}
+	if p.CreatedBy == uuid.Nil {
+		return fmt.Errorf("created_by is required")
+	}
+
+	// A policy applies to exactly one scope
+	scopes := 0
+	if p.ProductID != nil {
+		scopes++
+	}
+	if p.ProductCategoryID != nil {
+		scopes++
+	}
+	if p.IsDefault {
+		scopes++
+	}
+	if scopes != 1 {
+		return fmt.Errorf("policy must apply to exactly one of a product, a product category or the storefront default")
+	}
+
+	if p.WarrantyPeriodMonths <= 0 || p.WarrantyPeriodMonths > 120 {
+		return fmt.Errorf("warranty period must be between 1 and 120 months")
+	}
+	if !p.CoverageType.Valid() {
+		return fmt.Errorf("invalid coverage type: %s", p.CoverageType)
+	}
+	if strings.TrimSpace(p.TermsAndConditions) == "" {
+		return fmt.Errorf("terms and conditions are required")
+	}
+	if p.MaxClaimsPerProduct <= 0 {
+		return fmt.Errorf("max claims per product must be positive")
+	}
+	if p.ClaimProcessingSLAHours <= 0 {
+		return fmt.Errorf("claim processing SLA hours must be positive")
+	}
+	if p.EffectiveFrom.IsZero() {
+		return fmt.Errorf("effective_from is required")
+	}
+	if p.EffectiveUntil != nil && p.EffectiveUntil.Format(policyDateLayout) < p.EffectiveFrom.Format(policyDateLayout) {
+		return fmt.Errorf("effective_until must not be before effective_from")
+	}
+
+	return nil
+}
+
+// Scope returns which products the policy applies to
+func (p *WarrantyPolicyTemplate) Scope() PolicyScope {
+	switch {
+	case p.ProductID != nil:
+		return PolicyScopeProduct
+	case p.ProductCategoryID != nil:
+		return PolicyScopeCategory
+	default:
+		return PolicyScopeDefault
+	}
+}
+
+// IsEffectiveOn checks if the policy is active on the calendar day of t.
+// Effective dates are inclusive.
+func (p *WarrantyPolicyTemplate) IsEffectiveOn(t time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	day := t.Format(policyDateLayout)
+	if day < p.EffectiveFrom.Format(policyDateLayout) {
+		return false
+	}
+	return p.EffectiveUntil == nil || day <= p.EffectiveUntil.Format(policyDateLayout)
+}
+
+// AppliesTo checks if the policy covers a product in the given category
+func (p *WarrantyPolicyTemplate) AppliesTo(productID uuid.UUID, categoryID *uuid.UUID) bool {
+	switch p.Scope() {
+	case PolicyScopeProduct:
+		return *p.ProductID == productID
+	case PolicyScopeCategory:
+		return categoryID != nil && *p.ProductCategoryID == *categoryID
+	default:
+		return p.IsDefault
+	}
+}
+
+// specificity ranks scopes so that narrower policies win
+func (p *WarrantyPolicyTemplate) specificity() int {
+	switch p.Scope() {
+	case PolicyScopeProduct:
+		return 3
+	case PolicyScopeCategory:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// SelectWarrantyPolicy picks the most specific policy in effect on the given
+// day for a product: a product policy, then a category policy, then the
+// storefront default. Among policies of the same scope the one that took
+// effect last wins. Returns nil when no policy applies.
+func SelectWarrantyPolicy(policies []*WarrantyPolicyTemplate, productID uuid.UUID, categoryID *uuid.UUID, on time.Time) *WarrantyPolicyTemplate {
+	var candidates []*WarrantyPolicyTemplate
+	for _, policy := range policies {
+		if policy.IsEffectiveOn(on) && policy.AppliesTo(productID, categoryID) {
+			candidates = append(candidates, policy)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.specificity() != b.specificity() {
+			return a.specificity() > b.specificity()
+		}
+		if !a.EffectiveFrom.Equal(b.EffectiveFrom) {
+			return a.EffectiveFrom.After(b.EffectiveFrom)
+		}
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+
+	return candidates[0]
+}
+
+// CountsTowardClaimLimit checks if a claim uses up one of the claims allowed
+// by a warranty policy. Rejected and cancelled claims do not.
+func CountsTowardClaimLimit(claim *WarrantyClaim) bool {
+	return claim.Status != ClaimStatusRejected && claim.Status != ClaimStatusCancelled
+}
@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSelectWarrantyPolicy(t *testing.T) {
+	storefrontID := uuid.New()
+	productID := uuid.New()
+	categoryID := uuid.New()
+	createdBy := uuid.New()
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+
+	storefrontDefault := NewWarrantyPolicyTemplate(storefrontID, "Default", 12, "Terms", createdBy)
+	storefrontDefault.IsDefault = true
+	storefrontDefault.EffectiveFrom = day("2024-01-01")
+
+	category := NewWarrantyPolicyTemplate(storefrontID, "Category", 18, "Terms", createdBy)
+	category.ProductCategoryID = &categoryID
+	category.EffectiveFrom = day("2024-01-01")
+
+	product := NewWarrantyPolicyTemplate(storefrontID, "Product", 24, "Terms", createdBy)
+	product.ProductID = &productID
+	product.EffectiveFrom = day("2024-03-01")
+	until := day("2024-06-30")
+	product.EffectiveUntil = &until
+
+	policies := []*WarrantyPolicyTemplate{storefrontDefault, category, product}
+
+	tests := []struct {
+		name       string
+		productID  uuid.UUID
+		categoryID *uuid.UUID
+		on         time.Time
+		want       *WarrantyPolicyTemplate
+	}{
+		{"product policy wins", productID, &categoryID, day("2024-04-15"), product},
+		{"product policy ends inclusive", productID, &categoryID, day("2024-06-30"), product},
+		{"category after product policy ends", productID, &categoryID, day("2024-07-01"), category},
+		{"category before product policy starts", productID, &categoryID, day("2024-02-01"), category},
+		{"default for other products", uuid.New(), nil, day("2024-04-15"), storefrontDefault},
+		{"nothing before any policy", productID, &categoryID, day("2023-12-31"), nil},
+	}
+	for _, tt := range tests {
+		if got := SelectWarrantyPolicy(policies, tt.productID, tt.categoryID, tt.on); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, policyName(got), policyName(tt.want))
+		}
+	}
+
+	// A newer default replaces an older one, and inactive policies are ignored
+	newerDefault := NewWarrantyPolicyTemplate(storefrontID, "Newer default", 6, "Terms", createdBy)
+	newerDefault.IsDefault = true
+	newerDefault.EffectiveFrom = day("2024-05-01")
+	category.IsActive = false
+	policies = append(policies, newerDefault)
+	if got := SelectWarrantyPolicy(policies, productID, &categoryID, day("2024-08-01")); got != newerDefault {
+		t.Errorf("Expected the newer default policy, got %v", policyName(got))
+	}
+}
+
+func TestWarrantyPolicyTemplateValidate(t *testing.T) {
+	productID := uuid.New()
+	categoryID := uuid.New()
+
+	policy := NewWarrantyPolicyTemplate(uuid.New(), "Electronics", 12, "Terms", uuid.New())
+	if err := policy.Validate(); err == nil {
+		t.Error("Expected a policy without a scope to be rejected")
+	}
+
+	policy.ProductID = &productID
+	if err := policy.Validate(); err != nil {
+		t.Errorf("Expected a product policy to be valid, got %v", err)
+	}
+
+	policy.ProductCategoryID = &categoryID
+	if err := policy.Validate(); err == nil {
+		t.Error("Expected a policy with two scopes to be rejected")
+	}
+
+	policy.ProductCategoryID = nil
+	until := policy.EffectiveFrom.AddDate(0, 0, -1)
+	policy.EffectiveUntil = &until
+	if err := policy.Validate(); err == nil {
+		t.Error("Expected effective_until before effective_from to be rejected")
+	}
+
+	policy.EffectiveUntil = nil
+	policy.CoverageType = "lifetime"
+	if err := policy.Validate(); err == nil {
+		t.Error("Expected an unknown coverage type to be rejected")
+	}
+}
+
+func policyName(p *WarrantyPolicyTemplate) string {
+	if p == nil {
+		return "<nil>"
+	}
+	return p.Name
+}
@@ -2,8 +2,9 @@ package model
 
 // Booking represents a courier booking response
 type Booking struct {
-	CourierID   string
-	BookingCode string
-	Status      string
-	Reference   map[string]interface{}
+	CourierID      string
+	BookingCode    string
+	TrackingNumber string // AWB issued by the courier
+	Status         string
+	Reference      map[string]interface{}
 }
\ No newline at end of file
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+)
+
+// CartRepository defines the interface for shopping cart data operations
+type CartRepository interface {
+	// Create creates a new empty cart
+	Create(ctx context.Context, cart *entity.Cart) error
+
+	// GetByCustomerID retrieves a customer's cart on a storefront, including its items
+	GetByCustomerID(ctx context.Context, storefrontID, customerID uuid.UUID) (*entity.Cart, error)
+
+	// GetBySessionID retrieves an anonymous session's cart on a storefront, including its items
+	GetBySessionID(ctx context.Context, storefrontID uuid.UUID, sessionID string) (*entity.Cart, error)
+
+	// SaveItem inserts a cart item or updates its quantity and price
+	SaveItem(ctx context.Context, item *entity.CartItem) error
+
+	// DeleteItem removes an item from a cart
+	DeleteItem(ctx context.Context, cartID, itemID uuid.UUID) error
+
+	// ClearItems removes all items from a cart
+	ClearItems(ctx context.Context, cartID uuid.UUID) error
+
+	// Touch updates the last activity time of a cart
+	Touch(ctx context.Context, cartID uuid.UUID) error
+
+	// Delete deletes a cart and its items
+	Delete(ctx context.Context, cartID uuid.UUID) error
+}
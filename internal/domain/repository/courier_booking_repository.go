@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+)
+
+// CourierBookingRepository defines the interface for courier booking data operations
+type CourierBookingRepository interface {
+	// Create creates a courier booking
+	Create(ctx context.Context, booking *entity.CourierBooking) error
+
+	// GetActiveByOrderID retrieves the active booking of an order
+	GetActiveByOrderID(ctx context.Context, orderID uuid.UUID) (*entity.CourierBooking, error)
+
+	// ListActiveByStorefront lists the active bookings of a storefront's
+	// orders made in [from, to), oldest first
+	ListActiveByStorefront(ctx context.Context, storefrontID uuid.UUID, from, to time.Time) ([]*entity.CourierBooking, error)
+
+	// NextReceiptSequence advances a courier's receipt sequence and returns
+	// the new value, starting at 1
+	NextReceiptSequence(ctx context.Context, courierCode string) (int64, error)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+)
+
+// CourierWebhookEventRepository defines the interface for courier webhook event data operations
+type CourierWebhookEventRepository interface {
+	// Create stores a raw courier webhook delivery
+	Create(ctx context.Context, event *entity.CourierWebhookEvent) error
+
+	// GetByID retrieves a courier webhook event by its ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.CourierWebhookEvent, error)
+
+	// Update updates the processing outcome of an event
+	Update(ctx context.Context, event *entity.CourierWebhookEvent) error
+
+	// GetWithFilters retrieves events with filters and pagination, newest first
+	GetWithFilters(ctx context.Context, filters *CourierWebhookEventFilters) ([]*entity.CourierWebhookEvent, error)
+
+	// Count counts events with optional filters
+	Count(ctx context.Context, filters *CourierWebhookEventFilters) (int, error)
+
+	// ClaimDueForRetry picks up to limit failed events whose retry is due and
+	// pushes their next retry back by lease so that concurrent workers skip them
+	ClaimDueForRetry(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.CourierWebhookEvent, error)
+}
+
+// CourierWebhookEventFilters represents filters for courier webhook event queries
+type CourierWebhookEventFilters struct {
+	CourierCode    *string                           `json:"courier_code,omitempty"`
+	Status         *entity.CourierWebhookEventStatus `json:"status,omitempty"`
+	TrackingNumber *string                           `json:"tracking_number,omitempty"`
+	ReceivedFrom   *time.Time                        `json:"received_from,omitempty"`
+	ReceivedTo     *time.Time                        `json:"received_to,omitempty"`
+	Page           int                               `json:"page"`
+	PageSize       int                               `json:"page_size"`
+}
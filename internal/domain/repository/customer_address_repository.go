@@ -58,30 +58,25 @@ type CustomerAddressRepository interface {
 type CustomerSessionRepository interface {
 	// Session lifecycle
 	Create(ctx context.Context, session *CustomerSession) error
-	GetBySessionToken(ctx context.Context, sessionToken string) (*CustomerSession, error)
-	GetByRefreshToken(ctx context.Context, refreshToken string) (*CustomerSession, error)
-	GetByCustomerID(ctx context.Context, storefrontID, customerID uuid.UUID) ([]*CustomerSession, error)
-	Update(ctx context.Context, session *CustomerSession) error
-	RevokeSession(ctx context.Context, sessionID uuid.UUID) error
-	RevokeAllSessions(ctx context.Context, storefrontID, customerID uuid.UUID) error
+	GetByID(ctx context.Context, storefrontID, sessionID uuid.UUID) (*CustomerSession, error)
+	RevokeSession(ctx context.Context, storefrontID, sessionID uuid.UUID, reason string) error
+	RevokeAllSessions(ctx context.Context, storefrontID, customerID uuid.UUID, reason string) (int, error)
+	RevokeStorefrontSessions(ctx context.Context, storefrontID uuid.UUID, reason string) (int, error)
 
 	// Session management
-	RefreshSession(ctx context.Context, refreshToken string, newSessionToken, newRefreshToken string) error
-	UpdateLastUsed(ctx context.Context, sessionID uuid.UUID) error
-	ExtendSession(ctx context.Context, sessionID uuid.UUID, newExpiresAt time.Time) error
+	// RefreshSession swaps the token digests of a session only while it still
+	// holds refreshToken. Returns false when another refresh won the race.
+	RefreshSession(ctx context.Context, storefrontID, sessionID uuid.UUID, refreshToken, newSessionToken, newRefreshToken string, expiresAt time.Time) (bool, error)
+	UpdateLastUsed(ctx context.Context, storefrontID, sessionID uuid.UUID) error
+	// MarkTwoFactorVerified records a passed second factor and swaps in the
+	// digests of the tokens that carry it
+	MarkTwoFactorVerified(ctx context.Context, storefrontID, sessionID uuid.UUID, newSessionToken, newRefreshToken string) error
+	// SetTwoFactorRequired flags the customer's open sessions as needing a second factor
+	SetTwoFactorRequired(ctx context.Context, storefrontID, customerID uuid.UUID, required bool) error
 
 	// Security operations
+	// GetActiveSessions returns unrevoked, unexpired sessions, most recently used first
 	GetActiveSessions(ctx context.Context, storefrontID, customerID uuid.UUID) ([]*CustomerSession, error)
-	GetSessionsByIPAddress(ctx context.Context, ipAddress string, limit int) ([]*CustomerSession, error)
-	GetSuspiciousSessions(ctx context.Context, storefrontID uuid.UUID) ([]*CustomerSession, error)
-
-	// Cleanup operations
-	CleanupExpiredSessions(ctx context.Context) (int, error)
-	CleanupRevokedSessions(ctx context.Context, olderThanDays int) (int, error)
-
-	// Analytics
-	GetSessionStats(ctx context.Context, storefrontID uuid.UUID) (*SessionStats, error)
-	GetActiveSessionCount(ctx context.Context, storefrontID uuid.UUID) (int, error)
 }
 
 // Supporting types for Customer Address operations
@@ -122,9 +117,9 @@ type CustomerSession struct {
 	CustomerID   uuid.UUID `json:"customer_id" db:"customer_id"`
 	StorefrontID uuid.UUID `json:"storefront_id" db:"storefront_id"`
 
-	// Session tokens
-	SessionToken string `json:"session_token" db:"session_token"`
-	RefreshToken string `json:"refresh_token" db:"refresh_token"`
+	// SHA-256 digests of the current access and refresh tokens
+	SessionToken string `json:"-" db:"session_token"`
+	RefreshToken string `json:"-" db:"refresh_token"`
 
 	// Session metadata
 	UserAgent *string `json:"user_agent,omitempty" db:"user_agent"`
@@ -135,6 +130,31 @@ type CustomerSession struct {
 	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
 	LastUsedAt time.Time  `json:"last_used_at" db:"last_used_at"`
 	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+
+	RevokedReason *string `json:"revoked_reason,omitempty" db:"revoked_reason"`
+
+	// Second factor
+	TwoFactorRequired   bool       `json:"two_factor_required" db:"two_factor_required"`
+	TwoFactorVerifiedAt *time.Time `json:"two_factor_verified_at,omitempty" db:"two_factor_verified_at"`
+}
+
+// Customer session revocation reasons
+const (
+	SessionRevokedLogout       = "logout"
+	SessionRevokedByCustomer   = "revoked"
+	SessionRevokedByAdmin      = "admin_revoked"
+	SessionRevokedLimit        = "session_limit"
+	SessionRevokedRefreshReuse = "refresh_token_reuse"
+)
+
+// TwoFactorPending reports whether the session still owes a second factor
+func (s *CustomerSession) TwoFactorPending() bool {
+	return s.TwoFactorRequired && s.TwoFactorVerifiedAt == nil
+}
+
+// IsActive reports whether the session can still authenticate requests
+func (s *CustomerSession) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
 }
 
 type SessionStats struct {
@@ -28,8 +28,8 @@ type CustomerRepository interface {
 	GetCustomerSegments(ctx context.Context, storefrontID uuid.UUID) ([]*CustomerSegment, error)
 
 	// Authentication-specific operations
-	GetByEmailVerificationToken(ctx context.Context, token string) (*entity.Customer, error)
-	GetByPasswordResetToken(ctx context.Context, token string) (*entity.Customer, error)
+	GetByEmailVerificationToken(ctx context.Context, storefrontID uuid.UUID, token string) (*entity.Customer, error)
+	GetByPasswordResetToken(ctx context.Context, storefrontID uuid.UUID, token string) (*entity.Customer, error)
 	UpdateLastLogin(ctx context.Context, storefrontID, customerID uuid.UUID) error
 	UpdateRefreshToken(ctx context.Context, storefrontID, customerID uuid.UUID, token string, expiresAt *time.Time) error
 	ClearRefreshToken(ctx context.Context, storefrontID, customerID uuid.UUID) error
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+)
+
+// CustomerTwoFactorRepository defines the interface for customer TOTP enrollments
+type CustomerTwoFactorRepository interface {
+	// GetByCustomerID returns the customer's enrollment, or nil when there is none
+	GetByCustomerID(ctx context.Context, storefrontID, customerID uuid.UUID) (*entity.CustomerTwoFactor, error)
+	// Save creates the enrollment or replaces a pending one
+	Save(ctx context.Context, twoFactor *entity.CustomerTwoFactor) error
+	// Update stores the enrollment state, attempts and recovery codes
+	Update(ctx context.Context, twoFactor *entity.CustomerTwoFactor) error
+	// UpdateAttempts stores the failed attempts and lockout of a verification
+	UpdateAttempts(ctx context.Context, twoFactor *entity.CustomerTwoFactor) error
+	// UseStep records the time step of an accepted code. Returns false when a
+	// code of that step or a later one was already used.
+	UseStep(ctx context.Context, storefrontID, customerID uuid.UUID, step int64) (bool, error)
+	// UseRecoveryCode removes an unused recovery code by its digest. Returns
+	// false when the code is unknown or another request used it first.
+	UseRecoveryCode(ctx context.Context, storefrontID, customerID uuid.UUID, codeHash string) (bool, error)
+	Delete(ctx context.Context, storefrontID, customerID uuid.UUID) error
+}
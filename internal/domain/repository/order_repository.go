@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/shopspring/decimal"
+)
+
+// OrderRepository defines the interface for order data operations
+type OrderRepository interface {
+	// Create creates a new order together with its items and initial status history
+	Create(ctx context.Context, order *entity.Order) error
+
+	// GetByID retrieves an order by its ID, including its items
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Order, error)
+
+	// GetByOrderNumber retrieves an order by its order number, including its items
+	GetByOrderNumber(ctx context.Context, orderNumber string) (*entity.Order, error)
+
+	// GetByPaymentReference retrieves an order by its payment reference
+	GetByPaymentReference(ctx context.Context, paymentReference string) (*entity.Order, error)
+
+	// GetByTrackingNumber retrieves an order by its shipping tracking number
+	GetByTrackingNumber(ctx context.Context, trackingNumber string) (*entity.Order, error)
+
+	// LockByID retrieves an order like GetByID and locks it until the
+	// transaction carried by the context ends
+	LockByID(ctx context.Context, id uuid.UUID) (*entity.Order, error)
+
+	// GetItems retrieves the items of an order
+	GetItems(ctx context.Context, orderID uuid.UUID) ([]*entity.OrderItem, error)
+
+	// Update updates an existing order (items are not modified)
+	Update(ctx context.Context, order *entity.Order) error
+
+	// UpdateStatus persists a status change and records it in the order history.
+	// It fails when the order no longer holds the history's from status.
+	UpdateStatus(ctx context.Context, order *entity.Order, history *entity.OrderStatusHistory) error
+
+	// UpdatePayment updates the payment status, method and reference of an order
+	UpdatePayment(ctx context.Context, orderID uuid.UUID, status entity.PaymentStatus, method, reference *string) error
+
+	// UpdatePaymentIfStatus updates the payment like UpdatePayment, but only while
+	// the payment is still in the expected status. It reports whether the order was updated.
+	UpdatePaymentIfStatus(ctx context.Context, orderID uuid.UUID, expected, status entity.PaymentStatus, method, reference *string) (bool, error)
+
+	// RecordPayment stores the amount a gateway charge has paid towards an
+	// order and returns the order's total paid amount. Repeated callbacks for
+	// a charge are counted once. Requires a transaction holding the order lock.
+	RecordPayment(ctx context.Context, orderID uuid.UUID, paymentReference string, amount decimal.Decimal) (decimal.Decimal, error)
+
+	// Delete soft deletes an order
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// GetStatusHistory retrieves the status history of an order
+	GetStatusHistory(ctx context.Context, orderID uuid.UUID) ([]*entity.OrderStatusHistory, error)
+
+	// GetWithFilters retrieves orders with filters and pagination
+	GetWithFilters(ctx context.Context, filters *OrderFilters) ([]*entity.Order, error)
+
+	// Count counts orders with optional filters
+	Count(ctx context.Context, filters *OrderFilters) (int, error)
+
+	// GetOrderStatistics retrieves order statistics for analytics
+	GetOrderStatistics(ctx context.Context, filters *OrderFilters) (*OrderStatistics, error)
+
+	// GenerateOrderNumber generates a unique order number
+	GenerateOrderNumber(ctx context.Context) (string, error)
+}
+
+// OrderFilters represents filters for order queries
+type OrderFilters struct {
+	CreatedBy     *uuid.UUID            `json:"created_by,omitempty"`
+	StorefrontID  *uuid.UUID            `json:"storefront_id,omitempty"`
+	CustomerID    *uuid.UUID            `json:"customer_id,omitempty"`
+	Status        *entity.OrderStatus   `json:"status,omitempty"`
+	PaymentStatus *entity.PaymentStatus `json:"payment_status,omitempty"`
+	Channel       *entity.OrderChannel  `json:"channel,omitempty"`
+	OrderNumber   *string               `json:"order_number,omitempty"`
+	CustomerEmail *string               `json:"customer_email,omitempty"`
+	Search        *string               `json:"search,omitempty"`
+	Tags          []string              `json:"tags,omitempty"`
+	DateFrom      *time.Time            `json:"date_from,omitempty"`
+	DateTo        *time.Time            `json:"date_to,omitempty"`
+	MinTotal      *float64              `json:"min_total,omitempty"`
+	MaxTotal      *float64              `json:"max_total,omitempty"`
+	Page          int                   `json:"page"`
+	PageSize      int                   `json:"page_size"`
+	SortBy        string                `json:"sort_by"`
+	SortDirection string                `json:"sort_direction"`
+	IncludeItems  bool                  `json:"include_items"`
+}
+
+// OrderStatistics represents order statistics
+type OrderStatistics struct {
+	TotalOrders       int64            `json:"total_orders"`
+	PendingOrders     int64            `json:"pending_orders"`
+	ProcessingOrders  int64            `json:"processing_orders"`
+	ShippedOrders     int64            `json:"shipped_orders"`
+	DeliveredOrders   int64            `json:"delivered_orders"`
+	CancelledOrders   int64            `json:"cancelled_orders"`
+	TotalRevenue      float64          `json:"total_revenue"`
+	AverageOrderValue float64          `json:"average_order_value"`
+	OrdersByStatus    map[string]int64 `json:"orders_by_status"`
+	OrdersByChannel   map[string]int64 `json:"orders_by_channel"`
+}
@@ -40,6 +40,9 @@ type ProductFilter struct {
 	UpdatedAfter  *time.Time `json:"updated_after,omitempty"`
 	UpdatedBefore *time.Time `json:"updated_before,omitempty"`
 
+	// Ownership filters
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+
 	// Text search
 	SearchQuery string `json:"search_query,omitempty"` // Search in name, description, SKU
 
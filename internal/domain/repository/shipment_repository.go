@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+)
+
+// ShipmentRepository defines the interface for shipment tracking data operations
+type ShipmentRepository interface {
+	// Create creates a shipment, doing nothing if the courier and tracking number already exist
+	Create(ctx context.Context, shipment *entity.Shipment) error
+
+	// GetByTrackingNumber retrieves a shipment by courier and tracking number
+	GetByTrackingNumber(ctx context.Context, courierCode, trackingNumber string) (*entity.Shipment, error)
+
+	// GetByWarrantyClaimID retrieves the most recent shipment of a warranty claim
+	GetByWarrantyClaimID(ctx context.Context, claimID uuid.UUID) (*entity.Shipment, error)
+
+	// GetByOrderID retrieves the most recent shipment of an order
+	GetByOrderID(ctx context.Context, orderID uuid.UUID) (*entity.Shipment, error)
+
+	// Update updates the current state and links of a shipment
+	Update(ctx context.Context, shipment *entity.Shipment) error
+
+	// AddEvent stores a tracking event and reports false if it was already recorded
+	AddEvent(ctx context.Context, event *entity.ShipmentTrackingEvent) (bool, error)
+
+	// GetEvents retrieves the tracking history of a shipment, oldest first
+	GetEvents(ctx context.Context, shipmentID uuid.UUID) ([]*entity.ShipmentTrackingEvent, error)
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+)
+
+// SparePartRepository defines the interface for spare parts inventory data operations
+type SparePartRepository interface {
+	// Create creates a new spare part
+	Create(ctx context.Context, part *entity.SparePart) error
+
+	// GetByID retrieves a spare part by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.SparePart, error)
+
+	// GetByPartNumber retrieves a storefront's spare part by part number
+	GetByPartNumber(ctx context.Context, storefrontID uuid.UUID, partNumber string) (*entity.SparePart, error)
+
+	// List retrieves a page of spare parts with filters
+	List(ctx context.Context, filters *SparePartFilters) ([]*entity.SparePart, error)
+
+	// Count counts the spare parts matching the filters
+	Count(ctx context.Context, filters *SparePartFilters) (int, error)
+
+	// Update updates the catalogue details of a spare part; stock is changed
+	// only through AdjustStock and the reservation methods
+	Update(ctx context.Context, part *entity.SparePart) error
+
+	// Delete soft deletes a spare part
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// AdjustStock adds delta to the quantity on hand, refusing to go below the
+	// reserved quantity. Received stock with a unit cost updates the weighted
+	// average unit cost.
+	AdjustStock(ctx context.Context, id uuid.UUID, delta int, receivedUnitCost *decimal.Decimal) (*entity.SparePart, error)
+
+	// Reserve holds quantity for a repair and reports false if not enough is available
+	Reserve(ctx context.Context, id uuid.UUID, quantity int) (bool, error)
+
+	// Unreserve returns held quantity to the available stock
+	Unreserve(ctx context.Context, id uuid.UUID, quantity int) error
+
+	// Consume removes held quantity from the stock on hand
+	Consume(ctx context.Context, id uuid.UUID, quantity int) (*entity.SparePart, error)
+
+	// CreateReservation creates a reservation of a part for a repair ticket
+	CreateReservation(ctx context.Context, reservation *entity.SparePartReservation) error
+
+	// GetReservationsByTicketID retrieves the parts reserved for a repair ticket
+	GetReservationsByTicketID(ctx context.Context, ticketID uuid.UUID) ([]*entity.SparePartReservation, error)
+
+	// UpdateReservation updates the state of a reservation
+	UpdateReservation(ctx context.Context, reservation *entity.SparePartReservation) error
+}
+
+// SparePartFilters represents filters for spare part queries
+type SparePartFilters struct {
+	StorefrontID uuid.UUID
+	Search       *string
+	IsActive     *bool
+	NeedsReorder bool
+	Page         int
+	PageSize     int
+}
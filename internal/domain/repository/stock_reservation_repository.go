@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+)
+
+// StockReservationRepository defines the interface for stock reservation data operations
+type StockReservationRepository interface {
+	// Create creates a new stock reservation
+	Create(ctx context.Context, reservation *entity.StockReservation) error
+
+	// GetByOrderID retrieves all reservations of an order
+	GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entity.StockReservation, error)
+
+	// GetExpiredOrderIDs retrieves orders holding active reservations that expired before the given time
+	GetExpiredOrderIDs(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
+
+	// Update updates the status of a reservation
+	Update(ctx context.Context, reservation *entity.StockReservation) error
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// Storefront config keys read by the application
+const (
+	// StorefrontConfigCaptcha holds the storefront's CAPTCHA provider and secret
+	StorefrontConfigCaptcha = "captcha"
+	// StorefrontConfigRateLimits holds per-route rate limits overriding the platform defaults
+	StorefrontConfigRateLimits = "rate_limits"
+)
+
+// StorefrontConfigRepository reads the key-value settings in storefront_configs
+type StorefrontConfigRepository interface {
+	// GetValue returns the raw JSON value of a key, or nil when it is not set
+	GetValue(ctx context.Context, storefrontID uuid.UUID, key string) (json.RawMessage, error)
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+)
+
+// TechnicianRepository defines the interface for technician roster data operations
+type TechnicianRepository interface {
+	// Create creates a new technician with their skills
+	Create(ctx context.Context, technician *entity.Technician) error
+
+	// GetByID retrieves a technician with their skills by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Technician, error)
+
+	// GetByUserID retrieves the technician of a storefront who is the given user
+	GetByUserID(ctx context.Context, storefrontID, userID uuid.UUID) (*entity.Technician, error)
+
+	// Update updates a technician and replaces their skills
+	Update(ctx context.Context, technician *entity.Technician) error
+
+	// Delete soft deletes a technician
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// ListWorkloads retrieves the technicians matching the filters with the
+	// work assigned to them
+	ListWorkloads(ctx context.Context, filters *TechnicianFilters) ([]*entity.TechnicianWorkload, error)
+}
+
+// TechnicianFilters represents filters for listing technicians
+type TechnicianFilters struct {
+	StorefrontID uuid.UUID
+	IsActive     *bool
+
+	// SkillCategoryID keeps the technicians skilled in the category or one
+	// of its parent categories
+	SkillCategoryID *uuid.UUID
+}
@@ -46,6 +46,10 @@ type WarrantyBarcodeRepository interface {
 	// Activate activates a warranty barcode
 	Activate(ctx context.Context, barcodeNumber string, activatedBy uuid.UUID) error
 
+	// Register stores the activation of a distributed barcode by a customer.
+	// Returns false when the barcode is no longer distributed.
+	Register(ctx context.Context, barcode *entity.WarrantyBarcode) (bool, error)
+
 	// Deactivate deactivates a warranty barcode
 	Deactivate(ctx context.Context, barcodeNumber string, reason string) error
 
@@ -73,6 +77,7 @@ type WarrantyBarcodeFilters struct {
 	Status           *entity.BarcodeStatus `json:"status,omitempty"`
 	CreatedBy        *uuid.UUID            `json:"created_by,omitempty"`
 	ActivatedBy      *uuid.UUID            `json:"activated_by,omitempty"`
+	CustomerID       *uuid.UUID            `json:"customer_id,omitempty"`
 	CreatedAfter     *time.Time            `json:"created_after,omitempty"`
 	CreatedBefore    *time.Time            `json:"created_before,omitempty"`
 	ActivatedAfter   *time.Time            `json:"activated_after,omitempty"`
@@ -19,6 +19,9 @@ type WarrantyClaimRepository interface {
 	// GetByClaimNumber retrieves a warranty claim by its claim number
 	GetByClaimNumber(ctx context.Context, claimNumber string) (*entity.WarrantyClaim, error)
 
+	// GetByTrackingNumber retrieves the most recent warranty claim shipped with the given tracking number
+	GetByTrackingNumber(ctx context.Context, trackingNumber string) (*entity.WarrantyClaim, error)
+
 	// GetByBarcodeID retrieves warranty claims for a specific barcode
 	GetByBarcodeID(ctx context.Context, barcodeID uuid.UUID) ([]*entity.WarrantyClaim, error)
 
@@ -40,9 +43,26 @@ type WarrantyClaimRepository interface {
 	// GetOverdueClaims retrieves warranty claims that are overdue
 	GetOverdueClaims(ctx context.Context, storefrontID uuid.UUID, limit, offset int) ([]*entity.WarrantyClaim, error)
 
+	// GetSLAEscalationCandidates retrieves open claims of all storefronts whose
+	// SLA is at risk and not yet escalated, or breached and not yet recorded
+	GetSLAEscalationCandidates(ctx context.Context, now time.Time, limit int) ([]*entity.WarrantyClaim, error)
+
 	// Update updates an existing warranty claim
 	Update(ctx context.Context, claim *entity.WarrantyClaim) error
 
+	// UpdateSLA updates only the SLA tracking fields of a warranty claim
+	UpdateSLA(ctx context.Context, claim *entity.WarrantyClaim) error
+
+	// ClaimSLAEscalation records the escalation of an open, unpaused claim
+	// that is at risk as of at and was not escalated yet. Returns false when
+	// the claim no longer qualifies, e.g. because another worker escalated it.
+	ClaimSLAEscalation(ctx context.Context, claim *entity.WarrantyClaim, at time.Time) (bool, error)
+
+	// ClaimSLABreach records the breach of an open, unpaused claim whose
+	// due-by has passed as of at. Returns false when the breach is already
+	// recorded or the claim no longer qualifies.
+	ClaimSLABreach(ctx context.Context, claim *entity.WarrantyClaim, at time.Time) (bool, error)
+
 	// UpdateStatus updates the status of a warranty claim
 	UpdateStatus(ctx context.Context, id uuid.UUID, status entity.ClaimStatus, updatedBy uuid.UUID, notes string) error
 
@@ -70,6 +90,12 @@ type WarrantyClaimRepository interface {
 	// GetClaimAttachments retrieves all attachments for a claim
 	GetClaimAttachments(ctx context.Context, claimID uuid.UUID) ([]*entity.ClaimAttachment, error)
 
+	// GetClaimAttachment retrieves an attachment by its ID
+	GetClaimAttachment(ctx context.Context, attachmentID uuid.UUID) (*entity.ClaimAttachment, error)
+
+	// GetAttachmentsByScanStatus retrieves the oldest attachments with a virus scan status
+	GetAttachmentsByScanStatus(ctx context.Context, status entity.VirusScanStatus, limit int) ([]*entity.ClaimAttachment, error)
+
 	// AddClaimAttachment adds a new attachment to a claim
 	AddClaimAttachment(ctx context.Context, attachment *entity.ClaimAttachment) error
 
@@ -85,6 +111,19 @@ type WarrantyClaimRepository interface {
 	// GetRepairTickets retrieves repair tickets for a claim
 	GetRepairTickets(ctx context.Context, claimID uuid.UUID) ([]*entity.RepairTicket, error)
 
+	// GetRepairTicketsWithFilters retrieves a storefront's repair tickets with filters and pagination
+	GetRepairTicketsWithFilters(ctx context.Context, filters *RepairTicketFilters) ([]*entity.RepairTicket, error)
+
+	// CountRepairTickets counts a storefront's repair tickets with filters
+	CountRepairTickets(ctx context.Context, filters *RepairTicketFilters) (int, error)
+
+	// GenerateRepairTicketNumber generates a unique repair ticket number
+	GenerateRepairTicketNumber(ctx context.Context, storefrontID uuid.UUID) (string, error)
+
+	// GetRepairTicketStatistics retrieves repair turnaround, cost and quality
+	// statistics, overall and per technician
+	GetRepairTicketStatistics(ctx context.Context, storefrontID uuid.UUID, startDate, endDate time.Time) (*RepairTicketStatistics, error)
+
 	// CreateRepairTicket creates a new repair ticket for a claim
 	CreateRepairTicket(ctx context.Context, ticket *entity.RepairTicket) error
 
@@ -121,6 +160,7 @@ type WarrantyClaimFilters struct {
 	Search               *string                `json:"search,omitempty"`
 	Tags                 []string               `json:"tags,omitempty"`
 	IsOverdue            *bool                  `json:"is_overdue,omitempty"`
+	SLAState             *entity.ClaimSLAState  `json:"sla_state,omitempty"`
 	HasAttachments       *bool                  `json:"has_attachments,omitempty"`
 	Page                 int                    `json:"page"`
 	PageSize             int                    `json:"page_size"`
@@ -130,6 +170,47 @@ type WarrantyClaimFilters struct {
 	IncludeAttachments   bool                   `json:"include_attachments"`
 }
 
+// RepairTicketFilters represents filters for repair ticket queries
+type RepairTicketFilters struct {
+	StorefrontID  uuid.UUID              `json:"storefront_id"`
+	ClaimID       *uuid.UUID             `json:"claim_id,omitempty"`
+	TechnicianID  *uuid.UUID             `json:"technician_id,omitempty"`
+	Statuses      []entity.RepairStatus  `json:"statuses,omitempty"`
+	Priorities    []entity.ClaimPriority `json:"priorities,omitempty"`
+	Search        *string                `json:"search,omitempty"`
+	Page          int                    `json:"page"`
+	PageSize      int                    `json:"page_size"`
+	SortBy        string                 `json:"sort_by"`
+	SortDirection string                 `json:"sort_direction"`
+}
+
+// RepairTicketStatistics represents repair ticket statistics. Turnaround runs
+// from assignment to completion; costs cover completed repairs only.
+type RepairTicketStatistics struct {
+	TotalTickets           int64                         `json:"total_tickets"`
+	CompletedTickets       int64                         `json:"completed_tickets"`
+	TicketsByStatus        map[string]int64              `json:"tickets_by_status"`
+	AverageTurnaroundHours float64                       `json:"average_turnaround_hours"`
+	TotalLaborCost         float64                       `json:"total_labor_cost"`
+	TotalPartsCost         float64                       `json:"total_parts_cost"`
+	QualityCheckedTickets  int64                         `json:"quality_checked_tickets"`
+	QualityPassedTickets   int64                         `json:"quality_passed_tickets"`
+	Technicians            []*TechnicianRepairStatistics `json:"technicians"`
+}
+
+// TechnicianRepairStatistics represents the repair statistics of one technician
+type TechnicianRepairStatistics struct {
+	TechnicianID           uuid.UUID `json:"technician_id" db:"technician_id"`
+	TechnicianName         string    `json:"technician_name" db:"technician_name"`
+	AssignedTickets        int64     `json:"assigned_tickets" db:"assigned_tickets"`
+	CompletedTickets       int64     `json:"completed_tickets" db:"completed_tickets"`
+	AverageTurnaroundHours float64   `json:"average_turnaround_hours" db:"average_turnaround_hours"`
+	TotalLaborCost         float64   `json:"total_labor_cost" db:"total_labor_cost"`
+	TotalPartsCost         float64   `json:"total_parts_cost" db:"total_parts_cost"`
+	QualityCheckedTickets  int64     `json:"quality_checked_tickets" db:"quality_checked_tickets"`
+	QualityPassedTickets   int64     `json:"quality_passed_tickets" db:"quality_passed_tickets"`
+}
+
 // ClaimStatistics represents warranty claim statistics
 type ClaimStatistics struct {
 	TotalClaims             int64                `json:"total_claims"`
@@ -150,7 +231,9 @@ type ClaimStatistics struct {
 	TotalReplacementCost    float64              `json:"total_replacement_cost"`
 	ClaimRate               float64              `json:"claim_rate"` // Claims per 1000 warranties
 	FirstTimeFixRate        float64              `json:"first_time_fix_rate"`
-	SLAComplianceRate       float64              `json:"sla_compliance_rate"`
+	SLAComplianceRate       float64              `json:"sla_compliance_rate"` // Share of resolved claims resolved within SLA
+	SLAAtRiskClaims         int64                `json:"sla_at_risk_claims"`
+	SLABreachedClaims       int64                `json:"sla_breached_claims"`
 	MonthlyTrends           []*MonthlyClaimTrend `json:"monthly_trends"`
 }
 
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+)
+
+// WarrantyPolicyRepository defines the interface for warranty policy template data operations
+type WarrantyPolicyRepository interface {
+	// Create creates a new warranty policy
+	Create(ctx context.Context, policy *entity.WarrantyPolicyTemplate) error
+
+	// GetByID retrieves a warranty policy of a storefront by its ID
+	GetByID(ctx context.Context, storefrontID, id uuid.UUID) (*entity.WarrantyPolicyTemplate, error)
+
+	// Update updates an existing warranty policy
+	Update(ctx context.Context, policy *entity.WarrantyPolicyTemplate) error
+
+	// Delete deletes a warranty policy
+	Delete(ctx context.Context, storefrontID, id uuid.UUID) error
+
+	// List retrieves warranty policies with filters and pagination
+	List(ctx context.Context, filters *WarrantyPolicyFilters) ([]*entity.WarrantyPolicyTemplate, error)
+
+	// Count counts warranty policies with filters
+	Count(ctx context.Context, filters *WarrantyPolicyFilters) (int, error)
+
+	// GetApplicable retrieves the active policies in effect on the given day
+	// that apply to the product, its category or the whole storefront
+	GetApplicable(ctx context.Context, storefrontID, productID uuid.UUID, categoryID *uuid.UUID, on time.Time) ([]*entity.WarrantyPolicyTemplate, error)
+}
+
+// WarrantyPolicyFilters represents filters for warranty policy queries
+type WarrantyPolicyFilters struct {
+	StorefrontID      uuid.UUID            `json:"storefront_id"`
+	ProductID         *uuid.UUID           `json:"product_id,omitempty"`
+	ProductCategoryID *uuid.UUID           `json:"product_category_id,omitempty"`
+	IsDefault         *bool                `json:"is_default,omitempty"`
+	IsActive          *bool                `json:"is_active,omitempty"`
+	CoverageType      *entity.CoverageType `json:"coverage_type,omitempty"`
+	Page              int                  `json:"page"`
+	PageSize          int                  `json:"page_size"`
+}
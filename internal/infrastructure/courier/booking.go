@@ -0,0 +1,78 @@
+package courier
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+)
+
+// Contact is the sender or recipient of a parcel
+type Contact struct {
+	Name       string
+	Phone      string
+	Address    string
+	PostalCode string
+}
+
+// BookingRequest describes a parcel to hand over to a courier
+type BookingRequest struct {
+	// Reference is our identifier for the parcel, sent as the courier's order ID
+	Reference string
+	// ServiceType is the courier's service code, e.g. "REG"
+	ServiceType string
+	Origin      Area
+	Destination Area
+	Sender      Contact
+	Recipient   Contact
+	// Weight is the parcel weight in kilograms
+	Weight          decimal.Decimal
+	Quantity        int
+	ItemDescription string
+	ItemValue       decimal.Decimal
+	// Pickup is the slot the courier collects the parcel in. Without one the
+	// seller drops the parcel off at a counter.
+	Pickup *model.PickupTime
+}
+
+// Booker books parcels with a single courier
+type Booker interface {
+	// CourierID identifies the courier, e.g. "sicepat"
+	CourierID() string
+
+	// Book creates the shipment and returns the courier's booking with the
+	// AWB issued for it
+	Book(ctx context.Context, req *BookingRequest) (*model.Booking, error)
+
+	// PickupTimes lists the courier's pickup slots on the given day, in the
+	// day's location. Slots starting too soon are listed as unavailable.
+	PickupTimes(ctx context.Context, origin Area, day time.Time) ([]model.PickupTime, error)
+}
+
+// pickupWindow is a daily pickup slot in local hours
+type pickupWindow struct {
+	start, end int
+}
+
+// pickupLeadTime is how long before a slot starts it must be booked
+const pickupLeadTime = time.Hour
+
+// pickupSlots lays the courier's daily windows out on the day
+func pickupSlots(courierID string, windows []pickupWindow, day, now time.Time) []model.PickupTime {
+	slots := make([]model.PickupTime, 0, len(windows))
+	for _, w := range windows {
+		start := time.Date(day.Year(), day.Month(), day.Day(), w.start, 0, 0, 0, day.Location())
+		end := time.Date(day.Year(), day.Month(), day.Day(), w.end, 0, 0, 0, day.Location())
+		slots = append(slots, model.PickupTime{
+			PickupRequestTime: start,
+			Date:              start.Format("2006-01-02"),
+			StartTime:         start.Format("15:04"),
+			EndTime:           end.Format("15:04"),
+			IsAvailable:       start.After(now.Add(pickupLeadTime)),
+			CourierID:         courierID,
+		})
+	}
+	return slots
+}
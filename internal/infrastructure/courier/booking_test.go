@@ -0,0 +1,221 @@
+package courier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/courier/couriertest"
+)
+
+var wita = time.FixedZone("WITA", 8*60*60)
+
+func testBookingRequest(pickup *model.PickupTime) *BookingRequest {
+	return &BookingRequest{
+		Reference:       "SO-20261016-0001",
+		ServiceType:     "REG",
+		Origin:          denpasar,
+		Destination:     abiansemal,
+		Sender:          Contact{Name: "Toko Bali", Phone: "081234567890", Address: "Jl. Gatot Subroto 1", PostalCode: "80111"},
+		Recipient:       Contact{Name: "Made", Phone: "081298765432", Address: "Br. Dinas Abiansemal", PostalCode: "80352"},
+		Weight:          decimal.RequireFromString("1.5"),
+		Quantity:        2,
+		ItemDescription: "Kopi bubuk",
+		ItemValue:       decimal.NewFromInt(150000),
+		Pickup:          pickup,
+	}
+}
+
+func testPickup() *model.PickupTime {
+	start := time.Date(2026, 10, 17, 9, 0, 0, 0, wita)
+	return &model.PickupTime{PickupRequestTime: start, Date: "2026-10-17", StartTime: "09:00", EndTime: "12:00", IsAvailable: true}
+}
+
+func TestPickupSlots(t *testing.T) {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, wita)
+	now := time.Date(2026, 10, 16, 12, 30, 0, 0, wita)
+
+	slots := pickupSlots("sicepat", siCepatPickupWindows, day, now)
+	if len(slots) != 2 {
+		t.Fatalf("got %d slots, want 2", len(slots))
+	}
+	if slots[0].StartTime != "09:00" || slots[0].EndTime != "12:00" || slots[0].IsAvailable {
+		t.Errorf("morning slot = %+v, want unavailable 09:00-12:00", slots[0])
+	}
+	if slots[1].StartTime != "13:00" || slots[1].IsAvailable {
+		t.Errorf("afternoon slot within lead time should be unavailable: %+v", slots[1])
+	}
+	if !slots[1].PickupRequestTime.Equal(time.Date(2026, 10, 16, 13, 0, 0, 0, wita)) || slots[1].Date != "2026-10-16" {
+		t.Errorf("unexpected slot time %+v", slots[1])
+	}
+
+	tomorrow := pickupSlots("sicepat", siCepatPickupWindows, day.AddDate(0, 0, 1), now)
+	for _, s := range tomorrow {
+		if !s.IsAvailable {
+			t.Errorf("slot %+v tomorrow should be available", s)
+		}
+	}
+}
+
+func TestSiCepatBooker(t *testing.T) {
+	server := couriertest.NewServer()
+	defer server.Close()
+
+	var seq int64
+	booker := NewSiCepatBooker(SiCepatBookingConfig{
+		PickupURL:      server.URL(),
+		APIKey:         "sicepat-key",
+		ResiRangeStart: "000888000001",
+		ResiRangeEnd:   "000888000002",
+		NextReceipt: func(context.Context) (int64, error) {
+			seq++
+			return seq, nil
+		},
+		OriginMapping:      testMapping(map[Area]map[string]interface{}{denpasar: {":city_code": "DPS"}}),
+		DestinationMapping: testMapping(map[Area]map[string]interface{}{abiansemal: {":district_code": "DPS21101"}}),
+	})
+
+	booking, err := booker.Book(context.Background(), testBookingRequest(testPickup()))
+	if err != nil {
+		t.Fatalf("Book() error = %v", err)
+	}
+	if booking.TrackingNumber != "000888000001" || booking.BookingCode != "PU2610160000123" || booking.CourierID != "sicepat" {
+		t.Errorf("unexpected booking %+v", booking)
+	}
+
+	requests := server.Requests(couriertest.SiCepatPickupPath)
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	var sent siCepatPickupRequest
+	if err := json.Unmarshal(requests[0].Body, &sent); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if sent.AuthKey != "sicepat-key" || sent.PickupMethod != "PICKUP" || sent.PickupRequestDate != "2026-10-17 09:00" {
+		t.Errorf("unexpected pickup request %+v", sent)
+	}
+	pkg := sent.PackageList[0]
+	if pkg.ReceiptNumber != "000888000001" || pkg.OriginCode != "DPS" || pkg.DestinationCode != "DPS21101" || pkg.TotalWeight != 1.5 {
+		t.Errorf("unexpected package %+v", pkg)
+	}
+
+	// Drop-offs still take a receipt number; the third one is out of range
+	booking, err = booker.Book(context.Background(), testBookingRequest(nil))
+	if err != nil {
+		t.Fatalf("Book() drop-off error = %v", err)
+	}
+	if booking.TrackingNumber != "000888000002" || booking.Reference["pickup_method"] != "DROP" {
+		t.Errorf("unexpected drop-off booking %+v", booking)
+	}
+	if _, err := booker.Book(context.Background(), testBookingRequest(nil)); err == nil || !strings.Contains(err.Error(), "exhausted") {
+		t.Errorf("Book() error = %v, want range exhausted", err)
+	}
+}
+
+func TestSiCepatBookerRejected(t *testing.T) {
+	server := couriertest.NewServer()
+	defer server.Close()
+	server.Respond(couriertest.SiCepatPickupPath, http.StatusOK, `{"status":"400","error_message":"Invalid auth_key"}`)
+
+	booker := NewSiCepatBooker(SiCepatBookingConfig{
+		PickupURL:          server.URL(),
+		ResiRangeStart:     "1",
+		ResiRangeEnd:       "9",
+		NextReceipt:        func(context.Context) (int64, error) { return 1, nil },
+		OriginMapping:      testMapping(map[Area]map[string]interface{}{denpasar: {":city_code": "DPS"}}),
+		DestinationMapping: testMapping(map[Area]map[string]interface{}{abiansemal: {":district_code": "DPS21101"}}),
+	})
+
+	if _, err := booker.Book(context.Background(), testBookingRequest(nil)); err == nil || !strings.Contains(err.Error(), "Invalid auth_key") {
+		t.Errorf("Book() error = %v, want courier rejection", err)
+	}
+}
+
+func TestJNTBooker(t *testing.T) {
+	server := couriertest.NewServer()
+	defer server.Close()
+
+	booker := NewJNTBooker(JNTBookingConfig{
+		OrderAPIURL: server.URL() + couriertest.JNTOrderPath,
+		Username:    "KIRIMKU",
+		APIKeyName:  "KIRIMKU-KEY",
+		APIKey:      "jnt-secret",
+		Mapping: testMapping(map[Area]map[string]interface{}{
+			denpasar:   {":origin_destination_code": "DPS"},
+			abiansemal: {":origin_destination_code": "DPS", ":receiver_area": "DPS021"},
+		}),
+	})
+
+	booking, err := booker.Book(context.Background(), testBookingRequest(testPickup()))
+	if err != nil {
+		t.Fatalf("Book() error = %v", err)
+	}
+	if booking.TrackingNumber != "JO0027364815" || booking.CourierID != "jnt" {
+		t.Errorf("unexpected booking %+v", booking)
+	}
+
+	requests := server.Requests(couriertest.JNTOrderPath)
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	data := requests[0].Form.Get("data_param")
+	if got, want := requests[0].Form.Get("data_sign"), jntSign([]byte(data), "jnt-secret"); got != want {
+		t.Errorf("data_sign = %q, want %q", got, want)
+	}
+	var sent jntOrderRequest
+	if err := json.Unmarshal([]byte(data), &sent); err != nil {
+		t.Fatalf("data_param is not JSON: %v", err)
+	}
+	if sent.APIKey != "KIRIMKU-KEY" || sent.ServiceType != jntServicePickup || sent.ReceiverArea != "DPS021" ||
+		sent.SendStartTime != "2026-10-17 09:00:00" || sent.SendEndTime != "2026-10-17 12:00:00" {
+		t.Errorf("unexpected order request %+v", sent)
+	}
+}
+
+func TestSAPXBooker(t *testing.T) {
+	server := couriertest.NewServer()
+	defer server.Close()
+
+	booker := NewSAPXBooker(SAPXBookingConfig{
+		APIURL:        server.URL(),
+		APIKeyPickup:  "pickup-key",
+		APIKeyDropoff: "dropoff-key",
+		CustomerCode:  "KIRIMKU",
+		Mapping: testMapping(map[Area]map[string]interface{}{
+			denpasar:   {":district_code": "DPS10009"},
+			abiansemal: {":district_code": "DPS21101"},
+		}),
+	})
+
+	booking, err := booker.Book(context.Background(), testBookingRequest(nil))
+	if err != nil {
+		t.Fatalf("Book() error = %v", err)
+	}
+	if booking.TrackingNumber != "SAPX2610160042" || booking.Reference["pickup_method"] != "dropoff" {
+		t.Errorf("unexpected booking %+v", booking)
+	}
+	if _, err := booker.Book(context.Background(), testBookingRequest(testPickup())); err != nil {
+		t.Fatalf("Book() pickup error = %v", err)
+	}
+
+	requests := server.Requests(couriertest.SAPXPickupPath)
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	if requests[0].Header.Get("api_key") != "dropoff-key" || requests[1].Header.Get("api_key") != "pickup-key" {
+		t.Errorf("pickups and drop-offs should use their own keys")
+	}
+	var sent sapxPickupRequest
+	if err := json.Unmarshal(requests[1].Body, &sent); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if sent.PickupDistrictCode != "DPS10009" || sent.DestinationDistrictCode != "DPS21101" || sent.PickupDate != "2026-10-17 09:00:00" {
+		t.Errorf("unexpected shipment push %+v", sent)
+	}
+}
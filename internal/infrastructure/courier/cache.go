@@ -0,0 +1,50 @@
+package courier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+	"github.com/kirimku/smartseller-backend/pkg/cache"
+)
+
+// CachingQuoter remembers the rates of a quoter per route and chargeable
+// weight, so browsing checkout does not call the courier on every request.
+// Failures are not cached.
+type CachingQuoter struct {
+	quoter RateQuoter
+	cache  cache.Cache
+	ttl    time.Duration
+}
+
+// NewCachingQuoter wraps a quoter with a cache whose entries live for ttl
+func NewCachingQuoter(quoter RateQuoter, c cache.Cache, ttl time.Duration) *CachingQuoter {
+	return &CachingQuoter{quoter: quoter, cache: c, ttl: ttl}
+}
+
+// CourierID identifies the wrapped courier
+func (q *CachingQuoter) CourierID() string {
+	return q.quoter.CourierID()
+}
+
+// QuoteRates returns the cached rates for the route, quoting them on a miss
+func (q *CachingQuoter) QuoteRates(ctx context.Context, req *RateRequest) ([]model.ShippingRate, error) {
+	key := rateCacheKey(q.quoter.CourierID(), req)
+	if cached, ok := q.cache.Get(key); ok {
+		if rates, ok := cached.([]model.ShippingRate); ok {
+			return append([]model.ShippingRate(nil), rates...), nil
+		}
+	}
+
+	rates, err := q.quoter.QuoteRates(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	q.cache.Set(key, append([]model.ShippingRate(nil), rates...), q.ttl)
+	return rates, nil
+}
+
+func rateCacheKey(courierID string, req *RateRequest) string {
+	return fmt.Sprintf("shipping_rate:%s:%s:%s:%d", courierID, areaKey(req.Origin), areaKey(req.Destination), chargeableWeight(req.Weight))
+}
@@ -0,0 +1,119 @@
+// Package courier quotes shipping rates from courier tariff APIs. Each courier
+// resolves the origin and destination areas to its own area codes through the
+// mapping files loaded by the config package and normalises its tariffs into
+// model.ShippingRate.
+package courier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+)
+
+// defaultTimeout bounds a tariff request when no HTTP client is supplied
+const defaultTimeout = 30 * time.Second
+
+// maxResponseSize caps the tariff response read from a courier
+const maxResponseSize = 1 << 20
+
+// ErrAreaNotCovered is returned when a courier has no codes for the origin or
+// destination area, meaning it does not ship that route
+var ErrAreaNotCovered = errors.New("courier: area not covered")
+
+// Area identifies a district the way the mapping files key it
+type Area struct {
+	Province string
+	City     string
+	District string
+}
+
+// String formats the area for logs and errors
+func (a Area) String() string {
+	return fmt.Sprintf("%s, %s, %s", a.District, a.City, a.Province)
+}
+
+// RateRequest describes a parcel to quote
+type RateRequest struct {
+	Origin      Area
+	Destination Area
+	// Weight is the parcel weight in kilograms
+	Weight decimal.Decimal
+}
+
+// RateQuoter quotes the services of a single courier for a parcel
+type RateQuoter interface {
+	// CourierID identifies the courier, e.g. "jne"
+	CourierID() string
+
+	// QuoteRates returns the courier's services and prices for the parcel. It
+	// returns ErrAreaNotCovered when the courier does not ship the route.
+	QuoteRates(ctx context.Context, req *RateRequest) ([]model.ShippingRate, error)
+}
+
+// Option configures a rate quoter
+type Option func(*options)
+
+type options struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// WithHTTPClient sets the HTTP client used to call the courier
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+func buildOptions(opts []Option) options {
+	o := options{
+		client: &http.Client{Timeout: defaultTimeout},
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// chargeableWeight rounds the parcel weight up to whole kilograms, the unit
+// couriers bill in, with a minimum of one kilogram
+func chargeableWeight(weight decimal.Decimal) int64 {
+	kg := weight.Ceil().IntPart()
+	if kg < 1 {
+		return 1
+	}
+	return kg
+}
+
+// send performs a tariff request and returns the body of a 2xx response
+func send(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, truncate(string(body), 200))
+	}
+	return body, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,333 @@
+package courier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/courier/couriertest"
+	"github.com/kirimku/smartseller-backend/pkg/cache"
+)
+
+var (
+	denpasar   = Area{Province: "Bali", City: "Denpasar", District: "Denpasar Barat"}
+	abiansemal = Area{Province: "Bali", City: "Badung", District: "Abiansemal"}
+)
+
+// testMapping builds a mapping shaped like the files loaded by the config package
+func testMapping(codes map[Area]map[string]interface{}) *AreaMapping {
+	mapping := make(map[string]map[string]map[string]map[string]interface{})
+	for area, c := range codes {
+		if mapping[area.Province] == nil {
+			mapping[area.Province] = make(map[string]map[string]map[string]interface{})
+		}
+		if mapping[area.Province][area.City] == nil {
+			mapping[area.Province][area.City] = make(map[string]map[string]interface{})
+		}
+		mapping[area.Province][area.City][area.District] = c
+	}
+	return NewAreaMapping(mapping)
+}
+
+func testRequest(weight string) *RateRequest {
+	return &RateRequest{Origin: denpasar, Destination: abiansemal, Weight: decimal.RequireFromString(weight)}
+}
+
+func TestAreaMappingLookup(t *testing.T) {
+	mapping := testMapping(map[Area]map[string]interface{}{
+		abiansemal: {":district_code": "DPS21101", "city_code": "BL11", "is_cod": true},
+	})
+
+	codes, ok := mapping.Lookup(Area{Province: "bali", City: " BADUNG ", District: "abiansemal"})
+	if !ok {
+		t.Fatal("expected area to match case-insensitively")
+	}
+	if got := codes.Code("district_code"); got != "DPS21101" {
+		t.Errorf("district_code = %q, want DPS21101", got)
+	}
+	if got := codes.Code("city_code"); got != "BL11" {
+		t.Errorf("city_code = %q, want BL11", got)
+	}
+	if got := codes.Code("zone_code"); got != "" {
+		t.Errorf("zone_code = %q, want empty", got)
+	}
+
+	if _, err := resolveCode(mapping, denpasar, "district_code"); !errors.Is(err, ErrAreaNotCovered) {
+		t.Errorf("resolveCode() error = %v, want ErrAreaNotCovered", err)
+	}
+}
+
+func TestNormalizeETA(t *testing.T) {
+	tests := map[string]string{
+		"1 hari":     "1 day",
+		"1 - 2 hari": "1-2 days",
+		"2 - 3":      "2-3 days",
+		"3":          "3 days",
+		"":           "",
+	}
+	for in, want := range tests {
+		if got := normalizeETA(in); got != want {
+			t.Errorf("normalizeETA(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestJNEQuoter(t *testing.T) {
+	server := couriertest.NewServer()
+	defer server.Close()
+
+	quoter := NewJNEQuoter(JNEConfig{
+		BaseURL:  server.URL(),
+		Username: "seller",
+		APIKey:   "jne-key",
+		Mapping: testMapping(map[Area]map[string]interface{}{
+			denpasar:   {":district_code": "DPS10009", ":city_code": "DPS10000"},
+			abiansemal: {":district_code": "DPS21101", ":city_code": "DPS21100"},
+		}),
+	})
+
+	rates, err := quoter.QuoteRates(context.Background(), testRequest("1.2"))
+	if err != nil {
+		t.Fatalf("QuoteRates() error = %v", err)
+	}
+
+	requests := server.Requests(couriertest.JNETariffPath)
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	form := requests[0].Form
+	if form.Get("from") != "DPS10000" || form.Get("thru") != "DPS21101" || form.Get("weight") != "2" {
+		t.Errorf("unexpected tariff request %v", form)
+	}
+	if form.Get("username") != "seller" || form.Get("api_key") != "jne-key" {
+		t.Errorf("credentials not sent: %v", form)
+	}
+
+	if len(rates) != 3 {
+		t.Fatalf("got %d rates, want 3", len(rates))
+	}
+	reg := rates[1]
+	if reg.CourierID != "jne" || reg.ServiceID != "REG23" || reg.ServiceName != "REG" {
+		t.Errorf("unexpected rate %+v", reg)
+	}
+	if reg.ServiceType != ServiceTypeRegular || reg.Price != 11000 || reg.EstimatedETA != "1-2 days" {
+		t.Errorf("rate not normalised: %+v", reg)
+	}
+	if rates[2].ServiceType != ServiceTypeExpress || rates[2].EstimatedETA != "1 day" {
+		t.Errorf("YES not normalised: %+v", rates[2])
+	}
+}
+
+func TestJNEQuoterRouteNotServed(t *testing.T) {
+	server := couriertest.NewServer()
+	defer server.Close()
+	server.Respond(couriertest.JNETariffPath, http.StatusOK, `{"error":"Price Not Found.","status":false}`)
+
+	quoter := NewJNEQuoter(JNEConfig{
+		BaseURL: server.URL(),
+		Mapping: testMapping(map[Area]map[string]interface{}{
+			denpasar:   {":district_code": "DPS10009", ":city_code": "DPS10000"},
+			abiansemal: {":district_code": "DPS21101", ":city_code": "DPS21100"},
+		}),
+	})
+
+	if _, err := quoter.QuoteRates(context.Background(), testRequest("1")); !errors.Is(err, ErrAreaNotCovered) {
+		t.Errorf("QuoteRates() error = %v, want ErrAreaNotCovered", err)
+	}
+}
+
+func TestJNTQuoter(t *testing.T) {
+	server := couriertest.NewServer()
+	defer server.Close()
+
+	quoter := NewJNTQuoter(JNTConfig{
+		TariffAPIURL: server.URL() + couriertest.JNTTariffPath,
+		TariffAPIKey: "jnt-key",
+		CustName:     "KIRIMKU",
+		Mapping: testMapping(map[Area]map[string]interface{}{
+			denpasar:   {":send_site_code": "DENPASAR", ":dest_area_code": "DENPASAR BARAT"},
+			abiansemal: {":send_site_code": "BADUNG", ":dest_area_code": "ABIANSEMAL"},
+		}),
+	})
+
+	rates, err := quoter.QuoteRates(context.Background(), testRequest("0.3"))
+	if err != nil {
+		t.Fatalf("QuoteRates() error = %v", err)
+	}
+
+	requests := server.Requests(couriertest.JNTTariffPath)
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	data := requests[0].Form.Get("data")
+	if got, want := requests[0].Form.Get("sign"), jntSign([]byte(data), "jnt-key"); got != want {
+		t.Errorf("sign = %q, want %q", got, want)
+	}
+	var sent jntTariffRequest
+	if err := json.Unmarshal([]byte(data), &sent); err != nil {
+		t.Fatalf("data is not JSON: %v", err)
+	}
+	if sent.SendSiteCode != "DENPASAR" || sent.DestAreaCode != "ABIANSEMAL" || sent.Weight != "1" || sent.ProductType != "EZ" {
+		t.Errorf("unexpected tariff request %+v", sent)
+	}
+
+	if len(rates) != 1 || rates[0].CourierID != "jnt" || rates[0].ServiceID != "EZ" || rates[0].Price != 10000 {
+		t.Errorf("unexpected rates %+v", rates)
+	}
+}
+
+func TestSiCepatQuoter(t *testing.T) {
+	server := couriertest.NewServer()
+	defer server.Close()
+
+	quoter := NewSiCepatQuoter(SiCepatConfig{
+		BaseURL:            server.URL(),
+		APIKey:             "sicepat-key",
+		OriginMapping:      testMapping(map[Area]map[string]interface{}{denpasar: {":city_code": "DPS", ":district_code": "DPS10009"}}),
+		DestinationMapping: testMapping(map[Area]map[string]interface{}{abiansemal: {":district_code": "DPS21101"}}),
+	})
+
+	rates, err := quoter.QuoteRates(context.Background(), testRequest("2"))
+	if err != nil {
+		t.Fatalf("QuoteRates() error = %v", err)
+	}
+
+	requests := server.Requests(couriertest.SiCepatTariffPath)
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	req := requests[0]
+	if req.Header.Get("api-key") != "sicepat-key" {
+		t.Errorf("api-key header not sent")
+	}
+	if req.Form.Get("origin") != "DPS" || req.Form.Get("destination") != "DPS21101" || req.Form.Get("weight") != "2" {
+		t.Errorf("unexpected tariff request %v", req.Form)
+	}
+
+	want := []model.ShippingRate{
+		{CourierID: "sicepat", ServiceID: "BEST", ServiceType: ServiceTypeExpress, Price: 17000, EstimatedETA: "1 day"},
+		{CourierID: "sicepat", ServiceID: "REG", ServiceType: ServiceTypeRegular, Price: 10000, EstimatedETA: "1-2 days"},
+	}
+	if len(rates) != len(want) {
+		t.Fatalf("got %d rates, want %d", len(rates), len(want))
+	}
+	for i, w := range want {
+		r := rates[i]
+		if r.CourierID != w.CourierID || r.ServiceID != w.ServiceID || r.ServiceType != w.ServiceType || r.Price != w.Price || r.EstimatedETA != w.EstimatedETA {
+			t.Errorf("rate %d = %+v, want %+v", i, r, w)
+		}
+	}
+}
+
+func TestSAPXQuoter(t *testing.T) {
+	server := couriertest.NewServer()
+	defer server.Close()
+
+	quoter := NewSAPXQuoter(SAPXConfig{
+		APIURL:       server.URL(),
+		APIKey:       "sapx-key",
+		CustomerCode: "CUST01",
+		Mapping: testMapping(map[Area]map[string]interface{}{
+			denpasar:   {"district_code": "BL0501", "tlc_branch_code": "DPS"},
+			abiansemal: {"district_code": "BL0401", "tlc_branch_code": "TBN"},
+		}),
+	})
+
+	rates, err := quoter.QuoteRates(context.Background(), testRequest("1"))
+	if err != nil {
+		t.Fatalf("QuoteRates() error = %v", err)
+	}
+
+	requests := server.Requests(couriertest.SAPXTariffPath)
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	if requests[0].Header.Get("api_key") != "sapx-key" {
+		t.Errorf("api_key header not sent")
+	}
+	var sent sapxTariffRequest
+	if err := json.Unmarshal(requests[0].Body, &sent); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if sent.Origin != "DPS" || sent.Destination != "BL0401" || sent.Weight != 1 || sent.CustomerCode != "CUST01" {
+		t.Errorf("unexpected price list request %+v", sent)
+	}
+
+	if len(rates) != 2 || rates[1].ServiceType != ServiceTypeExpress || rates[1].Price != 21000 || rates[0].EstimatedETA != "2-3 days" {
+		t.Errorf("unexpected rates %+v", rates)
+	}
+}
+
+func TestQuoterAreaNotCovered(t *testing.T) {
+	server := couriertest.NewServer()
+	defer server.Close()
+
+	quoter := NewSiCepatQuoter(SiCepatConfig{
+		BaseURL:            server.URL(),
+		OriginMapping:      testMapping(map[Area]map[string]interface{}{denpasar: {":city_code": "DPS"}}),
+		DestinationMapping: testMapping(nil),
+	})
+
+	if _, err := quoter.QuoteRates(context.Background(), testRequest("1")); !errors.Is(err, ErrAreaNotCovered) {
+		t.Errorf("QuoteRates() error = %v, want ErrAreaNotCovered", err)
+	}
+	if n := len(server.Requests(couriertest.SiCepatTariffPath)); n != 0 {
+		t.Errorf("courier called %d times for an unmapped area", n)
+	}
+}
+
+func TestCachingQuoter(t *testing.T) {
+	server := couriertest.NewServer()
+	defer server.Close()
+
+	quoter := NewCachingQuoter(NewSiCepatQuoter(SiCepatConfig{
+		BaseURL:            server.URL(),
+		OriginMapping:      testMapping(map[Area]map[string]interface{}{denpasar: {":city_code": "DPS"}}),
+		DestinationMapping: testMapping(map[Area]map[string]interface{}{abiansemal: {":district_code": "DPS21101"}}),
+	}), cache.NewInMemoryCache(time.Minute, time.Minute), time.Minute)
+
+	ctx := context.Background()
+	calls := func() int { return len(server.Requests(couriertest.SiCepatTariffPath)) }
+
+	first, err := quoter.QuoteRates(ctx, testRequest("1.5"))
+	if err != nil {
+		t.Fatalf("QuoteRates() error = %v", err)
+	}
+	first[0].Price = 0
+
+	second, err := quoter.QuoteRates(ctx, testRequest("2"))
+	if err != nil {
+		t.Fatalf("QuoteRates() error = %v", err)
+	}
+	if calls() != 1 {
+		t.Errorf("same route and chargeable weight called the courier %d times, want 1", calls())
+	}
+	if second[0].Price != 17000 {
+		t.Errorf("cached rates were modified through a returned slice")
+	}
+
+	if _, err := quoter.QuoteRates(ctx, testRequest("3")); err != nil {
+		t.Fatalf("QuoteRates() error = %v", err)
+	}
+	if calls() != 2 {
+		t.Errorf("a heavier parcel was served from the cache")
+	}
+
+	server.Respond(couriertest.SiCepatTariffPath, http.StatusBadGateway, `{}`)
+	if _, err := quoter.QuoteRates(ctx, testRequest("4")); err == nil {
+		t.Fatal("expected the courier failure to be returned")
+	}
+	server.Respond(couriertest.SiCepatTariffPath, http.StatusOK, `{"sicepat":{"status":{"code":200},"results":[]}}`)
+	if _, err := quoter.QuoteRates(ctx, testRequest("4")); err != nil {
+		t.Fatalf("QuoteRates() error = %v", err)
+	}
+	if calls() != 4 {
+		t.Errorf("failure was cached: %d calls, want 4", calls())
+	}
+}
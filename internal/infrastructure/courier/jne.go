@@ -0,0 +1,140 @@
+package courier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+)
+
+// jneTariffPath is JNE's tariff endpoint
+const jneTariffPath = "/tracing/api/pricedev"
+
+// jneServiceTypes maps JNE service codes to normalised types
+var jneServiceTypes = map[string]string{
+	"CTC":    ServiceTypeRegular,
+	"REG":    ServiceTypeRegular,
+	"CTCYES": ServiceTypeExpress,
+	"YES":    ServiceTypeExpress,
+	"CTCOKE": ServiceTypeEconomy,
+	"OKE":    ServiceTypeEconomy,
+	"JTR":    ServiceTypeCargo,
+}
+
+// JNEConfig holds the JNE tariff API credentials and area mapping
+type JNEConfig struct {
+	BaseURL  string
+	Username string
+	APIKey   string
+	// Mapping resolves areas to JNE city (origin) and district (destination) codes
+	Mapping *AreaMapping
+}
+
+// JNEQuoter quotes JNE services
+type JNEQuoter struct {
+	cfg    JNEConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewJNEQuoter creates a JNE rate quoter
+func NewJNEQuoter(cfg JNEConfig, opts ...Option) *JNEQuoter {
+	o := buildOptions(opts)
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &JNEQuoter{cfg: cfg, client: o.client, now: o.now}
+}
+
+// CourierID identifies the courier
+func (q *JNEQuoter) CourierID() string {
+	return "jne"
+}
+
+// jneTariffResponse is the body of a tariff response. Unserved routes come
+// back with status false and an error message instead of prices.
+type jneTariffResponse struct {
+	Price []struct {
+		ServiceDisplay string `json:"service_display"`
+		ServiceCode    string `json:"service_code"`
+		GoodsType      string `json:"goods_type"`
+		Price          string `json:"price"`
+		EtdFrom        string `json:"etd_from"`
+		EtdThru        string `json:"etd_thru"`
+		Times          string `json:"times"`
+	} `json:"price"`
+	Error string `json:"error"`
+}
+
+// QuoteRates quotes JNE services from the origin city to the destination district
+func (q *JNEQuoter) QuoteRates(ctx context.Context, req *RateRequest) ([]model.ShippingRate, error) {
+	from, err := resolveCode(q.cfg.Mapping, req.Origin, "city_code")
+	if err != nil {
+		return nil, err
+	}
+	thru, err := resolveCode(q.cfg.Mapping, req.Destination, "district_code")
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"username": {q.cfg.Username},
+		"api_key":  {q.cfg.APIKey},
+		"from":     {from},
+		"thru":     {thru},
+		"weight":   {strconv.FormatInt(chargeableWeight(req.Weight), 10)},
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, q.cfg.BaseURL+jneTariffPath, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("jne: failed to build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpReq.Header.Set("Accept", "application/json")
+
+	body, err := send(q.client, httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("jne: %w", err)
+	}
+
+	var resp jneTariffResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("jne: failed to decode response: %w", err)
+	}
+	if len(resp.Price) == 0 {
+		if resp.Error != "" {
+			return nil, fmt.Errorf("%w: jne: %s", ErrAreaNotCovered, resp.Error)
+		}
+		return nil, nil
+	}
+
+	now := q.now()
+	rates := make([]model.ShippingRate, 0, len(resp.Price))
+	for _, p := range resp.Price {
+		price, err := strconv.ParseFloat(strings.TrimSpace(p.Price), 64)
+		if err != nil {
+			return nil, fmt.Errorf("jne: invalid price %q for %s", p.Price, p.ServiceCode)
+		}
+
+		var eta string
+		if p.Times == "" || strings.EqualFold(p.Times, "D") {
+			eta = normalizeETA(strings.TrimSpace(p.EtdFrom + " " + p.EtdThru))
+		}
+
+		rates = append(rates, model.ShippingRate{
+			CourierID:    q.CourierID(),
+			CourierName:  "JNE",
+			ServiceID:    p.ServiceCode,
+			ServiceName:  p.ServiceDisplay,
+			ServiceType:  serviceType(jneServiceTypes, p.ServiceDisplay),
+			Description:  p.GoodsType,
+			Price:        price,
+			EstimatedETA: eta,
+			CreatedAt:    now,
+		})
+	}
+	return rates, nil
+}
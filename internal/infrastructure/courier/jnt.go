@@ -0,0 +1,149 @@
+package courier
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+)
+
+// jntProductType is the J&T EZ (regular) product, the only one its tariff
+// API prices
+const jntProductType = "EZ"
+
+// JNTConfig holds the J&T tariff API credentials and area mapping
+type JNTConfig struct {
+	TariffAPIURL string
+	TariffAPIKey string
+	CustName     string
+	// Mapping resolves areas to J&T sending sites (origin) and destination areas
+	Mapping *AreaMapping
+}
+
+// JNTQuoter quotes J&T Express services
+type JNTQuoter struct {
+	cfg    JNTConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewJNTQuoter creates a J&T rate quoter
+func NewJNTQuoter(cfg JNTConfig, opts ...Option) *JNTQuoter {
+	o := buildOptions(opts)
+	return &JNTQuoter{cfg: cfg, client: o.client, now: o.now}
+}
+
+// CourierID identifies the courier
+func (q *JNTQuoter) CourierID() string {
+	return "jnt"
+}
+
+// jntTariffRequest is the data parameter of a tariff request
+type jntTariffRequest struct {
+	Weight       string `json:"weight"`
+	SendSiteCode string `json:"sendSiteCode"`
+	DestAreaCode string `json:"destAreaCode"`
+	CusName      string `json:"cusName"`
+	ProductType  string `json:"productType"`
+}
+
+// jntTariffResponse is the body of a tariff response. Content holds the
+// prices as a JSON-encoded string.
+type jntTariffResponse struct {
+	IsSuccess string `json:"is_success"`
+	Message   string `json:"message"`
+	Content   string `json:"content"`
+}
+
+type jntTariff struct {
+	Name string `json:"name"`
+	Cost string `json:"cost"`
+}
+
+// QuoteRates quotes J&T services from the origin sending site to the
+// destination area
+func (q *JNTQuoter) QuoteRates(ctx context.Context, req *RateRequest) ([]model.ShippingRate, error) {
+	sendSite, err := resolveCode(q.cfg.Mapping, req.Origin, "send_site_code")
+	if err != nil {
+		return nil, err
+	}
+	destArea, err := resolveCode(q.cfg.Mapping, req.Destination, "dest_area_code")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(jntTariffRequest{
+		Weight:       strconv.FormatInt(chargeableWeight(req.Weight), 10),
+		SendSiteCode: sendSite,
+		DestAreaCode: destArea,
+		CusName:      q.cfg.CustName,
+		ProductType:  jntProductType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("jnt: failed to encode request: %w", err)
+	}
+
+	form := url.Values{
+		"data": {string(data)},
+		"sign": {jntSign(data, q.cfg.TariffAPIKey)},
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, q.cfg.TariffAPIURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("jnt: failed to build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body, err := send(q.client, httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("jnt: %w", err)
+	}
+
+	var resp jntTariffResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("jnt: failed to decode response: %w", err)
+	}
+	if resp.IsSuccess != "true" {
+		return nil, fmt.Errorf("jnt: tariff request failed: %s", resp.Message)
+	}
+
+	var tariffs []jntTariff
+	if err := json.Unmarshal([]byte(resp.Content), &tariffs); err != nil {
+		return nil, fmt.Errorf("jnt: failed to decode tariffs: %w", err)
+	}
+
+	now := q.now()
+	rates := make([]model.ShippingRate, 0, len(tariffs))
+	for _, t := range tariffs {
+		price, err := strconv.ParseFloat(strings.TrimSpace(t.Cost), 64)
+		if err != nil {
+			return nil, fmt.Errorf("jnt: invalid cost %q for %s", t.Cost, t.Name)
+		}
+		rates = append(rates, model.ShippingRate{
+			CourierID:   q.CourierID(),
+			CourierName: "J&T Express",
+			ServiceID:   t.Name,
+			ServiceName: t.Name,
+			ServiceType: ServiceTypeRegular,
+			Description: "J&T Express regular service",
+			Price:       price,
+			CreatedAt:   now,
+		})
+	}
+	return rates, nil
+}
+
+// jntSign signs a tariff request: base64 of the hex MD5 of the data followed
+// by the API key
+func jntSign(data []byte, key string) string {
+	sum := md5.Sum(append(append([]byte{}, data...), key...))
+	return base64.StdEncoding.EncodeToString([]byte(hex.EncodeToString(sum[:])))
+}
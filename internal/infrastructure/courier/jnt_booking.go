@@ -0,0 +1,188 @@
+package courier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+)
+
+// J&T order service types
+const (
+	jntServicePickup  = "1"
+	jntServiceDropOff = "6"
+)
+
+// jntTimeLayout is the timestamp format of J&T order requests
+const jntTimeLayout = "2006-01-02 15:04:05"
+
+// jntPickupWindows are the daily slots J&T collects parcels in
+var jntPickupWindows = []pickupWindow{{9, 12}, {12, 15}, {15, 18}}
+
+// JNTBookingConfig holds the J&T order API credentials and area mapping
+type JNTBookingConfig struct {
+	OrderAPIURL string
+	Username    string
+	// APIKeyName is the key identifier sent with each order
+	APIKeyName string
+	// APIKey signs the order requests
+	APIKey string
+	// Mapping resolves areas to J&T origin/destination codes and receiver areas
+	Mapping *AreaMapping
+}
+
+// JNTBooker creates J&T Express orders, which issue an AWB straight away
+type JNTBooker struct {
+	cfg    JNTBookingConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewJNTBooker creates a J&T booker
+func NewJNTBooker(cfg JNTBookingConfig, opts ...Option) *JNTBooker {
+	o := buildOptions(opts)
+	return &JNTBooker{cfg: cfg, client: o.client, now: o.now}
+}
+
+// CourierID identifies the courier
+func (b *JNTBooker) CourierID() string {
+	return "jnt"
+}
+
+// jntOrderRequest is the data_param of an order request
+type jntOrderRequest struct {
+	Username        string  `json:"username"`
+	APIKey          string  `json:"api_key"`
+	OrderID         string  `json:"orderid"`
+	ShipperName     string  `json:"shipper_name"`
+	ShipperContact  string  `json:"shipper_contact"`
+	ShipperPhone    string  `json:"shipper_phone"`
+	ShipperAddr     string  `json:"shipper_addr"`
+	OriginCode      string  `json:"origin_code"`
+	ReceiverName    string  `json:"receiver_name"`
+	ReceiverPhone   string  `json:"receiver_phone"`
+	ReceiverAddr    string  `json:"receiver_addr"`
+	ReceiverZip     string  `json:"receiver_zip"`
+	DestinationCode string  `json:"destination_code"`
+	ReceiverArea    string  `json:"receiver_area"`
+	Qty             int     `json:"qty"`
+	Weight          float64 `json:"weight"`
+	GoodsDesc       string  `json:"goodsdesc"`
+	GoodsValue      float64 `json:"goodsvalue"`
+	ServiceType     string  `json:"servicetype"`
+	ExpressType     string  `json:"expresstype"`
+	OrderDate       string  `json:"orderdate"`
+	SendStartTime   string  `json:"sendstarttime,omitempty"`
+	SendEndTime     string  `json:"sendendtime,omitempty"`
+}
+
+// jntOrderResponse is the body of an order response
+type jntOrderResponse struct {
+	Success bool   `json:"success"`
+	Desc    string `json:"desc"`
+	Detail  []struct {
+		OrderID string `json:"orderid"`
+		Status  string `json:"status"`
+		AwbNo   string `json:"awb_no"`
+		Desc    string `json:"desc"`
+	} `json:"detail"`
+}
+
+// Book creates a J&T order for pickup in the requested slot, or for drop-off
+func (b *JNTBooker) Book(ctx context.Context, req *BookingRequest) (*model.Booking, error) {
+	origin, err := resolveCode(b.cfg.Mapping, req.Origin, "origin_destination_code")
+	if err != nil {
+		return nil, err
+	}
+	destination, err := resolveCode(b.cfg.Mapping, req.Destination, "origin_destination_code")
+	if err != nil {
+		return nil, err
+	}
+	receiverArea, err := resolveCode(b.cfg.Mapping, req.Destination, "receiver_area")
+	if err != nil {
+		return nil, err
+	}
+
+	order := jntOrderRequest{
+		Username:        b.cfg.Username,
+		APIKey:          b.cfg.APIKeyName,
+		OrderID:         req.Reference,
+		ShipperName:     req.Sender.Name,
+		ShipperContact:  req.Sender.Name,
+		ShipperPhone:    req.Sender.Phone,
+		ShipperAddr:     req.Sender.Address,
+		OriginCode:      origin,
+		ReceiverName:    req.Recipient.Name,
+		ReceiverPhone:   req.Recipient.Phone,
+		ReceiverAddr:    req.Recipient.Address,
+		ReceiverZip:     req.Recipient.PostalCode,
+		DestinationCode: destination,
+		ReceiverArea:    receiverArea,
+		Qty:             req.Quantity,
+		Weight:          req.Weight.InexactFloat64(),
+		GoodsDesc:       req.ItemDescription,
+		GoodsValue:      req.ItemValue.InexactFloat64(),
+		ServiceType:     jntServiceDropOff,
+		ExpressType:     "1",
+		OrderDate:       b.now().Format(jntTimeLayout),
+	}
+	if req.Pickup != nil {
+		start := req.Pickup.PickupRequestTime
+		order.ServiceType = jntServicePickup
+		order.SendStartTime = start.Format(jntTimeLayout)
+		order.SendEndTime = start.Format("2006-01-02") + " " + req.Pickup.EndTime + ":00"
+	}
+
+	data, err := json.Marshal(order)
+	if err != nil {
+		return nil, fmt.Errorf("jnt: failed to encode request: %w", err)
+	}
+	form := url.Values{
+		"data_param": {string(data)},
+		"data_sign":  {jntSign(data, b.cfg.APIKey)},
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, b.cfg.OrderAPIURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("jnt: failed to build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body, err := send(b.client, httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("jnt: %w", err)
+	}
+
+	var resp jntOrderResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("jnt: failed to decode response: %w", err)
+	}
+	if !resp.Success || len(resp.Detail) == 0 || resp.Detail[0].AwbNo == "" {
+		reason := resp.Desc
+		if len(resp.Detail) > 0 && resp.Detail[0].Desc != "" {
+			reason = resp.Detail[0].Desc
+		}
+		return nil, fmt.Errorf("jnt: order request failed: %s", reason)
+	}
+
+	detail := resp.Detail[0]
+	return &model.Booking{
+		CourierID:      b.CourierID(),
+		BookingCode:    detail.OrderID,
+		TrackingNumber: detail.AwbNo,
+		Status:         "booked",
+		Reference: map[string]interface{}{
+			"orderid":     detail.OrderID,
+			"servicetype": order.ServiceType,
+		},
+	}, nil
+}
+
+// PickupTimes lists J&T's pickup slots on the day
+func (b *JNTBooker) PickupTimes(_ context.Context, _ Area, day time.Time) ([]model.PickupTime, error) {
+	return pickupSlots(b.CourierID(), jntPickupWindows, day, b.now()), nil
+}
@@ -0,0 +1,76 @@
+package courier
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AreaMapping resolves areas to a courier's area codes. It wraps the
+// Province -> City -> District -> Codes maps loaded from the mapping files;
+// names match case-insensitively.
+type AreaMapping struct {
+	areas map[string]AreaCodes
+}
+
+// AreaCodes are the codes a mapping file lists for a district. Keys are
+// written with or without the leading colon the Ruby-era files use.
+type AreaCodes map[string]interface{}
+
+// NewAreaMapping indexes a Province -> City -> District -> Codes mapping
+func NewAreaMapping(mapping map[string]map[string]map[string]map[string]interface{}) *AreaMapping {
+	areas := make(map[string]AreaCodes)
+	for province, cities := range mapping {
+		for city, districts := range cities {
+			for district, codes := range districts {
+				if codes == nil {
+					continue
+				}
+				areas[areaKey(Area{Province: province, City: city, District: district})] = codes
+			}
+		}
+	}
+	return &AreaMapping{areas: areas}
+}
+
+// Lookup returns the codes of an area
+func (m *AreaMapping) Lookup(area Area) (AreaCodes, bool) {
+	if m == nil {
+		return nil, false
+	}
+	codes, ok := m.areas[areaKey(area)]
+	return codes, ok
+}
+
+// Code returns a code of the area, or "" when the mapping has none
+func (c AreaCodes) Code(name string) string {
+	value, ok := c[":"+name]
+	if !ok {
+		value, ok = c[name]
+	}
+	if !ok || value == nil {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprint(value))
+}
+
+func areaKey(area Area) string {
+	return normalizeName(area.Province) + "|" + normalizeName(area.City) + "|" + normalizeName(area.District)
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// resolveCode looks up a code of an area, reporting ErrAreaNotCovered when
+// the mapping lacks the area or the code
+func resolveCode(mapping *AreaMapping, area Area, name string) (string, error) {
+	codes, ok := mapping.Lookup(area)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrAreaNotCovered, area)
+	}
+	code := codes.Code(name)
+	if code == "" {
+		return "", fmt.Errorf("%w: %s has no %s", ErrAreaNotCovered, area, name)
+	}
+	return code, nil
+}
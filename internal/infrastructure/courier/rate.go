@@ -0,0 +1,68 @@
+package courier
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Normalised service types, so options from different couriers compare
+const (
+	ServiceTypeSameDay = "same_day"
+	ServiceTypeExpress = "express"
+	ServiceTypeRegular = "regular"
+	ServiceTypeEconomy = "economy"
+	ServiceTypeCargo   = "cargo"
+	ServiceTypeOther   = "other"
+)
+
+// serviceType looks up the normalised type of a courier service code
+func serviceType(types map[string]string, code string) string {
+	if t, ok := types[strings.ToUpper(strings.TrimSpace(code))]; ok {
+		return t
+	}
+	return ServiceTypeOther
+}
+
+var etaDays = regexp.MustCompile(`\d+`)
+
+// parseETA reads delivery estimates such as "2", "1-2" or "1 - 2 hari" into
+// a day range
+func parseETA(s string) (minDays, maxDays int, ok bool) {
+	matches := etaDays.FindAllString(s, 2)
+	if len(matches) == 0 {
+		return 0, 0, false
+	}
+	minDays, _ = strconv.Atoi(matches[0])
+	maxDays = minDays
+	if len(matches) == 2 {
+		maxDays, _ = strconv.Atoi(matches[1])
+	}
+	if maxDays < minDays {
+		minDays, maxDays = maxDays, minDays
+	}
+	return minDays, maxDays, true
+}
+
+// formatETA renders a day range the same way for every courier
+func formatETA(minDays, maxDays int) string {
+	switch {
+	case minDays == maxDays && minDays == 1:
+		return "1 day"
+	case minDays == maxDays:
+		return fmt.Sprintf("%d days", minDays)
+	default:
+		return fmt.Sprintf("%d-%d days", minDays, maxDays)
+	}
+}
+
+// normalizeETA formats a courier's free-form estimate, or returns "" when it
+// has none
+func normalizeETA(s string) string {
+	minDays, maxDays, ok := parseETA(s)
+	if !ok {
+		return ""
+	}
+	return formatETA(minDays, maxDays)
+}
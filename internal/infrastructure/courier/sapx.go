@@ -0,0 +1,130 @@
+package courier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+)
+
+// sapxTariffPath is SAPX's published price list endpoint
+const sapxTariffPath = "/master/shipment_cost/publish"
+
+// sapxServiceTypes maps SAPX service codes to normalised types
+var sapxServiceTypes = map[string]string{
+	"SDS": ServiceTypeSameDay,
+	"ODS": ServiceTypeExpress,
+	"REG": ServiceTypeRegular,
+	"ECO": ServiceTypeEconomy,
+	"CRG": ServiceTypeCargo,
+}
+
+// SAPXConfig holds the SAPX price list credentials and area mapping
+type SAPXConfig struct {
+	APIURL       string
+	APIKey       string
+	CustomerCode string
+	// Mapping resolves areas to SAPX branch (origin) and district (destination) codes
+	Mapping *AreaMapping
+}
+
+// SAPXQuoter quotes SAP Express services
+type SAPXQuoter struct {
+	cfg    SAPXConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSAPXQuoter creates a SAPX rate quoter
+func NewSAPXQuoter(cfg SAPXConfig, opts ...Option) *SAPXQuoter {
+	o := buildOptions(opts)
+	cfg.APIURL = strings.TrimRight(cfg.APIURL, "/")
+	return &SAPXQuoter{cfg: cfg, client: o.client, now: o.now}
+}
+
+// CourierID identifies the courier
+func (q *SAPXQuoter) CourierID() string {
+	return "sapx"
+}
+
+// sapxTariffRequest is the body of a price list request
+type sapxTariffRequest struct {
+	CustomerCode string `json:"customer_code"`
+	Origin       string `json:"origin"`
+	Destination  string `json:"destination"`
+	Weight       int64  `json:"weight"`
+}
+
+// sapxTariffResponse is the body of a price list response
+type sapxTariffResponse struct {
+	Status      string `json:"status"`
+	Msg         string `json:"msg"`
+	PriceDetail []struct {
+		ServiceTypeCode string  `json:"service_type_code"`
+		ServiceTypeName string  `json:"service_type_name"`
+		Cost            float64 `json:"cost"`
+		SLA             string  `json:"sla"`
+	} `json:"price_detail"`
+}
+
+// QuoteRates quotes SAPX services from the origin branch to the destination district
+func (q *SAPXQuoter) QuoteRates(ctx context.Context, req *RateRequest) ([]model.ShippingRate, error) {
+	origin, err := resolveCode(q.cfg.Mapping, req.Origin, "tlc_branch_code")
+	if err != nil {
+		return nil, err
+	}
+	destination, err := resolveCode(q.cfg.Mapping, req.Destination, "district_code")
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(sapxTariffRequest{
+		CustomerCode: q.cfg.CustomerCode,
+		Origin:       origin,
+		Destination:  destination,
+		Weight:       chargeableWeight(req.Weight),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sapx: failed to encode request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, q.cfg.APIURL+sapxTariffPath, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("sapx: failed to build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("api_key", q.cfg.APIKey)
+
+	body, err := send(q.client, httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("sapx: %w", err)
+	}
+
+	var resp sapxTariffResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("sapx: failed to decode response: %w", err)
+	}
+	if !strings.EqualFold(resp.Status, "SUCCESS") {
+		return nil, fmt.Errorf("sapx: price list request failed: %s", resp.Msg)
+	}
+
+	now := q.now()
+	rates := make([]model.ShippingRate, 0, len(resp.PriceDetail))
+	for _, p := range resp.PriceDetail {
+		rates = append(rates, model.ShippingRate{
+			CourierID:    q.CourierID(),
+			CourierName:  "SAP Express",
+			ServiceID:    p.ServiceTypeCode,
+			ServiceName:  p.ServiceTypeName,
+			ServiceType:  serviceType(sapxServiceTypes, p.ServiceTypeCode),
+			Price:        p.Cost,
+			EstimatedETA: normalizeETA(p.SLA),
+			CreatedAt:    now,
+		})
+	}
+	return rates, nil
+}
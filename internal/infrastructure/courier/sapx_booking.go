@@ -0,0 +1,165 @@
+package courier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+)
+
+// sapxPickupPath is SAPX's single shipment push endpoint
+const sapxPickupPath = "/shipment/pickup/single_push"
+
+// sapxPickupWindows are the daily slots SAPX collects parcels in
+var sapxPickupWindows = []pickupWindow{{10, 13}, {13, 16}}
+
+// SAPXBookingConfig holds the SAPX shipment credentials and area mapping.
+// SAPX issues separate keys for pickups and drop-offs.
+type SAPXBookingConfig struct {
+	APIURL        string
+	APIKeyPickup  string
+	APIKeyDropoff string
+	CustomerCode  string
+	// Mapping resolves areas to SAPX district codes
+	Mapping *AreaMapping
+}
+
+// SAPXBooker pushes shipments to SAP Express, which issues an AWB straight away
+type SAPXBooker struct {
+	cfg    SAPXBookingConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSAPXBooker creates a SAPX booker
+func NewSAPXBooker(cfg SAPXBookingConfig, opts ...Option) *SAPXBooker {
+	o := buildOptions(opts)
+	cfg.APIURL = strings.TrimRight(cfg.APIURL, "/")
+	return &SAPXBooker{cfg: cfg, client: o.client, now: o.now}
+}
+
+// CourierID identifies the courier
+func (b *SAPXBooker) CourierID() string {
+	return "sapx"
+}
+
+// sapxPickupRequest is the body of a shipment push
+type sapxPickupRequest struct {
+	CustomerCode            string  `json:"customer_code"`
+	ReferenceNo             string  `json:"reference_no"`
+	PickupName              string  `json:"pickup_name"`
+	PickupAddress           string  `json:"pickup_address"`
+	PickupPhone             string  `json:"pickup_phone"`
+	PickupPostalCode        string  `json:"pickup_postal_code"`
+	PickupDistrictCode      string  `json:"pickup_district_code"`
+	PickupDate              string  `json:"pickup_date,omitempty"`
+	ServiceTypeCode         string  `json:"service_type_code"`
+	Quantity                int     `json:"quantity"`
+	Weight                  float64 `json:"weight"`
+	ItemName                string  `json:"item_name"`
+	ItemValue               float64 `json:"item_value"`
+	ReceiverName            string  `json:"receiver_name"`
+	ReceiverAddress         string  `json:"receiver_address"`
+	ReceiverPhone           string  `json:"receiver_phone"`
+	ReceiverPostalCode      string  `json:"receiver_postal_code"`
+	DestinationDistrictCode string  `json:"destination_district_code"`
+}
+
+// sapxPickupResponse is the body of a shipment push response
+type sapxPickupResponse struct {
+	Status string `json:"status"`
+	Msg    string `json:"msg"`
+	Data   struct {
+		AwbNo       string `json:"awb_no"`
+		ReferenceNo string `json:"reference_no"`
+	} `json:"data"`
+}
+
+// Book pushes the shipment for pickup in the requested slot, or for drop-off
+func (b *SAPXBooker) Book(ctx context.Context, req *BookingRequest) (*model.Booking, error) {
+	origin, err := resolveCode(b.cfg.Mapping, req.Origin, "district_code")
+	if err != nil {
+		return nil, err
+	}
+	destination, err := resolveCode(b.cfg.Mapping, req.Destination, "district_code")
+	if err != nil {
+		return nil, err
+	}
+
+	body := sapxPickupRequest{
+		CustomerCode:            b.cfg.CustomerCode,
+		ReferenceNo:             req.Reference,
+		PickupName:              req.Sender.Name,
+		PickupAddress:           req.Sender.Address,
+		PickupPhone:             req.Sender.Phone,
+		PickupPostalCode:        req.Sender.PostalCode,
+		PickupDistrictCode:      origin,
+		ServiceTypeCode:         req.ServiceType,
+		Quantity:                req.Quantity,
+		Weight:                  req.Weight.InexactFloat64(),
+		ItemName:                req.ItemDescription,
+		ItemValue:               req.ItemValue.InexactFloat64(),
+		ReceiverName:            req.Recipient.Name,
+		ReceiverAddress:         req.Recipient.Address,
+		ReceiverPhone:           req.Recipient.Phone,
+		ReceiverPostalCode:      req.Recipient.PostalCode,
+		DestinationDistrictCode: destination,
+	}
+	apiKey := b.cfg.APIKeyDropoff
+	if req.Pickup != nil {
+		apiKey = b.cfg.APIKeyPickup
+		body.PickupDate = req.Pickup.PickupRequestTime.Format("2006-01-02 15:04:05")
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("sapx: no API key configured for this handover method")
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("sapx: failed to encode request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, b.cfg.APIURL+sapxPickupPath, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("sapx: failed to build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("api_key", apiKey)
+
+	respBody, err := send(b.client, httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("sapx: %w", err)
+	}
+
+	var resp sapxPickupResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("sapx: failed to decode response: %w", err)
+	}
+	if !strings.EqualFold(resp.Status, "SUCCESS") || resp.Data.AwbNo == "" {
+		return nil, fmt.Errorf("sapx: shipment push failed: %s", resp.Msg)
+	}
+
+	method := "dropoff"
+	if req.Pickup != nil {
+		method = "pickup"
+	}
+	return &model.Booking{
+		CourierID:      b.CourierID(),
+		BookingCode:    resp.Data.AwbNo,
+		TrackingNumber: resp.Data.AwbNo,
+		Status:         "booked",
+		Reference: map[string]interface{}{
+			"reference_no":  resp.Data.ReferenceNo,
+			"pickup_method": method,
+		},
+	}, nil
+}
+
+// PickupTimes lists SAPX's pickup slots on the day
+func (b *SAPXBooker) PickupTimes(_ context.Context, _ Area, day time.Time) ([]model.PickupTime, error) {
+	return pickupSlots(b.CourierID(), sapxPickupWindows, day, b.now()), nil
+}
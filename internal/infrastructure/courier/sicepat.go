@@ -0,0 +1,126 @@
+package courier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+)
+
+// siCepatTariffPath is SiCepat's tariff endpoint
+const siCepatTariffPath = "/customer/tariff"
+
+// siCepatServiceTypes maps SiCepat service codes to normalised types
+var siCepatServiceTypes = map[string]string{
+	"SDS":   ServiceTypeSameDay,
+	"BEST":  ServiceTypeExpress,
+	"REG":   ServiceTypeRegular,
+	"SIUNT": ServiceTypeRegular,
+	"HALU":  ServiceTypeEconomy,
+	"GOKIL": ServiceTypeCargo,
+}
+
+// SiCepatConfig holds the SiCepat tariff API credentials and area mappings
+type SiCepatConfig struct {
+	BaseURL string
+	APIKey  string
+	// OriginMapping resolves origin areas to SiCepat city codes
+	OriginMapping *AreaMapping
+	// DestinationMapping resolves destination areas to SiCepat district codes
+	DestinationMapping *AreaMapping
+}
+
+// SiCepatQuoter quotes SiCepat services
+type SiCepatQuoter struct {
+	cfg    SiCepatConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSiCepatQuoter creates a SiCepat rate quoter
+func NewSiCepatQuoter(cfg SiCepatConfig, opts ...Option) *SiCepatQuoter {
+	o := buildOptions(opts)
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &SiCepatQuoter{cfg: cfg, client: o.client, now: o.now}
+}
+
+// CourierID identifies the courier
+func (q *SiCepatQuoter) CourierID() string {
+	return "sicepat"
+}
+
+// siCepatTariffResponse is the body of a tariff response
+type siCepatTariffResponse struct {
+	SiCepat struct {
+		Status struct {
+			Code        int    `json:"code"`
+			Description string `json:"description"`
+		} `json:"status"`
+		Results []struct {
+			Service     string  `json:"service"`
+			Description string  `json:"description"`
+			Tariff      float64 `json:"tariff"`
+			Etd         string  `json:"etd"`
+		} `json:"results"`
+	} `json:"sicepat"`
+}
+
+// QuoteRates quotes SiCepat services from the origin city to the destination district
+func (q *SiCepatQuoter) QuoteRates(ctx context.Context, req *RateRequest) ([]model.ShippingRate, error) {
+	origin, err := resolveCode(q.cfg.OriginMapping, req.Origin, "city_code")
+	if err != nil {
+		return nil, err
+	}
+	destination, err := resolveCode(q.cfg.DestinationMapping, req.Destination, "district_code")
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{
+		"origin":      {origin},
+		"destination": {destination},
+		"weight":      {strconv.FormatInt(chargeableWeight(req.Weight), 10)},
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, q.cfg.BaseURL+siCepatTariffPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("sicepat: failed to build request: %w", err)
+	}
+	httpReq.Header.Set("api-key", q.cfg.APIKey)
+	httpReq.Header.Set("Accept", "application/json")
+
+	body, err := send(q.client, httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("sicepat: %w", err)
+	}
+
+	var resp siCepatTariffResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("sicepat: failed to decode response: %w", err)
+	}
+	if resp.SiCepat.Status.Code != http.StatusOK {
+		return nil, fmt.Errorf("sicepat: tariff request failed: %s", resp.SiCepat.Status.Description)
+	}
+
+	now := q.now()
+	rates := make([]model.ShippingRate, 0, len(resp.SiCepat.Results))
+	for _, r := range resp.SiCepat.Results {
+		rates = append(rates, model.ShippingRate{
+			CourierID:    q.CourierID(),
+			CourierName:  "SiCepat",
+			ServiceID:    r.Service,
+			ServiceName:  r.Service,
+			ServiceType:  serviceType(siCepatServiceTypes, r.Service),
+			Description:  r.Description,
+			Price:        r.Tariff,
+			EstimatedETA: normalizeETA(r.Etd),
+			CreatedAt:    now,
+		})
+	}
+	return rates, nil
+}
@@ -0,0 +1,229 @@
+package courier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+)
+
+// siCepatPickupPath is SiCepat's pickup request endpoint
+const siCepatPickupPath = "/api/partner/requestpickuppackage"
+
+// siCepatPickupWindows are the daily slots SiCepat collects parcels in
+var siCepatPickupWindows = []pickupWindow{{9, 12}, {13, 16}}
+
+// ReceiptSequence hands out increasing numbers starting at 1. Couriers that
+// let partners number their own receipts use it to step through the range
+// they allocated.
+type ReceiptSequence func(ctx context.Context) (int64, error)
+
+// SiCepatBookingConfig holds the SiCepat pickup API credentials, the receipt
+// number range SiCepat allocated to us and the area mappings
+type SiCepatBookingConfig struct {
+	PickupURL      string
+	APIKey         string
+	ResiRangeStart string
+	ResiRangeEnd   string
+	NextReceipt    ReceiptSequence
+	// OriginMapping resolves origin areas to SiCepat city codes
+	OriginMapping *AreaMapping
+	// DestinationMapping resolves destination areas to SiCepat district codes
+	DestinationMapping *AreaMapping
+}
+
+// SiCepatBooker books SiCepat pickups and drop-offs. SiCepat does not issue
+// AWBs; each parcel carries the next receipt number of our range.
+type SiCepatBooker struct {
+	cfg    SiCepatBookingConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSiCepatBooker creates a SiCepat booker
+func NewSiCepatBooker(cfg SiCepatBookingConfig, opts ...Option) *SiCepatBooker {
+	o := buildOptions(opts)
+	cfg.PickupURL = strings.TrimRight(cfg.PickupURL, "/")
+	return &SiCepatBooker{cfg: cfg, client: o.client, now: o.now}
+}
+
+// CourierID identifies the courier
+func (b *SiCepatBooker) CourierID() string {
+	return "sicepat"
+}
+
+// siCepatPickupRequest is the body of a pickup request
+type siCepatPickupRequest struct {
+	AuthKey             string                 `json:"auth_key"`
+	ReferenceNumber     string                 `json:"reference_number"`
+	PickupRequestDate   string                 `json:"pickup_request_date,omitempty"`
+	PickupMethod        string                 `json:"pickup_method"`
+	PickupMerchantName  string                 `json:"pickup_merchant_name"`
+	PickupAddress       string                 `json:"pickup_address"`
+	PickupCity          string                 `json:"pickup_city"`
+	PickupMerchantPhone string                 `json:"pickup_merchant_phone"`
+	PackageList         []siCepatPickupPackage `json:"PackageList"`
+}
+
+type siCepatPickupPackage struct {
+	ReceiptNumber     string  `json:"receipt_number"`
+	OriginCode        string  `json:"origin_code"`
+	DeliveryType      string  `json:"delivery_type"`
+	ParcelCategory    string  `json:"parcel_category"`
+	ParcelContent     string  `json:"parcel_content"`
+	ParcelQty         int     `json:"parcel_qty"`
+	ParcelValue       float64 `json:"parcel_value"`
+	TotalWeight       float64 `json:"total_weight"`
+	ShipperName       string  `json:"shipper_name"`
+	ShipperAddress    string  `json:"shipper_address"`
+	ShipperProvince   string  `json:"shipper_province"`
+	ShipperCity       string  `json:"shipper_city"`
+	ShipperDistrict   string  `json:"shipper_district"`
+	ShipperZip        string  `json:"shipper_zip"`
+	ShipperPhone      string  `json:"shipper_phone"`
+	RecipientName     string  `json:"recipient_name"`
+	RecipientAddress  string  `json:"recipient_address"`
+	RecipientProvince string  `json:"recipient_province"`
+	RecipientCity     string  `json:"recipient_city"`
+	RecipientDistrict string  `json:"recipient_district"`
+	RecipientZip      string  `json:"recipient_zip"`
+	RecipientPhone    string  `json:"recipient_phone"`
+	DestinationCode   string  `json:"destination_code"`
+}
+
+// siCepatPickupResponse is the body of a pickup response
+type siCepatPickupResponse struct {
+	Status        string `json:"status"`
+	ErrorMessage  string `json:"error_message"`
+	RequestNumber string `json:"request_number"`
+	Datas         []struct {
+		ReceiptNumber string `json:"receipt_number"`
+	} `json:"datas"`
+}
+
+// Book requests a pickup, or registers a drop-off, under the next receipt number
+func (b *SiCepatBooker) Book(ctx context.Context, req *BookingRequest) (*model.Booking, error) {
+	origin, err := resolveCode(b.cfg.OriginMapping, req.Origin, "city_code")
+	if err != nil {
+		return nil, err
+	}
+	destination, err := resolveCode(b.cfg.DestinationMapping, req.Destination, "district_code")
+	if err != nil {
+		return nil, err
+	}
+
+	receiptNumber, err := b.nextReceiptNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body := siCepatPickupRequest{
+		AuthKey:             b.cfg.APIKey,
+		ReferenceNumber:     req.Reference,
+		PickupMethod:        "DROP",
+		PickupMerchantName:  req.Sender.Name,
+		PickupAddress:       req.Sender.Address,
+		PickupCity:          req.Origin.City,
+		PickupMerchantPhone: req.Sender.Phone,
+		PackageList: []siCepatPickupPackage{{
+			ReceiptNumber:     receiptNumber,
+			OriginCode:        origin,
+			DeliveryType:      req.ServiceType,
+			ParcelCategory:    "Normal",
+			ParcelContent:     req.ItemDescription,
+			ParcelQty:         req.Quantity,
+			ParcelValue:       req.ItemValue.InexactFloat64(),
+			TotalWeight:       req.Weight.InexactFloat64(),
+			ShipperName:       req.Sender.Name,
+			ShipperAddress:    req.Sender.Address,
+			ShipperProvince:   req.Origin.Province,
+			ShipperCity:       req.Origin.City,
+			ShipperDistrict:   req.Origin.District,
+			ShipperZip:        req.Sender.PostalCode,
+			ShipperPhone:      req.Sender.Phone,
+			RecipientName:     req.Recipient.Name,
+			RecipientAddress:  req.Recipient.Address,
+			RecipientProvince: req.Destination.Province,
+			RecipientCity:     req.Destination.City,
+			RecipientDistrict: req.Destination.District,
+			RecipientZip:      req.Recipient.PostalCode,
+			RecipientPhone:    req.Recipient.Phone,
+			DestinationCode:   destination,
+		}},
+	}
+	if req.Pickup != nil {
+		body.PickupMethod = "PICKUP"
+		body.PickupRequestDate = req.Pickup.PickupRequestTime.Format("2006-01-02 15:04")
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("sicepat: failed to encode request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, b.cfg.PickupURL+siCepatPickupPath, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("sicepat: failed to build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	respBody, err := send(b.client, httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("sicepat: %w", err)
+	}
+
+	var resp siCepatPickupResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("sicepat: failed to decode response: %w", err)
+	}
+	if resp.Status != "200" {
+		return nil, fmt.Errorf("sicepat: pickup request failed: %s", resp.ErrorMessage)
+	}
+
+	return &model.Booking{
+		CourierID:      b.CourierID(),
+		BookingCode:    resp.RequestNumber,
+		TrackingNumber: receiptNumber,
+		Status:         "booked",
+		Reference: map[string]interface{}{
+			"request_number": resp.RequestNumber,
+			"pickup_method":  body.PickupMethod,
+		},
+	}, nil
+}
+
+// PickupTimes lists SiCepat's pickup slots on the day
+func (b *SiCepatBooker) PickupTimes(_ context.Context, _ Area, day time.Time) ([]model.PickupTime, error) {
+	return pickupSlots(b.CourierID(), siCepatPickupWindows, day, b.now()), nil
+}
+
+// nextReceiptNumber takes the next number of the allocated receipt range,
+// padded to the width of the range
+func (b *SiCepatBooker) nextReceiptNumber(ctx context.Context) (string, error) {
+	start, err := strconv.ParseInt(b.cfg.ResiRangeStart, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("sicepat: invalid receipt range start %q", b.cfg.ResiRangeStart)
+	}
+	end, err := strconv.ParseInt(b.cfg.ResiRangeEnd, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("sicepat: invalid receipt range end %q", b.cfg.ResiRangeEnd)
+	}
+	if b.cfg.NextReceipt == nil {
+		return "", fmt.Errorf("sicepat: no receipt sequence configured")
+	}
+
+	seq, err := b.cfg.NextReceipt(ctx)
+	if err != nil {
+		return "", fmt.Errorf("sicepat: failed to allocate receipt number: %w", err)
+	}
+	number := start + seq - 1
+	if seq < 1 || number > end {
+		return "", fmt.Errorf("sicepat: receipt number range %s-%s is exhausted", b.cfg.ResiRangeStart, b.cfg.ResiRangeEnd)
+	}
+	return fmt.Sprintf("%0*d", len(b.cfg.ResiRangeStart), number), nil
+}
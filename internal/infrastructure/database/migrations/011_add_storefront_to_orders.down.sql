@@ -0,0 +1,10 @@
+-- Drop the order number sequence
+DROP SEQUENCE IF EXISTS order_number_seq;
+
+-- Remove indexes first
+DROP INDEX IF EXISTS idx_orders_storefront_status;
+DROP INDEX IF EXISTS idx_orders_storefront_id;
+
+-- Remove storefront_id column from orders table
+ALTER TABLE orders
+DROP COLUMN IF EXISTS storefront_id;
@@ -0,0 +1,22 @@
+-- Link orders to the storefront they were placed on so storefront customers
+-- and tenant-aware queries can scope orders correctly
+ALTER TABLE orders
+ADD COLUMN IF NOT EXISTS storefront_id UUID REFERENCES storefronts(id);
+
+-- Backfill storefront for existing orders placed by storefront customers
+UPDATE orders o
+SET storefront_id = c.storefront_id
+FROM customers c
+WHERE o.customer_id = c.id AND o.storefront_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_orders_storefront_id ON orders(storefront_id);
+CREATE INDEX IF NOT EXISTS idx_orders_storefront_status ON orders(storefront_id, status)
+WHERE deleted_at IS NULL;
+
+-- Order numbers are drawn from a sequence so that concurrent checkouts never
+-- get the same number. It continues after the highest number already issued.
+CREATE SEQUENCE IF NOT EXISTS order_number_seq;
+
+SELECT setval('order_number_seq', COALESCE(MAX(CAST(SUBSTRING(order_number FROM 14) AS BIGINT)), 0) + 1, false)
+FROM orders
+WHERE order_number ~ '^ORD-[0-9]{8}-[0-9]+$';
@@ -0,0 +1,5 @@
+-- Drop stock reservations
+DROP INDEX IF EXISTS idx_stock_reservations_active_expiry;
+DROP INDEX IF EXISTS idx_stock_reservations_order_id;
+
+DROP TABLE IF EXISTS stock_reservations CASCADE;
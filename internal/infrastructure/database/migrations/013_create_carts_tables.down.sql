@@ -0,0 +1,4 @@
+-- Drop cart tables
+
+DROP TABLE IF EXISTS cart_items CASCADE;
+DROP TABLE IF EXISTS carts CASCADE;
@@ -0,0 +1,45 @@
+-- Server-side shopping carts. A cart belongs either to a signed-in customer or,
+-- when the storefront allows guest checkout, to an anonymous session.
+CREATE TABLE IF NOT EXISTS carts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    storefront_id UUID NOT NULL REFERENCES storefronts(id) ON DELETE CASCADE,
+
+    -- Owner
+    customer_id UUID REFERENCES customers(id) ON DELETE CASCADE,
+    session_id VARCHAR(100),
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT carts_owner_check CHECK (customer_id IS NOT NULL OR session_id IS NOT NULL)
+);
+
+-- Cart Items
+CREATE TABLE IF NOT EXISTS cart_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    cart_id UUID NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+
+    -- Product Information
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    product_variant_id UUID REFERENCES product_variants(id) ON DELETE CASCADE,
+
+    -- Item Details
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_price DECIMAL(15,2) NOT NULL, -- price when the item was added
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_carts_storefront_customer ON carts(storefront_id, customer_id)
+WHERE customer_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_carts_storefront_session ON carts(storefront_id, session_id)
+WHERE session_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_carts_updated_at ON carts(updated_at);
+
+CREATE INDEX IF NOT EXISTS idx_cart_items_cart_id ON cart_items(cart_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cart_items_cart_product_variant ON cart_items(
+    cart_id, product_id, COALESCE(product_variant_id, '00000000-0000-0000-0000-000000000000'::uuid)
+);
@@ -0,0 +1,5 @@
+-- Drop courier webhook events
+DROP INDEX IF EXISTS idx_courier_webhook_events_status;
+DROP INDEX IF EXISTS idx_courier_webhook_events_courier_received;
+
+DROP TABLE IF EXISTS courier_webhook_events CASCADE;
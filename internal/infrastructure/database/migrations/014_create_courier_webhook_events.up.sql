@@ -0,0 +1,27 @@
+-- Raw courier webhook deliveries. The payload is stored exactly as received so
+-- that deliveries can be replayed once a mapping or processing bug is fixed.
+CREATE TABLE IF NOT EXISTS courier_webhook_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    courier_code VARCHAR(50) NOT NULL,
+
+    -- Delivery
+    payload TEXT NOT NULL,
+    signature VARCHAR(512),
+    remote_ip VARCHAR(45),
+
+    -- Processing Outcome
+    status VARCHAR(20) NOT NULL DEFAULT 'received'
+        CHECK (status IN ('received', 'processed', 'failed', 'rejected')),
+    error_message TEXT,
+    updates_count INTEGER NOT NULL DEFAULT 0,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    processed_at TIMESTAMP WITH TIME ZONE,
+
+    -- Timestamps
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_courier_webhook_events_courier_received ON courier_webhook_events(courier_code, received_at DESC);
+CREATE INDEX IF NOT EXISTS idx_courier_webhook_events_status ON courier_webhook_events(status);
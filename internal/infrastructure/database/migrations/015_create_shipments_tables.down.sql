@@ -0,0 +1,8 @@
+-- Drop shipment tracking tables
+DROP INDEX IF EXISTS idx_shipment_events_shipment_occurred;
+DROP INDEX IF EXISTS idx_shipments_warranty_claim_id;
+DROP INDEX IF EXISTS idx_shipments_order_id;
+DROP INDEX IF EXISTS idx_shipments_tracking_number;
+
+DROP TABLE IF EXISTS shipment_events CASCADE;
+DROP TABLE IF EXISTS shipments CASCADE;
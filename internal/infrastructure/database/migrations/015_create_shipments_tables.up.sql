@@ -0,0 +1,52 @@
+-- Shipments tracked through courier webhooks. The current state is derived
+-- from the newest event; delivered_at is the time of the first delivery scan.
+CREATE TABLE IF NOT EXISTS shipments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    courier_code VARCHAR(50) NOT NULL,
+    tracking_number VARCHAR(100) NOT NULL,
+
+    -- What Is Being Shipped
+    order_id UUID REFERENCES orders(id) ON DELETE SET NULL,
+    warranty_claim_id UUID REFERENCES warranty_claims(id) ON DELETE SET NULL,
+
+    -- Current State
+    status VARCHAR(30) NOT NULL DEFAULT 'unknown'
+        CHECK (status IN ('unknown', 'pickup_pending', 'picked_up', 'in_transit', 'out_for_delivery',
+                          'delivered', 'delivery_failed', 'returning', 'returned', 'exception')),
+    status_text TEXT,
+    location VARCHAR(255),
+    last_event_at TIMESTAMP WITH TIME ZONE,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_shipments_courier_tracking UNIQUE (courier_code, tracking_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipments_tracking_number ON shipments(tracking_number);
+CREATE INDEX IF NOT EXISTS idx_shipments_order_id ON shipments(order_id) WHERE order_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_shipments_warranty_claim_id ON shipments(warranty_claim_id) WHERE warranty_claim_id IS NOT NULL;
+
+-- Normalized tracking history. Couriers resend events, so each event is
+-- stored once per shipment, keyed by a hash of its content.
+CREATE TABLE IF NOT EXISTS shipment_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shipment_id UUID NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+
+    -- Event
+    status VARCHAR(30) NOT NULL,
+    raw_status VARCHAR(100),
+    description TEXT,
+    location VARCHAR(255),
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    dedup_key VARCHAR(64) NOT NULL,
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_shipment_events_dedup UNIQUE (shipment_id, dedup_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipment_events_shipment_occurred ON shipment_events(shipment_id, occurred_at);
@@ -0,0 +1,14 @@
+-- Drop courier webhook event retries
+DROP INDEX IF EXISTS idx_courier_webhook_events_retry;
+
+UPDATE courier_webhook_events SET status = 'failed' WHERE status = 'dead_lettered';
+
+ALTER TABLE courier_webhook_events DROP CONSTRAINT IF EXISTS courier_webhook_events_status_check;
+ALTER TABLE courier_webhook_events ADD CONSTRAINT courier_webhook_events_status_check
+    CHECK (status IN ('received', 'processed', 'failed', 'rejected'));
+
+ALTER TABLE courier_webhook_events
+    DROP COLUMN IF EXISTS next_retry_at,
+    DROP COLUMN IF EXISTS last_attempt_at,
+    DROP COLUMN IF EXISTS signature_valid,
+    DROP COLUMN IF EXISTS headers;
@@ -0,0 +1,14 @@
+-- Keep the full delivery (headers and signature check result) with each courier
+-- webhook event and schedule failed deliveries for retry. Events that keep
+-- failing are dead-lettered until an admin replays them.
+ALTER TABLE courier_webhook_events
+    ADD COLUMN IF NOT EXISTS headers JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS signature_valid BOOLEAN,
+    ADD COLUMN IF NOT EXISTS last_attempt_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS next_retry_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE courier_webhook_events DROP CONSTRAINT IF EXISTS courier_webhook_events_status_check;
+ALTER TABLE courier_webhook_events ADD CONSTRAINT courier_webhook_events_status_check
+    CHECK (status IN ('received', 'processed', 'failed', 'dead_lettered', 'rejected'));
+
+CREATE INDEX IF NOT EXISTS idx_courier_webhook_events_retry ON courier_webhook_events(next_retry_at) WHERE status = 'failed';
@@ -0,0 +1,5 @@
+-- Drop tenant migrations
+DROP INDEX IF EXISTS idx_tenant_migrations_running;
+DROP INDEX IF EXISTS idx_tenant_migrations_storefront;
+
+DROP TABLE IF EXISTS tenant_migrations CASCADE;
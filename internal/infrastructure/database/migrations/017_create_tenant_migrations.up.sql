@@ -0,0 +1,30 @@
+-- Tenant migration jobs. A storefront's isolation strategy is the target of its
+-- latest completed migration; storefronts without one use the shared schema.
+CREATE TABLE IF NOT EXISTS tenant_migrations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    storefront_id UUID NOT NULL REFERENCES storefronts(id) ON DELETE CASCADE,
+    source_type VARCHAR(20) NOT NULL CHECK (source_type IN ('shared', 'schema', 'database')),
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('shared', 'schema', 'database')),
+
+    -- Progress
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'copying', 'verifying', 'completed', 'failed', 'rolled_back')),
+    current_table VARCHAR(100),
+    tables JSONB NOT NULL DEFAULT '[]',
+    rows_total BIGINT NOT NULL DEFAULT 0,
+    rows_copied BIGINT NOT NULL DEFAULT 0,
+    error_message TEXT,
+
+    -- Timestamps
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    rolled_back_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_migrations_storefront ON tenant_migrations(storefront_id, created_at DESC);
+
+-- Only one migration per storefront may run at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_migrations_running ON tenant_migrations(storefront_id)
+    WHERE status IN ('pending', 'copying', 'verifying');
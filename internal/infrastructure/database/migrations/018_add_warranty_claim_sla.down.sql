@@ -0,0 +1,12 @@
+-- Drop warranty claim SLA tracking
+DROP INDEX IF EXISTS idx_warranty_claims_sla_open;
+
+ALTER TABLE warranty_claims
+    DROP COLUMN IF EXISTS sla_breached_at,
+    DROP COLUMN IF EXISTS sla_escalated_at,
+    DROP COLUMN IF EXISTS sla_stopped_at,
+    DROP COLUMN IF EXISTS sla_paused_seconds,
+    DROP COLUMN IF EXISTS sla_paused_at,
+    DROP COLUMN IF EXISTS sla_at_risk_at,
+    DROP COLUMN IF EXISTS sla_due_at,
+    DROP COLUMN IF EXISTS sla_policy_hours;
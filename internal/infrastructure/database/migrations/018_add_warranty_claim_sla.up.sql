@@ -0,0 +1,17 @@
+-- Track the processing SLA of each warranty claim. The due-by comes from the
+-- warranty policy's claim_processing_sla_hours scaled by the claim priority,
+-- is pushed back while the claim waits on the customer, and stops once the
+-- claim is resolved. Escalation and breach timestamps keep the sweeper from
+-- alerting twice.
+ALTER TABLE warranty_claims
+    ADD COLUMN IF NOT EXISTS sla_policy_hours INTEGER,
+    ADD COLUMN IF NOT EXISTS sla_due_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS sla_at_risk_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS sla_paused_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS sla_paused_seconds BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS sla_stopped_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS sla_escalated_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS sla_breached_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_warranty_claims_sla_open ON warranty_claims(sla_at_risk_at, sla_due_at)
+    WHERE sla_stopped_at IS NULL AND sla_paused_at IS NULL;
@@ -0,0 +1,9 @@
+-- Drop persisted customer session tracking
+DROP INDEX IF EXISTS idx_customer_sessions_active;
+
+ALTER TABLE customer_sessions DROP COLUMN IF EXISTS revoked_reason;
+
+CREATE TRIGGER update_customer_sessions_last_used_at
+    BEFORE UPDATE ON customer_sessions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
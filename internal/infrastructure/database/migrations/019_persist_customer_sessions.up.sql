@@ -0,0 +1,14 @@
+-- Customer sessions are persisted so they survive restarts and can be revoked
+-- from any replica. session_token and refresh_token hold SHA-256 digests of the
+-- issued JWTs, never the tokens themselves.
+
+-- customer_sessions has no updated_at column, so the generic updated_at
+-- trigger made every UPDATE fail. The repository maintains last_used_at.
+DROP TRIGGER IF EXISTS update_customer_sessions_last_used_at ON customer_sessions;
+
+-- Why a session ended: logout, revoked, admin_revoked, session_limit or
+-- refresh_token_reuse
+ALTER TABLE customer_sessions ADD COLUMN IF NOT EXISTS revoked_reason VARCHAR(50);
+
+CREATE INDEX IF NOT EXISTS idx_customer_sessions_active ON customer_sessions(storefront_id, customer_id, last_used_at DESC)
+    WHERE revoked_at IS NULL;
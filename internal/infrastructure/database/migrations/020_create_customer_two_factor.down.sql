@@ -0,0 +1,7 @@
+-- Drop customer two-factor authentication
+ALTER TABLE customer_sessions
+    DROP COLUMN IF EXISTS two_factor_verified_at,
+    DROP COLUMN IF EXISTS two_factor_required;
+
+DROP TRIGGER IF EXISTS update_customer_two_factor_updated_at ON customer_two_factor;
+DROP TABLE IF EXISTS customer_two_factor;
@@ -0,0 +1,28 @@
+-- TOTP two-factor authentication for storefront customers. The secret is
+-- sealed with AES-GCM before it is stored; recovery codes are kept as SHA-256
+-- digests and removed once used. last_used_step stops a code from being
+-- replayed within its validity window.
+CREATE TABLE IF NOT EXISTS customer_two_factor (
+    customer_id UUID PRIMARY KEY REFERENCES customers(id) ON DELETE CASCADE,
+    storefront_id UUID NOT NULL REFERENCES storefronts(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,
+    recovery_code_hashes TEXT[] NOT NULL DEFAULT '{}',
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    enabled_at TIMESTAMP WITH TIME ZONE, -- NULL while enrollment awaits its first code
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_two_factor_storefront_id ON customer_two_factor(storefront_id);
+
+CREATE TRIGGER update_customer_two_factor_updated_at
+    BEFORE UPDATE ON customer_two_factor
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Sessions of enrolled customers need a second factor before they are trusted
+ALTER TABLE customer_sessions
+    ADD COLUMN IF NOT EXISTS two_factor_required BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS two_factor_verified_at TIMESTAMP WITH TIME ZONE;
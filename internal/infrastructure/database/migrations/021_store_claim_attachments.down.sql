@@ -0,0 +1,9 @@
+-- Drop claim attachment storage tracking
+DROP INDEX IF EXISTS idx_claim_attachments_scan_pending;
+
+ALTER TABLE claim_attachments DROP COLUMN IF EXISTS deleted_at;
+
+-- Attachments uploaded by sellers would violate the constraint, so existing
+-- rows are not checked
+ALTER TABLE claim_attachments
+    ADD CONSTRAINT claim_attachments_uploaded_by_fkey FOREIGN KEY (uploaded_by) REFERENCES customers(id) NOT VALID;
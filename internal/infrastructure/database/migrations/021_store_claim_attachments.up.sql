@@ -0,0 +1,11 @@
+-- Claim attachments are stored in object storage and virus scanned after
+-- upload. Sellers upload attachments too, so uploaded_by no longer has to be
+-- a customer.
+ALTER TABLE claim_attachments DROP CONSTRAINT IF EXISTS claim_attachments_uploaded_by_fkey;
+
+-- The claim repository soft deletes attachments
+ALTER TABLE claim_attachments ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Attachments still waiting on a scan, picked up again after a restart
+CREATE INDEX IF NOT EXISTS idx_claim_attachments_scan_pending ON claim_attachments(uploaded_at)
+    WHERE virus_scan_status = 'pending' AND deleted_at IS NULL;
@@ -0,0 +1,19 @@
+-- Drop repair ticket work order tracking
+ALTER TABLE warranty_claims
+    DROP COLUMN IF EXISTS repair_parts_cost,
+    DROP COLUMN IF EXISTS repair_labor_cost;
+
+DROP INDEX IF EXISTS idx_repair_tickets_ticket_number;
+
+ALTER TABLE repair_tickets ALTER COLUMN test_results SET DEFAULT '{}'::jsonb;
+
+ALTER TABLE repair_tickets
+    DROP COLUMN IF EXISTS quality_checked_at,
+    DROP COLUMN IF EXISTS quality_checked_by,
+    DROP COLUMN IF EXISTS created_by,
+    DROP COLUMN IF EXISTS special_instructions,
+    DROP COLUMN IF EXISTS required_parts,
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS estimated_hours,
+    DROP COLUMN IF EXISTS priority,
+    DROP COLUMN IF EXISTS ticket_number;
@@ -0,0 +1,30 @@
+-- Repair tickets carry the work order a seller creates for a claim: a
+-- per-storefront ticket number, priority and estimate, the parts expected to
+-- be needed, and who created and quality checked the repair
+ALTER TABLE repair_tickets
+    ADD COLUMN IF NOT EXISTS ticket_number VARCHAR(30),
+    ADD COLUMN IF NOT EXISTS priority VARCHAR(10) NOT NULL DEFAULT 'normal'
+        CHECK (priority IN ('low', 'normal', 'high', 'urgent')),
+    ADD COLUMN IF NOT EXISTS estimated_hours DECIMAL(6,2),
+    ADD COLUMN IF NOT EXISTS description TEXT,
+    ADD COLUMN IF NOT EXISTS required_parts TEXT[],
+    ADD COLUMN IF NOT EXISTS special_instructions TEXT,
+    ADD COLUMN IF NOT EXISTS created_by UUID REFERENCES users(id),
+    ADD COLUMN IF NOT EXISTS quality_checked_by UUID REFERENCES users(id),
+    ADD COLUMN IF NOT EXISTS quality_checked_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE repair_tickets SET ticket_number = 'RPR-' || UPPER(SUBSTRING(id::text FROM 1 FOR 8))
+    WHERE ticket_number IS NULL;
+ALTER TABLE repair_tickets ALTER COLUMN ticket_number SET NOT NULL;
+
+-- Test results are a list of individual tests
+ALTER TABLE repair_tickets ALTER COLUMN test_results SET DEFAULT '[]'::jsonb;
+UPDATE repair_tickets SET test_results = '[]'::jsonb WHERE test_results = '{}'::jsonb;
+
+CREATE INDEX IF NOT EXISTS idx_repair_tickets_ticket_number ON repair_tickets(ticket_number);
+
+-- The labor and parts split of a claim's repair cost, filled in from the
+-- repair ticket that completed it
+ALTER TABLE warranty_claims
+    ADD COLUMN IF NOT EXISTS repair_labor_cost DECIMAL(15,2) NOT NULL DEFAULT 0.00,
+    ADD COLUMN IF NOT EXISTS repair_parts_cost DECIMAL(15,2) NOT NULL DEFAULT 0.00;
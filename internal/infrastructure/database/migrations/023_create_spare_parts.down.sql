@@ -0,0 +1,5 @@
+-- Drop the spare parts inventory
+DROP TABLE IF EXISTS spare_part_reservations;
+
+DROP TRIGGER IF EXISTS update_spare_parts_updated_at ON spare_parts;
+DROP TABLE IF EXISTS spare_parts;
@@ -0,0 +1,55 @@
+-- Spare parts catalogue of a storefront's service centre. quantity_reserved
+-- is the part of quantity_on_hand held by repair tickets in progress; a part
+-- needs reordering once the quantity still available drops to its
+-- reorder_threshold. unit_cost is the weighted average cost of stock received.
+CREATE TABLE IF NOT EXISTS spare_parts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    storefront_id UUID NOT NULL REFERENCES storefronts(id) ON DELETE CASCADE,
+    part_number VARCHAR(100) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    supplier VARCHAR(255),
+    unit_cost DECIMAL(15,2) NOT NULL DEFAULT 0.00 CHECK (unit_cost >= 0),
+    quantity_on_hand INTEGER NOT NULL DEFAULT 0 CHECK (quantity_on_hand >= 0),
+    quantity_reserved INTEGER NOT NULL DEFAULT 0 CHECK (quantity_reserved >= 0),
+    reorder_threshold INTEGER NOT NULL DEFAULT 0 CHECK (reorder_threshold >= 0),
+    reorder_quantity INTEGER NOT NULL DEFAULT 0 CHECK (reorder_quantity >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT chk_spare_parts_reserved_on_hand CHECK (quantity_reserved <= quantity_on_hand)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_spare_parts_storefront_part_number ON spare_parts(storefront_id, part_number)
+WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_spare_parts_reorder ON spare_parts(storefront_id)
+WHERE deleted_at IS NULL AND quantity_on_hand - quantity_reserved <= reorder_threshold;
+
+CREATE TRIGGER update_spare_parts_updated_at
+    BEFORE UPDATE ON spare_parts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Parts a repair ticket needs. A pending reservation is waiting for stock, a
+-- reserved one holds stock, and a consumed one was used by the completed
+-- repair at the recorded unit cost.
+CREATE TABLE IF NOT EXISTS spare_part_reservations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    spare_part_id UUID NOT NULL REFERENCES spare_parts(id),
+    repair_ticket_id UUID NOT NULL REFERENCES repair_tickets(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'reserved', 'consumed', 'released')),
+    unit_cost DECIMAL(15,2),
+    reserved_at TIMESTAMP WITH TIME ZONE,
+    consumed_at TIMESTAMP WITH TIME ZONE,
+    released_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_spare_part_reservations_ticket_id ON spare_part_reservations(repair_ticket_id);
+CREATE INDEX IF NOT EXISTS idx_spare_part_reservations_open ON spare_part_reservations(spare_part_id)
+WHERE status IN ('pending', 'reserved');
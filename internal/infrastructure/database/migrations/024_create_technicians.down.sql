@@ -0,0 +1,7 @@
+-- Drop the technician roster
+DROP INDEX IF EXISTS idx_warranty_claims_technician_open;
+
+DROP TABLE IF EXISTS technician_skills;
+
+DROP TRIGGER IF EXISTS update_technicians_updated_at ON technicians;
+DROP TABLE IF EXISTS technicians;
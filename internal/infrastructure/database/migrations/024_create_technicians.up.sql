@@ -0,0 +1,38 @@
+-- Repair roster of a storefront. Each technician is a seller user with a
+-- capacity of concurrent claims and weekly working hours in their timezone.
+-- Claims are assigned to the technician's user, as before.
+CREATE TABLE IF NOT EXISTS technicians (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    storefront_id UUID NOT NULL REFERENCES storefronts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    max_concurrent_tickets INTEGER NOT NULL DEFAULT 5 CHECK (max_concurrent_tickets > 0),
+    working_hours JSONB NOT NULL DEFAULT '[]'::jsonb,
+    timezone VARCHAR(50) NOT NULL DEFAULT 'Asia/Jakarta',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_technicians_storefront_user ON technicians(storefront_id, user_id)
+WHERE deleted_at IS NULL;
+
+CREATE TRIGGER update_technicians_updated_at
+    BEFORE UPDATE ON technicians
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Product categories a technician repairs, including their subcategories
+CREATE TABLE IF NOT EXISTS technician_skills (
+    technician_id UUID NOT NULL REFERENCES technicians(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES product_categories(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (technician_id, category_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_technician_skills_category_id ON technician_skills(category_id);
+
+-- Workload counts look up a technician's open claims
+CREATE INDEX IF NOT EXISTS idx_warranty_claims_technician_open ON warranty_claims(storefront_id, assigned_technician_id)
+WHERE status IN ('assigned', 'in_repair');
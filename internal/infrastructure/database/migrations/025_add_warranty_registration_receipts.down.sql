@@ -0,0 +1,2 @@
+-- Remove the proof of purchase of registered warranty barcodes
+ALTER TABLE warranty_barcodes DROP COLUMN IF EXISTS proof_of_purchase_path;
@@ -0,0 +1,3 @@
+-- Customers register distributed barcodes themselves. The proof of purchase
+-- they upload is kept in object storage under this key.
+ALTER TABLE warranty_barcodes ADD COLUMN IF NOT EXISTS proof_of_purchase_path VARCHAR(500);
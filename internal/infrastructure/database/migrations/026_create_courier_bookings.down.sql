@@ -0,0 +1,5 @@
+-- Drop courier bookings and receipt sequences
+DROP TABLE IF EXISTS courier_receipt_sequences;
+
+DROP TRIGGER IF EXISTS update_courier_bookings_updated_at ON courier_bookings;
+DROP TABLE IF EXISTS courier_bookings;
@@ -0,0 +1,38 @@
+-- Parcels booked with a courier from the seller dashboard. The courier's AWB
+-- becomes the order's tracking number; pickup_start/pickup_end hold the slot
+-- the courier collects the parcel in and are empty for drop-offs.
+CREATE TABLE IF NOT EXISTS courier_bookings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    courier_code VARCHAR(50) NOT NULL,
+    service_type VARCHAR(50) NOT NULL,
+    booking_code VARCHAR(100),
+    tracking_number VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'booked'
+        CHECK (status IN ('booked', 'cancelled')),
+    pickup_start TIMESTAMP WITH TIME ZONE,
+    pickup_end TIMESTAMP WITH TIME ZONE,
+    booked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_courier_bookings_pickup CHECK ((pickup_start IS NULL) = (pickup_end IS NULL))
+);
+
+-- An order has at most one active booking
+CREATE UNIQUE INDEX IF NOT EXISTS idx_courier_bookings_active_order ON courier_bookings(order_id)
+WHERE status = 'booked';
+CREATE INDEX IF NOT EXISTS idx_courier_bookings_tracking_number ON courier_bookings(courier_code, tracking_number);
+
+CREATE TRIGGER update_courier_bookings_updated_at
+    BEFORE UPDATE ON courier_bookings
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Couriers that let partners number their own receipts allocate a range;
+-- last_value is how far into it we are.
+CREATE TABLE IF NOT EXISTS courier_receipt_sequences (
+    courier_code VARCHAR(50) PRIMARY KEY,
+    last_value BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+-- Remove the booked weight of courier bookings
+DROP INDEX IF EXISTS idx_courier_bookings_created_at;
+ALTER TABLE courier_bookings DROP COLUMN IF EXISTS weight;
@@ -0,0 +1,7 @@
+-- Labels print the weight the parcel was booked at, which the seller may have
+-- set instead of the weight of the order's items
+ALTER TABLE courier_bookings ADD COLUMN IF NOT EXISTS weight DECIMAL(10,3);
+
+-- Labels for a day's bookings are printed in bulk
+CREATE INDEX IF NOT EXISTS idx_courier_bookings_created_at ON courier_bookings(created_at)
+WHERE status = 'booked';
@@ -0,0 +1,11 @@
+-- Interrupted rollbacks go back to the outcome they started from
+UPDATE tenant_migrations SET status = 'completed' WHERE status = 'rolling_back' AND completed_at IS NOT NULL;
+UPDATE tenant_migrations SET status = 'failed' WHERE status = 'rolling_back';
+
+DROP INDEX IF EXISTS idx_tenant_migrations_running;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_migrations_running ON tenant_migrations(storefront_id)
+    WHERE status IN ('pending', 'copying', 'verifying');
+
+ALTER TABLE tenant_migrations DROP CONSTRAINT IF EXISTS tenant_migrations_status_check;
+ALTER TABLE tenant_migrations ADD CONSTRAINT tenant_migrations_status_check
+    CHECK (status IN ('pending', 'copying', 'verifying', 'completed', 'failed', 'rolled_back'));
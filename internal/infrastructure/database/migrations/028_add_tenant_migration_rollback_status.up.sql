@@ -0,0 +1,9 @@
+-- Rollbacks hold the storefront like a running migration while they check the
+-- target for writes and switch back, so no new migration may start meanwhile
+ALTER TABLE tenant_migrations DROP CONSTRAINT IF EXISTS tenant_migrations_status_check;
+ALTER TABLE tenant_migrations ADD CONSTRAINT tenant_migrations_status_check
+    CHECK (status IN ('pending', 'copying', 'verifying', 'rolling_back', 'completed', 'failed', 'rolled_back'));
+
+DROP INDEX IF EXISTS idx_tenant_migrations_running;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_migrations_running ON tenant_migrations(storefront_id)
+    WHERE status IN ('pending', 'copying', 'verifying', 'rolling_back');
@@ -0,0 +1,3 @@
+-- Remove the amounts received for orders
+DROP TABLE IF EXISTS order_payments;
+ALTER TABLE orders DROP COLUMN IF EXISTS paid_amount;
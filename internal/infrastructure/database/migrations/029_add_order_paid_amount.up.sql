@@ -0,0 +1,16 @@
+-- Orders can be paid in parts, possibly through several gateway charges.
+-- Each charge keeps the highest amount its gateway reported as paid, and the
+-- order keeps the total of its charges.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS paid_amount DECIMAL(15,2) NOT NULL DEFAULT 0.00;
+
+CREATE TABLE IF NOT EXISTS order_payments (
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    payment_reference VARCHAR(255) NOT NULL,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (order_id, payment_reference)
+);
+
+-- Orders settled before amounts were tracked count as paid in full
+UPDATE orders SET paid_amount = total_amount WHERE payment_status = 'paid';
@@ -0,0 +1,2 @@
+-- Remove soft deletion of warranty claims
+ALTER TABLE warranty_claims DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,2 @@
+-- The claim repository soft deletes claims and filters them out of every query
+ALTER TABLE warranty_claims ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
@@ -10,8 +10,18 @@ import (
 // TxFn represents a function that can be executed within a transaction
 type TxFn func(ctx context.Context, tx *sqlx.Tx) error
 
-// WithTransaction executes the given function within a database transaction
+// txContextKey is the context key under which the active transaction is stored
+type txContextKey struct{}
+
+// WithTransaction executes the given function within a database transaction.
+// The transaction is also attached to the context handed to fn, so repository
+// calls made with that context join it (see TxFromContext). Nested calls reuse
+// the outer transaction instead of opening a new one.
 func WithTransaction(ctx context.Context, db *sqlx.DB, fn TxFn) error {
+	if tx, ok := TxFromContext(ctx); ok {
+		return fn(ctx, tx)
+	}
+
 	tx, err := db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
@@ -24,7 +34,7 @@ func WithTransaction(ctx context.Context, db *sqlx.DB, fn TxFn) error {
 		}
 	}()
 
-	if err := fn(ctx, tx); err != nil {
+	if err := fn(context.WithValue(ctx, txContextKey{}, tx), tx); err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
 			return fmt.Errorf("rollback error: %v (original error: %w)", rbErr, err)
 		}
@@ -37,3 +47,9 @@ func WithTransaction(ctx context.Context, db *sqlx.DB, fn TxFn) error {
 
 	return nil
 }
+
+// TxFromContext returns the transaction started by WithTransaction, if any
+func TxFromContext(ctx context.Context) (*sqlx.Tx, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(*sqlx.Tx)
+	return tx, ok && tx != nil
+}
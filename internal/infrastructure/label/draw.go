@@ -0,0 +1,198 @@
+package label
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+)
+
+// canvas is a drawing surface for one label. Coordinates are millimetres
+// from the label's top-left corner; text is positioned by the top of its line.
+type canvas interface {
+	text(x, y, size float64, bold bool, s string)
+	textWidth(size float64, bold bool, s string) float64
+	line(x1, y1, x2, y2 float64)
+	rect(x, y, w, h float64)
+	barcode(x, y, w, h float64, modules []bool)
+}
+
+// Layout of the label, in millimetres
+const (
+	margin     = 4.0
+	innerWidth = labelWidth - 2*margin
+	lineHeight = 4.2
+)
+
+// courierNames are the names printed in the label header
+var courierNames = map[string]string{
+	"jne":     "JNE",
+	"jnt":     "J&T EXPRESS",
+	"sicepat": "SICEPAT",
+	"sapx":    "SAP EXPRESS",
+}
+
+// drawLabel lays a receipt out on an A6 label
+func drawLabel(c canvas, r *model.Receipt, includeBarcode bool) error {
+	// Header: courier and service
+	name, ok := courierNames[strings.ToLower(r.CourierID)]
+	if !ok {
+		name = strings.ToUpper(r.CourierID)
+	}
+	c.text(margin, 5, 14, true, name)
+	if r.ServiceType != "" {
+		service := strings.ToUpper(r.ServiceType)
+		w := c.textWidth(14, true, service) + 4
+		c.rect(labelWidth-margin-w, 4, w, 8)
+		c.text(labelWidth-margin-w+2, 5, 14, true, service)
+	}
+	c.line(margin, 14, labelWidth-margin, 14)
+
+	// AWB, with its barcode
+	y := 17.0
+	if includeBarcode {
+		modules, err := barcodeModules(r.TrackingNumber)
+		if err != nil {
+			return err
+		}
+		c.barcode(margin+4, y, innerWidth-8, 19, modules)
+		y += 21
+		centered(c, y, 12, true, r.TrackingNumber)
+	} else {
+		y += 6
+		centered(c, y, 18, true, r.TrackingNumber)
+	}
+	c.line(margin, 44, labelWidth-margin, 44)
+
+	// Recipient
+	c.text(margin, 46, 7, true, "TO")
+	c.text(margin, 49.5, 11, true, fit(c, r.RecipientName, 11, true, innerWidth))
+	c.text(margin, 55, 9, false, r.RecipientPhone)
+	for i, l := range wrap(c, r.RecipientAddress, 9, false, innerWidth, 4) {
+		c.text(margin, 59.5+float64(i)*lineHeight, 9, false, l)
+	}
+	c.line(margin, 78, labelWidth-margin, 78)
+
+	// Sender
+	c.text(margin, 80, 7, true, "FROM")
+	c.text(margin, 83.5, 9, true, fit(c, r.SenderName, 9, true, innerWidth))
+	c.text(margin, 87.7, 9, false, r.SenderPhone)
+	for i, l := range wrap(c, r.SenderAddress, 9, false, innerWidth, 2) {
+		c.text(margin, 91.9+float64(i)*lineHeight, 9, false, l)
+	}
+	c.line(margin, 101, labelWidth-margin, 101)
+
+	// Parcel
+	weight := "Weight: -"
+	if r.Weight > 0 {
+		weight = "Weight: " + decimal.NewFromFloat(r.Weight).Round(2).String() + " kg"
+	}
+	c.text(margin, 103, 9, true, weight)
+	if r.ReceiptNumber != "" {
+		ref := "Ref: " + r.ReceiptNumber
+		c.text(labelWidth-margin-c.textWidth(9, true, ref), 103, 9, true, ref)
+	}
+	if r.Notes != "" {
+		c.text(margin, 107.5, 8, false, fit(c, r.Notes, 8, false, innerWidth))
+	}
+	c.line(margin, 112, labelWidth-margin, 112)
+
+	// Contents
+	c.text(margin, 114, 7, true, "CONTENTS")
+	y = 117.5
+	lines := contentLines(r.Items)
+	if r.SpecialInstructions != "" {
+		lines = append(lines, "Note: "+r.SpecialInstructions)
+	}
+	const maxContentLines = 5
+	if len(lines) > maxContentLines {
+		more := len(lines) - maxContentLines + 1
+		lines = append(lines[:maxContentLines-1], fmt.Sprintf("+%d more", more))
+	}
+	for _, l := range lines {
+		c.text(margin, y, 8, false, fit(c, l, 8, false, innerWidth))
+		y += 3.8
+	}
+
+	// Footer
+	if !r.GeneratedAt.IsZero() {
+		c.text(margin, 142, 6, false, "Printed "+r.GeneratedAt.Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+// contentLines lists the items of the parcel, one per line
+func contentLines(items []model.Item) []string {
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		line := fmt.Sprintf("%dx %s", item.Quantity, item.Name)
+		if item.SKU != "" {
+			line += " (" + item.SKU + ")"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// centered writes a line of text centred across the label
+func centered(c canvas, y, size float64, bold bool, s string) {
+	s = fit(c, s, size, bold, innerWidth)
+	c.text((labelWidth-c.textWidth(size, bold, s))/2, y, size, bold, s)
+}
+
+// wrap breaks text into lines no wider than width, ending the last line with
+// an ellipsis when it does not fit in maxLines
+func wrap(c canvas, s string, size float64, bold bool, width float64, maxLines int) []string {
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if c.textWidth(size, bold, candidate) <= width {
+			current = candidate
+			continue
+		}
+		if current != "" {
+			lines = append(lines, current)
+		}
+		current = word
+		// A single word wider than the line is split where it overflows
+		for c.textWidth(size, bold, current) > width {
+			runes := []rune(current)
+			n := len(runes) - 1
+			for n > 1 && c.textWidth(size, bold, string(runes[:n])) > width {
+				n--
+			}
+			lines = append(lines, string(runes[:n]))
+			current = string(runes[n:])
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		lines[maxLines-1] = fit(c, lines[maxLines-1]+" ...", size, bold, width)
+	}
+	return lines
+}
+
+// fit shortens text to width, marking the cut with an ellipsis
+func fit(c canvas, s string, size float64, bold bool, width float64) string {
+	if c.textWidth(size, bold, s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for n := len(runes) - 1; n > 0; n-- {
+		cut := strings.TrimSpace(string(runes[:n])) + "..."
+		if c.textWidth(size, bold, cut) <= width {
+			return cut
+		}
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package label
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+)
+
+// dpi is the resolution of image labels, that of common thermal printers
+const dpi = 203
+
+// pxPerMM converts millimetres to pixels at the label resolution
+const pxPerMM = dpi / 25.4
+
+// glyph is the size of the bitmap font the label text is scaled from
+var glyph = struct{ width, height, ascent int }{7, 13, 11}
+
+// renderImage writes one label as a PNG or JPEG sized for an A6 thermal label
+func renderImage(w io.Writer, r *model.Receipt, opts Options) error {
+	img := image.NewRGBA(image.Rect(0, 0, px(labelWidth), px(labelHeight)))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	if err := drawLabel(&imageCanvas{img: img}, r, opts.IncludeBarcode); err != nil {
+		return fmt.Errorf("failed to draw label %s: %w", r.TrackingNumber, err)
+	}
+
+	if opts.Format == FormatJPG {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: 90})
+	}
+	return png.Encode(w, img)
+}
+
+// px converts millimetres to whole pixels
+func px(mm float64) int {
+	return int(math.Round(mm * pxPerMM))
+}
+
+// imageCanvas draws a label onto a raster image
+type imageCanvas struct {
+	img *image.RGBA
+}
+
+// scale is the whole-number factor the bitmap font is enlarged by to come
+// closest to a font size in points
+func (c *imageCanvas) scale(size float64) int {
+	s := int(math.Round(size * ptToMM * pxPerMM / float64(glyph.height)))
+	if s < 1 {
+		return 1
+	}
+	return s
+}
+
+func (c *imageCanvas) text(x, y, size float64, bold bool, s string) {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return
+	}
+
+	// Render at the font's own size, then enlarge it without smoothing so
+	// the text stays crisp on a thermal printer
+	mask := image.NewAlpha(image.Rect(0, 0, len(runes)*glyph.width, glyph.height))
+	d := font.Drawer{
+		Dst:  mask,
+		Src:  image.Opaque,
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(0, glyph.ascent),
+	}
+	d.DrawString(s)
+
+	scale := c.scale(size)
+	scaled := image.NewAlpha(image.Rect(0, 0, mask.Bounds().Dx()*scale, mask.Bounds().Dy()*scale))
+	draw.NearestNeighbor.Scale(scaled, scaled.Bounds(), mask, mask.Bounds(), draw.Src, nil)
+
+	at := image.Pt(px(x), px(y))
+	draw.DrawMask(c.img, scaled.Bounds().Add(at), image.Black, image.Point{}, scaled, image.Point{}, draw.Over)
+	if bold {
+		at.X += scale
+		draw.DrawMask(c.img, scaled.Bounds().Add(at), image.Black, image.Point{}, scaled, image.Point{}, draw.Over)
+	}
+}
+
+func (c *imageCanvas) textWidth(size float64, bold bool, s string) float64 {
+	width := len([]rune(s)) * glyph.width * c.scale(size)
+	if bold {
+		width += c.scale(size)
+	}
+	return float64(width) / pxPerMM
+}
+
+func (c *imageCanvas) line(x1, y1, x2, y2 float64) {
+	const thickness = 2
+	r := image.Rect(px(x1), px(y1), px(x2), px(y2)).Canon()
+	if r.Dx() < thickness {
+		r.Max.X = r.Min.X + thickness
+	}
+	if r.Dy() < thickness {
+		r.Max.Y = r.Min.Y + thickness
+	}
+	draw.Draw(c.img, r, image.Black, image.Point{}, draw.Src)
+}
+
+func (c *imageCanvas) rect(x, y, w, h float64) {
+	c.line(x, y, x+w, y)
+	c.line(x, y+h, x+w, y+h)
+	c.line(x, y, x, y+h)
+	c.line(x+w, y, x+w, y+h)
+}
+
+func (c *imageCanvas) barcode(x, y, w, h float64, modules []bool) {
+	if len(modules) == 0 {
+		return
+	}
+	// Bars must be whole pixels wide for scanners to read them, so the
+	// barcode is narrowed to a whole number of pixels per module and centred
+	module := px(w) / len(modules)
+	if module < 1 {
+		module = 1
+	}
+	left := px(x) + (px(w)-module*len(modules))/2
+	top, bottom := px(y), px(y+h)
+	for i, bar := range modules {
+		if bar {
+			r := image.Rect(left+i*module, top, left+(i+1)*module, bottom)
+			draw.Draw(c.img, r, image.Black, image.Point{}, draw.Src)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Package label renders printable shipping labels from courier receipts. A
+// label is A6, the size of thermal label rolls; labels are printed one per
+// page or four to an A4 sheet, as PDF, or one at a time as PNG or JPEG.
+package label
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/boombuler/barcode/code128"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+)
+
+// Label size in millimetres
+const (
+	labelWidth  = 105.0
+	labelHeight = 148.0
+)
+
+// Format is the file format labels are rendered to
+type Format string
+
+const (
+	FormatPDF Format = "pdf"
+	FormatPNG Format = "png"
+	FormatJPG Format = "jpg"
+)
+
+// ParseFormat parses a format name, defaulting to PDF
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "pdf":
+		return FormatPDF, nil
+	case "png":
+		return FormatPNG, nil
+	case "jpg", "jpeg":
+		return FormatJPG, nil
+	}
+	return "", fmt.Errorf("unsupported label format %q", s)
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatPNG:
+		return "image/png"
+	case FormatJPG:
+		return "image/jpeg"
+	default:
+		return "application/pdf"
+	}
+}
+
+// Extension returns the file extension of the format
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+// Layout is how labels are laid out on the printed page
+type Layout string
+
+const (
+	// LayoutA6 prints each label on its own A6 page, for thermal printers
+	LayoutA6 Layout = "a6"
+	// LayoutA4 prints four labels to an A4 sheet, for office printers
+	LayoutA4 Layout = "a4"
+)
+
+// ParseLayout parses a layout name, defaulting to A6
+func ParseLayout(s string) (Layout, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "a6":
+		return LayoutA6, nil
+	case "a4":
+		return LayoutA4, nil
+	}
+	return "", fmt.Errorf("unsupported label layout %q", s)
+}
+
+// Options control how labels are rendered
+type Options struct {
+	Format Format
+	Layout Layout
+	// IncludeBarcode prints the AWB as a Code128 barcode above its number
+	IncludeBarcode bool
+}
+
+// ErrNoLabels is returned when there is nothing to render
+var ErrNoLabels = errors.New("no labels to render")
+
+// ErrImageSheet is returned when an image is asked to hold more than one
+// label; sheets are only rendered as PDF
+var ErrImageSheet = errors.New("image labels hold a single A6 label; use pdf for sheets")
+
+// Render writes the receipts' labels to w
+func Render(w io.Writer, receipts []model.Receipt, opts Options) error {
+	if len(receipts) == 0 {
+		return ErrNoLabels
+	}
+
+	switch opts.Format {
+	case FormatPDF, "":
+		return renderPDF(w, receipts, opts)
+	case FormatPNG, FormatJPG:
+		if len(receipts) > 1 || opts.Layout == LayoutA4 {
+			return ErrImageSheet
+		}
+		return renderImage(w, &receipts[0], opts)
+	}
+	return fmt.Errorf("unsupported label format %q", opts.Format)
+}
+
+// barcodeModules encodes the tracking number as Code128 and returns its
+// modules, true for a bar
+func barcodeModules(trackingNumber string) ([]bool, error) {
+	code, err := code128.Encode(trackingNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode barcode: %w", err)
+	}
+
+	bounds := code.Bounds()
+	modules := make([]bool, bounds.Dx())
+	for i := range modules {
+		gray := color.GrayModel.Convert(code.At(bounds.Min.X+i, bounds.Min.Y)).(color.Gray)
+		modules[i] = gray.Y < 128
+	}
+	return modules, nil
+}
@@ -0,0 +1,143 @@
+package label
+
+import (
+	"bytes"
+	"errors"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+)
+
+func testReceipt(trackingNumber string) model.Receipt {
+	return model.Receipt{
+		ReceiptNumber:    "SO-20261016-0001",
+		TrackingNumber:   trackingNumber,
+		CourierID:        "sicepat",
+		ServiceType:      "REG",
+		SenderName:       "Toko Bali",
+		SenderPhone:      "081234567890",
+		SenderAddress:    "Jl. Gatot Subroto 1, Denpasar, Bali 80111",
+		RecipientName:    "Made Wirawan",
+		RecipientPhone:   "081298765432",
+		RecipientAddress: "Br. Dinas Abiansemal, Jl. Raya Abiansemal No. 12, Kec. Abiansemal, Kab. Badung, Bali 80352",
+		Weight:           1.5,
+		GeneratedAt:      time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC),
+		Items: []model.Item{
+			{Name: "Kopi bubuk Kintamani 250g", SKU: "KOPI-250", Quantity: 2},
+			{Name: "Gula aren", Quantity: 1},
+		},
+		Notes: "Pickup 17 Oct 09:00-12:00",
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	cases := map[string]Format{"": FormatPDF, "PDF": FormatPDF, "png": FormatPNG, "jpeg": FormatJPG, "jpg": FormatJPG}
+	for in, want := range cases {
+		got, err := ParseFormat(in)
+		if err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("gif"); err == nil {
+		t.Error("expected an error for gif")
+	}
+	if FormatJPG.ContentType() != "image/jpeg" || FormatPDF.Extension() != ".pdf" {
+		t.Error("unexpected content type or extension")
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	if l, err := ParseLayout(""); err != nil || l != LayoutA6 {
+		t.Errorf("ParseLayout(\"\") = %q, %v; want a6", l, err)
+	}
+	if l, err := ParseLayout("A4"); err != nil || l != LayoutA4 {
+		t.Errorf("ParseLayout(\"A4\") = %q, %v; want a4", l, err)
+	}
+	if _, err := ParseLayout("letter"); err == nil {
+		t.Error("expected an error for letter")
+	}
+}
+
+func TestRenderPDF(t *testing.T) {
+	var a6 bytes.Buffer
+	err := Render(&a6, []model.Receipt{testReceipt("000888000001")}, Options{Format: FormatPDF, Layout: LayoutA6, IncludeBarcode: true})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if !bytes.HasPrefix(a6.Bytes(), []byte("%PDF")) {
+		t.Fatal("output is not a PDF")
+	}
+	if got := strings.Count(a6.String(), "/Type /Page\n"); got != 1 {
+		t.Errorf("A6 labels: got %d pages, want 1", got)
+	}
+
+	receipts := make([]model.Receipt, 5)
+	for i := range receipts {
+		receipts[i] = testReceipt("00088800000" + string(rune('1'+i)))
+	}
+	var a4 bytes.Buffer
+	if err := Render(&a4, receipts, Options{Format: FormatPDF, Layout: LayoutA4, IncludeBarcode: true}); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if got := strings.Count(a4.String(), "/Type /Page\n"); got != 2 {
+		t.Errorf("A4 sheets: got %d pages, want 2 for 5 labels", got)
+	}
+}
+
+func TestRenderImage(t *testing.T) {
+	receipt := testReceipt("000888000001")
+
+	var buf bytes.Buffer
+	if err := Render(&buf, []model.Receipt{receipt}, Options{Format: FormatPNG, IncludeBarcode: true}); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("output is not a PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != px(labelWidth) || b.Dy() != px(labelHeight) {
+		t.Errorf("got %dx%d image, want A6 at %d dpi", b.Dx(), b.Dy(), dpi)
+	}
+
+	buf.Reset()
+	if err := Render(&buf, []model.Receipt{receipt}, Options{Format: FormatJPG}); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if _, err := jpeg.Decode(&buf); err != nil {
+		t.Errorf("output is not a JPEG: %v", err)
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, nil, Options{}); !errors.Is(err, ErrNoLabels) {
+		t.Errorf("got %v, want ErrNoLabels", err)
+	}
+
+	two := []model.Receipt{testReceipt("000888000001"), testReceipt("000888000002")}
+	if err := Render(&buf, two, Options{Format: FormatPNG}); !errors.Is(err, ErrImageSheet) {
+		t.Errorf("got %v, want ErrImageSheet for several images", err)
+	}
+	one := two[:1]
+	if err := Render(&buf, one, Options{Format: FormatPNG, Layout: LayoutA4}); !errors.Is(err, ErrImageSheet) {
+		t.Errorf("got %v, want ErrImageSheet for an A4 image", err)
+	}
+}
+
+func TestBarcodeModules(t *testing.T) {
+	modules, err := barcodeModules("000888000001")
+	if err != nil {
+		t.Fatalf("barcodeModules: %v", err)
+	}
+	// Code128 starts with a bar and ends with the two-module bar of its stop pattern
+	if len(modules) == 0 || !modules[0] || !modules[len(modules)-1] || !modules[len(modules)-2] {
+		t.Errorf("unexpected modules %v", modules)
+	}
+	if (len(modules)-13)%11 != 0 {
+		t.Errorf("got %d modules, want 11 per symbol plus the 13 module stop", len(modules))
+	}
+}
@@ -0,0 +1,122 @@
+package label
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+)
+
+// ptToMM converts a font size in points to millimetres
+const ptToMM = 25.4 / 72
+
+// sheetOffsets are the top-left corners of the labels on an A4 sheet
+var sheetOffsets = [][2]float64{
+	{0, 0},
+	{labelWidth, 0},
+	{0, labelHeight},
+	{labelWidth, labelHeight},
+}
+
+// renderPDF writes the labels as a PDF, one per A6 page or four per A4 sheet
+func renderPDF(w io.Writer, receipts []model.Receipt, opts Options) error {
+	pageSize := "A6"
+	if opts.Layout == LayoutA4 {
+		pageSize = "A4"
+	}
+
+	pdf := gofpdf.New("P", "mm", pageSize, "")
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetTitle("Shipping labels", true)
+	c := &pdfCanvas{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+
+	for i := range receipts {
+		if opts.Layout == LayoutA4 {
+			slot := i % len(sheetOffsets)
+			if slot == 0 {
+				pdf.AddPage()
+				cutLines(pdf)
+			}
+			c.ox, c.oy = sheetOffsets[slot][0], sheetOffsets[slot][1]
+		} else {
+			pdf.AddPage()
+		}
+
+		if err := drawLabel(c, &receipts[i], opts.IncludeBarcode); err != nil {
+			return fmt.Errorf("failed to draw label %s: %w", receipts[i].TrackingNumber, err)
+		}
+	}
+
+	if err := pdf.Error(); err != nil {
+		return fmt.Errorf("failed to render labels: %w", err)
+	}
+	return pdf.Output(w)
+}
+
+// cutLines marks where an A4 sheet is cut into labels
+func cutLines(pdf *gofpdf.Fpdf) {
+	pdf.SetLineWidth(0.1)
+	pdf.SetDashPattern([]float64{2, 2}, 0)
+	pdf.Line(labelWidth, 0, labelWidth, 2*labelHeight)
+	pdf.Line(0, labelHeight, 2*labelWidth, labelHeight)
+	pdf.SetDashPattern([]float64{}, 0)
+}
+
+// pdfCanvas draws a label onto a PDF page at an offset
+type pdfCanvas struct {
+	pdf    *gofpdf.Fpdf
+	tr     func(string) string
+	ox, oy float64
+}
+
+func (c *pdfCanvas) font(size float64, bold bool) {
+	style := ""
+	if bold {
+		style = "B"
+	}
+	c.pdf.SetFont("Helvetica", style, size)
+}
+
+func (c *pdfCanvas) text(x, y, size float64, bold bool, s string) {
+	c.font(size, bold)
+	// PDF places text on its baseline, about 80% of the font size down
+	c.pdf.Text(c.ox+x, c.oy+y+size*ptToMM*0.8, c.tr(s))
+}
+
+func (c *pdfCanvas) textWidth(size float64, bold bool, s string) float64 {
+	c.font(size, bold)
+	return c.pdf.GetStringWidth(c.tr(s))
+}
+
+func (c *pdfCanvas) line(x1, y1, x2, y2 float64) {
+	c.pdf.SetLineWidth(0.3)
+	c.pdf.Line(c.ox+x1, c.oy+y1, c.ox+x2, c.oy+y2)
+}
+
+func (c *pdfCanvas) rect(x, y, w, h float64) {
+	c.pdf.SetLineWidth(0.3)
+	c.pdf.Rect(c.ox+x, c.oy+y, w, h, "D")
+}
+
+func (c *pdfCanvas) barcode(x, y, w, h float64, modules []bool) {
+	if len(modules) == 0 {
+		return
+	}
+	module := w / float64(len(modules))
+	c.pdf.SetFillColor(0, 0, 0)
+	// Each run of bars is drawn as one rectangle
+	for i := 0; i < len(modules); {
+		if !modules[i] {
+			i++
+			continue
+		}
+		start := i
+		for i < len(modules) && modules[i] {
+			i++
+		}
+		c.pdf.Rect(c.ox+x+float64(start)*module, c.oy+y, float64(i-start)*module, h, "F")
+	}
+}
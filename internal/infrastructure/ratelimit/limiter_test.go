@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newTestLimiter returns a limiter on a memory store whose clock the test moves
+func newTestLimiter(start time.Time) (*Limiter, *time.Time) {
+	now := start
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limiter := NewLimiter(store)
+	limiter.now = func() time.Time { return now }
+	return limiter, &now
+}
+
+func TestLimiterRejectsOverLimit(t *testing.T) {
+	limiter, _ := newTestLimiter(time.Unix(1_700_000_000, 0))
+	policy := Policy{Limit: 3, WindowSeconds: 60}
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		decision, err := limiter.Allow(ctx, "login", policy, "203.0.113.7")
+		if err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		if !decision.Allowed || decision.Remaining != 3-i {
+			t.Fatalf("request %d: %+v", i, decision)
+		}
+	}
+
+	decision, _ := limiter.Allow(ctx, "login", policy, "203.0.113.7")
+	if decision.Allowed {
+		t.Error("fourth request allowed")
+	}
+
+	// Other clients and policies have their own counters
+	if decision, _ := limiter.Allow(ctx, "login", policy, "198.51.100.1"); !decision.Allowed {
+		t.Error("another client was limited")
+	}
+	if decision, _ := limiter.Allow(ctx, "register", policy, "203.0.113.7"); !decision.Allowed {
+		t.Error("another policy was limited")
+	}
+}
+
+func TestLimiterSlidesPreviousWindow(t *testing.T) {
+	limiter, now := newTestLimiter(time.Unix(1_700_000_070, 0)) // 30s into a window
+	policy := Policy{Limit: 4, WindowSeconds: 60}
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		limiter.Allow(ctx, "api", policy, "client")
+	}
+
+	// A quarter into the next window, three quarters of the previous count
+	// still apply: 3 + 1 = 4 is allowed, the next request is not
+	*now = time.Unix(1_700_000_115, 0)
+	if decision, _ := limiter.Allow(ctx, "api", policy, "client"); !decision.Allowed {
+		t.Fatalf("request within the sliding limit rejected: %+v", decision)
+	}
+	if decision, _ := limiter.Allow(ctx, "api", policy, "client"); decision.Allowed {
+		t.Fatalf("request over the sliding limit allowed: %+v", decision)
+	}
+
+	// Two windows later the old requests no longer count
+	*now = now.Add(2 * time.Minute)
+	if decision, _ := limiter.Allow(ctx, "api", policy, "client"); !decision.Allowed || decision.Remaining != 3 {
+		t.Fatalf("limit did not recover: %+v", decision)
+	}
+}
+
+func TestPolicySetForAndMerge(t *testing.T) {
+	defaults := PolicySet{
+		Default: Policy{Limit: 60, WindowSeconds: 60},
+		Routes:  map[string]Policy{"POST /auth/login": {Limit: 10, WindowSeconds: 60}},
+	}
+	storefront := PolicySet{
+		Routes: map[string]Policy{"POST /auth/register": {Limit: 2, WindowSeconds: 60}},
+	}
+	merged := defaults.Merge(storefront)
+
+	if name, policy := merged.For("POST", "/auth/login"); name != "POST /auth/login" || policy.Limit != 10 {
+		t.Errorf("login policy = %s %+v", name, policy)
+	}
+	if _, policy := merged.For("POST", "/auth/register"); policy.Limit != 2 {
+		t.Errorf("register policy = %+v", policy)
+	}
+	if name, policy := merged.For("GET", "/orders"); name != "default" || policy.Limit != 60 {
+		t.Errorf("default policy = %s %+v", name, policy)
+	}
+	if len(defaults.Routes) != 1 {
+		t.Error("Merge modified the receiver")
+	}
+}
+
+func TestMemoryStoreBlocks(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	store.Block(ctx, &BlockedIP{IP: "203.0.113.7", BlockedAt: now, ExpiresAt: now.Add(time.Minute)})
+	store.Block(ctx, &BlockedIP{IP: "198.51.100.1", BlockedAt: now, ExpiresAt: now.Add(-time.Second)})
+
+	if block, _ := store.GetBlock(ctx, "203.0.113.7"); block == nil {
+		t.Fatal("active block not found")
+	}
+	if block, _ := store.GetBlock(ctx, "198.51.100.1"); block != nil {
+		t.Error("expired block returned")
+	}
+	if blocks, _ := store.ListBlocks(ctx); len(blocks) != 1 {
+		t.Errorf("expected 1 active block, got %d", len(blocks))
+	}
+
+	if ok, _ := store.Unblock(ctx, "203.0.113.7"); !ok {
+		t.Error("Unblock did not find the block")
+	}
+	if block, _ := store.GetBlock(ctx, "203.0.113.7"); block != nil {
+		t.Error("block survived Unblock")
+	}
+}
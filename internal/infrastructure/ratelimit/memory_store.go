@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval is how often expired entries are dropped
+const memorySweepInterval = time.Minute
+
+// MemoryStore keeps counters and blocks in process memory. It suits a single
+// replica and tests; deployments with several replicas use RedisStore.
+type MemoryStore struct {
+	mu        sync.Mutex
+	counters  map[string]memoryCounter
+	blocks    map[string]*BlockedIP
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type memoryCounter struct {
+	count     int64
+	expiresAt time.Time
+}
+
+// NewMemoryStore creates an in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		counters:  make(map[string]memoryCounter),
+		blocks:    make(map[string]*BlockedIP),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Increment adds one to a counter and returns the new count
+func (s *MemoryStore) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	counter, ok := s.counters[key]
+	if !ok || !now.Before(counter.expiresAt) {
+		counter = memoryCounter{expiresAt: now.Add(ttl)}
+	}
+	counter.count++
+	s.counters[key] = counter
+
+	return counter.count, nil
+}
+
+// Count returns a counter's value, or 0 when it does not exist
+func (s *MemoryStore) Count(ctx context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter, ok := s.counters[key]
+	if !ok || !s.now().Before(counter.expiresAt) {
+		return 0, nil
+	}
+	return counter.count, nil
+}
+
+// Delete removes a counter
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.counters, key)
+	return nil
+}
+
+// Block records a blocked IP until the block expires
+func (s *MemoryStore) Block(ctx context.Context, block *BlockedIP) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *block
+	s.blocks[block.IP] = &stored
+	return nil
+}
+
+// GetBlock returns the active block of an IP, or nil when it is not blocked
+func (s *MemoryStore) GetBlock(ctx context.Context, ip string) (*BlockedIP, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	block, ok := s.blocks[ip]
+	if !ok || !s.now().Before(block.ExpiresAt) {
+		return nil, nil
+	}
+	found := *block
+	return &found, nil
+}
+
+// ListBlocks returns the active blocks, most recent first
+func (s *MemoryStore) ListBlocks(ctx context.Context) ([]*BlockedIP, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	blocks := make([]*BlockedIP, 0, len(s.blocks))
+	for _, block := range s.blocks {
+		if now.Before(block.ExpiresAt) {
+			found := *block
+			blocks = append(blocks, &found)
+		}
+	}
+	sortBlocks(blocks)
+	return blocks, nil
+}
+
+// Unblock lifts the block of an IP and reports whether there was one
+func (s *MemoryStore) Unblock(ctx context.Context, ip string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	block, ok := s.blocks[ip]
+	delete(s.blocks, ip)
+	return ok && s.now().Before(block.ExpiresAt), nil
+}
+
+// sweep drops expired entries. The caller holds the lock.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, counter := range s.counters {
+		if !now.Before(counter.expiresAt) {
+			delete(s.counters, key)
+		}
+	}
+	for ip, block := range s.blocks {
+		if !now.Before(block.ExpiresAt) {
+			delete(s.blocks, ip)
+		}
+	}
+}
+
+// sortBlocks orders blocks most recent first
+func sortBlocks(blocks []*BlockedIP) {
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].BlockedAt.After(blocks[j].BlockedAt)
+	})
+}
@@ -0,0 +1,155 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kirimku/smartseller-backend/pkg/redis"
+)
+
+const (
+	// redisCounterPrefix namespaces rate-limit counters in Redis
+	redisCounterPrefix = "smartseller:ratelimit:"
+
+	// redisBlockPrefix namespaces blocked IPs in Redis
+	redisBlockPrefix = "smartseller:blocked-ip:"
+
+	// redisScanCount is the page size used when listing blocks
+	redisScanCount = 100
+)
+
+// RedisStore keeps counters and blocks in Redis, shared by every replica
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a Redis-backed store
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Increment adds one to a counter and returns the new count. The expiry is
+// set by the request that creates the counter.
+func (s *RedisStore) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	key = redisCounterPrefix + key
+
+	count, err := s.client.Incr(ctx, key)
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment rate limit counter: %w", err)
+	}
+	if count == 1 {
+		if _, err := s.client.PExpire(ctx, key, ttl); err != nil {
+			return 0, fmt.Errorf("failed to expire rate limit counter: %w", err)
+		}
+	}
+
+	return count, nil
+}
+
+// Count returns a counter's value, or 0 when it does not exist
+func (s *RedisStore) Count(ctx context.Context, key string) (int64, error) {
+	value, err := s.client.Get(ctx, redisCounterPrefix+key)
+	if errors.Is(err, redis.ErrNil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read rate limit counter: %w", err)
+	}
+
+	count, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate limit counter: %w", err)
+	}
+	return count, nil
+}
+
+// Delete removes a counter
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	if _, err := s.client.Del(ctx, redisCounterPrefix+key); err != nil {
+		return fmt.Errorf("failed to delete rate limit counter: %w", err)
+	}
+	return nil
+}
+
+// Block records a blocked IP until the block expires
+func (s *RedisStore) Block(ctx context.Context, block *BlockedIP) error {
+	ttl := block.TTL(time.Now())
+	if ttl <= 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(block)
+	if err != nil {
+		return fmt.Errorf("failed to encode blocked IP: %w", err)
+	}
+	if err := s.client.Set(ctx, redisBlockPrefix+block.IP, data, ttl); err != nil {
+		return fmt.Errorf("failed to store blocked IP: %w", err)
+	}
+	return nil
+}
+
+// GetBlock returns the active block of an IP, or nil when it is not blocked
+func (s *RedisStore) GetBlock(ctx context.Context, ip string) (*BlockedIP, error) {
+	return s.getBlock(ctx, redisBlockPrefix+ip)
+}
+
+// ListBlocks returns the active blocks, most recent first
+func (s *RedisStore) ListBlocks(ctx context.Context) ([]*BlockedIP, error) {
+	var blocks []*BlockedIP
+
+	cursor := uint64(0)
+	for {
+		keys, next, err := s.client.Scan(ctx, cursor, redisBlockPrefix+"*", redisScanCount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list blocked IPs: %w", err)
+		}
+
+		for _, key := range keys {
+			block, err := s.getBlock(ctx, key)
+			if err != nil {
+				return nil, err
+			}
+			// The block may have expired since the scan
+			if block != nil {
+				blocks = append(blocks, block)
+			}
+		}
+
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+
+	sortBlocks(blocks)
+	return blocks, nil
+}
+
+// Unblock lifts the block of an IP and reports whether there was one
+func (s *RedisStore) Unblock(ctx context.Context, ip string) (bool, error) {
+	deleted, err := s.client.Del(ctx, redisBlockPrefix+ip)
+	if err != nil {
+		return false, fmt.Errorf("failed to unblock IP: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+func (s *RedisStore) getBlock(ctx context.Context, key string) (*BlockedIP, error) {
+	data, err := s.client.Get(ctx, key)
+	if errors.Is(err, redis.ErrNil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blocked IP: %w", err)
+	}
+
+	var block BlockedIP
+	if err := json.Unmarshal(data, &block); err != nil {
+		return nil, fmt.Errorf("failed to decode blocked IP: %w", err)
+	}
+	return &block, nil
+}
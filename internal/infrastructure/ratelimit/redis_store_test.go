@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kirimku/smartseller-backend/pkg/redis"
+	"github.com/kirimku/smartseller-backend/pkg/redis/redistest"
+)
+
+func newTestRedisStore(t *testing.T) *RedisStore {
+	t.Helper()
+
+	server, err := redistest.NewServer()
+	if err != nil {
+		t.Fatalf("Failed to start Redis server: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	opts, err := redis.ParseURL(server.URL())
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %v", err)
+	}
+	client := redis.NewClient(opts)
+	t.Cleanup(func() { client.Close() })
+
+	return NewRedisStore(client)
+}
+
+func TestRedisStoreSharesCountersAcrossLimiters(t *testing.T) {
+	store := newTestRedisStore(t)
+	ctx := context.Background()
+	policy := Policy{Limit: 2, WindowSeconds: 60}
+
+	// Two replicas with their own limiters share one store
+	first, second := NewLimiter(store), NewLimiter(store)
+	first.Allow(ctx, "login", policy, "203.0.113.7")
+	second.Allow(ctx, "login", policy, "203.0.113.7")
+
+	decision, err := first.Allow(ctx, "login", policy, "203.0.113.7")
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if decision.Allowed {
+		t.Error("limit was applied per replica instead of across replicas")
+	}
+}
+
+func TestRedisStoreCounterExpires(t *testing.T) {
+	store := newTestRedisStore(t)
+	ctx := context.Background()
+
+	if count, _ := store.Increment(ctx, "failed:203.0.113.7", 20*time.Millisecond); count != 1 {
+		t.Fatalf("Increment = %d, want 1", count)
+	}
+	store.Increment(ctx, "failed:203.0.113.7", 20*time.Millisecond)
+	if count, _ := store.Count(ctx, "failed:203.0.113.7"); count != 2 {
+		t.Fatalf("Count = %d, want 2", count)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if count, _ := store.Count(ctx, "failed:203.0.113.7"); count != 0 {
+		t.Errorf("Count after expiry = %d, want 0", count)
+	}
+}
+
+func TestRedisStoreBlocks(t *testing.T) {
+	store := newTestRedisStore(t)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	for _, ip := range []string{"203.0.113.7", "198.51.100.1"} {
+		err := store.Block(ctx, &BlockedIP{IP: ip, Reason: "failed attempts", FailedAttempts: 5, BlockedAt: now, ExpiresAt: now.Add(time.Hour)})
+		if err != nil {
+			t.Fatalf("Block: %v", err)
+		}
+	}
+
+	block, err := store.GetBlock(ctx, "203.0.113.7")
+	if err != nil || block == nil || block.FailedAttempts != 5 || !block.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("GetBlock = %+v, %v", block, err)
+	}
+
+	blocks, err := store.ListBlocks(ctx)
+	if err != nil || len(blocks) != 2 {
+		t.Fatalf("ListBlocks = %d blocks, %v", len(blocks), err)
+	}
+
+	if ok, _ := store.Unblock(ctx, "203.0.113.7"); !ok {
+		t.Error("Unblock did not find the block")
+	}
+	if ok, _ := store.Unblock(ctx, "203.0.113.7"); ok {
+		t.Error("Unblock found a lifted block")
+	}
+	if block, _ := store.GetBlock(ctx, "203.0.113.7"); block != nil {
+		t.Error("block survived Unblock")
+	}
+}
@@ -0,0 +1,44 @@
+// Package ratelimit keeps request counters and blocked IPs in a store shared
+// by every API replica, so limits hold across the deployment instead of per
+// process.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Store holds rate-limit counters and IP blocks. Entries expire on their
+// own, so the store never grows beyond the clients seen in the last window.
+type Store interface {
+	// Increment adds one to a counter and returns the new count. A new
+	// counter expires after ttl.
+	Increment(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// Count returns a counter's value, or 0 when it does not exist
+	Count(ctx context.Context, key string) (int64, error)
+	// Delete removes a counter
+	Delete(ctx context.Context, key string) error
+
+	// Block records a blocked IP until the block expires
+	Block(ctx context.Context, block *BlockedIP) error
+	// GetBlock returns the active block of an IP, or nil when it is not blocked
+	GetBlock(ctx context.Context, ip string) (*BlockedIP, error)
+	// ListBlocks returns the active blocks
+	ListBlocks(ctx context.Context) ([]*BlockedIP, error)
+	// Unblock lifts the block of an IP and reports whether there was one
+	Unblock(ctx context.Context, ip string) (bool, error)
+}
+
+// BlockedIP is an IP refused for suspicious activity
+type BlockedIP struct {
+	IP             string    `json:"ip"`
+	Reason         string    `json:"reason"`
+	FailedAttempts int       `json:"failed_attempts"`
+	BlockedAt      time.Time `json:"blocked_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// TTL returns how long the block has left
+func (b *BlockedIP) TTL(now time.Time) time.Duration {
+	return b.ExpiresAt.Sub(now)
+}
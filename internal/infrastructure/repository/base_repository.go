@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/kirimku/smartseller-backend/internal/domain/errors"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/database"
 	"github.com/kirimku/smartseller-backend/internal/infrastructure/tenant"
 )
 
@@ -88,7 +89,7 @@ func (qb *queryBuilder) From(table string) QueryBuilder {
 	// Add schema prefix for schema-based tenancy
 	switch qb.tenantCtx.TenantType {
 	case tenant.TenantTypeSchema:
-		qb.fromTable = fmt.Sprintf("tenant_%s.%s", qb.tenantCtx.StorefrontID.String(), table)
+		qb.fromTable = tenant.SchemaName(qb.tenantCtx.StorefrontID) + "." + table
 	default:
 		qb.fromTable = table
 	}
@@ -123,7 +124,7 @@ func (qb *queryBuilder) Join(joinType, table, condition string) QueryBuilder {
 	// Add schema prefix for joins too
 	switch qb.tenantCtx.TenantType {
 	case tenant.TenantTypeSchema:
-		table = fmt.Sprintf("tenant_%s.%s", qb.tenantCtx.StorefrontID.String(), table)
+		table = tenant.SchemaName(qb.tenantCtx.StorefrontID) + "." + table
 	}
 
 	qb.joins = append(qb.joins, joinClause{
@@ -385,6 +386,15 @@ func (br *BaseRepository) GetDB(ctx context.Context, storefrontID uuid.UUID) (*s
 	return sqlx.NewDb(db, "postgres"), nil
 }
 
+// GetWriteDB returns the tenant's database connection for writes, which are
+// refused while the storefront's data is being migrated
+func (br *BaseRepository) GetWriteDB(ctx context.Context, storefrontID uuid.UUID) (*sqlx.DB, error) {
+	if err := br.tenantResolver.CheckWritable(ctx, storefrontID); err != nil {
+		return nil, err
+	}
+	return br.GetDB(ctx, storefrontID)
+}
+
 // NewQueryBuilder creates a new query builder with tenant context
 func (br *BaseRepository) NewQueryBuilder(ctx context.Context, storefrontID uuid.UUID) (QueryBuilder, error) {
 	// Get storefront to create tenant context
@@ -428,7 +438,7 @@ func (br *BaseRepository) GetTenantContext(ctx context.Context, storefrontID uui
 
 // ExecuteInTransaction executes a function within a database transaction
 func (br *BaseRepository) ExecuteInTransaction(ctx context.Context, storefrontID uuid.UUID, fn func(*sqlx.Tx) error) error {
-	db, err := br.GetDB(ctx, storefrontID)
+	db, err := br.GetWriteDB(ctx, storefrontID)
 	if err != nil {
 		return err
 	}
@@ -453,6 +463,23 @@ func (br *BaseRepository) ExecuteInTransaction(ctx context.Context, storefrontID
 	return err
 }
 
+// dbExecutor is implemented by both *sqlx.DB and *sqlx.Tx
+type dbExecutor interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+}
+
+// executorFromContext returns the transaction started by database.WithTransaction
+// when the context carries one, otherwise the given connection
+func executorFromContext(ctx context.Context, db *sqlx.DB) dbExecutor {
+	if tx, ok := database.TxFromContext(ctx); ok {
+		return tx
+	}
+	return db
+}
+
 // ValidateStorefrontAccess checks if the storefront exists and is accessible
 func (br *BaseRepository) ValidateStorefrontAccess(ctx context.Context, storefrontID uuid.UUID) error {
 	// This could be cached for performance
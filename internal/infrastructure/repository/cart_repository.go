@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/rs/zerolog"
+)
+
+const cartSelectColumns = `id, storefront_id, customer_id, session_id, created_at, updated_at`
+
+// CartRepositoryImpl implements the CartRepository interface.
+// Every method joins the transaction carried by the context, if any.
+type CartRepositoryImpl struct {
+	db     *sqlx.DB
+	logger zerolog.Logger
+}
+
+// NewCartRepository creates a new cart repository
+func NewCartRepository(db *sqlx.DB, logger zerolog.Logger) repository.CartRepository {
+	return &CartRepositoryImpl{
+		db:     db,
+		logger: logger.With().Str("repository", "cart").Logger(),
+	}
+}
+
+// Create creates a new empty cart
+func (r *CartRepositoryImpl) Create(ctx context.Context, cart *entity.Cart) error {
+	if err := cart.Validate(); err != nil {
+		return fmt.Errorf("invalid cart: %w", err)
+	}
+
+	query := `
+		INSERT INTO carts (id, storefront_id, customer_id, session_id, created_at, updated_at)
+		VALUES (:id, :storefront_id, :customer_id, :session_id, :created_at, :updated_at)`
+
+	if _, err := executorFromContext(ctx, r.db).NamedExecContext(ctx, query, cart); err != nil {
+		r.logger.Error().Err(err).Str("storefront_id", cart.StorefrontID.String()).Msg("Failed to create cart")
+		return fmt.Errorf("failed to create cart: %w", err)
+	}
+
+	return nil
+}
+
+// GetByCustomerID retrieves a customer's cart on a storefront, including its items
+func (r *CartRepositoryImpl) GetByCustomerID(ctx context.Context, storefrontID, customerID uuid.UUID) (*entity.Cart, error) {
+	query := `SELECT ` + cartSelectColumns + ` FROM carts WHERE storefront_id = $1 AND customer_id = $2`
+	return r.getOne(ctx, query, storefrontID, customerID)
+}
+
+// GetBySessionID retrieves an anonymous session's cart on a storefront, including its items
+func (r *CartRepositoryImpl) GetBySessionID(ctx context.Context, storefrontID uuid.UUID, sessionID string) (*entity.Cart, error) {
+	query := `SELECT ` + cartSelectColumns + ` FROM carts WHERE storefront_id = $1 AND session_id = $2 AND customer_id IS NULL`
+	return r.getOne(ctx, query, storefrontID, sessionID)
+}
+
+// getOne loads a single cart and its items
+func (r *CartRepositoryImpl) getOne(ctx context.Context, query string, args ...interface{}) (*entity.Cart, error) {
+	exec := executorFromContext(ctx, r.db)
+
+	var cart entity.Cart
+	if err := exec.GetContext(ctx, &cart, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Msg("Failed to get cart")
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+
+	itemsQuery := `
+		SELECT id, cart_id, product_id, product_variant_id, quantity, unit_price, created_at, updated_at
+		FROM cart_items
+		WHERE cart_id = $1
+		ORDER BY created_at`
+
+	if err := exec.SelectContext(ctx, &cart.Items, itemsQuery, cart.ID); err != nil {
+		r.logger.Error().Err(err).Str("cart_id", cart.ID.String()).Msg("Failed to get cart items")
+		return nil, fmt.Errorf("failed to get cart items: %w", err)
+	}
+
+	return &cart, nil
+}
+
+// SaveItem inserts a cart item or updates its quantity and price
+func (r *CartRepositoryImpl) SaveItem(ctx context.Context, item *entity.CartItem) error {
+	query := `
+		INSERT INTO cart_items (
+			id, cart_id, product_id, product_variant_id, quantity, unit_price, created_at, updated_at
+		) VALUES (
+			:id, :cart_id, :product_id, :product_variant_id, :quantity, :unit_price, :created_at, :updated_at
+		)
+		ON CONFLICT (id) DO UPDATE SET
+			quantity = EXCLUDED.quantity,
+			unit_price = EXCLUDED.unit_price,
+			updated_at = EXCLUDED.updated_at`
+
+	if _, err := executorFromContext(ctx, r.db).NamedExecContext(ctx, query, item); err != nil {
+		r.logger.Error().Err(err).Str("cart_id", item.CartID.String()).Msg("Failed to save cart item")
+		return fmt.Errorf("failed to save cart item: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteItem removes an item from a cart
+func (r *CartRepositoryImpl) DeleteItem(ctx context.Context, cartID, itemID uuid.UUID) error {
+	query := `DELETE FROM cart_items WHERE id = $1 AND cart_id = $2`
+
+	result, err := executorFromContext(ctx, r.db).ExecContext(ctx, query, itemID, cartID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("item_id", itemID.String()).Msg("Failed to delete cart item")
+		return fmt.Errorf("failed to delete cart item: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("cart item not found")
+	}
+
+	return nil
+}
+
+// ClearItems removes all items from a cart
+func (r *CartRepositoryImpl) ClearItems(ctx context.Context, cartID uuid.UUID) error {
+	query := `DELETE FROM cart_items WHERE cart_id = $1`
+
+	if _, err := executorFromContext(ctx, r.db).ExecContext(ctx, query, cartID); err != nil {
+		r.logger.Error().Err(err).Str("cart_id", cartID.String()).Msg("Failed to clear cart")
+		return fmt.Errorf("failed to clear cart: %w", err)
+	}
+
+	return nil
+}
+
+// Touch updates the last activity time of a cart
+func (r *CartRepositoryImpl) Touch(ctx context.Context, cartID uuid.UUID) error {
+	query := `UPDATE carts SET updated_at = $1 WHERE id = $2`
+
+	if _, err := executorFromContext(ctx, r.db).ExecContext(ctx, query, time.Now(), cartID); err != nil {
+		return fmt.Errorf("failed to update cart: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a cart and its items
+func (r *CartRepositoryImpl) Delete(ctx context.Context, cartID uuid.UUID) error {
+	query := `DELETE FROM carts WHERE id = $1`
+
+	if _, err := executorFromContext(ctx, r.db).ExecContext(ctx, query, cartID); err != nil {
+		r.logger.Error().Err(err).Str("cart_id", cartID.String()).Msg("Failed to delete cart")
+		return fmt.Errorf("failed to delete cart: %w", err)
+	}
+
+	return nil
+}
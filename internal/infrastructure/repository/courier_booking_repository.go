@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/rs/zerolog"
+)
+
+// courierBookingColumns lists the columns selected for a courier booking
+const courierBookingColumns = `
+	id, order_id, courier_code, service_type, booking_code, tracking_number, weight, status,
+	pickup_start, pickup_end, booked_by, created_at, updated_at`
+
+// CourierBookingRepositoryImpl implements the CourierBookingRepository interface.
+// Every method joins the transaction carried by the context, if any.
+type CourierBookingRepositoryImpl struct {
+	db     *sqlx.DB
+	logger zerolog.Logger
+}
+
+// NewCourierBookingRepository creates a new courier booking repository
+func NewCourierBookingRepository(db *sqlx.DB, logger zerolog.Logger) repository.CourierBookingRepository {
+	return &CourierBookingRepositoryImpl{
+		db:     db,
+		logger: logger.With().Str("repository", "courier_booking").Logger(),
+	}
+}
+
+// Create creates a courier booking
+func (r *CourierBookingRepositoryImpl) Create(ctx context.Context, booking *entity.CourierBooking) error {
+	if err := booking.Validate(); err != nil {
+		return fmt.Errorf("invalid courier booking: %w", err)
+	}
+
+	query := `
+		INSERT INTO courier_bookings (
+			id, order_id, courier_code, service_type, booking_code, tracking_number, weight, status,
+			pickup_start, pickup_end, booked_by, created_at, updated_at
+		) VALUES (
+			:id, :order_id, :courier_code, :service_type, :booking_code, :tracking_number, :weight, :status,
+			:pickup_start, :pickup_end, :booked_by, :created_at, :updated_at
+		)`
+
+	if _, err := executorFromContext(ctx, r.db).NamedExecContext(ctx, query, booking); err != nil {
+		r.logger.Error().Err(err).Str("order_id", booking.OrderID.String()).Msg("Failed to create courier booking")
+		return fmt.Errorf("failed to create courier booking: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveByOrderID retrieves the active booking of an order
+func (r *CourierBookingRepositoryImpl) GetActiveByOrderID(ctx context.Context, orderID uuid.UUID) (*entity.CourierBooking, error) {
+	query := `SELECT` + courierBookingColumns + ` FROM courier_bookings WHERE order_id = $1 AND status = $2`
+
+	var booking entity.CourierBooking
+	if err := executorFromContext(ctx, r.db).GetContext(ctx, &booking, query, orderID, entity.CourierBookingStatusBooked); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("Failed to get courier booking")
+		return nil, fmt.Errorf("failed to get courier booking by order_id: %w", err)
+	}
+
+	return &booking, nil
+}
+
+// ListActiveByStorefront lists the active bookings of a storefront's orders
+// made in [from, to), oldest first
+func (r *CourierBookingRepositoryImpl) ListActiveByStorefront(ctx context.Context, storefrontID uuid.UUID, from, to time.Time) ([]*entity.CourierBooking, error) {
+	query := `
+		SELECT` + courierBookingColumns + `
+		FROM courier_bookings
+		WHERE order_id IN (SELECT id FROM orders WHERE storefront_id = $1)
+			AND status = $2 AND created_at >= $3 AND created_at < $4
+		ORDER BY created_at`
+
+	var bookings []*entity.CourierBooking
+	if err := executorFromContext(ctx, r.db).SelectContext(ctx, &bookings, query, storefrontID, entity.CourierBookingStatusBooked, from, to); err != nil {
+		r.logger.Error().Err(err).Str("storefront_id", storefrontID.String()).Msg("Failed to list courier bookings")
+		return nil, fmt.Errorf("failed to list courier bookings: %w", err)
+	}
+
+	return bookings, nil
+}
+
+// NextReceiptSequence advances a courier's receipt sequence and returns the
+// new value, starting at 1. Concurrent callers never get the same value.
+func (r *CourierBookingRepositoryImpl) NextReceiptSequence(ctx context.Context, courierCode string) (int64, error) {
+	query := `
+		INSERT INTO courier_receipt_sequences (courier_code, last_value)
+		VALUES ($1, 1)
+		ON CONFLICT (courier_code) DO UPDATE
+		SET last_value = courier_receipt_sequences.last_value + 1, updated_at = CURRENT_TIMESTAMP
+		RETURNING last_value`
+
+	var value int64
+	if err := executorFromContext(ctx, r.db).GetContext(ctx, &value, query, courierCode); err != nil {
+		r.logger.Error().Err(err).Str("courier_code", courierCode).Msg("Failed to advance receipt sequence")
+		return 0, fmt.Errorf("failed to advance receipt sequence: %w", err)
+	}
+
+	return value, nil
+}
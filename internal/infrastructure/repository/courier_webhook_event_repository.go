@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/rs/zerolog"
+)
+
+// courierWebhookEventColumns lists the columns selected for a courier webhook event
+const courierWebhookEventColumns = `
+	id, courier_code, payload, headers, signature, signature_valid, remote_ip, status,
+	error_message, updates_count, attempts, last_attempt_at, next_retry_at, processed_at,
+	received_at, created_at, updated_at`
+
+// CourierWebhookEventRepositoryImpl implements the CourierWebhookEventRepository interface
+type CourierWebhookEventRepositoryImpl struct {
+	db     *sqlx.DB
+	logger zerolog.Logger
+}
+
+// NewCourierWebhookEventRepository creates a new courier webhook event repository
+func NewCourierWebhookEventRepository(db *sqlx.DB, logger zerolog.Logger) repository.CourierWebhookEventRepository {
+	return &CourierWebhookEventRepositoryImpl{
+		db:     db,
+		logger: logger.With().Str("repository", "courier_webhook_event").Logger(),
+	}
+}
+
+// Create stores a raw courier webhook delivery
+func (r *CourierWebhookEventRepositoryImpl) Create(ctx context.Context, event *entity.CourierWebhookEvent) error {
+	if err := event.Validate(); err != nil {
+		return fmt.Errorf("invalid courier webhook event: %w", err)
+	}
+
+	query := `
+		INSERT INTO courier_webhook_events (
+			id, courier_code, payload, headers, signature, signature_valid, remote_ip, status,
+			error_message, updates_count, attempts, last_attempt_at, next_retry_at, processed_at,
+			received_at, created_at, updated_at
+		) VALUES (
+			:id, :courier_code, :payload, :headers, :signature, :signature_valid, :remote_ip, :status,
+			:error_message, :updates_count, :attempts, :last_attempt_at, :next_retry_at, :processed_at,
+			:received_at, :created_at, :updated_at
+		)`
+
+	if _, err := executorFromContext(ctx, r.db).NamedExecContext(ctx, query, event); err != nil {
+		r.logger.Error().Err(err).Str("courier_code", event.CourierCode).Msg("Failed to create courier webhook event")
+		return fmt.Errorf("failed to create courier webhook event: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a courier webhook event by its ID
+func (r *CourierWebhookEventRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity.CourierWebhookEvent, error) {
+	query := `SELECT` + courierWebhookEventColumns + ` FROM courier_webhook_events WHERE id = $1`
+
+	var event entity.CourierWebhookEvent
+	if err := executorFromContext(ctx, r.db).GetContext(ctx, &event, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Str("id", id.String()).Msg("Failed to get courier webhook event")
+		return nil, fmt.Errorf("failed to get courier webhook event: %w", err)
+	}
+
+	return &event, nil
+}
+
+// Update updates the processing outcome of an event
+func (r *CourierWebhookEventRepositoryImpl) Update(ctx context.Context, event *entity.CourierWebhookEvent) error {
+	query := `
+		UPDATE courier_webhook_events
+		SET status = $1, signature_valid = $2, error_message = $3, updates_count = $4, attempts = $5,
+			last_attempt_at = $6, next_retry_at = $7, processed_at = $8, updated_at = $9
+		WHERE id = $10`
+
+	result, err := executorFromContext(ctx, r.db).ExecContext(ctx, query,
+		event.Status, event.SignatureValid, event.ErrorMessage, event.UpdatesCount, event.Attempts,
+		event.LastAttemptAt, event.NextRetryAt, event.ProcessedAt, event.UpdatedAt, event.ID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", event.ID.String()).Msg("Failed to update courier webhook event")
+		return fmt.Errorf("failed to update courier webhook event: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("courier webhook event not found")
+	}
+
+	return nil
+}
+
+// GetWithFilters retrieves events with filters and pagination, newest first
+func (r *CourierWebhookEventRepositoryImpl) GetWithFilters(ctx context.Context, filters *repository.CourierWebhookEventFilters) ([]*entity.CourierWebhookEvent, error) {
+	whereClause, args := buildCourierWebhookEventWhere(filters)
+	query := `SELECT` + courierWebhookEventColumns + ` FROM courier_webhook_events` + whereClause +
+		` ORDER BY received_at DESC`
+
+	if filters.PageSize > 0 {
+		args = append(args, filters.PageSize)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+		if filters.Page > 1 {
+			args = append(args, (filters.Page-1)*filters.PageSize)
+			query += fmt.Sprintf(" OFFSET $%d", len(args))
+		}
+	}
+
+	var events []*entity.CourierWebhookEvent
+	if err := executorFromContext(ctx, r.db).SelectContext(ctx, &events, query, args...); err != nil {
+		r.logger.Error().Err(err).Msg("Failed to get courier webhook events with filters")
+		return nil, fmt.Errorf("failed to get courier webhook events with filters: %w", err)
+	}
+
+	return events, nil
+}
+
+// Count counts events with optional filters
+func (r *CourierWebhookEventRepositoryImpl) Count(ctx context.Context, filters *repository.CourierWebhookEventFilters) (int, error) {
+	whereClause, args := buildCourierWebhookEventWhere(filters)
+	query := `SELECT COUNT(*) FROM courier_webhook_events` + whereClause
+
+	var count int
+	if err := executorFromContext(ctx, r.db).GetContext(ctx, &count, query, args...); err != nil {
+		r.logger.Error().Err(err).Msg("Failed to count courier webhook events")
+		return 0, fmt.Errorf("failed to count courier webhook events: %w", err)
+	}
+
+	return count, nil
+}
+
+// ClaimDueForRetry picks up to limit failed events whose retry is due and
+// pushes their next retry back by lease so that concurrent workers skip them
+func (r *CourierWebhookEventRepositoryImpl) ClaimDueForRetry(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.CourierWebhookEvent, error) {
+	query := `
+		UPDATE courier_webhook_events
+		SET next_retry_at = $2, updated_at = $1
+		WHERE id IN (
+			SELECT id FROM courier_webhook_events
+			WHERE status = $3 AND next_retry_at <= $1
+			ORDER BY next_retry_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING` + courierWebhookEventColumns
+
+	var events []*entity.CourierWebhookEvent
+	if err := executorFromContext(ctx, r.db).SelectContext(ctx, &events, query,
+		now, now.Add(lease), entity.CourierWebhookEventStatusFailed, limit); err != nil {
+		r.logger.Error().Err(err).Msg("Failed to claim courier webhook events for retry")
+		return nil, fmt.Errorf("failed to claim courier webhook events for retry: %w", err)
+	}
+
+	return events, nil
+}
+
+// buildCourierWebhookEventWhere builds the WHERE clause for event filters
+func buildCourierWebhookEventWhere(filters *repository.CourierWebhookEventFilters) (string, []interface{}) {
+	var whereConditions []string
+	var args []interface{}
+
+	if filters.CourierCode != nil {
+		args = append(args, *filters.CourierCode)
+		whereConditions = append(whereConditions, fmt.Sprintf("courier_code = $%d", len(args)))
+	}
+	if filters.Status != nil {
+		args = append(args, *filters.Status)
+		whereConditions = append(whereConditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filters.TrackingNumber != nil {
+		// Payloads are stored verbatim, so a tracking number is matched as text
+		args = append(args, "%"+*filters.TrackingNumber+"%")
+		whereConditions = append(whereConditions, fmt.Sprintf("payload ILIKE $%d", len(args)))
+	}
+	if filters.ReceivedFrom != nil {
+		args = append(args, *filters.ReceivedFrom)
+		whereConditions = append(whereConditions, fmt.Sprintf("received_at >= $%d", len(args)))
+	}
+	if filters.ReceivedTo != nil {
+		args = append(args, *filters.ReceivedTo)
+		whereConditions = append(whereConditions, fmt.Sprintf("received_at <= $%d", len(args)))
+	}
+
+	if len(whereConditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(whereConditions, " AND "), args
+}
@@ -116,7 +116,7 @@ func (r *PostgreSQLCustomerRepository) Create(ctx context.Context, customer *ent
 		`
 
 		// Get tenant-specific database
-		db, err := r.GetDB(ctx, customer.StorefrontID)
+		db, err := r.GetWriteDB(ctx, customer.StorefrontID)
 		if err != nil {
 			return err
 		}
@@ -310,7 +310,7 @@ func (r *PostgreSQLCustomerRepository) Update(ctx context.Context, customer *ent
 			WHERE id = :id AND storefront_id = :storefront_id AND deleted_at IS NULL
 		`
 
-		db, err := r.GetDB(ctx, customer.StorefrontID)
+		db, err := r.GetWriteDB(ctx, customer.StorefrontID)
 		if err != nil {
 			return err
 		}
@@ -364,7 +364,7 @@ func (r *PostgreSQLCustomerRepository) SoftDelete(ctx context.Context, storefron
 		updateQuery := strings.Replace(query, "SELECT * FROM", "UPDATE", 1)
 		updateQuery = strings.Replace(updateQuery, " WHERE", " SET deleted_at = NOW() WHERE", 1)
 
-		db, err := r.GetDB(ctx, storefrontID)
+		db, err := r.GetWriteDB(ctx, storefrontID)
 		if err != nil {
 			return err
 		}
@@ -405,7 +405,7 @@ func (r *PostgreSQLCustomerRepository) HardDelete(ctx context.Context, storefron
 		// Rebuild as DELETE
 		deleteQuery := strings.Replace(query, "SELECT * FROM", "DELETE FROM", 1)
 
-		db, err := r.GetDB(ctx, storefrontID)
+		db, err := r.GetWriteDB(ctx, storefrontID)
 		if err != nil {
 			return err
 		}
@@ -730,16 +730,30 @@ func (r *PostgreSQLCustomerRepository) GetCustomerStats(ctx context.Context, sto
 }
 
 // Authentication methods
-func (r *PostgreSQLCustomerRepository) GetByEmailVerificationToken(ctx context.Context, token string) (*entity.Customer, error) {
+func (r *PostgreSQLCustomerRepository) GetByEmailVerificationToken(ctx context.Context, storefrontID uuid.UUID, token string) (*entity.Customer, error) {
 	var customer entity.Customer
 
 	return &customer, WithMetrics(r.metricsCollector, "GET_BY_EMAIL_TOKEN", "customers", func() error {
-		query := `
-			SELECT * FROM customers 
-			WHERE email_verification_token = $1 AND deleted_at IS NULL
-		`
+		tenantCtx, err := r.GetTenantContext(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
+
+		qb := NewQueryBuilder(tenantCtx)
+		query, args := qb.
+			Select("*").
+			From("customers").
+			TenantWhere(storefrontID).
+			Where("email_verification_token = $1", token).
+			Where("deleted_at IS NULL").
+			Build()
+
+		db, err := r.GetDB(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
 
-		err := r.db.GetContext(ctx, &customer, query, token)
+		err = db.GetContext(ctx, &customer, query, args...)
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errors.ErrInvalidEmailToken
@@ -751,18 +765,31 @@ func (r *PostgreSQLCustomerRepository) GetByEmailVerificationToken(ctx context.C
 	})
 }
 
-func (r *PostgreSQLCustomerRepository) GetByPasswordResetToken(ctx context.Context, token string) (*entity.Customer, error) {
+func (r *PostgreSQLCustomerRepository) GetByPasswordResetToken(ctx context.Context, storefrontID uuid.UUID, token string) (*entity.Customer, error) {
 	var customer entity.Customer
 
 	return &customer, WithMetrics(r.metricsCollector, "GET_BY_PASSWORD_TOKEN", "customers", func() error {
-		query := `
-			SELECT * FROM customers 
-			WHERE password_reset_token = $1 
-			AND password_reset_expires_at > NOW() 
-			AND deleted_at IS NULL
-		`
+		tenantCtx, err := r.GetTenantContext(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
+
+		qb := NewQueryBuilder(tenantCtx)
+		query, args := qb.
+			Select("*").
+			From("customers").
+			TenantWhere(storefrontID).
+			Where("password_reset_token = $1", token).
+			Where("password_reset_expires_at > NOW()").
+			Where("deleted_at IS NULL").
+			Build()
 
-		err := r.db.GetContext(ctx, &customer, query, token)
+		db, err := r.GetDB(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
+
+		err = db.GetContext(ctx, &customer, query, args...)
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errors.ErrInvalidPasswordToken
@@ -844,7 +871,7 @@ func (r *PostgreSQLCustomerRepository) UpdateEmailVerification(ctx context.Conte
 			SET email_verified_at = $1, email_verification_token = NULL, updated_at = $2
 			WHERE id = $3 AND storefront_id = $4 AND deleted_at IS NULL`
 
-		db, err := r.GetDB(ctx, storefrontID)
+		db, err := r.GetWriteDB(ctx, storefrontID)
 		if err != nil {
 			return err
 		}
@@ -894,7 +921,7 @@ func (r *PostgreSQLCustomerRepository) UpdatePassword(ctx context.Context, store
 			SET password_hash = $1, updated_at = $2
 			WHERE id = $3 AND storefront_id = $4 AND deleted_at IS NULL`
 
-		db, err := r.GetDB(ctx, storefrontID)
+		db, err := r.GetWriteDB(ctx, storefrontID)
 		if err != nil {
 			return err
 		}
@@ -924,7 +951,7 @@ func (r *PostgreSQLCustomerRepository) SetPasswordResetToken(ctx context.Context
 			SET password_reset_token = $1, password_reset_expires_at = $2, updated_at = $3
 			WHERE id = $4 AND storefront_id = $5 AND deleted_at IS NULL`
 
-		db, err := r.GetDB(ctx, storefrontID)
+		db, err := r.GetWriteDB(ctx, storefrontID)
 		if err != nil {
 			return err
 		}
@@ -954,7 +981,7 @@ func (r *PostgreSQLCustomerRepository) ClearPasswordResetToken(ctx context.Conte
 			SET password_reset_token = NULL, password_reset_expires_at = NULL, updated_at = $1
 			WHERE id = $2 AND storefront_id = $3 AND deleted_at IS NULL`
 
-		db, err := r.GetDB(ctx, storefrontID)
+		db, err := r.GetWriteDB(ctx, storefrontID)
 		if err != nil {
 			return err
 		}
@@ -984,7 +1011,7 @@ func (r *PostgreSQLCustomerRepository) SetEmailVerificationToken(ctx context.Con
 			SET email_verification_token = $1, updated_at = $2
 			WHERE id = $3 AND storefront_id = $4 AND deleted_at IS NULL`
 
-		db, err := r.GetDB(ctx, storefrontID)
+		db, err := r.GetWriteDB(ctx, storefrontID)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/tenant"
+)
+
+// customerSessionColumns lists the columns selected for a customer session
+const customerSessionColumns = `
+	id, customer_id, storefront_id, session_token, refresh_token, user_agent, ip_address,
+	expires_at, created_at, last_used_at, revoked_at, revoked_reason, two_factor_required,
+	two_factor_verified_at`
+
+// PostgreSQLCustomerSessionRepository implements the CustomerSessionRepository interface using PostgreSQL
+type PostgreSQLCustomerSessionRepository struct {
+	*BaseRepository
+	metricsCollector MetricsCollector
+}
+
+// NewPostgreSQLCustomerSessionRepository creates a new PostgreSQL customer session repository
+func NewPostgreSQLCustomerSessionRepository(
+	db *sqlx.DB,
+	tenantResolver tenant.TenantResolver,
+	metricsCollector MetricsCollector,
+) repository.CustomerSessionRepository {
+	if metricsCollector == nil {
+		metricsCollector = &NoOpMetricsCollector{}
+	}
+
+	return &PostgreSQLCustomerSessionRepository{
+		BaseRepository:   NewBaseRepository(db, tenantResolver),
+		metricsCollector: metricsCollector,
+	}
+}
+
+// Create stores a new customer session
+func (r *PostgreSQLCustomerSessionRepository) Create(ctx context.Context, session *repository.CustomerSession) error {
+	return WithMetrics(r.metricsCollector, "CREATE", "customer_sessions", func() error {
+		if session.ID == uuid.Nil {
+			session.ID = uuid.New()
+		}
+
+		now := time.Now()
+		session.CreatedAt = now
+		session.LastUsedAt = now
+
+		db, err := r.GetDB(ctx, session.StorefrontID)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO customer_sessions (
+				id, customer_id, storefront_id, session_token, refresh_token, user_agent, ip_address,
+				expires_at, created_at, last_used_at, two_factor_required
+			) VALUES (
+				:id, :customer_id, :storefront_id, :session_token, :refresh_token, :user_agent, :ip_address,
+				:expires_at, :created_at, :last_used_at, :two_factor_required
+			)`
+
+		if _, err := db.NamedExecContext(ctx, query, session); err != nil {
+			return fmt.Errorf("failed to create customer session: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetByID retrieves a session of the storefront, revoked or not
+func (r *PostgreSQLCustomerSessionRepository) GetByID(ctx context.Context, storefrontID, sessionID uuid.UUID) (*repository.CustomerSession, error) {
+	var session *repository.CustomerSession
+
+	err := WithMetrics(r.metricsCollector, "GET_BY_ID", "customer_sessions", func() error {
+		db, err := r.GetDB(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
+
+		var found repository.CustomerSession
+		query := `SELECT` + customerSessionColumns + ` FROM customer_sessions WHERE id = $1 AND storefront_id = $2`
+		if err := db.GetContext(ctx, &found, query, sessionID, storefrontID); err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			return fmt.Errorf("failed to get customer session: %w", err)
+		}
+
+		session = &found
+		return nil
+	})
+
+	return session, err
+}
+
+// RevokeSession revokes a single session. Revoking a revoked session keeps
+// the original reason.
+func (r *PostgreSQLCustomerSessionRepository) RevokeSession(ctx context.Context, storefrontID, sessionID uuid.UUID, reason string) error {
+	return WithMetrics(r.metricsCollector, "REVOKE", "customer_sessions", func() error {
+		db, err := r.GetDB(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE customer_sessions
+			SET revoked_at = $1, revoked_reason = $2
+			WHERE id = $3 AND storefront_id = $4 AND revoked_at IS NULL`
+
+		if _, err := db.ExecContext(ctx, query, time.Now(), reason, sessionID, storefrontID); err != nil {
+			return fmt.Errorf("failed to revoke customer session: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// RevokeAllSessions revokes every open session of a customer
+func (r *PostgreSQLCustomerSessionRepository) RevokeAllSessions(ctx context.Context, storefrontID, customerID uuid.UUID, reason string) (int, error) {
+	var revoked int
+
+	err := WithMetrics(r.metricsCollector, "REVOKE_ALL", "customer_sessions", func() error {
+		db, err := r.GetDB(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE customer_sessions
+			SET revoked_at = $1, revoked_reason = $2
+			WHERE storefront_id = $3 AND customer_id = $4 AND revoked_at IS NULL`
+
+		revoked, err = execRowsAffected(ctx, db, query, time.Now(), reason, storefrontID, customerID)
+		if err != nil {
+			return fmt.Errorf("failed to revoke customer sessions: %w", err)
+		}
+
+		return nil
+	})
+
+	return revoked, err
+}
+
+// RevokeStorefrontSessions revokes every open session of a storefront
+func (r *PostgreSQLCustomerSessionRepository) RevokeStorefrontSessions(ctx context.Context, storefrontID uuid.UUID, reason string) (int, error) {
+	var revoked int
+
+	err := WithMetrics(r.metricsCollector, "REVOKE_STOREFRONT", "customer_sessions", func() error {
+		db, err := r.GetDB(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE customer_sessions
+			SET revoked_at = $1, revoked_reason = $2
+			WHERE storefront_id = $3 AND revoked_at IS NULL`
+
+		revoked, err = execRowsAffected(ctx, db, query, time.Now(), reason, storefrontID)
+		if err != nil {
+			return fmt.Errorf("failed to revoke storefront sessions: %w", err)
+		}
+
+		return nil
+	})
+
+	return revoked, err
+}
+
+// RefreshSession rotates the token digests of an open session. The update
+// only applies while the session still holds refreshToken, so two concurrent
+// refreshes with the same token cannot both succeed.
+func (r *PostgreSQLCustomerSessionRepository) RefreshSession(
+	ctx context.Context,
+	storefrontID, sessionID uuid.UUID,
+	refreshToken, newSessionToken, newRefreshToken string,
+	expiresAt time.Time,
+) (bool, error) {
+	var rotated bool
+
+	err := WithMetrics(r.metricsCollector, "REFRESH", "customer_sessions", func() error {
+		db, err := r.GetDB(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE customer_sessions
+			SET session_token = $1, refresh_token = $2, expires_at = $3, last_used_at = $4
+			WHERE id = $5 AND storefront_id = $6 AND refresh_token = $7 AND revoked_at IS NULL`
+
+		affected, err := execRowsAffected(ctx, db, query,
+			newSessionToken, newRefreshToken, expiresAt, time.Now(), sessionID, storefrontID, refreshToken)
+		if err != nil {
+			return fmt.Errorf("failed to refresh customer session: %w", err)
+		}
+
+		rotated = affected > 0
+		return nil
+	})
+
+	return rotated, err
+}
+
+// UpdateLastUsed records activity on a session
+func (r *PostgreSQLCustomerSessionRepository) UpdateLastUsed(ctx context.Context, storefrontID, sessionID uuid.UUID) error {
+	return WithMetrics(r.metricsCollector, "UPDATE_LAST_USED", "customer_sessions", func() error {
+		db, err := r.GetDB(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
+
+		query := `UPDATE customer_sessions SET last_used_at = $1 WHERE id = $2 AND storefront_id = $3`
+		if _, err := db.ExecContext(ctx, query, time.Now(), sessionID, storefrontID); err != nil {
+			return fmt.Errorf("failed to update customer session last used: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// MarkTwoFactorVerified records that the session passed a second factor
+func (r *PostgreSQLCustomerSessionRepository) MarkTwoFactorVerified(ctx context.Context, storefrontID, sessionID uuid.UUID, newSessionToken, newRefreshToken string) error {
+	return WithMetrics(r.metricsCollector, "MARK_TWO_FACTOR_VERIFIED", "customer_sessions", func() error {
+		db, err := r.GetDB(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		query := `
+			UPDATE customer_sessions
+			SET session_token = $1, refresh_token = $2, two_factor_verified_at = $3, last_used_at = $3
+			WHERE id = $4 AND storefront_id = $5 AND revoked_at IS NULL`
+
+		affected, err := execRowsAffected(ctx, db, query, newSessionToken, newRefreshToken, now, sessionID, storefrontID)
+		if err != nil {
+			return fmt.Errorf("failed to mark customer session two-factor verified: %w", err)
+		}
+		if affected == 0 {
+			return fmt.Errorf("customer session not found")
+		}
+
+		return nil
+	})
+}
+
+// SetTwoFactorRequired flags the customer's open sessions as needing a second factor
+func (r *PostgreSQLCustomerSessionRepository) SetTwoFactorRequired(ctx context.Context, storefrontID, customerID uuid.UUID, required bool) error {
+	return WithMetrics(r.metricsCollector, "SET_TWO_FACTOR_REQUIRED", "customer_sessions", func() error {
+		db, err := r.GetDB(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE customer_sessions
+			SET two_factor_required = $1
+			WHERE storefront_id = $2 AND customer_id = $3 AND revoked_at IS NULL`
+
+		if _, err := db.ExecContext(ctx, query, required, storefrontID, customerID); err != nil {
+			return fmt.Errorf("failed to update customer session two-factor requirement: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetActiveSessions returns the customer's open sessions, most recently used first
+func (r *PostgreSQLCustomerSessionRepository) GetActiveSessions(ctx context.Context, storefrontID, customerID uuid.UUID) ([]*repository.CustomerSession, error) {
+	var sessions []*repository.CustomerSession
+
+	err := WithMetrics(r.metricsCollector, "GET_ACTIVE", "customer_sessions", func() error {
+		db, err := r.GetDB(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
+
+		query := `SELECT` + customerSessionColumns + ` FROM customer_sessions
+			WHERE storefront_id = $1 AND customer_id = $2 AND revoked_at IS NULL AND expires_at > $3
+			ORDER BY last_used_at DESC`
+
+		if err := db.SelectContext(ctx, &sessions, query, storefrontID, customerID, time.Now()); err != nil {
+			return fmt.Errorf("failed to get active customer sessions: %w", err)
+		}
+
+		return nil
+	})
+
+	return sessions, err
+}
+
+// execRowsAffected runs a statement and returns the number of rows it touched
+func execRowsAffected(ctx context.Context, db *sqlx.DB, query string, args ...interface{}) (int, error) {
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return int(affected), nil
+}
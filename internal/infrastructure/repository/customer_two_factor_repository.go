@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/tenant"
+)
+
+// customerTwoFactorColumns lists the columns selected for a customer TOTP enrollment
+const customerTwoFactorColumns = `
+	customer_id, storefront_id, secret, recovery_code_hashes, last_used_step, failed_attempts,
+	locked_until, enabled_at, created_at, updated_at`
+
+// PostgreSQLCustomerTwoFactorRepository implements the CustomerTwoFactorRepository interface using PostgreSQL
+type PostgreSQLCustomerTwoFactorRepository struct {
+	*BaseRepository
+	metricsCollector MetricsCollector
+}
+
+// NewPostgreSQLCustomerTwoFactorRepository creates a new PostgreSQL customer two-factor repository
+func NewPostgreSQLCustomerTwoFactorRepository(
+	db *sqlx.DB,
+	tenantResolver tenant.TenantResolver,
+	metricsCollector MetricsCollector,
+) repository.CustomerTwoFactorRepository {
+	if metricsCollector == nil {
+		metricsCollector = &NoOpMetricsCollector{}
+	}
+
+	return &PostgreSQLCustomerTwoFactorRepository{
+		BaseRepository:   NewBaseRepository(db, tenantResolver),
+		metricsCollector: metricsCollector,
+	}
+}
+
+// GetByCustomerID returns the customer's enrollment, or nil when there is none
+func (r *PostgreSQLCustomerTwoFactorRepository) GetByCustomerID(ctx context.Context, storefrontID, customerID uuid.UUID) (*entity.CustomerTwoFactor, error) {
+	var twoFactor *entity.CustomerTwoFactor
+
+	err := WithMetrics(r.metricsCollector, "GET_BY_CUSTOMER_ID", "customer_two_factor", func() error {
+		db, err := r.GetDB(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
+
+		var found entity.CustomerTwoFactor
+		query := `SELECT` + customerTwoFactorColumns + ` FROM customer_two_factor WHERE customer_id = $1 AND storefront_id = $2`
+		if err := db.GetContext(ctx, &found, query, customerID, storefrontID); err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			return fmt.Errorf("failed to get customer two-factor: %w", err)
+		}
+
+		twoFactor = &found
+		return nil
+	})
+
+	return twoFactor, err
+}
+
+// Save creates the enrollment or replaces a pending one. A confirmed
+// enrollment is never overwritten.
+func (r *PostgreSQLCustomerTwoFactorRepository) Save(ctx context.Context, twoFactor *entity.CustomerTwoFactor) error {
+	return WithMetrics(r.metricsCollector, "SAVE", "customer_two_factor", func() error {
+		db, err := r.GetDB(ctx, twoFactor.StorefrontID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		twoFactor.CreatedAt = now
+		twoFactor.UpdatedAt = now
+
+		query := `
+			INSERT INTO customer_two_factor (
+				customer_id, storefront_id, secret, recovery_code_hashes, last_used_step, failed_attempts,
+				locked_until, enabled_at, created_at, updated_at
+			) VALUES (
+				:customer_id, :storefront_id, :secret, :recovery_code_hashes, :last_used_step, :failed_attempts,
+				:locked_until, :enabled_at, :created_at, :updated_at
+			)
+			ON CONFLICT (customer_id) DO UPDATE SET
+				secret = EXCLUDED.secret,
+				recovery_code_hashes = EXCLUDED.recovery_code_hashes,
+				last_used_step = EXCLUDED.last_used_step,
+				failed_attempts = EXCLUDED.failed_attempts,
+				locked_until = EXCLUDED.locked_until,
+				enabled_at = EXCLUDED.enabled_at,
+				created_at = EXCLUDED.created_at,
+				updated_at = EXCLUDED.updated_at
+			WHERE customer_two_factor.enabled_at IS NULL`
+
+		affected, err := namedExecRowsAffected(ctx, db, query, twoFactor)
+		if err != nil {
+			return fmt.Errorf("failed to save customer two-factor: %w", err)
+		}
+		if affected == 0 {
+			return fmt.Errorf("two-factor authentication is already enabled")
+		}
+
+		return nil
+	})
+}
+
+// Update stores the enrollment state, attempts and recovery codes
+func (r *PostgreSQLCustomerTwoFactorRepository) Update(ctx context.Context, twoFactor *entity.CustomerTwoFactor) error {
+	return WithMetrics(r.metricsCollector, "UPDATE", "customer_two_factor", func() error {
+		db, err := r.GetDB(ctx, twoFactor.StorefrontID)
+		if err != nil {
+			return err
+		}
+
+		twoFactor.UpdatedAt = time.Now()
+		query := `
+			UPDATE customer_two_factor
+			SET recovery_code_hashes = $1, failed_attempts = $2, locked_until = $3, enabled_at = $4, updated_at = $5
+			WHERE customer_id = $6 AND storefront_id = $7`
+
+		affected, err := execRowsAffected(ctx, db, query,
+			twoFactor.RecoveryCodeHashes, twoFactor.FailedAttempts, twoFactor.LockedUntil, twoFactor.EnabledAt,
+			twoFactor.UpdatedAt, twoFactor.CustomerID, twoFactor.StorefrontID)
+		if err != nil {
+			return fmt.Errorf("failed to update customer two-factor: %w", err)
+		}
+		if affected == 0 {
+			return fmt.Errorf("customer two-factor not found")
+		}
+
+		return nil
+	})
+}
+
+// UpdateAttempts stores the failed attempts and lockout of a verification.
+// Recovery codes are left alone so a concurrent code use is not undone.
+func (r *PostgreSQLCustomerTwoFactorRepository) UpdateAttempts(ctx context.Context, twoFactor *entity.CustomerTwoFactor) error {
+	return WithMetrics(r.metricsCollector, "UPDATE_ATTEMPTS", "customer_two_factor", func() error {
+		db, err := r.GetDB(ctx, twoFactor.StorefrontID)
+		if err != nil {
+			return err
+		}
+
+		twoFactor.UpdatedAt = time.Now()
+		query := `
+			UPDATE customer_two_factor
+			SET failed_attempts = $1, locked_until = $2, updated_at = $3
+			WHERE customer_id = $4 AND storefront_id = $5`
+
+		affected, err := execRowsAffected(ctx, db, query,
+			twoFactor.FailedAttempts, twoFactor.LockedUntil, twoFactor.UpdatedAt, twoFactor.CustomerID, twoFactor.StorefrontID)
+		if err != nil {
+			return fmt.Errorf("failed to update customer two-factor attempts: %w", err)
+		}
+		if affected == 0 {
+			return fmt.Errorf("customer two-factor not found")
+		}
+
+		return nil
+	})
+}
+
+// UseStep records the time step of an accepted code, refusing steps that are
+// not newer than the last one used
+func (r *PostgreSQLCustomerTwoFactorRepository) UseStep(ctx context.Context, storefrontID, customerID uuid.UUID, step int64) (bool, error) {
+	var accepted bool
+
+	err := WithMetrics(r.metricsCollector, "USE_STEP", "customer_two_factor", func() error {
+		db, err := r.GetDB(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE customer_two_factor
+			SET last_used_step = $1
+			WHERE customer_id = $2 AND storefront_id = $3 AND last_used_step < $1`
+
+		affected, err := execRowsAffected(ctx, db, query, step, customerID, storefrontID)
+		if err != nil {
+			return fmt.Errorf("failed to record customer two-factor step: %w", err)
+		}
+
+		accepted = affected > 0
+		return nil
+	})
+
+	return accepted, err
+}
+
+// UseRecoveryCode removes a recovery code digest only while it is still
+// stored, so each code is accepted once even under concurrent requests
+func (r *PostgreSQLCustomerTwoFactorRepository) UseRecoveryCode(ctx context.Context, storefrontID, customerID uuid.UUID, codeHash string) (bool, error) {
+	var accepted bool
+
+	err := WithMetrics(r.metricsCollector, "USE_RECOVERY_CODE", "customer_two_factor", func() error {
+		db, err := r.GetDB(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE customer_two_factor
+			SET recovery_code_hashes = array_remove(recovery_code_hashes, $1), updated_at = $2
+			WHERE customer_id = $3 AND storefront_id = $4 AND $1 = ANY(recovery_code_hashes)`
+
+		affected, err := execRowsAffected(ctx, db, query, codeHash, time.Now(), customerID, storefrontID)
+		if err != nil {
+			return fmt.Errorf("failed to use customer recovery code: %w", err)
+		}
+
+		accepted = affected > 0
+		return nil
+	})
+
+	return accepted, err
+}
+
+// Delete removes the customer's enrollment
+func (r *PostgreSQLCustomerTwoFactorRepository) Delete(ctx context.Context, storefrontID, customerID uuid.UUID) error {
+	return WithMetrics(r.metricsCollector, "DELETE", "customer_two_factor", func() error {
+		db, err := r.GetDB(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
+
+		query := `DELETE FROM customer_two_factor WHERE customer_id = $1 AND storefront_id = $2`
+		if _, err := db.ExecContext(ctx, query, customerID, storefrontID); err != nil {
+			return fmt.Errorf("failed to delete customer two-factor: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// namedExecRowsAffected runs a named statement and returns the number of rows it touched
+func namedExecRowsAffected(ctx context.Context, db *sqlx.DB, query string, arg interface{}) (int, error) {
+	result, err := db.NamedExecContext(ctx, query, arg)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return int(affected), nil
+}
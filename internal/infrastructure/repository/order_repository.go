@@ -0,0 +1,716 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/database"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/tenant"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+const orderInsertQuery = `
+	INSERT INTO orders (
+		id, order_number, storefront_id, customer_id, customer_email, customer_phone,
+		status, subtotal, tax_amount, shipping_amount, discount_amount, total_amount, currency,
+		payment_status, payment_method, payment_reference,
+		shipping_method, shipping_tracking_number, shipping_carrier,
+		billing_first_name, billing_last_name, billing_company, billing_address_line_1,
+		billing_address_line_2, billing_city, billing_state_province, billing_postal_code,
+		billing_country, billing_phone,
+		shipping_first_name, shipping_last_name, shipping_company, shipping_address_line_1,
+		shipping_address_line_2, shipping_city, shipping_state_province, shipping_postal_code,
+		shipping_country, shipping_phone,
+		notes, internal_notes, tags, channel, channel_reference,
+		order_date, confirmed_at, shipped_at, delivered_at, cancelled_at,
+		created_by, created_at, updated_at
+	) VALUES (
+		:id, :order_number, :storefront_id, :customer_id, :customer_email, :customer_phone,
+		:status, :subtotal, :tax_amount, :shipping_amount, :discount_amount, :total_amount, :currency,
+		:payment_status, :payment_method, :payment_reference,
+		:shipping_method, :shipping_tracking_number, :shipping_carrier,
+		:billing_first_name, :billing_last_name, :billing_company, :billing_address_line_1,
+		:billing_address_line_2, :billing_city, :billing_state_province, :billing_postal_code,
+		:billing_country, :billing_phone,
+		:shipping_first_name, :shipping_last_name, :shipping_company, :shipping_address_line_1,
+		:shipping_address_line_2, :shipping_city, :shipping_state_province, :shipping_postal_code,
+		:shipping_country, :shipping_phone,
+		:notes, :internal_notes, :tags, :channel, :channel_reference,
+		:order_date, :confirmed_at, :shipped_at, :delivered_at, :cancelled_at,
+		:created_by, :created_at, :updated_at
+	)`
+
+const orderItemInsertQuery = `
+	INSERT INTO order_items (
+		id, order_id, product_id, product_variant_id, product_name, product_sku, variant_name,
+		unit_price, quantity, total_price, product_weight, product_image_url,
+		created_at, updated_at
+	) VALUES (
+		:id, :order_id, :product_id, :product_variant_id, :product_name, :product_sku, :variant_name,
+		:unit_price, :quantity, :total_price, :product_weight, :product_image_url,
+		:created_at, :updated_at
+	)`
+
+const orderStatusHistoryInsertQuery = `
+	INSERT INTO order_status_history (
+		id, order_id, from_status, to_status, reason, notes, changed_by, changed_at
+	) VALUES (
+		:id, :order_id, :from_status, :to_status, :reason, :notes, :changed_by, :changed_at
+	)`
+
+// OrderRepositoryImpl implements the OrderRepository interface
+type OrderRepositoryImpl struct {
+	*BaseRepository
+	logger zerolog.Logger
+}
+
+// NewOrderRepository creates a new order repository
+func NewOrderRepository(
+	db *sqlx.DB,
+	tenantResolver tenant.TenantResolver,
+	logger zerolog.Logger,
+) repository.OrderRepository {
+	return &OrderRepositoryImpl{
+		BaseRepository: NewBaseRepository(db, tenantResolver),
+		logger:         logger.With().Str("repository", "order").Logger(),
+	}
+}
+
+// newOrderQueryBuilder returns a query builder over the shared orders table.
+// Orders stay in the shared database whatever a storefront's isolation, since
+// they are written together with the stock of seller-owned products.
+func newOrderQueryBuilder() QueryBuilder {
+	return NewQueryBuilder(&tenant.TenantContext{TenantType: tenant.TenantTypeShared})
+}
+
+// Create creates a new order together with its items and initial status history.
+// When the context carries a transaction from database.WithTransaction the
+// order is written as part of it.
+func (r *OrderRepositoryImpl) Create(ctx context.Context, order *entity.Order) error {
+	if tx, ok := database.TxFromContext(ctx); ok {
+		return r.insertOrder(ctx, tx, order)
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.insertOrder(ctx, tx, order); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit order: %w", err)
+	}
+
+	r.logger.Info().Str("order_number", order.OrderNumber).Str("id", order.ID.String()).Int("items", len(order.Items)).Msg("Order created")
+	return nil
+}
+
+// insertOrder writes the order, its items and the initial history entry using the given transaction
+func (r *OrderRepositoryImpl) insertOrder(ctx context.Context, tx *sqlx.Tx, order *entity.Order) error {
+	if _, err := tx.NamedExecContext(ctx, orderInsertQuery, order); err != nil {
+		r.logger.Error().Err(err).Str("order_number", order.OrderNumber).Msg("Failed to create order")
+		return fmt.Errorf("failed to create order: %w", err)
+	}
+
+	for _, item := range order.Items {
+		item.OrderID = order.ID
+		if item.ID == uuid.Nil {
+			item.ID = uuid.New()
+		}
+		if _, err := tx.NamedExecContext(ctx, orderItemInsertQuery, item); err != nil {
+			r.logger.Error().Err(err).Str("order_number", order.OrderNumber).Str("product_name", item.ProductName).Msg("Failed to create order item")
+			return fmt.Errorf("failed to create order item %s: %w", item.ProductName, err)
+		}
+	}
+
+	history := &entity.OrderStatusHistory{
+		ID:        uuid.New(),
+		OrderID:   order.ID,
+		ToStatus:  order.Status,
+		ChangedAt: order.CreatedAt,
+	}
+	if _, err := tx.NamedExecContext(ctx, orderStatusHistoryInsertQuery, history); err != nil {
+		r.logger.Error().Err(err).Str("order_number", order.OrderNumber).Msg("Failed to record initial order status")
+		return fmt.Errorf("failed to record initial order status: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an order by its ID, including its items
+func (r *OrderRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity.Order, error) {
+	query := `SELECT * FROM orders WHERE id = $1 AND deleted_at IS NULL`
+	return r.getOne(ctx, query, id, "id", id.String())
+}
+
+// GetByOrderNumber retrieves an order by its order number, including its items
+func (r *OrderRepositoryImpl) GetByOrderNumber(ctx context.Context, orderNumber string) (*entity.Order, error) {
+	query := `SELECT * FROM orders WHERE order_number = $1 AND deleted_at IS NULL`
+	return r.getOne(ctx, query, orderNumber, "order_number", orderNumber)
+}
+
+// GetByPaymentReference retrieves an order by its payment reference
+func (r *OrderRepositoryImpl) GetByPaymentReference(ctx context.Context, paymentReference string) (*entity.Order, error) {
+	query := `SELECT * FROM orders WHERE payment_reference = $1 AND deleted_at IS NULL ORDER BY created_at DESC LIMIT 1`
+	return r.getOne(ctx, query, paymentReference, "payment_reference", paymentReference)
+}
+
+// GetByTrackingNumber retrieves an order by its shipping tracking number
+func (r *OrderRepositoryImpl) GetByTrackingNumber(ctx context.Context, trackingNumber string) (*entity.Order, error) {
+	query := `SELECT * FROM orders WHERE shipping_tracking_number = $1 AND deleted_at IS NULL ORDER BY created_at DESC LIMIT 1`
+	return r.getOne(ctx, query, trackingNumber, "tracking_number", trackingNumber)
+}
+
+// getOne runs a single-order lookup across all storefronts and loads the items
+func (r *OrderRepositoryImpl) getOne(ctx context.Context, query string, arg interface{}, field, value string) (*entity.Order, error) {
+	var order entity.Order
+	err := r.db.GetContext(ctx, &order, query, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Str(field, value).Msg("Failed to get order")
+		return nil, fmt.Errorf("failed to get order by %s: %w", field, err)
+	}
+
+	items, err := r.GetItems(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	order.Items = items
+
+	order.ComputeFields()
+	return &order, nil
+}
+
+// LockByID retrieves an order and locks its row until the transaction carried
+// by the context ends, so concurrent payment updates wait for the caller
+func (r *OrderRepositoryImpl) LockByID(ctx context.Context, id uuid.UUID) (*entity.Order, error) {
+	if _, ok := database.TxFromContext(ctx); !ok {
+		return nil, fmt.Errorf("locking an order requires a transaction")
+	}
+
+	query := `SELECT * FROM orders WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+	var order entity.Order
+	err := executorFromContext(ctx, r.db).GetContext(ctx, &order, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Str("id", id.String()).Msg("Failed to lock order")
+		return nil, fmt.Errorf("failed to lock order: %w", err)
+	}
+
+	items, err := r.GetItems(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	order.Items = items
+
+	order.ComputeFields()
+	return &order, nil
+}
+
+// GetItems retrieves the items of an order
+func (r *OrderRepositoryImpl) GetItems(ctx context.Context, orderID uuid.UUID) ([]*entity.OrderItem, error) {
+	query := `SELECT * FROM order_items WHERE order_id = $1 ORDER BY created_at ASC`
+
+	var items []*entity.OrderItem
+	err := r.db.SelectContext(ctx, &items, query, orderID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("Failed to get order items")
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+
+	return items, nil
+}
+
+// Update updates an existing order (items are not modified), joining the
+// transaction carried by the context, if any
+func (r *OrderRepositoryImpl) Update(ctx context.Context, order *entity.Order) error {
+	order.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE orders SET
+			customer_email = :customer_email, customer_phone = :customer_phone,
+			subtotal = :subtotal, tax_amount = :tax_amount, shipping_amount = :shipping_amount,
+			discount_amount = :discount_amount, total_amount = :total_amount,
+			payment_status = :payment_status, payment_method = :payment_method,
+			payment_reference = :payment_reference,
+			shipping_method = :shipping_method, shipping_tracking_number = :shipping_tracking_number,
+			shipping_carrier = :shipping_carrier,
+			billing_first_name = :billing_first_name, billing_last_name = :billing_last_name,
+			billing_company = :billing_company, billing_address_line_1 = :billing_address_line_1,
+			billing_address_line_2 = :billing_address_line_2, billing_city = :billing_city,
+			billing_state_province = :billing_state_province, billing_postal_code = :billing_postal_code,
+			billing_country = :billing_country, billing_phone = :billing_phone,
+			shipping_first_name = :shipping_first_name, shipping_last_name = :shipping_last_name,
+			shipping_company = :shipping_company, shipping_address_line_1 = :shipping_address_line_1,
+			shipping_address_line_2 = :shipping_address_line_2, shipping_city = :shipping_city,
+			shipping_state_province = :shipping_state_province, shipping_postal_code = :shipping_postal_code,
+			shipping_country = :shipping_country, shipping_phone = :shipping_phone,
+			notes = :notes, internal_notes = :internal_notes, tags = :tags,
+			channel_reference = :channel_reference,
+			updated_at = :updated_at
+		WHERE id = :id AND deleted_at IS NULL`
+
+	result, err := sqlx.NamedExecContext(ctx, executorFromContext(ctx, r.db), query, order)
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", order.ID.String()).Msg("Failed to update order")
+		return fmt.Errorf("failed to update order: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("order not found")
+	}
+
+	r.logger.Info().Str("id", order.ID.String()).Msg("Order updated")
+	return nil
+}
+
+// UpdateStatus persists a status change and records it in the order history
+func (r *OrderRepositoryImpl) UpdateStatus(ctx context.Context, order *entity.Order, history *entity.OrderStatusHistory) error {
+	if tx, ok := database.TxFromContext(ctx); ok {
+		return r.updateStatus(ctx, tx, order, history)
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.updateStatus(ctx, tx, order, history); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit order status update: %w", err)
+	}
+
+	r.logger.Info().Str("id", order.ID.String()).Str("status", order.Status.String()).Msg("Order status updated")
+	return nil
+}
+
+// updateStatus writes the status change and its history entry using the given
+// transaction. The write only applies while the order still holds the status
+// the transition started from, so concurrent transitions cannot overwrite each other.
+func (r *OrderRepositoryImpl) updateStatus(ctx context.Context, tx *sqlx.Tx, order *entity.Order, history *entity.OrderStatusHistory) error {
+	query := `
+		UPDATE orders SET
+			status = $1,
+			payment_status = $2,
+			shipping_tracking_number = $3,
+			shipping_carrier = $4,
+			confirmed_at = $5,
+			shipped_at = $6,
+			delivered_at = $7,
+			cancelled_at = $8,
+			updated_at = $9
+		WHERE id = $10 AND deleted_at IS NULL AND ($11::varchar IS NULL OR status = $11)`
+
+	var previousStatus *string
+	if history != nil && history.FromStatus != nil {
+		status := history.FromStatus.String()
+		previousStatus = &status
+	}
+
+	result, err := tx.ExecContext(ctx, query,
+		order.Status, order.PaymentStatus, order.ShippingTrackingNumber, order.ShippingCarrier,
+		order.ConfirmedAt, order.ShippedAt, order.DeliveredAt, order.CancelledAt, order.UpdatedAt,
+		order.ID, previousStatus)
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", order.ID.String()).Str("status", order.Status.String()).Msg("Failed to update order status")
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		if previousStatus != nil {
+			return fmt.Errorf("cannot transition from %s to %s: order status changed concurrently", *previousStatus, order.Status)
+		}
+		return fmt.Errorf("order not found")
+	}
+
+	if history != nil {
+		if _, err := tx.NamedExecContext(ctx, orderStatusHistoryInsertQuery, history); err != nil {
+			r.logger.Error().Err(err).Str("id", order.ID.String()).Msg("Failed to record order status history")
+			return fmt.Errorf("failed to record order status history: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// UpdatePayment updates the payment status, method and reference of an order
+func (r *OrderRepositoryImpl) UpdatePayment(ctx context.Context, orderID uuid.UUID, status entity.PaymentStatus, method, reference *string) error {
+	query := `
+		UPDATE orders SET
+			payment_status = $1,
+			payment_method = COALESCE($2, payment_method),
+			payment_reference = COALESCE($3, payment_reference),
+			updated_at = $4
+		WHERE id = $5 AND deleted_at IS NULL`
+
+	result, err := executorFromContext(ctx, r.db).ExecContext(ctx, query, status, method, reference, time.Now(), orderID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", orderID.String()).Str("payment_status", string(status)).Msg("Failed to update order payment")
+		return fmt.Errorf("failed to update order payment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("order not found")
+	}
+
+	r.logger.Info().Str("id", orderID.String()).Str("payment_status", string(status)).Msg("Order payment updated")
+	return nil
+}
+
+// UpdatePaymentIfStatus updates the payment of an order still in the expected
+// payment status, so concurrent gateway callbacks apply at most once
+func (r *OrderRepositoryImpl) UpdatePaymentIfStatus(ctx context.Context, orderID uuid.UUID, expected, status entity.PaymentStatus, method, reference *string) (bool, error) {
+	query := `
+		UPDATE orders SET
+			payment_status = $1,
+			payment_method = COALESCE($2, payment_method),
+			payment_reference = COALESCE($3, payment_reference),
+			updated_at = $4
+		WHERE id = $5 AND payment_status = $6 AND deleted_at IS NULL`
+
+	result, err := executorFromContext(ctx, r.db).ExecContext(ctx, query, status, method, reference, time.Now(), orderID, expected)
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", orderID.String()).Str("payment_status", string(status)).Msg("Failed to update order payment")
+		return false, fmt.Errorf("failed to update order payment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	r.logger.Info().Str("id", orderID.String()).Str("from", string(expected)).Str("payment_status", string(status)).Msg("Order payment updated")
+	return true, nil
+}
+
+// RecordPayment stores the amount a gateway charge has paid towards an order
+// and returns the order's total paid amount. Gateways report what a charge has
+// received so far, so a charge keeps the highest amount reported for it.
+func (r *OrderRepositoryImpl) RecordPayment(ctx context.Context, orderID uuid.UUID, paymentReference string, amount decimal.Decimal) (decimal.Decimal, error) {
+	// The order lock serialises callbacks, so the total below sees every charge
+	tx, ok := database.TxFromContext(ctx)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("recording a payment requires a transaction")
+	}
+
+	now := time.Now()
+	query := `
+		INSERT INTO order_payments (order_id, payment_reference, amount, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (order_id, payment_reference) DO UPDATE SET
+			amount = GREATEST(order_payments.amount, EXCLUDED.amount),
+			updated_at = EXCLUDED.updated_at`
+	if _, err := tx.ExecContext(ctx, query, orderID, paymentReference, amount, now); err != nil {
+		r.logger.Error().Err(err).Str("id", orderID.String()).Str("payment_reference", paymentReference).Msg("Failed to record order payment")
+		return decimal.Zero, fmt.Errorf("failed to record order payment: %w", err)
+	}
+
+	query = `
+		UPDATE orders SET
+			paid_amount = (SELECT COALESCE(SUM(amount), 0) FROM order_payments WHERE order_id = $1),
+			updated_at = $2
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING paid_amount`
+
+	var paidAmount decimal.Decimal
+	if err := tx.GetContext(ctx, &paidAmount, query, orderID, now); err != nil {
+		if err == sql.ErrNoRows {
+			return decimal.Zero, fmt.Errorf("order not found")
+		}
+		r.logger.Error().Err(err).Str("id", orderID.String()).Msg("Failed to update order paid amount")
+		return decimal.Zero, fmt.Errorf("failed to update order paid amount: %w", err)
+	}
+
+	r.logger.Info().Str("id", orderID.String()).Str("payment_reference", paymentReference).Str("paid_amount", paidAmount.String()).Msg("Order payment recorded")
+	return paidAmount, nil
+}
+
+// Delete soft deletes an order
+func (r *OrderRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+	query := `UPDATE orders SET deleted_at = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, now, now, id)
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", id.String()).Msg("Failed to delete order")
+		return fmt.Errorf("failed to delete order: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("order not found")
+	}
+
+	r.logger.Info().Str("id", id.String()).Msg("Order deleted")
+	return nil
+}
+
+// GetStatusHistory retrieves the status history of an order
+func (r *OrderRepositoryImpl) GetStatusHistory(ctx context.Context, orderID uuid.UUID) ([]*entity.OrderStatusHistory, error) {
+	query := `SELECT * FROM order_status_history WHERE order_id = $1 ORDER BY changed_at ASC`
+
+	var history []*entity.OrderStatusHistory
+	err := r.db.SelectContext(ctx, &history, query, orderID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("Failed to get order status history")
+		return nil, fmt.Errorf("failed to get order status history: %w", err)
+	}
+
+	return history, nil
+}
+
+// GetWithFilters retrieves orders with filters and pagination
+func (r *OrderRepositoryImpl) GetWithFilters(ctx context.Context, filters *repository.OrderFilters) ([]*entity.Order, error) {
+	qb := newOrderQueryBuilder()
+
+	qb = r.applyFilters(qb.Select("*"), filters)
+
+	// Apply sorting
+	sortBy := "created_at"
+	switch filters.SortBy {
+	case "order_date", "total_amount", "order_number", "status", "updated_at":
+		sortBy = filters.SortBy
+	}
+	sortDirection := "DESC"
+	if filters.SortDirection != "" {
+		sortDirection = strings.ToUpper(filters.SortDirection)
+	}
+	qb = qb.OrderBy(sortBy, sortDirection)
+
+	// Apply pagination
+	if filters.PageSize > 0 {
+		qb = qb.Limit(filters.PageSize)
+		if filters.Page > 1 {
+			qb = qb.Offset((filters.Page - 1) * filters.PageSize)
+		}
+	}
+
+	query, args := qb.Build()
+
+	var orders []*entity.Order
+	err := r.db.SelectContext(ctx, &orders, query, args...)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("Failed to get orders with filters")
+		return nil, fmt.Errorf("failed to get orders with filters: %w", err)
+	}
+
+	for _, order := range orders {
+		if filters.IncludeItems {
+			items, err := r.GetItems(ctx, order.ID)
+			if err != nil {
+				return nil, err
+			}
+			order.Items = items
+		}
+		order.ComputeFields()
+	}
+
+	return orders, nil
+}
+
+// Count counts orders with optional filters
+func (r *OrderRepositoryImpl) Count(ctx context.Context, filters *repository.OrderFilters) (int, error) {
+	qb := newOrderQueryBuilder()
+
+	query, args := r.applyFilters(qb, filters).BuildCount()
+
+	var count int
+	err := r.db.GetContext(ctx, &count, query, args...)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("Failed to count orders")
+		return 0, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	return count, nil
+}
+
+// applyFilters applies filters to the query builder
+func (r *OrderRepositoryImpl) applyFilters(qb QueryBuilder, filters *repository.OrderFilters) QueryBuilder {
+	qb = qb.From("orders").Where("deleted_at IS NULL")
+
+	if filters.StorefrontID != nil {
+		qb = qb.TenantWhere(*filters.StorefrontID)
+	}
+
+	if filters.CreatedBy != nil {
+		qb = qb.Where("created_by = $1", *filters.CreatedBy)
+	}
+
+	if filters.CustomerID != nil {
+		qb = qb.Where("customer_id = $1", *filters.CustomerID)
+	}
+
+	if filters.Status != nil {
+		qb = qb.Where("status = $1", *filters.Status)
+	}
+
+	if filters.PaymentStatus != nil {
+		qb = qb.Where("payment_status = $1", *filters.PaymentStatus)
+	}
+
+	if filters.Channel != nil {
+		qb = qb.Where("channel = $1", *filters.Channel)
+	}
+
+	if filters.OrderNumber != nil {
+		qb = qb.Where("order_number = $1", *filters.OrderNumber)
+	}
+
+	if filters.CustomerEmail != nil {
+		qb = qb.Where("LOWER(customer_email) = LOWER($1)", *filters.CustomerEmail)
+	}
+
+	if filters.Search != nil && *filters.Search != "" {
+		qb = qb.Where(`(
+			order_number ILIKE $1 OR
+			customer_email ILIKE $1 OR
+			shipping_first_name ILIKE $1 OR
+			shipping_last_name ILIKE $1 OR
+			shipping_tracking_number ILIKE $1
+		)`, "%"+*filters.Search+"%")
+	}
+
+	if len(filters.Tags) > 0 {
+		qb = qb.Where("tags && $1", pq.Array(filters.Tags))
+	}
+
+	if filters.DateFrom != nil {
+		qb = qb.Where("order_date >= $1", *filters.DateFrom)
+	}
+
+	if filters.DateTo != nil {
+		qb = qb.Where("order_date <= $1", *filters.DateTo)
+	}
+
+	if filters.MinTotal != nil {
+		qb = qb.Where("total_amount >= $1", *filters.MinTotal)
+	}
+
+	if filters.MaxTotal != nil {
+		qb = qb.Where("total_amount <= $1", *filters.MaxTotal)
+	}
+
+	return qb
+}
+
+// GetOrderStatistics retrieves order statistics for analytics
+func (r *OrderRepositoryImpl) GetOrderStatistics(ctx context.Context, filters *repository.OrderFilters) (*repository.OrderStatistics, error) {
+	qb := newOrderQueryBuilder()
+
+	query, args := r.applyFilters(qb.Select(
+		"COUNT(*)",
+		"COUNT(CASE WHEN status = 'pending' THEN 1 END)",
+		"COUNT(CASE WHEN status IN ('confirmed', 'processing') THEN 1 END)",
+		"COUNT(CASE WHEN status = 'shipped' THEN 1 END)",
+		"COUNT(CASE WHEN status = 'delivered' THEN 1 END)",
+		"COUNT(CASE WHEN status = 'cancelled' THEN 1 END)",
+		"COALESCE(SUM(CASE WHEN payment_status = 'paid' THEN total_amount END), 0)",
+		"COALESCE(AVG(total_amount), 0)",
+	), filters).Build()
+
+	var stats repository.OrderStatistics
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(
+		&stats.TotalOrders,
+		&stats.PendingOrders,
+		&stats.ProcessingOrders,
+		&stats.ShippedOrders,
+		&stats.DeliveredOrders,
+		&stats.CancelledOrders,
+		&stats.TotalRevenue,
+		&stats.AverageOrderValue,
+	)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("Failed to get order statistics")
+		return nil, fmt.Errorf("failed to get order statistics: %w", err)
+	}
+
+	stats.OrdersByStatus, err = r.countGroupedBy(ctx, filters, "status")
+	if err != nil {
+		return nil, err
+	}
+	stats.OrdersByChannel, err = r.countGroupedBy(ctx, filters, "channel")
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// countGroupedBy counts filtered orders grouped by a single column
+func (r *OrderRepositoryImpl) countGroupedBy(ctx context.Context, filters *repository.OrderFilters, column string) (map[string]int64, error) {
+	query, args := r.applyFilters(newOrderQueryBuilder().Select(column, "COUNT(*)"), filters).GroupBy(column).Build()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orders by %s: %w", column, err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]int64)
+	for rows.Next() {
+		var key sql.NullString
+		var count int64
+		if err := rows.Scan(&key, &count); err != nil {
+			continue
+		}
+		result[key.String] = count
+	}
+
+	return result, nil
+}
+
+// GenerateOrderNumber generates a unique order number
+func (r *OrderRepositoryImpl) GenerateOrderNumber(ctx context.Context) (string, error) {
+	// Generate format: ORD-YYYYMMDD-NNNNNN. The number comes from a sequence,
+	// so concurrent checkouts never get the same one.
+	prefix := fmt.Sprintf("ORD-%s-", time.Now().Format("20060102"))
+
+	var nextNum int64
+	err := r.db.GetContext(ctx, &nextNum, `SELECT nextval('order_number_seq')`)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("Failed to get next order number")
+		return "", fmt.Errorf("failed to get next order number: %w", err)
+	}
+
+	return fmt.Sprintf("%s%06d", prefix, nextNum), nil
+}
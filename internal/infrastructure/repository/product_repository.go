@@ -790,6 +790,12 @@ func (r *PostgreSQLProductRepository) Count(ctx context.Context, filter *reposit
 			whereConditions = append(whereConditions, fmt.Sprintf("category_id IN (%s)", strings.Join(placeholders, ",")))
 		}
 
+		if filter.CreatedBy != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("created_by = $%d", argIndex))
+			args = append(args, *filter.CreatedBy)
+			argIndex++
+		}
+
 		if filter.MinPrice != nil {
 			whereConditions = append(whereConditions, fmt.Sprintf("base_price >= $%d", argIndex))
 			args = append(args, *filter.MinPrice)
@@ -904,6 +910,12 @@ func (r *PostgreSQLProductRepository) Search(ctx context.Context, query string,
 			whereConditions = append(whereConditions, fmt.Sprintf("p.category_id IN (%s)", strings.Join(placeholders, ",")))
 		}
 
+		if filter.CreatedBy != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("p.created_by = $%d", argIndex))
+			args = append(args, *filter.CreatedBy)
+			argIndex++
+		}
+
 		if filter.MinPrice != nil {
 			whereConditions = append(whereConditions, fmt.Sprintf("p.base_price >= $%d", argIndex))
 			args = append(args, *filter.MinPrice)
@@ -1153,19 +1165,80 @@ func (r *PostgreSQLProductRepository) GetByCategoryPath(ctx context.Context, cat
 	return r.GetByCategory(ctx, categoryID, filter, include)
 }
 
+// UpdateStock sets the stock quantity of a product
 func (r *PostgreSQLProductRepository) UpdateStock(ctx context.Context, productID uuid.UUID, quantity int) error {
-	// TODO: Implement stock update
-	return fmt.Errorf("not implemented")
+	if quantity < 0 {
+		return fmt.Errorf("stock quantity cannot be negative")
+	}
+
+	query := `UPDATE products SET stock_quantity = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL`
+
+	result, err := executorFromContext(ctx, r.db).ExecContext(ctx, query, quantity, time.Now(), productID)
+	if err != nil {
+		return fmt.Errorf("failed to update stock: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return NewNotFoundError("Product", productID)
+	}
+
+	return nil
 }
 
+// DeductStock atomically removes quantity from a product's stock, failing
+// instead of going negative when another order got there first
 func (r *PostgreSQLProductRepository) DeductStock(ctx context.Context, productID uuid.UUID, quantity int) error {
-	// TODO: Implement stock deduction
-	return fmt.Errorf("not implemented")
+	if quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+
+	query := `
+		UPDATE products
+		SET stock_quantity = stock_quantity - $1, updated_at = $2
+		WHERE id = $3 AND deleted_at IS NULL AND stock_quantity >= $1`
+
+	result, err := executorFromContext(ctx, r.db).ExecContext(ctx, query, quantity, time.Now(), productID)
+	if err != nil {
+		return fmt.Errorf("failed to deduct stock: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("insufficient stock for product %s", productID)
+	}
+
+	return nil
 }
 
+// RestockInventory adds quantity back to a product's stock
 func (r *PostgreSQLProductRepository) RestockInventory(ctx context.Context, productID uuid.UUID, quantity int) error {
-	// TODO: Implement restock
-	return fmt.Errorf("not implemented")
+	if quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+
+	query := `UPDATE products SET stock_quantity = stock_quantity + $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL`
+
+	result, err := executorFromContext(ctx, r.db).ExecContext(ctx, query, quantity, time.Now(), productID)
+	if err != nil {
+		return fmt.Errorf("failed to restock inventory: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return NewNotFoundError("Product", productID)
+	}
+
+	return nil
 }
 
 func (r *PostgreSQLProductRepository) GetLowStockProducts(ctx context.Context, threshold int, include *repository.ProductInclude) ([]*entity.Product, error) {
@@ -627,7 +627,26 @@ func (r *PostgreSQLProductVariantRepository) ReorderVariants(ctx context.Context
 
 // RestockInventory restocks inventory for a variant
 func (r *PostgreSQLProductVariantRepository) RestockInventory(ctx context.Context, variantID uuid.UUID, quantity int) error {
-	return fmt.Errorf("RestockInventory not yet implemented")
+	if quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+
+	query := `UPDATE product_variants SET stock_quantity = stock_quantity + $1, updated_at = $2 WHERE id = $3`
+
+	result, err := executorFromContext(ctx, r.db).ExecContext(ctx, query, quantity, time.Now(), variantID)
+	if err != nil {
+		return fmt.Errorf("failed to restock variant: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("variant with ID '%s' not found", variantID)
+	}
+
+	return nil
 }
 
 // SearchByOptions searches variants by option values
@@ -726,16 +745,59 @@ func (r *PostgreSQLProductVariantRepository) GetOutOfStockVariants(ctx context.C
 	return nil, fmt.Errorf("not implemented")
 }
 
+// UpdateStock sets the stock quantity of a variant
 func (r *PostgreSQLProductVariantRepository) UpdateStock(ctx context.Context, variantID uuid.UUID, quantity int) error {
-	return fmt.Errorf("not implemented")
+	if quantity < 0 {
+		return fmt.Errorf("stock quantity cannot be negative")
+	}
+
+	query := `UPDATE product_variants SET stock_quantity = $1, updated_at = $2 WHERE id = $3`
+
+	result, err := executorFromContext(ctx, r.db).ExecContext(ctx, query, quantity, time.Now(), variantID)
+	if err != nil {
+		return fmt.Errorf("failed to update variant stock: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("variant with ID '%s' not found", variantID)
+	}
+
+	return nil
 }
 
 func (r *PostgreSQLProductVariantRepository) BulkUpdateStock(ctx context.Context, updates []repository.VariantStockUpdate) error {
 	return fmt.Errorf("not implemented")
 }
 
+// DeductStock atomically removes quantity from a variant's stock
 func (r *PostgreSQLProductVariantRepository) DeductStock(ctx context.Context, variantID uuid.UUID, quantity int) error {
-	return fmt.Errorf("not implemented")
+	if quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+
+	query := `
+		UPDATE product_variants
+		SET stock_quantity = stock_quantity - $1, updated_at = $2
+		WHERE id = $3 AND stock_quantity >= $1`
+
+	result, err := executorFromContext(ctx, r.db).ExecContext(ctx, query, quantity, time.Now(), variantID)
+	if err != nil {
+		return fmt.Errorf("failed to deduct variant stock: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("insufficient stock for variant %s", variantID)
+	}
+
+	return nil
 }
 
 func (r *PostgreSQLProductVariantRepository) RestockVariant(ctx context.Context, variantID uuid.UUID, quantity int) error {
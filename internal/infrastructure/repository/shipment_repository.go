@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/rs/zerolog"
+)
+
+// shipmentColumns lists the columns selected for a shipment
+const shipmentColumns = `
+	id, courier_code, tracking_number, order_id, warranty_claim_id, status, status_text,
+	location, last_event_at, delivered_at, created_at, updated_at`
+
+// ShipmentRepositoryImpl implements the ShipmentRepository interface.
+// Every method joins the transaction carried by the context, if any.
+type ShipmentRepositoryImpl struct {
+	db     *sqlx.DB
+	logger zerolog.Logger
+}
+
+// NewShipmentRepository creates a new shipment repository
+func NewShipmentRepository(db *sqlx.DB, logger zerolog.Logger) repository.ShipmentRepository {
+	return &ShipmentRepositoryImpl{
+		db:     db,
+		logger: logger.With().Str("repository", "shipment").Logger(),
+	}
+}
+
+// Create creates a shipment, doing nothing if the courier and tracking number already exist
+func (r *ShipmentRepositoryImpl) Create(ctx context.Context, shipment *entity.Shipment) error {
+	if err := shipment.Validate(); err != nil {
+		return fmt.Errorf("invalid shipment: %w", err)
+	}
+
+	query := `
+		INSERT INTO shipments (
+			id, courier_code, tracking_number, order_id, warranty_claim_id, status, status_text,
+			location, last_event_at, delivered_at, created_at, updated_at
+		) VALUES (
+			:id, :courier_code, :tracking_number, :order_id, :warranty_claim_id, :status, :status_text,
+			:location, :last_event_at, :delivered_at, :created_at, :updated_at
+		)
+		ON CONFLICT (courier_code, tracking_number) DO NOTHING`
+
+	if _, err := executorFromContext(ctx, r.db).NamedExecContext(ctx, query, shipment); err != nil {
+		r.logger.Error().Err(err).Str("tracking_number", shipment.TrackingNumber).Msg("Failed to create shipment")
+		return fmt.Errorf("failed to create shipment: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTrackingNumber retrieves a shipment by courier and tracking number
+func (r *ShipmentRepositoryImpl) GetByTrackingNumber(ctx context.Context, courierCode, trackingNumber string) (*entity.Shipment, error) {
+	query := `SELECT` + shipmentColumns + ` FROM shipments WHERE courier_code = $1 AND tracking_number = $2`
+	return r.getOne(ctx, query, "tracking_number", trackingNumber, courierCode, trackingNumber)
+}
+
+// GetByWarrantyClaimID retrieves the most recent shipment of a warranty claim
+func (r *ShipmentRepositoryImpl) GetByWarrantyClaimID(ctx context.Context, claimID uuid.UUID) (*entity.Shipment, error) {
+	query := `SELECT` + shipmentColumns + ` FROM shipments WHERE warranty_claim_id = $1 ORDER BY created_at DESC LIMIT 1`
+	return r.getOne(ctx, query, "warranty_claim_id", claimID.String(), claimID)
+}
+
+// GetByOrderID retrieves the most recent shipment of an order
+func (r *ShipmentRepositoryImpl) GetByOrderID(ctx context.Context, orderID uuid.UUID) (*entity.Shipment, error) {
+	query := `SELECT` + shipmentColumns + ` FROM shipments WHERE order_id = $1 ORDER BY created_at DESC LIMIT 1`
+	return r.getOne(ctx, query, "order_id", orderID.String(), orderID)
+}
+
+// getOne runs a single-shipment lookup
+func (r *ShipmentRepositoryImpl) getOne(ctx context.Context, query, field, value string, args ...interface{}) (*entity.Shipment, error) {
+	var shipment entity.Shipment
+	if err := executorFromContext(ctx, r.db).GetContext(ctx, &shipment, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Str(field, value).Msg("Failed to get shipment")
+		return nil, fmt.Errorf("failed to get shipment by %s: %w", field, err)
+	}
+
+	return &shipment, nil
+}
+
+// Update updates the current state and links of a shipment
+func (r *ShipmentRepositoryImpl) Update(ctx context.Context, shipment *entity.Shipment) error {
+	query := `
+		UPDATE shipments
+		SET order_id = :order_id, warranty_claim_id = :warranty_claim_id, status = :status,
+			status_text = :status_text, location = :location, last_event_at = :last_event_at,
+			delivered_at = :delivered_at, updated_at = :updated_at
+		WHERE id = :id`
+
+	result, err := executorFromContext(ctx, r.db).NamedExecContext(ctx, query, shipment)
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", shipment.ID.String()).Msg("Failed to update shipment")
+		return fmt.Errorf("failed to update shipment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("shipment not found")
+	}
+
+	return nil
+}
+
+// AddEvent stores a tracking event and reports false if it was already recorded
+func (r *ShipmentRepositoryImpl) AddEvent(ctx context.Context, event *entity.ShipmentTrackingEvent) (bool, error) {
+	if err := event.Validate(); err != nil {
+		return false, fmt.Errorf("invalid shipment event: %w", err)
+	}
+
+	query := `
+		INSERT INTO shipment_events (
+			id, shipment_id, status, raw_status, description, location, occurred_at, dedup_key, created_at
+		) VALUES (
+			:id, :shipment_id, :status, :raw_status, :description, :location, :occurred_at, :dedup_key, :created_at
+		)
+		ON CONFLICT (shipment_id, dedup_key) DO NOTHING`
+
+	result, err := executorFromContext(ctx, r.db).NamedExecContext(ctx, query, event)
+	if err != nil {
+		r.logger.Error().Err(err).Str("shipment_id", event.ShipmentID.String()).Msg("Failed to add shipment event")
+		return false, fmt.Errorf("failed to add shipment event: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetEvents retrieves the tracking history of a shipment, oldest first
+func (r *ShipmentRepositoryImpl) GetEvents(ctx context.Context, shipmentID uuid.UUID) ([]*entity.ShipmentTrackingEvent, error) {
+	query := `
+		SELECT id, shipment_id, status, raw_status, description, location, occurred_at, dedup_key, created_at
+		FROM shipment_events
+		WHERE shipment_id = $1
+		ORDER BY occurred_at ASC, created_at ASC`
+
+	var events []*entity.ShipmentTrackingEvent
+	if err := executorFromContext(ctx, r.db).SelectContext(ctx, &events, query, shipmentID); err != nil {
+		r.logger.Error().Err(err).Str("shipment_id", shipmentID.String()).Msg("Failed to get shipment events")
+		return nil, fmt.Errorf("failed to get shipment events: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,361 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+// sparePartColumns lists the columns selected for a spare part
+const sparePartColumns = `
+	id, storefront_id, part_number, name, description, supplier, unit_cost,
+	quantity_on_hand, quantity_reserved, reorder_threshold, reorder_quantity,
+	is_active, created_at, updated_at, deleted_at`
+
+// sparePartReservationColumns lists the columns selected for a reservation,
+// with the part number and name of its spare part
+const sparePartReservationColumns = `
+	r.id, r.spare_part_id, r.repair_ticket_id, r.quantity, r.status, r.unit_cost,
+	r.reserved_at, r.consumed_at, r.released_at, r.created_at, r.updated_at,
+	p.part_number, p.name AS part_name`
+
+// SparePartRepositoryImpl implements the SparePartRepository interface.
+// Every method joins the transaction carried by the context, if any.
+type SparePartRepositoryImpl struct {
+	db     *sqlx.DB
+	logger zerolog.Logger
+}
+
+// NewSparePartRepository creates a new spare part repository
+func NewSparePartRepository(db *sqlx.DB, logger zerolog.Logger) repository.SparePartRepository {
+	return &SparePartRepositoryImpl{
+		db:     db,
+		logger: logger.With().Str("repository", "spare_part").Logger(),
+	}
+}
+
+// Create creates a new spare part
+func (r *SparePartRepositoryImpl) Create(ctx context.Context, part *entity.SparePart) error {
+	if err := part.Validate(); err != nil {
+		return fmt.Errorf("invalid spare part: %w", err)
+	}
+
+	query := `
+		INSERT INTO spare_parts (
+			id, storefront_id, part_number, name, description, supplier, unit_cost,
+			quantity_on_hand, quantity_reserved, reorder_threshold, reorder_quantity,
+			is_active, created_at, updated_at
+		) VALUES (
+			:id, :storefront_id, :part_number, :name, :description, :supplier, :unit_cost,
+			:quantity_on_hand, :quantity_reserved, :reorder_threshold, :reorder_quantity,
+			:is_active, :created_at, :updated_at
+		)`
+
+	if _, err := executorFromContext(ctx, r.db).NamedExecContext(ctx, query, part); err != nil {
+		r.logger.Error().Err(err).Str("part_number", part.PartNumber).Msg("Failed to create spare part")
+		return fmt.Errorf("failed to create spare part: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a spare part by ID
+func (r *SparePartRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity.SparePart, error) {
+	query := `SELECT` + sparePartColumns + ` FROM spare_parts WHERE id = $1 AND deleted_at IS NULL`
+	return r.getOne(ctx, query, "id", id.String(), id)
+}
+
+// GetByPartNumber retrieves a storefront's spare part by part number
+func (r *SparePartRepositoryImpl) GetByPartNumber(ctx context.Context, storefrontID uuid.UUID, partNumber string) (*entity.SparePart, error) {
+	query := `SELECT` + sparePartColumns + ` FROM spare_parts WHERE storefront_id = $1 AND part_number = $2 AND deleted_at IS NULL`
+	return r.getOne(ctx, query, "part_number", partNumber, storefrontID, partNumber)
+}
+
+// getOne runs a single-part lookup
+func (r *SparePartRepositoryImpl) getOne(ctx context.Context, query, field, value string, args ...interface{}) (*entity.SparePart, error) {
+	var part entity.SparePart
+	if err := executorFromContext(ctx, r.db).GetContext(ctx, &part, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Str(field, value).Msg("Failed to get spare part")
+		return nil, fmt.Errorf("failed to get spare part by %s: %w", field, err)
+	}
+
+	return &part, nil
+}
+
+// List retrieves a page of spare parts with filters
+func (r *SparePartRepositoryImpl) List(ctx context.Context, filters *repository.SparePartFilters) ([]*entity.SparePart, error) {
+	where, args := sparePartFilterConditions(filters)
+
+	orderBy := "part_number"
+	if filters.NeedsReorder {
+		orderBy = "quantity_on_hand - quantity_reserved - reorder_threshold, part_number"
+	}
+
+	query := `SELECT` + sparePartColumns + ` FROM spare_parts WHERE ` + where + ` ORDER BY ` + orderBy
+	if filters.PageSize > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filters.PageSize)
+		if filters.Page > 1 {
+			query += fmt.Sprintf(" OFFSET %d", (filters.Page-1)*filters.PageSize)
+		}
+	}
+
+	var parts []*entity.SparePart
+	if err := executorFromContext(ctx, r.db).SelectContext(ctx, &parts, query, args...); err != nil {
+		r.logger.Error().Err(err).Str("storefront_id", filters.StorefrontID.String()).Msg("Failed to list spare parts")
+		return nil, fmt.Errorf("failed to list spare parts: %w", err)
+	}
+
+	return parts, nil
+}
+
+// Count counts the spare parts matching the filters
+func (r *SparePartRepositoryImpl) Count(ctx context.Context, filters *repository.SparePartFilters) (int, error) {
+	where, args := sparePartFilterConditions(filters)
+	query := `SELECT COUNT(*) FROM spare_parts WHERE ` + where
+
+	var count int
+	if err := executorFromContext(ctx, r.db).GetContext(ctx, &count, query, args...); err != nil {
+		r.logger.Error().Err(err).Str("storefront_id", filters.StorefrontID.String()).Msg("Failed to count spare parts")
+		return 0, fmt.Errorf("failed to count spare parts: %w", err)
+	}
+
+	return count, nil
+}
+
+// sparePartFilterConditions builds the WHERE clause for spare part filters
+func sparePartFilterConditions(filters *repository.SparePartFilters) (string, []interface{}) {
+	conditions := []string{"storefront_id = $1", "deleted_at IS NULL"}
+	args := []interface{}{filters.StorefrontID}
+	argIndex := 2
+
+	if filters.IsActive != nil {
+		conditions = append(conditions, fmt.Sprintf("is_active = $%d", argIndex))
+		args = append(args, *filters.IsActive)
+		argIndex++
+	}
+
+	if filters.NeedsReorder {
+		conditions = append(conditions, "quantity_on_hand - quantity_reserved <= reorder_threshold")
+	}
+
+	if filters.Search != nil && *filters.Search != "" {
+		conditions = append(conditions, fmt.Sprintf("(part_number ILIKE $%[1]d OR name ILIKE $%[1]d OR supplier ILIKE $%[1]d)", argIndex))
+		args = append(args, "%"+*filters.Search+"%")
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// Update updates the catalogue details of a spare part
+func (r *SparePartRepositoryImpl) Update(ctx context.Context, part *entity.SparePart) error {
+	if err := part.Validate(); err != nil {
+		return fmt.Errorf("invalid spare part: %w", err)
+	}
+
+	query := `
+		UPDATE spare_parts
+		SET part_number = :part_number, name = :name, description = :description,
+			supplier = :supplier, unit_cost = :unit_cost, reorder_threshold = :reorder_threshold,
+			reorder_quantity = :reorder_quantity, is_active = :is_active, updated_at = :updated_at
+		WHERE id = :id AND deleted_at IS NULL`
+
+	result, err := executorFromContext(ctx, r.db).NamedExecContext(ctx, query, part)
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", part.ID.String()).Msg("Failed to update spare part")
+		return fmt.Errorf("failed to update spare part: %w", err)
+	}
+
+	return checkSparePartAffected(result)
+}
+
+// Delete soft deletes a spare part
+func (r *SparePartRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE spare_parts SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+
+	result, err := executorFromContext(ctx, r.db).ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", id.String()).Msg("Failed to delete spare part")
+		return fmt.Errorf("failed to delete spare part: %w", err)
+	}
+
+	return checkSparePartAffected(result)
+}
+
+// AdjustStock adds delta to the quantity on hand, refusing to go below the
+// reserved quantity. Received stock with a unit cost updates the weighted
+// average unit cost.
+func (r *SparePartRepositoryImpl) AdjustStock(ctx context.Context, id uuid.UUID, delta int, receivedUnitCost *decimal.Decimal) (*entity.SparePart, error) {
+	query := `
+		UPDATE spare_parts
+		SET unit_cost = CASE
+				WHEN $2 > 0 AND $3::DECIMAL IS NOT NULL
+				THEN ROUND((quantity_on_hand * unit_cost + $2 * $3::DECIMAL) / (quantity_on_hand + $2), 2)
+				ELSE unit_cost
+			END,
+			quantity_on_hand = quantity_on_hand + $2,
+			updated_at = $4
+		WHERE id = $1 AND deleted_at IS NULL AND quantity_on_hand + $2 >= quantity_reserved
+		RETURNING` + sparePartColumns
+
+	var part entity.SparePart
+	err := executorFromContext(ctx, r.db).GetContext(ctx, &part, query, id, delta, receivedUnitCost, time.Now())
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("insufficient unreserved stock for spare part %s", id)
+	}
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", id.String()).Int("delta", delta).Msg("Failed to adjust spare part stock")
+		return nil, fmt.Errorf("failed to adjust spare part stock: %w", err)
+	}
+
+	return &part, nil
+}
+
+// Reserve holds quantity for a repair and reports false if not enough is available
+func (r *SparePartRepositoryImpl) Reserve(ctx context.Context, id uuid.UUID, quantity int) (bool, error) {
+	query := `
+		UPDATE spare_parts
+		SET quantity_reserved = quantity_reserved + $2, updated_at = $3
+		WHERE id = $1 AND deleted_at IS NULL AND quantity_on_hand - quantity_reserved >= $2`
+
+	result, err := executorFromContext(ctx, r.db).ExecContext(ctx, query, id, quantity, time.Now())
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", id.String()).Int("quantity", quantity).Msg("Failed to reserve spare part")
+		return false, fmt.Errorf("failed to reserve spare part: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// Unreserve returns held quantity to the available stock
+func (r *SparePartRepositoryImpl) Unreserve(ctx context.Context, id uuid.UUID, quantity int) error {
+	query := `
+		UPDATE spare_parts
+		SET quantity_reserved = GREATEST(quantity_reserved - $2, 0), updated_at = $3
+		WHERE id = $1`
+
+	result, err := executorFromContext(ctx, r.db).ExecContext(ctx, query, id, quantity, time.Now())
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", id.String()).Int("quantity", quantity).Msg("Failed to unreserve spare part")
+		return fmt.Errorf("failed to unreserve spare part: %w", err)
+	}
+
+	return checkSparePartAffected(result)
+}
+
+// Consume removes held quantity from the stock on hand
+func (r *SparePartRepositoryImpl) Consume(ctx context.Context, id uuid.UUID, quantity int) (*entity.SparePart, error) {
+	query := `
+		UPDATE spare_parts
+		SET quantity_on_hand = quantity_on_hand - $2,
+			quantity_reserved = quantity_reserved - $2,
+			updated_at = $3
+		WHERE id = $1 AND quantity_reserved >= $2
+		RETURNING` + sparePartColumns
+
+	var part entity.SparePart
+	err := executorFromContext(ctx, r.db).GetContext(ctx, &part, query, id, quantity, time.Now())
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("spare part %s has less than %d reserved", id, quantity)
+	}
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", id.String()).Int("quantity", quantity).Msg("Failed to consume spare part")
+		return nil, fmt.Errorf("failed to consume spare part: %w", err)
+	}
+
+	return &part, nil
+}
+
+// CreateReservation creates a reservation of a part for a repair ticket
+func (r *SparePartRepositoryImpl) CreateReservation(ctx context.Context, reservation *entity.SparePartReservation) error {
+	if err := reservation.Validate(); err != nil {
+		return fmt.Errorf("invalid spare part reservation: %w", err)
+	}
+
+	query := `
+		INSERT INTO spare_part_reservations (
+			id, spare_part_id, repair_ticket_id, quantity, status, unit_cost,
+			reserved_at, consumed_at, released_at, created_at, updated_at
+		) VALUES (
+			:id, :spare_part_id, :repair_ticket_id, :quantity, :status, :unit_cost,
+			:reserved_at, :consumed_at, :released_at, :created_at, :updated_at
+		)`
+
+	if _, err := executorFromContext(ctx, r.db).NamedExecContext(ctx, query, reservation); err != nil {
+		r.logger.Error().Err(err).Str("repair_ticket_id", reservation.RepairTicketID.String()).Msg("Failed to create spare part reservation")
+		return fmt.Errorf("failed to create spare part reservation: %w", err)
+	}
+
+	return nil
+}
+
+// GetReservationsByTicketID retrieves the parts reserved for a repair ticket
+func (r *SparePartRepositoryImpl) GetReservationsByTicketID(ctx context.Context, ticketID uuid.UUID) ([]*entity.SparePartReservation, error) {
+	query := `
+		SELECT` + sparePartReservationColumns + `
+		FROM spare_part_reservations r
+		JOIN spare_parts p ON p.id = r.spare_part_id
+		WHERE r.repair_ticket_id = $1
+		ORDER BY r.created_at`
+
+	var reservations []*entity.SparePartReservation
+	if err := executorFromContext(ctx, r.db).SelectContext(ctx, &reservations, query, ticketID); err != nil {
+		r.logger.Error().Err(err).Str("repair_ticket_id", ticketID.String()).Msg("Failed to get spare part reservations")
+		return nil, fmt.Errorf("failed to get spare part reservations: %w", err)
+	}
+
+	return reservations, nil
+}
+
+// UpdateReservation updates the state of a reservation
+func (r *SparePartRepositoryImpl) UpdateReservation(ctx context.Context, reservation *entity.SparePartReservation) error {
+	query := `
+		UPDATE spare_part_reservations
+		SET status = :status, unit_cost = :unit_cost, reserved_at = :reserved_at,
+			consumed_at = :consumed_at, released_at = :released_at, updated_at = :updated_at
+		WHERE id = :id`
+
+	result, err := executorFromContext(ctx, r.db).NamedExecContext(ctx, query, reservation)
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", reservation.ID.String()).Msg("Failed to update spare part reservation")
+		return fmt.Errorf("failed to update spare part reservation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("spare part reservation not found")
+	}
+
+	return nil
+}
+
+// checkSparePartAffected reports a missing spare part when nothing was updated
+func checkSparePartAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("spare part not found")
+	}
+	return nil
+}
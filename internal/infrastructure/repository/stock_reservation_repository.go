@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/rs/zerolog"
+)
+
+// StockReservationRepositoryImpl implements the StockReservationRepository interface.
+// Every method joins the transaction carried by the context, if any.
+type StockReservationRepositoryImpl struct {
+	db     *sqlx.DB
+	logger zerolog.Logger
+}
+
+// NewStockReservationRepository creates a new stock reservation repository
+func NewStockReservationRepository(db *sqlx.DB, logger zerolog.Logger) repository.StockReservationRepository {
+	return &StockReservationRepositoryImpl{
+		db:     db,
+		logger: logger.With().Str("repository", "stock_reservation").Logger(),
+	}
+}
+
+// Create creates a new stock reservation
+func (r *StockReservationRepositoryImpl) Create(ctx context.Context, reservation *entity.StockReservation) error {
+	if err := reservation.Validate(); err != nil {
+		return fmt.Errorf("invalid stock reservation: %w", err)
+	}
+
+	query := `
+		INSERT INTO stock_reservations (
+			id, order_id, product_id, product_variant_id, quantity, status,
+			expires_at, released_at, release_reason, created_at, updated_at
+		) VALUES (
+			:id, :order_id, :product_id, :product_variant_id, :quantity, :status,
+			:expires_at, :released_at, :release_reason, :created_at, :updated_at
+		)`
+
+	if _, err := executorFromContext(ctx, r.db).NamedExecContext(ctx, query, reservation); err != nil {
+		r.logger.Error().Err(err).Str("order_id", reservation.OrderID.String()).Msg("Failed to create stock reservation")
+		return fmt.Errorf("failed to create stock reservation: %w", err)
+	}
+
+	return nil
+}
+
+// GetByOrderID retrieves all reservations of an order
+func (r *StockReservationRepositoryImpl) GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entity.StockReservation, error) {
+	query := `
+		SELECT id, order_id, product_id, product_variant_id, quantity, status,
+			expires_at, released_at, release_reason, created_at, updated_at
+		FROM stock_reservations
+		WHERE order_id = $1
+		ORDER BY created_at`
+
+	var reservations []*entity.StockReservation
+	if err := executorFromContext(ctx, r.db).SelectContext(ctx, &reservations, query, orderID); err != nil {
+		r.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("Failed to get stock reservations")
+		return nil, fmt.Errorf("failed to get stock reservations: %w", err)
+	}
+
+	return reservations, nil
+}
+
+// GetExpiredOrderIDs retrieves orders holding active reservations that expired before the given time
+func (r *StockReservationRepositoryImpl) GetExpiredOrderIDs(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT order_id
+		FROM stock_reservations
+		WHERE status = $1 AND expires_at < $2
+		GROUP BY order_id
+		ORDER BY MIN(expires_at)
+		LIMIT $3`
+
+	var orderIDs []uuid.UUID
+	if err := executorFromContext(ctx, r.db).SelectContext(ctx, &orderIDs, query, entity.StockReservationStatusActive, before, limit); err != nil {
+		r.logger.Error().Err(err).Msg("Failed to get expired stock reservations")
+		return nil, fmt.Errorf("failed to get expired stock reservations: %w", err)
+	}
+
+	return orderIDs, nil
+}
+
+// Update updates the status of a reservation
+func (r *StockReservationRepositoryImpl) Update(ctx context.Context, reservation *entity.StockReservation) error {
+	query := `
+		UPDATE stock_reservations
+		SET status = $1, released_at = $2, release_reason = $3, updated_at = $4
+		WHERE id = $5`
+
+	result, err := executorFromContext(ctx, r.db).ExecContext(ctx, query,
+		reservation.Status, reservation.ReleasedAt, reservation.ReleaseReason, reservation.UpdatedAt, reservation.ID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", reservation.ID.String()).Msg("Failed to update stock reservation")
+		return fmt.Errorf("failed to update stock reservation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("stock reservation not found")
+	}
+
+	return nil
+}
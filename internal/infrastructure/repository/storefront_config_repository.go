@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+)
+
+// PostgreSQLStorefrontConfigRepository implements the StorefrontConfigRepository interface using PostgreSQL
+type PostgreSQLStorefrontConfigRepository struct {
+	db               *sqlx.DB
+	metricsCollector MetricsCollector
+}
+
+// NewPostgreSQLStorefrontConfigRepository creates a new PostgreSQL storefront config repository
+func NewPostgreSQLStorefrontConfigRepository(db *sqlx.DB, metricsCollector MetricsCollector) repository.StorefrontConfigRepository {
+	if metricsCollector == nil {
+		metricsCollector = &NoOpMetricsCollector{}
+	}
+
+	return &PostgreSQLStorefrontConfigRepository{
+		db:               db,
+		metricsCollector: metricsCollector,
+	}
+}
+
+// GetValue returns the raw JSON value of a key, falling back to its default
+// value, or nil when the storefront does not set it
+func (r *PostgreSQLStorefrontConfigRepository) GetValue(ctx context.Context, storefrontID uuid.UUID, key string) (json.RawMessage, error) {
+	var value []byte
+
+	err := WithMetrics(r.metricsCollector, "GET_VALUE", "storefront_configs", func() error {
+		query := `
+			SELECT COALESCE(config_value, default_value)
+			FROM storefront_configs
+			WHERE storefront_id = $1 AND config_key = $2`
+
+		if err := r.db.GetContext(ctx, &value, query, storefrontID, key); err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			return fmt.Errorf("failed to get storefront config %s: %w", key, err)
+		}
+
+		return nil
+	})
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	return json.RawMessage(value), nil
+}
@@ -530,17 +530,27 @@ func (r *PostgreSQLStorefrontRepository) GetStorefrontStats(ctx context.Context,
 	var stats repository.StorefrontStats
 
 	err := WithMetrics(r.metricsCollector, "GET_STATS", "storefronts", func() error {
-		// Basic customer stats
-		customerQuery := `
-			SELECT 
-				COUNT(*) as customer_count,
-				COUNT(*) FILTER (WHERE created_at >= date_trunc('month', CURRENT_DATE)) as new_customers_this_month
-			FROM customers 
-			WHERE storefront_id = $1 AND deleted_at IS NULL
-		`
+		// Basic customer stats, read from wherever the storefront's customers live
+		tenantCtx, err := r.GetTenantContext(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
+
+		customerQuery, args := NewQueryBuilder(tenantCtx).
+			Select("COUNT(*) as customer_count",
+				"COUNT(*) FILTER (WHERE created_at >= date_trunc('month', CURRENT_DATE)) as new_customers_this_month").
+			From("customers").
+			TenantWhere(storefrontID).
+			Where("deleted_at IS NULL").
+			Build()
+
+		db, err := r.GetDB(ctx, storefrontID)
+		if err != nil {
+			return err
+		}
 
 		var customerCount, newCustomersThisMonth int
-		err := r.db.QueryRowContext(ctx, customerQuery, storefrontID).Scan(&customerCount, &newCustomersThisMonth)
+		err = db.QueryRowContext(ctx, customerQuery, args...).Scan(&customerCount, &newCustomersThisMonth)
 		if err != nil && err != sql.ErrNoRows {
 			return fmt.Errorf("failed to get customer stats: %w", err)
 		}
@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+)
+
+// technicianColumns lists the columns selected for a technician, with the
+// name and email of their user
+const technicianColumns = `
+	t.id, t.storefront_id, t.user_id, t.max_concurrent_tickets, t.working_hours,
+	t.timezone, t.is_active, t.created_at, t.updated_at, t.deleted_at,
+	u.name, COALESCE(u.email, '') AS email`
+
+// technicianWorkloadColumns counts the work assigned to a technician: the
+// claims assigned to or in repair with them, and their open repair tickets
+const technicianWorkloadColumns = `,
+	(SELECT COUNT(*) FROM warranty_claims c
+		WHERE c.storefront_id = t.storefront_id AND c.assigned_technician_id = t.user_id
+			AND c.status IN ('assigned', 'in_repair')) AS active_claims,
+	(SELECT COUNT(*) FROM repair_tickets rt JOIN warranty_claims c ON c.id = rt.claim_id
+		WHERE c.storefront_id = t.storefront_id AND rt.technician_id = t.user_id
+			AND rt.status IN ('assigned', 'in_progress', 'waiting_parts')) AS open_tickets,
+	(SELECT COUNT(*) FROM repair_tickets rt JOIN warranty_claims c ON c.id = rt.claim_id
+		WHERE c.storefront_id = t.storefront_id AND rt.technician_id = t.user_id
+			AND rt.status = 'waiting_parts') AS waiting_parts_tickets`
+
+// TechnicianRepositoryImpl implements the TechnicianRepository interface.
+// Every method joins the transaction carried by the context, if any.
+type TechnicianRepositoryImpl struct {
+	db     *sqlx.DB
+	logger zerolog.Logger
+}
+
+// NewTechnicianRepository creates a new technician repository
+func NewTechnicianRepository(db *sqlx.DB, logger zerolog.Logger) repository.TechnicianRepository {
+	return &TechnicianRepositoryImpl{
+		db:     db,
+		logger: logger.With().Str("repository", "technician").Logger(),
+	}
+}
+
+// Create creates a new technician with their skills
+func (r *TechnicianRepositoryImpl) Create(ctx context.Context, technician *entity.Technician) error {
+	if err := technician.Validate(); err != nil {
+		return fmt.Errorf("invalid technician: %w", err)
+	}
+
+	query := `
+		INSERT INTO technicians (
+			id, storefront_id, user_id, max_concurrent_tickets, working_hours,
+			timezone, is_active, created_at, updated_at
+		) VALUES (
+			:id, :storefront_id, :user_id, :max_concurrent_tickets, :working_hours,
+			:timezone, :is_active, :created_at, :updated_at
+		)`
+
+	if _, err := executorFromContext(ctx, r.db).NamedExecContext(ctx, query, technician); err != nil {
+		r.logger.Error().Err(err).Str("user_id", technician.UserID.String()).Msg("Failed to create technician")
+		return fmt.Errorf("failed to create technician: %w", err)
+	}
+
+	return r.insertSkills(ctx, technician)
+}
+
+// GetByID retrieves a technician with their skills by ID
+func (r *TechnicianRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity.Technician, error) {
+	query := `
+		SELECT` + technicianColumns + `
+		FROM technicians t JOIN users u ON u.id = t.user_id
+		WHERE t.id = $1 AND t.deleted_at IS NULL`
+	return r.getOne(ctx, query, "id", id.String(), id)
+}
+
+// GetByUserID retrieves the technician of a storefront who is the given user
+func (r *TechnicianRepositoryImpl) GetByUserID(ctx context.Context, storefrontID, userID uuid.UUID) (*entity.Technician, error) {
+	query := `
+		SELECT` + technicianColumns + `
+		FROM technicians t JOIN users u ON u.id = t.user_id
+		WHERE t.storefront_id = $1 AND t.user_id = $2 AND t.deleted_at IS NULL`
+	return r.getOne(ctx, query, "user_id", userID.String(), storefrontID, userID)
+}
+
+// getOne runs a single-technician lookup and loads the skills
+func (r *TechnicianRepositoryImpl) getOne(ctx context.Context, query, field, value string, args ...interface{}) (*entity.Technician, error) {
+	var technician entity.Technician
+	if err := executorFromContext(ctx, r.db).GetContext(ctx, &technician, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Str(field, value).Msg("Failed to get technician")
+		return nil, fmt.Errorf("failed to get technician by %s: %w", field, err)
+	}
+
+	if err := r.loadSkills(ctx, []*entity.Technician{&technician}); err != nil {
+		return nil, err
+	}
+
+	return &technician, nil
+}
+
+// Update updates a technician and replaces their skills
+func (r *TechnicianRepositoryImpl) Update(ctx context.Context, technician *entity.Technician) error {
+	if err := technician.Validate(); err != nil {
+		return fmt.Errorf("invalid technician: %w", err)
+	}
+
+	query := `
+		UPDATE technicians
+		SET max_concurrent_tickets = :max_concurrent_tickets, working_hours = :working_hours,
+			timezone = :timezone, is_active = :is_active, updated_at = :updated_at
+		WHERE id = :id AND deleted_at IS NULL`
+
+	executor := executorFromContext(ctx, r.db)
+	result, err := executor.NamedExecContext(ctx, query, technician)
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", technician.ID.String()).Msg("Failed to update technician")
+		return fmt.Errorf("failed to update technician: %w", err)
+	}
+	if err := checkTechnicianAffected(result); err != nil {
+		return err
+	}
+
+	if _, err := executor.ExecContext(ctx, `DELETE FROM technician_skills WHERE technician_id = $1`, technician.ID); err != nil {
+		r.logger.Error().Err(err).Str("id", technician.ID.String()).Msg("Failed to clear technician skills")
+		return fmt.Errorf("failed to update technician skills: %w", err)
+	}
+
+	return r.insertSkills(ctx, technician)
+}
+
+// Delete soft deletes a technician
+func (r *TechnicianRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE technicians SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+
+	result, err := executorFromContext(ctx, r.db).ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", id.String()).Msg("Failed to delete technician")
+		return fmt.Errorf("failed to delete technician: %w", err)
+	}
+
+	return checkTechnicianAffected(result)
+}
+
+// ListWorkloads retrieves the technicians matching the filters with the work
+// assigned to them
+func (r *TechnicianRepositoryImpl) ListWorkloads(ctx context.Context, filters *repository.TechnicianFilters) ([]*entity.TechnicianWorkload, error) {
+	conditions := []string{"t.storefront_id = $1", "t.deleted_at IS NULL"}
+	args := []interface{}{filters.StorefrontID}
+	argIndex := 2
+
+	if filters.IsActive != nil {
+		conditions = append(conditions, fmt.Sprintf("t.is_active = $%d", argIndex))
+		args = append(args, *filters.IsActive)
+		argIndex++
+	}
+
+	if filters.SkillCategoryID != nil {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM technician_skills s
+			WHERE s.technician_id = t.id AND s.category_id IN (
+				WITH RECURSIVE ancestors AS (
+					SELECT id, parent_id FROM product_categories WHERE id = $%d
+					UNION ALL
+					SELECT pc.id, pc.parent_id FROM product_categories pc JOIN ancestors a ON pc.id = a.parent_id
+				)
+				SELECT id FROM ancestors
+			))`, argIndex))
+		args = append(args, *filters.SkillCategoryID)
+	}
+
+	query := `
+		SELECT` + technicianColumns + technicianWorkloadColumns + `
+		FROM technicians t JOIN users u ON u.id = t.user_id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY u.name, t.created_at`
+
+	var workloads []*entity.TechnicianWorkload
+	if err := executorFromContext(ctx, r.db).SelectContext(ctx, &workloads, query, args...); err != nil {
+		r.logger.Error().Err(err).Str("storefront_id", filters.StorefrontID.String()).Msg("Failed to list technician workloads")
+		return nil, fmt.Errorf("failed to list technician workloads: %w", err)
+	}
+
+	technicians := make([]*entity.Technician, 0, len(workloads))
+	for _, workload := range workloads {
+		technicians = append(technicians, &workload.Technician)
+	}
+	if err := r.loadSkills(ctx, technicians); err != nil {
+		return nil, err
+	}
+
+	return workloads, nil
+}
+
+// insertSkills stores the skills of a technician
+func (r *TechnicianRepositoryImpl) insertSkills(ctx context.Context, technician *entity.Technician) error {
+	categoryIDs := technician.SkillCategoryIDs()
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO technician_skills (technician_id, category_id)
+		SELECT $1, UNNEST($2::uuid[])
+		ON CONFLICT DO NOTHING`
+
+	if _, err := executorFromContext(ctx, r.db).ExecContext(ctx, query, technician.ID, pq.Array(categoryIDs)); err != nil {
+		r.logger.Error().Err(err).Str("id", technician.ID.String()).Msg("Failed to store technician skills")
+		return fmt.Errorf("failed to store technician skills: %w", err)
+	}
+
+	return nil
+}
+
+// loadSkills fills in the skills of the technicians
+func (r *TechnicianRepositoryImpl) loadSkills(ctx context.Context, technicians []*entity.Technician) error {
+	if len(technicians) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*entity.Technician, len(technicians))
+	ids := make([]uuid.UUID, 0, len(technicians))
+	for _, technician := range technicians {
+		technician.Skills = []entity.TechnicianSkill{}
+		byID[technician.ID] = technician
+		ids = append(ids, technician.ID)
+	}
+
+	query := `
+		SELECT s.technician_id, s.category_id, c.name AS category_name
+		FROM technician_skills s JOIN product_categories c ON c.id = s.category_id
+		WHERE s.technician_id = ANY($1)
+		ORDER BY c.name`
+
+	var rows []struct {
+		TechnicianID uuid.UUID `db:"technician_id"`
+		entity.TechnicianSkill
+	}
+	if err := executorFromContext(ctx, r.db).SelectContext(ctx, &rows, query, pq.Array(ids)); err != nil {
+		r.logger.Error().Err(err).Int("technicians", len(ids)).Msg("Failed to load technician skills")
+		return fmt.Errorf("failed to load technician skills: %w", err)
+	}
+
+	for _, row := range rows {
+		if technician, ok := byID[row.TechnicianID]; ok {
+			technician.Skills = append(technician.Skills, row.TechnicianSkill)
+		}
+	}
+
+	return nil
+}
+
+// checkTechnicianAffected reports a missing technician when nothing was updated
+func checkTechnicianAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("technician not found")
+	}
+	return nil
+}
@@ -436,6 +436,46 @@ func (r *WarrantyBarcodeRepositoryImpl) Activate(ctx context.Context, barcodeNum
 	return nil
 }
 
+// Register stores the activation of a distributed barcode by a customer. The
+// status condition keeps two registrations of the same barcode from both
+// succeeding.
+func (r *WarrantyBarcodeRepositoryImpl) Register(ctx context.Context, barcode *entity.WarrantyBarcode) (bool, error) {
+	db, err := r.GetDB(ctx, barcode.StorefrontID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	query := `
+		UPDATE warranty_barcodes SET
+			status = :status,
+			activated_at = :activated_at,
+			customer_id = :customer_id,
+			purchase_date = :purchase_date,
+			purchase_location = :purchase_location,
+			purchase_invoice = :purchase_invoice,
+			proof_of_purchase_path = :proof_of_purchase_path,
+			expiry_date = :expiry_date,
+			updated_at = :updated_at
+		WHERE id = :id AND storefront_id = :storefront_id AND status = 'distributed' AND deleted_at IS NULL`
+
+	result, err := db.NamedExecContext(ctx, query, barcode)
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", barcode.ID.String()).Msg("Failed to register warranty barcode")
+		return false, fmt.Errorf("failed to register warranty barcode: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	r.logger.Info().Str("barcode_number", barcode.BarcodeNumber).Str("customer_id", barcode.CustomerID.String()).Msg("Warranty barcode registered")
+	return true, nil
+}
+
 // Deactivate deactivates a warranty barcode
 func (r *WarrantyBarcodeRepositoryImpl) Deactivate(ctx context.Context, barcodeNumber string, reason string) error {
 	// First get the barcode to determine storefront
@@ -626,6 +666,10 @@ func (r *WarrantyBarcodeRepositoryImpl) applyFilters(qb QueryBuilder, filters *r
 		qb = qb.Where("activated_by = $1", *filters.ActivatedBy)
 	}
 
+	if filters.CustomerID != nil {
+		qb = qb.Where("customer_id = $1", *filters.CustomerID)
+	}
+
 	if filters.CreatedAfter != nil {
 		qb = qb.Where("created_at >= $1", *filters.CreatedAfter)
 	}
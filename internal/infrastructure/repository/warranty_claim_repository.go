@@ -13,6 +13,7 @@ import (
 	"github.com/kirimku/smartseller-backend/internal/domain/entity"
 	"github.com/kirimku/smartseller-backend/internal/domain/repository"
 	"github.com/kirimku/smartseller-backend/internal/infrastructure/tenant"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog"
 )
 
@@ -49,13 +50,15 @@ func (r *WarrantyClaimRepositoryImpl) Create(ctx context.Context, claim *entity.
 			status, previous_status, status_updated_at, status_updated_by,
 			validated_by, assigned_technician_id, estimated_completion_date, actual_completion_date,
 			resolution_type, repair_notes, replacement_product_id, refund_amount,
-			repair_cost, shipping_cost, replacement_cost, total_cost,
+			repair_cost, repair_labor_cost, repair_parts_cost, shipping_cost, replacement_cost, total_cost,
 			customer_name, customer_email, customer_phone,
 			pickup_address,
 			shipping_provider, tracking_number, estimated_delivery_date, actual_delivery_date, delivery_status,
 			customer_notes, admin_notes, rejection_reason, internal_notes,
 			priority, tags,
 			customer_satisfaction_rating, customer_feedback, processing_time_hours,
+			sla_policy_hours, sla_due_at, sla_at_risk_at, sla_paused_at, sla_paused_seconds,
+			sla_stopped_at, sla_escalated_at, sla_breached_at,
 			created_at, updated_at
 		) VALUES (
 			:id, :claim_number, :barcode_id, :customer_id, :product_id, :storefront_id,
@@ -64,13 +67,15 @@ func (r *WarrantyClaimRepositoryImpl) Create(ctx context.Context, claim *entity.
 			:status, :previous_status, :status_updated_at, :status_updated_by,
 			:validated_by, :assigned_technician_id, :estimated_completion_date, :actual_completion_date,
 			:resolution_type, :repair_notes, :replacement_product_id, :refund_amount,
-			:repair_cost, :shipping_cost, :replacement_cost, :total_cost,
+			:repair_cost, :repair_labor_cost, :repair_parts_cost, :shipping_cost, :replacement_cost, :total_cost,
 			:customer_name, :customer_email, :customer_phone,
 			:pickup_address,
 			:shipping_provider, :tracking_number, :estimated_delivery_date, :actual_delivery_date, :delivery_status,
 			:customer_notes, :admin_notes, :rejection_reason, :internal_notes,
 			:priority, :tags,
 			:customer_satisfaction_rating, :customer_feedback, :processing_time_hours,
+			:sla_policy_hours, :sla_due_at, :sla_at_risk_at, :sla_paused_at, :sla_paused_seconds,
+			:sla_stopped_at, :sla_escalated_at, :sla_breached_at,
 			:created_at, :updated_at
 		)`
 
@@ -121,6 +126,28 @@ func (r *WarrantyClaimRepositoryImpl) GetByClaimNumber(ctx context.Context, clai
 	return &claim, nil
 }
 
+// GetByTrackingNumber retrieves the most recent warranty claim shipped with the given tracking number
+func (r *WarrantyClaimRepositoryImpl) GetByTrackingNumber(ctx context.Context, trackingNumber string) (*entity.WarrantyClaim, error) {
+	query := `
+		SELECT * FROM warranty_claims
+		WHERE tracking_number = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	var claim entity.WarrantyClaim
+	err := r.db.GetContext(ctx, &claim, query, trackingNumber)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Str("tracking_number", trackingNumber).Msg("Failed to get warranty claim by tracking number")
+		return nil, fmt.Errorf("failed to get warranty claim by tracking number: %w", err)
+	}
+
+	claim.ComputeFields()
+	return &claim, nil
+}
+
 // GetByBarcodeID retrieves warranty claims for a specific barcode
 func (r *WarrantyClaimRepositoryImpl) GetByBarcodeID(ctx context.Context, barcodeID uuid.UUID) ([]*entity.WarrantyClaim, error) {
 	query := `SELECT * FROM warranty_claims WHERE barcode_id = $1 AND deleted_at IS NULL ORDER BY created_at DESC`
@@ -331,6 +358,8 @@ func (r *WarrantyClaimRepositoryImpl) Update(ctx context.Context, claim *entity.
 			replacement_product_id = :replacement_product_id,
 			refund_amount = :refund_amount,
 			repair_cost = :repair_cost,
+			repair_labor_cost = :repair_labor_cost,
+			repair_parts_cost = :repair_parts_cost,
 			shipping_cost = :shipping_cost,
 			replacement_cost = :replacement_cost,
 			total_cost = :total_cost,
@@ -350,10 +379,18 @@ func (r *WarrantyClaimRepositoryImpl) Update(ctx context.Context, claim *entity.
 			customer_satisfaction_rating = :customer_satisfaction_rating,
 			customer_feedback = :customer_feedback,
 			processing_time_hours = :processing_time_hours,
+			sla_policy_hours = :sla_policy_hours,
+			sla_due_at = :sla_due_at,
+			sla_at_risk_at = :sla_at_risk_at,
+			sla_paused_at = :sla_paused_at,
+			sla_paused_seconds = :sla_paused_seconds,
+			sla_stopped_at = :sla_stopped_at,
+			sla_escalated_at = :sla_escalated_at,
+			sla_breached_at = :sla_breached_at,
 			updated_at = :updated_at
 		WHERE id = :id AND storefront_id = :storefront_id AND deleted_at IS NULL`
 
-	result, err := db.NamedExecContext(ctx, query, claim)
+	result, err := executorFromContext(ctx, db).NamedExecContext(ctx, query, claim)
 	if err != nil {
 		r.logger.Error().Err(err).Str("id", claim.ID.String()).Msg("Failed to update warranty claim")
 		return fmt.Errorf("failed to update warranty claim: %w", err)
@@ -372,6 +409,119 @@ func (r *WarrantyClaimRepositoryImpl) Update(ctx context.Context, claim *entity.
 	return nil
 }
 
+// UpdateSLA updates only the SLA tracking fields of a warranty claim, so
+// pausing or resuming the clock does not overwrite concurrent edits to the claim
+func (r *WarrantyClaimRepositoryImpl) UpdateSLA(ctx context.Context, claim *entity.WarrantyClaim) error {
+	db, err := r.GetDB(ctx, claim.StorefrontID)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	query := `
+		UPDATE warranty_claims SET
+			sla_policy_hours = :sla_policy_hours,
+			sla_due_at = :sla_due_at,
+			sla_at_risk_at = :sla_at_risk_at,
+			sla_paused_at = :sla_paused_at,
+			sla_paused_seconds = :sla_paused_seconds,
+			sla_stopped_at = :sla_stopped_at,
+			sla_escalated_at = :sla_escalated_at,
+			sla_breached_at = :sla_breached_at
+		WHERE id = :id AND storefront_id = :storefront_id AND deleted_at IS NULL`
+
+	result, err := executorFromContext(ctx, db).NamedExecContext(ctx, query, claim)
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", claim.ID.String()).Msg("Failed to update warranty claim SLA")
+		return fmt.Errorf("failed to update warranty claim SLA: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("warranty claim not found or not updated")
+	}
+
+	return nil
+}
+
+// ClaimSLAEscalation sets sla_escalated_at only while the claim still needs
+// escalating, so that of several workers sweeping the same claim one alerts
+func (r *WarrantyClaimRepositoryImpl) ClaimSLAEscalation(ctx context.Context, claim *entity.WarrantyClaim, at time.Time) (bool, error) {
+	query := `
+		UPDATE warranty_claims SET sla_escalated_at = $1
+		WHERE id = $2 AND storefront_id = $3 AND deleted_at IS NULL
+			AND sla_escalated_at IS NULL
+			AND sla_stopped_at IS NULL
+			AND sla_paused_at IS NULL
+			AND sla_at_risk_at <= $1
+		RETURNING id`
+
+	return r.claimSLAColumn(ctx, claim, "escalation", query, at, claim.ID, claim.StorefrontID)
+}
+
+// ClaimSLABreach sets sla_breached_at to the claim's current due-by only while
+// the breach is unrecorded, so that of several workers one alerts
+func (r *WarrantyClaimRepositoryImpl) ClaimSLABreach(ctx context.Context, claim *entity.WarrantyClaim, at time.Time) (bool, error) {
+	query := `
+		UPDATE warranty_claims SET sla_breached_at = sla_due_at
+		WHERE id = $2 AND storefront_id = $3 AND deleted_at IS NULL
+			AND sla_breached_at IS NULL
+			AND sla_stopped_at IS NULL
+			AND sla_paused_at IS NULL
+			AND sla_due_at <= $1
+		RETURNING id`
+
+	return r.claimSLAColumn(ctx, claim, "breach", query, at, claim.ID, claim.StorefrontID)
+}
+
+// claimSLAColumn runs a conditional SLA update and reports whether it applied
+func (r *WarrantyClaimRepositoryImpl) claimSLAColumn(ctx context.Context, claim *entity.WarrantyClaim, event, query string, args ...interface{}) (bool, error) {
+	db, err := r.GetDB(ctx, claim.StorefrontID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var id uuid.UUID
+	if err := executorFromContext(ctx, db).GetContext(ctx, &id, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		r.logger.Error().Err(err).Str("id", claim.ID.String()).Msgf("Failed to record warranty claim SLA %s", event)
+		return false, fmt.Errorf("failed to record warranty claim SLA %s: %w", event, err)
+	}
+
+	return true, nil
+}
+
+// GetSLAEscalationCandidates retrieves open claims of all storefronts whose
+// SLA is at risk and not yet escalated, or breached and not yet recorded
+func (r *WarrantyClaimRepositoryImpl) GetSLAEscalationCandidates(ctx context.Context, now time.Time, limit int) ([]*entity.WarrantyClaim, error) {
+	// Like GetByID, this searches the shared database across all storefronts
+	query := `
+		SELECT * FROM warranty_claims
+		WHERE deleted_at IS NULL
+			AND sla_due_at IS NOT NULL
+			AND sla_stopped_at IS NULL
+			AND sla_paused_at IS NULL
+			AND (
+				(sla_escalated_at IS NULL AND sla_at_risk_at <= $1)
+				OR (sla_breached_at IS NULL AND sla_due_at <= $1)
+			)
+		ORDER BY sla_due_at ASC
+		LIMIT $2`
+
+	var claims []*entity.WarrantyClaim
+	if err := r.db.SelectContext(ctx, &claims, query, now, limit); err != nil {
+		r.logger.Error().Err(err).Msg("Failed to get SLA escalation candidates")
+		return nil, fmt.Errorf("failed to get SLA escalation candidates: %w", err)
+	}
+
+	return claims, nil
+}
+
 // UpdateStatus updates the status of a warranty claim
 func (r *WarrantyClaimRepositoryImpl) UpdateStatus(ctx context.Context, id uuid.UUID, status entity.ClaimStatus, updatedBy uuid.UUID, notes string) error {
 	// First get the claim to determine storefront
@@ -655,9 +805,35 @@ func (r *WarrantyClaimRepositoryImpl) applyFilters(qb QueryBuilder, filters *rep
 		`, entity.ClaimStatusCompleted, entity.ClaimStatusCancelled, entity.ClaimStatusRejected, entity.ClaimStatusDelivered)
 	}
 
+	if filters.SLAState != nil {
+		qb = qb.Where(claimSLAStateCondition(*filters.SLAState))
+	}
+
 	return qb
 }
 
+// claimSLAStateCondition returns the WHERE condition matching claims in an
+// SLA state, mirroring entity.WarrantyClaim.SLAState
+func claimSLAStateCondition(state entity.ClaimSLAState) string {
+	const open = "sla_due_at IS NOT NULL AND sla_stopped_at IS NULL AND sla_breached_at IS NULL"
+	switch state {
+	case entity.ClaimSLAStateOnTrack:
+		return open + " AND sla_paused_at IS NULL AND sla_at_risk_at > NOW()"
+	case entity.ClaimSLAStateAtRisk:
+		return open + " AND sla_paused_at IS NULL AND sla_at_risk_at <= NOW() AND sla_due_at > NOW()"
+	case entity.ClaimSLAStatePaused:
+		return open + " AND sla_paused_at IS NOT NULL"
+	case entity.ClaimSLAStateBreached:
+		return `(sla_breached_at IS NOT NULL OR (
+			sla_due_at <= NOW() AND sla_stopped_at IS NULL AND sla_paused_at IS NULL
+		))`
+	case entity.ClaimSLAStateMet:
+		return "sla_due_at IS NOT NULL AND sla_stopped_at IS NOT NULL AND sla_breached_at IS NULL"
+	default:
+		return "FALSE"
+	}
+}
+
 // GetClaimsByDateRange retrieves claims within a date range
 func (r *WarrantyClaimRepositoryImpl) GetClaimsByDateRange(ctx context.Context, storefrontID uuid.UUID, startDate, endDate time.Time, limit, offset int) ([]*entity.WarrantyClaim, error) {
 	db, err := r.GetDB(ctx, storefrontID)
@@ -715,7 +891,11 @@ func (r *WarrantyClaimRepositoryImpl) GetClaimStatistics(ctx context.Context, st
 			AVG(CASE WHEN customer_satisfaction_rating IS NOT NULL THEN customer_satisfaction_rating END) as avg_satisfaction,
 			SUM(repair_cost) as total_repair_cost,
 			SUM(shipping_cost) as total_shipping_cost,
-			SUM(replacement_cost) as total_replacement_cost
+			SUM(replacement_cost) as total_replacement_cost,
+			COUNT(CASE WHEN sla_due_at IS NOT NULL AND sla_stopped_at IS NOT NULL THEN 1 END) as sla_resolved_claims,
+			COUNT(CASE WHEN sla_due_at IS NOT NULL AND sla_stopped_at IS NOT NULL AND sla_breached_at IS NULL THEN 1 END) as sla_met_claims,
+			COUNT(CASE WHEN ` + claimSLAStateCondition(entity.ClaimSLAStateAtRisk) + ` THEN 1 END) as sla_at_risk_claims,
+			COUNT(CASE WHEN ` + claimSLAStateCondition(entity.ClaimSLAStateBreached) + ` THEN 1 END) as sla_breached_claims
 		FROM warranty_claims 
 		WHERE storefront_id = $1 
 			AND created_at >= $2 
@@ -724,6 +904,7 @@ func (r *WarrantyClaimRepositoryImpl) GetClaimStatistics(ctx context.Context, st
 
 	var stats repository.ClaimStatistics
 	var avgResolutionTime, avgRepairCost, avgSatisfaction sql.NullFloat64
+	var slaResolvedClaims, slaMetClaims int64
 
 	row := db.QueryRowContext(ctx, query, storefrontID, startDate, endDate)
 	err = row.Scan(
@@ -739,6 +920,10 @@ func (r *WarrantyClaimRepositoryImpl) GetClaimStatistics(ctx context.Context, st
 		&stats.TotalRepairCost,
 		&stats.TotalShippingCost,
 		&stats.TotalReplacementCost,
+		&slaResolvedClaims,
+		&slaMetClaims,
+		&stats.SLAAtRiskClaims,
+		&stats.SLABreachedClaims,
 	)
 	if err != nil {
 		r.logger.Error().Err(err).Msg("Failed to get claim statistics")
@@ -754,6 +939,9 @@ func (r *WarrantyClaimRepositoryImpl) GetClaimStatistics(ctx context.Context, st
 	if avgSatisfaction.Valid {
 		stats.CustomerSatisfactionAvg = avgSatisfaction.Float64
 	}
+	if slaResolvedClaims > 0 {
+		stats.SLAComplianceRate = float64(slaMetClaims) / float64(slaResolvedClaims) * 100
+	}
 
 	// Initialize maps
 	stats.ClaimsByCategory = make(map[string]int64)
@@ -854,12 +1042,17 @@ func (r *WarrantyClaimRepositoryImpl) AddTimelineEntry(ctx context.Context, entr
 	return nil
 }
 
+// claimAttachmentColumns lists the stored claim attachment columns
+const claimAttachmentColumns = `
+	id, claim_id, uploaded_by, filename, original_filename, file_path, file_url,
+	file_size, mime_type, attachment_type, description, is_processed, processing_notes,
+	checksum, virus_scan_status, virus_scan_date, uploaded_at`
+
 // GetClaimAttachments retrieves all attachments for a claim
 func (r *WarrantyClaimRepositoryImpl) GetClaimAttachments(ctx context.Context, claimID uuid.UUID) ([]*entity.ClaimAttachment, error) {
-	query := `
-		SELECT * FROM claim_attachments 
+	query := `SELECT ` + claimAttachmentColumns + ` FROM claim_attachments 
 		WHERE claim_id = $1 AND deleted_at IS NULL 
-		ORDER BY created_at DESC`
+		ORDER BY uploaded_at DESC`
 
 	var attachments []*entity.ClaimAttachment
 	err := r.db.SelectContext(ctx, &attachments, query, claimID)
@@ -868,20 +1061,58 @@ func (r *WarrantyClaimRepositoryImpl) GetClaimAttachments(ctx context.Context, c
 		return nil, fmt.Errorf("failed to get claim attachments: %w", err)
 	}
 
+	for _, attachment := range attachments {
+		attachment.ComputeFields()
+	}
+	return attachments, nil
+}
+
+// GetClaimAttachment retrieves an attachment by its ID
+func (r *WarrantyClaimRepositoryImpl) GetClaimAttachment(ctx context.Context, attachmentID uuid.UUID) (*entity.ClaimAttachment, error) {
+	query := `SELECT ` + claimAttachmentColumns + ` FROM claim_attachments WHERE id = $1 AND deleted_at IS NULL`
+
+	var attachment entity.ClaimAttachment
+	err := r.db.GetContext(ctx, &attachment, query, attachmentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Str("id", attachmentID.String()).Msg("Failed to get claim attachment")
+		return nil, fmt.Errorf("failed to get claim attachment: %w", err)
+	}
+
+	attachment.ComputeFields()
+	return &attachment, nil
+}
+
+// GetAttachmentsByScanStatus retrieves the oldest attachments with a virus scan status
+func (r *WarrantyClaimRepositoryImpl) GetAttachmentsByScanStatus(ctx context.Context, status entity.VirusScanStatus, limit int) ([]*entity.ClaimAttachment, error) {
+	query := `SELECT ` + claimAttachmentColumns + ` FROM claim_attachments
+		WHERE virus_scan_status = $1 AND deleted_at IS NULL
+		ORDER BY uploaded_at ASC
+		LIMIT $2`
+
+	var attachments []*entity.ClaimAttachment
+	err := r.db.SelectContext(ctx, &attachments, query, status, limit)
+	if err != nil {
+		r.logger.Error().Err(err).Str("status", string(status)).Msg("Failed to get claim attachments by scan status")
+		return nil, fmt.Errorf("failed to get claim attachments by scan status: %w", err)
+	}
+
+	for _, attachment := range attachments {
+		attachment.ComputeFields()
+	}
 	return attachments, nil
 }
 
 // AddClaimAttachment adds a new attachment to a claim
 func (r *WarrantyClaimRepositoryImpl) AddClaimAttachment(ctx context.Context, attachment *entity.ClaimAttachment) error {
 	query := `
-		INSERT INTO claim_attachments (
-			id, claim_id, file_name, file_path, file_url, file_size, file_type,
-			mime_type, attachment_type, description, uploaded_by, security_scan_status,
-			security_scan_result, created_at, updated_at
+		INSERT INTO claim_attachments (` + claimAttachmentColumns + `
 		) VALUES (
-			:id, :claim_id, :file_name, :file_path, :file_url, :file_size, :file_type,
-			:mime_type, :attachment_type, :description, :uploaded_by, :security_scan_status,
-			:security_scan_result, :created_at, :updated_at
+			:id, :claim_id, :uploaded_by, :filename, :original_filename, :file_path, :file_url,
+			:file_size, :mime_type, :attachment_type, :description, :is_processed, :processing_notes,
+			:checksum, :virus_scan_status, :virus_scan_date, :uploaded_at
 		)`
 
 	_, err := r.db.NamedExecContext(ctx, query, attachment)
@@ -901,6 +1132,7 @@ func (r *WarrantyClaimRepositoryImpl) UpdateClaimAttachment(ctx context.Context,
 			file_path = :file_path,
 			file_url = :file_url,
 			description = :description,
+			checksum = :checksum,
 			virus_scan_status = :virus_scan_status,
 			virus_scan_date = :virus_scan_date,
 			is_processed = :is_processed,
@@ -928,9 +1160,9 @@ func (r *WarrantyClaimRepositoryImpl) UpdateClaimAttachment(ctx context.Context,
 // DeleteClaimAttachment deletes a claim attachment
 func (r *WarrantyClaimRepositoryImpl) DeleteClaimAttachment(ctx context.Context, attachmentID uuid.UUID) error {
 	now := time.Now()
-	query := `UPDATE claim_attachments SET deleted_at = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL`
+	query := `UPDATE claim_attachments SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
 
-	result, err := r.db.ExecContext(ctx, query, now, now, attachmentID)
+	result, err := r.db.ExecContext(ctx, query, now, attachmentID)
 	if err != nil {
 		r.logger.Error().Err(err).Str("id", attachmentID.String()).Msg("Failed to delete claim attachment")
 		return fmt.Errorf("failed to delete claim attachment: %w", err)
@@ -948,12 +1180,39 @@ func (r *WarrantyClaimRepositoryImpl) DeleteClaimAttachment(ctx context.Context,
 	return nil
 }
 
+// repairTicketColumns selects a repair ticket with the claim and technician
+// it is shown with. Costs are generated columns, NULL only on legacy rows.
+const repairTicketColumns = `
+	rt.id, rt.ticket_number, rt.claim_id, rt.priority, rt.estimated_hours, rt.description,
+	rt.required_parts, rt.special_instructions, rt.technician_id, rt.assigned_at, rt.start_date,
+	rt.target_completion_date, rt.actual_completion_date, rt.status, rt.diagnosis, rt.repair_steps,
+	rt.parts_used, COALESCE(rt.labor_hours, 0) AS labor_hours, rt.hourly_rate,
+	COALESCE(rt.parts_cost, 0) AS parts_cost, COALESCE(rt.labor_cost, 0) AS labor_cost,
+	COALESCE(rt.total_cost, 0) AS total_cost,
+	rt.quality_check_passed, rt.quality_notes, rt.quality_checked_by, rt.quality_checked_at,
+	rt.test_results, rt.before_photos, rt.after_photos, rt.process_photos,
+	rt.technician_notes, rt.supervisor_notes, rt.created_by, rt.created_at, rt.updated_at,
+	wc.storefront_id, wc.claim_number, u.name AS technician_name`
+
+// repairTicketJoins joins a repair ticket to its claim and technician
+const repairTicketJoins = `
+	FROM repair_tickets rt
+	JOIN warranty_claims wc ON wc.id = rt.claim_id
+	LEFT JOIN users u ON u.id = rt.technician_id`
+
+// repairTicketSortColumns lists the columns repair tickets can be sorted by
+var repairTicketSortColumns = map[string]string{
+	"created_at":             "rt.created_at",
+	"priority":               "rt.priority",
+	"status":                 "rt.status",
+	"target_completion_date": "rt.target_completion_date",
+}
+
 // GetRepairTickets retrieves repair tickets for a claim
 func (r *WarrantyClaimRepositoryImpl) GetRepairTickets(ctx context.Context, claimID uuid.UUID) ([]*entity.RepairTicket, error) {
-	query := `
-		SELECT * FROM repair_tickets 
-		WHERE claim_id = $1 AND deleted_at IS NULL 
-		ORDER BY created_at DESC`
+	query := `SELECT ` + repairTicketColumns + repairTicketJoins + `
+		WHERE rt.claim_id = $1
+		ORDER BY rt.created_at DESC`
 
 	var tickets []*entity.RepairTicket
 	err := r.db.SelectContext(ctx, &tickets, query, claimID)
@@ -962,68 +1221,183 @@ func (r *WarrantyClaimRepositoryImpl) GetRepairTickets(ctx context.Context, clai
 		return nil, fmt.Errorf("failed to get repair tickets: %w", err)
 	}
 
+	for _, ticket := range tickets {
+		ticket.ComputeFields()
+	}
 	return tickets, nil
 }
 
+// GetRepairTicketsWithFilters retrieves a storefront's repair tickets with filters and pagination
+func (r *WarrantyClaimRepositoryImpl) GetRepairTicketsWithFilters(ctx context.Context, filters *repository.RepairTicketFilters) ([]*entity.RepairTicket, error) {
+	where, args := repairTicketFilterConditions(filters)
+
+	sortBy, ok := repairTicketSortColumns[filters.SortBy]
+	if !ok {
+		sortBy = "rt.created_at"
+	}
+	sortDirection := "DESC"
+	if strings.EqualFold(filters.SortDirection, "asc") {
+		sortDirection = "ASC"
+	}
+
+	query := `SELECT ` + repairTicketColumns + repairTicketJoins + `
+		WHERE ` + where + `
+		ORDER BY ` + sortBy + ` ` + sortDirection
+	if filters.PageSize > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filters.PageSize)
+		if filters.Page > 1 {
+			query += fmt.Sprintf(" OFFSET %d", (filters.Page-1)*filters.PageSize)
+		}
+	}
+
+	var tickets []*entity.RepairTicket
+	err := r.db.SelectContext(ctx, &tickets, query, args...)
+	if err != nil {
+		r.logger.Error().Err(err).Str("storefront_id", filters.StorefrontID.String()).Msg("Failed to get repair tickets with filters")
+		return nil, fmt.Errorf("failed to get repair tickets with filters: %w", err)
+	}
+
+	for _, ticket := range tickets {
+		ticket.ComputeFields()
+	}
+	return tickets, nil
+}
+
+// CountRepairTickets counts a storefront's repair tickets with filters
+func (r *WarrantyClaimRepositoryImpl) CountRepairTickets(ctx context.Context, filters *repository.RepairTicketFilters) (int, error) {
+	where, args := repairTicketFilterConditions(filters)
+	query := `SELECT COUNT(*)` + repairTicketJoins + ` WHERE ` + where
+
+	var count int
+	err := r.db.GetContext(ctx, &count, query, args...)
+	if err != nil {
+		r.logger.Error().Err(err).Str("storefront_id", filters.StorefrontID.String()).Msg("Failed to count repair tickets")
+		return 0, fmt.Errorf("failed to count repair tickets: %w", err)
+	}
+
+	return count, nil
+}
+
+// repairTicketFilterConditions builds the WHERE conditions for repair ticket filters
+func repairTicketFilterConditions(filters *repository.RepairTicketFilters) (string, []interface{}) {
+	conditions := []string{"wc.storefront_id = $1", "wc.deleted_at IS NULL"}
+	args := []interface{}{filters.StorefrontID}
+	argIndex := 2
+
+	if filters.ClaimID != nil {
+		conditions = append(conditions, fmt.Sprintf("rt.claim_id = $%d", argIndex))
+		args = append(args, *filters.ClaimID)
+		argIndex++
+	}
+
+	if filters.TechnicianID != nil {
+		conditions = append(conditions, fmt.Sprintf("rt.technician_id = $%d", argIndex))
+		args = append(args, *filters.TechnicianID)
+		argIndex++
+	}
+
+	if len(filters.Statuses) > 0 {
+		statuses := make([]string, len(filters.Statuses))
+		for i, status := range filters.Statuses {
+			statuses[i] = string(status)
+		}
+		conditions = append(conditions, fmt.Sprintf("rt.status = ANY($%d)", argIndex))
+		args = append(args, pq.Array(statuses))
+		argIndex++
+	}
+
+	if len(filters.Priorities) > 0 {
+		priorities := make([]string, len(filters.Priorities))
+		for i, priority := range filters.Priorities {
+			priorities[i] = string(priority)
+		}
+		conditions = append(conditions, fmt.Sprintf("rt.priority = ANY($%d)", argIndex))
+		args = append(args, pq.Array(priorities))
+		argIndex++
+	}
+
+	if filters.Search != nil && *filters.Search != "" {
+		conditions = append(conditions, fmt.Sprintf(`(
+			rt.ticket_number ILIKE $%[1]d OR
+			wc.claim_number ILIKE $%[1]d OR
+			rt.description ILIKE $%[1]d OR
+			rt.diagnosis ILIKE $%[1]d
+		)`, argIndex))
+		args = append(args, "%"+*filters.Search+"%")
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
 // CreateRepairTicket creates a new repair ticket for a claim
 func (r *WarrantyClaimRepositoryImpl) CreateRepairTicket(ctx context.Context, ticket *entity.RepairTicket) error {
 	query := `
 		INSERT INTO repair_tickets (
-			id, ticket_number, claim_id, assigned_technician_id, repair_center_id,
-			repair_type, issue_diagnosis, repair_steps, parts_required, labor_hours_estimated,
-			labor_hours_actual, repair_cost, parts_cost, total_cost, status, priority,
-			estimated_completion_date, actual_completion_date, quality_check_passed,
-			quality_check_notes, customer_approval_required, customer_approved,
-			technician_notes, internal_notes, created_at, updated_at
+			id, ticket_number, claim_id, priority, estimated_hours, description,
+			required_parts, special_instructions, technician_id, assigned_at, start_date,
+			target_completion_date, actual_completion_date, status, diagnosis, repair_steps,
+			parts_used, labor_hours, hourly_rate, parts_cost,
+			quality_check_passed, quality_notes, quality_checked_by, quality_checked_at,
+			test_results, before_photos, after_photos, process_photos,
+			technician_notes, supervisor_notes, created_by, created_at, updated_at
 		) VALUES (
-			:id, :ticket_number, :claim_id, :assigned_technician_id, :repair_center_id,
-			:repair_type, :issue_diagnosis, :repair_steps, :parts_required, :labor_hours_estimated,
-			:labor_hours_actual, :repair_cost, :parts_cost, :total_cost, :status, :priority,
-			:estimated_completion_date, :actual_completion_date, :quality_check_passed,
-			:quality_check_notes, :customer_approval_required, :customer_approved,
-			:technician_notes, :internal_notes, :created_at, :updated_at
+			:id, :ticket_number, :claim_id, :priority, :estimated_hours, :description,
+			:required_parts, :special_instructions, :technician_id, :assigned_at, :start_date,
+			:target_completion_date, :actual_completion_date, :status, :diagnosis, :repair_steps,
+			:parts_used, :labor_hours, :hourly_rate, :parts_cost,
+			:quality_check_passed, :quality_notes, :quality_checked_by, :quality_checked_at,
+			:test_results, :before_photos, :after_photos, :process_photos,
+			:technician_notes, :supervisor_notes, :created_by, :created_at, :updated_at
 		)`
 
-	_, err := r.db.NamedExecContext(ctx, query, ticket)
+	_, err := executorFromContext(ctx, r.db).NamedExecContext(ctx, query, ticket)
 	if err != nil {
 		r.logger.Error().Err(err).Str("claim_id", ticket.ClaimID.String()).Msg("Failed to create repair ticket")
 		return fmt.Errorf("failed to create repair ticket: %w", err)
 	}
 
+	r.logger.Info().Str("ticket_number", ticket.TicketNumber).Str("id", ticket.ID.String()).Msg("Repair ticket created")
 	return nil
 }
 
-// UpdateRepairTicket updates an existing repair ticket
+// UpdateRepairTicket updates an existing repair ticket. Labor and total cost
+// are generated by the database from the hours, rate and parts cost.
 func (r *WarrantyClaimRepositoryImpl) UpdateRepairTicket(ctx context.Context, ticket *entity.RepairTicket) error {
 	ticket.UpdatedAt = time.Now()
 
 	query := `
-		UPDATE repair_tickets SET 
-			assigned_technician_id = :assigned_technician_id,
-			repair_center_id = :repair_center_id,
-			repair_type = :repair_type,
-			issue_diagnosis = :issue_diagnosis,
-			repair_steps = :repair_steps,
-			parts_required = :parts_required,
-			labor_hours_estimated = :labor_hours_estimated,
-			labor_hours_actual = :labor_hours_actual,
-			repair_cost = :repair_cost,
-			parts_cost = :parts_cost,
-			total_cost = :total_cost,
-			status = :status,
+		UPDATE repair_tickets SET
 			priority = :priority,
-			estimated_completion_date = :estimated_completion_date,
+			estimated_hours = :estimated_hours,
+			description = :description,
+			required_parts = :required_parts,
+			special_instructions = :special_instructions,
+			technician_id = :technician_id,
+			assigned_at = :assigned_at,
+			start_date = :start_date,
+			target_completion_date = :target_completion_date,
 			actual_completion_date = :actual_completion_date,
+			status = :status,
+			diagnosis = :diagnosis,
+			repair_steps = :repair_steps,
+			parts_used = :parts_used,
+			labor_hours = :labor_hours,
+			hourly_rate = :hourly_rate,
+			parts_cost = :parts_cost,
 			quality_check_passed = :quality_check_passed,
-			quality_check_notes = :quality_check_notes,
-			customer_approval_required = :customer_approval_required,
-			customer_approved = :customer_approved,
+			quality_notes = :quality_notes,
+			quality_checked_by = :quality_checked_by,
+			quality_checked_at = :quality_checked_at,
+			test_results = :test_results,
+			before_photos = :before_photos,
+			after_photos = :after_photos,
+			process_photos = :process_photos,
 			technician_notes = :technician_notes,
-			internal_notes = :internal_notes,
+			supervisor_notes = :supervisor_notes,
 			updated_at = :updated_at
-		WHERE id = :id AND deleted_at IS NULL`
+		WHERE id = :id`
 
-	result, err := r.db.NamedExecContext(ctx, query, ticket)
+	result, err := executorFromContext(ctx, r.db).NamedExecContext(ctx, query, ticket)
 	if err != nil {
 		r.logger.Error().Err(err).Str("id", ticket.ID.String()).Msg("Failed to update repair ticket")
 		return fmt.Errorf("failed to update repair ticket: %w", err)
@@ -1043,7 +1417,7 @@ func (r *WarrantyClaimRepositoryImpl) UpdateRepairTicket(ctx context.Context, ti
 
 // GetRepairTicketByID retrieves a repair ticket by its ID
 func (r *WarrantyClaimRepositoryImpl) GetRepairTicketByID(ctx context.Context, ticketID uuid.UUID) (*entity.RepairTicket, error) {
-	query := `SELECT * FROM repair_tickets WHERE id = $1 AND deleted_at IS NULL`
+	query := `SELECT ` + repairTicketColumns + repairTicketJoins + ` WHERE rt.id = $1`
 
 	var ticket entity.RepairTicket
 	err := r.db.GetContext(ctx, &ticket, query, ticketID)
@@ -1055,5 +1429,96 @@ func (r *WarrantyClaimRepositoryImpl) GetRepairTicketByID(ctx context.Context, t
 		return nil, fmt.Errorf("failed to get repair ticket by ID: %w", err)
 	}
 
+	ticket.ComputeFields()
 	return &ticket, nil
 }
+
+// GenerateRepairTicketNumber generates a unique repair ticket number
+func (r *WarrantyClaimRepositoryImpl) GenerateRepairTicketNumber(ctx context.Context, storefrontID uuid.UUID) (string, error) {
+	// Generate format: RPR-YYYY-NNNNNN
+	year := time.Now().Year()
+
+	// Get the next sequence number for this year and storefront
+	query := `
+		SELECT COALESCE(MAX(
+			CASE WHEN rt.ticket_number ~ ('^RPR-' || $1 || '-[0-9]{6}$')
+			THEN CAST(SUBSTRING(rt.ticket_number FROM 10) AS INTEGER)
+			ELSE 0 END
+		), 0) + 1
+		FROM repair_tickets rt
+		JOIN warranty_claims wc ON wc.id = rt.claim_id
+		WHERE wc.storefront_id = $2`
+
+	var nextNum int
+	err := r.db.GetContext(ctx, &nextNum, query, strconv.Itoa(year), storefrontID)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("Failed to get next repair ticket number")
+		return "", fmt.Errorf("failed to get next repair ticket number: %w", err)
+	}
+
+	return fmt.Sprintf("RPR-%d-%06d", year, nextNum), nil
+}
+
+// GetRepairTicketStatistics retrieves repair turnaround, cost and quality
+// statistics for tickets created in a date range, overall and per technician
+func (r *WarrantyClaimRepositoryImpl) GetRepairTicketStatistics(ctx context.Context, storefrontID uuid.UUID, startDate, endDate time.Time) (*repository.RepairTicketStatistics, error) {
+	const aggregates = `
+		COUNT(*) AS assigned_tickets,
+		COUNT(*) FILTER (WHERE rt.status = 'completed') AS completed_tickets,
+		COALESCE(AVG(EXTRACT(EPOCH FROM (rt.actual_completion_date - rt.assigned_at)) / 3600)
+			FILTER (WHERE rt.status = 'completed'), 0) AS average_turnaround_hours,
+		COALESCE(SUM(rt.labor_cost) FILTER (WHERE rt.status = 'completed'), 0) AS total_labor_cost,
+		COALESCE(SUM(rt.parts_cost) FILTER (WHERE rt.status = 'completed'), 0) AS total_parts_cost,
+		COUNT(*) FILTER (WHERE rt.quality_check_passed IS NOT NULL) AS quality_checked_tickets,
+		COUNT(*) FILTER (WHERE rt.quality_check_passed) AS quality_passed_tickets`
+	const scope = `
+		FROM repair_tickets rt
+		JOIN warranty_claims wc ON wc.id = rt.claim_id
+		LEFT JOIN users u ON u.id = rt.technician_id
+		WHERE wc.storefront_id = $1 AND rt.created_at >= $2 AND rt.created_at <= $3`
+
+	var stats repository.RepairTicketStatistics
+	row := r.db.QueryRowxContext(ctx, `SELECT `+aggregates+scope, storefrontID, startDate, endDate)
+	err := row.Scan(
+		&stats.TotalTickets,
+		&stats.CompletedTickets,
+		&stats.AverageTurnaroundHours,
+		&stats.TotalLaborCost,
+		&stats.TotalPartsCost,
+		&stats.QualityCheckedTickets,
+		&stats.QualityPassedTickets,
+	)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("Failed to get repair ticket statistics")
+		return nil, fmt.Errorf("failed to get repair ticket statistics: %w", err)
+	}
+
+	stats.TicketsByStatus = make(map[string]int64)
+	rows, err := r.db.QueryContext(ctx, `SELECT rt.status, COUNT(*)`+scope+` GROUP BY rt.status`, storefrontID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repair tickets by status: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status string
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			continue
+		}
+		stats.TicketsByStatus[status] = count
+	}
+
+	technicianQuery := `
+		SELECT rt.technician_id, COALESCE(u.name, '') AS technician_name,` + aggregates + scope + `
+		GROUP BY rt.technician_id, u.name
+		ORDER BY completed_tickets DESC, technician_name`
+
+	err = r.db.SelectContext(ctx, &stats.Technicians, technicianQuery, storefrontID, startDate, endDate)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("Failed to get repair statistics per technician")
+		return nil, fmt.Errorf("failed to get repair statistics per technician: %w", err)
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/tenant"
+	"github.com/rs/zerolog"
+)
+
+// warrantyPolicyColumns lists the columns selected for a warranty policy.
+// Columns with database defaults are nullable, so they are coalesced.
+const warrantyPolicyColumns = `
+	id, name, description, storefront_id, product_id, product_category_id,
+	COALESCE(is_default, FALSE) AS is_default, warranty_period_months, coverage_type,
+	terms_and_conditions, exclusions, coverage_limitations,
+	COALESCE(requires_purchase_receipt, TRUE) AS requires_purchase_receipt,
+	COALESCE(max_claims_per_product, 3) AS max_claims_per_product,
+	COALESCE(claim_processing_sla_hours, 72) AS claim_processing_sla_hours,
+	COALESCE(is_active, TRUE) AS is_active, effective_from, effective_until,
+	created_by, created_at, updated_at`
+
+// WarrantyPolicyRepositoryImpl implements the WarrantyPolicyRepository interface
+type WarrantyPolicyRepositoryImpl struct {
+	*BaseRepository
+	logger zerolog.Logger
+}
+
+// NewWarrantyPolicyRepository creates a new warranty policy repository
+func NewWarrantyPolicyRepository(
+	db *sqlx.DB,
+	tenantResolver tenant.TenantResolver,
+	logger zerolog.Logger,
+) repository.WarrantyPolicyRepository {
+	return &WarrantyPolicyRepositoryImpl{
+		BaseRepository: NewBaseRepository(db, tenantResolver),
+		logger:         logger.With().Str("repository", "warranty_policy").Logger(),
+	}
+}
+
+// Create creates a new warranty policy
+func (r *WarrantyPolicyRepositoryImpl) Create(ctx context.Context, policy *entity.WarrantyPolicyTemplate) error {
+	db, err := r.GetWriteDB(ctx, policy.StorefrontID)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	query := `
+		INSERT INTO warranty_policy_templates (
+			id, name, description, storefront_id, product_id, product_category_id, is_default,
+			warranty_period_months, coverage_type, terms_and_conditions, exclusions, coverage_limitations,
+			requires_purchase_receipt, max_claims_per_product, claim_processing_sla_hours,
+			is_active, effective_from, effective_until, created_by, created_at, updated_at
+		) VALUES (
+			:id, :name, :description, :storefront_id, :product_id, :product_category_id, :is_default,
+			:warranty_period_months, :coverage_type, :terms_and_conditions, :exclusions, :coverage_limitations,
+			:requires_purchase_receipt, :max_claims_per_product, :claim_processing_sla_hours,
+			:is_active, :effective_from, :effective_until, :created_by, :created_at, :updated_at
+		)`
+
+	if _, err := db.NamedExecContext(ctx, query, policy); err != nil {
+		r.logger.Error().Err(err).Str("name", policy.Name).Msg("Failed to create warranty policy")
+		return fmt.Errorf("failed to create warranty policy: %w", err)
+	}
+
+	r.logger.Info().Str("id", policy.ID.String()).Str("scope", string(policy.Scope())).Msg("Warranty policy created")
+	return nil
+}
+
+// GetByID retrieves a warranty policy of a storefront by its ID
+func (r *WarrantyPolicyRepositoryImpl) GetByID(ctx context.Context, storefrontID, id uuid.UUID) (*entity.WarrantyPolicyTemplate, error) {
+	db, err := r.GetDB(ctx, storefrontID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	query := `SELECT` + warrantyPolicyColumns + ` FROM warranty_policy_templates WHERE id = $1 AND storefront_id = $2`
+
+	var policy entity.WarrantyPolicyTemplate
+	if err := db.GetContext(ctx, &policy, query, id, storefrontID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Str("id", id.String()).Msg("Failed to get warranty policy by ID")
+		return nil, fmt.Errorf("failed to get warranty policy by ID: %w", err)
+	}
+
+	return &policy, nil
+}
+
+// Update updates an existing warranty policy
+func (r *WarrantyPolicyRepositoryImpl) Update(ctx context.Context, policy *entity.WarrantyPolicyTemplate) error {
+	db, err := r.GetWriteDB(ctx, policy.StorefrontID)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	policy.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE warranty_policy_templates SET
+			name = :name,
+			description = :description,
+			product_id = :product_id,
+			product_category_id = :product_category_id,
+			is_default = :is_default,
+			warranty_period_months = :warranty_period_months,
+			coverage_type = :coverage_type,
+			terms_and_conditions = :terms_and_conditions,
+			exclusions = :exclusions,
+			coverage_limitations = :coverage_limitations,
+			requires_purchase_receipt = :requires_purchase_receipt,
+			max_claims_per_product = :max_claims_per_product,
+			claim_processing_sla_hours = :claim_processing_sla_hours,
+			is_active = :is_active,
+			effective_from = :effective_from,
+			effective_until = :effective_until,
+			updated_at = :updated_at
+		WHERE id = :id AND storefront_id = :storefront_id`
+
+	result, err := db.NamedExecContext(ctx, query, policy)
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", policy.ID.String()).Msg("Failed to update warranty policy")
+		return fmt.Errorf("failed to update warranty policy: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("warranty policy not found")
+	}
+
+	return nil
+}
+
+// Delete deletes a warranty policy
+func (r *WarrantyPolicyRepositoryImpl) Delete(ctx context.Context, storefrontID, id uuid.UUID) error {
+	db, err := r.GetWriteDB(ctx, storefrontID)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	query := `DELETE FROM warranty_policy_templates WHERE id = $1 AND storefront_id = $2`
+
+	result, err := db.ExecContext(ctx, query, id, storefrontID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("id", id.String()).Msg("Failed to delete warranty policy")
+		return fmt.Errorf("failed to delete warranty policy: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("warranty policy not found")
+	}
+
+	r.logger.Info().Str("id", id.String()).Msg("Warranty policy deleted")
+	return nil
+}
+
+// List retrieves warranty policies with filters and pagination
+func (r *WarrantyPolicyRepositoryImpl) List(ctx context.Context, filters *repository.WarrantyPolicyFilters) ([]*entity.WarrantyPolicyTemplate, error) {
+	db, err := r.GetDB(ctx, filters.StorefrontID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	whereClause, args := buildWarrantyPolicyWhere(filters)
+	query := `SELECT` + warrantyPolicyColumns + ` FROM warranty_policy_templates` + whereClause +
+		` ORDER BY effective_from DESC, created_at DESC`
+
+	if filters.PageSize > 0 {
+		args = append(args, filters.PageSize)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+		if filters.Page > 1 {
+			args = append(args, (filters.Page-1)*filters.PageSize)
+			query += fmt.Sprintf(" OFFSET $%d", len(args))
+		}
+	}
+
+	var policies []*entity.WarrantyPolicyTemplate
+	if err := db.SelectContext(ctx, &policies, query, args...); err != nil {
+		r.logger.Error().Err(err).Msg("Failed to list warranty policies")
+		return nil, fmt.Errorf("failed to list warranty policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+// Count counts warranty policies with filters
+func (r *WarrantyPolicyRepositoryImpl) Count(ctx context.Context, filters *repository.WarrantyPolicyFilters) (int, error) {
+	db, err := r.GetDB(ctx, filters.StorefrontID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	whereClause, args := buildWarrantyPolicyWhere(filters)
+	query := `SELECT COUNT(*) FROM warranty_policy_templates` + whereClause
+
+	var count int
+	if err := db.GetContext(ctx, &count, query, args...); err != nil {
+		r.logger.Error().Err(err).Msg("Failed to count warranty policies")
+		return 0, fmt.Errorf("failed to count warranty policies: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetApplicable retrieves the active policies in effect on the given day
+// that apply to the product, its category or the whole storefront
+func (r *WarrantyPolicyRepositoryImpl) GetApplicable(ctx context.Context, storefrontID, productID uuid.UUID, categoryID *uuid.UUID, on time.Time) ([]*entity.WarrantyPolicyTemplate, error) {
+	db, err := r.GetDB(ctx, storefrontID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	query := `SELECT` + warrantyPolicyColumns + `
+		FROM warranty_policy_templates
+		WHERE storefront_id = $1
+			AND COALESCE(is_active, TRUE)
+			AND effective_from <= $2::date
+			AND (effective_until IS NULL OR effective_until >= $2::date)
+			AND (product_id = $3 OR product_category_id = $4 OR COALESCE(is_default, FALSE))`
+
+	var policies []*entity.WarrantyPolicyTemplate
+	if err := db.SelectContext(ctx, &policies, query, storefrontID, on.Format("2006-01-02"), productID, categoryID); err != nil {
+		r.logger.Error().Err(err).Str("product_id", productID.String()).Msg("Failed to get applicable warranty policies")
+		return nil, fmt.Errorf("failed to get applicable warranty policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+// buildWarrantyPolicyWhere builds the WHERE clause for policy filters
+func buildWarrantyPolicyWhere(filters *repository.WarrantyPolicyFilters) (string, []interface{}) {
+	args := []interface{}{filters.StorefrontID}
+	whereConditions := []string{"storefront_id = $1"}
+
+	if filters.ProductID != nil {
+		args = append(args, *filters.ProductID)
+		whereConditions = append(whereConditions, fmt.Sprintf("product_id = $%d", len(args)))
+	}
+	if filters.ProductCategoryID != nil {
+		args = append(args, *filters.ProductCategoryID)
+		whereConditions = append(whereConditions, fmt.Sprintf("product_category_id = $%d", len(args)))
+	}
+	if filters.IsDefault != nil {
+		args = append(args, *filters.IsDefault)
+		whereConditions = append(whereConditions, fmt.Sprintf("COALESCE(is_default, FALSE) = $%d", len(args)))
+	}
+	if filters.IsActive != nil {
+		args = append(args, *filters.IsActive)
+		whereConditions = append(whereConditions, fmt.Sprintf("COALESCE(is_active, TRUE) = $%d", len(args)))
+	}
+	if filters.CoverageType != nil {
+		args = append(args, *filters.CoverageType)
+		whereConditions = append(whereConditions, fmt.Sprintf("coverage_type = $%d", len(args)))
+	}
+
+	return " WHERE " + strings.Join(whereConditions, " AND "), args
+}
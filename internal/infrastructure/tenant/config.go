@@ -12,6 +12,7 @@ func DefaultTenantConfig() *TenantConfig {
 		SharedDatabaseURL:       "postgres://localhost:5432/smartseller?sslmode=disable",
 		TenantDatabasePattern:   "postgres://localhost:5432/smartseller_tenant_%s?sslmode=disable",
 		MaxConnectionsPerTenant: 10,
+		MigrationBatchSize:      500,
 		MigrationThresholds: MigrationThresholds{
 			SchemaThreshold: struct {
 				CustomerCount int           `yaml:"customer_count"`
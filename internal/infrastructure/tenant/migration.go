@@ -0,0 +1,283 @@
+package tenant
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TenantMigrationStatus represents the state of a tenant migration job
+type TenantMigrationStatus string
+
+const (
+	TenantMigrationStatusPending     TenantMigrationStatus = "pending"      // Created, not started yet
+	TenantMigrationStatusCopying     TenantMigrationStatus = "copying"      // Creating the target and copying rows
+	TenantMigrationStatusVerifying   TenantMigrationStatus = "verifying"    // Comparing row counts
+	TenantMigrationStatusRollingBack TenantMigrationStatus = "rolling_back" // Checking the target and switching back to the source
+	TenantMigrationStatusCompleted   TenantMigrationStatus = "completed"    // Connections switched to the target
+	TenantMigrationStatusFailed      TenantMigrationStatus = "failed"       // Stopped, the tenant still uses the source
+	TenantMigrationStatusRolledBack  TenantMigrationStatus = "rolled_back"  // Target dropped, the tenant uses the source again
+)
+
+// IsRunning returns true while the migration job is working
+func (s TenantMigrationStatus) IsRunning() bool {
+	return s == TenantMigrationStatusPending || s == TenantMigrationStatusCopying || s == TenantMigrationStatusVerifying
+}
+
+// FreezesWrites returns true while the storefront's data must not change
+// because it is being copied or compared
+func (s TenantMigrationStatus) FreezesWrites() bool {
+	return s.IsRunning() || s == TenantMigrationStatusRollingBack
+}
+
+// TableProgress tracks the copy of a single table
+type TableProgress struct {
+	Table      string `json:"table"`
+	SourceRows int64  `json:"source_rows"`
+	CopiedRows int64  `json:"copied_rows"`
+	TargetRows int64  `json:"target_rows"`
+	Verified   bool   `json:"verified"`
+}
+
+// TableProgressList is the per-table progress of a migration stored as JSONB
+type TableProgressList []TableProgress
+
+// Value implements the driver.Valuer interface for database storage
+func (l TableProgressList) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (l *TableProgressList) Scan(value interface{}) error {
+	if value == nil {
+		*l = TableProgressList{}
+		return nil
+	}
+
+	var b []byte
+	switch v := value.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into TableProgressList", value)
+	}
+
+	return json.Unmarshal(b, l)
+}
+
+// TenantMigration is a job moving a storefront's data to another isolation strategy
+type TenantMigration struct {
+	ID           uuid.UUID             `json:"id"`
+	StorefrontID uuid.UUID             `json:"storefront_id"`
+	SourceType   TenantType            `json:"source_type"`
+	TargetType   TenantType            `json:"target_type"`
+	Status       TenantMigrationStatus `json:"status"`
+	CurrentTable *string               `json:"current_table,omitempty"`
+	Tables       TableProgressList     `json:"tables"`
+	RowsTotal    int64                 `json:"rows_total"`
+	RowsCopied   int64                 `json:"rows_copied"`
+	ErrorMessage *string               `json:"error_message,omitempty"`
+	StartedAt    *time.Time            `json:"started_at,omitempty"`
+	CompletedAt  *time.Time            `json:"completed_at,omitempty"`
+	RolledBackAt *time.Time            `json:"rolled_back_at,omitempty"`
+	CreatedAt    time.Time             `json:"created_at"`
+	UpdatedAt    time.Time             `json:"updated_at"`
+}
+
+// ProgressPercent returns how much of the data has been copied
+func (m *TenantMigration) ProgressPercent() float64 {
+	if m.Status == TenantMigrationStatusCompleted {
+		return 100
+	}
+	if m.RowsTotal == 0 {
+		return 0
+	}
+	return float64(m.RowsCopied) * 100 / float64(m.RowsTotal)
+}
+
+// CanRollback returns true if the migration finished or failed and its target
+// can be dropped. A rollback that was interrupted may be retried.
+func (m *TenantMigration) CanRollback() bool {
+	return m.Status == TenantMigrationStatusCompleted || m.Status == TenantMigrationStatusFailed ||
+		m.Status == TenantMigrationStatusRollingBack
+}
+
+// tenantMigrationColumns lists the columns selected for a tenant migration
+const tenantMigrationColumns = `
+	id, storefront_id, source_type, target_type, status, current_table, tables, rows_total,
+	rows_copied, error_message, started_at, completed_at, rolled_back_at, created_at, updated_at`
+
+// createMigration stores a new migration job. The partial unique index on
+// running migrations rejects a second job for the same storefront.
+func (tr *tenantResolver) createMigration(ctx context.Context, m *TenantMigration) error {
+	query := `
+		INSERT INTO tenant_migrations (
+			id, storefront_id, source_type, target_type, status, tables, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := tr.sharedDB.ExecContext(ctx, query,
+		m.ID, m.StorefrontID, m.SourceType, m.TargetType, m.Status, m.Tables, m.CreatedAt, m.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create tenant migration: %w", err)
+	}
+	return nil
+}
+
+// saveMigration stores the progress of a migration job
+func (tr *tenantResolver) saveMigration(ctx context.Context, m *TenantMigration) error {
+	m.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE tenant_migrations
+		SET status = $1, current_table = $2, tables = $3, rows_total = $4, rows_copied = $5,
+			error_message = $6, started_at = $7, completed_at = $8, rolled_back_at = $9, updated_at = $10
+		WHERE id = $11`
+
+	_, err := tr.sharedDB.ExecContext(ctx, query,
+		m.Status, m.CurrentTable, m.Tables, m.RowsTotal, m.RowsCopied,
+		m.ErrorMessage, m.StartedAt, m.CompletedAt, m.RolledBackAt, m.UpdatedAt, m.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save tenant migration: %w", err)
+	}
+	return nil
+}
+
+// GetTenantMigration retrieves a migration job by its ID
+func (tr *tenantResolver) GetTenantMigration(ctx context.Context, migrationID uuid.UUID) (*TenantMigration, error) {
+	query := `SELECT` + tenantMigrationColumns + ` FROM tenant_migrations WHERE id = $1`
+	return tr.getMigration(ctx, query, migrationID)
+}
+
+// GetLatestTenantMigration retrieves the most recent migration job of a storefront
+func (tr *tenantResolver) GetLatestTenantMigration(ctx context.Context, storefrontID uuid.UUID) (*TenantMigration, error) {
+	query := `SELECT` + tenantMigrationColumns + ` FROM tenant_migrations
+		WHERE storefront_id = $1 ORDER BY created_at DESC LIMIT 1`
+	return tr.getMigration(ctx, query, storefrontID)
+}
+
+// getMigration runs a single-migration lookup
+func (tr *tenantResolver) getMigration(ctx context.Context, query string, args ...interface{}) (*TenantMigration, error) {
+	var m TenantMigration
+	err := tr.sharedDB.QueryRowContext(ctx, query, args...).Scan(
+		&m.ID, &m.StorefrontID, &m.SourceType, &m.TargetType, &m.Status, &m.CurrentTable, &m.Tables,
+		&m.RowsTotal, &m.RowsCopied, &m.ErrorMessage, &m.StartedAt, &m.CompletedAt, &m.RolledBackAt,
+		&m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get tenant migration: %w", err)
+	}
+	return &m, nil
+}
+
+// loadPlacements reads the isolation strategy of every migrated storefront,
+// which is the target of its latest completed migration, and the storefronts
+// whose writes are frozen by a migration or rollback in progress
+func (tr *tenantResolver) loadPlacements(ctx context.Context) error {
+	tr.placementMu.Lock()
+	defer tr.placementMu.Unlock()
+
+	query := `
+		SELECT DISTINCT ON (storefront_id) storefront_id, target_type
+		FROM tenant_migrations
+		WHERE status = $1
+		ORDER BY storefront_id, completed_at DESC`
+
+	rows, err := tr.sharedDB.QueryContext(ctx, query, TenantMigrationStatusCompleted)
+	if err != nil {
+		return fmt.Errorf("failed to load tenant placements: %w", err)
+	}
+	defer rows.Close()
+
+	placements := make(map[uuid.UUID]TenantType)
+	for rows.Next() {
+		var storefrontID uuid.UUID
+		var tenantType TenantType
+		if err := rows.Scan(&storefrontID, &tenantType); err != nil {
+			return fmt.Errorf("failed to scan tenant placement: %w", err)
+		}
+		placements[storefrontID] = tenantType
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to load tenant placements: %w", err)
+	}
+
+	frozen, err := tr.loadFrozen(ctx)
+	if err != nil {
+		return err
+	}
+
+	tr.mu.Lock()
+	tr.placements = placements
+	tr.frozen = frozen
+	tr.mu.Unlock()
+
+	return nil
+}
+
+// loadFrozen reads the storefronts with a migration or rollback in progress.
+// Jobs that stopped saving progress were interrupted and no longer freeze.
+func (tr *tenantResolver) loadFrozen(ctx context.Context) (map[uuid.UUID]struct{}, error) {
+	query := `
+		SELECT storefront_id FROM tenant_migrations
+		WHERE status IN ($1, $2, $3, $4) AND updated_at > $5`
+
+	rows, err := tr.sharedDB.QueryContext(ctx, query,
+		TenantMigrationStatusPending, TenantMigrationStatusCopying, TenantMigrationStatusVerifying,
+		TenantMigrationStatusRollingBack, time.Now().Add(-migrationStaleAfter))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load frozen tenants: %w", err)
+	}
+	defer rows.Close()
+
+	frozen := make(map[uuid.UUID]struct{})
+	for rows.Next() {
+		var storefrontID uuid.UUID
+		if err := rows.Scan(&storefrontID); err != nil {
+			return nil, fmt.Errorf("failed to scan frozen tenant: %w", err)
+		}
+		frozen[storefrontID] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load frozen tenants: %w", err)
+	}
+	return frozen, nil
+}
+
+// claimRollback moves a migration to rolling_back unless another rollback
+// holds it, reporting whether the claim succeeded
+func (tr *tenantResolver) claimRollback(ctx context.Context, m *TenantMigration) (bool, error) {
+	now := time.Now()
+	query := `
+		UPDATE tenant_migrations SET status = $1, updated_at = $2
+		WHERE id = $3 AND (status IN ($4, $5) OR (status = $1 AND updated_at <= $6))`
+
+	result, err := tr.sharedDB.ExecContext(ctx, query,
+		TenantMigrationStatusRollingBack, now, m.ID,
+		TenantMigrationStatusCompleted, TenantMigrationStatusFailed, now.Add(-migrationStaleAfter))
+	if err != nil {
+		return false, fmt.Errorf("failed to start tenant rollback: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to start tenant rollback: %w", err)
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	m.Status = TenantMigrationStatusRollingBack
+	m.UpdatedAt = now
+	return true, nil
+}
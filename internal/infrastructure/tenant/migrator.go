@@ -0,0 +1,914 @@
+package tenant
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+)
+
+const (
+	// defaultMigrationBatchSize is used when the config does not set a batch size
+	defaultMigrationBatchSize = 500
+
+	// migrationStaleAfter is how long a running migration may go without saving
+	// progress before it is considered interrupted, e.g. by a restart
+	migrationStaleAfter = 15 * time.Minute
+
+	// migrationWriteSettle is how long a migration or rollback waits after
+	// freezing writes, so that every instance has received the freeze and
+	// writes that passed the check before it have finished
+	migrationWriteSettle = 5 * time.Second
+)
+
+// tenantTable is a table holding storefront data. Scope selects the
+// storefront's rows in the source; {schema} is replaced with the source
+// schema and $1 is the storefront ID.
+type tenantTable struct {
+	name  string
+	scope string
+}
+
+// tenantTables lists the tables copied by a tenant migration. Only tables
+// whose repositories resolve every query through the tenant's connection are
+// listed, and each needs an updated_at column, which a rollback uses to find
+// writes made to the target. A migration is refused while any table left in
+// the source references one of them, see checkTableDependencies.
+var tenantTables = []tenantTable{
+	{name: "customers", scope: "storefront_id = $1"},
+	{name: "warranty_policy_templates", scope: "storefront_id = $1"},
+}
+
+// tableReference is a foreign key from one table to another
+type tableReference struct {
+	table      string
+	referenced string
+}
+
+// checkTableDependencies rejects a migration that would leave tables behind
+// which reference migrated tables. Their rows would keep pointing at the
+// source, and inserts for rows created in the target would fail the foreign
+// key, so the storefront's dependent tables must move together.
+func checkTableDependencies(references []tableReference) error {
+	migrated := make(map[string]bool, len(tenantTables))
+	for _, table := range tenantTables {
+		migrated[table.name] = true
+	}
+
+	var blocking []string
+	seen := make(map[string]bool)
+	for _, ref := range references {
+		if !migrated[ref.referenced] || migrated[ref.table] || seen[ref.table] {
+			continue
+		}
+		seen[ref.table] = true
+		blocking = append(blocking, fmt.Sprintf("%s (references %s)", ref.table, ref.referenced))
+	}
+	if len(blocking) == 0 {
+		return nil
+	}
+
+	sort.Strings(blocking)
+	return fmt.Errorf("cannot migrate tenant: tables that stay in the source depend on migrated tables: %s",
+		strings.Join(blocking, ", "))
+}
+
+// readTableReferences lists the foreign keys between tables of the source
+func readTableReferences(ctx context.Context, source *tenantLocation) ([]tableReference, error) {
+	query := `
+		SELECT DISTINCT c.relname, r.relname
+		FROM pg_constraint k
+		JOIN pg_class c ON c.oid = k.conrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_class r ON r.oid = k.confrelid
+		JOIN pg_namespace rn ON rn.oid = r.relnamespace
+		WHERE k.contype = 'f' AND n.nspname = $1 AND rn.nspname = $1
+		ORDER BY 1, 2`
+
+	rows, err := source.db.QueryContext(ctx, query, source.schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	var references []tableReference
+	for rows.Next() {
+		var ref tableReference
+		if err := rows.Scan(&ref.table, &ref.referenced); err != nil {
+			return nil, fmt.Errorf("failed to read foreign keys: %w", err)
+		}
+		references = append(references, ref)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read foreign keys: %w", err)
+	}
+	return references, nil
+}
+
+// tenantLocation is where a tenant's tables live
+type tenantLocation struct {
+	tenantType TenantType
+	db         *sql.DB
+	schema     string
+}
+
+// table returns the quoted, schema-qualified name of a table at the location
+func (l *tenantLocation) table(name string) string {
+	return pq.QuoteIdentifier(l.schema) + "." + pq.QuoteIdentifier(name)
+}
+
+// tableColumn describes a column read from the source catalog
+type tableColumn struct {
+	name      string
+	dataType  string
+	notNull   bool
+	def       *string
+	generated bool
+}
+
+// SchemaName returns the schema holding a storefront's tables under schema isolation
+func SchemaName(storefrontID uuid.UUID) string {
+	return "tenant_" + strings.ReplaceAll(storefrontID.String(), "-", "_")
+}
+
+// withSearchPath adds a search_path run-time parameter to a connection string,
+// which may be a URL or a key/value string
+func withSearchPath(dsn, searchPath string) string {
+	if strings.Contains(dsn, "://") {
+		u, err := url.Parse(dsn)
+		if err == nil {
+			q := u.Query()
+			q.Set("search_path", searchPath)
+			u.RawQuery = q.Encode()
+			return u.String()
+		}
+	}
+	return strings.TrimSpace(dsn) + " search_path=" + searchPath
+}
+
+// databaseName returns the database a connection string points to
+func databaseName(dsn string) (string, error) {
+	if strings.Contains(dsn, "://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", fmt.Errorf("invalid database URL: %w", err)
+		}
+		if name := strings.TrimPrefix(u.Path, "/"); name != "" {
+			return name, nil
+		}
+		return "", fmt.Errorf("database URL has no database name")
+	}
+
+	for _, field := range strings.Fields(dsn) {
+		if name, ok := strings.CutPrefix(field, "dbname="); ok && name != "" {
+			return strings.Trim(name, "'"), nil
+		}
+	}
+	return "", fmt.Errorf("connection string has no database name")
+}
+
+// validateMigrationPath checks that a tenant can move from one strategy to another.
+// Tenants only move towards stronger isolation; moving back is a rollback.
+func validateMigrationPath(source, target TenantType) error {
+	switch {
+	case source == TenantTypeShared && (target == TenantTypeSchema || target == TenantTypeDatabase),
+		source == TenantTypeSchema && target == TenantTypeDatabase:
+		return nil
+	default:
+		return fmt.Errorf("cannot migrate tenant from %s to %s isolation", source, target)
+	}
+}
+
+// MigrateTenant starts a job moving a storefront's data to another isolation
+// strategy and returns it. The job creates the target schema or database,
+// copies the storefront's tables in batches, verifies row counts and then
+// switches the storefront's connections to the target. Writes to the
+// storefront are frozen on every instance until the job ends. Source rows are
+// kept so that the migration can be rolled back. A migration that would leave
+// dependent tables behind is refused before the job starts.
+func (tr *tenantResolver) MigrateTenant(ctx context.Context, storefrontID uuid.UUID, targetType TenantType) (*TenantMigration, error) {
+	storefront, err := tr.storefrontRepo.GetByID(ctx, storefrontID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storefront: %w", err)
+	}
+	if storefront == nil {
+		return nil, fmt.Errorf("storefront not found")
+	}
+
+	sourceType, err := tr.GetTenantType(ctx, storefrontID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMigrationPath(sourceType, targetType); err != nil {
+		return nil, err
+	}
+
+	source, err := tr.location(ctx, storefrontID, sourceType, false)
+	if err != nil {
+		return nil, err
+	}
+	references, err := readTableReferences(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTableDependencies(references); err != nil {
+		return nil, err
+	}
+
+	latest, err := tr.GetLatestTenantMigration(ctx, storefrontID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.Status.FreezesWrites() {
+		if time.Since(latest.UpdatedAt) < migrationStaleAfter {
+			return nil, fmt.Errorf("tenant migration %s is already running", latest.ID)
+		}
+		message := "migration interrupted"
+		latest.Status = TenantMigrationStatusFailed
+		if latest.CompletedAt != nil {
+			// An interrupted rollback leaves the storefront on the target
+			message = "rollback interrupted"
+			latest.Status = TenantMigrationStatusCompleted
+		}
+		latest.ErrorMessage = &message
+		latest.CurrentTable = nil
+		if err := tr.saveMigration(ctx, latest); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	migration := &TenantMigration{
+		ID:           uuid.New(),
+		StorefrontID: storefrontID,
+		SourceType:   sourceType,
+		TargetType:   targetType,
+		Status:       TenantMigrationStatusPending,
+		Tables:       TableProgressList{},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := tr.createMigration(ctx, migration); err != nil {
+		return nil, err
+	}
+	tr.setWriteFreeze(storefrontID, true)
+
+	// The job outlives the request that started it
+	go tr.runMigration(context.Background(), migration, storefront)
+
+	return migration, nil
+}
+
+// runMigration executes a migration job, recording failures on the job
+func (tr *tenantResolver) runMigration(ctx context.Context, m *TenantMigration, storefront *entity.Storefront) {
+	if err := tr.executeMigration(ctx, m, storefront); err != nil {
+		message := err.Error()
+		m.Status = TenantMigrationStatusFailed
+		m.ErrorMessage = &message
+		m.CurrentTable = nil
+		// Nothing else can record the failure, so it is best effort
+		_ = tr.saveMigration(ctx, m)
+		tr.setWriteFreeze(m.StorefrontID, false)
+	}
+}
+
+// executeMigration copies and verifies the storefront's tables and switches
+// the storefront to the target
+func (tr *tenantResolver) executeMigration(ctx context.Context, m *TenantMigration, storefront *entity.Storefront) error {
+	startedAt := time.Now()
+	m.Status = TenantMigrationStatusCopying
+	m.StartedAt = &startedAt
+	if err := tr.saveMigration(ctx, m); err != nil {
+		return err
+	}
+	if err := waitForWriters(ctx); err != nil {
+		return err
+	}
+
+	source, err := tr.location(ctx, m.StorefrontID, m.SourceType, false)
+	if err != nil {
+		return err
+	}
+	target, err := tr.location(ctx, m.StorefrontID, m.TargetType, true)
+	if err != nil {
+		return err
+	}
+
+	// Count first so that progress can be reported against a total
+	m.Tables = make(TableProgressList, len(tenantTables))
+	m.RowsTotal = 0
+	for i, table := range tenantTables {
+		count, err := tr.countSourceRows(ctx, source, table, storefront)
+		if err != nil {
+			return err
+		}
+		m.Tables[i] = TableProgress{Table: table.name, SourceRows: count}
+		m.RowsTotal += count
+	}
+	if err := tr.saveMigration(ctx, m); err != nil {
+		return err
+	}
+
+	if target.db != source.db {
+		if err := copyFunctions(ctx, source, target); err != nil {
+			return err
+		}
+	}
+
+	for i, table := range tenantTables {
+		name := table.name
+		m.CurrentTable = &name
+
+		columns, err := readColumns(ctx, source, table.name)
+		if err != nil {
+			return err
+		}
+		if err := createTable(ctx, source, target, table.name, columns); err != nil {
+			return err
+		}
+		if err := tr.copyTable(ctx, m, i, source, target, table, columns, storefront); err != nil {
+			return err
+		}
+		// Indexes and triggers are added after the rows so that the copy does
+		// not maintain indexes row by row or fire triggers on copied rows
+		if err := copyIndexesAndTriggers(ctx, source, target, table.name); err != nil {
+			return err
+		}
+	}
+
+	m.Status = TenantMigrationStatusVerifying
+	m.CurrentTable = nil
+	if err := tr.saveMigration(ctx, m); err != nil {
+		return err
+	}
+
+	for i, table := range tenantTables {
+		sourceRows, err := tr.countSourceRows(ctx, source, table, storefront)
+		if err != nil {
+			return err
+		}
+		var targetRows int64
+		if err := target.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+target.table(table.name)).Scan(&targetRows); err != nil {
+			return fmt.Errorf("failed to count %s in target: %w", table.name, err)
+		}
+
+		m.Tables[i].TargetRows = targetRows
+		if sourceRows != targetRows {
+			return fmt.Errorf("row count mismatch for %s: %d in source, %d in target", table.name, sourceRows, targetRows)
+		}
+		m.Tables[i].Verified = true
+	}
+
+	return tr.switchPlacement(ctx, m, m.TargetType, TenantMigrationStatusCompleted)
+}
+
+// location resolves where a tenant's tables live for an isolation strategy,
+// creating the schema or database when create is set
+func (tr *tenantResolver) location(ctx context.Context, storefrontID uuid.UUID, tenantType TenantType, create bool) (*tenantLocation, error) {
+	switch tenantType {
+	case TenantTypeShared:
+		return &tenantLocation{tenantType: tenantType, db: tr.sharedDB, schema: "public"}, nil
+
+	case TenantTypeSchema:
+		schema := SchemaName(storefrontID)
+		if create {
+			if _, err := tr.sharedDB.ExecContext(ctx, `CREATE SCHEMA IF NOT EXISTS `+pq.QuoteIdentifier(schema)); err != nil {
+				return nil, fmt.Errorf("failed to create tenant schema: %w", err)
+			}
+		}
+		return &tenantLocation{tenantType: tenantType, db: tr.sharedDB, schema: schema}, nil
+
+	case TenantTypeDatabase:
+		if create {
+			if err := tr.createTenantDatabase(ctx, storefrontID); err != nil {
+				return nil, err
+			}
+		}
+		db, err := tr.getTenantConnection(storefrontID, TenantTypeDatabase)
+		if err != nil {
+			return nil, err
+		}
+		return &tenantLocation{tenantType: tenantType, db: db, schema: "public"}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported tenant type: %s", tenantType)
+	}
+}
+
+// createTenantDatabase creates the storefront's database on the shared server
+func (tr *tenantResolver) createTenantDatabase(ctx context.Context, storefrontID uuid.UUID) error {
+	name, err := databaseName(tr.tenantDSN(storefrontID, TenantTypeDatabase))
+	if err != nil {
+		return err
+	}
+
+	var exists bool
+	if err := tr.sharedDB.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM pg_database WHERE datname = $1)`, name).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check tenant database: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	if _, err := tr.sharedDB.ExecContext(ctx, `CREATE DATABASE `+pq.QuoteIdentifier(name)); err != nil {
+		return fmt.Errorf("failed to create tenant database: %w", err)
+	}
+	return nil
+}
+
+// countSourceRows counts the storefront's rows of a table in the source
+func (tr *tenantResolver) countSourceRows(ctx context.Context, source *tenantLocation, table tenantTable, storefront *entity.Storefront) (int64, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s`, source.table(table.name), scopeCondition(source, table))
+
+	var count int64
+	if err := source.db.QueryRowContext(ctx, query, storefront.ID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count %s in source: %w", table.name, err)
+	}
+	return count, nil
+}
+
+// copyTable copies the storefront's rows of a table in batches ordered by ID.
+// Rows already in the target are skipped, so an interrupted copy can be rerun.
+func (tr *tenantResolver) copyTable(ctx context.Context, m *TenantMigration, index int, source, target *tenantLocation, table tenantTable, columns []tableColumn, storefront *entity.Storefront) error {
+	var names []string
+	for _, col := range columns {
+		if !col.generated {
+			names = append(names, pq.QuoteIdentifier(col.name))
+		}
+	}
+	columnList := strings.Join(names, ", ")
+
+	batchSize := tr.config.MigrationBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultMigrationBatchSize
+	}
+
+	selectQuery := fmt.Sprintf(`SELECT %s FROM %s WHERE (%s) AND id > $2 ORDER BY id LIMIT %d`,
+		columnList, source.table(table.name), scopeCondition(source, table), batchSize)
+
+	lastID := uuid.Nil.String()
+	for {
+		batch, nextID, err := readBatch(ctx, source.db, selectQuery, storefront.ID, lastID, len(names))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", table.name, err)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := insertBatch(ctx, target, table.name, columnList, len(names), batch); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", table.name, err)
+		}
+
+		m.Tables[index].CopiedRows += int64(len(batch))
+		m.RowsCopied += int64(len(batch))
+		if err := tr.saveMigration(ctx, m); err != nil {
+			return err
+		}
+
+		if len(batch) < batchSize {
+			return nil
+		}
+		lastID = nextID
+	}
+}
+
+// readBatch reads one batch of rows and returns them with the last row's ID
+func readBatch(ctx context.Context, db *sql.DB, query string, scope interface{}, lastID string, width int) ([][]interface{}, string, error) {
+	rows, err := db.QueryContext(ctx, query, scope, lastID)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, "", err
+	}
+	idIndex := -1
+	for i, col := range cols {
+		if col == "id" {
+			idIndex = i
+		}
+	}
+	if idIndex < 0 {
+		return nil, "", fmt.Errorf("table has no id column")
+	}
+
+	var batch [][]interface{}
+	var nextID string
+	for rows.Next() {
+		values := make([]interface{}, width)
+		pointers := make([]interface{}, width)
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, "", err
+		}
+
+		// Text-format values come back as bytes; send them back as text so
+		// that JSON, numeric and array columns are not encoded as bytea
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		nextID = fmt.Sprint(values[idIndex])
+		batch = append(batch, values)
+	}
+
+	return batch, nextID, rows.Err()
+}
+
+// insertBatch writes a batch of rows to the target in a single statement
+func insertBatch(ctx context.Context, target *tenantLocation, table, columnList string, width int, batch [][]interface{}) error {
+	placeholders := make([]string, 0, len(batch))
+	args := make([]interface{}, 0, len(batch)*width)
+	for _, row := range batch {
+		params := make([]string, width)
+		for i := range row {
+			args = append(args, row[i])
+			params[i] = fmt.Sprintf("$%d", len(args))
+		}
+		placeholders = append(placeholders, "("+strings.Join(params, ", ")+")")
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES %s ON CONFLICT DO NOTHING`,
+		target.table(table), columnList, strings.Join(placeholders, ", "))
+	_, err := target.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// scopeCondition returns a table's scope for the source location
+func scopeCondition(source *tenantLocation, table tenantTable) string {
+	return strings.ReplaceAll(table.scope, "{schema}", pq.QuoteIdentifier(source.schema))
+}
+
+// readColumns reads a table's column definitions from the source catalog
+func readColumns(ctx context.Context, source *tenantLocation, table string) ([]tableColumn, error) {
+	query := `
+		SELECT a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull,
+			pg_get_expr(d.adbin, d.adrelid), a.attgenerated <> ''
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE n.nspname = $1 AND c.relname = $2 AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum`
+
+	rows, err := source.db.QueryContext(ctx, query, source.schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []tableColumn
+	for rows.Next() {
+		var col tableColumn
+		if err := rows.Scan(&col.name, &col.dataType, &col.notNull, &col.def, &col.generated); err != nil {
+			return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+		}
+		columns = append(columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s does not exist in the source", table)
+	}
+
+	return columns, nil
+}
+
+// createTable creates a table in the target with the source's columns and its
+// primary key, unique and check constraints. Foreign keys are not copied
+// because referenced tables such as users stay in the shared schema.
+func createTable(ctx context.Context, source, target *tenantLocation, table string, columns []tableColumn) error {
+	var defs []string
+	for _, col := range columns {
+		def := pq.QuoteIdentifier(col.name) + " " + col.dataType
+		switch {
+		case col.generated && col.def != nil:
+			def += " GENERATED ALWAYS AS (" + *col.def + ") STORED"
+		case col.def != nil && !strings.Contains(*col.def, "nextval("):
+			def += " DEFAULT " + *col.def
+		}
+		if col.notNull {
+			def += " NOT NULL"
+		}
+		defs = append(defs, def)
+	}
+
+	query := `
+		SELECT con.conname, pg_get_constraintdef(con.oid)
+		FROM pg_constraint con
+		JOIN pg_class c ON c.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2 AND con.contype IN ('p', 'u', 'c')
+		ORDER BY con.contype, con.conname`
+
+	rows, err := source.db.QueryContext(ctx, query, source.schema, table)
+	if err != nil {
+		return fmt.Errorf("failed to read constraints of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, def string
+		if err := rows.Scan(&name, &def); err != nil {
+			return fmt.Errorf("failed to read constraints of %s: %w", table, err)
+		}
+		defs = append(defs, "CONSTRAINT "+pq.QuoteIdentifier(name)+" "+def)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read constraints of %s: %w", table, err)
+	}
+
+	ddl := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\t%s\n)", target.table(table), strings.Join(defs, ",\n\t"))
+	if _, err := target.db.ExecContext(ctx, ddl); err != nil {
+		return fmt.Errorf("failed to create %s in target: %w", table, err)
+	}
+	return nil
+}
+
+// copyIndexesAndTriggers recreates a table's secondary indexes and triggers in the target
+func copyIndexesAndTriggers(ctx context.Context, source, target *tenantLocation, table string) error {
+	sourceTable := source.schema + "." + table + " "
+	targetTable := target.table(table) + " "
+
+	// Indexes backing constraints were created with the table
+	indexQuery := `
+		SELECT i.indexdef
+		FROM pg_indexes i
+		WHERE i.schemaname = $1 AND i.tablename = $2
+			AND NOT EXISTS (
+				SELECT 1 FROM pg_constraint con
+				JOIN pg_class c ON c.oid = con.conindid
+				JOIN pg_namespace n ON n.oid = c.relnamespace
+				WHERE n.nspname = i.schemaname AND c.relname = i.indexname
+			)`
+
+	indexDefs, err := queryStrings(ctx, source.db, indexQuery, source.schema, table)
+	if err != nil {
+		return fmt.Errorf("failed to read indexes of %s: %w", table, err)
+	}
+	for _, def := range indexDefs {
+		def = strings.Replace(def, " ON "+sourceTable, " ON "+targetTable, 1)
+		def = strings.Replace(def, "CREATE UNIQUE INDEX ", "CREATE UNIQUE INDEX IF NOT EXISTS ", 1)
+		def = strings.Replace(def, "CREATE INDEX ", "CREATE INDEX IF NOT EXISTS ", 1)
+		if _, err := target.db.ExecContext(ctx, def); err != nil {
+			return fmt.Errorf("failed to create index on %s in target: %w", table, err)
+		}
+	}
+
+	triggerQuery := `
+		SELECT t.tgname, pg_get_triggerdef(t.oid)
+		FROM pg_trigger t
+		JOIN pg_class c ON c.oid = t.tgrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2 AND NOT t.tgisinternal`
+
+	rows, err := source.db.QueryContext(ctx, triggerQuery, source.schema, table)
+	if err != nil {
+		return fmt.Errorf("failed to read triggers of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	type trigger struct{ name, def string }
+	var triggers []trigger
+	for rows.Next() {
+		var t trigger
+		if err := rows.Scan(&t.name, &t.def); err != nil {
+			return fmt.Errorf("failed to read triggers of %s: %w", table, err)
+		}
+		triggers = append(triggers, t)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read triggers of %s: %w", table, err)
+	}
+
+	for _, t := range triggers {
+		drop := fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s", pq.QuoteIdentifier(t.name), target.table(table))
+		if _, err := target.db.ExecContext(ctx, drop); err != nil {
+			return fmt.Errorf("failed to replace trigger on %s in target: %w", table, err)
+		}
+		def := strings.Replace(t.def, " ON "+sourceTable, " ON "+targetTable, 1)
+		if _, err := target.db.ExecContext(ctx, def); err != nil {
+			return fmt.Errorf("failed to create trigger on %s in target: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+// copyFunctions recreates the source's own SQL functions, which triggers and
+// check constraints call, in a target database
+func copyFunctions(ctx context.Context, source, target *tenantLocation) error {
+	query := `
+		SELECT pg_get_functiondef(p.oid)
+		FROM pg_proc p
+		JOIN pg_namespace n ON n.oid = p.pronamespace
+		WHERE n.nspname = 'public' AND p.prokind = 'f'
+			AND NOT EXISTS (SELECT 1 FROM pg_depend d WHERE d.objid = p.oid AND d.deptype = 'e')`
+
+	defs, err := queryStrings(ctx, source.db, query)
+	if err != nil {
+		return fmt.Errorf("failed to read functions: %w", err)
+	}
+	for _, def := range defs {
+		if _, err := target.db.ExecContext(ctx, def); err != nil {
+			return fmt.Errorf("failed to create function in target: %w", err)
+		}
+	}
+	return nil
+}
+
+// queryStrings runs a query returning a single text column
+func queryStrings(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+// switchPlacement records the migration outcome and points the storefront at
+// the given isolation strategy in one step, so that no connection is handed
+// out for the old placement once the outcome is stored
+func (tr *tenantResolver) switchPlacement(ctx context.Context, m *TenantMigration, tenantType TenantType, status TenantMigrationStatus) error {
+	tr.placementMu.Lock()
+	defer tr.placementMu.Unlock()
+
+	now := time.Now()
+	m.Status = status
+	m.CurrentTable = nil
+	m.ErrorMessage = nil
+	if status == TenantMigrationStatusCompleted {
+		m.CompletedAt = &now
+	} else {
+		m.RolledBackAt = &now
+	}
+	if err := tr.saveMigration(ctx, m); err != nil {
+		return err
+	}
+
+	tr.mu.Lock()
+	if tenantType == tr.config.DefaultTenantType {
+		delete(tr.placements, m.StorefrontID)
+	} else {
+		tr.placements[m.StorefrontID] = tenantType
+	}
+	delete(tr.frozen, m.StorefrontID)
+	tr.mu.Unlock()
+
+	tr.publishPlacementChange(m.StorefrontID)
+	tr.InvalidateStorefrontByID(m.StorefrontID)
+	return nil
+}
+
+// waitForWriters waits for migrationWriteSettle after a write freeze
+func waitForWriters(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(migrationWriteSettle):
+		return nil
+	}
+}
+
+// RollbackTenantMigration moves a storefront back to the source of a finished
+// or failed migration and drops the migration's target. Writes are frozen
+// while the target is checked, and a completed migration is not rolled back
+// once its target has accepted writes, as dropping the target would lose them.
+func (tr *tenantResolver) RollbackTenantMigration(ctx context.Context, migrationID uuid.UUID) (*TenantMigration, error) {
+	m, err := tr.GetTenantMigration(ctx, migrationID)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, fmt.Errorf("tenant migration not found")
+	}
+	if !m.CanRollback() {
+		return nil, fmt.Errorf("tenant migration in status %s cannot be rolled back", m.Status)
+	}
+
+	// Only a completed migration ever switched the storefront to its target
+	switched := m.CompletedAt != nil
+	if switched {
+		latest, err := tr.GetLatestTenantMigration(ctx, m.StorefrontID)
+		if err != nil {
+			return nil, err
+		}
+		if latest != nil && latest.ID != m.ID {
+			return nil, fmt.Errorf("only the latest tenant migration can be rolled back")
+		}
+	}
+
+	previous := m.Status
+	claimed, err := tr.claimRollback(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, fmt.Errorf("tenant migration %s is already being rolled back", m.ID)
+	}
+
+	if switched {
+		if err := tr.checkTargetUnchanged(ctx, m); err != nil {
+			if previous == TenantMigrationStatusRollingBack {
+				previous = TenantMigrationStatusCompleted
+			}
+			m.Status = previous
+			if saveErr := tr.saveMigration(ctx, m); saveErr != nil {
+				return nil, saveErr
+			}
+			tr.setWriteFreeze(m.StorefrontID, false)
+			return nil, err
+		}
+	}
+
+	if err := tr.switchPlacement(ctx, m, m.SourceType, TenantMigrationStatusRolledBack); err != nil {
+		tr.setWriteFreeze(m.StorefrontID, false)
+		return nil, err
+	}
+
+	if err := tr.dropTarget(ctx, m); err != nil {
+		// The storefront already uses the source again; a leftover target is harmless
+		message := err.Error()
+		m.ErrorMessage = &message
+		if saveErr := tr.saveMigration(ctx, m); saveErr != nil {
+			return nil, saveErr
+		}
+	}
+
+	return m, nil
+}
+
+// checkTargetUnchanged freezes writes to the storefront and fails if the
+// target of a completed migration changed after the switch: rows were added
+// or removed, or a row was updated since the migration completed
+func (tr *tenantResolver) checkTargetUnchanged(ctx context.Context, m *TenantMigration) error {
+	tr.setWriteFreeze(m.StorefrontID, true)
+	if err := waitForWriters(ctx); err != nil {
+		return err
+	}
+
+	target, err := tr.location(ctx, m.StorefrontID, m.TargetType, false)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range m.Tables {
+		query := `SELECT COUNT(*), COALESCE(BOOL_OR(updated_at > $1), FALSE) FROM ` + target.table(table.Table)
+
+		var rows int64
+		var updated bool
+		if err := target.db.QueryRowContext(ctx, query, m.CompletedAt).Scan(&rows, &updated); err != nil {
+			return fmt.Errorf("failed to check %s in target: %w", table.Table, err)
+		}
+		if rows != table.TargetRows || updated {
+			return fmt.Errorf("tenant migration cannot be rolled back: %s has been written to since the migration completed", table.Table)
+		}
+	}
+	return nil
+}
+
+// dropTarget removes the schema or database a migration copied into
+func (tr *tenantResolver) dropTarget(ctx context.Context, m *TenantMigration) error {
+	switch m.TargetType {
+	case TenantTypeSchema:
+		tr.closeTenantConnection(m.StorefrontID, TenantTypeSchema)
+		if _, err := tr.sharedDB.ExecContext(ctx, `DROP SCHEMA IF EXISTS `+pq.QuoteIdentifier(SchemaName(m.StorefrontID))+` CASCADE`); err != nil {
+			return fmt.Errorf("failed to drop tenant schema: %w", err)
+		}
+	case TenantTypeDatabase:
+		name, err := databaseName(tr.tenantDSN(m.StorefrontID, TenantTypeDatabase))
+		if err != nil {
+			return err
+		}
+		tr.closeTenantConnection(m.StorefrontID, TenantTypeDatabase)
+		if _, err := tr.sharedDB.ExecContext(ctx, `DROP DATABASE IF EXISTS `+pq.QuoteIdentifier(name)); err != nil {
+			return fmt.Errorf("failed to drop tenant database: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package tenant
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSchemaName(t *testing.T) {
+	id := uuid.MustParse("0b6c3f0e-52a4-4b8e-9d3c-1f2e3d4c5b6a")
+	if got, want := SchemaName(id), "tenant_0b6c3f0e_52a4_4b8e_9d3c_1f2e3d4c5b6a"; got != want {
+		t.Errorf("SchemaName() = %s, want %s", got, want)
+	}
+}
+
+func TestWithSearchPath(t *testing.T) {
+	tests := map[string]string{
+		"postgres://app@localhost:5432/smartseller?sslmode=disable": "postgres://app@localhost:5432/smartseller?search_path=tenant_a%2Cpublic&sslmode=disable",
+		"host=localhost dbname=smartseller sslmode=disable":         "host=localhost dbname=smartseller sslmode=disable search_path=tenant_a,public",
+	}
+	for dsn, want := range tests {
+		if got := withSearchPath(dsn, "tenant_a,public"); got != want {
+			t.Errorf("withSearchPath(%q) = %q, want %q", dsn, got, want)
+		}
+	}
+}
+
+func TestDatabaseName(t *testing.T) {
+	tests := map[string]string{
+		"postgres://localhost:5432/smartseller_tenant_abc?sslmode=disable": "smartseller_tenant_abc",
+		"host=localhost dbname=smartseller_tenant_abc sslmode=disable":     "smartseller_tenant_abc",
+	}
+	for dsn, want := range tests {
+		got, err := databaseName(dsn)
+		if err != nil || got != want {
+			t.Errorf("databaseName(%q) = %q, %v, want %q", dsn, got, err, want)
+		}
+	}
+
+	if _, err := databaseName("host=localhost sslmode=disable"); err == nil {
+		t.Error("Expected an error for a connection string without a database")
+	}
+}
+
+func TestValidateMigrationPath(t *testing.T) {
+	allowed := [][2]TenantType{
+		{TenantTypeShared, TenantTypeSchema},
+		{TenantTypeShared, TenantTypeDatabase},
+		{TenantTypeSchema, TenantTypeDatabase},
+	}
+	for _, path := range allowed {
+		if err := validateMigrationPath(path[0], path[1]); err != nil {
+			t.Errorf("Expected %s to %s to be allowed, got %v", path[0], path[1], err)
+		}
+	}
+
+	rejected := [][2]TenantType{
+		{TenantTypeShared, TenantTypeShared},
+		{TenantTypeSchema, TenantTypeShared},
+		{TenantTypeDatabase, TenantTypeSchema},
+	}
+	for _, path := range rejected {
+		if err := validateMigrationPath(path[0], path[1]); err == nil {
+			t.Errorf("Expected %s to %s to be rejected", path[0], path[1])
+		}
+	}
+}
+
+func TestTenantMigrationProgress(t *testing.T) {
+	m := &TenantMigration{Status: TenantMigrationStatusCopying, RowsTotal: 200, RowsCopied: 50}
+	if got := m.ProgressPercent(); got != 25 {
+		t.Errorf("ProgressPercent() = %v, want 25", got)
+	}
+	if m.CanRollback() {
+		t.Error("Expected a running migration not to be rollbackable")
+	}
+
+	m.Status = TenantMigrationStatusCompleted
+	if got := m.ProgressPercent(); got != 100 {
+		t.Errorf("ProgressPercent() = %v, want 100", got)
+	}
+	if !m.CanRollback() {
+		t.Error("Expected a completed migration to be rollbackable")
+	}
+}
+
+func TestTenantMigrationStatusFreezesWrites(t *testing.T) {
+	frozen := []TenantMigrationStatus{
+		TenantMigrationStatusPending, TenantMigrationStatusCopying,
+		TenantMigrationStatusVerifying, TenantMigrationStatusRollingBack,
+	}
+	for _, status := range frozen {
+		if !status.FreezesWrites() {
+			t.Errorf("Expected %s to freeze writes", status)
+		}
+	}
+
+	open := []TenantMigrationStatus{
+		TenantMigrationStatusCompleted, TenantMigrationStatusFailed, TenantMigrationStatusRolledBack,
+	}
+	for _, status := range open {
+		if status.FreezesWrites() {
+			t.Errorf("Expected %s not to freeze writes", status)
+		}
+	}
+}
+
+func TestCheckTableDependencies(t *testing.T) {
+	err := checkTableDependencies([]tableReference{
+		{table: "customers", referenced: "storefronts"},
+		{table: "customer_addresses", referenced: "customers"},
+		{table: "carts", referenced: "customers"},
+		{table: "carts", referenced: "storefronts"},
+	})
+	if err == nil {
+		t.Fatal("Expected tables referencing migrated tables to block the migration")
+	}
+	for _, table := range []string{"customer_addresses", "carts"} {
+		if !strings.Contains(err.Error(), table) {
+			t.Errorf("Expected %s to be reported, got %v", table, err)
+		}
+	}
+	if strings.Contains(err.Error(), "storefronts (") {
+		t.Errorf("Expected references to shared tables to be allowed, got %v", err)
+	}
+
+	// References to tables left in the source do not block a migration
+	if err := checkTableDependencies([]tableReference{{table: "customers", referenced: "storefronts"}}); err != nil {
+		t.Errorf("Expected no dependency error, got %v", err)
+	}
+}
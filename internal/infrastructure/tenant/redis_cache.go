@@ -0,0 +1,297 @@
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/pkg/redis"
+)
+
+const (
+	// redisCacheKeyPrefix namespaces tenant cache keys in Redis
+	redisCacheKeyPrefix = "smartseller:tenant:"
+
+	// redisInvalidationChannel carries the keys evicted on any replica
+	redisInvalidationChannel = "smartseller:tenant:invalidations"
+
+	// redisClearAll is published when the whole cache is cleared
+	redisClearAll = "*"
+
+	// redisPlacementPrefix marks messages announcing that a storefront's
+	// placement or write freeze changed. They share the invalidation channel
+	// so that a replica that missed them also knows to clear its cache.
+	redisPlacementPrefix = "placement:"
+
+	// redisLocalTTL bounds how long a replica serves an entry from memory.
+	// Invalidations normally evict it sooner; the bound covers messages lost
+	// while a replica is disconnected from Redis.
+	redisLocalTTL = 30 * time.Second
+
+	// redisOperationTimeout bounds each Redis call made by the cache
+	redisOperationTimeout = 2 * time.Second
+
+	// redisResubscribeDelay is the pause before subscribing again after the
+	// subscription connection is lost
+	redisResubscribeDelay = time.Second
+)
+
+// RedisCache is a tenant cache shared by all API replicas through Redis.
+// Each replica keeps recently used entries in memory, and invalidations are
+// published so that every replica evicts its copy. Redis errors are treated
+// as cache misses so that requests fall back to the database. Placement
+// changes travel on the same channel.
+type RedisCache struct {
+	client    *redis.Client
+	local     *inMemoryCache
+	onPlace   func()
+	onPlaceMu sync.RWMutex
+	stop      chan struct{}
+	stopOnce  sync.Once
+	ready     chan struct{}
+	readyOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewRedisTenantCache creates a Redis-backed tenant cache and subscribes to
+// invalidations from other replicas
+func NewRedisTenantCache(client *redis.Client) *RedisCache {
+	cache := &RedisCache{
+		client: client,
+		local:  newInMemoryCache(1000, time.Minute),
+		stop:   make(chan struct{}),
+		ready:  make(chan struct{}),
+	}
+
+	cache.wg.Add(1)
+	go cache.listen()
+
+	return cache
+}
+
+// GetStorefront retrieves a storefront from cache
+func (c *RedisCache) GetStorefront(key string) *entity.Storefront {
+	if storefront := c.local.GetStorefront(key); storefront != nil {
+		return storefront
+	}
+
+	var storefront entity.Storefront
+	if !c.get("storefront:"+key, &storefront) {
+		return nil
+	}
+	c.local.SetStorefront(key, &storefront, redisLocalTTL)
+	return &storefront
+}
+
+// SetStorefront stores a storefront in cache
+func (c *RedisCache) SetStorefront(key string, storefront *entity.Storefront, ttl time.Duration) {
+	c.set("storefront:"+key, storefront, ttl)
+	c.local.SetStorefront(key, storefront, min(ttl, redisLocalTTL))
+}
+
+// InvalidateStorefront removes a storefront from cache on every replica
+func (c *RedisCache) InvalidateStorefront(key string) {
+	c.invalidate("storefront:" + key)
+}
+
+// GetCustomer retrieves a customer from cache. Sensitive fields are not
+// serialized, so cached customers cannot be used for authentication.
+func (c *RedisCache) GetCustomer(storefrontID, customerID string) *entity.Customer {
+	if customer := c.local.GetCustomer(storefrontID, customerID); customer != nil {
+		return customer
+	}
+
+	var customer entity.Customer
+	if !c.get("customer:"+storefrontID+":"+customerID, &customer) {
+		return nil
+	}
+	c.local.SetCustomer(storefrontID, customerID, &customer, redisLocalTTL)
+	return &customer
+}
+
+// SetCustomer stores a customer in cache
+func (c *RedisCache) SetCustomer(storefrontID, customerID string, customer *entity.Customer, ttl time.Duration) {
+	c.set("customer:"+storefrontID+":"+customerID, customer, ttl)
+	c.local.SetCustomer(storefrontID, customerID, customer, min(ttl, redisLocalTTL))
+}
+
+// InvalidateCustomer removes a customer from cache on every replica
+func (c *RedisCache) InvalidateCustomer(storefrontID, customerID string) {
+	c.invalidate("customer:" + storefrontID + ":" + customerID)
+}
+
+// Clear removes all tenant entries from Redis and from every replica's memory
+func (c *RedisCache) Clear() {
+	c.local.Clear()
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisOperationTimeout)
+	defer cancel()
+
+	var cursor uint64
+	for {
+		keys, next, err := c.client.Scan(ctx, cursor, redisCacheKeyPrefix+"*", 100)
+		if err != nil {
+			break
+		}
+		if len(keys) > 0 {
+			if _, err := c.client.Del(ctx, keys...); err != nil {
+				break
+			}
+		}
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+
+	_, _ = c.client.Publish(ctx, redisInvalidationChannel, []byte(redisClearAll))
+}
+
+// Size returns the number of tenant entries in Redis
+func (c *RedisCache) Size() int {
+	ctx, cancel := context.WithTimeout(context.Background(), redisOperationTimeout)
+	defer cancel()
+
+	size := 0
+	var cursor uint64
+	for {
+		keys, next, err := c.client.Scan(ctx, cursor, redisCacheKeyPrefix+"*", 100)
+		if err != nil {
+			return size
+		}
+		size += len(keys)
+		if next == 0 {
+			return size
+		}
+		cursor = next
+	}
+}
+
+// PublishPlacementChange tells every replica that a storefront's placement or
+// write freeze changed
+func (c *RedisCache) PublishPlacementChange(storefrontID uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisOperationTimeout)
+	defer cancel()
+	_, _ = c.client.Publish(ctx, redisInvalidationChannel, []byte(redisPlacementPrefix+storefrontID.String()))
+}
+
+// OnPlacementChange registers the function run when a placement change is
+// published, and whenever the subscription is renewed as changes may have
+// been missed meanwhile
+func (c *RedisCache) OnPlacementChange(fn func()) {
+	c.onPlaceMu.Lock()
+	c.onPlace = fn
+	c.onPlaceMu.Unlock()
+}
+
+// placementChanged runs the registered placement change function
+func (c *RedisCache) placementChanged() {
+	c.onPlaceMu.RLock()
+	fn := c.onPlace
+	c.onPlaceMu.RUnlock()
+	if fn != nil {
+		fn()
+	}
+}
+
+// Close stops listening for invalidations. The Redis client is left open.
+func (c *RedisCache) Close() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+	c.wg.Wait()
+	c.local.Stop()
+}
+
+// get reads and decodes an entry, reporting whether it was found
+func (c *RedisCache) get(key string, dest interface{}) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), redisOperationTimeout)
+	defer cancel()
+
+	data, err := c.client.Get(ctx, redisCacheKeyPrefix+key)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, dest) == nil
+}
+
+// set encodes and stores an entry
+func (c *RedisCache) set(key string, value interface{}, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisOperationTimeout)
+	defer cancel()
+	_ = c.client.Set(ctx, redisCacheKeyPrefix+key, data, ttl)
+}
+
+// invalidate removes an entry from Redis and tells every replica to evict it
+func (c *RedisCache) invalidate(key string) {
+	c.local.remove(key)
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisOperationTimeout)
+	defer cancel()
+	_, _ = c.client.Del(ctx, redisCacheKeyPrefix+key)
+	_, _ = c.client.Publish(ctx, redisInvalidationChannel, []byte(key))
+}
+
+// listen evicts entries invalidated by other replicas. The subscription is
+// renewed when its connection drops; messages published in between are
+// lost, so the memory cache is cleared on every renewal.
+func (c *RedisCache) listen() {
+	defer c.wg.Done()
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), redisOperationTimeout)
+		sub, err := c.client.Subscribe(ctx, redisInvalidationChannel)
+		cancel()
+		if err != nil {
+			select {
+			case <-c.stop:
+				return
+			case <-time.After(redisResubscribeDelay):
+				continue
+			}
+		}
+
+		c.local.Clear()
+		c.readyOnce.Do(func() { close(c.ready) })
+		c.placementChanged()
+
+		if stopped := c.receive(sub); stopped {
+			return
+		}
+	}
+}
+
+// receive applies invalidations until the subscription ends or the cache is
+// closed, reporting whether the cache was closed
+func (c *RedisCache) receive(sub *redis.PubSub) bool {
+	defer sub.Close()
+
+	for {
+		select {
+		case <-c.stop:
+			return true
+		case msg, ok := <-sub.Channel():
+			if !ok {
+				return false
+			}
+			switch key := string(msg.Payload); {
+			case key == redisClearAll:
+				c.local.Clear()
+			case strings.HasPrefix(key, redisPlacementPrefix):
+				c.placementChanged()
+			default:
+				c.local.remove(key)
+			}
+		}
+	}
+}
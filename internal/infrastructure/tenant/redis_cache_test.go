@@ -0,0 +1,119 @@
+package tenant
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/pkg/redis"
+	"github.com/kirimku/smartseller-backend/pkg/redis/redistest"
+)
+
+func newTestRedisCache(t *testing.T, url string) *RedisCache {
+	t.Helper()
+
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		t.Fatalf("ParseURL() error = %v", err)
+	}
+	client := redis.NewClient(opts)
+	cache := NewRedisTenantCache(client)
+	t.Cleanup(func() {
+		cache.Close()
+		client.Close()
+	})
+
+	select {
+	case <-cache.ready:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the invalidation subscription")
+	}
+	return cache
+}
+
+// eventually polls a condition that depends on a published invalidation
+func eventually(t *testing.T, condition func() bool, message string) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal(message)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRedisCacheSharesStorefronts(t *testing.T) {
+	server, err := redistest.NewServer()
+	if err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	replicaA := newTestRedisCache(t, server.URL())
+	replicaB := newTestRedisCache(t, server.URL())
+
+	storefront := &entity.Storefront{ID: uuid.New(), Slug: "acme", Name: "Acme", Status: entity.StorefrontStatusActive}
+	replicaA.SetStorefront("acme", storefront, time.Hour)
+
+	cached := replicaB.GetStorefront("acme")
+	if cached == nil || cached.ID != storefront.ID {
+		t.Fatalf("Expected replica B to read the storefront from Redis, got %+v", cached)
+	}
+	if replicaB.Size() != 1 {
+		t.Errorf("Size() = %d, want 1", replicaB.Size())
+	}
+
+	// Replica B now serves the storefront from memory; invalidating on
+	// replica A must evict that copy too
+	replicaA.InvalidateStorefront("acme")
+	eventually(t, func() bool { return replicaB.GetStorefront("acme") == nil },
+		"Expected the invalidation to evict the storefront on replica B")
+}
+
+func TestRedisCacheClear(t *testing.T) {
+	server, err := redistest.NewServer()
+	if err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	replicaA := newTestRedisCache(t, server.URL())
+	replicaB := newTestRedisCache(t, server.URL())
+
+	name := "Budi"
+	customer := &entity.Customer{ID: uuid.New(), FirstName: &name}
+	replicaA.SetCustomer("store", customer.ID.String(), customer, time.Hour)
+	if replicaB.GetCustomer("store", customer.ID.String()) == nil {
+		t.Fatal("Expected replica B to read the customer from Redis")
+	}
+
+	replicaA.Clear()
+	if keys := server.Keys(); len(keys) != 0 {
+		t.Errorf("Expected Redis to be empty, got %v", keys)
+	}
+	eventually(t, func() bool { return replicaB.GetCustomer("store", customer.ID.String()) == nil },
+		"Expected the clear to evict the customer on replica B")
+}
+
+func TestRedisCachePublishesPlacementChanges(t *testing.T) {
+	server, err := redistest.NewServer()
+	if err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	replicaA := newTestRedisCache(t, server.URL())
+	replicaB := newTestRedisCache(t, server.URL())
+
+	var reloads atomic.Int32
+	replicaB.OnPlacementChange(func() { reloads.Add(1) })
+
+	replicaA.PublishPlacementChange(uuid.New())
+	eventually(t, func() bool { return reloads.Load() == 1 },
+		"Expected replica B to reload placements")
+}
@@ -40,6 +40,11 @@ type inMemoryCache struct {
 
 // NewInMemoryTenantCache creates a new in-memory tenant cache
 func NewInMemoryTenantCache(maxSize int, cleanupInterval time.Duration) TenantCache {
+	return newInMemoryCache(maxSize, cleanupInterval)
+}
+
+// newInMemoryCache creates the in-memory cache and starts its cleanup
+func newInMemoryCache(maxSize int, cleanupInterval time.Duration) *inMemoryCache {
 	cache := &inMemoryCache{
 		items:       make(map[string]cacheItem),
 		maxSize:     maxSize,
@@ -140,6 +145,14 @@ func (c *inMemoryCache) InvalidateCustomer(storefrontID, customerID string) {
 	delete(c.items, cacheKey)
 }
 
+// remove deletes an item by its full cache key
+func (c *inMemoryCache) remove(cacheKey string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.items, cacheKey)
+}
+
 // Clear removes all items from cache
 func (c *inMemoryCache) Clear() {
 	c.mu.Lock()
@@ -221,16 +234,3 @@ func (c *inMemoryCache) Stop() {
 	default:
 	}
 }
-
-// RedisCache is a Redis-based cache implementation (placeholder)
-type RedisCache struct {
-	// Redis client and configuration would go here
-	// This is a placeholder for future Redis integration
-}
-
-// NewRedisTenantCache creates a new Redis-based tenant cache (placeholder)
-func NewRedisTenantCache(redisURL string) TenantCache {
-	// This would initialize a Redis client and return a Redis-based cache
-	// For now, return in-memory cache as fallback
-	return NewInMemoryTenantCache(1000, 5*time.Minute)
-}
@@ -3,6 +3,7 @@ package tenant
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -17,29 +18,41 @@ type TenantType string
 
 const (
 	TenantTypeShared   TenantType = "shared"   // Current: Row-level isolation
-	TenantTypeSchema   TenantType = "schema"   // Future: Schema per tenant
-	TenantTypeDatabase TenantType = "database" // Future: Database per tenant
+	TenantTypeSchema   TenantType = "schema"   // Schema per tenant
+	TenantTypeDatabase TenantType = "database" // Database per tenant
 )
 
+// ErrTenantWriteFrozen is returned for writes to a storefront whose data is
+// being copied by a migration or compared by a rollback
+var ErrTenantWriteFrozen = errors.New("storefront data is being migrated, try again later")
+
 // TenantContext holds tenant information for the current request
 type TenantContext struct {
 	StorefrontID   uuid.UUID
 	StorefrontSlug string
+	StorefrontName string
 	SellerID       uuid.UUID
 	TenantType     TenantType
+
+	// RequireCustomerTwoFactor mirrors the storefront setting
+	RequireCustomerTwoFactor bool
 }
 
 // TenantResolver handles tenant database resolution and management
 type TenantResolver interface {
 	GetTenantType(ctx context.Context, storefrontID uuid.UUID) (TenantType, error)
 	GetDatabaseConnection(ctx context.Context, storefrontID uuid.UUID) (*sql.DB, error)
+	CheckWritable(ctx context.Context, storefrontID uuid.UUID) error
 	GetStorefrontBySlug(ctx context.Context, slug string) (*entity.Storefront, error)
 	GetStorefrontByDomain(ctx context.Context, domain string) (*entity.Storefront, error)
 	CreateTenantContext(storefront *entity.Storefront) *TenantContext
 
 	// Tenant migration and management
 	CanMigrateTenant(ctx context.Context, storefrontID uuid.UUID) (bool, TenantType, error)
-	MigrateTenant(ctx context.Context, storefrontID uuid.UUID, targetType TenantType) error
+	MigrateTenant(ctx context.Context, storefrontID uuid.UUID, targetType TenantType) (*TenantMigration, error)
+	GetTenantMigration(ctx context.Context, migrationID uuid.UUID) (*TenantMigration, error)
+	GetLatestTenantMigration(ctx context.Context, storefrontID uuid.UUID) (*TenantMigration, error)
+	RollbackTenantMigration(ctx context.Context, migrationID uuid.UUID) (*TenantMigration, error)
 	GetTenantStats(ctx context.Context, storefrontID uuid.UUID) (*TenantStats, error)
 
 	// Cache management
@@ -47,6 +60,18 @@ type TenantResolver interface {
 	InvalidateStorefrontByID(storefrontID uuid.UUID)
 }
 
+// PlacementNotifier shares placement changes between API instances, so that
+// every instance switches connections and freezes writes together
+type PlacementNotifier interface {
+	// PublishPlacementChange tells every instance that a storefront's
+	// placement or write freeze changed
+	PublishPlacementChange(storefrontID uuid.UUID)
+
+	// OnPlacementChange registers the function run when a change is published
+	// or changes may have been missed
+	OnPlacementChange(fn func())
+}
+
 // TenantStats holds metrics for tenant migration decisions
 type TenantStats struct {
 	StorefrontID     uuid.UUID `json:"storefront_id"`
@@ -64,11 +89,15 @@ type TenantStats struct {
 // tenantResolver is the concrete implementation
 type tenantResolver struct {
 	sharedDB       *sql.DB
-	tenantDBs      map[uuid.UUID]*sql.DB
+	tenantDBs      map[string]*sql.DB       // Connection pools keyed by connection string
+	placements     map[uuid.UUID]TenantType // Strategies of migrated storefronts
+	frozen         map[uuid.UUID]struct{}   // Storefronts whose writes wait for a migration
+	notifier       PlacementNotifier        // Nil when the cache is local to this instance
 	config         *TenantConfig
 	cache          TenantCache
 	storefrontRepo repository.StorefrontRepository
 	mu             sync.RWMutex
+	placementMu    sync.Mutex // Serializes placement switches and reloads
 	statsCache     map[uuid.UUID]*cachedStats
 	statsCacheMu   sync.RWMutex
 }
@@ -85,11 +114,12 @@ type TenantConfig struct {
 	SharedDatabaseURL       string                `yaml:"shared_database_url"`
 	TenantDatabasePattern   string                `yaml:"tenant_database_pattern"`
 	MaxConnectionsPerTenant int                   `yaml:"max_connections_per_tenant"`
+	MigrationBatchSize      int                   `yaml:"migration_batch_size"`
 	MigrationThresholds     MigrationThresholds   `yaml:"migration_thresholds"`
 	CacheSettings           CacheSettings         `yaml:"cache_settings"`
 }
 
-// MigrationThresholds define when to automatically migrate tenants
+// MigrationThresholds define when a tenant is recommended for migration
 type MigrationThresholds struct {
 	SchemaThreshold struct {
 		CustomerCount int           `yaml:"customer_count"`
@@ -124,13 +154,27 @@ func NewTenantResolver(
 ) TenantResolver {
 	resolver := &tenantResolver{
 		sharedDB:       sharedDB,
-		tenantDBs:      make(map[uuid.UUID]*sql.DB),
+		tenantDBs:      make(map[string]*sql.DB),
+		placements:     make(map[uuid.UUID]TenantType),
+		frozen:         make(map[uuid.UUID]struct{}),
 		config:         config,
 		cache:          cache,
 		storefrontRepo: storefrontRepo,
 		statsCache:     make(map[uuid.UUID]*cachedStats),
 	}
 
+	// Storefronts keep the default strategy until placements can be read,
+	// e.g. before the tenant migrations table exists
+	_ = resolver.loadPlacements(context.Background())
+
+	// Shared caches also carry placement changes made by other instances
+	if notifier, ok := cache.(PlacementNotifier); ok {
+		resolver.notifier = notifier
+		notifier.OnPlacementChange(func() {
+			_ = resolver.loadPlacements(context.Background())
+		})
+	}
+
 	// Start background cleanup goroutine
 	go resolver.startCleanup()
 
@@ -144,20 +188,13 @@ func (tr *tenantResolver) GetTenantType(ctx context.Context, storefrontID uuid.U
 		return tenantType, nil
 	}
 
-	// Check automatic migration thresholds
-	stats, err := tr.GetTenantStats(ctx, storefrontID)
-	if err != nil {
-		// If we can't get stats, default to shared
-		return tr.config.DefaultTenantType, nil
-	}
-
-	// Apply automatic migration rules
-	if tr.shouldMigrateToDatabase(stats) {
-		return TenantTypeDatabase, nil
-	}
-
-	if tr.shouldMigrateToSchema(stats) {
-		return TenantTypeSchema, nil
+	// Storefronts move only through a completed migration, as their data
+	// has to be copied before connections can point at the new location
+	tr.mu.RLock()
+	tenantType, exists := tr.placements[storefrontID]
+	tr.mu.RUnlock()
+	if exists {
+		return tenantType, nil
 	}
 
 	return tr.config.DefaultTenantType, nil
@@ -171,15 +208,27 @@ func (tr *tenantResolver) GetDatabaseConnection(ctx context.Context, storefrontI
 	}
 
 	switch tenantType {
-	case TenantTypeShared, TenantTypeSchema:
+	case TenantTypeShared:
 		return tr.sharedDB, nil
-	case TenantTypeDatabase:
-		return tr.getTenantDatabase(storefrontID)
+	case TenantTypeSchema, TenantTypeDatabase:
+		return tr.getTenantConnection(storefrontID, tenantType)
 	default:
 		return nil, fmt.Errorf("unsupported tenant type: %s", tenantType)
 	}
 }
 
+// CheckWritable returns ErrTenantWriteFrozen while a migration or rollback
+// of the storefront is in progress
+func (tr *tenantResolver) CheckWritable(ctx context.Context, storefrontID uuid.UUID) error {
+	tr.mu.RLock()
+	_, frozen := tr.frozen[storefrontID]
+	tr.mu.RUnlock()
+	if frozen {
+		return ErrTenantWriteFrozen
+	}
+	return nil
+}
+
 // GetStorefrontBySlug retrieves a storefront by its slug with caching
 func (tr *tenantResolver) GetStorefrontBySlug(ctx context.Context, slug string) (*entity.Storefront, error) {
 	// Try cache first
@@ -230,10 +279,12 @@ func (tr *tenantResolver) CreateTenantContext(storefront *entity.Storefront) *Te
 	tenantType, _ := tr.GetTenantType(context.Background(), storefront.ID)
 
 	return &TenantContext{
-		StorefrontID:   storefront.ID,
-		StorefrontSlug: storefront.Slug,
-		SellerID:       storefront.SellerID,
-		TenantType:     tenantType,
+		StorefrontID:             storefront.ID,
+		StorefrontSlug:           storefront.Slug,
+		StorefrontName:           storefront.GetDisplayName(),
+		SellerID:                 storefront.SellerID,
+		TenantType:               tenantType,
+		RequireCustomerTwoFactor: storefront.Settings.RequireCustomerTwoFactor,
 	}
 }
 
@@ -262,24 +313,6 @@ func (tr *tenantResolver) CanMigrateTenant(ctx context.Context, storefrontID uui
 	return false, currentType, nil
 }
 
-// MigrateTenant migrates a tenant to a different isolation strategy (placeholder implementation)
-func (tr *tenantResolver) MigrateTenant(ctx context.Context, storefrontID uuid.UUID, targetType TenantType) error {
-	// This is a complex operation that would involve:
-	// 1. Creating new schema/database
-	// 2. Copying data
-	// 3. Updating configuration
-	// 4. Switching connections
-	// 5. Cleaning up old data
-
-	// For now, just update the override configuration
-	tr.config.TenantOverrides[storefrontID.String()] = targetType
-
-	// Invalidate caches
-	tr.InvalidateStorefrontByID(storefrontID)
-
-	return nil // Placeholder implementation
-}
-
 // GetTenantStats retrieves comprehensive tenant statistics with caching
 func (tr *tenantResolver) GetTenantStats(ctx context.Context, storefrontID uuid.UUID) (*TenantStats, error) {
 	// Check cache first
@@ -333,16 +366,57 @@ func (tr *tenantResolver) InvalidateStorefrontByID(storefrontID uuid.UUID) {
 	delete(tr.statsCache, storefrontID)
 	tr.statsCacheMu.Unlock()
 
-	// We'd need to reverse lookup the slug to clear the cache
-	// This is a limitation of the current cache design
+	// Storefronts are cached by slug and domain, so look up both keys
+	storefront, err := tr.storefrontRepo.GetByID(context.Background(), storefrontID)
+	if err != nil || storefront == nil {
+		return
+	}
+	tr.cache.InvalidateStorefront(storefront.Slug)
+	if storefront.Domain != nil {
+		tr.cache.InvalidateStorefront("domain:" + *storefront.Domain)
+	}
 }
 
 // Helper methods
 
-// getTenantDatabase retrieves or creates a database connection for a specific tenant
-func (tr *tenantResolver) getTenantDatabase(storefrontID uuid.UUID) (*sql.DB, error) {
+// setWriteFreeze blocks or allows writes to a storefront on this instance and
+// tells the other instances, which read the freeze from the migration's
+// stored status
+func (tr *tenantResolver) setWriteFreeze(storefrontID uuid.UUID, frozen bool) {
+	tr.mu.Lock()
+	if frozen {
+		tr.frozen[storefrontID] = struct{}{}
+	} else {
+		delete(tr.frozen, storefrontID)
+	}
+	tr.mu.Unlock()
+
+	tr.publishPlacementChange(storefrontID)
+}
+
+// publishPlacementChange tells the other instances to reload placements
+func (tr *tenantResolver) publishPlacementChange(storefrontID uuid.UUID) {
+	if tr.notifier != nil {
+		tr.notifier.PublishPlacementChange(storefrontID)
+	}
+}
+
+// tenantDSN returns the connection string for a tenant's schema or database.
+// Schema tenants connect to the shared database with their schema first in
+// the search path, so unqualified table names resolve to the tenant's tables.
+func (tr *tenantResolver) tenantDSN(storefrontID uuid.UUID, tenantType TenantType) string {
+	if tenantType == TenantTypeSchema {
+		return withSearchPath(tr.config.SharedDatabaseURL, SchemaName(storefrontID)+",public")
+	}
+	return fmt.Sprintf(tr.config.TenantDatabasePattern, storefrontID.String())
+}
+
+// getTenantConnection retrieves or creates a connection pool for a tenant's schema or database
+func (tr *tenantResolver) getTenantConnection(storefrontID uuid.UUID, tenantType TenantType) (*sql.DB, error) {
+	dsn := tr.tenantDSN(storefrontID, tenantType)
+
 	tr.mu.RLock()
-	if db, exists := tr.tenantDBs[storefrontID]; exists {
+	if db, exists := tr.tenantDBs[dsn]; exists {
 		tr.mu.RUnlock()
 		return db, nil
 	}
@@ -353,14 +427,11 @@ func (tr *tenantResolver) getTenantDatabase(storefrontID uuid.UUID) (*sql.DB, er
 	defer tr.mu.Unlock()
 
 	// Double-check pattern
-	if db, exists := tr.tenantDBs[storefrontID]; exists {
+	if db, exists := tr.tenantDBs[dsn]; exists {
 		return db, nil
 	}
 
-	// Generate database URL for this tenant
-	dbURL := fmt.Sprintf(tr.config.TenantDatabasePattern, storefrontID.String())
-
-	db, err := sql.Open("postgres", dbURL)
+	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to tenant database: %w", err)
 	}
@@ -376,10 +447,23 @@ func (tr *tenantResolver) getTenantDatabase(storefrontID uuid.UUID) (*sql.DB, er
 		return nil, fmt.Errorf("failed to ping tenant database: %w", err)
 	}
 
-	tr.tenantDBs[storefrontID] = db
+	tr.tenantDBs[dsn] = db
 	return db, nil
 }
 
+// closeTenantConnection closes the cached pool for a tenant's schema or database
+func (tr *tenantResolver) closeTenantConnection(storefrontID uuid.UUID, tenantType TenantType) {
+	dsn := tr.tenantDSN(storefrontID, tenantType)
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if db, exists := tr.tenantDBs[dsn]; exists {
+		db.Close()
+		delete(tr.tenantDBs, dsn)
+	}
+}
+
 // shouldMigrateToDatabase checks if tenant should be migrated to database isolation
 func (tr *tenantResolver) shouldMigrateToDatabase(stats *TenantStats) bool {
 	threshold := tr.config.MigrationThresholds.DatabaseThreshold
@@ -420,13 +504,16 @@ func (tr *tenantResolver) startCleanup() {
 
 		// Clean up database connections that haven't been used
 		tr.mu.Lock()
-		for storefrontID, db := range tr.tenantDBs {
+		for dsn, db := range tr.tenantDBs {
 			// Check if connection is still valid and close inactive ones
 			if err := db.Ping(); err != nil {
 				db.Close()
-				delete(tr.tenantDBs, storefrontID)
+				delete(tr.tenantDBs, dsn)
 			}
 		}
 		tr.mu.Unlock()
+
+		// Pick up migrations completed or rolled back by other instances
+		_ = tr.loadPlacements(context.Background())
 	}
 }
@@ -286,13 +286,19 @@ func (h *JNEWebhookHandler) HandleWebhook(ctx context.Context, payload []byte) (
 		statusText = jnePayload.Note
 	}
 
-	normalizedStatus := h.normalizeJNEStatus(statusCode, statusText)
+	normalizedStatus := h.normalizeJNEStatus(statusText, statusCode)
+
+	receiverName := jnePayload.ReceiverName
+	if receiverName == "" {
+		receiverName = jnePayload.Details.ReceiverName
+	}
 
 	// Create tracking update
 	update := &TrackingUpdate{
 		TrackingNumber: trackingNumber,
 		CourierCode:    "jne",
 		Status:         normalizedStatus,
+		RawStatus:      statusCode,
 		StatusText:     statusText,
 		Location:       h.buildLocation(jnePayload.Location, jnePayload.City, jnePayload.Office),
 		Timestamp:      eventTime,
@@ -306,7 +312,7 @@ func (h *JNEWebhookHandler) HandleWebhook(ctx context.Context, payload []byte) (
 			"shipment_date":       jnePayload.ShipmentDate,
 			"shipper_name":        jnePayload.ShipperName,
 			"shipper_address":     jnePayload.ShipperAddress,
-			"receiver_name":       jnePayload.ReceiverName,
+			"receiver_name":       receiverName,
 			"receiver_address":    jnePayload.ReceiverAddress,
 			"summary_status":      jnePayload.SummaryStatus,
 			"last_status":         jnePayload.LastStatus,
@@ -364,10 +370,10 @@ func (h *JNEWebhookHandler) ValidateSignature(payload []byte, signature string)
 // normalizeJNEStatus converts JNE status codes to our standard tracking states
 // Based on Ruby reference implementation with delivered codes D01-D12, DB1
 // Updated to handle new webhook format status values
-func (h *JNEWebhookHandler) normalizeJNEStatus(status, statusCode string) TrackingStateTrackingState {
+func (h *JNEWebhookHandler) normalizeJNEStatus(status, statusCode string) TrackingState {
 	// Check if status code indicates delivered status first
 	if h.isDeliveredStatus(statusCode) {
-		return TrackingStateTrackingStateDelivered
+		return TrackingStateDelivered
 	}
 
 	// Use status code for more precise mapping if available
@@ -375,39 +381,39 @@ func (h *JNEWebhookHandler) normalizeJNEStatus(status, statusCode string) Tracki
 		switch strings.ToUpper(statusCode) {
 		// New webhook format status codes
 		case "MANIFESTED", "PICKUPED", "PICKUP_COMPLETED":
-			return TrackingStateTrackingStatePickedUp
+			return TrackingStatePickedUp
 		case "IN_TRANSIT", "INTRANSIT", "ON_TRANSIT":
-			return TrackingStateTrackingStateInTransit
+			return TrackingStateInTransit
 		case "OUT_FOR_DELIVERY", "WITH_DELIVERY_COURIER":
-			return TrackingStateTrackingStateOutForDelivery
+			return TrackingStateOutForDelivery
 		case "DELIVERED", "DELIVERY_COMPLETED":
-			return TrackingStateTrackingStateDelivered
+			return TrackingStateDelivered
 		case "DELIVERY_FAILED", "FAILED":
-			return TrackingStateTrackingStateDeliveryFailed
+			return TrackingStateDeliveryFailed
 		case "RETURN_TO_ORIGIN", "RETURNING":
-			return TrackingStateTrackingStateReturning
+			return TrackingStateReturning
 		case "RETURNED", "RTO":
-			return TrackingStateTrackingStateReturned
+			return TrackingStateReturned
 		case "CANCELLED", "VOID":
-			return TrackingStateTrackingStateException
+			return TrackingStateException
 
 		// Legacy status codes
 		case "BOOKING", "BOOKED", "CREATED", "B01":
-			return TrackingStateTrackingStatePickupPending
+			return TrackingStatePickupPending
 		case "PICKUP", "PICKED", "MANIFEST", "M01", "M02":
-			return TrackingStateTrackingStatePickedUp
+			return TrackingStatePickedUp
 		case "TRANSIT", "SORTING", "T01", "T02", "T03":
-			return TrackingStateTrackingStateInTransit
+			return TrackingStateInTransit
 		case "DELIVERING", "O01", "O02":
-			return TrackingStateTrackingStateOutForDelivery
+			return TrackingStateOutForDelivery
 		case "UNSUCCESSFUL", "F01", "F02", "F03":
-			return TrackingStateTrackingStateDeliveryFailed
+			return TrackingStateDeliveryFailed
 		case "RETURN", "R01", "R02":
-			return TrackingStateTrackingStateReturning
+			return TrackingStateReturning
 		case "R03":
-			return TrackingStateTrackingStateReturned
+			return TrackingStateReturned
 		case "CANCEL", "C01":
-			return TrackingStateTrackingStateException
+			return TrackingStateException
 		}
 	}
 
@@ -416,48 +422,47 @@ func (h *JNEWebhookHandler) normalizeJNEStatus(status, statusCode string) Tracki
 	switch {
 	// New webhook format status mappings
 	case strings.Contains(statusLower, "in_transit"), strings.Contains(statusLower, "in transit"):
-		return TrackingStateTrackingStateInTransit
+		return TrackingStateInTransit
 	case strings.Contains(statusLower, "manifested"):
-		return TrackingStateTrackingStatePickedUp
+		return TrackingStatePickedUp
 	case strings.Contains(statusLower, "pickup_completed"), strings.Contains(statusLower, "pickup completed"):
-		return TrackingStateTrackingStatePickedUp
+		return TrackingStatePickedUp
 	case strings.Contains(statusLower, "out_for_delivery"), strings.Contains(statusLower, "out for delivery"):
-		return TrackingStateTrackingStateOutForDelivery
+		return TrackingStateOutForDelivery
 	case strings.Contains(statusLower, "delivery_completed"), strings.Contains(statusLower, "delivery completed"):
-		return TrackingStateTrackingStateDelivered
+		return TrackingStateDelivered
 	case strings.Contains(statusLower, "delivery_failed"), strings.Contains(statusLower, "delivery failed"):
-		return TrackingStateTrackingStateDeliveryFailed
+		return TrackingStateDeliveryFailed
 	case strings.Contains(statusLower, "return_to_origin"), strings.Contains(statusLower, "return to origin"):
-		return TrackingStateTrackingStateReturning
+		return TrackingStateReturning
 
 	// Legacy status text mappings
 	case strings.Contains(statusLower, "delivered"), strings.Contains(statusLower, "terkirim"),
 		strings.Contains(statusLower, "selesai"), strings.Contains(statusLower, "diterima"):
-		return TrackingStateTrackingStateDelivered
+		return TrackingStateDelivered
 	case strings.Contains(statusLower, "booking"), strings.Contains(statusLower, "created"),
 		strings.Contains(statusLower, "dibuat"):
-		return TrackingStateTrackingStatePickupPending
+		return TrackingStatePickupPending
 	case strings.Contains(statusLower, "pickup"), strings.Contains(statusLower, "picked"),
 		strings.Contains(statusLower, "manifest"), strings.Contains(statusLower, "diambil"):
-		return TrackingStateTrackingStatePickedUp
+		return TrackingStatePickedUp
 	case strings.Contains(statusLower, "transit"), strings.Contains(statusLower, "sorting"),
 		strings.Contains(statusLower, "perjalanan"):
-		return TrackingStateTrackingStateInTransit
-	case strings.Contains(statusLower, "delivering"), strings.Contains(statusLower, "pengiriman"):
-		return TrackingStateTrackingStateOutForDelivery
+		return TrackingStateInTransit
 	case strings.Contains(statusLower, "failed"), strings.Contains(statusLower, "unsuccessful"),
 		strings.Contains(statusLower, "gagal"):
-		return TrackingStateTrackingStateDeliveryFailed
+		return TrackingStateDeliveryFailed
+	case strings.Contains(statusLower, "delivering"), strings.Contains(statusLower, "pengiriman"):
+		return TrackingStateOutForDelivery
+	case strings.Contains(statusLower, "returned"), strings.Contains(statusLower, "dikembalikan"):
+		return TrackingStateReturned
 	case strings.Contains(statusLower, "returning"), strings.Contains(statusLower, "return"):
-		if strings.Contains(statusLower, "returned") || strings.Contains(statusLower, "dikembalikan") {
-			return TrackingStateTrackingStateReturned
-		}
-		return TrackingStateTrackingStateReturning
+		return TrackingStateReturning
 	case strings.Contains(statusLower, "cancelled"), strings.Contains(statusLower, "void"),
 		strings.Contains(statusLower, "dibatalkan"):
-		return TrackingStateTrackingStateException
+		return TrackingStateException
 	default:
-		return TrackingStateTrackingStateUnknown
+		return TrackingStateUnknown
 	}
 }
 
@@ -181,10 +181,16 @@ func (h *NinjaVanWebhookHandler) createTrackingUpdate(nvPayload NinjaVanWebhookP
 		statusText = nvPayload.Status
 	}
 
+	rawStatus := nvPayload.StatusCode
+	if rawStatus == "" {
+		rawStatus = nvPayload.Status
+	}
+
 	return &TrackingUpdate{
 		TrackingNumber: trackingNumber,
 		CourierCode:    "ninjavan",
 		Status:         normalizedStatus,
+		RawStatus:      rawStatus,
 		StatusText:     statusText,
 		Location:       location,
 		Timestamp:      timestamp,
@@ -199,29 +205,29 @@ func (h *NinjaVanWebhookHandler) ValidateSignature(payload []byte, signature str
 }
 
 // normalizeNinjaVanStatus converts NinjaVan status codes to our standard tracking states
-func (h *NinjaVanWebhookHandler) normalizeNinjaVanStatus(status, statusCode string) TrackingStateTrackingState {
+func (h *NinjaVanWebhookHandler) normalizeNinjaVanStatus(status, statusCode string) TrackingState {
 	// Use status code for more precise mapping if available
 	if statusCode != "" {
 		switch strings.ToUpper(statusCode) {
 		case "PENDING", "PENDING_PICKUP", "CREATED", "BOOKED":
-			return TrackingStateTrackingStatePickupPending
+			return TrackingStatePickupPending
 		case "PICKED_UP", "PICKUP_DONE", "COLLECTED", "MANIFEST":
-			return TrackingStateTrackingStatePickedUp
+			return TrackingStatePickedUp
 		case "IN_TRANSIT", "ROUTING", "ARRIVED_AT_ORIGIN", "DEPARTED_FROM_ORIGIN",
 			"ARRIVED_AT_DESTINATION", "SORTING", "ON_VEHICLE", "TRANSIT":
-			return TrackingStateTrackingStateInTransit
+			return TrackingStateInTransit
 		case "OUT_FOR_DELIVERY", "ON_VEHICLE_FOR_DELIVERY", "DELIVERY_PENDING", "DELIVERING":
-			return TrackingStateTrackingStateOutForDelivery
+			return TrackingStateOutForDelivery
 		case "DELIVERED", "COMPLETED", "POD_RECEIVED", "DELIVERY_SUCCESS":
-			return TrackingStateTrackingStateDelivered
+			return TrackingStateDelivered
 		case "FAILED_DELIVERY", "DELIVERY_FAIL", "RECIPIENT_NOT_AVAILABLE", "DELIVERY_FAILED":
-			return TrackingStateTrackingStateDeliveryFailed
+			return TrackingStateDeliveryFailed
 		case "RETURNING", "RETURN_TO_SENDER", "RTO_PENDING":
-			return TrackingStateTrackingStateReturning
+			return TrackingStateReturning
 		case "RETURNED", "RTO_DELIVERED", "CANCELLED":
-			return TrackingStateTrackingStateReturned
+			return TrackingStateReturned
 		case "EXCEPTION", "DAMAGED", "LOST", "VOID":
-			return TrackingStateTrackingStateException
+			return TrackingStateException
 		}
 	}
 
@@ -230,30 +236,30 @@ func (h *NinjaVanWebhookHandler) normalizeNinjaVanStatus(status, statusCode stri
 	switch {
 	case strings.Contains(statusLower, "pending"), strings.Contains(statusLower, "created"),
 		strings.Contains(statusLower, "booked"):
-		return TrackingStateTrackingStatePickupPending
+		return TrackingStatePickupPending
 	case strings.Contains(statusLower, "picked"), strings.Contains(statusLower, "collected"),
 		strings.Contains(statusLower, "manifest"):
-		return TrackingStateTrackingStatePickedUp
+		return TrackingStatePickedUp
 	case strings.Contains(statusLower, "transit"), strings.Contains(statusLower, "routing"),
 		strings.Contains(statusLower, "sorting"), strings.Contains(statusLower, "vehicle"):
-		return TrackingStateTrackingStateInTransit
+		return TrackingStateInTransit
 	case strings.Contains(statusLower, "delivery") && !strings.Contains(statusLower, "delivered"):
-		return TrackingStateTrackingStateOutForDelivery
+		return TrackingStateOutForDelivery
 	case strings.Contains(statusLower, "delivered"), strings.Contains(statusLower, "completed"),
 		strings.Contains(statusLower, "pod"):
-		return TrackingStateTrackingStateDelivered
+		return TrackingStateDelivered
 	case strings.Contains(statusLower, "failed"), strings.Contains(statusLower, "unsuccessful"):
-		return TrackingStateTrackingStateDeliveryFailed
+		return TrackingStateDeliveryFailed
 	case strings.Contains(statusLower, "returning"), strings.Contains(statusLower, "return"):
 		if strings.Contains(statusLower, "returned") {
-			return TrackingStateTrackingStateReturned
+			return TrackingStateReturned
 		}
-		return TrackingStateTrackingStateReturning
+		return TrackingStateReturning
 	case strings.Contains(statusLower, "cancelled"), strings.Contains(statusLower, "void"),
 		strings.Contains(statusLower, "exception"):
-		return TrackingStateTrackingStateException
+		return TrackingStateException
 	default:
-		return TrackingStateTrackingStateUnknown
+		return TrackingStateUnknown
 	}
 }
 
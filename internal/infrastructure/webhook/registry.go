@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registry holds the courier webhook handlers keyed by courier code
+type Registry struct {
+	mu       sync.RWMutex
+	handlers map[string]WebhookHandler
+}
+
+// NewRegistry creates a registry with the given handlers
+func NewRegistry(handlers ...WebhookHandler) *Registry {
+	r := &Registry{
+		handlers: make(map[string]WebhookHandler, len(handlers)),
+	}
+	for _, h := range handlers {
+		r.Register(h)
+	}
+	return r
+}
+
+// Register adds a handler under its courier code, replacing any previous one
+func (r *Registry) Register(h WebhookHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[normalizeCourierCode(h.GetCourierCode())] = h
+}
+
+// Get returns the handler for a courier code
+func (r *Registry) Get(courierCode string) (WebhookHandler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	h, ok := r.handlers[normalizeCourierCode(courierCode)]
+	return h, ok
+}
+
+// CourierCodes returns the registered courier codes in alphabetical order
+func (r *Registry) CourierCodes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	codes := make([]string, 0, len(r.handlers))
+	for code := range r.handlers {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// normalizeCourierCode makes courier codes from URLs and handlers comparable
+func normalizeCourierCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
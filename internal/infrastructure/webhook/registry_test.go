@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_Get(t *testing.T) {
+	registry := NewRegistry(
+		NewJNEWebhookHandler(""),
+		NewSiCepatWebhookHandler(""),
+		NewNinjaVanWebhookHandler(""),
+	)
+
+	tests := []struct {
+		name        string
+		courierCode string
+		found       bool
+	}{
+		{"JNE", "jne", true},
+		{"SiCepat upper case", "SICEPAT", true},
+		{"NinjaVan with whitespace", " ninjavan ", true},
+		{"unknown courier", "jnt", false},
+		{"empty courier", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, ok := registry.Get(tt.courierCode)
+			assert.Equal(t, tt.found, ok)
+			if tt.found {
+				assert.Equal(t, normalizeCourierCode(tt.courierCode), handler.GetCourierCode())
+			}
+		})
+	}
+
+	assert.Equal(t, []string{"jne", "ninjavan", "sicepat"}, registry.CourierCodes())
+}
@@ -175,6 +175,11 @@ func (h *SiCepatWebhookHandler) HandleWebhook(ctx context.Context, payload []byt
 		statusText = shipping.SummaryStatus
 	}
 
+	rawStatus := shipping.SummaryStatus
+	if rawStatus == "" {
+		rawStatus = shipping.LastStatus
+	}
+
 	// Build location from shipping histories if available
 	location := ""
 	if len(shipping.ShipmentHistories) > 0 {
@@ -190,6 +195,7 @@ func (h *SiCepatWebhookHandler) HandleWebhook(ctx context.Context, payload []byt
 		TrackingNumber: trackingNumber,
 		CourierCode:    "sicepat",
 		Status:         normalizedStatus,
+		RawStatus:      rawStatus,
 		StatusText:     statusText,
 		Location:       location,
 		Timestamp:      eventTime,
@@ -291,39 +297,39 @@ func (h *SiCepatWebhookHandler) CreateSuccessResponse() []byte {
 }
 
 // normalizeSiCepatStatus converts SiCepat status codes to our standard tracking states
-func (h *SiCepatWebhookHandler) normalizeSiCepatStatus(summaryStatus, lastStatus string) TrackingStateTrackingState {
+func (h *SiCepatWebhookHandler) normalizeSiCepatStatus(summaryStatus, lastStatus string) TrackingState {
 	// Check both summary and last status for comprehensive mapping
 	status := strings.ToLower(strings.TrimSpace(summaryStatus + " " + lastStatus))
 
 	switch {
 	case strings.Contains(status, "booking"), strings.Contains(status, "created"),
 		strings.Contains(status, "new"), strings.Contains(status, "pending"):
-		return TrackingStateTrackingStatePickupPending
+		return TrackingStatePickupPending
 	case strings.Contains(status, "pickup"), strings.Contains(status, "picked"),
 		strings.Contains(status, "manifest"), strings.Contains(status, "collected"):
-		return TrackingStateTrackingStatePickedUp
+		return TrackingStatePickedUp
 	case strings.Contains(status, "transit"), strings.Contains(status, "processing"),
 		strings.Contains(status, "sorting"), strings.Contains(status, "shipment"):
-		return TrackingStateTrackingStateInTransit
+		return TrackingStateInTransit
 	case strings.Contains(status, "delivering"), strings.Contains(status, "out for delivery"),
 		strings.Contains(status, "with courier"), strings.Contains(status, "on delivery"):
-		return TrackingStateTrackingStateOutForDelivery
+		return TrackingStateOutForDelivery
 	case strings.Contains(status, "delivered"), strings.Contains(status, "pod"),
 		strings.Contains(status, "success"), strings.Contains(status, "complete"):
-		return TrackingStateTrackingStateDelivered
+		return TrackingStateDelivered
 	case strings.Contains(status, "failed"), strings.Contains(status, "unsuccessful"),
 		strings.Contains(status, "problem"), strings.Contains(status, "exception"):
-		return TrackingStateTrackingStateDeliveryFailed
+		return TrackingStateDeliveryFailed
 	case strings.Contains(status, "returning"), strings.Contains(status, "return"):
 		if strings.Contains(status, "returned") || strings.Contains(status, "complete") {
-			return TrackingStateTrackingStateReturned
+			return TrackingStateReturned
 		}
-		return TrackingStateTrackingStateReturning
+		return TrackingStateReturning
 	case strings.Contains(status, "cancelled"), strings.Contains(status, "void"),
 		strings.Contains(status, "cancel"):
-		return TrackingStateTrackingStateException
+		return TrackingStateException
 	default:
-		return TrackingStateTrackingStateUnknown
+		return TrackingStateUnknown
 	}
 }
 
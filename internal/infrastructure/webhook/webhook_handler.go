@@ -9,11 +9,48 @@ import (
 	"time"
 )
 
-// TrackingUpdate represents a tracking update (temporary placeholder)
+// TrackingState is the courier-independent state of a shipment
+type TrackingState string
+
+const (
+	TrackingStateUnknown        TrackingState = "unknown"
+	TrackingStatePickupPending  TrackingState = "pickup_pending"
+	TrackingStatePickedUp       TrackingState = "picked_up"
+	TrackingStateInTransit      TrackingState = "in_transit"
+	TrackingStateOutForDelivery TrackingState = "out_for_delivery"
+	TrackingStateDelivered      TrackingState = "delivered"
+	TrackingStateDeliveryFailed TrackingState = "delivery_failed"
+	TrackingStateReturning      TrackingState = "returning"
+	TrackingStateReturned       TrackingState = "returned"
+	TrackingStateException      TrackingState = "exception"
+)
+
+// String returns the string representation of TrackingState
+func (s TrackingState) String() string {
+	return string(s)
+}
+
+// IsShipped returns true once the courier has taken the parcel
+func (s TrackingState) IsShipped() bool {
+	switch s {
+	case TrackingStatePickedUp, TrackingStateInTransit, TrackingStateOutForDelivery,
+		TrackingStateDelivered, TrackingStateDeliveryFailed, TrackingStateReturning, TrackingStateReturned:
+		return true
+	default:
+		return false
+	}
+}
+
+// TrackingUpdate represents a normalized tracking event pushed by a courier
 type TrackingUpdate struct {
 	TrackingNumber string
-	Status         string
+	CourierCode    string
+	Status         TrackingState
+	RawStatus      string // Status code as sent by the courier
+	StatusText     string
+	Location       string
 	Timestamp      time.Time
+	Metadata       map[string]interface{}
 }
 
 // WebhookHandler defines the interface for handling courier webhooks
@@ -26,6 +63,9 @@ type WebhookHandler interface {
 
 	// GetCourierCode returns the courier code this handler supports
 	GetCourierCode() string
+
+	// HasSecret reports whether a signing secret is configured
+	HasSecret() bool
 }
 
 // BaseWebhookHandler provides common functionality for webhook handlers
@@ -47,11 +87,19 @@ func (h *BaseWebhookHandler) GetCourierCode() string {
 	return h.courierCode
 }
 
+// HasSecret reports whether a signing secret is configured. Handlers without
+// one reject every payload.
+func (h *BaseWebhookHandler) HasSecret() bool {
+	return h.secretKey != ""
+}
+
 // ValidateHMACSignature validates HMAC SHA256 signature (common pattern)
 func (h *BaseWebhookHandler) ValidateHMACSignature(payload []byte, signature string) error {
 	if h.secretKey == "" {
-		// If no secret key configured, skip validation (for testing)
-		return nil
+		return fmt.Errorf("webhook secret is not configured")
+	}
+	if signature == "" {
+		return fmt.Errorf("missing webhook signature")
 	}
 
 	// Create HMAC SHA256 hash
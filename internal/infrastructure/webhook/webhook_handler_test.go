@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestBaseWebhookHandler_ValidateHMACSignature(t *testing.T) {
+	payload := []byte(`{"awb":"JNE123","status":"DELIVERED"}`)
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		wantErr   bool
+	}{
+		{"no secret rejects unsigned payload", "", "", true},
+		{"no secret rejects any signature", "", sign("", payload), true},
+		{"missing signature", "s3cret", "", true},
+		{"wrong signature", "s3cret", sign("other", payload), true},
+		{"valid signature", "s3cret", sign("s3cret", payload), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewBaseWebhookHandler("jne", tt.secret)
+			err := h.ValidateHMACSignature(payload, tt.signature)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestJNEWebhookHandler_ValidateSignature_Unsigned(t *testing.T) {
+	payload := []byte(`{"awb":"JNE123","status":"DELIVERED"}`)
+
+	assert.False(t, NewJNEWebhookHandler("").HasSecret())
+	assert.Error(t, NewJNEWebhookHandler("").ValidateSignature(payload, ""))
+	assert.Error(t, NewJNEWebhookHandler("s3cret").ValidateSignature(payload, ""))
+	assert.NoError(t, NewJNEWebhookHandler("s3cret").ValidateSignature(payload, "sha256="+sign("s3cret", payload)))
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
+	"github.com/kirimku/smartseller-backend/pkg/utils"
+)
+
+// BlockedIPHandler lets platform admins review and lift the IP blocks placed
+// by fraud detection
+type BlockedIPHandler struct {
+	fraudDetector *middleware.FraudDetector
+	logger        *slog.Logger
+}
+
+// NewBlockedIPHandler creates a new blocked IP handler
+func NewBlockedIPHandler(fraudDetector *middleware.FraudDetector, logger *slog.Logger) *BlockedIPHandler {
+	return &BlockedIPHandler{
+		fraudDetector: fraudDetector,
+		logger:        logger,
+	}
+}
+
+// ListBlockedIPs handles listing the IPs currently blocked
+// @Summary List blocked IPs
+// @Description List the IPs blocked after repeated failed attempts, newest first. Blocks are shared by every API replica and expire on their own.
+// @Tags Security
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} []ratelimit.BlockedIP
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/security/blocked-ips [get]
+func (h *BlockedIPHandler) ListBlockedIPs(c *gin.Context) {
+	blocks, err := h.fraudDetector.ListBlocked(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list blocked IPs", slog.String("error", err.Error()))
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to list blocked IPs", nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Blocked IPs retrieved successfully", blocks)
+}
+
+// UnblockIP handles lifting the block of an IP
+// @Summary Unblock an IP
+// @Description Lift the block of an IP and forget its failed attempts
+// @Tags Security
+// @Produce json
+// @Security BearerAuth
+// @Param ip path string true "Blocked IP address"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/security/blocked-ips/{ip} [delete]
+func (h *BlockedIPHandler) UnblockIP(c *gin.Context) {
+	ip := c.Param("ip")
+	if net.ParseIP(ip) == nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid IP address", nil)
+		return
+	}
+
+	unblocked, err := h.fraudDetector.Unblock(c.Request.Context(), ip)
+	if err != nil {
+		h.logger.Error("Failed to unblock IP", slog.String("ip", ip), slog.String("error", err.Error()))
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to unblock IP", nil)
+		return
+	}
+	if !unblocked {
+		utils.ErrorResponse(c, http.StatusNotFound, "IP is not blocked", nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "IP unblocked successfully", nil)
+}
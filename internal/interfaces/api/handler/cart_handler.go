@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
+	"github.com/kirimku/smartseller-backend/pkg/utils"
+)
+
+// CartHandler handles storefront shopping cart requests for customers and guests
+type CartHandler struct {
+	cartUseCase usecase.CartUseCase
+	logger      *slog.Logger
+}
+
+// NewCartHandler creates a new cart handler
+func NewCartHandler(cartUseCase usecase.CartUseCase, logger *slog.Logger) *CartHandler {
+	return &CartHandler{
+		cartUseCase: cartUseCase,
+		logger:      logger,
+	}
+}
+
+// GetCart handles retrieving the current cart
+// @Summary Get cart
+// @Description Get the customer's cart, or the guest cart identified by the X-Cart-Session header, with prices and stock revalidated
+// @Tags Storefront Cart
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param X-Cart-Session header string false "Guest cart session"
+// @Success 200 {object} dto.CartResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/cart [get]
+func (h *CartHandler) GetCart(c *gin.Context) {
+	owner, ok := getCartOwner(c, false)
+	if !ok {
+		return
+	}
+
+	cart, err := h.cartUseCase.GetCart(c.Request.Context(), owner)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve cart")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Cart retrieved successfully", cart)
+}
+
+// AddItem handles adding a product to the cart
+// @Summary Add cart item
+// @Description Add a product or variant to the cart. Guests without a cart session receive a new one in the X-Cart-Session header.
+// @Tags Storefront Cart
+// @Accept json
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param X-Cart-Session header string false "Guest cart session"
+// @Param request body dto.AddCartItemRequest true "Item to add"
+// @Success 200 {object} dto.CartResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/cart/items [post]
+func (h *CartHandler) AddItem(c *gin.Context) {
+	owner, ok := getCartOwner(c, true)
+	if !ok {
+		return
+	}
+
+	var req dto.AddCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	cart, err := h.cartUseCase.AddItem(c.Request.Context(), owner, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to add item to cart")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Item added to cart", cart)
+}
+
+// UpdateItem handles changing the quantity of a cart item
+// @Summary Update cart item
+// @Description Change the quantity of a cart item. A quantity of zero removes the item.
+// @Tags Storefront Cart
+// @Accept json
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param id path string true "Cart item ID"
+// @Param X-Cart-Session header string false "Guest cart session"
+// @Param request body dto.UpdateCartItemRequest true "New quantity"
+// @Success 200 {object} dto.CartResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/cart/items/{id} [put]
+func (h *CartHandler) UpdateItem(c *gin.Context) {
+	owner, ok := getCartOwner(c, false)
+	if !ok {
+		return
+	}
+
+	itemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid cart item ID format", err)
+		return
+	}
+
+	var req dto.UpdateCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+	if req.Quantity < 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Quantity must not be negative", nil)
+		return
+	}
+
+	cart, err := h.cartUseCase.UpdateItem(c.Request.Context(), owner, itemID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update cart item")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Cart item updated", cart)
+}
+
+// RemoveItem handles removing an item from the cart
+// @Summary Remove cart item
+// @Description Remove an item from the cart
+// @Tags Storefront Cart
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param id path string true "Cart item ID"
+// @Param X-Cart-Session header string false "Guest cart session"
+// @Success 200 {object} dto.CartResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/cart/items/{id} [delete]
+func (h *CartHandler) RemoveItem(c *gin.Context) {
+	owner, ok := getCartOwner(c, false)
+	if !ok {
+		return
+	}
+
+	itemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid cart item ID format", err)
+		return
+	}
+
+	cart, err := h.cartUseCase.RemoveItem(c.Request.Context(), owner, itemID)
+	if err != nil {
+		h.handleError(c, err, "Failed to remove cart item")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Cart item removed", cart)
+}
+
+// ClearCart handles removing every item from the cart
+// @Summary Clear cart
+// @Description Remove all items from the cart
+// @Tags Storefront Cart
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param X-Cart-Session header string false "Guest cart session"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/cart [delete]
+func (h *CartHandler) ClearCart(c *gin.Context) {
+	owner, ok := getCartOwner(c, false)
+	if !ok {
+		return
+	}
+
+	if err := h.cartUseCase.ClearCart(c.Request.Context(), owner); err != nil {
+		h.handleError(c, err, "Failed to clear cart")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Cart cleared", nil)
+}
+
+// getCartOwner identifies the cart owner from the customer token or, for
+// guests, the cart session. When create is set a guest without a session is
+// issued a new one.
+func getCartOwner(c *gin.Context, create bool) (usecase.CartOwner, bool) {
+	storefrontID, exists := middleware.GetCustomerStorefrontID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Storefront context required", nil)
+		return usecase.CartOwner{}, false
+	}
+
+	owner := usecase.CartOwner{StorefrontID: storefrontID}
+
+	if customerIDStr, ok := middleware.GetCustomerID(c); ok {
+		customerID, err := uuid.Parse(customerIDStr)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid customer ID", nil)
+			return usecase.CartOwner{}, false
+		}
+		owner.CustomerID = &customerID
+		return owner, true
+	}
+
+	owner.SessionID = middleware.GetCartSessionID(c)
+	if owner.SessionID == "" && create {
+		owner.SessionID = uuid.New().String()
+	}
+	if owner.SessionID != "" {
+		middleware.SetCartSessionID(c, owner.SessionID)
+	}
+
+	return owner, true
+}
+
+// handleError maps cart use case errors to HTTP responses
+func (h *CartHandler) handleError(c *gin.Context, err error, message string) {
+	status := cartErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(message, slog.String("error", err.Error()))
+		utils.ErrorResponse(c, status, message, nil)
+		return
+	}
+
+	utils.ErrorResponse(c, status, err.Error(), nil)
+}
+
+// cartErrorStatus maps cart use case errors to HTTP status codes
+func cartErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "guest carts are not enabled"):
+		return http.StatusUnauthorized
+	case strings.Contains(msg, "insufficient stock"):
+		return http.StatusConflict
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "not available"),
+		strings.Contains(msg, "required"),
+		strings.Contains(msg, "invalid"),
+		strings.Contains(msg, "must"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
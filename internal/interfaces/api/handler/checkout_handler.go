@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/pkg/utils"
+)
+
+// CheckoutHandler handles storefront checkout requests
+type CheckoutHandler struct {
+	checkoutUseCase usecase.CheckoutUseCase
+	logger          *slog.Logger
+}
+
+// NewCheckoutHandler creates a new checkout handler
+func NewCheckoutHandler(checkoutUseCase usecase.CheckoutUseCase, logger *slog.Logger) *CheckoutHandler {
+	return &CheckoutHandler{
+		checkoutUseCase: checkoutUseCase,
+		logger:          logger,
+	}
+}
+
+// CreateOrder handles placing an order from the customer's cart
+// @Summary Checkout
+// @Description Validate the items in the caller's cart (customer or X-Cart-Session), reserve stock, place a pending order and empty the cart. Guests may check out when the storefront enables guest checkout. Stock is released if payment is not received before reservation_expires_at.
+// @Tags Storefront Checkout
+// @Accept json
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param request body dto.CheckoutRequest true "Checkout request"
+// @Success 201 {object} dto.CheckoutResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/checkout [post]
+func (h *CheckoutHandler) CreateOrder(c *gin.Context) {
+	owner, ok := getCartOwner(c, false)
+	if !ok {
+		return
+	}
+
+	var req dto.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	result, err := h.checkoutUseCase.Checkout(c.Request.Context(), owner, &req)
+	if err != nil {
+		status := checkoutErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			h.logger.Error("Failed to checkout",
+				slog.String("error", err.Error()),
+				slog.String("storefront_id", owner.StorefrontID.String()))
+			utils.ErrorResponse(c, status, "Failed to place order", nil)
+			return
+		}
+		utils.ErrorResponse(c, status, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Order placed successfully", result)
+}
+
+// checkoutErrorStatus maps checkout use case errors to HTTP status codes
+func checkoutErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "insufficient stock"):
+		return http.StatusConflict
+	case strings.Contains(msg, "guest checkout is not enabled"):
+		return http.StatusUnauthorized
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "cart is empty"),
+		strings.Contains(msg, "not available"),
+		strings.Contains(msg, "not allowed"),
+		strings.Contains(msg, "not accepting"),
+		strings.Contains(msg, "required"),
+		strings.Contains(msg, "invalid"),
+		strings.Contains(msg, "must"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,300 +1,287 @@
 package handler
 
 import (
-	"fmt"
-	"io"
+	"errors"
 	"log/slog"
-	"mime/multipart"
+	"mime"
 	"net/http"
-	"path/filepath"
-	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
 	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/pkg/utils"
 )
 
+// maxClaimAttachmentRequestSize caps an upload request: the largest allowed
+// attachment plus room for the rest of the form
+const maxClaimAttachmentRequestSize = entity.MaxFileSizeVideo + 1<<20
+
 // ClaimAttachmentHandler handles claim attachment-related HTTP requests
 type ClaimAttachmentHandler struct {
-	logger *slog.Logger
+	attachmentUseCase usecase.ClaimAttachmentUseCase
+	storefrontRepo    repository.StorefrontRepository
+	logger            *slog.Logger
 }
 
 // NewClaimAttachmentHandler creates a new claim attachment handler
-func NewClaimAttachmentHandler() *ClaimAttachmentHandler {
+func NewClaimAttachmentHandler(attachmentUseCase usecase.ClaimAttachmentUseCase, storefrontRepo repository.StorefrontRepository, logger *slog.Logger) *ClaimAttachmentHandler {
 	return &ClaimAttachmentHandler{
-		logger: slog.Default(),
+		attachmentUseCase: attachmentUseCase,
+		storefrontRepo:    storefrontRepo,
+		logger:            logger,
 	}
 }
 
-// ListAttachments handles GET /api/v1/admin/warranty/claims/:claim_id/attachments
+// ListAttachments handles listing the attachments of a claim
+// @Summary List claim attachments
+// @Description List the attachments of a warranty claim with their virus scan status
+// @Tags warranty-claims
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Claim ID"
+// @Success 200 {object} dto.SuccessResponse{data=dto.ClaimAttachmentListResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/claims/{id}/attachments [get]
 func (h *ClaimAttachmentHandler) ListAttachments(c *gin.Context) {
-	claimID := c.Param("id")
-	
-	// Validate claim ID
-	if claimID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_failed",
-			"message": "Claim ID is required",
-		})
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	claimID, ok := parseUUIDParam(c, "id", "claim ID")
+	if !ok {
 		return
 	}
 
-	// Mock implementation - replace with actual service call
-	attachments := []*dto.ClaimAttachmentResponse{
-		{
-			ID:                 "att_001",
-			ClaimID:            claimID,
-			FileName:           "receipt.pdf",
-			FilePath:           "/uploads/claims/2024/01/receipt.pdf",
-			FileURL:            "https://cdn.example.com/claims/receipt.pdf",
-			FileSize:           1024000,
-			FileType:           "pdf",
-			MimeType:           "application/pdf",
-			AttachmentType:     "receipt",
-			UploadedBy:         "user_123",
-			SecurityScanStatus: "passed",
-			CreatedAt:          time.Now().Add(-24 * time.Hour),
-			UpdatedAt:          time.Now().Add(-12 * time.Hour),
-		},
-		{
-			ID:                 "att_002",
-			ClaimID:            claimID,
-			FileName:           "damage_photo.jpg",
-			FilePath:           "/uploads/claims/2024/01/damage_photo.jpg",
-			FileURL:            "https://cdn.example.com/claims/damage_photo.jpg",
-			FileSize:           2048000,
-			FileType:           "jpg",
-			MimeType:           "image/jpeg",
-			AttachmentType:     "photo",
-			UploadedBy:         "user_123",
-			SecurityScanStatus: "pending",
-			CreatedAt:          time.Now().Add(-12 * time.Hour),
-			UpdatedAt:          time.Now().Add(-12 * time.Hour),
-		},
+	attachments, err := h.attachmentUseCase.ListAttachments(c.Request.Context(), storefrontID, claimID)
+	if err != nil {
+		h.handleError(c, err, "Failed to list claim attachments", slog.String("claim_id", claimID.String()))
+		return
 	}
 
-	response := dto.ClaimAttachmentListResponse{
+	utils.SuccessResponse(c, http.StatusOK, "Claim attachments retrieved successfully", dto.ClaimAttachmentListResponse{
 		Attachments: attachments,
 		Total:       len(attachments),
-	}
-
-	h.logger.Info("Listed claim attachments", "claim_id", claimID, "count", len(attachments))
-	c.JSON(http.StatusOK, response)
+	})
 }
 
-// UploadAttachment handles POST /api/v1/admin/warranty/claims/:claim_id/attachments/upload
+// UploadAttachment handles uploading a file to a claim
+// @Summary Upload claim attachment
+// @Description Upload a file to a warranty claim. The file is stored with its SHA-256 checksum and virus scanned in the background; it can be downloaded once the scan passes.
+// @Tags warranty-claims
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Claim ID"
+// @Param file formData file true "Attachment"
+// @Param attachment_type formData string false "Attachment type, detected from the file when omitted" Enums(receipt, photo, invoice, document, video, other)
+// @Param description formData string false "Description"
+// @Success 201 {object} dto.SuccessResponse{data=dto.ClaimAttachmentResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 413 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/claims/{id}/attachments/upload [post]
 func (h *ClaimAttachmentHandler) UploadAttachment(c *gin.Context) {
-	claimID := c.Param("claim_id")
-	
-	// Validate claim ID
-	if claimID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_failed",
-			"message": "Claim ID is required",
-		})
+	userID, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
 		return
 	}
-
-	// Parse multipart form
-	err := c.Request.ParseMultipartForm(32 << 20) // 32 MB max
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid_form",
-			"message": "Failed to parse multipart form",
-		})
+	claimID, ok := parseUUIDParam(c, "id", "claim ID")
+	if !ok {
 		return
 	}
 
-	// Get file from form
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxClaimAttachmentRequestSize)
 	file, header, err := c.Request.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "file_required",
-			"message": "File is required",
-		})
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, "File too large", nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusBadRequest, "File is required", nil)
 		return
 	}
 	defer file.Close()
 
-	// Validate file
-	if err := h.validateFile(header); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_failed",
-			"message": err.Error(),
-		})
-		return
+	upload := &usecase.ClaimAttachmentUpload{
+		Filename:       header.Filename,
+		ContentType:    header.Header.Get("Content-Type"),
+		Size:           header.Size,
+		AttachmentType: c.PostForm("attachment_type"),
+		Content:        file,
 	}
-
-	// Security scan (mock implementation)
-	if err := h.scanFileForViruses(file); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "security_scan_failed",
-			"message": "File failed security scan",
-		})
-		return
+	if description := c.PostForm("description"); description != "" {
+		upload.Description = &description
 	}
 
-	// Mock file upload - replace with actual file storage service
-	attachmentID := fmt.Sprintf("att_%d", time.Now().Unix())
-	
-	response := dto.ClaimAttachmentResponse{
-		ID:                 attachmentID,
-		ClaimID:            claimID,
-		FileName:           header.Filename,
-		FilePath:           fmt.Sprintf("/uploads/claims/%s/%s", claimID, header.Filename),
-		FileURL:            fmt.Sprintf("https://cdn.example.com/claims/%s", header.Filename),
-		FileSize:           header.Size,
-		FileType:           strings.TrimPrefix(filepath.Ext(header.Filename), "."),
-		MimeType:           header.Header.Get("Content-Type"),
-		AttachmentType:     "document", // Default type
-		UploadedBy:         "current_user", // Replace with actual user ID
-		SecurityScanStatus: "pending",
-		CreatedAt:          time.Now(),
-		UpdatedAt:          time.Now(),
+	attachment, err := h.attachmentUseCase.UploadAttachment(c.Request.Context(), storefrontID, claimID, userID, upload)
+	if err != nil {
+		h.handleError(c, err, "Failed to upload claim attachment", slog.String("claim_id", claimID.String()))
+		return
 	}
 
-	h.logger.Info("Uploaded attachment", "claim_id", claimID, "file_name", header.Filename, "attachment_id", attachmentID)
-	c.JSON(http.StatusCreated, response)
+	utils.SuccessResponse(c, http.StatusCreated, "Attachment uploaded and queued for virus scan", attachment)
 }
 
-// DownloadAttachment handles GET /api/v1/admin/warranty/claims/:claim_id/attachments/:attachment_id/download
+// DownloadAttachment handles downloading an attachment
+// @Summary Download claim attachment
+// @Description Download an attachment that passed its virus scan. Pending, infected and rejected attachments cannot be downloaded.
+// @Tags warranty-claims
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path string true "Claim ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/claims/{id}/attachments/{attachment_id}/download [get]
 func (h *ClaimAttachmentHandler) DownloadAttachment(c *gin.Context) {
-	claimID := c.Param("claim_id")
-	attachmentID := c.Param("attachment_id")
-	
-	// Validate parameters
-	if claimID == "" || attachmentID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_failed",
-			"message": "Claim ID and attachment ID are required",
-		})
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	claimID, ok := parseUUIDParam(c, "id", "claim ID")
+	if !ok {
+		return
+	}
+	attachmentID, ok := parseUUIDParam(c, "attachment_id", "attachment ID")
+	if !ok {
 		return
 	}
 
-	// Mock implementation - replace with actual file retrieval
-	// In real implementation, you would:
-	// 1. Verify attachment belongs to claim
-	// 2. Check user permissions
-	// 3. Retrieve file from storage
-	// 4. Stream file to response
+	download, err := h.attachmentUseCase.DownloadAttachment(c.Request.Context(), storefrontID, claimID, attachmentID)
+	if err != nil {
+		h.handleError(c, err, "Failed to download claim attachment", slog.String("attachment_id", attachmentID.String()))
+		return
+	}
+	defer download.Content.Close()
 
-	h.logger.Info("Downloaded attachment", "claim_id", claimID, "attachment_id", attachmentID)
-	
-	// Mock file response
-	c.Header("Content-Disposition", "attachment; filename=sample_file.pdf")
-	c.Header("Content-Type", "application/pdf")
-	c.String(http.StatusOK, "Mock file content")
+	headers := map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": download.Filename}),
+		"X-Content-Type-Options": "nosniff",
+	}
+	if download.Checksum != "" {
+		headers["ETag"] = `"` + download.Checksum + `"`
+	}
+	c.DataFromReader(http.StatusOK, download.Size, download.MimeType, download.Content, headers)
 }
 
-// DeleteAttachment handles DELETE /api/v1/admin/warranty/claims/:claim_id/attachments/:attachment_id
+// DeleteAttachment handles deleting an attachment
+// @Summary Delete claim attachment
+// @Description Delete an attachment and its stored file
+// @Tags warranty-claims
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Claim ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/claims/{id}/attachments/{attachment_id} [delete]
 func (h *ClaimAttachmentHandler) DeleteAttachment(c *gin.Context) {
-	claimID := c.Param("claim_id")
-	attachmentID := c.Param("attachment_id")
-	
-	// Validate parameters
-	if claimID == "" || attachmentID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_failed",
-			"message": "Claim ID and attachment ID are required",
-		})
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	claimID, ok := parseUUIDParam(c, "id", "claim ID")
+	if !ok {
+		return
+	}
+	attachmentID, ok := parseUUIDParam(c, "attachment_id", "attachment ID")
+	if !ok {
 		return
 	}
 
-	// Mock implementation - replace with actual deletion logic
-	// In real implementation, you would:
-	// 1. Verify attachment belongs to claim
-	// 2. Check user permissions
-	// 3. Delete file from storage
-	// 4. Remove database record
+	if err := h.attachmentUseCase.DeleteAttachment(c.Request.Context(), storefrontID, claimID, attachmentID); err != nil {
+		h.handleError(c, err, "Failed to delete claim attachment", slog.String("attachment_id", attachmentID.String()))
+		return
+	}
 
-	h.logger.Info("Deleted attachment", "claim_id", claimID, "attachment_id", attachmentID)
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Attachment deleted successfully",
-	})
+	utils.SuccessResponse(c, http.StatusOK, "Attachment deleted successfully", nil)
 }
 
-// ApproveAttachment handles POST /api/v1/admin/warranty/claims/:claim_id/attachments/:attachment_id/approve
+// ApproveAttachment handles approving or rejecting an attachment
+// @Summary Review claim attachment
+// @Description Approve an attachment that passed its virus scan, or reject it so it can no longer be downloaded
+// @Tags warranty-claims
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Claim ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Param request body dto.AttachmentApprovalRequest true "Review decision"
+// @Success 200 {object} dto.SuccessResponse{data=dto.ClaimAttachmentResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/claims/{id}/attachments/{attachment_id}/approve [post]
 func (h *ClaimAttachmentHandler) ApproveAttachment(c *gin.Context) {
-	claimID := c.Param("claim_id")
-	attachmentID := c.Param("attachment_id")
-	
-	// Validate parameters
-	if claimID == "" || attachmentID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_failed",
-			"message": "Claim ID and attachment ID are required",
-		})
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	claimID, ok := parseUUIDParam(c, "id", "claim ID")
+	if !ok {
+		return
+	}
+	attachmentID, ok := parseUUIDParam(c, "attachment_id", "attachment ID")
+	if !ok {
 		return
 	}
 
 	var req dto.AttachmentApprovalRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid_request",
-			"message": "Invalid request body",
-		})
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
 		return
 	}
 
-	// Mock implementation - replace with actual approval logic
-	now := time.Now()
-	approved := req.Action == "approve"
-	
-	response := dto.ClaimAttachmentResponse{
-		ID:                 attachmentID,
-		ClaimID:            claimID,
-		FileName:           "sample_file.pdf",
-		FilePath:           "/uploads/claims/sample/sample_file.pdf",
-		FileURL:            "https://cdn.example.com/claims/sample_file.pdf",
-		FileSize:           1024000,
-		FileType:           "pdf",
-		MimeType:           "application/pdf",
-		AttachmentType:     "document",
-		UploadedBy:         "user_123",
-		SecurityScanStatus: "passed",
-		CreatedAt:          now.Add(-24 * time.Hour),
-		UpdatedAt:          now,
+	attachment, err := h.attachmentUseCase.ReviewAttachment(c.Request.Context(), storefrontID, claimID, attachmentID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to review claim attachment", slog.String("attachment_id", attachmentID.String()))
+		return
 	}
 
-	h.logger.Info("Processed attachment approval", "claim_id", claimID, "attachment_id", attachmentID, "action", req.Action, "approved", approved)
-	c.JSON(http.StatusOK, response)
+	utils.SuccessResponse(c, http.StatusOK, "Attachment reviewed successfully", attachment)
 }
 
-// validateFile validates uploaded file
-func (h *ClaimAttachmentHandler) validateFile(header *multipart.FileHeader) error {
-	// Check file size (10MB limit)
-	maxSize := int64(10 << 20) // 10 MB
-	if header.Size > maxSize {
-		return fmt.Errorf("file size exceeds limit of %d bytes", maxSize)
+// handleError maps claim attachment use case errors to HTTP responses
+func (h *ClaimAttachmentHandler) handleError(c *gin.Context, err error, message string, attrs ...any) {
+	if errors.Is(err, usecase.ErrAttachmentUnavailable) {
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+		return
 	}
 
-	// Check file extension
-	allowedExtensions := []string{".pdf", ".jpg", ".jpeg", ".png", ".gif", ".doc", ".docx", ".txt"}
-	ext := strings.ToLower(filepath.Ext(header.Filename))
-	
-	for _, allowed := range allowedExtensions {
-		if ext == allowed {
-			return nil
-		}
+	status := warrantyClaimErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(message, append(attrs, slog.String("error", err.Error()))...)
+		utils.ErrorResponse(c, status, message, nil)
+		return
 	}
-	
-	return fmt.Errorf("file type %s is not allowed", ext)
+
+	utils.ErrorResponse(c, status, err.Error(), nil)
 }
 
-// scanFileForViruses performs virus scanning (mock implementation)
-func (h *ClaimAttachmentHandler) scanFileForViruses(file multipart.File) error {
-	// Mock implementation - always pass
-	// In real implementation, integrate with antivirus service
-	
-	// Read first few bytes to simulate scanning
-	buffer := make([]byte, 1024)
-	_, err := file.Read(buffer)
-	if err != nil && err != io.EOF {
-		return fmt.Errorf("failed to read file for scanning")
+// parseUUIDParam reads a UUID path parameter, responding 400 when it is malformed
+func parseUUIDParam(c *gin.Context, name, label string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid "+label+" format", nil)
+		return uuid.Nil, false
 	}
-	
-	// Reset file pointer
-	file.Seek(0, 0)
-	
-	return nil
-}
\ No newline at end of file
+	return id, true
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/service"
+	"github.com/kirimku/smartseller-backend/internal/domain/errors"
+	"github.com/kirimku/smartseller-backend/pkg/utils"
+)
+
+// CourierBookingHandler handles booking order parcels with couriers from the
+// seller dashboard
+type CourierBookingHandler struct {
+	bookingService *service.CourierBookingService
+	logger         *slog.Logger
+}
+
+// NewCourierBookingHandler creates a new courier booking handler
+func NewCourierBookingHandler(bookingService *service.CourierBookingService, logger *slog.Logger) *CourierBookingHandler {
+	return &CourierBookingHandler{
+		bookingService: bookingService,
+		logger:         logger,
+	}
+}
+
+// BookOrder handles booking an order's parcel with a courier
+// @Summary Book courier
+// @Description Book a paid order's parcel with J&T, SiCepat or SAPX and get its AWB. Pass a pickup date and slot start from the pickup times endpoint to have the courier collect it; leave them out to drop the parcel off. The AWB becomes the order's tracking number and the order ships on the courier's first scan.
+// @Tags Admin Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body dto.CourierBookingRequest true "Courier, service and pickup slot"
+// @Success 201 {object} dto.CourierBookingResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/orders/{id}/booking [post]
+func (h *CourierBookingHandler) BookOrder(c *gin.Context) {
+	sellerID, orderID, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CourierBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	booking, err := h.bookingService.Book(c.Request.Context(), orderID, sellerID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to book courier", orderID)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Courier booked successfully", booking)
+}
+
+// GetBooking handles retrieving an order's courier booking
+// @Summary Get courier booking
+// @Description Get the active courier booking of an order with its AWB and pickup slot
+// @Tags Admin Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} dto.CourierBookingResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/orders/{id}/booking [get]
+func (h *CourierBookingHandler) GetBooking(c *gin.Context) {
+	sellerID, orderID, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	booking, err := h.bookingService.GetBooking(c.Request.Context(), orderID, sellerID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve courier booking", orderID)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Courier booking retrieved successfully", booking)
+}
+
+// GetPickupTimes handles listing a courier's pickup slots for an order
+// @Summary List pickup times
+// @Description List the courier's pickup slots on a day in the storefront's timezone. Slots starting too soon are marked unavailable.
+// @Tags Admin Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param courier query string true "Courier" example(sicepat)
+// @Param date query string false "Day as YYYY-MM-DD, defaults to today" example(2026-10-17)
+// @Success 200 {object} dto.PickupTimesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/orders/{id}/pickup-times [get]
+func (h *CourierBookingHandler) GetPickupTimes(c *gin.Context) {
+	sellerID, orderID, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	times, err := h.bookingService.PickupTimes(c.Request.Context(), orderID, sellerID, c.Query("courier"), c.Query("date"))
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve pickup times", orderID)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pickup times retrieved successfully", times)
+}
+
+// parseRequest reads the authenticated seller and the order ID, writing the
+// error response when either is missing or malformed
+func (h *CourierBookingHandler) parseRequest(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Authentication required", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	sellerID, err := uuid.Parse(userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return sellerID, orderID, true
+}
+
+// handleError maps courier booking service errors to HTTP responses
+func (h *CourierBookingHandler) handleError(c *gin.Context, err error, message string, orderID uuid.UUID) {
+	status := customerSessionErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(message,
+			slog.String("order_id", orderID.String()),
+			slog.String("error", err.Error()))
+		utils.ErrorResponse(c, status, message, nil)
+		return
+	}
+
+	if appErr, ok := err.(*errors.AppError); ok {
+		utils.ErrorResponse(c, status, appErr.Message, nil)
+		return
+	}
+	utils.ErrorResponse(c, status, err.Error(), nil)
+}
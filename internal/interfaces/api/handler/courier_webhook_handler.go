@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/pkg/utils"
+)
+
+// maxCourierWebhookBodySize caps the payload accepted from a courier
+const maxCourierWebhookBodySize = 1 << 20
+
+// courierSignatureHeaders lists the headers couriers send their HMAC signature in
+var courierSignatureHeaders = []string{
+	"X-Signature",
+	"X-Webhook-Signature",
+	"X-Hub-Signature-256",
+	"X-JNE-Signature",
+	"X-SiCepat-Signature",
+	"X-NinjaVan-Hmac-SHA256",
+}
+
+// courierWebhookRedactedHeaders are never stored with a delivery
+var courierWebhookRedactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+}
+
+// CourierWebhookHandler handles tracking pushes from courier partners
+type CourierWebhookHandler struct {
+	courierWebhookUseCase usecase.CourierWebhookUseCase
+	logger                *slog.Logger
+}
+
+// NewCourierWebhookHandler creates a new courier webhook handler
+func NewCourierWebhookHandler(courierWebhookUseCase usecase.CourierWebhookUseCase, logger *slog.Logger) *CourierWebhookHandler {
+	return &CourierWebhookHandler{
+		courierWebhookUseCase: courierWebhookUseCase,
+		logger:                logger,
+	}
+}
+
+// HandleWebhook handles a tracking push from a courier
+// @Summary Receive courier webhook
+// @Description Receive a tracking update from a courier (jne, sicepat, ninjavan). The raw payload is stored, its HMAC signature validated and the tracking updates applied to orders and warranty claim shipments.
+// @Tags Courier Webhooks
+// @Accept json
+// @Produce json
+// @Param courier path string true "Courier code"
+// @Param X-Signature header string false "HMAC SHA256 signature of the body"
+// @Success 200 {object} dto.CourierWebhookResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /webhooks/couriers/{courier} [post]
+func (h *CourierWebhookHandler) HandleWebhook(c *gin.Context) {
+	courierCode := c.Param("courier")
+
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxCourierWebhookBodySize+1))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read request body", err)
+		return
+	}
+	if len(payload) > maxCourierWebhookBodySize {
+		utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, "Request body too large", nil)
+		return
+	}
+	if len(payload) == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Request body is required", nil)
+		return
+	}
+
+	result, err := h.courierWebhookUseCase.HandleWebhook(c.Request.Context(), courierCode, payload, courierWebhookHeaders(c), courierSignature(c), c.ClientIP())
+	if err != nil {
+		h.handleError(c, err, "Failed to process courier webhook", slog.String("courier", courierCode))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhook processed successfully", result)
+}
+
+// ListEvents handles listing stored courier webhook deliveries
+// @Summary List courier webhooks
+// @Description Get a paginated list of stored courier webhook deliveries, newest first. Filter by status=dead_lettered to find deliveries whose retries were exhausted.
+// @Tags Courier Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page" default(20)
+// @Param courier query string false "Filter by courier code"
+// @Param status query string false "Filter by processing status" Enums(received, processed, failed, dead_lettered, rejected)
+// @Param tracking_number query string false "Filter by a tracking number contained in the payload"
+// @Param date_from query string false "Deliveries received on or after this date (YYYY-MM-DD)"
+// @Param date_to query string false "Deliveries received on or before this date (YYYY-MM-DD)"
+// @Success 200 {object} dto.CourierWebhookEventListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/webhooks/couriers/events [get]
+func (h *CourierWebhookHandler) ListEvents(c *gin.Context) {
+	filters, err := parseCourierWebhookEventFilters(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	filters.Page, filters.PageSize = parseOrderPagination(c)
+
+	result, err := h.courierWebhookUseCase.ListEvents(c.Request.Context(), filters)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve courier webhooks")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhooks retrieved successfully", result)
+}
+
+// GetEvent handles inspecting a stored courier webhook delivery
+// @Summary Get courier webhook
+// @Description Get a stored courier webhook delivery with its raw payload, headers, signature check result and processing outcome
+// @Tags Courier Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook event ID"
+// @Success 200 {object} dto.CourierWebhookEventResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/webhooks/couriers/events/{id} [get]
+func (h *CourierWebhookHandler) GetEvent(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid webhook event ID format", err)
+		return
+	}
+
+	result, err := h.courierWebhookUseCase.GetEvent(c.Request.Context(), eventID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve courier webhook", slog.String("event_id", eventID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhook retrieved successfully", result)
+}
+
+// ReplayEvent handles processing a stored courier webhook again
+// @Summary Replay courier webhook
+// @Description Process a stored courier webhook delivery again, e.g. after a status mapping fix. Dead-lettered deliveries are only retried this way. Deliveries rejected for an invalid signature cannot be replayed.
+// @Tags Courier Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook event ID"
+// @Success 200 {object} dto.CourierWebhookResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/webhooks/couriers/events/{id}/replay [post]
+func (h *CourierWebhookHandler) ReplayEvent(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid webhook event ID format", err)
+		return
+	}
+
+	result, err := h.courierWebhookUseCase.ReplayEvent(c.Request.Context(), eventID)
+	if err != nil {
+		h.handleError(c, err, "Failed to replay courier webhook", slog.String("event_id", eventID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhook replayed successfully", result)
+}
+
+// handleError maps courier webhook use case errors to HTTP responses
+func (h *CourierWebhookHandler) handleError(c *gin.Context, err error, message string, attrs ...any) {
+	status := courierWebhookErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(message, append(attrs, slog.String("error", err.Error()))...)
+		utils.ErrorResponse(c, status, message, nil)
+		return
+	}
+
+	utils.ErrorResponse(c, status, err.Error(), nil)
+}
+
+// courierWebhookHeaders returns the request headers to store with a delivery
+func courierWebhookHeaders(c *gin.Context) map[string]string {
+	headers := make(map[string]string, len(c.Request.Header))
+	for name, values := range c.Request.Header {
+		if courierWebhookRedactedHeaders[name] {
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+// parseCourierWebhookEventFilters reads courier webhook event filter query parameters
+func parseCourierWebhookEventFilters(c *gin.Context) (*repository.CourierWebhookEventFilters, error) {
+	filters := &repository.CourierWebhookEventFilters{}
+
+	if v := strings.ToLower(strings.TrimSpace(c.Query("courier"))); v != "" {
+		filters.CourierCode = &v
+	}
+	if v := c.Query("status"); v != "" {
+		status := entity.CourierWebhookEventStatus(v)
+		filters.Status = &status
+	}
+	if v := strings.TrimSpace(c.Query("tracking_number")); v != "" {
+		filters.TrackingNumber = &v
+	}
+	if v := c.Query("date_from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return nil, errInvalidQuery("date_from")
+		}
+		filters.ReceivedFrom = &t
+	}
+	if v := c.Query("date_to"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return nil, errInvalidQuery("date_to")
+		}
+		// Include the whole day
+		t = t.Add(24*time.Hour - time.Nanosecond)
+		filters.ReceivedTo = &t
+	}
+
+	return filters, nil
+}
+
+// courierSignature returns the first signature header sent with the request
+func courierSignature(c *gin.Context) string {
+	for _, header := range courierSignatureHeaders {
+		if signature := strings.TrimSpace(c.GetHeader(header)); signature != "" {
+			return signature
+		}
+	}
+	return ""
+}
+
+// courierWebhookErrorStatus maps courier webhook use case errors to HTTP status codes
+func courierWebhookErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "failed to"):
+		return http.StatusInternalServerError
+	case strings.Contains(msg, "signature validation failed"):
+		return http.StatusUnauthorized
+	case strings.Contains(msg, "not supported"),
+		strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "invalid"),
+		strings.Contains(msg, "cannot be replayed"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
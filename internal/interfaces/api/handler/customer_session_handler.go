@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/service"
+	"github.com/kirimku/smartseller-backend/internal/domain/errors"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
+	"github.com/kirimku/smartseller-backend/pkg/utils"
+)
+
+// CustomerSessionHandler lets customers manage the devices signed in to their
+// account and lets sellers sign customers out
+type CustomerSessionHandler struct {
+	sessionService *service.CustomerSessionService
+	storefrontRepo repository.StorefrontRepository
+	logger         *slog.Logger
+}
+
+// NewCustomerSessionHandler creates a new customer session handler
+func NewCustomerSessionHandler(
+	sessionService *service.CustomerSessionService,
+	storefrontRepo repository.StorefrontRepository,
+	logger *slog.Logger,
+) *CustomerSessionHandler {
+	return &CustomerSessionHandler{
+		sessionService: sessionService,
+		storefrontRepo: storefrontRepo,
+		logger:         logger,
+	}
+}
+
+// ListMySessions handles listing the devices signed in to the customer's account
+// @Summary List my devices
+// @Description List the active sessions of the authenticated customer, most recently used first
+// @Tags Storefront Sessions
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Success 200 {object} []dto.CustomerSessionResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/sessions [get]
+func (h *CustomerSessionHandler) ListMySessions(c *gin.Context) {
+	storefrontID, customerID, ok := h.getCustomerContext(c)
+	if !ok {
+		return
+	}
+
+	currentSessionID, _ := uuid.Parse(c.GetString("session_id"))
+
+	sessions, err := h.sessionService.ListSessions(c.Request.Context(), storefrontID, customerID, currentSessionID)
+	if err != nil {
+		h.handleError(c, err, "Failed to list sessions")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sessions retrieved successfully", sessions)
+}
+
+// RevokeMySession handles signing one of the customer's devices out
+// @Summary Revoke one of my devices
+// @Description Revoke a session of the authenticated customer. Its tokens stop working immediately.
+// @Tags Storefront Sessions
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param id path string true "Session ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/sessions/{id} [delete]
+func (h *CustomerSessionHandler) RevokeMySession(c *gin.Context) {
+	storefrontID, customerID, ok := h.getCustomerContext(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid session ID", nil)
+		return
+	}
+
+	if err := h.sessionService.RevokeSession(c.Request.Context(), storefrontID, customerID, sessionID); err != nil {
+		h.handleError(c, err, "Failed to revoke session", slog.String("session_id", sessionID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Session revoked successfully", nil)
+}
+
+// RevokeCustomerSessions handles signing a customer out of every device
+// @Summary Revoke all sessions of a customer
+// @Description Revoke every active session of a customer of the seller's storefront
+// @Tags Customer Sessions
+// @Produce json
+// @Security BearerAuth
+// @Param customer_id path string true "Customer ID"
+// @Success 200 {object} dto.CustomerSessionRevokeResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/customers/{customer_id}/sessions [delete]
+func (h *CustomerSessionHandler) RevokeCustomerSessions(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+
+	customerID, err := uuid.Parse(c.Param("customer_id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid customer ID", nil)
+		return
+	}
+
+	revoked, err := h.sessionService.RevokeCustomerSessions(c.Request.Context(), storefrontID, customerID)
+	if err != nil {
+		h.handleError(c, err, "Failed to revoke customer sessions", slog.String("customer_id", customerID.String()))
+		return
+	}
+
+	h.logger.Info("Revoked customer sessions",
+		slog.String("storefront_id", storefrontID.String()),
+		slog.String("customer_id", customerID.String()),
+		slog.Int("revoked_sessions", revoked))
+
+	utils.SuccessResponse(c, http.StatusOK, "Customer sessions revoked successfully", dto.CustomerSessionRevokeResponse{RevokedSessions: revoked})
+}
+
+// RevokeStorefrontSessions handles signing every customer of the storefront out
+// @Summary Revoke all customer sessions of the storefront
+// @Description Kill switch that revokes every active customer session of the seller's storefront
+// @Tags Customer Sessions
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.CustomerSessionRevokeResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/customer-sessions [delete]
+func (h *CustomerSessionHandler) RevokeStorefrontSessions(c *gin.Context) {
+	userID, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+
+	revoked, err := h.sessionService.RevokeStorefrontSessions(c.Request.Context(), storefrontID)
+	if err != nil {
+		h.handleError(c, err, "Failed to revoke storefront sessions", slog.String("storefront_id", storefrontID.String()))
+		return
+	}
+
+	h.logger.Warn("Revoked all customer sessions of storefront",
+		slog.String("storefront_id", storefrontID.String()),
+		slog.String("user_id", userID.String()),
+		slog.Int("revoked_sessions", revoked))
+
+	utils.SuccessResponse(c, http.StatusOK, "Storefront sessions revoked successfully", dto.CustomerSessionRevokeResponse{RevokedSessions: revoked})
+}
+
+// getCustomerContext extracts the storefront and customer from the request,
+// writing an error response when either is missing
+func (h *CustomerSessionHandler) getCustomerContext(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	customerIDStr, exists := middleware.GetCustomerID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Authentication required", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	customerID, err := uuid.Parse(customerIDStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid customer ID", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	storefrontID, exists := middleware.GetCustomerStorefrontID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Storefront context required", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return storefrontID, customerID, true
+}
+
+// handleError maps customer session service errors to HTTP responses
+func (h *CustomerSessionHandler) handleError(c *gin.Context, err error, message string, attrs ...any) {
+	status := customerSessionErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(message, append(attrs, slog.String("error", err.Error()))...)
+		utils.ErrorResponse(c, status, message, nil)
+		return
+	}
+
+	utils.ErrorResponse(c, status, err.Error(), nil)
+}
+
+// customerSessionErrorStatus maps a customer session service error to an HTTP status
+func customerSessionErrorStatus(err error) int {
+	appErr, ok := err.(*errors.AppError)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+
+	switch appErr.Type {
+	case errors.ErrorTypeValidation:
+		return http.StatusBadRequest
+	case errors.ErrorTypeNotFound:
+		return http.StatusNotFound
+	case errors.ErrorTypeAuthorization:
+		return http.StatusUnauthorized
+	case errors.ErrorTypeRateLimit:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
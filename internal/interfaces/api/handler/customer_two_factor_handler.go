@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/service"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
+	"github.com/kirimku/smartseller-backend/pkg/utils"
+)
+
+// CustomerTwoFactorHandler handles TOTP enrollment and step-up verification
+// for storefront customers
+type CustomerTwoFactorHandler struct {
+	twoFactorService *service.CustomerTwoFactorService
+	logger           *slog.Logger
+}
+
+// NewCustomerTwoFactorHandler creates a new customer two-factor handler
+func NewCustomerTwoFactorHandler(twoFactorService *service.CustomerTwoFactorService, logger *slog.Logger) *CustomerTwoFactorHandler {
+	return &CustomerTwoFactorHandler{
+		twoFactorService: twoFactorService,
+		logger:           logger,
+	}
+}
+
+// GetStatus handles reading the customer's two-factor status
+// @Summary Get my two-factor status
+// @Description Report whether TOTP is enabled, whether the storefront requires it and how many recovery codes are left
+// @Tags Storefront Two-Factor
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Success 200 {object} dto.CustomerTwoFactorStatusResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/2fa [get]
+func (h *CustomerTwoFactorHandler) GetStatus(c *gin.Context) {
+	subject, ok := h.getSubject(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.twoFactorService.Status(c.Request.Context(), subject)
+	if err != nil {
+		h.handleError(c, err, "Failed to get two-factor status", subject)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor status retrieved successfully", status)
+}
+
+// BeginEnrollment handles starting TOTP enrollment
+// @Summary Start two-factor enrollment
+// @Description Generate a TOTP secret and its otpauth provisioning URI for a QR code. Enrollment stays pending until confirmed.
+// @Tags Storefront Two-Factor
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Success 200 {object} dto.CustomerTwoFactorEnrollmentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/2fa/enroll [post]
+func (h *CustomerTwoFactorHandler) BeginEnrollment(c *gin.Context) {
+	subject, ok := h.getSubject(c)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.twoFactorService.BeginEnrollment(c.Request.Context(), subject)
+	if err != nil {
+		h.handleError(c, err, "Failed to start two-factor enrollment", subject)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor enrollment started", enrollment)
+}
+
+// ConfirmEnrollment handles enabling TOTP with a first code
+// @Summary Confirm two-factor enrollment
+// @Description Enable TOTP with a code from the authenticator app. Returns recovery codes, shown only once, and tokens carrying the two-factor claim.
+// @Tags Storefront Two-Factor
+// @Accept json
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param request body dto.CustomerTwoFactorCodeRequest true "TOTP code"
+// @Success 200 {object} dto.CustomerTwoFactorVerifyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/2fa/enroll/confirm [post]
+func (h *CustomerTwoFactorHandler) ConfirmEnrollment(c *gin.Context) {
+	subject, ok := h.getSubject(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CustomerTwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	response, err := h.twoFactorService.ConfirmEnrollment(c.Request.Context(), subject, req.Code)
+	if err != nil {
+		h.handleError(c, err, "Failed to confirm two-factor enrollment", subject)
+		return
+	}
+
+	h.logger.Info("Customer enabled two-factor authentication",
+		slog.String("storefront_id", subject.StorefrontID.String()),
+		slog.String("customer_id", subject.CustomerID.String()))
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication enabled", response)
+}
+
+// Verify handles step-up verification of the current session
+// @Summary Verify a two-factor code
+// @Description Verify a TOTP or recovery code for the current session and issue tokens carrying the two-factor claim
+// @Tags Storefront Two-Factor
+// @Accept json
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param request body dto.CustomerTwoFactorCodeRequest true "TOTP or recovery code"
+// @Success 200 {object} dto.CustomerTwoFactorVerifyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/2fa/verify [post]
+func (h *CustomerTwoFactorHandler) Verify(c *gin.Context) {
+	subject, ok := h.getSubject(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CustomerTwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	response, err := h.twoFactorService.Verify(c.Request.Context(), subject, req.Code)
+	if err != nil {
+		h.handleError(c, err, "Failed to verify two-factor code", subject)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor verification successful", response)
+}
+
+// Disable handles turning TOTP off
+// @Summary Disable two-factor authentication
+// @Description Disable TOTP after checking a current code. Not allowed when the storefront requires two-factor authentication.
+// @Tags Storefront Two-Factor
+// @Accept json
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param request body dto.CustomerTwoFactorCodeRequest true "TOTP or recovery code"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/2fa/disable [post]
+func (h *CustomerTwoFactorHandler) Disable(c *gin.Context) {
+	subject, ok := h.getSubject(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CustomerTwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	if err := h.twoFactorService.Disable(c.Request.Context(), subject, req.Code); err != nil {
+		h.handleError(c, err, "Failed to disable two-factor authentication", subject)
+		return
+	}
+
+	h.logger.Info("Customer disabled two-factor authentication",
+		slog.String("storefront_id", subject.StorefrontID.String()),
+		slog.String("customer_id", subject.CustomerID.String()))
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication disabled", nil)
+}
+
+// getSubject extracts the customer and session from the request, writing an
+// error response when they are missing
+func (h *CustomerTwoFactorHandler) getSubject(c *gin.Context) (service.CustomerTwoFactorSubject, bool) {
+	customerIDStr, exists := middleware.GetCustomerID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Authentication required", nil)
+		return service.CustomerTwoFactorSubject{}, false
+	}
+
+	customerID, err := uuid.Parse(customerIDStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid customer ID", nil)
+		return service.CustomerTwoFactorSubject{}, false
+	}
+
+	sessionID, err := uuid.Parse(c.GetString("session_id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid session", nil)
+		return service.CustomerTwoFactorSubject{}, false
+	}
+
+	storefrontID, exists := middleware.GetCustomerStorefrontID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Storefront context required", nil)
+		return service.CustomerTwoFactorSubject{}, false
+	}
+
+	return service.CustomerTwoFactorSubject{
+		StorefrontID: storefrontID,
+		CustomerID:   customerID,
+		SessionID:    sessionID,
+		Email:        c.GetString("customer_email"),
+	}, true
+}
+
+// handleError maps customer two-factor service errors to HTTP responses
+func (h *CustomerTwoFactorHandler) handleError(c *gin.Context, err error, message string, subject service.CustomerTwoFactorSubject) {
+	status := customerSessionErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(message,
+			slog.String("storefront_id", subject.StorefrontID.String()),
+			slog.String("customer_id", subject.CustomerID.String()),
+			slog.String("error", err.Error()))
+		utils.ErrorResponse(c, status, message, nil)
+		return
+	}
+
+	utils.ErrorResponse(c, status, err.Error(), nil)
+}
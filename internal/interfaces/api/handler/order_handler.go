@@ -0,0 +1,486 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/pkg/utils"
+)
+
+// OrderHandler handles admin order management HTTP requests
+type OrderHandler struct {
+	orderUseCase   usecase.OrderUseCase
+	storefrontRepo repository.StorefrontRepository
+	logger         *slog.Logger
+}
+
+// NewOrderHandler creates a new order handler
+func NewOrderHandler(orderUseCase usecase.OrderUseCase, storefrontRepo repository.StorefrontRepository, logger *slog.Logger) *OrderHandler {
+	return &OrderHandler{
+		orderUseCase:   orderUseCase,
+		storefrontRepo: storefrontRepo,
+		logger:         logger,
+	}
+}
+
+// ListOrders handles listing orders with filters
+// @Summary List orders
+// @Description Get a paginated list of orders with optional filters
+// @Tags Admin Orders
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page" default(20)
+// @Param customer_id query string false "Filter by customer ID"
+// @Param status query string false "Filter by order status"
+// @Param payment_status query string false "Filter by payment status"
+// @Param channel query string false "Filter by sales channel"
+// @Param search query string false "Search by order number, email, recipient or tracking number"
+// @Param date_from query string false "Orders placed on or after this date (YYYY-MM-DD)"
+// @Param date_to query string false "Orders placed on or before this date (YYYY-MM-DD)"
+// @Param sort_by query string false "Sort field" Enums(created_at, order_date, total_amount, order_number, status, updated_at)
+// @Param sort_direction query string false "Sort direction" Enums(asc, desc)
+// @Success 200 {object} dto.OrderListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/orders [get]
+func (h *OrderHandler) ListOrders(c *gin.Context) {
+	userID, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+
+	filters, err := parseOrderFilters(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	page, pageSize := parseOrderPagination(c)
+	filters.Page = page
+	filters.PageSize = pageSize
+	filters.SortBy = c.DefaultQuery("sort_by", "created_at")
+	filters.SortDirection = "desc"
+	if strings.EqualFold(c.Query("sort_direction"), "asc") {
+		filters.SortDirection = "asc"
+	}
+
+	orders, err := h.orderUseCase.ListOrders(c.Request.Context(), storefrontID, filters)
+	if err != nil {
+		h.logger.Error("Failed to list orders", slog.String("error", err.Error()), slog.String("user_id", userID.String()))
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve orders", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Orders retrieved successfully", orders)
+}
+
+// GetOrder handles retrieving a single order
+// @Summary Get order
+// @Description Get an order with its items
+// @Tags Admin Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} dto.OrderResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/orders/{id} [get]
+func (h *OrderHandler) GetOrder(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID format", err)
+		return
+	}
+
+	order, err := h.orderUseCase.GetOrder(c.Request.Context(), storefrontID, orderID)
+	if err != nil {
+		h.handleOrderError(c, err, "Failed to retrieve order", orderID)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Order retrieved successfully", order)
+}
+
+// CreateOrder handles manual order creation
+// @Summary Create order
+// @Description Create an order manually, e.g. for phone or chat sales
+// @Tags Admin Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateOrderRequest true "Order data"
+// @Success 201 {object} dto.OrderResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/orders [post]
+func (h *OrderHandler) CreateOrder(c *gin.Context) {
+	userID, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	if len(req.Items) == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Order must contain at least one item", nil)
+		return
+	}
+
+	order, err := h.orderUseCase.CreateOrder(c.Request.Context(), storefrontID, &req, userID)
+	if err != nil {
+		h.handleOrderError(c, err, "Failed to create order", uuid.Nil)
+		return
+	}
+
+	h.logger.Info("Order created",
+		slog.String("order_id", order.ID),
+		slog.String("order_number", order.OrderNumber),
+		slog.String("user_id", userID.String()))
+
+	utils.SuccessResponse(c, http.StatusCreated, "Order created successfully", order)
+}
+
+// UpdateOrderStatus handles order status transitions
+// @Summary Update order status
+// @Description Move an order to the next status. Shipping requires a tracking number.
+// @Tags Admin Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body dto.UpdateOrderStatusRequest true "Status update"
+// @Success 200 {object} dto.OrderResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/orders/{id}/status [put]
+func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
+	userID, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID format", err)
+		return
+	}
+
+	var req dto.UpdateOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	order, err := h.orderUseCase.UpdateOrderStatus(c.Request.Context(), storefrontID, orderID, &req, userID)
+	if err != nil {
+		h.handleOrderError(c, err, "Failed to update order status", orderID)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Order status updated successfully", order)
+}
+
+// UpdatePaymentStatus handles manual payment status updates
+// @Summary Update order payment status
+// @Description Record a payment update for an order, e.g. a confirmed bank transfer
+// @Tags Admin Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body dto.UpdateOrderPaymentRequest true "Payment update"
+// @Success 200 {object} dto.OrderResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/orders/{id}/payment [put]
+func (h *OrderHandler) UpdatePaymentStatus(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID format", err)
+		return
+	}
+
+	var req dto.UpdateOrderPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	order, err := h.orderUseCase.UpdatePaymentStatus(c.Request.Context(), storefrontID, orderID, &req)
+	if err != nil {
+		h.handleOrderError(c, err, "Failed to update payment status", orderID)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Payment status updated successfully", order)
+}
+
+// CancelOrder handles order cancellation by the seller
+// @Summary Cancel order
+// @Description Cancel an order that has not been shipped yet
+// @Tags Admin Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body dto.CancelOrderRequest false "Cancellation reason"
+// @Success 200 {object} dto.OrderResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/orders/{id}/cancel [post]
+func (h *OrderHandler) CancelOrder(c *gin.Context) {
+	userID, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID format", err)
+		return
+	}
+
+	var req dto.CancelOrderRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+			return
+		}
+	}
+
+	order, err := h.orderUseCase.CancelOrder(c.Request.Context(), storefrontID, orderID, &req, userID)
+	if err != nil {
+		h.handleOrderError(c, err, "Failed to cancel order", orderID)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Order cancelled successfully", order)
+}
+
+// GetOrderHistory handles retrieving the status history of an order
+// @Summary Get order status history
+// @Description Get every status change of an order in chronological order
+// @Tags Admin Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {array} dto.OrderStatusHistoryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/orders/{id}/history [get]
+func (h *OrderHandler) GetOrderHistory(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID format", err)
+		return
+	}
+
+	history, err := h.orderUseCase.GetOrderHistory(c.Request.Context(), storefrontID, orderID)
+	if err != nil {
+		h.handleOrderError(c, err, "Failed to retrieve order history", orderID)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Order history retrieved successfully", history)
+}
+
+// GetOrderStatistics handles retrieving order statistics
+// @Summary Get order statistics
+// @Description Get order counts and revenue, optionally filtered
+// @Tags Admin Orders
+// @Produce json
+// @Security BearerAuth
+// @Param date_from query string false "Orders placed on or after this date (YYYY-MM-DD)"
+// @Param date_to query string false "Orders placed on or before this date (YYYY-MM-DD)"
+// @Success 200 {object} dto.OrderStatisticsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/orders/stats [get]
+func (h *OrderHandler) GetOrderStatistics(c *gin.Context) {
+	userID, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+
+	filters, err := parseOrderFilters(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	stats, err := h.orderUseCase.GetOrderStatistics(c.Request.Context(), storefrontID, filters)
+	if err != nil {
+		h.logger.Error("Failed to get order statistics", slog.String("error", err.Error()), slog.String("user_id", userID.String()))
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve order statistics", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Order statistics retrieved successfully", stats)
+}
+
+// handleOrderError maps order use case errors to HTTP responses
+func (h *OrderHandler) handleOrderError(c *gin.Context, err error, message string, orderID uuid.UUID) {
+	status := orderErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(message, slog.String("error", err.Error()), slog.String("order_id", orderID.String()))
+		utils.ErrorResponse(c, status, message, err)
+		return
+	}
+
+	utils.ErrorResponse(c, status, err.Error(), nil)
+}
+
+// orderErrorStatus picks the HTTP status for an order use case error
+func orderErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "cannot transition"),
+		strings.Contains(msg, "cannot cancel"),
+		strings.Contains(msg, "cannot refund"),
+		strings.Contains(msg, "can only"),
+		strings.Contains(msg, "can no longer"):
+		return http.StatusConflict
+	case strings.Contains(msg, "invalid"),
+		strings.Contains(msg, "required"),
+		strings.Contains(msg, "cannot be negative"),
+		strings.Contains(msg, "must be"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseOrderPagination reads page and page_size query parameters
+func parseOrderPagination(c *gin.Context) (int, int) {
+	page := 1
+	pageSize := 20
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 && ps <= 100 {
+			pageSize = ps
+		}
+	}
+
+	return page, pageSize
+}
+
+// parseOrderFilters reads order filter query parameters. The storefront is
+// taken from the authenticated seller, not from the query.
+func parseOrderFilters(c *gin.Context) (*repository.OrderFilters, error) {
+	filters := &repository.OrderFilters{}
+
+	if v := c.Query("customer_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, errInvalidQuery("customer_id")
+		}
+		filters.CustomerID = &id
+	}
+
+	if v := c.Query("status"); v != "" {
+		status := entity.OrderStatus(v)
+		if !status.Valid() {
+			return nil, errInvalidQuery("status")
+		}
+		filters.Status = &status
+	}
+
+	if v := c.Query("payment_status"); v != "" {
+		paymentStatus := entity.PaymentStatus(v)
+		if !paymentStatus.Valid() {
+			return nil, errInvalidQuery("payment_status")
+		}
+		filters.PaymentStatus = &paymentStatus
+	}
+
+	if v := c.Query("channel"); v != "" {
+		channel := entity.OrderChannel(v)
+		if !channel.Valid() {
+			return nil, errInvalidQuery("channel")
+		}
+		filters.Channel = &channel
+	}
+
+	if v := c.Query("search"); v != "" {
+		filters.Search = &v
+	}
+
+	if v := c.Query("date_from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return nil, errInvalidQuery("date_from")
+		}
+		filters.DateFrom = &t
+	}
+
+	if v := c.Query("date_to"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return nil, errInvalidQuery("date_to")
+		}
+		// Include the whole day
+		t = t.Add(24*time.Hour - time.Nanosecond)
+		filters.DateTo = &t
+	}
+
+	return filters, nil
+}
+
+// errInvalidQuery builds the error returned for malformed query parameters
+func errInvalidQuery(param string) error {
+	return fmt.Errorf("Invalid %s parameter", param)
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
+	"github.com/kirimku/smartseller-backend/pkg/utils"
+)
+
+// maxPaymentCallbackBodySize caps the payload accepted from a payment gateway
+const maxPaymentCallbackBodySize = 1 << 20
+
+// PaymentHandler handles order payments and payment gateway callbacks
+type PaymentHandler struct {
+	paymentUseCase usecase.PaymentUseCase
+	logger         *slog.Logger
+}
+
+// NewPaymentHandler creates a new payment handler
+func NewPaymentHandler(paymentUseCase usecase.PaymentUseCase, logger *slog.Logger) *PaymentHandler {
+	return &PaymentHandler{
+		paymentUseCase: paymentUseCase,
+		logger:         logger,
+	}
+}
+
+// CreatePayment handles starting the payment of a pending order
+// @Summary Pay for an order
+// @Description Create a hosted invoice, or an e-wallet charge for orders placed with the ewallet payment method, and return where the customer pays. Guests confirm the email they checked out with. A failed payment can be retried; the order is updated when the gateway reports the outcome.
+// @Tags Storefront Checkout
+// @Accept json
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param request body dto.OrderPaymentRequest true "Payment request"
+// @Success 201 {object} dto.OrderPaymentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/checkout/payment [post]
+func (h *PaymentHandler) CreatePayment(c *gin.Context) {
+	storefrontID, exists := middleware.GetCustomerStorefrontID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Storefront context required", nil)
+		return
+	}
+
+	var customerID *uuid.UUID
+	if customerIDStr, ok := middleware.GetCustomerID(c); ok {
+		id, err := uuid.Parse(customerIDStr)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid customer ID", nil)
+			return
+		}
+		customerID = &id
+	}
+
+	var req dto.OrderPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	result, err := h.paymentUseCase.CreateOrderPayment(c.Request.Context(), storefrontID, customerID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create payment", slog.String("order_id", req.OrderID))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Payment created successfully", result)
+}
+
+// HandleCallback handles a payment outcome pushed by a gateway
+// @Summary Receive payment callback
+// @Description Receive an invoice or e-wallet charge outcome from a payment gateway (xendit or durianpay). The callback token or signature is verified before the order's payment status and reference are updated. Repeated callbacks are acknowledged without changing the order.
+// @Tags Payment Webhooks
+// @Accept json
+// @Produce json
+// @Param gateway path string true "Gateway name"
+// @Param X-Callback-Token header string false "Xendit callback verification token"
+// @Param X-Signature header string false "DurianPay SNAP notification signature"
+// @Success 200 {object} dto.PaymentCallbackResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /webhooks/payments/{gateway} [post]
+func (h *PaymentHandler) HandleCallback(c *gin.Context) {
+	gateway := c.Param("gateway")
+
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxPaymentCallbackBodySize+1))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read request body", err)
+		return
+	}
+	if len(payload) > maxPaymentCallbackBodySize {
+		utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, "Request body too large", nil)
+		return
+	}
+	if len(payload) == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Request body is required", nil)
+		return
+	}
+
+	result, err := h.paymentUseCase.HandleCallback(c.Request.Context(), gateway, c.Request.Header, payload)
+	if err != nil {
+		h.handleError(c, err, "Failed to process payment callback", slog.String("gateway", gateway))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Callback processed successfully", result)
+}
+
+// handleError maps payment use case errors to HTTP responses
+func (h *PaymentHandler) handleError(c *gin.Context, err error, message string, attrs ...any) {
+	status := paymentErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(message, append(attrs, slog.String("error", err.Error()))...)
+		utils.ErrorResponse(c, status, message, nil)
+		return
+	}
+
+	utils.ErrorResponse(c, status, err.Error(), nil)
+}
+
+// paymentErrorStatus maps payment use case errors to HTTP status codes
+func paymentErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "failed to"):
+		return http.StatusInternalServerError
+	case strings.Contains(msg, "verification failed"):
+		return http.StatusUnauthorized
+	case strings.Contains(msg, "not supported"),
+		strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "cannot be paid"),
+		strings.Contains(msg, "already"),
+		strings.Contains(msg, "no longer pending"):
+		return http.StatusConflict
+	case strings.Contains(msg, "invalid"),
+		strings.Contains(msg, "required"),
+		strings.Contains(msg, "must"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
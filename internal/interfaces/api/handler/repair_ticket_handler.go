@@ -1,639 +1,696 @@
 package handler
 
 import (
+	"log/slog"
 	"net/http"
-	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/shopspring/decimal"
+
 	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
 	"github.com/kirimku/smartseller-backend/pkg/utils"
 )
 
 // RepairTicketHandler handles repair ticket management operations
 type RepairTicketHandler struct {
-	// In a real implementation, this would include service dependencies
-	// repairTicketService service.RepairTicketService
+	repairUseCase  usecase.RepairTicketUseCase
+	storefrontRepo repository.StorefrontRepository
+	logger         *slog.Logger
 }
 
 // NewRepairTicketHandler creates a new repair ticket handler
-func NewRepairTicketHandler() *RepairTicketHandler {
-	return &RepairTicketHandler{}
+func NewRepairTicketHandler(repairUseCase usecase.RepairTicketUseCase, storefrontRepo repository.StorefrontRepository, logger *slog.Logger) *RepairTicketHandler {
+	return &RepairTicketHandler{
+		repairUseCase:  repairUseCase,
+		storefrontRepo: storefrontRepo,
+		logger:         logger,
+	}
 }
 
 // CreateRepairTicket creates a new repair ticket for a warranty claim
 // @Summary Create repair ticket
-// @Description Create a new repair ticket for a warranty claim
+// @Description Create a repair ticket for a validated, assigned or in-repair claim. The technician defaults to the claim's assigned technician, and a validated claim is assigned to the ticket's technician.
 // @Tags repair-tickets
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Claim ID"
 // @Param request body dto.RepairTicketCreateRequest true "Repair ticket creation request"
-// @Success 201 {object} dto.RepairTicketResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Success 201 {object} dto.SuccessResponse{data=dto.RepairTicketResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
 // @Router /api/v1/admin/warranty/claims/{id}/repair-tickets [post]
 func (h *RepairTicketHandler) CreateRepairTicket(c *gin.Context) {
-	claimID := c.Param("id")
-	if claimID == "" {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Claim ID is required", nil)
+	userID, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	claimID, ok := parseUUIDParam(c, "id", "claim ID")
+	if !ok {
 		return
 	}
 
 	var req dto.RepairTicketCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
-		return
-	}
-
-	// Mock implementation - in real scenario, this would call the service layer
-	now := time.Now()
-	ticketID := uuid.New().String()
-	
-	response := &dto.RepairTicketResponse{
-		ID:                       ticketID,
-		TicketNumber:             "RPR-2024-" + strconv.Itoa(int(now.Unix())),
-		ClaimID:                  claimID,
-		ClaimNumber:              "WAR-2024-001234",
-		Status:                   "pending",
-		Priority:                 req.Priority,
-		EstimatedHours:           req.EstimatedHours,
-		Description:              req.Description,
-		RequiredParts:            req.RequiredParts,
-		SpecialInstructions:      req.SpecialInstructions,
-		LaborCost:                decimal.NewFromFloat(0),
-		PartsCost:                decimal.NewFromFloat(0),
-		TotalCost:                decimal.NewFromFloat(0),
-		QualityCheckStatus:       "pending",
-		CustomerApprovalRequired: req.CustomerApprovalRequired,
-		CustomerApprovalStatus:   "pending",
-		CreatedBy:                "550e8400-e29b-41d4-a716-446655440004",
-		CreatedAt:                now,
-		UpdatedAt:                now,
-	}
-
-	c.JSON(http.StatusCreated, response)
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	ticket, err := h.repairUseCase.CreateRepairTicket(c.Request.Context(), storefrontID, claimID, userID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create repair ticket", slog.String("claim_id", claimID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Repair ticket created successfully", ticket)
+}
+
+// ListClaimRepairTickets lists the repair tickets of a warranty claim
+// @Summary List claim repair tickets
+// @Description List the repair tickets of a warranty claim, newest first
+// @Tags repair-tickets
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Claim ID"
+// @Success 200 {object} dto.SuccessResponse{data=[]dto.RepairTicketResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/claims/{id}/repair-tickets [get]
+func (h *RepairTicketHandler) ListClaimRepairTickets(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	claimID, ok := parseUUIDParam(c, "id", "claim ID")
+	if !ok {
+		return
+	}
+
+	tickets, err := h.repairUseCase.ListClaimRepairTickets(c.Request.Context(), storefrontID, claimID)
+	if err != nil {
+		h.handleError(c, err, "Failed to list repair tickets", slog.String("claim_id", claimID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Repair tickets retrieved successfully", tickets)
 }
 
 // ListRepairTickets retrieves a paginated list of repair tickets
 // @Summary List repair tickets
-// @Description Get a paginated list of repair tickets with filtering options
+// @Description Get a paginated list of the storefront's repair tickets with filtering options
 // @Tags repair-tickets
-// @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(20)
-// @Param sort_by query string false "Sort by field" Enums(created_at,priority,status,estimated_completion_date)
-// @Param sort_order query string false "Sort order" Enums(asc,desc)
-// @Param status query []string false "Filter by status"
-// @Param priority query []string false "Filter by priority"
+// @Param sort_by query string false "Sort field" Enums(created_at, priority, status, target_completion_date)
+// @Param sort_dir query string false "Sort direction" Enums(asc, desc)
+// @Param status query string false "Comma-separated statuses" example(assigned,in_progress)
+// @Param priority query string false "Comma-separated priorities" example(high,urgent)
 // @Param technician_id query string false "Filter by technician ID"
-// @Param search_term query string false "Search term"
-// @Success 200 {object} dto.RepairTicketListResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Param claim_id query string false "Filter by claim ID"
+// @Param search query string false "Search ticket number, claim number or description"
+// @Success 200 {object} dto.SuccessResponse{data=dto.RepairTicketListResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
 // @Router /api/v1/admin/warranty/repair-tickets [get]
 func (h *RepairTicketHandler) ListRepairTickets(c *gin.Context) {
-	// Parse query parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
-	
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 20
-	}
-
-	// Mock implementation - in real scenario, this would call the service layer
-	now := time.Now()
-	mockTickets := []dto.RepairTicketResponse{
-		{
-			ID:                      "550e8400-e29b-41d4-a716-446655440000",
-			TicketNumber:            "RPR-2024-001234",
-			ClaimID:                 "550e8400-e29b-41d4-a716-446655440001",
-			ClaimNumber:             "WAR-2024-001234",
-			Status:                  "assigned",
-			Priority:                "high",
-			AssignedTechnicianID:    stringPtr("550e8400-e29b-41d4-a716-446655440002"),
-			TechnicianName:          stringPtr("John Smith"),
-			AssignedAt:              &now,
-			EstimatedHours:          decimal.NewFromFloat(4.5),
-			EstimatedCompletionDate: timePtr(now.Add(48 * time.Hour)),
-			Description:             "Replace faulty motherboard and test all components",
-			RequiredParts:           []string{"motherboard", "thermal_paste"},
-			SpecialInstructions:     "Handle with care - customer reported water damage",
-			LaborCost:               decimal.NewFromFloat(120.00),
-			PartsCost:               decimal.NewFromFloat(85.50),
-			TotalCost:               decimal.NewFromFloat(205.50),
-			QualityCheckStatus:      "pending",
-			CustomerApprovalRequired: true,
-			CustomerApprovalStatus:  "pending",
-			CreatedBy:               "550e8400-e29b-41d4-a716-446655440004",
-			CreatedAt:               now.Add(-24 * time.Hour),
-			UpdatedAt:               now,
-		},
-		{
-			ID:                      "550e8400-e29b-41d4-a716-446655440005",
-			TicketNumber:            "RPR-2024-001235",
-			ClaimID:                 "550e8400-e29b-41d4-a716-446655440006",
-			ClaimNumber:             "WAR-2024-001235",
-			Status:                  "completed",
-			Priority:                "normal",
-			AssignedTechnicianID:    stringPtr("550e8400-e29b-41d4-a716-446655440007"),
-			TechnicianName:          stringPtr("Jane Doe"),
-			AssignedAt:              timePtr(now.Add(-72 * time.Hour)),
-			EstimatedHours:          decimal.NewFromFloat(2.0),
-			ActualHours:             decimalPtr(decimal.NewFromFloat(1.8)),
-			EstimatedCompletionDate: timePtr(now.Add(-24 * time.Hour)),
-			ActualCompletionDate:    timePtr(now.Add(-12 * time.Hour)),
-			Description:             "Replace screen assembly",
-			RequiredParts:           []string{"screen", "adhesive"},
-			UsedParts:               []string{"screen", "adhesive", "screws"},
-			SpecialInstructions:     "Standard screen replacement procedure",
-			RepairNotes:             stringPtr("Successfully replaced screen, all tests passed"),
-			LaborCost:               decimal.NewFromFloat(60.00),
-			PartsCost:               decimal.NewFromFloat(45.00),
-			TotalCost:               decimal.NewFromFloat(105.00),
-			QualityCheckStatus:      "approved",
-			QualityCheckedBy:        stringPtr("550e8400-e29b-41d4-a716-446655440008"),
-			QualityCheckDate:        timePtr(now.Add(-6 * time.Hour)),
-			QualityCheckNotes:       stringPtr("All functionality verified, repair approved"),
-			CustomerApprovalRequired: false,
-			CustomerApprovalStatus:  "not_required",
-			CreatedBy:               "550e8400-e29b-41d4-a716-446655440004",
-			CreatedAt:               now.Add(-72 * time.Hour),
-			UpdatedAt:               now.Add(-12 * time.Hour),
-		},
-	}
-
-	response := &dto.RepairTicketListResponse{
-		Tickets: mockTickets,
-		Pagination: dto.PaginationResponse{
-			Page:       page,
-			Limit:      pageSize,
-			Total:      2,
-			TotalPages: 1,
-			HasNext:    false,
-			HasPrev:    false,
-		},
-		Filters: dto.RepairTicketFiltersResponse{
-			AvailableStatuses:             []string{"pending", "assigned", "in_progress", "completed", "cancelled"},
-			AvailablePriorities:           []string{"low", "normal", "high", "urgent"},
-			AvailableQualityCheckStatuses: []string{"pending", "approved", "rejected"},
-		},
-	}
-
-	c.JSON(http.StatusOK, response)
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+
+	filters, err := parseRepairTicketFilters(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	filters.StorefrontID = storefrontID
+	filters.Page, filters.PageSize = parseOrderPagination(c)
+
+	response, err := h.repairUseCase.ListRepairTickets(c.Request.Context(), filters)
+	if err != nil {
+		h.handleError(c, err, "Failed to list repair tickets", slog.String("storefront_id", storefrontID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Repair tickets retrieved successfully", response)
 }
 
-// GetRepairTicket retrieves a specific repair ticket by ID
+// GetRepairTicket retrieves a specific repair ticket
 // @Summary Get repair ticket
-// @Description Get detailed information about a specific repair ticket
+// @Description Get a repair ticket with its parts, test results and costs
 // @Tags repair-tickets
-// @Accept json
 // @Produce json
-// @Param id path string true "Repair Ticket ID"
-// @Success 200 {object} dto.RepairTicketResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
-// @Router /api/v1/admin/warranty/repair-tickets/{id} [get]
+// @Security BearerAuth
+// @Param ticketId path string true "Repair ticket ID"
+// @Success 200 {object} dto.SuccessResponse{data=dto.RepairTicketResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/repair-tickets/{ticketId} [get]
 func (h *RepairTicketHandler) GetRepairTicket(c *gin.Context) {
-	claimID := c.Param("id")
-	ticketID := c.Param("ticketId")
-	
-	if claimID == "" || ticketID == "" {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Claim ID and Ticket ID are required", nil)
-		return
-	}
-
-	// Mock implementation - in real scenario, this would call the service layer
-	now := time.Now()
-	response := &dto.RepairTicketResponse{
-		ID:                      ticketID,
-		TicketNumber:            "RPR-2024-001234",
-		ClaimID:                 "550e8400-e29b-41d4-a716-446655440001",
-		ClaimNumber:             "WAR-2024-001234",
-		Status:                  "assigned",
-		Priority:                "high",
-		AssignedTechnicianID:    stringPtr("550e8400-e29b-41d4-a716-446655440002"),
-		TechnicianName:          stringPtr("John Smith"),
-		AssignedAt:              &now,
-		EstimatedHours:          decimal.NewFromFloat(4.5),
-		EstimatedCompletionDate: timePtr(now.Add(48 * time.Hour)),
-		Description:             "Replace faulty motherboard and test all components",
-		RequiredParts:           []string{"motherboard", "thermal_paste"},
-		SpecialInstructions:     "Handle with care - customer reported water damage",
-		LaborCost:               decimal.NewFromFloat(120.00),
-		PartsCost:               decimal.NewFromFloat(85.50),
-		TotalCost:               decimal.NewFromFloat(205.50),
-		QualityCheckStatus:      "pending",
-		CustomerApprovalRequired: true,
-		CustomerApprovalStatus:  "pending",
-		CreatedBy:               "550e8400-e29b-41d4-a716-446655440004",
-		CreatedAt:               now.Add(-24 * time.Hour),
-		UpdatedAt:               now,
-	}
-
-	c.JSON(http.StatusOK, response)
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	ticketID, ok := parseUUIDParam(c, "ticketId", "repair ticket ID")
+	if !ok {
+		return
+	}
+
+	ticket, err := h.repairUseCase.GetRepairTicket(c.Request.Context(), storefrontID, ticketID)
+	if err != nil {
+		h.handleError(c, err, "Failed to get repair ticket", slog.String("ticket_id", ticketID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Repair ticket retrieved successfully", ticket)
 }
 
-// UpdateRepairTicket updates an existing repair ticket
+// UpdateRepairTicket updates a repair ticket
 // @Summary Update repair ticket
-// @Description Update details of an existing repair ticket
+// @Description Update the work order of a repair ticket that is still open
 // @Tags repair-tickets
 // @Accept json
 // @Produce json
-// @Param id path string true "Repair Ticket ID"
+// @Security BearerAuth
+// @Param ticketId path string true "Repair ticket ID"
 // @Param request body dto.RepairTicketUpdateRequest true "Repair ticket update request"
-// @Success 200 {object} dto.RepairTicketResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
-// @Router /api/v1/admin/warranty/repair-tickets/{id} [put]
+// @Success 200 {object} dto.SuccessResponse{data=dto.RepairTicketResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/repair-tickets/{ticketId} [put]
 func (h *RepairTicketHandler) UpdateRepairTicket(c *gin.Context) {
-	claimID := c.Param("id")
-	ticketID := c.Param("ticketId")
-	
-	if claimID == "" || ticketID == "" {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Claim ID and Ticket ID are required", nil)
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	ticketID, ok := parseUUIDParam(c, "ticketId", "repair ticket ID")
+	if !ok {
 		return
 	}
 
 	var req dto.RepairTicketUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
-		return
-	}
-
-	// Mock implementation - in real scenario, this would call the service layer
-	now := time.Now()
-	response := &dto.RepairTicketResponse{
-		ID:                      ticketID,
-		TicketNumber:            "RPR-2024-001234",
-		ClaimID:                 "550e8400-e29b-41d4-a716-446655440001",
-		ClaimNumber:             "WAR-2024-001234",
-		Status:                  "assigned",
-		Priority:                getStringValue(req.Priority, "high"),
-		AssignedTechnicianID:    stringPtr("550e8400-e29b-41d4-a716-446655440002"),
-		TechnicianName:          stringPtr("John Smith"),
-		AssignedAt:              &now,
-		EstimatedHours:          getDecimalValue(req.EstimatedHours, decimal.NewFromFloat(4.5)),
-		EstimatedCompletionDate: timePtr(now.Add(48 * time.Hour)),
-		Description:             getStringValue(req.Description, "Replace faulty motherboard and test all components"),
-		RequiredParts:           req.RequiredParts,
-		UsedParts:               req.UsedParts,
-		SpecialInstructions:     getStringValue(req.SpecialInstructions, "Handle with care - customer reported water damage"),
-		RepairNotes:             req.RepairNotes,
-		LaborCost:               getDecimalValue(req.LaborCost, decimal.NewFromFloat(120.00)),
-		PartsCost:               getDecimalValue(req.PartsCost, decimal.NewFromFloat(85.50)),
-		TotalCost:               decimal.NewFromFloat(205.50),
-		QualityCheckStatus:      "pending",
-		CustomerApprovalRequired: true,
-		CustomerApprovalStatus:  "pending",
-		CreatedBy:               "550e8400-e29b-41d4-a716-446655440004",
-		CreatedAt:               now.Add(-24 * time.Hour),
-		UpdatedAt:               now,
-	}
-
-	c.JSON(http.StatusOK, response)
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	ticket, err := h.repairUseCase.UpdateRepairTicket(c.Request.Context(), storefrontID, ticketID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update repair ticket", slog.String("ticket_id", ticketID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Repair ticket updated successfully", ticket)
 }
 
-// AssignTechnician assigns a technician to a repair ticket
+// AssignTechnician assigns a repair ticket to another technician
 // @Summary Assign technician
-// @Description Assign a technician to a repair ticket
+// @Description Hand a repair that has not started to another technician. The claim is reassigned along with the ticket.
 // @Tags repair-tickets
 // @Accept json
 // @Produce json
-// @Param id path string true "Repair Ticket ID"
+// @Security BearerAuth
+// @Param ticketId path string true "Repair ticket ID"
 // @Param request body dto.RepairTicketAssignmentRequest true "Technician assignment request"
-// @Success 200 {object} dto.RepairTicketResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
-// @Router /api/v1/admin/warranty/repair-tickets/{id}/assign [put]
+// @Success 200 {object} dto.SuccessResponse{data=dto.RepairTicketResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/repair-tickets/{ticketId}/assign [put]
 func (h *RepairTicketHandler) AssignTechnician(c *gin.Context) {
-	claimID := c.Param("id")
-	ticketID := c.Param("ticketId")
-	
-	if claimID == "" || ticketID == "" {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Claim ID and Ticket ID are required", nil)
+	userID, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	ticketID, ok := parseUUIDParam(c, "ticketId", "repair ticket ID")
+	if !ok {
 		return
 	}
 
 	var req dto.RepairTicketAssignmentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
-		return
-	}
-
-	// Mock implementation - in real scenario, this would call the service layer
-	now := time.Now()
-	response := &dto.RepairTicketResponse{
-		ID:                      ticketID,
-		TicketNumber:            "RPR-2024-001234",
-		ClaimID:                 "550e8400-e29b-41d4-a716-446655440001",
-		ClaimNumber:             "WAR-2024-001234",
-		Status:                  "assigned",
-		Priority:                "high",
-		AssignedTechnicianID:    &req.TechnicianID,
-		TechnicianName:          stringPtr("John Smith"),
-		AssignedAt:              &now,
-		EstimatedHours:          decimal.NewFromFloat(4.5),
-		EstimatedCompletionDate: req.EstimatedCompletionDate,
-		Description:             "Replace faulty motherboard and test all components",
-		RequiredParts:           []string{"motherboard", "thermal_paste"},
-		SpecialInstructions:     "Handle with care - customer reported water damage",
-		LaborCost:               decimal.NewFromFloat(120.00),
-		PartsCost:               decimal.NewFromFloat(85.50),
-		TotalCost:               decimal.NewFromFloat(205.50),
-		QualityCheckStatus:      "pending",
-		CustomerApprovalRequired: true,
-		CustomerApprovalStatus:  "pending",
-		CreatedBy:               "550e8400-e29b-41d4-a716-446655440004",
-		CreatedAt:               now.Add(-24 * time.Hour),
-		UpdatedAt:               now,
-	}
-
-	c.JSON(http.StatusOK, response)
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	ticket, err := h.repairUseCase.AssignTechnician(c.Request.Context(), storefrontID, ticketID, userID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to assign technician", slog.String("ticket_id", ticketID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Technician assigned successfully", ticket)
+}
+
+// StartRepair starts the repair work
+// @Summary Start repair
+// @Description Record the diagnosis and start the repair. An assigned claim moves to in repair.
+// @Tags repair-tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param ticketId path string true "Repair ticket ID"
+// @Param request body dto.RepairTicketStartRequest true "Repair start request"
+// @Success 200 {object} dto.SuccessResponse{data=dto.RepairTicketResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/repair-tickets/{ticketId}/start [put]
+func (h *RepairTicketHandler) StartRepair(c *gin.Context) {
+	userID, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	ticketID, ok := parseUUIDParam(c, "ticketId", "repair ticket ID")
+	if !ok {
+		return
+	}
+
+	var req dto.RepairTicketStartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	ticket, err := h.repairUseCase.StartRepair(c.Request.Context(), storefrontID, ticketID, userID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to start repair", slog.String("ticket_id", ticketID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Repair started successfully", ticket)
 }
 
-// CompleteRepair marks a repair ticket as completed
+// AddPartUsage records a part bought outside the spare parts inventory
+// @Summary Add part usage
+// @Description Record a part bought outside the spare parts inventory for a repair that is in progress or waiting for parts
+// @Tags repair-tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param ticketId path string true "Repair ticket ID"
+// @Param request body dto.RepairTicketPartRequest true "Part usage"
+// @Success 200 {object} dto.SuccessResponse{data=dto.RepairTicketResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/repair-tickets/{ticketId}/parts [post]
+func (h *RepairTicketHandler) AddPartUsage(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	ticketID, ok := parseUUIDParam(c, "ticketId", "repair ticket ID")
+	if !ok {
+		return
+	}
+
+	var req dto.RepairTicketPartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	ticket, err := h.repairUseCase.AddPartUsage(c.Request.Context(), storefrontID, ticketID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to add part usage", slog.String("ticket_id", ticketID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Part usage recorded successfully", ticket)
+}
+
+// RequestSparePart requests a part from the spare parts inventory
+// @Summary Request spare part
+// @Description Request a part from the storefront's spare parts inventory by ID or part number. A repair that has started reserves it right away and waits for parts if it is out of stock; an assigned repair reserves it when it starts. Reserved parts are taken out of stock when the repair completes.
+// @Tags repair-tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param ticketId path string true "Repair ticket ID"
+// @Param request body dto.RepairTicketSparePartRequest true "Spare part request"
+// @Success 200 {object} dto.SuccessResponse{data=dto.RepairTicketResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/repair-tickets/{ticketId}/spare-parts [post]
+func (h *RepairTicketHandler) RequestSparePart(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	ticketID, ok := parseUUIDParam(c, "ticketId", "repair ticket ID")
+	if !ok {
+		return
+	}
+
+	var req dto.RepairTicketSparePartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	ticket, err := h.repairUseCase.RequestSparePart(c.Request.Context(), storefrontID, ticketID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to request spare part", slog.String("ticket_id", ticketID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Spare part requested successfully", ticket)
+}
+
+// MarkWaitingForParts pauses the repair until parts arrive
+// @Summary Mark waiting for parts
+// @Description Pause a repair in progress until parts arrive
+// @Tags repair-tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param ticketId path string true "Repair ticket ID"
+// @Param request body dto.RepairTicketWaitingPartsRequest true "Waiting for parts request"
+// @Success 200 {object} dto.SuccessResponse{data=dto.RepairTicketResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/repair-tickets/{ticketId}/waiting-parts [put]
+func (h *RepairTicketHandler) MarkWaitingForParts(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	ticketID, ok := parseUUIDParam(c, "ticketId", "repair ticket ID")
+	if !ok {
+		return
+	}
+
+	var req dto.RepairTicketWaitingPartsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	ticket, err := h.repairUseCase.MarkWaitingForParts(c.Request.Context(), storefrontID, ticketID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to mark repair as waiting for parts", slog.String("ticket_id", ticketID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Repair marked as waiting for parts", ticket)
+}
+
+// ResumeRepair resumes a repair that was waiting for parts
+// @Summary Resume repair
+// @Description Resume a repair that was waiting for parts, once every spare part it needs can be reserved
+// @Tags repair-tickets
+// @Produce json
+// @Security BearerAuth
+// @Param ticketId path string true "Repair ticket ID"
+// @Success 200 {object} dto.SuccessResponse{data=dto.RepairTicketResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/repair-tickets/{ticketId}/resume [put]
+func (h *RepairTicketHandler) ResumeRepair(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	ticketID, ok := parseUUIDParam(c, "ticketId", "repair ticket ID")
+	if !ok {
+		return
+	}
+
+	ticket, err := h.repairUseCase.ResumeRepair(c.Request.Context(), storefrontID, ticketID)
+	if err != nil {
+		h.handleError(c, err, "Failed to resume repair", slog.String("ticket_id", ticketID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Repair resumed successfully", ticket)
+}
+
+// CompleteRepair marks a repair as completed
 // @Summary Complete repair
-// @Description Mark a repair ticket as completed with final details
+// @Description Record the labor, repair steps and test results of a repair and its quality check. Reserved spare parts are taken out of stock and recorded as parts used at their inventory cost. A passed check completes the claim's repair with the ticket's labor and parts cost; a failed one leaves the claim in repair for another ticket.
 // @Tags repair-tickets
 // @Accept json
 // @Produce json
-// @Param id path string true "Repair Ticket ID"
+// @Security BearerAuth
+// @Param ticketId path string true "Repair ticket ID"
 // @Param request body dto.RepairTicketCompletionRequest true "Repair completion request"
-// @Success 200 {object} dto.RepairTicketResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
-// @Router /api/v1/admin/warranty/repair-tickets/{id}/complete [put]
+// @Success 200 {object} dto.SuccessResponse{data=dto.RepairTicketResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/repair-tickets/{ticketId}/complete [put]
 func (h *RepairTicketHandler) CompleteRepair(c *gin.Context) {
-	claimID := c.Param("id")
-	ticketID := c.Param("ticketId")
-	
-	if claimID == "" || ticketID == "" {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Claim ID and Ticket ID are required", nil)
+	userID, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	ticketID, ok := parseUUIDParam(c, "ticketId", "repair ticket ID")
+	if !ok {
 		return
 	}
 
 	var req dto.RepairTicketCompletionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
-		return
-	}
-
-	// Mock implementation - in real scenario, this would call the service layer
-	now := time.Now()
-	totalCost := req.LaborCost.Add(req.PartsCost)
-	
-	response := &dto.RepairTicketResponse{
-		ID:                      ticketID,
-		TicketNumber:            "RPR-2024-001234",
-		ClaimID:                 "550e8400-e29b-41d4-a716-446655440001",
-		ClaimNumber:             "WAR-2024-001234",
-		Status:                  "completed",
-		Priority:                "high",
-		AssignedTechnicianID:    stringPtr("550e8400-e29b-41d4-a716-446655440002"),
-		TechnicianName:          stringPtr("John Smith"),
-		AssignedAt:              timePtr(now.Add(-48 * time.Hour)),
-		EstimatedHours:          decimal.NewFromFloat(4.5),
-		ActualHours:             &req.ActualHours,
-		EstimatedCompletionDate: timePtr(now.Add(-24 * time.Hour)),
-		ActualCompletionDate:    &now,
-		Description:             "Replace faulty motherboard and test all components",
-		RequiredParts:           []string{"motherboard", "thermal_paste"},
-		UsedParts:               req.UsedParts,
-		SpecialInstructions:     "Handle with care - customer reported water damage",
-		RepairNotes:             &req.RepairNotes,
-		LaborCost:               req.LaborCost,
-		PartsCost:               req.PartsCost,
-		TotalCost:               totalCost,
-		QualityCheckStatus:      "pending",
-		CustomerApprovalRequired: true,
-		CustomerApprovalStatus:  "approved",
-		CustomerApprovedAt:      timePtr(now.Add(-24 * time.Hour)),
-		CreatedBy:               "550e8400-e29b-41d4-a716-446655440004",
-		CreatedAt:               now.Add(-48 * time.Hour),
-		UpdatedAt:               now,
-	}
-
-	c.JSON(http.StatusOK, response)
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	ticket, err := h.repairUseCase.CompleteRepair(c.Request.Context(), storefrontID, ticketID, userID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to complete repair", slog.String("ticket_id", ticketID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Repair completed successfully", ticket)
 }
 
-// QualityCheck performs quality control approval/rejection
+// QualityCheck records a supervisor's quality check of a completed repair
 // @Summary Quality check
-// @Description Perform quality control approval or rejection of a repair
+// @Description Approve or reject a completed repair. Approving a repair that failed its first check completes the claim's repair.
 // @Tags repair-tickets
 // @Accept json
 // @Produce json
-// @Param id path string true "Repair Ticket ID"
+// @Security BearerAuth
+// @Param ticketId path string true "Repair ticket ID"
 // @Param request body dto.RepairTicketQualityCheckRequest true "Quality check request"
-// @Success 200 {object} dto.RepairTicketResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
-// @Router /api/v1/admin/warranty/repair-tickets/{id}/quality-check [put]
+// @Success 200 {object} dto.SuccessResponse{data=dto.RepairTicketResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/repair-tickets/{ticketId}/quality-check [put]
 func (h *RepairTicketHandler) QualityCheck(c *gin.Context) {
-	claimID := c.Param("id")
-	ticketID := c.Param("ticketId")
-	
-	if claimID == "" || ticketID == "" {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Claim ID and Ticket ID are required", nil)
+	userID, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	ticketID, ok := parseUUIDParam(c, "ticketId", "repair ticket ID")
+	if !ok {
 		return
 	}
 
 	var req dto.RepairTicketQualityCheckRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err)
-		return
-	}
-
-	// Mock implementation - in real scenario, this would call the service layer
-	now := time.Now()
-	qcStatus := "approved"
-	if req.Action == "reject" {
-		qcStatus = "rejected"
-	}
-	
-	response := &dto.RepairTicketResponse{
-		ID:                      ticketID,
-		TicketNumber:            "RPR-2024-001234",
-		ClaimID:                 "550e8400-e29b-41d4-a716-446655440001",
-		ClaimNumber:             "WAR-2024-001234",
-		Status:                  "completed",
-		Priority:                "high",
-		AssignedTechnicianID:    stringPtr("550e8400-e29b-41d4-a716-446655440002"),
-		TechnicianName:          stringPtr("John Smith"),
-		AssignedAt:              timePtr(now.Add(-48 * time.Hour)),
-		EstimatedHours:          decimal.NewFromFloat(4.5),
-		ActualHours:             decimalPtr(decimal.NewFromFloat(5.2)),
-		EstimatedCompletionDate: timePtr(now.Add(-24 * time.Hour)),
-		ActualCompletionDate:    timePtr(now.Add(-6 * time.Hour)),
-		Description:             "Replace faulty motherboard and test all components",
-		RequiredParts:           []string{"motherboard", "thermal_paste"},
-		UsedParts:               []string{"motherboard", "thermal_paste", "screws"},
-		SpecialInstructions:     "Handle with care - customer reported water damage",
-		RepairNotes:             stringPtr("Successfully replaced motherboard, all tests passed"),
-		LaborCost:               decimal.NewFromFloat(120.00),
-		PartsCost:               decimal.NewFromFloat(85.50),
-		TotalCost:               decimal.NewFromFloat(205.50),
-		QualityCheckStatus:      qcStatus,
-		QualityCheckedBy:        stringPtr("550e8400-e29b-41d4-a716-446655440008"),
-		QualityCheckDate:        &now,
-		QualityCheckNotes:       &req.Notes,
-		CustomerApprovalRequired: true,
-		CustomerApprovalStatus:  "approved",
-		CustomerApprovedAt:      timePtr(now.Add(-24 * time.Hour)),
-		CreatedBy:               "550e8400-e29b-41d4-a716-446655440004",
-		CreatedAt:               now.Add(-48 * time.Hour),
-		UpdatedAt:               now,
-	}
-
-	c.JSON(http.StatusOK, response)
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	ticket, err := h.repairUseCase.QualityCheck(c.Request.Context(), storefrontID, ticketID, userID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to record quality check", slog.String("ticket_id", ticketID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Quality check recorded successfully", ticket)
 }
 
-// GetRepairStatistics retrieves repair ticket analytics and statistics
-// @Summary Get repair statistics
-// @Description Get comprehensive analytics and statistics for repair tickets
+// CancelRepairTicket cancels a repair ticket
+// @Summary Cancel repair ticket
+// @Description Cancel a repair ticket that has not completed, returning its reserved spare parts to inventory. The claim keeps its status so another ticket can be opened.
 // @Tags repair-tickets
 // @Accept json
 // @Produce json
-// @Param date_from query string false "Start date for statistics (YYYY-MM-DD)"
-// @Param date_to query string false "End date for statistics (YYYY-MM-DD)"
-// @Param technician_id query string false "Filter by technician ID"
-// @Success 200 {object} dto.RepairTicketStatisticsResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Param ticketId path string true "Repair ticket ID"
+// @Param request body dto.RepairTicketCancelRequest true "Cancellation request"
+// @Success 200 {object} dto.SuccessResponse{data=dto.RepairTicketResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/repair-tickets/{ticketId}/cancel [put]
+func (h *RepairTicketHandler) CancelRepairTicket(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	ticketID, ok := parseUUIDParam(c, "ticketId", "repair ticket ID")
+	if !ok {
+		return
+	}
+
+	var req dto.RepairTicketCancelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	ticket, err := h.repairUseCase.CancelRepairTicket(c.Request.Context(), storefrontID, ticketID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to cancel repair ticket", slog.String("ticket_id", ticketID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Repair ticket cancelled successfully", ticket)
+}
+
+// GetRepairStatistics retrieves repair ticket statistics
+// @Summary Get repair statistics
+// @Description Get repair turnaround, labor and parts cost and quality check pass rate, overall and per technician
+// @Tags repair-tickets
+// @Produce json
+// @Security BearerAuth
+// @Param date_from query string false "Statistics from date (YYYY-MM-DD), defaults to one month ago"
+// @Param date_to query string false "Statistics to date (YYYY-MM-DD), defaults to now"
+// @Success 200 {object} dto.SuccessResponse{data=dto.RepairTicketStatisticsResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
 // @Router /api/v1/admin/warranty/repair-tickets/statistics [get]
 func (h *RepairTicketHandler) GetRepairStatistics(c *gin.Context) {
-	// Mock implementation - in real scenario, this would call the service layer
-	now := time.Now()
-	
-	response := &dto.RepairTicketStatisticsResponse{
-		TotalTickets: 450,
-		TicketsByStatus: map[string]int{
-			"pending":     45,
-			"assigned":    120,
-			"in_progress": 85,
-			"completed":   180,
-			"cancelled":   20,
-		},
-		TicketsByPriority: map[string]int{
-			"low":    100,
-			"normal": 200,
-			"high":   120,
-			"urgent": 30,
-		},
-		TicketsByTechnician: map[string]int{
-			"John Smith": 45,
-			"Jane Doe":   38,
-			"Bob Wilson": 42,
-		},
-		AverageRepairTime:    decimal.NewFromFloat(4.8),
-		AverageLaborCost:     decimal.NewFromFloat(95.50),
-		AveragePartsCost:     decimal.NewFromFloat(67.25),
-		TotalLaborCost:       decimal.NewFromFloat(42975.00),
-		TotalPartsCost:       decimal.NewFromFloat(30262.50),
-		TotalRepairCost:      decimal.NewFromFloat(73237.50),
-		CompletionRate:       decimal.NewFromFloat(88.9),
-		QualityApprovalRate:  decimal.NewFromFloat(94.2),
-		CustomerApprovalRate: decimal.NewFromFloat(91.5),
-		TicketsThisMonth:     85,
-		TicketsLastMonth:     72,
-		GrowthRate:           decimal.NewFromFloat(18.1),
-		TopTechnicians: []dto.TechnicianStatsResponse{
-			{
-				TechnicianID:        "550e8400-e29b-41d4-a716-446655440002",
-				TechnicianName:      "John Smith",
-				AssignedTickets:     45,
-				CompletedTickets:    42,
-				CompletionRate:      decimal.NewFromFloat(93.3),
-				AverageRepairTime:   decimal.NewFromFloat(4.2),
-				QualityApprovalRate: decimal.NewFromFloat(97.6),
-				TotalRevenue:        decimal.NewFromFloat(8950.00),
-			},
-			{
-				TechnicianID:        "550e8400-e29b-41d4-a716-446655440007",
-				TechnicianName:      "Jane Doe",
-				AssignedTickets:     38,
-				CompletedTickets:    36,
-				CompletionRate:      decimal.NewFromFloat(94.7),
-				AverageRepairTime:   decimal.NewFromFloat(3.8),
-				QualityApprovalRate: decimal.NewFromFloat(100.0),
-				TotalRevenue:        decimal.NewFromFloat(7650.00),
-			},
-		},
-		RecentTickets: []dto.RepairTicketResponse{
-			{
-				ID:                      "550e8400-e29b-41d4-a716-446655440000",
-				TicketNumber:            "RPR-2024-001234",
-				ClaimID:                 "550e8400-e29b-41d4-a716-446655440001",
-				ClaimNumber:             "WAR-2024-001234",
-				Status:                  "assigned",
-				Priority:                "high",
-				AssignedTechnicianID:    stringPtr("550e8400-e29b-41d4-a716-446655440002"),
-				TechnicianName:          stringPtr("John Smith"),
-				AssignedAt:              &now,
-				EstimatedHours:          decimal.NewFromFloat(4.5),
-				EstimatedCompletionDate: timePtr(now.Add(48 * time.Hour)),
-				Description:             "Replace faulty motherboard and test all components",
-				RequiredParts:           []string{"motherboard", "thermal_paste"},
-				SpecialInstructions:     "Handle with care - customer reported water damage",
-				LaborCost:               decimal.NewFromFloat(120.00),
-				PartsCost:               decimal.NewFromFloat(85.50),
-				TotalCost:               decimal.NewFromFloat(205.50),
-				QualityCheckStatus:      "pending",
-				CustomerApprovalRequired: true,
-				CustomerApprovalStatus:  "pending",
-				CreatedBy:               "550e8400-e29b-41d4-a716-446655440004",
-				CreatedAt:               now.Add(-24 * time.Hour),
-				UpdatedAt:               now,
-			},
-		},
-	}
-
-	c.JSON(http.StatusOK, response)
-}
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+
+	var startDate, endDate *time.Time
+	if v := c.Query("date_from"); v != "" {
+		date, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, errInvalidQuery("date_from").Error(), nil)
+			return
+		}
+		startDate = &date
+	}
+	if v := c.Query("date_to"); v != "" {
+		date, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, errInvalidQuery("date_to").Error(), nil)
+			return
+		}
+		// Include the whole day
+		date = date.Add(24*time.Hour - time.Nanosecond)
+		endDate = &date
+	}
 
-// Helper functions for handling optional fields
-func stringPtr(s string) *string {
-	return &s
+	stats, err := h.repairUseCase.GetRepairStatistics(c.Request.Context(), storefrontID, startDate, endDate)
+	if err != nil {
+		h.handleError(c, err, "Failed to get repair statistics", slog.String("storefront_id", storefrontID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Repair statistics retrieved successfully", stats)
 }
 
-func timePtr(t time.Time) *time.Time {
-	return &t
+// handleError maps repair ticket use case errors to HTTP responses
+func (h *RepairTicketHandler) handleError(c *gin.Context, err error, message string, attrs ...any) {
+	status := warrantyClaimErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(message, append(attrs, slog.String("error", err.Error()))...)
+		utils.ErrorResponse(c, status, message, nil)
+		return
+	}
+
+	utils.ErrorResponse(c, status, err.Error(), nil)
 }
 
-func decimalPtr(d decimal.Decimal) *decimal.Decimal {
-	return &d
+// repairTicketSortColumns lists the columns repair tickets can be sorted by
+var repairTicketSortColumns = map[string]bool{
+	"created_at":             true,
+	"priority":               true,
+	"status":                 true,
+	"target_completion_date": true,
 }
 
-func getStringValue(ptr *string, defaultValue string) string {
-	if ptr != nil {
-		return *ptr
+// parseRepairTicketFilters reads repair ticket filter query parameters
+func parseRepairTicketFilters(c *gin.Context) (*repository.RepairTicketFilters, error) {
+	filters := &repository.RepairTicketFilters{}
+
+	if v := c.Query("status"); v != "" {
+		for _, s := range strings.Split(v, ",") {
+			status := entity.RepairStatus(strings.TrimSpace(s))
+			if !status.Valid() {
+				return nil, errInvalidQuery("status")
+			}
+			filters.Statuses = append(filters.Statuses, status)
+		}
+	}
+
+	if v := c.Query("priority"); v != "" {
+		for _, p := range strings.Split(v, ",") {
+			priority := entity.ClaimPriority(strings.TrimSpace(p))
+			if !priority.Valid() {
+				return nil, errInvalidQuery("priority")
+			}
+			filters.Priorities = append(filters.Priorities, priority)
+		}
+	}
+
+	if v := c.Query("technician_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, errInvalidQuery("technician_id")
+		}
+		filters.TechnicianID = &id
+	}
+
+	if v := c.Query("claim_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, errInvalidQuery("claim_id")
+		}
+		filters.ClaimID = &id
+	}
+
+	if v := c.Query("search"); v != "" {
+		filters.Search = &v
 	}
-	return defaultValue
-}
 
-func getDecimalValue(ptr *decimal.Decimal, defaultValue decimal.Decimal) decimal.Decimal {
-	if ptr != nil {
-		return *ptr
+	filters.SortBy = c.DefaultQuery("sort_by", "created_at")
+	if !repairTicketSortColumns[filters.SortBy] {
+		return nil, errInvalidQuery("sort_by")
 	}
-	return defaultValue
-}
\ No newline at end of file
+	filters.SortDirection = strings.ToLower(c.DefaultQuery("sort_dir", "desc"))
+	if filters.SortDirection != "asc" && filters.SortDirection != "desc" {
+		return nil, errInvalidQuery("sort_dir")
+	}
+
+	return filters, nil
+}
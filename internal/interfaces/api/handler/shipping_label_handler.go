@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/service"
+	"github.com/kirimku/smartseller-backend/internal/domain/errors"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/pkg/utils"
+)
+
+// ShippingLabelHandler handles printing shipping labels of booked orders
+type ShippingLabelHandler struct {
+	labelService   *service.ShippingLabelService
+	storefrontRepo repository.StorefrontRepository
+	logger         *slog.Logger
+}
+
+// NewShippingLabelHandler creates a new shipping label handler
+func NewShippingLabelHandler(labelService *service.ShippingLabelService, storefrontRepo repository.StorefrontRepository, logger *slog.Logger) *ShippingLabelHandler {
+	return &ShippingLabelHandler{
+		labelService:   labelService,
+		storefrontRepo: storefrontRepo,
+		logger:         logger,
+	}
+}
+
+// GetOrderLabel handles printing the shipping label of a booked order
+// @Summary Print shipping label
+// @Description Print the shipping label of an order booked with a courier, with the AWB as a Code128 barcode, the sender and recipient and the parcel's contents. Labels are A6 for thermal printers; the a4 layout prints the label in the top-left quarter of an A4 sheet.
+// @Tags Admin Orders
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param format query string false "pdf, png or jpg" default(pdf)
+// @Param layout query string false "a6 or a4, pdf only" default(a6)
+// @Param barcode query bool false "Print the AWB barcode" default(true)
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/orders/{id}/label [get]
+func (h *ShippingLabelHandler) GetOrderLabel(c *gin.Context) {
+	sellerID, err := uuid.Parse(utils.GetUserIDFromContext(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+	orderID, ok := parseUUIDParam(c, "id", "order ID")
+	if !ok {
+		return
+	}
+	opts, ok := parseShippingLabelOptions(c)
+	if !ok {
+		return
+	}
+
+	download, err := h.labelService.OrderLabel(c.Request.Context(), orderID, sellerID, opts)
+	if err != nil {
+		h.handleError(c, err, "Failed to print shipping label", slog.String("order_id", orderID.String()))
+		return
+	}
+
+	writeShippingLabel(c, download)
+}
+
+// GetDailyLabels handles printing the labels of all orders booked on a day
+// @Summary Print day's shipping labels
+// @Description Print the shipping labels of every order booked with a courier on a day in the storefront's timezone, oldest booking first. Use the a4 layout to print four labels to a sheet; images hold a single label, so use pdf when more than one order was booked.
+// @Tags Admin Orders
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param date query string false "Day as YYYY-MM-DD, defaults to today" example(2026-10-16)
+// @Param format query string false "pdf, png or jpg" default(pdf)
+// @Param layout query string false "a6 or a4, pdf only" default(a6)
+// @Param barcode query bool false "Print the AWB barcodes" default(true)
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/orders/labels [get]
+func (h *ShippingLabelHandler) GetDailyLabels(c *gin.Context) {
+	sellerID, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	opts, ok := parseShippingLabelOptions(c)
+	if !ok {
+		return
+	}
+
+	download, err := h.labelService.DailyLabels(c.Request.Context(), storefrontID, sellerID, c.Query("date"), opts)
+	if err != nil {
+		h.handleError(c, err, "Failed to print shipping labels", slog.String("storefront_id", storefrontID.String()))
+		return
+	}
+
+	writeShippingLabel(c, download)
+}
+
+// handleError maps shipping label service errors to HTTP responses
+func (h *ShippingLabelHandler) handleError(c *gin.Context, err error, message string, attrs ...any) {
+	status := customerSessionErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(message, append(attrs, slog.String("error", err.Error()))...)
+		utils.ErrorResponse(c, status, message, nil)
+		return
+	}
+
+	if appErr, ok := err.(*errors.AppError); ok {
+		utils.ErrorResponse(c, status, appErr.Message, nil)
+		return
+	}
+	utils.ErrorResponse(c, status, err.Error(), nil)
+}
+
+// parseShippingLabelOptions reads label options from query parameters
+func parseShippingLabelOptions(c *gin.Context) (service.ShippingLabelOptions, bool) {
+	opts := service.ShippingLabelOptions{
+		Format:         c.Query("format"),
+		Layout:         c.Query("layout"),
+		IncludeBarcode: true,
+	}
+	if v := c.Query("barcode"); v != "" {
+		barcode, err := strconv.ParseBool(v)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid barcode value, expected true or false", nil)
+			return opts, false
+		}
+		opts.IncludeBarcode = barcode
+	}
+	return opts, true
+}
+
+// writeShippingLabel sends a rendered label file as a download
+func writeShippingLabel(c *gin.Context, download *service.ShippingLabelDownload) {
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": download.Filename}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("X-Label-Count", strconv.Itoa(download.Labels))
+	c.Data(http.StatusOK, download.MimeType, download.Content)
+}
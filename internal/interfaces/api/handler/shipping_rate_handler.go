@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
+	"github.com/kirimku/smartseller-backend/pkg/utils"
+)
+
+// ShippingRateHandler handles shipping rate quotes at checkout
+type ShippingRateHandler struct {
+	shippingRateUseCase usecase.ShippingRateUseCase
+	logger              *slog.Logger
+}
+
+// NewShippingRateHandler creates a new shipping rate handler
+func NewShippingRateHandler(shippingRateUseCase usecase.ShippingRateUseCase, logger *slog.Logger) *ShippingRateHandler {
+	return &ShippingRateHandler{
+		shippingRateUseCase: shippingRateUseCase,
+		logger:              logger,
+	}
+}
+
+// QuoteRates handles quoting the shipping options of a cart
+// @Summary Quote shipping rates
+// @Description Weigh the cart and quote every supported courier (JNE, J&T, SiCepat, SAPX) from the storefront's shipping origin to the destination district. Options are sorted cheapest first; couriers that do not serve the route are left out.
+// @Tags Storefront Checkout
+// @Accept json
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param request body dto.ShippingRateRequest true "Cart and destination"
+// @Success 200 {object} dto.ShippingRateResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/checkout/shipping-rates [post]
+func (h *ShippingRateHandler) QuoteRates(c *gin.Context) {
+	storefrontID, exists := middleware.GetCustomerStorefrontID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Storefront context required", nil)
+		return
+	}
+
+	var req dto.ShippingRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	result, err := h.shippingRateUseCase.QuoteRates(c.Request.Context(), storefrontID, &req)
+	if err != nil {
+		status := shippingRateErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			h.logger.Error("Failed to quote shipping rates",
+				slog.String("error", err.Error()),
+				slog.String("storefront_id", storefrontID.String()))
+			utils.ErrorResponse(c, status, "Failed to quote shipping rates", nil)
+			return
+		}
+		utils.ErrorResponse(c, status, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shipping rates retrieved successfully", result)
+}
+
+// shippingRateErrorStatus maps shipping rate use case errors to HTTP status codes
+func shippingRateErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "failed to"):
+		return http.StatusInternalServerError
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "not configured"):
+		return http.StatusConflict
+	case strings.Contains(msg, "not available"),
+		strings.Contains(msg, "not accepting"),
+		strings.Contains(msg, "required"),
+		strings.Contains(msg, "invalid"),
+		strings.Contains(msg, "must"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
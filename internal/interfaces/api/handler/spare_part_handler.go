@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/pkg/utils"
+)
+
+// SparePartHandler handles spare parts inventory HTTP requests
+type SparePartHandler struct {
+	partUseCase    usecase.SparePartUseCase
+	storefrontRepo repository.StorefrontRepository
+	logger         *slog.Logger
+}
+
+// NewSparePartHandler creates a new spare part handler
+func NewSparePartHandler(partUseCase usecase.SparePartUseCase, storefrontRepo repository.StorefrontRepository, logger *slog.Logger) *SparePartHandler {
+	return &SparePartHandler{
+		partUseCase:    partUseCase,
+		storefrontRepo: storefrontRepo,
+		logger:         logger,
+	}
+}
+
+// ListSpareParts handles listing the spare parts inventory
+// @Summary List spare parts
+// @Description Get a paginated list of the storefront's spare parts with their stock. needs_reorder lists the parts whose available stock dropped to their reorder threshold, lowest first.
+// @Tags spare-parts
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param search query string false "Search part number, name or supplier"
+// @Param is_active query bool false "Filter by active status"
+// @Param needs_reorder query bool false "Only parts that need reordering"
+// @Success 200 {object} dto.SuccessResponse{data=dto.SparePartListResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/spare-parts [get]
+func (h *SparePartHandler) ListSpareParts(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+
+	filters := &repository.SparePartFilters{StorefrontID: storefrontID}
+	if v := c.Query("search"); v != "" {
+		filters.Search = &v
+	}
+	if v := c.Query("is_active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, errInvalidQuery("is_active").Error(), nil)
+			return
+		}
+		filters.IsActive = &active
+	}
+	if v := c.Query("needs_reorder"); v != "" {
+		needsReorder, err := strconv.ParseBool(v)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, errInvalidQuery("needs_reorder").Error(), nil)
+			return
+		}
+		filters.NeedsReorder = needsReorder
+	}
+	filters.Page, filters.PageSize = parseOrderPagination(c)
+
+	response, err := h.partUseCase.ListSpareParts(c.Request.Context(), filters)
+	if err != nil {
+		h.handleError(c, err, "Failed to list spare parts", slog.String("storefront_id", storefrontID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Spare parts retrieved successfully", response)
+}
+
+// CreateSparePart handles adding a part to the inventory
+// @Summary Create spare part
+// @Description Add a part to the storefront's spare parts inventory. Part numbers are unique per storefront.
+// @Tags spare-parts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.SparePartCreateRequest true "Spare part"
+// @Success 201 {object} dto.SuccessResponse{data=dto.SparePartResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/spare-parts [post]
+func (h *SparePartHandler) CreateSparePart(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+
+	var req dto.SparePartCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	part, err := h.partUseCase.CreateSparePart(c.Request.Context(), storefrontID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create spare part", slog.String("part_number", req.PartNumber))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Spare part created successfully", part)
+}
+
+// GetSparePart handles retrieving a spare part
+// @Summary Get spare part
+// @Description Get a spare part with its stock on hand, reserved and available
+// @Tags spare-parts
+// @Produce json
+// @Security BearerAuth
+// @Param partId path string true "Spare part ID"
+// @Success 200 {object} dto.SuccessResponse{data=dto.SparePartResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/spare-parts/{partId} [get]
+func (h *SparePartHandler) GetSparePart(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	partID, ok := parseUUIDParam(c, "partId", "spare part ID")
+	if !ok {
+		return
+	}
+
+	part, err := h.partUseCase.GetSparePart(c.Request.Context(), storefrontID, partID)
+	if err != nil {
+		h.handleError(c, err, "Failed to get spare part", slog.String("part_id", partID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Spare part retrieved successfully", part)
+}
+
+// UpdateSparePart handles updating the catalogue details of a spare part
+// @Summary Update spare part
+// @Description Update the catalogue details and reorder settings of a spare part. Stock is changed through stock adjustments and repairs.
+// @Tags spare-parts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param partId path string true "Spare part ID"
+// @Param request body dto.SparePartUpdateRequest true "Spare part update"
+// @Success 200 {object} dto.SuccessResponse{data=dto.SparePartResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/spare-parts/{partId} [put]
+func (h *SparePartHandler) UpdateSparePart(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	partID, ok := parseUUIDParam(c, "partId", "spare part ID")
+	if !ok {
+		return
+	}
+
+	var req dto.SparePartUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	part, err := h.partUseCase.UpdateSparePart(c.Request.Context(), storefrontID, partID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update spare part", slog.String("part_id", partID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Spare part updated successfully", part)
+}
+
+// DeleteSparePart handles removing a spare part from the inventory
+// @Summary Delete spare part
+// @Description Remove a spare part that no repair holds stock of
+// @Tags spare-parts
+// @Produce json
+// @Security BearerAuth
+// @Param partId path string true "Spare part ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/spare-parts/{partId} [delete]
+func (h *SparePartHandler) DeleteSparePart(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	partID, ok := parseUUIDParam(c, "partId", "spare part ID")
+	if !ok {
+		return
+	}
+
+	if err := h.partUseCase.DeleteSparePart(c.Request.Context(), storefrontID, partID); err != nil {
+		h.handleError(c, err, "Failed to delete spare part", slog.String("part_id", partID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Spare part deleted successfully", nil)
+}
+
+// AdjustStock handles recording stock received or written off
+// @Summary Adjust spare part stock
+// @Description Record stock received (positive quantity) or written off (negative quantity). The unit cost of received stock updates the part's weighted average cost. Stock reserved for repairs cannot be written off.
+// @Tags spare-parts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param partId path string true "Spare part ID"
+// @Param request body dto.SparePartStockAdjustmentRequest true "Stock adjustment"
+// @Success 200 {object} dto.SuccessResponse{data=dto.SparePartResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/spare-parts/{partId}/stock [post]
+func (h *SparePartHandler) AdjustStock(c *gin.Context) {
+	userID, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	partID, ok := parseUUIDParam(c, "partId", "spare part ID")
+	if !ok {
+		return
+	}
+
+	var req dto.SparePartStockAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	part, err := h.partUseCase.AdjustStock(c.Request.Context(), storefrontID, partID, userID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to adjust spare part stock", slog.String("part_id", partID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Spare part stock adjusted successfully", part)
+}
+
+// handleError maps spare part use case errors to HTTP responses
+func (h *SparePartHandler) handleError(c *gin.Context, err error, message string, attrs ...any) {
+	status := warrantyClaimErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(message, append(attrs, slog.String("error", err.Error()))...)
+		utils.ErrorResponse(c, status, message, nil)
+		return
+	}
+
+	utils.ErrorResponse(c, status, err.Error(), nil)
+}
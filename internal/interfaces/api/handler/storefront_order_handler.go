@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
+	"github.com/kirimku/smartseller-backend/pkg/utils"
+)
+
+// StorefrontOrderHandler handles order requests from authenticated storefront customers
+type StorefrontOrderHandler struct {
+	orderUseCase usecase.OrderUseCase
+	logger       *slog.Logger
+}
+
+// NewStorefrontOrderHandler creates a new storefront order handler
+func NewStorefrontOrderHandler(orderUseCase usecase.OrderUseCase, logger *slog.Logger) *StorefrontOrderHandler {
+	return &StorefrontOrderHandler{
+		orderUseCase: orderUseCase,
+		logger:       logger,
+	}
+}
+
+// GetCustomerOrders handles listing the authenticated customer's orders
+// @Summary List my orders
+// @Description Get a paginated list of the customer's orders on this storefront
+// @Tags Storefront Orders
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page" default(20)
+// @Param status query string false "Filter by order status"
+// @Success 200 {object} dto.OrderListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/orders [get]
+func (h *StorefrontOrderHandler) GetCustomerOrders(c *gin.Context) {
+	storefrontID, customerID, ok := h.getCustomerContext(c)
+	if !ok {
+		return
+	}
+
+	var status *entity.OrderStatus
+	if v := c.Query("status"); v != "" {
+		s := entity.OrderStatus(v)
+		if !s.Valid() {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid status parameter", nil)
+			return
+		}
+		status = &s
+	}
+
+	page, pageSize := parseOrderPagination(c)
+
+	orders, err := h.orderUseCase.ListCustomerOrders(c.Request.Context(), storefrontID, customerID, status, page, pageSize)
+	if err != nil {
+		h.logger.Error("Failed to list customer orders",
+			slog.String("error", err.Error()),
+			slog.String("customer_id", customerID.String()))
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve orders", nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Orders retrieved successfully", orders)
+}
+
+// GetOrder handles retrieving one of the customer's orders
+// @Summary Get my order
+// @Description Get one of the customer's orders with its items
+// @Tags Storefront Orders
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param id path string true "Order ID"
+// @Success 200 {object} dto.OrderResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/orders/{id} [get]
+func (h *StorefrontOrderHandler) GetOrder(c *gin.Context) {
+	storefrontID, customerID, ok := h.getCustomerContext(c)
+	if !ok {
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID format", err)
+		return
+	}
+
+	order, err := h.orderUseCase.GetCustomerOrder(c.Request.Context(), storefrontID, customerID, orderID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve order", orderID)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Order retrieved successfully", order)
+}
+
+// GetOrderStatus handles retrieving the tracking status of one of the customer's orders
+// @Summary Get my order status
+// @Description Get the current status, shipment details and status history of an order
+// @Tags Storefront Orders
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param id path string true "Order ID"
+// @Success 200 {object} dto.OrderTrackingResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/orders/{id}/status [get]
+func (h *StorefrontOrderHandler) GetOrderStatus(c *gin.Context) {
+	storefrontID, customerID, ok := h.getCustomerContext(c)
+	if !ok {
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID format", err)
+		return
+	}
+
+	status, err := h.orderUseCase.GetCustomerOrderStatus(c.Request.Context(), storefrontID, customerID, orderID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve order status", orderID)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Order status retrieved successfully", status)
+}
+
+// CancelOrder handles cancellation of one of the customer's orders
+// @Summary Cancel my order
+// @Description Cancel an order that the seller has not started processing yet
+// @Tags Storefront Orders
+// @Accept json
+// @Produce json
+// @Security CustomerBearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param id path string true "Order ID"
+// @Param request body dto.CancelOrderRequest false "Cancellation reason"
+// @Success 200 {object} dto.OrderResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/storefront/{slug}/orders/{id}/cancel [post]
+func (h *StorefrontOrderHandler) CancelOrder(c *gin.Context) {
+	storefrontID, customerID, ok := h.getCustomerContext(c)
+	if !ok {
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID format", err)
+		return
+	}
+
+	var req dto.CancelOrderRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+			return
+		}
+	}
+
+	order, err := h.orderUseCase.CancelCustomerOrder(c.Request.Context(), storefrontID, customerID, orderID, req.Reason)
+	if err != nil {
+		h.handleError(c, err, "Failed to cancel order", orderID)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Order cancelled successfully", order)
+}
+
+// getCustomerContext extracts the storefront and customer from the request,
+// writing an error response when either is missing
+func (h *StorefrontOrderHandler) getCustomerContext(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	customerIDStr, exists := middleware.GetCustomerID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Authentication required", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	customerID, err := uuid.Parse(customerIDStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid customer ID", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	storefrontID, exists := middleware.GetCustomerStorefrontID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Storefront context required", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return storefrontID, customerID, true
+}
+
+// handleError maps order use case errors to HTTP responses for customers
+func (h *StorefrontOrderHandler) handleError(c *gin.Context, err error, message string, orderID uuid.UUID) {
+	status := orderErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(message, slog.String("error", err.Error()), slog.String("order_id", orderID.String()))
+		utils.ErrorResponse(c, status, message, nil)
+		return
+	}
+
+	utils.ErrorResponse(c, status, err.Error(), nil)
+}
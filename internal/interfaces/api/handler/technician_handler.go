@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/pkg/utils"
+)
+
+// TechnicianHandler handles repair roster HTTP requests
+type TechnicianHandler struct {
+	technicianUseCase usecase.TechnicianUseCase
+	storefrontRepo    repository.StorefrontRepository
+	logger            *slog.Logger
+}
+
+// NewTechnicianHandler creates a new technician handler
+func NewTechnicianHandler(technicianUseCase usecase.TechnicianUseCase, storefrontRepo repository.StorefrontRepository, logger *slog.Logger) *TechnicianHandler {
+	return &TechnicianHandler{
+		technicianUseCase: technicianUseCase,
+		storefrontRepo:    storefrontRepo,
+		logger:            logger,
+	}
+}
+
+// ListTechnicians handles listing the repair roster
+// @Summary List technicians
+// @Description Get the storefront's repair roster with each technician's skills, capacity and working hours
+// @Tags technicians
+// @Produce json
+// @Security BearerAuth
+// @Param is_active query bool false "Filter by active status"
+// @Success 200 {object} dto.SuccessResponse{data=[]dto.TechnicianResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/technicians [get]
+func (h *TechnicianHandler) ListTechnicians(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+
+	var isActive *bool
+	if v := c.Query("is_active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, errInvalidQuery("is_active").Error(), nil)
+			return
+		}
+		isActive = &active
+	}
+
+	technicians, err := h.technicianUseCase.ListTechnicians(c.Request.Context(), storefrontID, isActive)
+	if err != nil {
+		h.handleError(c, err, "Failed to list technicians", slog.String("storefront_id", storefrontID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Technicians retrieved successfully", technicians)
+}
+
+// CreateTechnician handles adding a seller user to the repair roster
+// @Summary Create technician
+// @Description Add a seller user to the storefront's repair roster. Skills are product categories and cover their subcategories. Working hours are weekly shifts in the technician's timezone; without any the technician takes work at any time.
+// @Tags technicians
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.TechnicianCreateRequest true "Technician"
+// @Success 201 {object} dto.SuccessResponse{data=dto.TechnicianResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/technicians [post]
+func (h *TechnicianHandler) CreateTechnician(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+
+	var req dto.TechnicianCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	technician, err := h.technicianUseCase.CreateTechnician(c.Request.Context(), storefrontID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create technician", slog.String("user_id", req.UserID))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Technician created successfully", technician)
+}
+
+// GetTechnicianWorkload handles retrieving the repair workload for supervisors
+// @Summary Get technician workload
+// @Description Get the claims and repair tickets each technician is working on, their remaining capacity and whether they are on shift, with the validated claims still waiting for a technician
+// @Tags technicians
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.SuccessResponse{data=dto.TechnicianWorkloadSummaryResponse}
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/technicians/workload [get]
+func (h *TechnicianHandler) GetTechnicianWorkload(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+
+	workload, err := h.technicianUseCase.GetWorkload(c.Request.Context(), storefrontID)
+	if err != nil {
+		h.handleError(c, err, "Failed to get technician workload", slog.String("storefront_id", storefrontID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Technician workload retrieved successfully", workload)
+}
+
+// GetTechnician handles retrieving a technician
+// @Summary Get technician
+// @Description Get a technician of the repair roster
+// @Tags technicians
+// @Produce json
+// @Security BearerAuth
+// @Param technicianId path string true "Technician ID"
+// @Success 200 {object} dto.SuccessResponse{data=dto.TechnicianResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/technicians/{technicianId} [get]
+func (h *TechnicianHandler) GetTechnician(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	technicianID, ok := parseUUIDParam(c, "technicianId", "technician ID")
+	if !ok {
+		return
+	}
+
+	technician, err := h.technicianUseCase.GetTechnician(c.Request.Context(), storefrontID, technicianID)
+	if err != nil {
+		h.handleError(c, err, "Failed to get technician", slog.String("technician_id", technicianID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Technician retrieved successfully", technician)
+}
+
+// UpdateTechnician handles updating a technician
+// @Summary Update technician
+// @Description Update a technician's capacity, working hours, skills or active status. Working hours and skills, when present, replace the current ones. Lowering the capacity keeps the claims already assigned.
+// @Tags technicians
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param technicianId path string true "Technician ID"
+// @Param request body dto.TechnicianUpdateRequest true "Technician update"
+// @Success 200 {object} dto.SuccessResponse{data=dto.TechnicianResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/technicians/{technicianId} [put]
+func (h *TechnicianHandler) UpdateTechnician(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	technicianID, ok := parseUUIDParam(c, "technicianId", "technician ID")
+	if !ok {
+		return
+	}
+
+	var req dto.TechnicianUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	technician, err := h.technicianUseCase.UpdateTechnician(c.Request.Context(), storefrontID, technicianID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update technician", slog.String("technician_id", technicianID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Technician updated successfully", technician)
+}
+
+// DeleteTechnician handles removing a technician from the repair roster
+// @Summary Delete technician
+// @Description Remove a technician with no active claims from the repair roster
+// @Tags technicians
+// @Produce json
+// @Security BearerAuth
+// @Param technicianId path string true "Technician ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/technicians/{technicianId} [delete]
+func (h *TechnicianHandler) DeleteTechnician(c *gin.Context) {
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	technicianID, ok := parseUUIDParam(c, "technicianId", "technician ID")
+	if !ok {
+		return
+	}
+
+	if err := h.technicianUseCase.DeleteTechnician(c.Request.Context(), storefrontID, technicianID); err != nil {
+		h.handleError(c, err, "Failed to delete technician", slog.String("technician_id", technicianID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Technician deleted successfully", nil)
+}
+
+// handleError maps technician use case errors to HTTP responses
+func (h *TechnicianHandler) handleError(c *gin.Context, err error, message string, attrs ...any) {
+	status := warrantyClaimErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(message, append(attrs, slog.String("error", err.Error()))...)
+		utils.ErrorResponse(c, status, message, nil)
+		return
+	}
+
+	utils.ErrorResponse(c, status, err.Error(), nil)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/kirimku/smartseller-backend/internal/application/dto"
 	"github.com/kirimku/smartseller-backend/internal/application/service"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
 	"github.com/kirimku/smartseller-backend/internal/domain/entity"
 	"github.com/kirimku/smartseller-backend/internal/domain/repository"
 	"github.com/kirimku/smartseller-backend/internal/infrastructure/tenant"
@@ -31,6 +32,7 @@ type WarrantyBarcodeHandler struct {
 	tenantResolver      tenant.TenantResolver
 	storefrontRepo      repository.StorefrontRepository
 	barcodeRepo         repository.WarrantyBarcodeRepository
+	policyUseCase       usecase.WarrantyPolicyUseCase
 }
 
 // NewWarrantyBarcodeHandler creates a new warranty barcode handler
@@ -41,7 +43,7 @@ func NewWarrantyBarcodeHandler(logger *slog.Logger) *WarrantyBarcodeHandler {
 }
 
 // NewWarrantyBarcodeHandlerWithDependencies creates a new warranty barcode handler with all dependencies
-func NewWarrantyBarcodeHandlerWithDependencies(logger *slog.Logger, db *sqlx.DB, tenantResolver tenant.TenantResolver, repo repository.WarrantyBarcodeRepository, storefrontRepo repository.StorefrontRepository) *WarrantyBarcodeHandler {
+func NewWarrantyBarcodeHandlerWithDependencies(logger *slog.Logger, db *sqlx.DB, tenantResolver tenant.TenantResolver, repo repository.WarrantyBarcodeRepository, storefrontRepo repository.StorefrontRepository, policyUseCase usecase.WarrantyPolicyUseCase) *WarrantyBarcodeHandler {
 	zeroLogger := zerolog.New(os.Stdout).With().Str("component", "warranty_barcode").Timestamp().Logger()
 	barcodeRepoAdapter := service.NewWarrantyBarcodeRepositoryAdapter(repo)
 	barcodeService := service.NewBarcodeGeneratorService(
@@ -58,6 +60,7 @@ func NewWarrantyBarcodeHandlerWithDependencies(logger *slog.Logger, db *sqlx.DB,
 		barcodeService: barcodeService,
 		storefrontRepo: storefrontRepo,
 		barcodeRepo:    repo,
+		policyUseCase:  policyUseCase,
 	}
 }
 
@@ -202,8 +205,33 @@ func (h *WarrantyBarcodeHandler) GenerateBarcodes(c *gin.Context) {
 	// Add storefront ID to context for tenant resolver
 	ctx := context.WithValue(c.Request.Context(), "storefront_id", storefrontID)
 
+	// Without an explicit period the product's warranty policy decides it
+	warrantyPeriodMonths := req.ExpiryMonths
+	if warrantyPeriodMonths < 0 || warrantyPeriodMonths > 120 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "expiry_months must be between 1 and 120", nil)
+		return
+	}
+	if warrantyPeriodMonths == 0 {
+		if h.policyUseCase == nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "expiry_months is required", nil)
+			return
+		}
+		policy, err := h.policyUseCase.ResolvePolicy(ctx, storefrontID, productID, time.Now())
+		if err != nil {
+			h.logger.Warn("Failed to resolve warranty policy", "product_id", productID, "error", err.Error())
+			utils.ErrorResponse(c, warrantyPolicyErrorStatus(err), "Failed to resolve warranty policy", err)
+			return
+		}
+		if policy == nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "No warranty policy applies to this product; expiry_months is required", nil)
+			return
+		}
+		warrantyPeriodMonths = policy.WarrantyPeriodMonths
+		h.logger.Info("Using warranty policy for barcode generation", "policy_id", policy.ID, "warranty_period_months", warrantyPeriodMonths)
+	}
+
 	// Debug logging before service call
-	h.logger.Info("DEBUG: Calling service", "warrantyPeriodMonths", req.ExpiryMonths)
+	h.logger.Info("DEBUG: Calling service", "warrantyPeriodMonths", warrantyPeriodMonths)
 
 	// Generate barcodes using the service
 	startTime := time.Now()
@@ -216,7 +244,7 @@ func (h *WarrantyBarcodeHandler) GenerateBarcodes(c *gin.Context) {
 			productID,
 			storefrontID,
 			createdBy,
-			req.ExpiryMonths,
+			warrantyPeriodMonths,
 		)
 		if err != nil {
 			h.logger.Error("Failed to generate barcode", "error", err.Error(), "attempt", i+1)
@@ -3,41 +3,50 @@ package handler
 import (
 	"log/slog"
 	"net/http"
-	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
 	"github.com/kirimku/smartseller-backend/pkg/utils"
 )
 
 // WarrantyClaimHandler handles warranty claim-related HTTP requests
 type WarrantyClaimHandler struct {
-	logger *slog.Logger
+	claimUseCase   usecase.WarrantyClaimUseCase
+	storefrontRepo repository.StorefrontRepository
+	logger         *slog.Logger
 }
 
 // NewWarrantyClaimHandler creates a new warranty claim handler
-func NewWarrantyClaimHandler(logger *slog.Logger) *WarrantyClaimHandler {
+func NewWarrantyClaimHandler(claimUseCase usecase.WarrantyClaimUseCase, storefrontRepo repository.StorefrontRepository, logger *slog.Logger) *WarrantyClaimHandler {
 	return &WarrantyClaimHandler{
-		logger: logger,
+		claimUseCase:   claimUseCase,
+		storefrontRepo: storefrontRepo,
+		logger:         logger,
 	}
 }
 
 // ListClaims handles claim listing requests
 // @Summary List warranty claims
-// @Description Get paginated list of warranty claims with advanced filtering options
+// @Description Get paginated list of warranty claims with advanced filtering options, including claims at risk of breaching or past their SLA
 // @Tags warranty-claims
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(20)
-// @Param sort_by query string false "Sort field" default("created_at")
+// @Param sort_by query string false "Sort field" Enums(created_at,claim_date,status,severity,priority,sla_due_at) default("created_at")
 // @Param sort_dir query string false "Sort direction" Enums(asc,desc) default("desc")
-// @Param status query string false "Filter by status" Enums(pending,validated,in_progress,completed,rejected,cancelled)
+// @Param status query string false "Filter by status" Enums(pending,validated,rejected,assigned,in_repair,repaired,replaced,shipped,delivered,completed,cancelled,disputed)
 // @Param severity query string false "Filter by severity" Enums(low,medium,high,critical)
+// @Param priority query string false "Filter by priority" Enums(low,normal,high,urgent)
 // @Param category query string false "Filter by category" Enums(hardware,software,performance,defect,damage,other)
+// @Param sla_state query string false "Filter by SLA state" Enums(on_track,at_risk,breached,paused,met)
 // @Param customer_id query string false "Filter by customer ID"
 // @Param product_id query string false "Filter by product ID"
 // @Param technician_id query string false "Filter by assigned technician"
@@ -51,97 +60,25 @@ func NewWarrantyClaimHandler(logger *slog.Logger) *WarrantyClaimHandler {
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/v1/admin/warranty/claims [get]
 func (h *WarrantyClaimHandler) ListClaims(c *gin.Context) {
-	userID := utils.GetUserIDFromContext(c)
-	if userID == "" {
-		h.logger.Warn("User ID not found in context")
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Authentication required", nil)
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
 		return
 	}
 
-	// Parse pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
-	_ = c.DefaultQuery("sort_by", "created_at")    // sortBy - will be used when implementing actual logic
-	_ = c.DefaultQuery("sort_dir", "desc")         // sortDir - will be used when implementing actual logic
-
-	// Validate pagination
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 20
-	}
-
-	// Parse filters
-	status := c.Query("status")
-	severity := c.Query("severity")
-	category := c.Query("category")
-	customerID := c.Query("customer_id")
-	productID := c.Query("product_id")
-	technicianID := c.Query("technician_id")
-	search := c.Query("search")
-
-	// Parse date filters - will be used when implementing actual logic
-	if dateFromStr := c.Query("date_from"); dateFromStr != "" {
-		if _, err := time.Parse("2006-01-02", dateFromStr); err == nil {
-			// dateFrom will be used when implementing actual logic
-		}
-	}
-	if dateToStr := c.Query("date_to"); dateToStr != "" {
-		if _, err := time.Parse("2006-01-02", dateToStr); err == nil {
-			// dateTo will be used when implementing actual logic
-		}
-	}
-
-	// TODO: Implement actual claim listing logic when usecase is ready
-	// For now, return a mock response
-	mockClaims := []dto.WarrantyClaimResponse{
-		{
-			ID:               uuid.New().String(),
-			ClaimNumber:      "WC-2024-001",
-			BarcodeID:        uuid.New().String(),
-			CustomerID:       uuid.New().String(),
-			ProductID:        uuid.New().String(),
-			StorefrontID:     uuid.New().String(),
-			IssueDescription: "Device not working properly",
-			IssueCategory:    "hardware",
-			IssueDate:        time.Now().AddDate(0, 0, -7),
-			Severity:         "medium",
-			ClaimDate:        time.Now().AddDate(0, 0, -5),
-			Status:           "pending",
-			CustomerName:     "John Doe",
-			CustomerEmail:    "john.doe@example.com",
-			Priority:         "medium",
-			CreatedAt:        time.Now().AddDate(0, 0, -5),
-			UpdatedAt:        time.Now().AddDate(0, 0, -1),
-		},
+	filters, err := parseWarrantyClaimFilters(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
 	}
+	filters.StorefrontID = &storefrontID
+	filters.Page, filters.PageSize = parseOrderPagination(c)
 
-	response := &dto.WarrantyClaimListResponse{
-		Claims: mockClaims,
-		Pagination: dto.PaginationResponse{
-			Page:       page,
-			Limit:      pageSize,
-			Total:      1,
-			TotalPages: 1,
-		},
+	response, err := h.claimUseCase.ListClaims(c.Request.Context(), filters)
+	if err != nil {
+		h.handleError(c, err, "Failed to list warranty claims", slog.String("storefront_id", storefrontID.String()))
+		return
 	}
 
-	h.logger.Info("Successfully retrieved warranty claims (mock)",
-		"page", page,
-		"page_size", pageSize,
-		"filters", map[string]string{
-			"status":        status,
-			"severity":      severity,
-			"category":      category,
-			"customer_id":   customerID,
-			"product_id":    productID,
-			"technician_id": technicianID,
-			"search":        search,
-		},
-		"user_id", userID,
-	)
-
 	utils.SuccessResponse(c, http.StatusOK, "Warranty claims retrieved successfully", response)
 }
 
@@ -233,7 +170,7 @@ func (h *WarrantyClaimHandler) GetClaim(c *gin.Context) {
 
 // ValidateClaim handles claim validation requests
 // @Summary Validate warranty claim
-// @Description Validate a warranty claim and update its status
+// @Description Validate, reject or request more information on a pending warranty claim. A validated claim is assigned to the active technician skilled in the product with the lowest share of their capacity in use, preferring technicians on shift; it stays validated when nobody is available.
 // @Tags warranty-claims
 // @Accept json
 // @Produce json
@@ -246,20 +183,14 @@ func (h *WarrantyClaimHandler) GetClaim(c *gin.Context) {
 // @Failure 403 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
-// @Router /api/v1/admin/warranty/claims/{id}/validate [put]
+// @Router /api/v1/admin/warranty/claims/{id}/validate [post]
 func (h *WarrantyClaimHandler) ValidateClaim(c *gin.Context) {
-	userID := utils.GetUserIDFromContext(c)
-	if userID == "" {
-		h.logger.Warn("User ID not found in context")
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Authentication required", nil)
+	userID, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
 		return
 	}
-
-	claimIDStr := c.Param("id")
-	claimID, err := uuid.Parse(claimIDStr)
-	if err != nil {
-		h.logger.Warn("Invalid claim ID format", "claim_id", claimIDStr)
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid claim ID format", nil)
+	claimID, ok := parseUUIDParam(c, "id", "claim ID")
+	if !ok {
 		return
 	}
 
@@ -269,39 +200,17 @@ func (h *WarrantyClaimHandler) ValidateClaim(c *gin.Context) {
 		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
 		return
 	}
-
-	// TODO: Implement actual claim validation logic when usecase is ready
-	// For now, return a mock response
-	mockClaim := &dto.WarrantyClaimResponse{
-		ID:               claimID.String(),
-		ClaimNumber:      "WC-2024-001",
-		BarcodeID:        uuid.New().String(),
-		CustomerID:       uuid.New().String(),
-		ProductID:        uuid.New().String(),
-		StorefrontID:     uuid.New().String(),
-		IssueDescription: "Device not working properly after 3 months",
-		IssueCategory:    "hardware",
-		IssueDate:        time.Now().AddDate(0, 0, -7),
-		Severity:         "medium",
-		ClaimDate:        time.Now().AddDate(0, 0, -5),
-		ValidatedAt:      &time.Time{},
-		Status:           "validated",
-		StatusUpdatedAt:  time.Now(),
-		CustomerName:     "John Doe",
-		CustomerEmail:    "john.doe@example.com",
-		Priority:         "medium",
-		CreatedAt:        time.Now().AddDate(0, 0, -5),
-		UpdatedAt:        time.Now(),
+	if req.Action == "" {
+		req.Action = "validate"
 	}
-	*mockClaim.ValidatedAt = time.Now()
 
-	h.logger.Info("Successfully validated warranty claim (mock)",
-		"claim_id", claimID,
-		"validated_by", userID,
-		"notes", req.Notes,
-	)
+	claim, err := h.claimUseCase.ValidateClaim(c.Request.Context(), storefrontID, claimID, &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to validate warranty claim", slog.String("claim_id", claimID.String()))
+		return
+	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Warranty claim validated successfully", mockClaim)
+	utils.SuccessResponse(c, http.StatusOK, "Warranty claim validated successfully", claim)
 }
 
 // RejectClaim handles claim rejection requests
@@ -377,7 +286,7 @@ func (h *WarrantyClaimHandler) RejectClaim(c *gin.Context) {
 
 // AssignTechnician handles technician assignment requests
 // @Summary Assign technician to claim
-// @Description Assign a technician to handle a warranty claim
+// @Description Assign a technician of the storefront's repair roster to a warranty claim. Supervisors may assign past a technician's capacity; inactive technicians cannot be assigned.
 // @Tags warranty-claims
 // @Accept json
 // @Produce json
@@ -390,20 +299,14 @@ func (h *WarrantyClaimHandler) RejectClaim(c *gin.Context) {
 // @Failure 403 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
-// @Router /api/v1/admin/warranty/claims/{id}/assign [put]
+// @Router /api/v1/admin/warranty/claims/{id}/assign [post]
 func (h *WarrantyClaimHandler) AssignTechnician(c *gin.Context) {
-	userID := utils.GetUserIDFromContext(c)
-	if userID == "" {
-		h.logger.Warn("User ID not found in context")
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Authentication required", nil)
+	userID, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
 		return
 	}
-
-	claimIDStr := c.Param("id")
-	claimID, err := uuid.Parse(claimIDStr)
-	if err != nil {
-		h.logger.Warn("Invalid claim ID format", "claim_id", claimIDStr)
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid claim ID format", nil)
+	claimID, ok := parseUUIDParam(c, "id", "claim ID")
+	if !ok {
 		return
 	}
 
@@ -414,45 +317,45 @@ func (h *WarrantyClaimHandler) AssignTechnician(c *gin.Context) {
 		return
 	}
 
-	// Validate technician ID
-	technicianID, err := uuid.Parse(req.TechnicianID)
+	claim, err := h.claimUseCase.AssignTechnician(c.Request.Context(), storefrontID, claimID, &req, userID)
 	if err != nil {
-		h.logger.Warn("Invalid technician ID format", "technician_id", req.TechnicianID)
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid technician ID format", nil)
+		h.handleError(c, err, "Failed to assign technician", slog.String("claim_id", claimID.String()))
 		return
 	}
 
-	// TODO: Implement actual technician assignment logic when usecase is ready
-	// For now, return a mock response
-	mockClaim := &dto.WarrantyClaimResponse{
-		ID:               claimID.String(),
-		ClaimNumber:      "WC-2024-001",
-		BarcodeID:        uuid.New().String(),
-		CustomerID:       uuid.New().String(),
-		ProductID:        uuid.New().String(),
-		StorefrontID:     uuid.New().String(),
-		IssueDescription: "Device not working properly after 3 months",
-		IssueCategory:    "hardware",
-		IssueDate:        time.Now().AddDate(0, 0, -7),
-		Severity:         "medium",
-		ClaimDate:        time.Now().AddDate(0, 0, -5),
-		Status:           "in_progress",
-		StatusUpdatedAt:  time.Now(),
-		CustomerName:     "John Doe",
-		CustomerEmail:    "john.doe@example.com",
-		Priority:         "medium",
-		CreatedAt:        time.Now().AddDate(0, 0, -5),
-		UpdatedAt:        time.Now(),
+	utils.SuccessResponse(c, http.StatusOK, "Technician assigned to warranty claim successfully", claim)
+}
+
+// AutoAssignTechnician handles automatic technician assignment requests
+// @Summary Auto-assign technician to claim
+// @Description Assign a validated claim to the active technician skilled in the product with the lowest share of their capacity in use, preferring technicians on shift. Used for claims validated while nobody was available.
+// @Tags warranty-claims
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Claim ID"
+// @Success 200 {object} dto.SuccessResponse{data=dto.WarrantyClaimResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/claims/{id}/auto-assign [post]
+func (h *WarrantyClaimHandler) AutoAssignTechnician(c *gin.Context) {
+	userID, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
+		return
+	}
+	claimID, ok := parseUUIDParam(c, "id", "claim ID")
+	if !ok {
+		return
 	}
 
-	h.logger.Info("Successfully assigned technician to warranty claim (mock)",
-		"claim_id", claimID,
-		"technician_id", technicianID,
-		"assigned_by", userID,
-		"notes", req.Notes,
-	)
+	claim, err := h.claimUseCase.AutoAssignTechnician(c.Request.Context(), storefrontID, claimID, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to assign technician", slog.String("claim_id", claimID.String()))
+		return
+	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Technician assigned to warranty claim successfully", mockClaim)
+	utils.SuccessResponse(c, http.StatusOK, "Technician assigned to warranty claim successfully", claim)
 }
 
 // CompleteClaim handles claim completion requests
@@ -530,80 +433,52 @@ func (h *WarrantyClaimHandler) CompleteClaim(c *gin.Context) {
 
 // GetClaimStatistics handles claim statistics requests
 // @Summary Get warranty claim statistics
-// @Description Get comprehensive statistics and analytics for warranty claims
+// @Description Get statistics for the storefront's warranty claims, including SLA compliance and claims at risk of or past their SLA
 // @Tags warranty-claims
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param date_from query string false "Statistics from date (YYYY-MM-DD)"
-// @Param date_to query string false "Statistics to date (YYYY-MM-DD)"
-// @Param storefront_id query string false "Filter by storefront ID"
-// @Param product_id query string false "Filter by product ID"
+// @Param date_from query string false "Statistics from date (YYYY-MM-DD), defaults to one month ago"
+// @Param date_to query string false "Statistics to date (YYYY-MM-DD), defaults to now"
 // @Success 200 {object} dto.SuccessResponse{data=dto.WarrantyClaimStatsResponse}
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 403 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
-// @Router /api/v1/admin/warranty/claims/statistics [get]
+// @Router /api/v1/admin/warranty/claims/stats [get]
 func (h *WarrantyClaimHandler) GetClaimStatistics(c *gin.Context) {
-	userID := utils.GetUserIDFromContext(c)
-	if userID == "" {
-		h.logger.Warn("User ID not found in context")
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Authentication required", nil)
+	_, storefrontID, ok := resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+	if !ok {
 		return
 	}
 
-	// Parse query parameters
-	dateFrom := c.Query("date_from")
-	dateTo := c.Query("date_to")
-	storefrontID := c.Query("storefront_id")
-	productID := c.Query("product_id")
-
-	// TODO: Implement actual statistics logic when usecase is ready
-	// For now, return a mock response
-	mockStats := &dto.WarrantyClaimStatsResponse{
-		TotalClaims: 150,
-		ClaimsByStatus: map[string]int{
-			"pending":     25,
-			"validated":   30,
-			"in_progress": 40,
-			"completed":   45,
-			"rejected":    10,
-		},
-		ClaimsBySeverity: map[string]int{
-			"low":      50,
-			"medium":   70,
-			"high":     25,
-			"critical": 5,
-		},
-		ClaimsByCategory: map[string]int{
-			"hardware":    60,
-			"software":    30,
-			"performance": 25,
-			"defect":      20,
-			"damage":      10,
-			"other":       5,
-		},
-		ClaimsByPriority: map[string]int{
-			"low":    60,
-			"medium": 70,
-			"high":   20,
-		},
-		ClaimsThisMonth: 25,
-		ClaimsLastMonth: 20,
+	var startDate, endDate *time.Time
+	if v := c.Query("date_from"); v != "" {
+		date, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, errInvalidQuery("date_from").Error(), nil)
+			return
+		}
+		startDate = &date
+	}
+	if v := c.Query("date_to"); v != "" {
+		date, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, errInvalidQuery("date_to").Error(), nil)
+			return
+		}
+		// Include the whole day
+		date = date.Add(24*time.Hour - time.Nanosecond)
+		endDate = &date
 	}
 
-	h.logger.Info("Successfully retrieved warranty claim statistics (mock)",
-		"filters", map[string]string{
-			"date_from":     dateFrom,
-			"date_to":       dateTo,
-			"storefront_id": storefrontID,
-			"product_id":    productID,
-		},
-		"user_id", userID,
-	)
+	stats, err := h.claimUseCase.GetClaimStatistics(c.Request.Context(), storefrontID, startDate, endDate)
+	if err != nil {
+		h.handleError(c, err, "Failed to get warranty claim statistics", slog.String("storefront_id", storefrontID.String()))
+		return
+	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Warranty claim statistics retrieved successfully", mockStats)
+	utils.SuccessResponse(c, http.StatusOK, "Warranty claim statistics retrieved successfully", stats)
 }
 
 // AddClaimNotes handles adding notes to claims
@@ -722,4 +597,141 @@ func (h *WarrantyClaimHandler) BulkUpdateClaimStatus(c *gin.Context) {
 	)
 
 	utils.SuccessResponse(c, http.StatusOK, "Claim statuses updated successfully", batchResponse)
-}
\ No newline at end of file
+}
+// handleError maps warranty claim use case errors to HTTP responses
+func (h *WarrantyClaimHandler) handleError(c *gin.Context, err error, message string, attrs ...any) {
+	status := warrantyClaimErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(message, append(attrs, slog.String("error", err.Error()))...)
+		utils.ErrorResponse(c, status, message, nil)
+		return
+	}
+
+	utils.ErrorResponse(c, status, err.Error(), nil)
+}
+
+// warrantyClaimSortColumns lists the columns claims can be sorted by
+var warrantyClaimSortColumns = map[string]bool{
+	"created_at": true,
+	"claim_date": true,
+	"status":     true,
+	"severity":   true,
+	"priority":   true,
+	"sla_due_at": true,
+}
+
+// parseWarrantyClaimFilters reads warranty claim filter and sorting query parameters
+func parseWarrantyClaimFilters(c *gin.Context) (*repository.WarrantyClaimFilters, error) {
+	filters := &repository.WarrantyClaimFilters{}
+
+	if v := c.Query("status"); v != "" {
+		status := entity.ClaimStatus(v)
+		if !status.Valid() {
+			return nil, errInvalidQuery("status")
+		}
+		filters.Status = &status
+	}
+
+	if v := c.Query("severity"); v != "" {
+		severity := entity.ClaimSeverity(v)
+		if !severity.Valid() {
+			return nil, errInvalidQuery("severity")
+		}
+		filters.Severity = &severity
+	}
+
+	if v := c.Query("priority"); v != "" {
+		priority := entity.ClaimPriority(v)
+		if !priority.Valid() {
+			return nil, errInvalidQuery("priority")
+		}
+		filters.Priority = &priority
+	}
+
+	if v := c.Query("category"); v != "" {
+		filters.IssueCategory = &v
+	}
+
+	if v := c.Query("sla_state"); v != "" {
+		state := entity.ClaimSLAState(v)
+		if !state.Valid() {
+			return nil, errInvalidQuery("sla_state")
+		}
+		filters.SLAState = &state
+	}
+
+	if v := c.Query("customer_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, errInvalidQuery("customer_id")
+		}
+		filters.CustomerID = &id
+	}
+
+	if v := c.Query("product_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, errInvalidQuery("product_id")
+		}
+		filters.ProductID = &id
+	}
+
+	if v := c.Query("technician_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, errInvalidQuery("technician_id")
+		}
+		filters.AssignedTechnicianID = &id
+	}
+
+	if v := c.Query("date_from"); v != "" {
+		date, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return nil, errInvalidQuery("date_from")
+		}
+		filters.CreatedAfter = &date
+	}
+
+	if v := c.Query("date_to"); v != "" {
+		date, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return nil, errInvalidQuery("date_to")
+		}
+		date = date.Add(24*time.Hour - time.Nanosecond)
+		filters.CreatedBefore = &date
+	}
+
+	if v := c.Query("search"); v != "" {
+		filters.Search = &v
+	}
+
+	filters.SortBy = c.DefaultQuery("sort_by", "created_at")
+	if !warrantyClaimSortColumns[filters.SortBy] {
+		return nil, errInvalidQuery("sort_by")
+	}
+	filters.SortDirection = strings.ToLower(c.DefaultQuery("sort_dir", "desc"))
+	if filters.SortDirection != "asc" && filters.SortDirection != "desc" {
+		return nil, errInvalidQuery("sort_dir")
+	}
+
+	return filters, nil
+}
+
+// warrantyClaimErrorStatus maps warranty claim use case errors to HTTP status codes
+func warrantyClaimErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "failed to"):
+		return http.StatusInternalServerError
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "already exists"):
+		return http.StatusConflict
+	case strings.Contains(msg, "invalid"),
+		strings.Contains(msg, "required"),
+		strings.Contains(msg, "must"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
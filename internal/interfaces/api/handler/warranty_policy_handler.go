@@ -0,0 +1,378 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
+	"github.com/kirimku/smartseller-backend/pkg/utils"
+)
+
+// WarrantyPolicyHandler handles warranty policy template requests
+type WarrantyPolicyHandler struct {
+	policyUseCase  usecase.WarrantyPolicyUseCase
+	storefrontRepo repository.StorefrontRepository
+	logger         *slog.Logger
+}
+
+// NewWarrantyPolicyHandler creates a new warranty policy handler
+func NewWarrantyPolicyHandler(policyUseCase usecase.WarrantyPolicyUseCase, storefrontRepo repository.StorefrontRepository, logger *slog.Logger) *WarrantyPolicyHandler {
+	return &WarrantyPolicyHandler{
+		policyUseCase:  policyUseCase,
+		storefrontRepo: storefrontRepo,
+		logger:         logger,
+	}
+}
+
+// CreatePolicy handles creating a warranty policy
+// @Summary Create warranty policy
+// @Description Create a warranty policy for a product, a product category or as the storefront default
+// @Tags Warranty Policies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateWarrantyPolicyRequest true "Warranty policy"
+// @Success 201 {object} dto.WarrantyPolicyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/policies [post]
+func (h *WarrantyPolicyHandler) CreatePolicy(c *gin.Context) {
+	userID, storefrontID, ok := h.sellerStorefront(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateWarrantyPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	result, err := h.policyUseCase.CreatePolicy(c.Request.Context(), storefrontID, userID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create warranty policy", slog.String("storefront_id", storefrontID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Warranty policy created successfully", result)
+}
+
+// ListPolicies handles listing warranty policies
+// @Summary List warranty policies
+// @Description List the warranty policies of the seller's storefront, latest effective first
+// @Tags Warranty Policies
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page" default(20)
+// @Param product_id query string false "Filter by product ID"
+// @Param product_category_id query string false "Filter by product category ID"
+// @Param is_default query bool false "Filter storefront default policies"
+// @Param is_active query bool false "Filter by active flag"
+// @Param coverage_type query string false "Filter by coverage type" Enums(manufacturer_defect, full_coverage, limited_warranty, extended_warranty)
+// @Success 200 {object} dto.WarrantyPolicyListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/policies [get]
+func (h *WarrantyPolicyHandler) ListPolicies(c *gin.Context) {
+	_, storefrontID, ok := h.sellerStorefront(c)
+	if !ok {
+		return
+	}
+
+	filters, err := parseWarrantyPolicyFilters(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	filters.StorefrontID = storefrontID
+	filters.Page, filters.PageSize = parseOrderPagination(c)
+
+	result, err := h.policyUseCase.ListPolicies(c.Request.Context(), filters)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve warranty policies", slog.String("storefront_id", storefrontID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Warranty policies retrieved successfully", result)
+}
+
+// GetPolicy handles retrieving a warranty policy
+// @Summary Get warranty policy
+// @Description Get a warranty policy of the seller's storefront
+// @Tags Warranty Policies
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Warranty policy ID"
+// @Success 200 {object} dto.WarrantyPolicyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/policies/{id} [get]
+func (h *WarrantyPolicyHandler) GetPolicy(c *gin.Context) {
+	_, storefrontID, ok := h.sellerStorefront(c)
+	if !ok {
+		return
+	}
+
+	policyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid warranty policy ID format", err)
+		return
+	}
+
+	result, err := h.policyUseCase.GetPolicy(c.Request.Context(), storefrontID, policyID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve warranty policy", slog.String("policy_id", policyID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Warranty policy retrieved successfully", result)
+}
+
+// UpdatePolicy handles updating a warranty policy
+// @Summary Update warranty policy
+// @Description Update the terms, claim rules, status or effective dates of a warranty policy. The products it applies to cannot be changed.
+// @Tags Warranty Policies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Warranty policy ID"
+// @Param request body dto.UpdateWarrantyPolicyRequest true "Fields to update"
+// @Success 200 {object} dto.WarrantyPolicyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/policies/{id} [put]
+func (h *WarrantyPolicyHandler) UpdatePolicy(c *gin.Context) {
+	_, storefrontID, ok := h.sellerStorefront(c)
+	if !ok {
+		return
+	}
+
+	policyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid warranty policy ID format", err)
+		return
+	}
+
+	var req dto.UpdateWarrantyPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request format", err)
+		return
+	}
+
+	result, err := h.policyUseCase.UpdatePolicy(c.Request.Context(), storefrontID, policyID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update warranty policy", slog.String("policy_id", policyID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Warranty policy updated successfully", result)
+}
+
+// DeletePolicy handles deleting a warranty policy
+// @Summary Delete warranty policy
+// @Description Delete a warranty policy. Barcodes already generated keep their warranty period.
+// @Tags Warranty Policies
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Warranty policy ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/policies/{id} [delete]
+func (h *WarrantyPolicyHandler) DeletePolicy(c *gin.Context) {
+	_, storefrontID, ok := h.sellerStorefront(c)
+	if !ok {
+		return
+	}
+
+	policyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid warranty policy ID format", err)
+		return
+	}
+
+	if err := h.policyUseCase.DeletePolicy(c.Request.Context(), storefrontID, policyID); err != nil {
+		h.handleError(c, err, "Failed to delete warranty policy", slog.String("policy_id", policyID.String()))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Warranty policy deleted successfully", nil)
+}
+
+// ResolvePolicy handles previewing which policy applies to a product
+// @Summary Resolve warranty policy
+// @Description Show the policy that applies to a product on a given day: a product policy, then a category policy, then the storefront default
+// @Tags Warranty Policies
+// @Produce json
+// @Security BearerAuth
+// @Param product_id query string true "Product ID"
+// @Param date query string false "Day to resolve the policy for (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} dto.WarrantyPolicyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/warranty/policies/resolve [get]
+func (h *WarrantyPolicyHandler) ResolvePolicy(c *gin.Context) {
+	_, storefrontID, ok := h.sellerStorefront(c)
+	if !ok {
+		return
+	}
+
+	productID, err := uuid.Parse(c.Query("product_id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, errInvalidQuery("product_id").Error(), nil)
+		return
+	}
+
+	on := time.Now()
+	if v := c.Query("date"); v != "" {
+		on, err = time.Parse("2006-01-02", v)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, errInvalidQuery("date").Error(), nil)
+			return
+		}
+	}
+
+	policy, err := h.policyUseCase.ResolvePolicy(c.Request.Context(), storefrontID, productID, on)
+	if err != nil {
+		h.handleError(c, err, "Failed to resolve warranty policy", slog.String("product_id", productID.String()))
+		return
+	}
+	if policy == nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "No warranty policy applies to this product", nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Warranty policy resolved successfully", dto.ConvertWarrantyPolicyToResponse(policy))
+}
+
+// sellerStorefront returns the authenticated seller and the storefront whose
+// policies are managed, writing an error response when either is missing
+func (h *WarrantyPolicyHandler) sellerStorefront(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	return resolveSellerStorefront(c, h.storefrontRepo, h.logger)
+}
+
+// resolveSellerStorefront resolves the authenticated seller and the storefront
+// from the tenant context, falling back to the seller's first storefront. It
+// writes the error response when either cannot be determined.
+func resolveSellerStorefront(c *gin.Context, storefrontRepo repository.StorefrontRepository, logger *slog.Logger) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := uuid.Parse(utils.GetUserIDFromContext(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Authentication required", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if storefrontID, exists := middleware.GetStorefrontID(c); exists {
+		return userID, storefrontID, true
+	}
+
+	storefronts, err := storefrontRepo.GetBySellerID(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error("Failed to get seller storefront", slog.String("error", err.Error()), slog.String("user_id", userID.String()))
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Unable to determine user's storefront", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	if len(storefronts) == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "No storefront associated with user", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, storefronts[0].ID, true
+}
+
+// handleError maps warranty policy use case errors to HTTP responses
+func (h *WarrantyPolicyHandler) handleError(c *gin.Context, err error, message string, attrs ...any) {
+	status := warrantyPolicyErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(message, append(attrs, slog.String("error", err.Error()))...)
+		utils.ErrorResponse(c, status, message, nil)
+		return
+	}
+
+	utils.ErrorResponse(c, status, err.Error(), nil)
+}
+
+// parseWarrantyPolicyFilters reads warranty policy filter query parameters
+func parseWarrantyPolicyFilters(c *gin.Context) (*repository.WarrantyPolicyFilters, error) {
+	filters := &repository.WarrantyPolicyFilters{}
+
+	if v := c.Query("product_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, errInvalidQuery("product_id")
+		}
+		filters.ProductID = &id
+	}
+
+	if v := c.Query("product_category_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, errInvalidQuery("product_category_id")
+		}
+		filters.ProductCategoryID = &id
+	}
+
+	if v := c.Query("is_default"); v != "" {
+		isDefault, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errInvalidQuery("is_default")
+		}
+		filters.IsDefault = &isDefault
+	}
+
+	if v := c.Query("is_active"); v != "" {
+		isActive, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errInvalidQuery("is_active")
+		}
+		filters.IsActive = &isActive
+	}
+
+	if v := c.Query("coverage_type"); v != "" {
+		coverageType := entity.CoverageType(v)
+		if !coverageType.Valid() {
+			return nil, errInvalidQuery("coverage_type")
+		}
+		filters.CoverageType = &coverageType
+	}
+
+	return filters, nil
+}
+
+// warrantyPolicyErrorStatus maps warranty policy use case errors to HTTP status codes
+func warrantyPolicyErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "failed to"):
+		return http.StatusInternalServerError
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "invalid"),
+		strings.Contains(msg, "required"),
+		strings.Contains(msg, "must"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -2,26 +2,67 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
 )
 
 // CustomerTrackingHandler handles customer claim tracking operations
 type CustomerTrackingHandler struct {
 	// TODO: Add dependencies like database, services, etc.
-	wsHandler *WebSocketHandler
+	wsHandler       *WebSocketHandler
+	trackingUseCase usecase.ShipmentTrackingUseCase
 }
 
 // NewCustomerTrackingHandler creates a new customer tracking handler
-func NewCustomerTrackingHandler(wsHandler *WebSocketHandler) *CustomerTrackingHandler {
+func NewCustomerTrackingHandler(wsHandler *WebSocketHandler, trackingUseCase usecase.ShipmentTrackingUseCase) *CustomerTrackingHandler {
 	return &CustomerTrackingHandler{
-		wsHandler: wsHandler,
+		wsHandler:       wsHandler,
+		trackingUseCase: trackingUseCase,
 		// TODO: Initialize other dependencies
 	}
 }
 
+// GetClaimShipment retrieves the courier tracking timeline of a claim's return shipment
+func (h *CustomerTrackingHandler) GetClaimShipment(c *gin.Context) {
+	claimID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid claim ID",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	customerIDStr, ok := middleware.GetCustomerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Customer authentication required"})
+		return
+	}
+	customerID, err := uuid.Parse(customerIDStr)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid customer ID"})
+		return
+	}
+
+	tracking, err := h.trackingUseCase.GetClaimTracking(c.Request.Context(), customerID, claimID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get shipment tracking"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tracking)
+}
+
 // GetClaimStatus retrieves current status and progress of a claim
 func (h *CustomerTrackingHandler) GetClaimStatus(c *gin.Context) {
 	claimID := c.Param("id")
@@ -1,117 +1,118 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
 	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
 	"github.com/shopspring/decimal"
 )
 
 // CustomerWarrantyHandler handles customer warranty operations
 type CustomerWarrantyHandler struct {
-	// TODO: Add warranty usecase dependency
-	// warrantyUsecase warranty.WarrantyUsecase
+	registrationUseCase usecase.WarrantyRegistrationUseCase
+	logger              *slog.Logger
 }
 
 // NewCustomerWarrantyHandler creates a new customer warranty handler
-func NewCustomerWarrantyHandler() *CustomerWarrantyHandler {
-	return &CustomerWarrantyHandler{}
+func NewCustomerWarrantyHandler(registrationUseCase usecase.WarrantyRegistrationUseCase, logger *slog.Logger) *CustomerWarrantyHandler {
+	return &CustomerWarrantyHandler{
+		registrationUseCase: registrationUseCase,
+		logger:              logger,
+	}
 }
 
 // RegisterWarranty handles customer warranty registration
+// @Summary Register a warranty
+// @Description Register the warranty of a product by activating its distributed barcode. Send JSON, or multipart form data with the registration JSON in the registration field and the receipt (JPEG, PNG, WebP or PDF, at most 10 MB) in the proof_of_purchase field. The receipt is required when the product's warranty policy asks for one. Registration closes a configured time after the barcode was distributed. The warranty is registered to the signed-in customer, who is emailed the warranty certificate.
+// @Tags Customer Warranty
+// @Accept json,mpfd
+// @Produce json
+// @Security BearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param request body dto.CustomerWarrantyRegistrationRequest false "Warranty registration"
+// @Param registration formData string false "Warranty registration JSON"
+// @Param proof_of_purchase formData file false "Proof of purchase"
+// @Success 201 {object} dto.CustomerWarrantyRegistrationResponse
+// @Failure 400 {object} dto.CustomerWarrantyErrorResponse
+// @Failure 401 {object} dto.CustomerWarrantyErrorResponse
+// @Failure 403 {object} dto.CustomerWarrantyErrorResponse
+// @Failure 404 {object} dto.CustomerWarrantyErrorResponse
+// @Failure 409 {object} dto.CustomerWarrantyErrorResponse
+// @Failure 413 {object} dto.CustomerWarrantyErrorResponse
+// @Failure 500 {object} dto.CustomerWarrantyErrorResponse
+// @Router /api/v1/storefront/{slug}/warranties/register [post]
 func (h *CustomerWarrantyHandler) RegisterWarranty(c *gin.Context) {
 	var req dto.CustomerWarrantyRegistrationRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.CustomerWarrantyErrorResponse{
-			Error:     "invalid_request",
-			Message:   "Invalid request format",
-			Details:   err.Error(),
-			Timestamp: time.Now(),
-		})
-		return
-	}
-
-	// TODO: Implement actual warranty registration logic
-	// For now, return mock response
-
-	// Mock customer info
-	customerInfo := dto.CustomerRegistrationInfo{
-		FirstName:   req.CustomerInfo.FirstName,
-		LastName:    req.CustomerInfo.LastName,
-		Email:       req.CustomerInfo.Email,
-		PhoneNumber: req.CustomerInfo.PhoneNumber,
-	}
+	var receipt *usecase.WarrantyReceiptUpload
+
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		// Leave room for the registration JSON next to the receipt
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, usecase.MaxWarrantyReceiptSize+1<<20)
+		if err := c.Request.ParseMultipartForm(1 << 20); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				h.respondError(c, http.StatusRequestEntityTooLarge, "file_too_large", "Proof of purchase is too large", "")
+				return
+			}
+			h.respondError(c, http.StatusBadRequest, "invalid_request", "Invalid request format", err.Error())
+			return
+		}
+		if err := binding.JSON.BindBody([]byte(c.Request.FormValue("registration")), &req); err != nil {
+			h.respondError(c, http.StatusBadRequest, "invalid_request", "Invalid request format", err.Error())
+			return
+		}
 
-	// Mock product
-	productInfo := dto.CustomerProductInfo{
-		ID:          uuid.New(),
-		SKU:         req.ProductSKU,
-		Name:        "Premium Smartphone X1",
-		Brand:       "TechBrand",
-		Category:    "Electronics",
-		Description: "Latest flagship smartphone with advanced features",
-		ImageURL:    "https://example.com/images/smartphone-x1.jpg",
-		Price:       func() *decimal.Decimal { p := decimal.NewFromFloat(999.99); return &p }(),
+		file, header, err := c.Request.FormFile("proof_of_purchase")
+		if err == nil {
+			defer file.Close()
+			receipt = &usecase.WarrantyReceiptUpload{
+				Filename: header.Filename,
+				Size:     header.Size,
+				Content:  file,
+			}
+		} else if !errors.Is(err, http.ErrMissingFile) {
+			h.respondError(c, http.StatusBadRequest, "invalid_request", "Invalid proof of purchase", err.Error())
+			return
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "invalid_request", "Invalid request format", err.Error())
+		return
 	}
 
-	// Mock customer
-	customer := dto.CustomerInfo{
-		ID:          uuid.New(),
-		FirstName:   customerInfo.FirstName,
-		LastName:    customerInfo.LastName,
-		Email:       customerInfo.Email,
-		PhoneNumber: customerInfo.PhoneNumber,
+	claims, ok := middleware.GetCustomerClaims(c)
+	if !ok {
+		h.respondError(c, http.StatusUnauthorized, "unauthorized", "Customer authentication required", "")
+		return
 	}
-
-	// Mock coverage
-	coverage := dto.CustomerWarrantyCoverage{
-		CoverageType:        "comprehensive",
-		CoveredComponents:   []string{"hardware", "software", "battery", "screen"},
-		ExcludedComponents:  []string{"water_damage", "physical_abuse", "normal_wear"},
-		RepairCoverage:      true,
-		ReplacementCoverage: true,
-		LaborCoverage:       true,
-		PartsCoverage:       true,
-		Terms: []string{
-			"Must provide proof of purchase",
-			"Damage must be reported within 30 days",
-			"Warranty void if tampered with",
-		},
-		Limitations: []string{
-			"Does not cover accidental damage",
-			"Limited to original purchaser",
-		},
+	customerID, err := uuid.Parse(claims.CustomerID)
+	if err != nil {
+		h.respondError(c, http.StatusUnauthorized, "unauthorized", "Invalid customer ID", "")
+		return
 	}
-
-	// Mock next steps
-	nextSteps := []string{
-		"Keep your warranty registration confirmation safe",
-		"Register for online warranty portal access",
-		"Download the mobile app for easy claim submission",
-		"Contact support if you have any questions",
+	storefrontID, err := uuid.Parse(claims.StorefrontID)
+	if err != nil {
+		h.respondError(c, http.StatusUnauthorized, "unauthorized", "Invalid storefront ID", "")
+		return
 	}
 
-	response := dto.CustomerWarrantyRegistrationResponse{
-		Success:          true,
-		RegistrationID:   uuid.New(),
-		WarrantyID:       uuid.New(),
-		BarcodeValue:     req.BarcodeValue,
-		Status:           "active",
-		ActivationDate:   time.Now(),
-		ExpiryDate:       time.Now().AddDate(2, 0, 0), // 2 years from now
-		WarrantyPeriod:   "2 years",
-		Product:          productInfo,
-		Customer:         customer,
-		Coverage:         coverage,
-		NextSteps:        nextSteps,
-		RegistrationTime: time.Now(),
+	response, err := h.registrationUseCase.RegisterWarranty(c.Request.Context(), storefrontID, customerID, &req, receipt)
+	if err != nil {
+		h.handleError(c, err, "Failed to register warranty")
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusCreated, response)
 }
 
 // GetWarranties handles listing customer warranties
@@ -361,4 +362,45 @@ func (h *CustomerWarrantyHandler) UpdateWarranty(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+// handleError maps warranty registration errors to HTTP responses. Not found
+// responses drive the enumeration guard in front of these routes.
+func (h *CustomerWarrantyHandler) handleError(c *gin.Context, err error, message string) {
+	status, code := customerWarrantyErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(message, slog.String("error", err.Error()))
+		h.respondError(c, status, code, message, "")
+		return
+	}
+	h.respondError(c, status, code, err.Error(), "")
+}
+
+func (h *CustomerWarrantyHandler) respondError(c *gin.Context, status int, code, message, details string) {
+	c.JSON(status, dto.CustomerWarrantyErrorResponse{
+		Error:     code,
+		Message:   message,
+		Code:      fmt.Sprintf("CWR_%d", status),
+		Details:   details,
+		Timestamp: time.Now(),
+		RequestID: c.GetString("request_id"),
+	})
+}
+
+// customerWarrantyErrorStatus maps a use case error message to its HTTP status
+// and error code
+func customerWarrantyErrorStatus(err error) (int, string) {
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "failed to"):
+		return http.StatusInternalServerError, "internal_error"
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound, "warranty_not_found"
+	case strings.Contains(msg, "already exists"):
+		return http.StatusConflict, "already_registered"
+	case strings.Contains(msg, "invalid"), strings.Contains(msg, "required"), strings.Contains(msg, "must"):
+		return http.StatusBadRequest, "invalid_request"
+	default:
+		return http.StatusInternalServerError, "internal_error"
+	}
+}
@@ -3,27 +3,48 @@ package handlers
 import (
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
 )
 
 // MobileWarrantyHandler handles mobile-specific warranty operations
 type MobileWarrantyHandler struct {
-	// TODO: Add dependencies like database, services, etc.
+	registrationUseCase usecase.WarrantyRegistrationUseCase
+	logger              *slog.Logger
 }
 
 // NewMobileWarrantyHandler creates a new mobile warranty handler
-func NewMobileWarrantyHandler() *MobileWarrantyHandler {
+func NewMobileWarrantyHandler(registrationUseCase usecase.WarrantyRegistrationUseCase, logger *slog.Logger) *MobileWarrantyHandler {
 	return &MobileWarrantyHandler{
-		// TODO: Initialize dependencies
+		registrationUseCase: registrationUseCase,
+		logger:              logger,
 	}
 }
 
 // ScanWarranty handles QR/barcode scanning for warranty lookup
+// @Summary Scan a warranty barcode
+// @Description Look up a scanned warranty barcode or QR code of the customer's storefront. Unregistered barcodes offer registration while the registration window is open; warranties registered to other customers are not found.
+// @Tags Mobile Warranty
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param slug path string true "Storefront slug"
+// @Param request body dto.MobileWarrantyScanRequest true "Scan"
+// @Success 200 {object} dto.MobileWarrantyScanResponse
+// @Failure 400 {object} dto.CustomerWarrantyErrorResponse
+// @Failure 401 {object} dto.CustomerWarrantyErrorResponse
+// @Failure 403 {object} dto.CustomerWarrantyErrorResponse
+// @Failure 404 {object} dto.CustomerWarrantyErrorResponse
+// @Failure 500 {object} dto.CustomerWarrantyErrorResponse
+// @Router /api/v1/storefront/{slug}/mobile/warranties/scan [post]
 func (h *MobileWarrantyHandler) ScanWarranty(c *gin.Context) {
 	var request dto.MobileWarrantyScanRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -34,54 +55,38 @@ func (h *MobileWarrantyHandler) ScanWarranty(c *gin.Context) {
 		return
 	}
 
-	// TODO: Validate scan data format
-	// TODO: Lookup warranty in database
-	// TODO: Check warranty validity and status
-	
-	// Mock response for now
-	response := dto.MobileWarrantyScanResponse{
-		Success:     true,
-		ScanType:    request.ScanType,
-		ScannedData: request.ScannedData,
-		Warranty: &dto.MobileWarrantyInfo{
-			ID:              "warranty-12345",
-			ProductName:     "SmartPhone Pro Max",
-			ProductModel:    "SPM-2024",
-			SerialNumber:    request.ScannedData,
-			PurchaseDate:    time.Now().AddDate(-1, 0, 0),
-			ExpiryDate:      time.Now().AddDate(1, 0, 0),
-			Status:          "active",
-			CoverageType:    "comprehensive",
-			IsValid:         true,
-			DaysRemaining:   365,
-			Retailer:        "TechStore Plus",
-			WarrantyTerms:   "2-year comprehensive warranty covering manufacturing defects",
-		},
-		Actions: []dto.MobileWarrantyAction{
-			{
-				Type:        "view_details",
-				Label:       "View Full Details",
-				Description: "See complete warranty information",
-				Enabled:     true,
-			},
-			{
-				Type:        "file_claim",
-				Label:       "File Warranty Claim",
-				Description: "Report an issue with your product",
-				Enabled:     true,
-			},
-			{
-				Type:        "contact_support",
-				Label:       "Contact Support",
-				Description: "Get help from our support team",
-				Enabled:     true,
-			},
-		},
-		Metadata: map[string]interface{}{
-			"scan_timestamp": time.Now(),
-			"scan_location":  "mobile_app",
-			"app_version":    c.GetHeader("App-Version"),
-		},
+	claims, ok := middleware.GetCustomerClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Customer authentication required"})
+		return
+	}
+	customerID, err := uuid.Parse(claims.CustomerID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid customer ID"})
+		return
+	}
+	storefrontID, err := uuid.Parse(claims.StorefrontID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid storefront ID"})
+		return
+	}
+
+	response, err := h.registrationUseCase.ScanWarranty(c.Request.Context(), storefrontID, customerID, &request)
+	if err != nil {
+		status, code := customerWarrantyErrorStatus(err)
+		message := err.Error()
+		if status == http.StatusInternalServerError {
+			h.logger.Error("Failed to scan warranty", slog.String("customer_id", customerID.String()), slog.String("error", message))
+			message = "Failed to scan warranty"
+		}
+		c.JSON(status, dto.CustomerWarrantyErrorResponse{
+			Error:     code,
+			Message:   message,
+			Code:      fmt.Sprintf("CWR_%d", status),
+			Timestamp: time.Now(),
+			RequestID: c.GetString("request_id"),
+		})
+		return
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -102,16 +103,27 @@ func (atm *AdminTenantMiddleware) CheckMigrationEligibility() gin.HandlerFunc {
 			return
 		}
 
+		currentType, err := atm.tenantResolver.GetTenantType(c.Request.Context(), storefrontID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "migration_check_failed",
+				"message": "Failed to resolve current tenant type",
+				"details": err.Error(),
+			})
+			return
+		}
+
 		c.JSON(http.StatusOK, gin.H{
 			"storefront_id": storefrontID,
 			"can_migrate":   canMigrate,
 			"target_type":   targetType,
-			"current_type":  "", // Would need to get current type
+			"current_type":  currentType,
 		})
 	}
 }
 
-// MigrateTenant initiates tenant migration
+// MigrateTenant starts a tenant migration job. The job runs in the background;
+// its progress is available from GetMigration.
 func (atm *AdminTenantMiddleware) MigrateTenant() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		storefrontIDParam := c.Param("storefront_id")
@@ -171,10 +183,10 @@ func (atm *AdminTenantMiddleware) MigrateTenant() gin.HandlerFunc {
 			}
 		}
 
-		// Perform migration
-		err = atm.tenantResolver.MigrateTenant(c.Request.Context(), storefrontID, req.TargetType)
+		// Start migration
+		migration, err := atm.tenantResolver.MigrateTenant(c.Request.Context(), storefrontID, req.TargetType)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
+			c.JSON(migrationErrorStatus(err), gin.H{
 				"error":   "migration_failed",
 				"message": "Failed to migrate tenant",
 				"details": err.Error(),
@@ -182,15 +194,141 @@ func (atm *AdminTenantMiddleware) MigrateTenant() gin.HandlerFunc {
 			return
 		}
 
+		c.JSON(http.StatusAccepted, gin.H{
+			"status":    "migration_initiated",
+			"message":   "Tenant migration has been initiated successfully",
+			"migration": migrationResponse(migration),
+		})
+	}
+}
+
+// GetLatestMigration returns the most recent migration job of a tenant
+func (atm *AdminTenantMiddleware) GetLatestMigration() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		storefrontIDParam := c.Param("storefront_id")
+		storefrontID, err := uuid.Parse(storefrontIDParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid_storefront_id",
+				"message": "Invalid storefront ID format",
+			})
+			return
+		}
+
+		migration, err := atm.tenantResolver.GetLatestTenantMigration(c.Request.Context(), storefrontID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "migration_fetch_failed",
+				"message": "Failed to fetch tenant migration",
+				"details": err.Error(),
+			})
+			return
+		}
+		if migration == nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "migration_not_found",
+				"message": "Tenant has no migrations",
+			})
+			return
+		}
+
 		c.JSON(http.StatusOK, gin.H{
-			"storefront_id": storefrontID,
-			"target_type":   req.TargetType,
-			"status":        "migration_initiated",
-			"message":       "Tenant migration has been initiated successfully",
+			"migration": migrationResponse(migration),
 		})
 	}
 }
 
+// GetMigration returns the progress of a migration job
+func (atm *AdminTenantMiddleware) GetMigration() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		migrationID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid_migration_id",
+				"message": "Invalid migration ID format",
+			})
+			return
+		}
+
+		migration, err := atm.tenantResolver.GetTenantMigration(c.Request.Context(), migrationID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "migration_fetch_failed",
+				"message": "Failed to fetch tenant migration",
+				"details": err.Error(),
+			})
+			return
+		}
+		if migration == nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "migration_not_found",
+				"message": "Tenant migration not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"migration": migrationResponse(migration),
+		})
+	}
+}
+
+// RollbackMigration moves a tenant back to the source of a migration and drops its target
+func (atm *AdminTenantMiddleware) RollbackMigration() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		migrationID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid_migration_id",
+				"message": "Invalid migration ID format",
+			})
+			return
+		}
+
+		migration, err := atm.tenantResolver.RollbackTenantMigration(c.Request.Context(), migrationID)
+		if err != nil {
+			c.JSON(migrationErrorStatus(err), gin.H{
+				"error":   "rollback_failed",
+				"message": "Failed to roll back tenant migration",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status":    "migration_rolled_back",
+			"message":   "Tenant migration has been rolled back successfully",
+			"migration": migrationResponse(migration),
+		})
+	}
+}
+
+// migrationResponse adds the overall progress to a migration job
+func migrationResponse(migration *tenant.TenantMigration) gin.H {
+	return gin.H{
+		"job":              migration,
+		"progress_percent": migration.ProgressPercent(),
+	}
+}
+
+// migrationErrorStatus maps tenant migration errors to HTTP status codes
+func migrationErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "already running"),
+		strings.Contains(message, "already being rolled back"),
+		strings.Contains(message, "cannot be rolled back"),
+		strings.Contains(message, "only the latest"):
+		return http.StatusConflict
+	case strings.Contains(message, "cannot migrate"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // InvalidateCache invalidates tenant cache
 func (atm *AdminTenantMiddleware) InvalidateCache() gin.HandlerFunc {
 	return func(c *gin.Context) {
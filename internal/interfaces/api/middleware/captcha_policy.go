@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/pkg/captcha"
+)
+
+// CaptchaPolicy decides which CAPTCHA provider, secret and thresholds apply to
+// a storefront. Storefronts configure their own site secret in
+// storefront_configs; the platform defaults fill in whatever they leave out.
+type CaptchaPolicy struct {
+	configs  *storefrontConfigCache
+	defaults captcha.Config
+	options  []captcha.Option
+}
+
+// NewCaptchaPolicy creates a CAPTCHA policy. configRepo may be nil, in which
+// case every storefront uses the defaults.
+func NewCaptchaPolicy(configRepo repository.StorefrontConfigRepository, defaults captcha.Config, opts ...captcha.Option) *CaptchaPolicy {
+	if defaults.Mode == "" {
+		defaults.Mode = captcha.ModeAdaptive
+	}
+
+	return &CaptchaPolicy{
+		configs:  newStorefrontConfigCache(configRepo),
+		defaults: defaults,
+		options:  opts,
+	}
+}
+
+// ConfigFor returns the CAPTCHA settings of a storefront
+func (p *CaptchaPolicy) ConfigFor(ctx context.Context, storefrontID uuid.UUID) (captcha.Config, error) {
+	// Fields the storefront sets override the defaults
+	config := p.defaults
+	if err := p.configs.Decode(ctx, storefrontID, repository.StorefrontConfigCaptcha, &config); err != nil {
+		return captcha.Config{}, fmt.Errorf("invalid captcha config for storefront %s: %w", storefrontID, err)
+	}
+	return config, nil
+}
+
+// Verify checks a token against the config's provider and thresholds. An
+// error means the provider could not be reached.
+func (p *CaptchaPolicy) Verify(ctx context.Context, config captcha.Config, token, remoteIP string) (bool, error) {
+	verifier, err := captcha.NewVerifier(config, p.options...)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := verifier.Verify(ctx, token, remoteIP)
+	if err != nil {
+		return false, err
+	}
+
+	return result.Passes(config), nil
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// CartSessionHeader carries the anonymous cart session of a guest
+	CartSessionHeader = "X-Cart-Session"
+	// CartSessionCookie mirrors the cart session for browser clients
+	CartSessionCookie = "cart_session"
+	cartSessionMaxAge = 86400 * 30
+)
+
+var (
+	cartSessionKeyOnce sync.Once
+	cartSessionKey     []byte
+)
+
+// getCartSessionKey returns the key cart sessions are signed with
+func getCartSessionKey() []byte {
+	cartSessionKeyOnce.Do(func() {
+		secretKey := os.Getenv("CART_SESSION_SECRET")
+		if secretKey == "" {
+			secretKey = os.Getenv("SESSION_KEY") // Fallback to existing key
+			if secretKey == "" {
+				secretKey = "cart-default-secret-key-for-development-only"
+			}
+		}
+		cartSessionKey = []byte(secretKey)
+	})
+	return cartSessionKey
+}
+
+// signCartSession returns the signature the server issues a cart session with
+func signCartSession(sessionID string) string {
+	mac := hmac.New(sha256.New, getCartSessionKey())
+	mac.Write([]byte(sessionID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// GetCartSessionID returns the guest cart session from the request header or
+// cookie. Sessions are handed out signed by SetCartSessionID, so a value the
+// server did not issue is ignored rather than letting clients choose, or
+// guess, the cart key of another guest.
+func GetCartSessionID(c *gin.Context) string {
+	token := strings.TrimSpace(c.GetHeader(CartSessionHeader))
+	if token == "" {
+		if cookie, err := c.Cookie(CartSessionCookie); err == nil {
+			token = strings.TrimSpace(cookie)
+		}
+	}
+
+	sessionID, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(signCartSession(sessionID))) {
+		return ""
+	}
+
+	id, err := uuid.Parse(sessionID)
+	if err != nil || id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
+
+// SetCartSessionID issues the guest cart session to the client, signed, in
+// both the response header and an HttpOnly cookie
+func SetCartSessionID(c *gin.Context, sessionID string) {
+	token := sessionID + "." + signCartSession(sessionID)
+	c.Header(CartSessionHeader, token)
+	c.SetCookie(CartSessionCookie, token, cartSessionMaxAge, "/", "", false, true)
+}
+
+// ClearCartSessionID removes the guest cart session cookie
+func ClearCartSessionID(c *gin.Context) {
+	c.SetCookie(CartSessionCookie, "", -1, "/", "", false, true)
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cartSessionFromRequest(header, cookie string) string {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/cart", nil)
+	if header != "" {
+		c.Request.Header.Set(CartSessionHeader, header)
+	}
+	if cookie != "" {
+		c.Request.AddCookie(&http.Cookie{Name: CartSessionCookie, Value: cookie})
+	}
+	return GetCartSessionID(c)
+}
+
+func TestCartSession_AcceptsIssuedSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sessionID := uuid.New().String()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	SetCartSessionID(c, sessionID)
+
+	token := w.Header().Get(CartSessionHeader)
+	require.NotEmpty(t, token)
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.True(t, cookies[0].HttpOnly)
+
+	assert.Equal(t, sessionID, cartSessionFromRequest(token, ""))
+	assert.Equal(t, sessionID, cartSessionFromRequest("", cookies[0].Value))
+}
+
+func TestCartSession_RejectsSessionsNotIssuedByServer(t *testing.T) {
+	sessionID := uuid.New().String()
+
+	assert.Empty(t, cartSessionFromRequest(sessionID, ""))
+	assert.Empty(t, cartSessionFromRequest("", sessionID))
+	assert.Empty(t, cartSessionFromRequest(sessionID+".forged", ""))
+	assert.Empty(t, cartSessionFromRequest(uuid.New().String()+"."+signCartSession(sessionID), ""))
+	assert.Empty(t, cartSessionFromRequest("not-a-uuid."+signCartSession("not-a-uuid"), ""))
+}
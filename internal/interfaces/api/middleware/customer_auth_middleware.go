@@ -1,23 +1,38 @@
 package middleware
 
 import (
-	"encoding/base64"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"net"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/ratelimit"
 	"github.com/kirimku/smartseller-backend/internal/infrastructure/tenant"
+	"github.com/kirimku/smartseller-backend/pkg/captcha"
 	"github.com/kirimku/smartseller-backend/pkg/utils"
 )
 
+// Customer token lifetimes. A session expires with its refresh token unless
+// the refresh token is rotated first.
+const (
+	CustomerAccessTokenTTL  = 24 * time.Hour
+	CustomerRefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+// customerSessionTouchInterval limits how often a session's last use is written
+const customerSessionTouchInterval = time.Minute
+
 // CustomerClaims represents JWT claims for customer authentication
 type CustomerClaims struct {
 	CustomerID    string `json:"customer_id"`
@@ -30,78 +45,35 @@ type CustomerClaims struct {
 	jwt.RegisteredClaims
 }
 
-// SimpleRateLimiter provides basic rate limiting functionality
-type SimpleRateLimiter struct {
-	requests    []time.Time
-	maxRequests int
-	window      time.Duration
-	mu          sync.Mutex
-}
-
-// NewSimpleRateLimiter creates a new simple rate limiter
-func NewSimpleRateLimiter(maxRequests int, window time.Duration) *SimpleRateLimiter {
-	return &SimpleRateLimiter{
-		requests:    make([]time.Time, 0),
-		maxRequests: maxRequests,
-		window:      window,
-	}
-}
-
-// Allow checks if a request is allowed
-func (rl *SimpleRateLimiter) Allow() bool {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
-
-	now := time.Now()
-	// Remove old requests outside the window
-	cutoff := now.Add(-rl.window)
-	validRequests := make([]time.Time, 0)
-	for _, req := range rl.requests {
-		if req.After(cutoff) {
-			validRequests = append(validRequests, req)
-		}
-	}
-	rl.requests = validRequests
-
-	// Check if we can allow this request
-	if len(rl.requests) >= rl.maxRequests {
-		return false
-	}
-
-	// Add this request
-	rl.requests = append(rl.requests, now)
-	return true
-}
-
 // CustomerAuthMiddleware provides JWT-based customer authentication
 type CustomerAuthMiddleware struct {
 	secretKey       string
 	refreshKey      string
-	rateLimiters    map[string]*SimpleRateLimiter
-	rateLimiterMu   sync.RWMutex
+	rateLimits      *RateLimitPolicy
 	fraudDetector   *FraudDetector
 	sessionManager  *CustomerSessionManager
+	captchaPolicy   *CaptchaPolicy
 }
 
-// FraudDetector handles IP-based fraud detection
+// FraudDetector blocks IPs after repeated failed attempts. Attempts and
+// blocks live in the rate-limit store, so every replica sees them.
 type FraudDetector struct {
-	suspiciousIPs   map[string]time.Time
-	failedAttempts  map[string]int
-	mu              sync.RWMutex
-	maxAttempts     int
-	blockDuration   time.Duration
+	store         ratelimit.Store
+	maxAttempts   int
+	blockDuration time.Duration
 }
 
-// CustomerSessionManager handles customer session management
+// CustomerSessionManager checks access tokens against the persisted customer
+// sessions, so a session revoked on any replica stops working everywhere
 type CustomerSessionManager struct {
-	activeSessions map[string]time.Time
-	mu             sync.RWMutex
-	maxSessions    int
-	sessionTimeout time.Duration
+	sessionRepo   repository.CustomerSessionRepository
+	touchInterval time.Duration
 }
 
-// NewCustomerAuthMiddleware creates a new customer authentication middleware
-func NewCustomerAuthMiddleware() *CustomerAuthMiddleware {
+// NewCustomerAuthMiddleware creates a new customer authentication middleware.
+// Without a CAPTCHA policy, CaptchaRequired lets every request through;
+// without a rate-limit policy, limits and blocks are kept in process memory.
+func NewCustomerAuthMiddleware(sessionRepo repository.CustomerSessionRepository, captchaPolicy *CaptchaPolicy, rateLimits *RateLimitPolicy) *CustomerAuthMiddleware {
 	secretKey := os.Getenv("CUSTOMER_JWT_SECRET")
 	if secretKey == "" {
 		secretKey = os.Getenv("SESSION_KEY") // Fallback to existing key
@@ -115,31 +87,122 @@ func NewCustomerAuthMiddleware() *CustomerAuthMiddleware {
 		refreshKey = secretKey + "-refresh"
 	}
 
+	if captchaPolicy == nil {
+		captchaPolicy = NewCaptchaPolicy(nil, captcha.Config{Mode: captcha.ModeOff})
+	}
+	if rateLimits == nil {
+		rateLimits = NewRateLimitPolicy(ratelimit.NewMemoryStore(), nil, DefaultCustomerRateLimits(60, 60))
+	}
+
 	return &CustomerAuthMiddleware{
 		secretKey:      secretKey,
 		refreshKey:     refreshKey,
-		rateLimiters:   make(map[string]*SimpleRateLimiter),
-		fraudDetector:  NewFraudDetector(),
-		sessionManager: NewCustomerSessionManager(),
+		rateLimits:     rateLimits,
+		fraudDetector:  NewFraudDetector(rateLimits.Store()),
+		sessionManager: NewCustomerSessionManager(sessionRepo),
+		captchaPolicy:  captchaPolicy,
 	}
 }
 
 // NewFraudDetector creates a new fraud detector
-func NewFraudDetector() *FraudDetector {
+func NewFraudDetector(store ratelimit.Store) *FraudDetector {
 	return &FraudDetector{
-		suspiciousIPs:  make(map[string]time.Time),
-		failedAttempts: make(map[string]int),
-		maxAttempts:    5,
-		blockDuration:  15 * time.Minute,
+		store:         store,
+		maxAttempts:   5,
+		blockDuration: 15 * time.Minute,
+	}
+}
+
+// FailedAttempts returns the number of recent failed attempts from an IP
+func (fd *FraudDetector) FailedAttempts(ctx context.Context, ip string) int {
+	count, err := fd.store.Count(ctx, fraudAttemptsKey(ip))
+	if err != nil {
+		return 0
+	}
+	return int(count)
+}
+
+// RecordFailure counts a failed attempt and blocks the IP once it runs out
+// of attempts. Attempts are forgotten a block duration after the first one.
+func (fd *FraudDetector) RecordFailure(ctx context.Context, ip string) error {
+	attempts, err := fd.store.Increment(ctx, fraudAttemptsKey(ip), fd.blockDuration)
+	if err != nil {
+		return err
+	}
+	if attempts < int64(fd.maxAttempts) {
+		return nil
+	}
+
+	now := time.Now()
+	return fd.store.Block(ctx, &ratelimit.BlockedIP{
+		IP:             ip,
+		Reason:         "too many failed attempts",
+		FailedAttempts: int(attempts),
+		BlockedAt:      now,
+		ExpiresAt:      now.Add(fd.blockDuration),
+	})
+}
+
+// Blocked returns the active block of an IP, or nil
+func (fd *FraudDetector) Blocked(ctx context.Context, ip string) (*ratelimit.BlockedIP, error) {
+	return fd.store.GetBlock(ctx, ip)
+}
+
+// ListBlocked returns the IPs currently blocked
+func (fd *FraudDetector) ListBlocked(ctx context.Context) ([]*ratelimit.BlockedIP, error) {
+	return fd.store.ListBlocks(ctx)
+}
+
+// Unblock lifts an IP's block and forgets its failed attempts. Reports
+// whether the IP was blocked.
+func (fd *FraudDetector) Unblock(ctx context.Context, ip string) (bool, error) {
+	if err := fd.store.Delete(ctx, fraudAttemptsKey(ip)); err != nil {
+		return false, err
+	}
+	return fd.store.Unblock(ctx, ip)
+}
+
+// RecordMiss counts a lookup of something that does not exist and blocks
+// the IP once it has made maxMisses of them within the window. Misses are
+// counted per resource, apart from failed attempts, so guessing barcodes does
+// not eat into a customer's login attempts.
+func (fd *FraudDetector) RecordMiss(ctx context.Context, ip, resource string, maxMisses int, window time.Duration) error {
+	key := lookupMissesKey(resource, ip)
+	misses, err := fd.store.Increment(ctx, key, window)
+	if err != nil {
+		return err
 	}
+	if misses < int64(maxMisses) {
+		return nil
+	}
+
+	now := time.Now()
+	if err := fd.store.Block(ctx, &ratelimit.BlockedIP{
+		IP:             ip,
+		Reason:         "too many lookups of unknown " + resource + "s",
+		FailedAttempts: int(misses),
+		BlockedAt:      now,
+		ExpiresAt:      now.Add(window),
+	}); err != nil {
+		return err
+	}
+	// The block takes over, so misses start again from zero once it lifts
+	return fd.store.Delete(ctx, key)
+}
+
+func fraudAttemptsKey(ip string) string {
+	return "failed-attempts:" + ip
+}
+
+func lookupMissesKey(resource, ip string) string {
+	return "lookup-misses:" + strings.ReplaceAll(resource, " ", "-") + ":" + ip
 }
 
 // NewCustomerSessionManager creates a new session manager
-func NewCustomerSessionManager() *CustomerSessionManager {
+func NewCustomerSessionManager(sessionRepo repository.CustomerSessionRepository) *CustomerSessionManager {
 	return &CustomerSessionManager{
-		activeSessions: make(map[string]time.Time),
-		maxSessions:    5, // Max 5 concurrent sessions per customer
-		sessionTimeout: 24 * time.Hour,
+		sessionRepo:   sessionRepo,
+		touchInterval: customerSessionTouchInterval,
 	}
 }
 
@@ -173,7 +236,8 @@ func (cam *CustomerAuthMiddleware) CustomerAuthRequired() gin.HandlerFunc {
 		}
 
 		// Check session validity
-		if !cam.checkSessionValidity(claims.SessionID, claims.CustomerID) {
+		session := cam.checkSessionValidity(c.Request.Context(), claims, token)
+		if session == nil {
 			utils.ErrorResponse(c, http.StatusUnauthorized, "Session expired or invalid", nil)
 			c.Abort()
 			return
@@ -181,11 +245,13 @@ func (cam *CustomerAuthMiddleware) CustomerAuthRequired() gin.HandlerFunc {
 
 		// Set customer context
 		cam.setCustomerContext(c, claims, token)
+		cam.setTwoFactorPending(c, claims, session)
 		c.Next()
 	}
 }
 
-// OptionalCustomerAuth middleware for endpoints that work with or without authentication
+// OptionalCustomerAuth middleware for endpoints that work with or without authentication.
+// Tokens that still owe a second factor are treated as anonymous.
 func (cam *CustomerAuthMiddleware) OptionalCustomerAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Check rate limiting
@@ -197,8 +263,11 @@ func (cam *CustomerAuthMiddleware) OptionalCustomerAuth() gin.HandlerFunc {
 		token, claims, err := cam.extractAndValidateToken(c, "access")
 		if err == nil && claims != nil {
 			// Verify tenant context if token is present
-			if cam.verifyTenantContext(c, claims) && cam.checkSessionValidity(claims.SessionID, claims.CustomerID) {
-				cam.setCustomerContext(c, claims, token)
+			if cam.verifyTenantContext(c, claims) {
+				session := cam.checkSessionValidity(c.Request.Context(), claims, token)
+				if session != nil && !cam.twoFactorPending(c, claims, session) {
+					cam.setCustomerContext(c, claims, token)
+				}
 			}
 		}
 
@@ -259,32 +328,85 @@ func (cam *CustomerAuthMiddleware) TwoFactorRequired() gin.HandlerFunc {
 	}
 }
 
-// RateLimitMiddleware provides configurable rate limiting for customer endpoints
+// TwoFactorEnforced rejects sessions that still owe a TOTP code, either
+// because the customer enrolled or because the storefront requires it. Runs
+// after CustomerAuthRequired.
+func (cam *CustomerAuthMiddleware) TwoFactorEnforced() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool("two_factor_pending") {
+			utils.ErrorResponse(c, http.StatusForbidden, "Two-factor verification required", nil)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RateLimitMiddleware provides configurable rate limiting for customer
+// endpoints. Routes with their own policy keep it; other routes allow
+// requestsPerMinute per client.
 func (cam *CustomerAuthMiddleware) RateLimitMiddleware(requestsPerMinute int) gin.HandlerFunc {
+	name := fmt.Sprintf("group-%d", requestsPerMinute)
+	policy := ratelimit.Policy{Limit: requestsPerMinute, WindowSeconds: 60}
+
 	return func(c *gin.Context) {
-		identifier := cam.getClientIdentifier(c)
-		
-		cam.rateLimiterMu.Lock()
-		limiter, exists := cam.rateLimiters[identifier]
-		if !exists {
-			limiter = NewSimpleRateLimiter(requestsPerMinute, time.Minute)
-			cam.rateLimiters[identifier] = limiter
+		if !cam.enforceRateLimit(c, name, &policy) {
+			return
 		}
-		cam.rateLimiterMu.Unlock()
+		c.Next()
+	}
+}
 
-		if !limiter.Allow() {
-			utils.ErrorResponse(c, http.StatusTooManyRequests, "Rate limit exceeded", nil)
-			c.Abort()
+// EnumerationGuard protects lookups by guessable identifiers. Blocked IPs
+// are rejected, and every not-found response counts as a miss against the
+// client's IP until it is blocked for the window.
+func (cam *CustomerAuthMiddleware) EnumerationGuard(resource string, maxMisses int, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !cam.checkFraudDetection(c) {
 			return
 		}
 
 		c.Next()
+
+		if c.Writer.Status() == http.StatusNotFound {
+			_ = cam.fraudDetector.RecordMiss(c.Request.Context(), c.ClientIP(), resource, maxMisses, window)
+		}
 	}
 }
 
-// CaptchaRequired middleware for endpoints requiring CAPTCHA verification
+// FraudDetector returns the detector holding failed attempts and blocked IPs
+func (cam *CustomerAuthMiddleware) FraudDetector() *FraudDetector {
+	return cam.fraudDetector
+}
+
+// CaptchaRequired middleware for endpoints requiring CAPTCHA verification.
+// The storefront's CAPTCHA mode decides whether every request must solve one
+// or, in adaptive mode, only clients with failed attempts.
 func (cam *CustomerAuthMiddleware) CaptchaRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		storefrontID := uuid.Nil
+		if tenantCtx := GetTenantContext(c); tenantCtx != nil {
+			storefrontID = tenantCtx.StorefrontID
+		}
+
+		config, err := cam.captchaPolicy.ConfigFor(c.Request.Context(), storefrontID)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusServiceUnavailable, "CAPTCHA verification unavailable", nil)
+			c.Abort()
+			return
+		}
+		if !config.Enabled() {
+			c.Next()
+			return
+		}
+
+		clientIP := c.ClientIP()
+		if config.Mode == captcha.ModeAdaptive && cam.fraudDetector.FailedAttempts(c.Request.Context(), clientIP) == 0 {
+			c.Next()
+			return
+		}
+
 		captchaToken := c.GetHeader("X-Captcha-Token")
 		if captchaToken == "" {
 			captchaToken = c.PostForm("captcha_token")
@@ -296,8 +418,14 @@ func (cam *CustomerAuthMiddleware) CaptchaRequired() gin.HandlerFunc {
 			return
 		}
 
-		// Verify CAPTCHA token (mock implementation)
-		if !cam.verifyCaptcha(captchaToken) {
+		valid, err := cam.captchaPolicy.Verify(c.Request.Context(), config, captchaToken, clientIP)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusServiceUnavailable, "CAPTCHA verification unavailable", nil)
+			c.Abort()
+			return
+		}
+		if !valid {
+			cam.recordFailedAttempt(c)
 			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid CAPTCHA", nil)
 			c.Abort()
 			return
@@ -368,7 +496,22 @@ func (cam *CustomerAuthMiddleware) extractAndValidateToken(c *gin.Context, token
 		return nil, nil, fmt.Errorf("invalid authorization format")
 	}
 
-	tokenString := parts[1]
+	return cam.parseToken(parts[1], tokenType)
+}
+
+// ParseRefreshToken validates a refresh token and returns its claims
+func (cam *CustomerAuthMiddleware) ParseRefreshToken(tokenString string) (*CustomerClaims, error) {
+	_, claims, err := cam.parseToken(tokenString, "refresh")
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != "refresh" {
+		return nil, fmt.Errorf("invalid token type")
+	}
+	return claims, nil
+}
+
+func (cam *CustomerAuthMiddleware) parseToken(tokenString, tokenType string) (*jwt.Token, *CustomerClaims, error) {
 	secretKey := cam.secretKey
 	if tokenType == "refresh" {
 		secretKey = cam.refreshKey
@@ -416,11 +559,43 @@ func (cam *CustomerAuthMiddleware) verifyTenantContext(c *gin.Context, claims *C
 }
 
 func (cam *CustomerAuthMiddleware) checkRateLimit(c *gin.Context) bool {
-	identifier := cam.getClientIdentifier(c)
-	
-	// Check rate limit
-	limiter := cam.getRateLimiter(identifier)
-	if !limiter.Allow() {
+	return cam.enforceRateLimit(c, "", nil)
+}
+
+// enforceRateLimit counts the request against the route's policy, or the
+// group policy when the route has none, and rejects it once the client is
+// over the limit. Only the first check of a request counts. When the store
+// is unreachable requests are let through rather than failing the API.
+func (cam *CustomerAuthMiddleware) enforceRateLimit(c *gin.Context, groupName string, groupPolicy *ratelimit.Policy) bool {
+	if c.GetBool("rate_limit_checked") {
+		return true
+	}
+	c.Set("rate_limit_checked", true)
+
+	ctx := c.Request.Context()
+	storefrontID := uuid.Nil
+	if tenantCtx := GetTenantContext(c); tenantCtx != nil {
+		storefrontID = tenantCtx.StorefrontID
+	}
+
+	// A broken storefront override falls back to the platform limits
+	policies, _ := cam.rateLimits.PoliciesFor(ctx, storefrontID)
+	name, policy := policies.For(c.Request.Method, c.FullPath())
+	if name == "default" && groupPolicy != nil {
+		name, policy = groupName, *groupPolicy
+	}
+	if !policy.Valid() {
+		return true
+	}
+
+	decision, err := cam.rateLimits.Allow(ctx, storefrontID, name, policy, cam.getClientIdentifier(c))
+	if err != nil {
+		return true
+	}
+
+	setRateLimitHeaders(c, decision)
+	if !decision.Allowed {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(decision.Reset.Seconds()))))
 		c.JSON(http.StatusTooManyRequests, gin.H{
 			"error":   "Rate limit exceeded",
 			"message": "Too many requests. Please try again later.",
@@ -432,66 +607,60 @@ func (cam *CustomerAuthMiddleware) checkRateLimit(c *gin.Context) bool {
 	return true
 }
 
-func (cam *CustomerAuthMiddleware) getRateLimiter(identifier string) *SimpleRateLimiter {
-	cam.rateLimiterMu.Lock()
-	limiter, exists := cam.rateLimiters[identifier]
-	if !exists {
-		limiter = NewSimpleRateLimiter(60, time.Minute) // Default: 60 requests per minute
-		cam.rateLimiters[identifier] = limiter
-	}
-	cam.rateLimiterMu.Unlock()
-	return limiter
+// setRateLimitHeaders reports the client's quota using the IETF RateLimit
+// header fields
+func setRateLimitHeaders(c *gin.Context, decision *ratelimit.Decision) {
+	c.Header("RateLimit-Limit", strconv.Itoa(decision.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(int(math.Ceil(decision.Reset.Seconds()))))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", decision.Limit, int(decision.Window.Seconds())))
 }
 
 func (cam *CustomerAuthMiddleware) checkFraudDetection(c *gin.Context) bool {
-	clientIP := cam.getClientIP(c)
-	
-	cam.fraudDetector.mu.RLock()
-	blockedUntil, isBlocked := cam.fraudDetector.suspiciousIPs[clientIP]
-	attempts := cam.fraudDetector.failedAttempts[clientIP]
-	cam.fraudDetector.mu.RUnlock()
+	clientIP := c.ClientIP()
 
-	if isBlocked && time.Now().Before(blockedUntil) {
-		utils.ErrorResponse(c, http.StatusForbidden, "IP temporarily blocked due to suspicious activity", nil)
-		c.Abort()
-		return false
+	// Blocks are advisory when the store is unreachable
+	block, err := cam.fraudDetector.Blocked(c.Request.Context(), clientIP)
+	if err != nil || block == nil {
+		return true
 	}
 
-	if attempts >= cam.fraudDetector.maxAttempts {
-		cam.fraudDetector.mu.Lock()
-		cam.fraudDetector.suspiciousIPs[clientIP] = time.Now().Add(cam.fraudDetector.blockDuration)
-		cam.fraudDetector.mu.Unlock()
-		
-		utils.ErrorResponse(c, http.StatusForbidden, "Too many failed attempts. IP blocked temporarily", nil)
-		c.Abort()
-		return false
-	}
-
-	return true
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(block.TTL(time.Now()).Seconds()))))
+	utils.ErrorResponse(c, http.StatusForbidden, "IP temporarily blocked due to suspicious activity", nil)
+	c.Abort()
+	return false
 }
 
-func (cam *CustomerAuthMiddleware) checkSessionValidity(sessionID, customerID string) bool {
-	cam.sessionManager.mu.RLock()
-	lastUsed, exists := cam.sessionManager.activeSessions[sessionID]
-	cam.sessionManager.mu.RUnlock()
+// checkSessionValidity returns the session behind an access token, or nil
+// when the session is gone, revoked or has moved on to a newer token
+func (cam *CustomerAuthMiddleware) checkSessionValidity(ctx context.Context, claims *CustomerClaims, token *jwt.Token) *repository.CustomerSession {
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return nil
+	}
+	storefrontID, err := uuid.Parse(claims.StorefrontID)
+	if err != nil {
+		return nil
+	}
 
-	if !exists {
-		return false
+	session, err := cam.sessionManager.sessionRepo.GetByID(ctx, storefrontID, sessionID)
+	if err != nil || session == nil {
+		return nil
 	}
 
-	if time.Now().Sub(lastUsed) > cam.sessionManager.sessionTimeout {
-		cam.sessionManager.mu.Lock()
-		delete(cam.sessionManager.activeSessions, sessionID)
-		cam.sessionManager.mu.Unlock()
-		return false
+	// Access tokens issued before the last refresh no longer match the session
+	now := time.Now()
+	if !session.IsActive(now) || session.CustomerID.String() != claims.CustomerID ||
+		session.SessionToken != HashCustomerToken(token.Raw) {
+		return nil
 	}
 
-	// Update last used time
-	cam.sessionManager.mu.Lock()
-	cam.sessionManager.activeSessions[sessionID] = time.Now()
-	cam.sessionManager.mu.Unlock()
+	// Last use is informational, so a failed write does not fail the request
+	if now.Sub(session.LastUsedAt) > cam.sessionManager.touchInterval {
+		_ = cam.sessionManager.sessionRepo.UpdateLastUsed(ctx, storefrontID, sessionID)
+	}
 
-	return true
+	return session
 }
 
 func (cam *CustomerAuthMiddleware) setCustomerContext(c *gin.Context, claims *CustomerClaims, token *jwt.Token) {
@@ -505,54 +674,32 @@ func (cam *CustomerAuthMiddleware) setCustomerContext(c *gin.Context, claims *Cu
 	c.Set("customer_permissions", claims.Permissions)
 }
 
-func (cam *CustomerAuthMiddleware) recordFailedAttempt(c *gin.Context) {
-	clientIP := cam.getClientIP(c)
-	
-	cam.fraudDetector.mu.Lock()
-	cam.fraudDetector.failedAttempts[clientIP]++
-	cam.fraudDetector.mu.Unlock()
+// setTwoFactorPending flags requests whose token has not passed a second
+// factor the session or storefront asks for
+func (cam *CustomerAuthMiddleware) setTwoFactorPending(c *gin.Context, claims *CustomerClaims, session *repository.CustomerSession) {
+	c.Set("two_factor_pending", cam.twoFactorPending(c, claims, session))
 }
 
-func (cam *CustomerAuthMiddleware) getClientIdentifier(c *gin.Context) string {
-	// Try to get customer ID first, fallback to IP
-	if customerID, exists := c.Get("customer_id"); exists {
-		return fmt.Sprintf("customer:%s", customerID)
+// twoFactorPending reports whether the token has not passed a second factor
+// the session or storefront asks for
+func (cam *CustomerAuthMiddleware) twoFactorPending(c *gin.Context, claims *CustomerClaims, session *repository.CustomerSession) bool {
+	required := session.TwoFactorRequired
+	if tenantCtx := GetTenantContext(c); tenantCtx != nil && tenantCtx.RequireCustomerTwoFactor {
+		required = true
 	}
-	return fmt.Sprintf("ip:%s", cam.getClientIP(c))
+	return required && !claims.TwoFactorAuth
 }
 
-func (cam *CustomerAuthMiddleware) getClientIP(c *gin.Context) string {
-	// Check X-Forwarded-For header first
-	if xff := c.GetHeader("X-Forwarded-For"); xff != "" {
-		ips := strings.Split(xff, ",")
-		if len(ips) > 0 {
-			return strings.TrimSpace(ips[0])
-		}
-	}
-
-	// Check X-Real-IP header
-	if xri := c.GetHeader("X-Real-IP"); xri != "" {
-		return xri
-	}
-
-	// Fallback to RemoteAddr
-	ip, _, err := net.SplitHostPort(c.Request.RemoteAddr)
-	if err != nil {
-		return c.Request.RemoteAddr
-	}
-	return ip
+func (cam *CustomerAuthMiddleware) recordFailedAttempt(c *gin.Context) {
+	_ = cam.fraudDetector.RecordFailure(c.Request.Context(), c.ClientIP())
 }
 
-func (cam *CustomerAuthMiddleware) verifyCaptcha(token string) bool {
-	// Mock CAPTCHA verification - in production, integrate with reCAPTCHA or similar
-	// This is a simple base64 check for demonstration
-	decoded, err := base64.StdEncoding.DecodeString(token)
-	if err != nil {
-		return false
+func (cam *CustomerAuthMiddleware) getClientIdentifier(c *gin.Context) string {
+	// Try to get customer ID first, fallback to IP
+	if customerID, exists := c.Get("customer_id"); exists {
+		return fmt.Sprintf("customer:%s", customerID)
 	}
-	
-	// Simple validation - in production, verify with CAPTCHA service
-	return len(decoded) > 10 && strings.Contains(string(decoded), "valid")
+	return fmt.Sprintf("ip:%s", c.ClientIP())
 }
 
 func (cam *CustomerAuthMiddleware) isOriginAllowed(origin string, allowedOrigins []string) bool {
@@ -637,9 +784,9 @@ func HasCustomerPermission(c *gin.Context, permission string) bool {
 
 // CreateCustomerToken creates a new JWT token for customer
 func (cam *CustomerAuthMiddleware) CreateCustomerToken(customerID, storefrontID, email, sessionID string, tokenType string, permissions []string, twoFactorAuth bool) (string, error) {
-	expiryTime := time.Now().Add(24 * time.Hour) // Access token: 24 hours
+	expiryTime := time.Now().Add(CustomerAccessTokenTTL)
 	if tokenType == "refresh" {
-		expiryTime = time.Now().Add(7 * 24 * time.Hour) // Refresh token: 7 days
+		expiryTime = time.Now().Add(CustomerRefreshTokenTTL)
 	}
 
 	claims := &CustomerClaims{
@@ -656,7 +803,7 @@ func (cam *CustomerAuthMiddleware) CreateCustomerToken(customerID, storefrontID,
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "smartseller-customer-api",
 			Subject:   customerID,
-			ID:        sessionID,
+			ID:        uuid.New().String(), // Unique per token, so rotated tokens never repeat
 		},
 	}
 
@@ -670,20 +817,9 @@ func (cam *CustomerAuthMiddleware) CreateCustomerToken(customerID, storefrontID,
 	return token.SignedString([]byte(secretKey))
 }
 
-// RevokeCustomerSession revokes a customer session
-func (cam *CustomerAuthMiddleware) RevokeCustomerSession(sessionID string) {
-	cam.sessionManager.mu.Lock()
-	delete(cam.sessionManager.activeSessions, sessionID)
-	cam.sessionManager.mu.Unlock()
+// HashCustomerToken returns the digest stored for a customer token. Sessions
+// never hold the tokens themselves.
+func HashCustomerToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
-
-// CreateCustomerSession creates a new customer session
-func (cam *CustomerAuthMiddleware) CreateCustomerSession(customerID string) string {
-	sessionID := uuid.New().String()
-	
-	cam.sessionManager.mu.Lock()
-	cam.sessionManager.activeSessions[sessionID] = time.Now()
-	cam.sessionManager.mu.Unlock()
-	
-	return sessionID
-}
\ No newline at end of file
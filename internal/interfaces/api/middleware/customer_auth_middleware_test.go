@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/tenant"
+)
+
+func newEnumerationGuardRouter(maxMisses int) (*gin.Engine, *CustomerAuthMiddleware) {
+	gin.SetMode(gin.TestMode)
+	cam := NewCustomerAuthMiddleware(nil, nil, nil)
+
+	router := gin.New()
+	// As in production without TRUSTED_PROXIES: no forwarding headers are believed
+	_ = router.SetTrustedProxies(nil)
+	router.GET("/warranty/:barcode", cam.EnumerationGuard("warranty barcode", maxMisses, time.Minute), func(c *gin.Context) {
+		if c.Param("barcode") == "known" {
+			c.Status(http.StatusOK)
+			return
+		}
+		c.Status(http.StatusNotFound)
+	})
+	return router, cam
+}
+
+func lookup(router *gin.Engine, ip, barcode string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/warranty/"+barcode, nil)
+	req.RemoteAddr = ip + ":40000"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestEnumerationGuard_BlocksAfterMaxMisses(t *testing.T) {
+	router, _ := newEnumerationGuardRouter(3)
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusNotFound, lookup(router, "192.0.2.1", "guess").Code)
+	}
+
+	// Once blocked, even real barcodes are refused
+	w := lookup(router, "192.0.2.1", "known")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// Other clients are unaffected
+	assert.Equal(t, http.StatusOK, lookup(router, "192.0.2.2", "known").Code)
+}
+
+func TestEnumerationGuard_IgnoresSpoofedForwardedFor(t *testing.T) {
+	router, _ := newEnumerationGuardRouter(3)
+
+	for i := 0; i < 5; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/warranty/guess", nil)
+		req.RemoteAddr = "192.0.2.1:40000"
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i))
+		req.Header.Set("X-Real-IP", fmt.Sprintf("203.0.113.%d", i))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+	}
+
+	// Rotating forwarding headers does not reset the block on the peer address
+	assert.Equal(t, http.StatusForbidden, lookup(router, "192.0.2.1", "known").Code)
+}
+
+func TestEnumerationGuard_FoundLookupsDoNotCount(t *testing.T) {
+	router, _ := newEnumerationGuardRouter(3)
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, http.StatusOK, lookup(router, "192.0.2.1", "known").Code)
+	}
+	assert.Equal(t, http.StatusNotFound, lookup(router, "192.0.2.1", "guess").Code)
+	assert.Equal(t, http.StatusNotFound, lookup(router, "192.0.2.1", "guess").Code)
+	assert.Equal(t, http.StatusOK, lookup(router, "192.0.2.1", "known").Code)
+}
+
+func TestEnumerationGuard_MissesDoNotCountAsFailedLogins(t *testing.T) {
+	router, cam := newEnumerationGuardRouter(100)
+
+	for i := 0; i < 5; i++ {
+		lookup(router, "192.0.2.1", "guess")
+	}
+	assert.Zero(t, cam.FraudDetector().FailedAttempts(context.Background(), "192.0.2.1"))
+}
+
+// stubSessionRepository serves a single customer session
+type stubSessionRepository struct {
+	repository.CustomerSessionRepository
+	session *repository.CustomerSession
+}
+
+func (r *stubSessionRepository) GetByID(ctx context.Context, storefrontID, sessionID uuid.UUID) (*repository.CustomerSession, error) {
+	if r.session == nil || r.session.ID != sessionID {
+		return nil, nil
+	}
+	return r.session, nil
+}
+
+func (r *stubSessionRepository) UpdateLastUsed(ctx context.Context, storefrontID, sessionID uuid.UUID) error {
+	return nil
+}
+
+func optionalAuthCustomer(t *testing.T, twoFactorRequired, twoFactorAuth bool) (string, bool) {
+	gin.SetMode(gin.TestMode)
+	storefrontID := uuid.New()
+	session := &repository.CustomerSession{
+		ID:                uuid.New(),
+		CustomerID:        uuid.New(),
+		StorefrontID:      storefrontID,
+		ExpiresAt:         time.Now().Add(time.Hour),
+		LastUsedAt:        time.Now(),
+		TwoFactorRequired: twoFactorRequired,
+	}
+	cam := NewCustomerAuthMiddleware(&stubSessionRepository{session: session}, nil, nil)
+
+	token, err := cam.CreateCustomerToken(session.CustomerID.String(), storefrontID.String(), "buyer@example.com", session.ID.String(), "access", nil, twoFactorAuth)
+	require.NoError(t, err)
+	session.SessionToken = HashCustomerToken(token)
+
+	var customerID string
+	var authenticated bool
+	router := gin.New()
+	router.GET("/cart", func(c *gin.Context) {
+		c.Set("tenant_context", &tenant.TenantContext{StorefrontID: storefrontID})
+	}, cam.OptionalCustomerAuth(), func(c *gin.Context) {
+		customerID, authenticated = GetCustomerID(c)
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/cart", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	return customerID, authenticated
+}
+
+func TestOptionalCustomerAuth_SetsCustomerForVerifiedSessions(t *testing.T) {
+	_, authenticated := optionalAuthCustomer(t, false, false)
+	assert.True(t, authenticated)
+
+	_, authenticated = optionalAuthCustomer(t, true, true)
+	assert.True(t, authenticated)
+}
+
+func TestOptionalCustomerAuth_TreatsPendingTwoFactorAsAnonymous(t *testing.T) {
+	customerID, authenticated := optionalAuthCustomer(t, true, false)
+	assert.False(t, authenticated)
+	assert.Empty(t, customerID)
+}
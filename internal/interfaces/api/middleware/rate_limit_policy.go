@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/ratelimit"
+)
+
+// DefaultCustomerRateLimits returns the platform rate limits for customer
+// endpoints: a general limit per client plus tighter limits on the endpoints
+// bots go for
+func DefaultCustomerRateLimits(limit, windowSeconds int) ratelimit.PolicySet {
+	return ratelimit.PolicySet{
+		Default: ratelimit.Policy{Limit: limit, WindowSeconds: windowSeconds},
+		Routes: map[string]ratelimit.Policy{
+			"POST /api/v1/storefront/:slug/auth/login":           {Limit: 10, WindowSeconds: 60},
+			"POST /api/v1/storefront/:slug/auth/register":        {Limit: 5, WindowSeconds: 60},
+			"POST /api/v1/storefront/:slug/auth/forgot-password": {Limit: 5, WindowSeconds: 300},
+			"POST /api/v1/storefront/:slug/2fa/verify":           {Limit: 10, WindowSeconds: 300},
+		},
+	}
+}
+
+// RateLimitPolicy applies per-route rate limits, which storefronts can tighten
+// or relax in storefront_configs, using counters shared by every replica
+type RateLimitPolicy struct {
+	limiter  *ratelimit.Limiter
+	configs  *storefrontConfigCache
+	defaults ratelimit.PolicySet
+}
+
+// NewRateLimitPolicy creates a rate-limit policy. configRepo may be nil, in
+// which case every storefront uses the defaults.
+func NewRateLimitPolicy(store ratelimit.Store, configRepo repository.StorefrontConfigRepository, defaults ratelimit.PolicySet) *RateLimitPolicy {
+	return &RateLimitPolicy{
+		limiter:  ratelimit.NewLimiter(store),
+		configs:  newStorefrontConfigCache(configRepo),
+		defaults: defaults,
+	}
+}
+
+// Store returns the store holding counters and blocked IPs
+func (p *RateLimitPolicy) Store() ratelimit.Store {
+	return p.limiter.Store()
+}
+
+// PoliciesFor returns the rate limits of a storefront
+func (p *RateLimitPolicy) PoliciesFor(ctx context.Context, storefrontID uuid.UUID) (ratelimit.PolicySet, error) {
+	var override ratelimit.PolicySet
+	if err := p.configs.Decode(ctx, storefrontID, repository.StorefrontConfigRateLimits, &override); err != nil {
+		return p.defaults, fmt.Errorf("invalid rate limit config for storefront %s: %w", storefrontID, err)
+	}
+	return p.defaults.Merge(override), nil
+}
+
+// Allow counts a request from a client of a storefront against a named policy
+func (p *RateLimitPolicy) Allow(ctx context.Context, storefrontID uuid.UUID, name string, policy ratelimit.Policy, identifier string) (*ratelimit.Decision, error) {
+	return p.limiter.Allow(ctx, name, policy, storefrontID.String()+":"+identifier)
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+)
+
+// storefrontConfigTTL is how long a storefront setting is cached
+const storefrontConfigTTL = time.Minute
+
+// storefrontConfigCache caches storefront_configs values read on every
+// request, such as CAPTCHA and rate-limit settings
+type storefrontConfigCache struct {
+	configRepo repository.StorefrontConfigRepository
+
+	mu      sync.RWMutex
+	entries map[storefrontConfigKey]cachedStorefrontConfig
+}
+
+type storefrontConfigKey struct {
+	storefrontID uuid.UUID
+	key          string
+}
+
+type cachedStorefrontConfig struct {
+	value     json.RawMessage
+	expiresAt time.Time
+}
+
+func newStorefrontConfigCache(configRepo repository.StorefrontConfigRepository) *storefrontConfigCache {
+	return &storefrontConfigCache{
+		configRepo: configRepo,
+		entries:    make(map[storefrontConfigKey]cachedStorefrontConfig),
+	}
+}
+
+// Decode reads a storefront setting into dest, leaving dest untouched when
+// the storefront does not set it
+func (c *storefrontConfigCache) Decode(ctx context.Context, storefrontID uuid.UUID, key string, dest interface{}) error {
+	if c.configRepo == nil || storefrontID == uuid.Nil {
+		return nil
+	}
+
+	value, err := c.get(ctx, storefrontID, key)
+	if err != nil || value == nil {
+		return err
+	}
+	return json.Unmarshal(value, dest)
+}
+
+func (c *storefrontConfigCache) get(ctx context.Context, storefrontID uuid.UUID, key string) (json.RawMessage, error) {
+	cacheKey := storefrontConfigKey{storefrontID: storefrontID, key: key}
+	now := time.Now()
+
+	c.mu.RLock()
+	cached, ok := c.entries[cacheKey]
+	c.mu.RUnlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.value, nil
+	}
+
+	value, err := c.configRepo.GetValue(ctx, storefrontID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	// Drop expired entries so storefronts that stopped sending traffic do not linger
+	for k, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[cacheKey] = cachedStorefrontConfig{value: value, expiresAt: now.Add(storefrontConfigTTL)}
+	c.mu.Unlock()
+
+	return value, nil
+}
@@ -1,7 +1,10 @@
 package router
 
 import (
+	"context"
 	"log/slog"
+	"net/http"
+	"net/url"
 	"os"
 	"time"
 
@@ -13,16 +16,27 @@ import (
 	"github.com/kirimku/smartseller-backend/internal/application/service"
 	"github.com/kirimku/smartseller-backend/internal/application/usecase"
 	"github.com/kirimku/smartseller-backend/internal/config"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/courier"
 	"github.com/kirimku/smartseller-backend/internal/infrastructure/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/ratelimit"
 	infraRepo "github.com/kirimku/smartseller-backend/internal/infrastructure/repository"
 	"github.com/kirimku/smartseller-backend/internal/infrastructure/tenant"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/webhook"
 	"github.com/kirimku/smartseller-backend/internal/interfaces/api/handler"
 	"github.com/kirimku/smartseller-backend/internal/interfaces/http/handlers"
 	customerMiddleware "github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
 	"github.com/kirimku/smartseller-backend/internal/interfaces/api/routes"
 
+	"github.com/kirimku/smartseller-backend/pkg/cache"
+	"github.com/kirimku/smartseller-backend/pkg/captcha"
+	"github.com/kirimku/smartseller-backend/pkg/clamav"
 	"github.com/kirimku/smartseller-backend/pkg/email"
 	"github.com/kirimku/smartseller-backend/pkg/middleware"
+	"github.com/kirimku/smartseller-backend/pkg/payment"
+	"github.com/kirimku/smartseller-backend/pkg/redis"
+	"github.com/kirimku/smartseller-backend/pkg/storage"
+	"github.com/kirimku/smartseller-backend/pkg/telegram"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
@@ -30,6 +44,9 @@ import (
 type Router struct {
 	db           *sqlx.DB
 	emailService email.EmailSender
+
+	// stopJobs stops the background jobs started while setting up routes
+	stopJobs []func()
 }
 
 // NewRouter creates a new router instance
@@ -44,6 +61,12 @@ func NewRouter(db *sqlx.DB, emailService email.EmailSender) *Router {
 func (r *Router) SetupRoutes() *gin.Engine {
 	router := gin.New()
 
+	// Only believe forwarding headers from our own proxies, so clients cannot
+	// pick the IP that rate limits and fraud detection key on
+	if err := router.SetTrustedProxies(config.AppConfig.TrustedProxies); err != nil {
+		panic("TRUSTED_PROXIES is misconfigured: " + err.Error())
+	}
+
 	// Add core middleware
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
@@ -77,6 +100,51 @@ func (r *Router) SetupRoutes() *gin.Engine {
 	return router
 }
 
+// Shutdown stops the background jobs started by SetupRoutes and waits for
+// sweeps in progress to finish
+func (r *Router) Shutdown() {
+	for _, stop := range r.stopJobs {
+		stop()
+	}
+	r.stopJobs = nil
+}
+
+// startJob starts a background job and keeps its stop function for Shutdown
+func (r *Router) startJob(job *usecase.PeriodicJob) {
+	r.stopJobs = append(r.stopJobs, job.Start())
+}
+
+// newRedisClient connects to the configured Redis server. It returns nil when
+// Redis is not configured or unreachable, in which case callers keep state in memory.
+func (r *Router) newRedisClient(logger *slog.Logger) *redis.Client {
+	if config.AppConfig.Redis.URL == "" {
+		return nil
+	}
+
+	opts, err := redis.ParseURL(config.AppConfig.Redis.URL)
+	if err != nil {
+		logger.Error("Invalid Redis URL", "error", err)
+		return nil
+	}
+	if config.AppConfig.Redis.Password != "" {
+		opts.Password = config.AppConfig.Redis.Password
+	}
+	if config.AppConfig.Redis.DB != 0 {
+		opts.DB = config.AppConfig.Redis.DB
+	}
+
+	client := redis.NewClient(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx); err != nil {
+		logger.Error("Failed to connect to Redis", "error", err)
+		client.Close()
+		return nil
+	}
+
+	return client
+}
+
 // setupAPIRoutes configures the API routes
 func (r *Router) setupAPIRoutes(router *gin.Engine) {
 	// Create a default structured logger
@@ -92,7 +160,19 @@ func (r *Router) setupAPIRoutes(router *gin.Engine) {
 
 	// Initialize tenant infrastructure first
 	tenantConfig := tenant.DefaultTenantConfig()
-	tenantCache := tenant.NewInMemoryTenantCache(1000, 5*time.Minute)
+	// Schema tenants connect to the application database with their own search path
+	tenantConfig.SharedDatabaseURL = config.AppConfig.Database.URL
+	// Replicas share the tenant cache through Redis when it is configured
+	// and their rate-limit counters and blocked IPs along with it
+	var tenantCache tenant.TenantCache
+	var rateLimitStore ratelimit.Store
+	if redisClient := r.newRedisClient(logger); redisClient != nil {
+		tenantCache = tenant.NewRedisTenantCache(redisClient)
+		rateLimitStore = ratelimit.NewRedisStore(redisClient)
+	} else {
+		tenantCache = tenant.NewInMemoryTenantCache(1000, 5*time.Minute)
+		rateLimitStore = ratelimit.NewMemoryStore()
+	}
 	
 	// Initialize repositories with proper parameters
 	customerRepo := repository.NewPostgreSQLCustomerRepository(r.db, nil, &repository.NoOpMetricsCollector{})
@@ -107,9 +187,33 @@ func (r *Router) setupAPIRoutes(router *gin.Engine) {
 	customerAddressRepo = repository.NewPostgreSQLCustomerAddressRepository(r.db, tenantResolver, &repository.NoOpMetricsCollector{})
 	storefrontRepo = repository.NewPostgreSQLStorefrontRepository(r.db, tenantResolver, &repository.NoOpMetricsCollector{})
 
+	// Customer sessions are persisted so every replica sees revocations
+	customerSessionRepo := repository.NewPostgreSQLCustomerSessionRepository(r.db, tenantResolver, &repository.NoOpMetricsCollector{})
+	customerTwoFactorRepo := repository.NewPostgreSQLCustomerTwoFactorRepository(r.db, tenantResolver, &repository.NoOpMetricsCollector{})
+	// Storefronts bring their own CAPTCHA site secret; the platform default covers the rest
+	storefrontConfigRepo := repository.NewPostgreSQLStorefrontConfigRepository(r.db, &repository.NoOpMetricsCollector{})
+	captchaPolicy := customerMiddleware.NewCaptchaPolicy(storefrontConfigRepo, captcha.Config{
+		Provider:  captcha.Provider(config.AppConfig.Captcha.Provider),
+		SecretKey: config.AppConfig.Captcha.SecretKey,
+		Mode:      captcha.Mode(config.AppConfig.Captcha.Mode),
+		MinScore:  config.AppConfig.Captcha.MinScore,
+	})
+	rateLimitPolicy := customerMiddleware.NewRateLimitPolicy(rateLimitStore, storefrontConfigRepo,
+		customerMiddleware.DefaultCustomerRateLimits(config.AppConfig.CustomerRateLimit.Limit, config.AppConfig.CustomerRateLimit.WindowSeconds))
+	customerAuthMiddleware := customerMiddleware.NewCustomerAuthMiddleware(customerSessionRepo, captchaPolicy, rateLimitPolicy)
+	customerSessionService := service.NewCustomerSessionService(customerSessionRepo, customerTwoFactorRepo, customerAuthMiddleware, config.AppConfig.CustomerSessions.MaxSessions)
+	// TOTP secrets are sealed with CUSTOMER_TWO_FACTOR_KEY or SESSION_KEY;
+	// without one the two-factor routes are left out. Sessions that need a
+	// second factor stay blocked rather than skipping it.
+	customerTwoFactorService, err := service.NewCustomerTwoFactorService(customerTwoFactorRepo, customerSessionService,
+		config.AppConfig.CustomerTwoFactor.EncryptionKey, config.AppConfig.CustomerTwoFactor.Issuer)
+	if err != nil {
+		logger.Warn("Customer two-factor key not configured, two-factor authentication disabled", "error", err)
+	}
+
 	// Initialize services
 	validationService := service.NewValidationServiceSimple(customerRepo, storefrontRepo, customerAddressRepo)
-	customerService := service.NewCustomerServiceSimple(customerRepo, tenantResolver)
+	customerService := service.NewCustomerServiceSimple(customerRepo, tenantResolver, customerSessionService)
 	
 	// Type assert email service to MailgunService for customer services
 	mailgunService, ok := r.emailService.(*email.MailgunService)
@@ -120,21 +224,26 @@ func (r *Router) setupAPIRoutes(router *gin.Engine) {
 	customerPasswordResetService := service.NewCustomerPasswordResetService(customerRepo, mailgunService)
 	customerEmailVerificationService := service.NewCustomerEmailVerificationService(customerRepo, mailgunService)
 	
+	// Server-side carts for customers and guest sessions
+	cartRepo := repository.NewCartRepository(r.db, zerolog.New(os.Stdout).With().Str("component", "cart").Timestamp().Logger())
+	cartUseCase := usecase.NewCartUseCase(r.db, cartRepo, storefrontRepo, productRepo, productVariantRepo, logger)
+	cartHandler := handler.NewCartHandler(cartUseCase, logger)
+
 	// Initialize customer auth handler
 	customerAuthHandler := handlers.NewCustomerAuthHandler(
 		customerService,
 		customerPasswordResetService,
 		customerEmailVerificationService,
 		validationService,
+		cartUseCase,
 	)
 
 	// Initialize address service and handler
 	addressService := service.NewCustomerAddressServiceSimple(customerAddressRepo, customerRepo, tenantResolver)
 	addressHandler := handler.NewAddressHandler(addressService, validationService)
 
-	// Initialize tenant and customer auth middleware
+	// Initialize tenant middleware
 	tenantMiddleware := customerMiddleware.NewTenantMiddleware(tenantResolver, "localhost")
-	customerAuthMiddleware := customerMiddleware.NewCustomerAuthMiddleware()
 
 	// Create use cases (existing)
 	userUseCase := usecase.NewUserUseCase(userRepo, r.emailService)
@@ -168,23 +277,270 @@ func (r *Router) setupAPIRoutes(router *gin.Engine) {
 	// Initialize warranty barcode handler with dependencies
 	zeroLogger := zerolog.New(os.Stdout).With().Str("component", "warranty_barcode").Timestamp().Logger()
 	warrantyBarcodeRepo := repository.NewWarrantyBarcodeRepository(r.db, tenantResolver, zeroLogger)
-	warrantyBarcodeHandler := handler.NewWarrantyBarcodeHandlerWithDependencies(logger, r.db, tenantResolver, warrantyBarcodeRepo, storefrontRepo)
+
+	// Warranty policy templates decide warranty periods and claim rules
+	warrantyPolicyRepo := repository.NewWarrantyPolicyRepository(r.db, tenantResolver, zeroLogger.With().Str("component", "warranty_policy").Logger())
+	warrantyPolicyUseCase := usecase.NewWarrantyPolicyUseCase(warrantyPolicyRepo, productRepo, productCategoryRepo, logger)
+	warrantyPolicyHandler := handler.NewWarrantyPolicyHandler(warrantyPolicyUseCase, storefrontRepo, logger)
+
+	warrantyBarcodeHandler := handler.NewWarrantyBarcodeHandlerWithDependencies(logger, r.db, tenantResolver, warrantyBarcodeRepo, storefrontRepo, warrantyPolicyUseCase)
+
+	// Public warranty lookups by customers holding a barcode
+	publicWarrantyUseCase := usecase.NewPublicWarrantyUseCase(warrantyBarcodeRepo, productRepo, customerRepo, logger)
+	publicWarrantyHandler := handler.NewPublicWarrantyHandler(publicWarrantyUseCase, logger)
 	
-	// Warranty claim handler
-	warrantyClaimHandler := handler.NewWarrantyClaimHandler(logger)
+	// Warranty claims, tracked against the processing SLA of their warranty policy
+	warrantyClaimRepo := repository.NewWarrantyClaimRepository(r.db, tenantResolver, zeroLogger.With().Str("component", "warranty_claim").Logger())
+	claimSLAConfig := entity.ClaimSLAConfig{
+		DefaultHours: config.AppConfig.ClaimSLA.DefaultHours,
+		AtRiskRatio:  config.AppConfig.ClaimSLA.AtRiskRatio,
+	}
+
+	// Repair roster; validated claims go to the least-loaded technician skilled in the product
+	technicianRepo := repository.NewTechnicianRepository(r.db, zeroLogger.With().Str("component", "technician").Logger())
+	technicianUseCase := usecase.NewTechnicianUseCase(r.db, technicianRepo, warrantyClaimRepo, storefrontRepo, userRepo, productRepo, productCategoryRepo, logger)
+	technicianHandler := handler.NewTechnicianHandler(technicianUseCase, storefrontRepo, logger)
+
+	warrantyClaimUseCase := usecase.NewWarrantyClaimUseCase(warrantyClaimRepo, warrantyBarcodeRepo, warrantyPolicyUseCase, technicianUseCase, claimSLAConfig)
+	warrantyClaimSLAUseCase := usecase.NewWarrantyClaimSLAUseCase(warrantyClaimRepo, storefrontRepo, userRepo, r.emailService, telegram.GetAlertManager(), logger)
+	r.startJob(usecase.NewClaimSLAMonitor(warrantyClaimSLAUseCase, config.AppConfig.ClaimSLA.SweepInterval, logger))
+	warrantyClaimHandler := handler.NewWarrantyClaimHandler(warrantyClaimUseCase, storefrontRepo, logger)
 	
 	// Claim attachment and timeline handlers
-	claimAttachmentHandler := handler.NewClaimAttachmentHandler()
+	// Claim attachments live in object storage and are only served once clamd passed them
+	attachmentStorage, err := storage.New(storage.Config{
+		Driver:    config.AppConfig.Storage.Driver,
+		LocalPath: config.AppConfig.Storage.LocalPath,
+		S3: storage.S3Config{
+			Endpoint:        config.AppConfig.Storage.S3Endpoint,
+			Region:          config.AppConfig.Storage.S3Region,
+			Bucket:          config.AppConfig.Storage.S3Bucket,
+			AccessKeyID:     config.AppConfig.Storage.S3AccessKeyID,
+			SecretAccessKey: config.AppConfig.Storage.S3SecretAccessKey,
+			PathStyle:       config.AppConfig.Storage.S3PathStyle,
+		},
+	})
+	if err != nil {
+		panic("Attachment storage is misconfigured: " + err.Error())
+	}
+	var attachmentScanner usecase.AttachmentScanner
+	if config.AppConfig.ClamAV.Address != "" {
+		clamClient, err := clamav.NewClient(config.AppConfig.ClamAV.Address, config.AppConfig.ClamAV.Timeout)
+		if err != nil {
+			panic("ClamAV is misconfigured: " + err.Error())
+		}
+		attachmentScanner = clamClient
+	}
+	claimAttachmentUseCase := usecase.NewClaimAttachmentUseCase(warrantyClaimRepo, attachmentStorage, attachmentScanner, logger)
+	r.startJob(usecase.NewAttachmentScanMonitor(claimAttachmentUseCase, config.AppConfig.ClamAV.SweepInterval, logger))
+	claimAttachmentHandler := handler.NewClaimAttachmentHandler(claimAttachmentUseCase, storefrontRepo, logger)
+
+	// Customers register distributed barcodes themselves, with a proof of
+	// purchase kept next to the claim attachments
+	warrantyRegistrationUseCase := usecase.NewWarrantyRegistrationUseCase(warrantyBarcodeRepo, productRepo, customerRepo, warrantyPolicyUseCase,
+		attachmentStorage, attachmentScanner, r.emailService, config.AppConfig.WarrantyRegistration.ActivationWindow, logger)
 	claimTimelineHandler := handler.NewClaimTimelineHandler()
 	
+	// Spare parts inventory, reserved and consumed by repair tickets
+	sparePartRepo := repository.NewSparePartRepository(r.db, zeroLogger.With().Str("component", "spare_part").Logger())
+	sparePartUseCase := usecase.NewSparePartUseCase(sparePartRepo, logger)
+	sparePartHandler := handler.NewSparePartHandler(sparePartUseCase, storefrontRepo, logger)
+
 	// Repair ticket handler
-	repairTicketHandler := handler.NewRepairTicketHandler()
+	repairTicketUseCase := usecase.NewRepairTicketUseCase(r.db, warrantyClaimRepo, sparePartRepo, technicianUseCase, logger)
+	repairTicketHandler := handler.NewRepairTicketHandler(repairTicketUseCase, storefrontRepo, logger)
 	
 	// Batch generation handler
 	batchGenerationHandler := handler.NewBatchGenerationHandler()
 
+	// Order management
+	orderRepo := repository.NewOrderRepository(r.db, tenantResolver, zeroLogger.With().Str("component", "order").Logger())
+	stockReservationRepo := repository.NewStockReservationRepository(r.db, zeroLogger.With().Str("component", "stock_reservation").Logger())
+	orderUseCase := usecase.NewOrderUseCase(r.db, orderRepo, stockReservationRepo, productUseCase, logger)
+	orderHandler := handler.NewOrderHandler(orderUseCase, storefrontRepo, logger)
+	storefrontOrderHandler := handler.NewStorefrontOrderHandler(orderUseCase, logger)
+
+	// Checkout with stock reservations released when payment does not arrive in time
+	checkoutUseCase := usecase.NewCheckoutUseCase(
+		r.db,
+		orderRepo,
+		cartRepo,
+		stockReservationRepo,
+		storefrontRepo,
+		customerRepo,
+		customerAddressRepo,
+		productRepo,
+		productVariantRepo,
+		productUseCase,
+		config.AppConfig.Checkout.ReservationTTL,
+		logger,
+	)
+	r.startJob(usecase.NewReservationReleaser(checkoutUseCase, config.AppConfig.Checkout.ReservationSweepInterval, logger))
+	checkoutHandler := handler.NewCheckoutHandler(checkoutUseCase, logger)
+
+	// Order payments through Xendit and DurianPay, settled by their callbacks.
+	// Storefronts pick the gateway per payment method in their settings.
+	paymentGateways := []payment.PaymentGateway{
+		payment.NewXenditGateway(payment.XenditConfig{
+			APIKey:        config.AppConfig.Payment.XenditApiKey,
+			BaseURL:       config.AppConfig.Payment.XenditBaseURL,
+			CallbackToken: config.AppConfig.Payment.XenditCallbackToken,
+		}),
+	}
+	durianPayConfig := config.AppConfig.Payment.DurianPay
+	durianPayWebhookPath := ""
+	if webhookURL, err := url.Parse(durianPayConfig.WebhookURL); err == nil {
+		durianPayWebhookPath = webhookURL.Path
+	}
+	durianPayGateway, err := payment.NewDurianPayGateway(payment.DurianPayConfig{
+		APIKey:        durianPayConfig.APIKey,
+		BaseURL:       durianPayConfig.BaseURL,
+		EnableSNAP:    durianPayConfig.EnableSNAP,
+		ClientKey:     durianPayConfig.ClientKey,
+		MerchantID:    durianPayConfig.MerchantID,
+		PrivateKey:    durianPayConfig.PrivateKey,
+		PublicKey:     durianPayConfig.PublicKey,
+		WebhookSecret: durianPayConfig.WebhookSecret,
+		WebhookPath:   durianPayWebhookPath,
+		MaxRetries:    durianPayConfig.MaxRetries,
+	}, payment.WithHTTPClient(&http.Client{Timeout: durianPayConfig.Timeout}))
+	if err != nil {
+		panic("DurianPay is misconfigured: " + err.Error())
+	}
+	paymentGateways = append(paymentGateways, durianPayGateway)
+	paymentUseCase := usecase.NewPaymentUseCase(
+		r.db,
+		orderRepo,
+		storefrontRepo,
+		paymentGateways,
+		config.AppConfig.Payment.Gateway,
+		time.Duration(config.AppConfig.Payment.InvoiceExpiryHours)*time.Hour,
+		config.AppConfig.Payment.DefaultEWalletChannel,
+		logger,
+	)
+	paymentHandler := handler.NewPaymentHandler(paymentUseCase, logger)
+	routes.SetupPaymentWebhookRoutes(router, paymentHandler)
+
+	// Shipping options at checkout, quoted by every courier with credentials
+	// and reused for the same route and weight
+	rateCacheTTL := config.AppConfig.Checkout.ShippingRateCacheTTL
+	rateCache := cache.NewInMemoryCache(rateCacheTTL, 2*rateCacheTTL)
+	var rateQuoters []courier.RateQuoter
+	if config.AppConfig.JNEAPIKey != "" {
+		rateQuoters = append(rateQuoters, courier.NewJNEQuoter(courier.JNEConfig{
+			BaseURL:  config.AppConfig.JNEAPIURL,
+			Username: config.AppConfig.JNEUsername,
+			APIKey:   config.AppConfig.JNEAPIKey,
+			Mapping:  courier.NewAreaMapping(config.AppConfig.JNEMapping),
+		}))
+	}
+	if config.AppConfig.JNTConfig.TariffAPIURL != "" {
+		rateQuoters = append(rateQuoters, courier.NewJNTQuoter(courier.JNTConfig{
+			TariffAPIURL: config.AppConfig.JNTConfig.TariffAPIURL,
+			TariffAPIKey: config.AppConfig.JNTConfig.TariffAPIKey,
+			CustName:     config.AppConfig.JNTConfig.CustName,
+			Mapping:      courier.NewAreaMapping(config.AppConfig.JNTMapping),
+		}))
+	}
+	if config.AppConfig.SiCepatConfig.APIKey != "" {
+		rateQuoters = append(rateQuoters, courier.NewSiCepatQuoter(courier.SiCepatConfig{
+			BaseURL:            config.AppConfig.SiCepatConfig.BaseURL,
+			APIKey:             config.AppConfig.SiCepatConfig.APIKey,
+			OriginMapping:      courier.NewAreaMapping(config.AppConfig.SiCepatMappingOrigin),
+			DestinationMapping: courier.NewAreaMapping(config.AppConfig.SiCepatMappingDestination),
+		}))
+	}
+	if config.AppConfig.SAPXConfig.APIKeyPickup != "" {
+		rateQuoters = append(rateQuoters, courier.NewSAPXQuoter(courier.SAPXConfig{
+			APIURL:       config.AppConfig.SAPXConfig.APIURL,
+			APIKey:       config.AppConfig.SAPXConfig.APIKeyPickup,
+			CustomerCode: config.AppConfig.SAPXConfig.CustomerCodeNonCOD,
+			Mapping:      courier.NewAreaMapping(config.AppConfig.SAPXMapping),
+		}, courier.WithHTTPClient(&http.Client{Timeout: config.AppConfig.SAPXConfig.Timeout})))
+	}
+	for i, quoter := range rateQuoters {
+		rateQuoters[i] = courier.NewCachingQuoter(quoter, rateCache, rateCacheTTL)
+	}
+	shippingRateUseCase := usecase.NewShippingRateUseCase(storefrontRepo, productRepo, productVariantRepo, rateQuoters, logger)
+	shippingRateHandler := handler.NewShippingRateHandler(shippingRateUseCase, logger)
+
+	// Courier tracking webhooks applied to orders and warranty claim shipments
+	courierWebhookEventRepo := repository.NewCourierWebhookEventRepository(r.db, zeroLogger.With().Str("component", "courier_webhook").Logger())
+	shipmentRepo := repository.NewShipmentRepository(r.db, zeroLogger.With().Str("component", "shipment").Logger())
+	shipmentTrackingUseCase := usecase.NewShipmentTrackingUseCase(r.db, shipmentRepo, warrantyClaimRepo, logger)
+	// Couriers without a signing secret are left out so their unsigned
+	// payloads are never accepted
+	courierWebhookRegistry := webhook.NewRegistry()
+	for _, courierHandler := range []webhook.WebhookHandler{
+		webhook.NewJNEWebhookHandler(config.AppConfig.CourierWebhooks.JNESecret),
+		webhook.NewSiCepatWebhookHandler(config.AppConfig.CourierWebhooks.SiCepatSecret),
+		webhook.NewNinjaVanWebhookHandler(config.AppConfig.CourierWebhooks.NinjaVanSecret),
+	} {
+		if !courierHandler.HasSecret() {
+			logger.Warn("Courier webhook secret not configured, webhooks disabled", "courier", courierHandler.GetCourierCode())
+			continue
+		}
+		courierWebhookRegistry.Register(courierHandler)
+	}
+	courierWebhookRetryPolicy := entity.CourierWebhookRetryPolicy{
+		MaxAttempts: config.AppConfig.CourierWebhooks.RetryMaxAttempts,
+		BaseDelay:   config.AppConfig.CourierWebhooks.RetryBaseDelay,
+		MaxDelay:    config.AppConfig.CourierWebhooks.RetryMaxDelay,
+	}
+	courierWebhookUseCase := usecase.NewCourierWebhookUseCase(courierWebhookRegistry, courierWebhookEventRepo, orderRepo, warrantyClaimRepo, shipmentTrackingUseCase, courierWebhookRetryPolicy, logger)
+	r.startJob(usecase.NewCourierWebhookRetrier(courierWebhookUseCase, config.AppConfig.CourierWebhooks.RetrySweepInterval, logger))
+	courierWebhookHandler := handler.NewCourierWebhookHandler(courierWebhookUseCase, logger)
+	routes.SetupCourierWebhookRoutes(router, courierWebhookHandler)
+
+	// Sellers book paid orders with couriers that have booking credentials
+	courierBookingRepo := repository.NewCourierBookingRepository(r.db, zeroLogger.With().Str("component", "courier_booking").Logger())
+	var bookers []courier.Booker
+	if config.AppConfig.SiCepatConfig.PickupURL != "" && config.AppConfig.SiCepatConfig.APIKey != "" {
+		bookers = append(bookers, courier.NewSiCepatBooker(courier.SiCepatBookingConfig{
+			PickupURL:      config.AppConfig.SiCepatConfig.PickupURL,
+			APIKey:         config.AppConfig.SiCepatConfig.APIKey,
+			ResiRangeStart: config.AppConfig.SiCepatConfig.ResiRangeStart,
+			ResiRangeEnd:   config.AppConfig.SiCepatConfig.ResiRangeEnd,
+			NextReceipt: func(ctx context.Context) (int64, error) {
+				return courierBookingRepo.NextReceiptSequence(ctx, "sicepat")
+			},
+			OriginMapping:      courier.NewAreaMapping(config.AppConfig.SiCepatMappingOrigin),
+			DestinationMapping: courier.NewAreaMapping(config.AppConfig.SiCepatMappingDestination),
+		}))
+	}
+	if config.AppConfig.JNTConfig.OrderAPIURL != "" {
+		bookers = append(bookers, courier.NewJNTBooker(courier.JNTBookingConfig{
+			OrderAPIURL: config.AppConfig.JNTConfig.OrderAPIURL,
+			Username:    config.AppConfig.JNTConfig.Username,
+			APIKeyName:  config.AppConfig.JNTConfig.APIKeyName,
+			APIKey:      config.AppConfig.JNTConfig.APIKey,
+			Mapping:     courier.NewAreaMapping(config.AppConfig.JNTMapping),
+		}))
+	}
+	if config.AppConfig.SAPXConfig.APIKeyPickup != "" || config.AppConfig.SAPXConfig.APIKeyDropoff != "" {
+		bookers = append(bookers, courier.NewSAPXBooker(courier.SAPXBookingConfig{
+			APIURL:        config.AppConfig.SAPXConfig.APIURL,
+			APIKeyPickup:  config.AppConfig.SAPXConfig.APIKeyPickup,
+			APIKeyDropoff: config.AppConfig.SAPXConfig.APIKeyDropoff,
+			CustomerCode:  config.AppConfig.SAPXConfig.CustomerCodeNonCOD,
+			Mapping:       courier.NewAreaMapping(config.AppConfig.SAPXMapping),
+		}, courier.WithHTTPClient(&http.Client{Timeout: config.AppConfig.SAPXConfig.Timeout})))
+	}
+	courierBookingService := service.NewCourierBookingService(r.db, orderRepo, storefrontRepo, courierBookingRepo, shipmentRepo, bookers, logger)
+	courierBookingHandler := handler.NewCourierBookingHandler(courierBookingService, logger)
+	shippingLabelService := service.NewShippingLabelService(orderRepo, storefrontRepo, courierBookingRepo, logger)
+	shippingLabelHandler := handler.NewShippingLabelHandler(shippingLabelService, storefrontRepo, logger)
+
+	// Customers manage their devices; sellers can sign customers out
+	customerSessionHandler := handler.NewCustomerSessionHandler(customerSessionService, storefrontRepo, logger)
+	blockedIPHandler := handler.NewBlockedIPHandler(customerAuthMiddleware.FraudDetector(), logger)
+	var customerTwoFactorHandler *handler.CustomerTwoFactorHandler
+	if customerTwoFactorService != nil {
+		customerTwoFactorHandler = handler.NewCustomerTwoFactorHandler(customerTwoFactorService, logger)
+	}
+
 	// Setup storefront customer routes
-	routes.SetupStorefrontCustomerRoutes(router, tenantMiddleware, customerAuthMiddleware, customerAuthHandler, addressHandler)
+	routes.SetupStorefrontCustomerRoutes(router, tenantMiddleware, customerAuthMiddleware, customerAuthHandler, addressHandler, storefrontOrderHandler, checkoutHandler, paymentHandler, shippingRateHandler, cartHandler, customerSessionHandler, customerTwoFactorHandler)
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
@@ -335,6 +691,59 @@ func (r *Router) setupAPIRoutes(router *gin.Engine) {
 		admin := v1.Group("/admin")
 		admin.Use(middleware.AuthMiddleware())
 		{
+			// Order management routes
+			orders := admin.Group("/orders")
+			{
+				orders.GET("", orderHandler.ListOrders)
+				orders.POST("", orderHandler.CreateOrder)
+				orders.GET("/stats", orderHandler.GetOrderStatistics)
+				orders.GET("/:id", orderHandler.GetOrder)
+				orders.GET("/:id/history", orderHandler.GetOrderHistory)
+				orders.PUT("/:id/status", orderHandler.UpdateOrderStatus)
+				orders.PUT("/:id/payment", orderHandler.UpdatePaymentStatus)
+				orders.POST("/:id/cancel", orderHandler.CancelOrder)
+				orders.POST("/:id/booking", courierBookingHandler.BookOrder)
+				orders.GET("/:id/booking", courierBookingHandler.GetBooking)
+				orders.GET("/:id/pickup-times", courierBookingHandler.GetPickupTimes)
+				orders.GET("/:id/label", shippingLabelHandler.GetOrderLabel)
+				orders.GET("/labels", shippingLabelHandler.GetDailyLabels)
+			}
+
+			// Customer session kill switches for the seller's storefront
+			admin.DELETE("/customers/:customer_id/sessions", customerSessionHandler.RevokeCustomerSessions)
+			admin.DELETE("/customer-sessions", customerSessionHandler.RevokeStorefrontSessions)
+
+			// Courier webhook deliveries
+			courierWebhooks := admin.Group("/webhooks/couriers")
+			courierWebhooks.Use(middleware.RequireAdmin(userUseCase))
+			{
+				courierWebhooks.GET("/events", courierWebhookHandler.ListEvents)
+				courierWebhooks.GET("/events/:id", courierWebhookHandler.GetEvent)
+				courierWebhooks.POST("/events/:id/replay", courierWebhookHandler.ReplayEvent)
+			}
+
+			// IPs blocked by fraud detection, shared by every replica
+			security := admin.Group("/security")
+			security.Use(middleware.RequireAdmin(userUseCase))
+			{
+				security.GET("/blocked-ips", blockedIPHandler.ListBlockedIPs)
+				security.DELETE("/blocked-ips/:ip", blockedIPHandler.UnblockIP)
+			}
+
+			// Tenant isolation management
+			adminTenant := customerMiddleware.NewAdminTenantMiddleware(tenantResolver)
+			tenants := admin.Group("/tenants")
+			tenants.Use(middleware.RequireAdmin(userUseCase))
+			{
+				tenants.POST("/cache/invalidate", adminTenant.InvalidateCache())
+				tenants.GET("/:storefront_id/stats", adminTenant.TenantStats())
+				tenants.GET("/:storefront_id/migration/eligibility", adminTenant.CheckMigrationEligibility())
+				tenants.POST("/:storefront_id/migrations", adminTenant.MigrateTenant())
+				tenants.GET("/:storefront_id/migrations/latest", adminTenant.GetLatestMigration())
+				tenants.GET("/migrations/:id", adminTenant.GetMigration())
+				tenants.POST("/migrations/:id/rollback", adminTenant.RollbackMigration())
+			}
+
 			warranty := admin.Group("/warranty")
 			{
 				// Barcode management routes
@@ -353,6 +762,17 @@ func (r *Router) setupAPIRoutes(router *gin.Engine) {
 					barcodes.GET("/validate/:barcode_value", warrantyBarcodeHandler.ValidateBarcode)
 				}
 
+				// Warranty policy templates
+				policies := warranty.Group("/policies")
+				{
+					policies.POST("", warrantyPolicyHandler.CreatePolicy)
+					policies.GET("", warrantyPolicyHandler.ListPolicies)
+					policies.GET("/resolve", warrantyPolicyHandler.ResolvePolicy)
+					policies.GET("/:id", warrantyPolicyHandler.GetPolicy)
+					policies.PUT("/:id", warrantyPolicyHandler.UpdatePolicy)
+					policies.DELETE("/:id", warrantyPolicyHandler.DeletePolicy)
+				}
+
 				// Batch generation routes
 				batches := warranty.Group("/claims/:id/batches")
 				{
@@ -382,6 +802,7 @@ func (r *Router) setupAPIRoutes(router *gin.Engine) {
 					claims.POST("/:id/validate", warrantyClaimHandler.ValidateClaim)
 					claims.POST("/:id/reject", warrantyClaimHandler.RejectClaim)
 					claims.POST("/:id/assign", warrantyClaimHandler.AssignTechnician)
+					claims.POST("/:id/auto-assign", warrantyClaimHandler.AutoAssignTechnician)
 					claims.POST("/:id/complete", warrantyClaimHandler.CompleteClaim)
 
 					// Claim management
@@ -412,23 +833,57 @@ func (r *Router) setupAPIRoutes(router *gin.Engine) {
 					}
 
 					// Repair ticket management routes
-					repairTickets := claims.Group("/:id/repair-tickets")
+					claimRepairTickets := claims.Group("/:id/repair-tickets")
 					{
-						repairTickets.POST("/", repairTicketHandler.CreateRepairTicket)
-						repairTickets.GET("/", repairTicketHandler.ListRepairTickets)
-						repairTickets.GET("/:ticketId", repairTicketHandler.GetRepairTicket)
-						repairTickets.PUT("/:ticketId", repairTicketHandler.UpdateRepairTicket)
-						repairTickets.PUT("/:ticketId/assign", repairTicketHandler.AssignTechnician)
-						repairTickets.PUT("/:ticketId/complete", repairTicketHandler.CompleteRepair)
-						repairTickets.PUT("/:ticketId/quality-check", repairTicketHandler.QualityCheck)
-						repairTickets.GET("/statistics", repairTicketHandler.GetRepairStatistics)
+						claimRepairTickets.POST("/", repairTicketHandler.CreateRepairTicket)
+						claimRepairTickets.GET("/", repairTicketHandler.ListClaimRepairTickets)
 					}
 				}
+
+				// Repair ticket workflow routes
+				repairTickets := warranty.Group("/repair-tickets")
+				{
+					repairTickets.GET("/", repairTicketHandler.ListRepairTickets)
+					repairTickets.GET("/statistics", repairTicketHandler.GetRepairStatistics)
+					repairTickets.GET("/:ticketId", repairTicketHandler.GetRepairTicket)
+					repairTickets.PUT("/:ticketId", repairTicketHandler.UpdateRepairTicket)
+					repairTickets.PUT("/:ticketId/assign", repairTicketHandler.AssignTechnician)
+					repairTickets.PUT("/:ticketId/start", repairTicketHandler.StartRepair)
+					repairTickets.POST("/:ticketId/parts", repairTicketHandler.AddPartUsage)
+					repairTickets.POST("/:ticketId/spare-parts", repairTicketHandler.RequestSparePart)
+					repairTickets.PUT("/:ticketId/waiting-parts", repairTicketHandler.MarkWaitingForParts)
+					repairTickets.PUT("/:ticketId/resume", repairTicketHandler.ResumeRepair)
+					repairTickets.PUT("/:ticketId/complete", repairTicketHandler.CompleteRepair)
+					repairTickets.PUT("/:ticketId/quality-check", repairTicketHandler.QualityCheck)
+					repairTickets.PUT("/:ticketId/cancel", repairTicketHandler.CancelRepairTicket)
+				}
+
+				// Technician roster routes
+				technicians := warranty.Group("/technicians")
+				{
+					technicians.GET("/", technicianHandler.ListTechnicians)
+					technicians.POST("/", technicianHandler.CreateTechnician)
+					technicians.GET("/workload", technicianHandler.GetTechnicianWorkload)
+					technicians.GET("/:technicianId", technicianHandler.GetTechnician)
+					technicians.PUT("/:technicianId", technicianHandler.UpdateTechnician)
+					technicians.DELETE("/:technicianId", technicianHandler.DeleteTechnician)
+				}
+
+				// Spare parts inventory routes
+				spareParts := warranty.Group("/spare-parts")
+				{
+					spareParts.GET("/", sparePartHandler.ListSpareParts)
+					spareParts.POST("/", sparePartHandler.CreateSparePart)
+					spareParts.GET("/:partId", sparePartHandler.GetSparePart)
+					spareParts.PUT("/:partId", sparePartHandler.UpdateSparePart)
+					spareParts.DELETE("/:partId", sparePartHandler.DeleteSparePart)
+					spareParts.POST("/:partId/stock", sparePartHandler.AdjustStock)
+				}
 			}
 		}
 
 		// Initialize customer authentication middleware for public and customer routes
-		customerAuth := customerMiddleware.NewCustomerAuthMiddleware()
+		customerAuth := customerMiddleware.NewCustomerAuthMiddleware(customerSessionRepo, captchaPolicy, rateLimitPolicy)
 
 		// Public API routes (no authentication required) - Phase 8 Implementation
 		public := v1.Group("/public")
@@ -436,8 +891,11 @@ func (r *Router) setupAPIRoutes(router *gin.Engine) {
 		public.Use(customerAuth.SecurityHeadersMiddleware())
 		public.Use(customerAuth.CORSMiddleware())
 		{
-			// Public warranty validation endpoints
-			routes.PublicWarrantyRoutes(public)
+			// Public warranty validation endpoints. Barcodes are scoped to the
+			// storefront when one is resolved, and IPs guessing barcodes are blocked.
+			routes.PublicWarrantyRoutes(public, publicWarrantyHandler,
+				tenantMiddleware.OptionalTenant(),
+				customerAuth.EnumerationGuard("warranty barcode", config.AppConfig.WarrantyLookup.MaxMisses, config.AppConfig.WarrantyLookup.MissWindow))
 		}
 
 		// Customer API routes (authentication required for customers) - Phase 8 Implementation
@@ -451,10 +909,22 @@ func (r *Router) setupAPIRoutes(router *gin.Engine) {
 		// Public customer endpoints (no authentication required)
 		customerPublic := customer.Group("/public")
 		{
-			// Customer warranty registration and management endpoints (public access)
-			routes.CustomerWarrantyRoutes(customerPublic)
+			// Customer warranty management endpoints (public access).
+			// IPs guessing warranties are blocked like public lookups.
+			routes.CustomerWarrantyRoutes(customerPublic, warrantyRegistrationUseCase, logger,
+				tenantMiddleware.OptionalTenant(),
+				customerAuth.EnumerationGuard("warranty barcode", config.AppConfig.WarrantyLookup.MaxMisses, config.AppConfig.WarrantyLookup.MissWindow))
 		}
+
+		// Warranty registration binds the barcode to the signed-in customer.
+		// IPs registering unknown barcodes are blocked like public lookups.
+		routes.SetupStorefrontWarrantyRoutes(v1, tenantMiddleware, customerAuth, warrantyRegistrationUseCase, logger,
+			customerAuth.EnumerationGuard("warranty barcode", config.AppConfig.WarrantyLookup.MaxMisses, config.AppConfig.WarrantyLookup.MissWindow))
 		
+		// Claim and mobile warranty endpoints backed by real data live under the customer's storefront
+		routes.SetupStorefrontClaimRoutes(v1, tenantMiddleware, customerAuth, shipmentTrackingUseCase)
+		routes.SetupStorefrontMobileWarrantyRoutes(v1, tenantMiddleware, customerAuth, warrantyRegistrationUseCase, logger)
+
 		// Protected customer endpoints (authentication required)
 		customerProtected := customer.Group("/protected")
 		customerProtected.Use(customerAuth.CustomerAuthRequired())
@@ -463,10 +933,10 @@ func (r *Router) setupAPIRoutes(router *gin.Engine) {
 			routes.CustomerClaimRoutes(customerProtected)
 			
 			// Customer claim tracking endpoints
-			routes.SetupCustomerTrackingRoutes(customerProtected)
+			routes.SetupCustomerTrackingRoutes(customerProtected, shipmentTrackingUseCase)
 			
 			// Mobile warranty endpoints for mobile app integration
-			routes.MobileWarrantyRoutes(customerProtected)
+			routes.MobileWarrantyRoutes(customerProtected, warrantyRegistrationUseCase, logger)
 		}
 	}
 }
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/handler"
+)
+
+// SetupCourierWebhookRoutes sets up the endpoints couriers push tracking updates to.
+// Requests are authenticated by the courier's HMAC signature, not by a user token.
+func SetupCourierWebhookRoutes(router *gin.Engine, courierWebhookHandler *handler.CourierWebhookHandler) {
+	couriers := router.Group("/webhooks/couriers")
+	{
+		couriers.POST("/:courier", courierWebhookHandler.HandleWebhook)
+	}
+}
@@ -2,13 +2,15 @@ package routes
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
 	"github.com/kirimku/smartseller-backend/internal/interfaces/api/handlers"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
 )
 
 // SetupCustomerTrackingRoutes sets up routes for customer claim tracking
-func SetupCustomerTrackingRoutes(router *gin.RouterGroup) {
+func SetupCustomerTrackingRoutes(router *gin.RouterGroup, trackingUseCase usecase.ShipmentTrackingUseCase) {
 	wsHandler := handlers.NewWebSocketHandler()
-	trackingHandler := handlers.NewCustomerTrackingHandler(wsHandler)
+	trackingHandler := handlers.NewCustomerTrackingHandler(wsHandler, trackingUseCase)
 
 	// Customer claim tracking routes
 	tracking := router.Group("/claims/:id")
@@ -28,4 +30,23 @@ func SetupCustomerTrackingRoutes(router *gin.RouterGroup) {
 	
 	// WebSocket endpoint for real-time updates
 	router.GET("/ws", wsHandler.HandleWebSocket)
-}
\ No newline at end of file
+}
+
+// SetupStorefrontClaimRoutes sets up the claim endpoints of signed-in storefront
+// customers. The storefront is resolved from the path, so customer tokens are
+// checked against it, and a pending second factor is enforced.
+func SetupStorefrontClaimRoutes(
+	router *gin.RouterGroup,
+	tenantMiddleware *middleware.TenantMiddleware,
+	customerAuth *middleware.CustomerAuthMiddleware,
+	trackingUseCase usecase.ShipmentTrackingUseCase,
+) {
+	trackingHandler := handlers.NewCustomerTrackingHandler(handlers.NewWebSocketHandler(), trackingUseCase)
+
+	storefront := router.Group("/storefront/:slug")
+	storefront.Use(tenantMiddleware.ResolveTenant(), customerAuth.CustomerAuthRequired(), customerAuth.TwoFactorEnforced())
+	{
+		// Get the courier tracking timeline of the return shipment
+		storefront.GET("/claims/:id/shipment", trackingHandler.GetClaimShipment)
+	}
+}
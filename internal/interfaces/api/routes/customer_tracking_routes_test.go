@@ -0,0 +1,155 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+	"github.com/kirimku/smartseller-backend/internal/domain/model"
+	"github.com/kirimku/smartseller-backend/internal/domain/repository"
+	"github.com/kirimku/smartseller-backend/internal/infrastructure/tenant"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
+)
+
+// stubTenantResolver resolves a single storefront by slug
+type stubTenantResolver struct {
+	tenant.TenantResolver
+	storefront *entity.Storefront
+}
+
+func (r *stubTenantResolver) GetStorefrontBySlug(ctx context.Context, slug string) (*entity.Storefront, error) {
+	if slug != r.storefront.Slug {
+		return nil, nil
+	}
+	return r.storefront, nil
+}
+
+func (r *stubTenantResolver) CreateTenantContext(storefront *entity.Storefront) *tenant.TenantContext {
+	return &tenant.TenantContext{
+		StorefrontID:   storefront.ID,
+		StorefrontSlug: storefront.Slug,
+		SellerID:       storefront.SellerID,
+	}
+}
+
+// stubSessionRepository serves a single customer session
+type stubSessionRepository struct {
+	repository.CustomerSessionRepository
+	session *repository.CustomerSession
+}
+
+func (r *stubSessionRepository) GetByID(ctx context.Context, storefrontID, sessionID uuid.UUID) (*repository.CustomerSession, error) {
+	if r.session.ID != sessionID || r.session.StorefrontID != storefrontID {
+		return nil, nil
+	}
+	return r.session, nil
+}
+
+func (r *stubSessionRepository) UpdateLastUsed(ctx context.Context, storefrontID, sessionID uuid.UUID) error {
+	return nil
+}
+
+// stubTrackingUseCase returns a fixed timeline for the customer's claim
+type stubTrackingUseCase struct {
+	usecase.ShipmentTrackingUseCase
+	customerID uuid.UUID
+	claimID    uuid.UUID
+}
+
+func (u *stubTrackingUseCase) GetClaimTracking(ctx context.Context, customerID, claimID uuid.UUID) (*model.TrackingInfo, error) {
+	if customerID != u.customerID || claimID != u.claimID {
+		return nil, assert.AnError
+	}
+	return &model.TrackingInfo{TrackingNumber: "JNE123", Status: "in_transit"}, nil
+}
+
+// storefrontRoutesFixture serves storefront customer routes for one signed-in customer
+type storefrontRoutesFixture struct {
+	router           *gin.Engine
+	tenantMiddleware *middleware.TenantMiddleware
+	auth             *middleware.CustomerAuthMiddleware
+	storefront       *entity.Storefront
+	session          *repository.CustomerSession
+}
+
+func newStorefrontRoutesFixture() *storefrontRoutesFixture {
+	gin.SetMode(gin.TestMode)
+
+	storefront := &entity.Storefront{ID: uuid.New(), SellerID: uuid.New(), Slug: "acme"}
+	session := &repository.CustomerSession{
+		ID:           uuid.New(),
+		CustomerID:   uuid.New(),
+		StorefrontID: storefront.ID,
+		ExpiresAt:    time.Now().Add(time.Hour),
+		LastUsedAt:   time.Now(),
+	}
+
+	return &storefrontRoutesFixture{
+		router:           gin.New(),
+		tenantMiddleware: middleware.NewTenantMiddleware(&stubTenantResolver{storefront: storefront}, "localhost"),
+		auth:             middleware.NewCustomerAuthMiddleware(&stubSessionRepository{session: session}, nil, nil),
+		storefront:       storefront,
+		session:          session,
+	}
+}
+
+func (f *storefrontRoutesFixture) request(t *testing.T, method, path, body string, twoFactorAuth bool) *httptest.ResponseRecorder {
+	token, err := f.auth.CreateCustomerToken(f.session.CustomerID.String(), f.storefront.ID.String(), "buyer@example.com",
+		f.session.ID.String(), "access", nil, twoFactorAuth)
+	require.NoError(t, err)
+	f.session.SessionToken = middleware.HashCustomerToken(token)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Host = "localhost"
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+func newClaimRoutesFixture() (*storefrontRoutesFixture, string) {
+	f := newStorefrontRoutesFixture()
+	claimID := uuid.New()
+	SetupStorefrontClaimRoutes(f.router.Group("/api/v1"), f.tenantMiddleware, f.auth,
+		&stubTrackingUseCase{customerID: f.session.CustomerID, claimID: claimID})
+
+	return f, "/api/v1/storefront/acme/claims/" + claimID.String() + "/shipment"
+}
+
+func TestStorefrontClaimRoutes_ShipmentReachesHandler(t *testing.T) {
+	f, path := newClaimRoutesFixture()
+
+	w := f.request(t, http.MethodGet, path, "", false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "JNE123")
+}
+
+func TestStorefrontClaimRoutes_RejectsTokensOfOtherStorefronts(t *testing.T) {
+	f, path := newClaimRoutesFixture()
+	f.storefront = &entity.Storefront{ID: uuid.New(), Slug: "other"}
+
+	w := f.request(t, http.MethodGet, path, "", false)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestStorefrontClaimRoutes_EnforcesPendingTwoFactor(t *testing.T) {
+	f, path := newClaimRoutesFixture()
+	f.session.TwoFactorRequired = true
+
+	w := f.request(t, http.MethodGet, path, "", false)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = f.request(t, http.MethodGet, path, "", true)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -1,20 +1,23 @@
 package routes
 
 import (
+	"log/slog"
+
 	"github.com/gin-gonic/gin"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
 	"github.com/kirimku/smartseller-backend/internal/interfaces/api/handlers"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
 )
 
-// CustomerWarrantyRoutes sets up customer warranty routes
-func CustomerWarrantyRoutes(router *gin.RouterGroup) {
-	handler := handlers.NewCustomerWarrantyHandler()
+// CustomerWarrantyRoutes sets up customer warranty routes. The middlewares run
+// in front of every route, for tenant resolution and barcode enumeration guarding.
+func CustomerWarrantyRoutes(router *gin.RouterGroup, registrationUseCase usecase.WarrantyRegistrationUseCase, logger *slog.Logger, middlewares ...gin.HandlerFunc) {
+	handler := handlers.NewCustomerWarrantyHandler(registrationUseCase, logger)
 
 	// Customer warranty registration and management routes
 	warranties := router.Group("/warranties")
+	warranties.Use(middlewares...)
 	{
-		// Register a new warranty
-		warranties.POST("/register", handler.RegisterWarranty)
-		
 		// List customer warranties with filtering and pagination
 		warranties.GET("", handler.GetWarranties)
 		
@@ -24,4 +27,27 @@ func CustomerWarrantyRoutes(router *gin.RouterGroup) {
 		// Update warranty information (customer details, preferences)
 		warranties.PUT("/:id", handler.UpdateWarranty)
 	}
-}
\ No newline at end of file
+}
+
+// SetupStorefrontWarrantyRoutes sets up warranty registration under the
+// customer's storefront. Warranties are registered to the signed-in customer,
+// so the route requires a customer token of the storefront in the path. The
+// middlewares run after authentication, e.g. for barcode enumeration guarding.
+func SetupStorefrontWarrantyRoutes(
+	router *gin.RouterGroup,
+	tenantMiddleware *middleware.TenantMiddleware,
+	customerAuth *middleware.CustomerAuthMiddleware,
+	registrationUseCase usecase.WarrantyRegistrationUseCase,
+	logger *slog.Logger,
+	middlewares ...gin.HandlerFunc,
+) {
+	handler := handlers.NewCustomerWarrantyHandler(registrationUseCase, logger)
+
+	storefront := router.Group("/storefront/:slug")
+	storefront.Use(tenantMiddleware.ResolveTenant(), customerAuth.CustomerAuthRequired(), customerAuth.TwoFactorEnforced())
+	storefront.Use(middlewares...)
+	{
+		// Register a new warranty
+		storefront.POST("/warranties/register", handler.RegisterWarranty)
+	}
+}
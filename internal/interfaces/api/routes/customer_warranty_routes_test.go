@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+)
+
+// stubWarrantyRegistrationUseCase registers warranties for the expected customer
+type stubWarrantyRegistrationUseCase struct {
+	usecase.WarrantyRegistrationUseCase
+	storefrontID uuid.UUID
+	customerID   uuid.UUID
+}
+
+func (u *stubWarrantyRegistrationUseCase) RegisterWarranty(ctx context.Context, storefrontID, customerID uuid.UUID, req *dto.CustomerWarrantyRegistrationRequest, receipt *usecase.WarrantyReceiptUpload) (*dto.CustomerWarrantyRegistrationResponse, error) {
+	if storefrontID != u.storefrontID || customerID != u.customerID {
+		return nil, assert.AnError
+	}
+	return &dto.CustomerWarrantyRegistrationResponse{BarcodeValue: req.BarcodeValue}, nil
+}
+
+const warrantyRegistrationBody = `{"barcode_value":"WB-2024-001234567","product_sku":"SKU-1","serial_number":"SN1",` +
+	`"purchase_date":"2024-01-15T00:00:00Z","retailer_name":"TechStore"}`
+
+func newWarrantyRoutesFixture() *storefrontRoutesFixture {
+	f := newStorefrontRoutesFixture()
+	SetupStorefrontWarrantyRoutes(f.router.Group("/api/v1"), f.tenantMiddleware, f.auth,
+		&stubWarrantyRegistrationUseCase{storefrontID: f.storefront.ID, customerID: f.session.CustomerID}, slog.Default())
+	return f
+}
+
+func TestStorefrontWarrantyRoutes_RegistersForSignedInCustomer(t *testing.T) {
+	f := newWarrantyRoutesFixture()
+
+	w := f.request(t, http.MethodPost, "/api/v1/storefront/acme/warranties/register", warrantyRegistrationBody, false)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), "WB-2024-001234567")
+}
+
+func TestStorefrontWarrantyRoutes_RequiresCustomerToken(t *testing.T) {
+	f := newWarrantyRoutesFixture()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/storefront/acme/warranties/register", strings.NewReader(warrantyRegistrationBody))
+	req.Host = "localhost"
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
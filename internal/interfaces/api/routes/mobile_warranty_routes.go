@@ -1,13 +1,17 @@
 package routes
 
 import (
+	"log/slog"
+
 	"github.com/gin-gonic/gin"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
 	"github.com/kirimku/smartseller-backend/internal/interfaces/api/handlers"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
 )
 
 // MobileWarrantyRoutes sets up mobile warranty routes for mobile app integration
-func MobileWarrantyRoutes(router *gin.RouterGroup) {
-	handler := handlers.NewMobileWarrantyHandler()
+func MobileWarrantyRoutes(router *gin.RouterGroup, registrationUseCase usecase.WarrantyRegistrationUseCase, logger *slog.Logger) {
+	handler := handlers.NewMobileWarrantyHandler(registrationUseCase, logger)
 
 	// Mobile-specific routes under /mobile prefix
 	mobile := router.Group("/mobile")
@@ -15,9 +19,6 @@ func MobileWarrantyRoutes(router *gin.RouterGroup) {
 		// Mobile warranty scanning routes
 		warranties := mobile.Group("/warranties")
 		{
-			// QR/Barcode scanning endpoint
-			warranties.POST("/scan", handler.ScanWarranty)
-			
 			// Camera permissions check
 			warranties.GET("/camera-permissions", handler.CheckCameraPermissions)
 		}
@@ -46,4 +47,24 @@ func MobileWarrantyRoutes(router *gin.RouterGroup) {
 			optimize.GET("/:endpoint", handler.GetMobileOptimizedResponse)
 		}
 	}
-}
\ No newline at end of file
+}
+
+// SetupStorefrontMobileWarrantyRoutes sets up the mobile warranty endpoints that
+// act on the customer's storefront. The storefront is resolved from the path,
+// so customer tokens are checked against it, and a pending second factor is enforced.
+func SetupStorefrontMobileWarrantyRoutes(
+	router *gin.RouterGroup,
+	tenantMiddleware *middleware.TenantMiddleware,
+	customerAuth *middleware.CustomerAuthMiddleware,
+	registrationUseCase usecase.WarrantyRegistrationUseCase,
+	logger *slog.Logger,
+) {
+	handler := handlers.NewMobileWarrantyHandler(registrationUseCase, logger)
+
+	storefront := router.Group("/storefront/:slug")
+	storefront.Use(tenantMiddleware.ResolveTenant(), customerAuth.CustomerAuthRequired(), customerAuth.TwoFactorEnforced())
+	{
+		// QR/Barcode scanning endpoint
+		storefront.POST("/mobile/warranties/scan", handler.ScanWarranty)
+	}
+}
@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kirimku/smartseller-backend/internal/application/dto"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
+	"github.com/kirimku/smartseller-backend/internal/domain/entity"
+)
+
+// stubRegistrationUseCase answers scans made on the expected storefront
+type stubRegistrationUseCase struct {
+	usecase.WarrantyRegistrationUseCase
+	storefrontID uuid.UUID
+	customerID   uuid.UUID
+}
+
+func (u *stubRegistrationUseCase) ScanWarranty(ctx context.Context, storefrontID, customerID uuid.UUID, req *dto.MobileWarrantyScanRequest) (*dto.MobileWarrantyScanResponse, error) {
+	if storefrontID != u.storefrontID || customerID != u.customerID {
+		return nil, assert.AnError
+	}
+	return &dto.MobileWarrantyScanResponse{Success: true, ScanType: req.ScanType, ScannedData: req.ScannedData}, nil
+}
+
+func TestStorefrontMobileWarrantyRoutes_ScanReachesHandler(t *testing.T) {
+	f := newStorefrontRoutesFixture()
+	SetupStorefrontMobileWarrantyRoutes(f.router.Group("/api/v1"), f.tenantMiddleware, f.auth,
+		&stubRegistrationUseCase{storefrontID: f.storefront.ID, customerID: f.session.CustomerID}, slog.Default())
+
+	body := `{"scan_type":"qr_code","scanned_data":"WB-2024-001234567"}`
+	w := f.request(t, http.MethodPost, "/api/v1/storefront/acme/mobile/warranties/scan", body, false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "WB-2024-001234567")
+
+	// Tokens issued by another storefront are refused
+	f.storefront = &entity.Storefront{ID: uuid.New(), Slug: "other"}
+	w = f.request(t, http.MethodPost, "/api/v1/storefront/acme/mobile/warranties/scan", body, false)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
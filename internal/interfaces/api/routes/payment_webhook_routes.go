@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/handler"
+)
+
+// SetupPaymentWebhookRoutes sets up the endpoints payment gateways report payment
+// outcomes to. Requests are authenticated by the gateway's callback token, not by a user token.
+func SetupPaymentWebhookRoutes(router *gin.Engine, paymentHandler *handler.PaymentHandler) {
+	payments := router.Group("/webhooks/payments")
+	{
+		payments.POST("/:gateway", paymentHandler.HandleCallback)
+	}
+}
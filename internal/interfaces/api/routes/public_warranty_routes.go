@@ -5,12 +5,12 @@ import (
 	"github.com/kirimku/smartseller-backend/internal/interfaces/api/handler"
 )
 
-// PublicWarrantyRoutes sets up public warranty routes
-func PublicWarrantyRoutes(router *gin.RouterGroup) {
-	publicWarrantyHandler := handler.NewPublicWarrantyHandler()
-
+// PublicWarrantyRoutes sets up public warranty routes. The middlewares run in
+// front of every route, for tenant resolution and barcode enumeration guarding.
+func PublicWarrantyRoutes(router *gin.RouterGroup, publicWarrantyHandler *handler.PublicWarrantyHandler, middlewares ...gin.HandlerFunc) {
 	// Public warranty endpoints - no authentication required
 	publicWarranty := router.Group("/warranty")
+	publicWarranty.Use(middlewares...)
 	{
 		// POST endpoints for warranty operations
 		publicWarranty.POST("/validate", publicWarrantyHandler.ValidateWarranty)
@@ -22,4 +22,4 @@ func PublicWarrantyRoutes(router *gin.RouterGroup) {
 		publicWarranty.GET("/:barcode", publicWarrantyHandler.GetWarrantyByBarcode)
 		publicWarranty.GET("/:barcode/product", publicWarrantyHandler.GetProductByBarcode)
 	}
-}
\ No newline at end of file
+}
@@ -14,6 +14,13 @@ func SetupStorefrontCustomerRoutes(
 	customerAuthMiddleware *middleware.CustomerAuthMiddleware,
 	customerAuthHandler *handlers.CustomerAuthHandler,
 	addressHandler *handler.AddressHandler,
+	orderHandler *handler.StorefrontOrderHandler,
+	checkoutHandler *handler.CheckoutHandler,
+	paymentHandler *handler.PaymentHandler,
+	shippingRateHandler *handler.ShippingRateHandler,
+	cartHandler *handler.CartHandler,
+	sessionHandler *handler.CustomerSessionHandler,
+	twoFactorHandler *handler.CustomerTwoFactorHandler,
 ) {
 	// Storefront-specific customer routes with tenant resolution
 	api := router.Group("/api/v1")
@@ -21,12 +28,13 @@ func SetupStorefrontCustomerRoutes(
 		storefront := api.Group("/storefront/:slug")
 		storefront.Use(tenantMiddleware.ResolveTenant())
 		{
-			// Public authentication endpoints (no auth required)
+			// Public authentication endpoints (no auth required). Registration and
+			// password resets attract bots, so they go through CAPTCHA.
 			auth := storefront.Group("/auth")
 			{
-				auth.POST("/register", customerAuthHandler.RegisterCustomer)
+				auth.POST("/register", customerAuthMiddleware.CaptchaRequired(), customerAuthHandler.RegisterCustomer)
 				auth.POST("/login", customerAuthHandler.LoginCustomer)
-				auth.POST("/forgot-password", customerAuthHandler.RequestPasswordReset)
+				auth.POST("/forgot-password", customerAuthMiddleware.CaptchaRequired(), customerAuthHandler.RequestPasswordReset)
 				auth.POST("/reset-password", customerAuthHandler.ConfirmPasswordReset)
 				auth.POST("/verify-email", customerAuthHandler.VerifyEmail)
 				auth.POST("/resend-verification", customerAuthHandler.ResendVerificationEmail)
@@ -40,11 +48,26 @@ func SetupStorefrontCustomerRoutes(
 				public.POST("/auth/refresh", customerAuthHandler.RefreshToken)
 				public.POST("/auth/logout", customerAuthHandler.LogoutCustomer)
 			}
+
+			// Two-factor endpoints stay reachable while verification is pending.
+			// They are left out when no encryption key is configured.
+			if twoFactorHandler != nil {
+				twoFactor := storefront.Group("/2fa")
+				twoFactor.Use(customerAuthMiddleware.CustomerAuthRequired())
+				{
+					twoFactor.GET("", twoFactorHandler.GetStatus)
+					twoFactor.POST("/enroll", twoFactorHandler.BeginEnrollment)
+					twoFactor.POST("/enroll/confirm", twoFactorHandler.ConfirmEnrollment)
+					twoFactor.POST("/verify", twoFactorHandler.Verify)
+					twoFactor.POST("/disable", twoFactorHandler.Disable)
+				}
+			}
 		}
 		
-		// Protected customer endpoints (require authentication)
+		// Protected customer endpoints (require authentication and, when the
+		// customer enrolled or the storefront requires it, a verified second factor)
 		protected := storefront.Group("")
-		protected.Use(customerAuthMiddleware.CustomerAuthRequired())
+		protected.Use(customerAuthMiddleware.CustomerAuthRequired(), customerAuthMiddleware.TwoFactorEnforced())
 		{
 			// Profile management - using customer auth handler for basic profile operations
 			profile := protected.Group("/profile")
@@ -56,6 +79,13 @@ func SetupStorefrontCustomerRoutes(
 				// profile.POST("/upload-avatar", customerHandler.UploadAvatar)
 			}
 			
+			// Devices signed in to the customer's account
+			sessions := protected.Group("/sessions")
+			{
+				sessions.GET("", sessionHandler.ListMySessions)
+				sessions.DELETE("/:id", sessionHandler.RevokeMySession)
+			}
+
 			// Address management - using dedicated address handler
 			addresses := protected.Group("/addresses")
 			{
@@ -75,6 +105,15 @@ func SetupStorefrontCustomerRoutes(
 				addressUtils.POST("/geocode", addressHandler.GeocodeAddress)
 				addressUtils.GET("/nearby", addressHandler.GetNearbyAddresses)
 			}
+
+			// Order history and tracking for the authenticated customer
+			orders := protected.Group("/orders")
+			{
+				orders.GET("", orderHandler.GetCustomerOrders)
+				orders.GET("/:id", orderHandler.GetOrder)
+				orders.GET("/:id/status", orderHandler.GetOrderStatus)
+				orders.POST("/:id/cancel", orderHandler.CancelOrder)
+			}
 		}
 		
 		// Optional authentication endpoints (for guest users)
@@ -100,32 +139,26 @@ func SetupStorefrontCustomerRoutes(
 			// {
 			//     search.GET("/products", searchHandler.SearchStorefrontProducts)
 			// }
+
+			// Carts belong to the customer or, for guests, to the X-Cart-Session
+			cart := optional.Group("/cart")
+			{
+				cart.GET("", cartHandler.GetCart)
+				cart.DELETE("", cartHandler.ClearCart)
+				cart.POST("/items", cartHandler.AddItem)
+				cart.PUT("/items/:id", cartHandler.UpdateItem)
+				cart.DELETE("/items/:id", cartHandler.RemoveItem)
+			}
+
+			// Checkout is open to guests when the storefront enables guest checkout
+			checkout := optional.Group("/checkout")
+			{
+				checkout.POST("", checkoutHandler.CreateOrder)
+				checkout.POST("/payment", paymentHandler.CreatePayment)
+				checkout.POST("/shipping-rates", shippingRateHandler.QuoteRates)
+				// TODO: Implement confirmation endpoint
+				// checkout.GET("/confirmation/:id", checkoutHandler.GetOrderConfirmation)
+			}
 		}
-		
-		// TODO: Implement shopping cart endpoints
-		// cart := protected.Group("/cart")
-		// {
-		//     cart.GET("", cartHandler.GetCart)
-		//     cart.POST("/items", cartHandler.AddItem)
-		//     cart.PUT("/items/:id", cartHandler.UpdateItem)
-		//     cart.DELETE("/items/:id", cartHandler.RemoveItem)
-		// }
-		
-		// TODO: Implement order endpoints
-		// orders := protected.Group("/orders")
-		// {
-		//     orders.GET("", orderHandler.GetCustomerOrders)
-		//     orders.POST("", orderHandler.CreateOrder)
-		//     orders.GET("/:id", orderHandler.GetOrder)
-		//     orders.GET("/:id/status", orderHandler.GetOrderStatus)
-		// }
-		
-		// TODO: Implement checkout endpoints
-		// checkout := protected.Group("/checkout")
-		// {
-		//     checkout.POST("", checkoutHandler.CreateOrder)
-		//     checkout.POST("/payment", checkoutHandler.ProcessPayment)
-		//     checkout.GET("/confirmation/:id", checkoutHandler.GetOrderConfirmation)
-		// }
 	}
-}
\ No newline at end of file
+}
@@ -8,7 +8,9 @@ import (
 
 	"github.com/kirimku/smartseller-backend/internal/application/dto"
 	"github.com/kirimku/smartseller-backend/internal/application/service"
+	"github.com/kirimku/smartseller-backend/internal/application/usecase"
 	"github.com/kirimku/smartseller-backend/internal/domain/errors"
+	"github.com/kirimku/smartseller-backend/internal/interfaces/api/middleware"
 )
 
 // CustomerAuthHandler handles customer authentication endpoints
@@ -17,6 +19,7 @@ type CustomerAuthHandler struct {
 	customerPasswordResetService         *service.CustomerPasswordResetService
 	customerEmailVerificationService    *service.CustomerEmailVerificationService
 	validationService                    service.ValidationService
+	cartUseCase                          usecase.CartUseCase
 }
 
 // NewCustomerAuthHandler creates a new customer authentication handler
@@ -25,12 +28,14 @@ func NewCustomerAuthHandler(
 	passwordResetService *service.CustomerPasswordResetService,
 	emailVerificationService *service.CustomerEmailVerificationService,
 	validationService service.ValidationService,
+	cartUseCase usecase.CartUseCase,
 ) *CustomerAuthHandler {
 	return &CustomerAuthHandler{
 		customerService:                   customerService,
 		customerPasswordResetService:      passwordResetService,
 		customerEmailVerificationService: emailVerificationService,
 		validationService:                 validationService,
+		cartUseCase:                       cartUseCase,
 	}
 }
 
@@ -100,6 +105,9 @@ func (h *CustomerAuthHandler) LoginCustomer(c *gin.Context) {
 		return
 	}
 
+	req.UserAgent = c.Request.UserAgent()
+	req.IPAddress = c.ClientIP()
+
 	response, err := h.customerService.AuthenticateCustomer(c.Request.Context(), &req)
 	if err != nil {
 		h.handleServiceError(c, err)
@@ -109,6 +117,8 @@ func (h *CustomerAuthHandler) LoginCustomer(c *gin.Context) {
 	// Set auth cookies
 	h.setAuthCookies(c, response.AccessToken, response.RefreshToken)
 
+	h.mergeGuestCart(c, response)
+
 	c.JSON(http.StatusOK, dto.SuccessResponse{
 		Success: true,
 		Message: "Login successful",
@@ -118,6 +128,16 @@ func (h *CustomerAuthHandler) LoginCustomer(c *gin.Context) {
 
 // LogoutCustomer handles customer logout
 func (h *CustomerAuthHandler) LogoutCustomer(c *gin.Context) {
+	// Revoke the session when the request is authenticated
+	if sessionID, exists := c.Get("session_id"); exists {
+		if id, err := uuid.Parse(sessionID.(string)); err == nil {
+			if err := h.customerService.LogoutCustomer(c.Request.Context(), id); err != nil {
+				h.handleServiceError(c, err)
+				return
+			}
+		}
+	}
+
 	// Clear auth cookies
 	h.clearAuthCookies(c)
 
@@ -147,8 +167,20 @@ func (h *CustomerAuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement token refresh logic
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "Token refresh not implemented"})
+	response, err := h.customerService.RefreshToken(c.Request.Context(), &req)
+	if err != nil {
+		h.clearAuthCookies(c)
+		h.handleServiceError(c, err)
+		return
+	}
+
+	h.setAuthCookies(c, response.AccessToken, response.RefreshToken)
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Token refreshed successfully",
+		Data:    response,
+	})
 }
 
 // RequestPasswordReset handles password reset requests
@@ -210,7 +242,14 @@ func (h *CustomerAuthHandler) ConfirmPasswordReset(c *gin.Context) {
 		return
 	}
 
-	err := h.customerPasswordResetService.ResetPassword(c.Request.Context(), req.Token, req.NewPassword)
+	// Get storefront ID from context
+	storefrontID, exists := c.Get("storefront_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Storefront context required"})
+		return
+	}
+
+	err := h.customerPasswordResetService.ResetPassword(c.Request.Context(), storefrontID.(uuid.UUID), req.Token, req.NewPassword)
 	if err != nil {
 		h.handleServiceError(c, err)
 		return
@@ -242,7 +281,14 @@ func (h *CustomerAuthHandler) VerifyEmail(c *gin.Context) {
 		return
 	}
 
-	err := h.customerEmailVerificationService.VerifyEmail(c.Request.Context(), token)
+	// Get storefront ID from context
+	storefrontID, exists := c.Get("storefront_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Storefront context required"})
+		return
+	}
+
+	err := h.customerEmailVerificationService.VerifyEmail(c.Request.Context(), storefrontID.(uuid.UUID), token)
 	if err != nil {
 		h.handleServiceError(c, err)
 		return
@@ -351,6 +397,23 @@ func (h *CustomerAuthHandler) setAuthCookies(c *gin.Context, accessToken, refres
 	c.SetCookie("refresh_token", refreshToken, 86400*7, "/", "", false, true)
 }
 
+// mergeGuestCart moves the cart built before login into the customer's cart.
+// Merging is best effort: a failure is logged by the cart use case and the
+// guest cart is kept so a later login can retry.
+func (h *CustomerAuthHandler) mergeGuestCart(c *gin.Context, response *dto.CustomerAuthResponse) {
+	sessionID := middleware.GetCartSessionID(c)
+	if h.cartUseCase == nil || sessionID == "" || response.Customer == nil {
+		return
+	}
+
+	customer := response.Customer
+	if err := h.cartUseCase.MergeGuestCart(c.Request.Context(), customer.StorefrontID, customer.ID, sessionID); err != nil {
+		return
+	}
+
+	middleware.ClearCartSessionID(c)
+}
+
 // clearAuthCookies clears authentication cookies
 func (h *CustomerAuthHandler) clearAuthCookies(c *gin.Context) {
 	c.SetCookie("access_token", "", -1, "/", "", false, true)
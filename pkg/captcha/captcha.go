@@ -0,0 +1,97 @@
+// Package captcha verifies CAPTCHA tokens with hCaptcha, reCAPTCHA and
+// Cloudflare Turnstile. All three use the same siteverify protocol: the token
+// is posted with the site secret and the provider answers with a JSON verdict.
+package captcha
+
+import (
+	"context"
+	"fmt"
+)
+
+// Provider names a CAPTCHA service
+type Provider string
+
+const (
+	ProviderHCaptcha  Provider = "hcaptcha"
+	ProviderReCaptcha Provider = "recaptcha"
+	ProviderTurnstile Provider = "turnstile"
+)
+
+// Mode controls when a CAPTCHA is demanded
+type Mode string
+
+const (
+	// ModeOff never demands a CAPTCHA
+	ModeOff Mode = "off"
+	// ModeAlways demands a CAPTCHA on every protected request
+	ModeAlways Mode = "always"
+	// ModeAdaptive demands a CAPTCHA only from clients with failed attempts
+	ModeAdaptive Mode = "adaptive"
+)
+
+// Config selects a provider and how strictly its verdict is applied
+type Config struct {
+	Provider  Provider `json:"provider"`
+	SecretKey string   `json:"secret_key"`
+	SiteKey   string   `json:"site_key,omitempty"`
+	Mode      Mode     `json:"mode,omitempty"`
+	// MinScore rejects verdicts scored below it, from 0 (bot) to 1 (human).
+	// Ignored when the provider does not score.
+	MinScore float64 `json:"min_score,omitempty"`
+	// Action, when set, must match the action the token was issued for
+	Action string `json:"action,omitempty"`
+}
+
+// Enabled reports whether the config demands CAPTCHAs at all
+func (c Config) Enabled() bool {
+	return c.Mode != ModeOff && c.Provider != "" && c.SecretKey != ""
+}
+
+// Result is a provider verdict
+type Result struct {
+	Success bool
+	// Score runs from 0 (bot) to 1 (human); HasScore is false when the
+	// provider did not score the token
+	Score      float64
+	HasScore   bool
+	Action     string
+	Hostname   string
+	ErrorCodes []string
+}
+
+// Passes applies the config's score threshold and expected action
+func (r *Result) Passes(cfg Config) bool {
+	if !r.Success {
+		return false
+	}
+	if r.HasScore && r.Score < cfg.MinScore {
+		return false
+	}
+	if cfg.Action != "" && r.Action != "" && r.Action != cfg.Action {
+		return false
+	}
+	return true
+}
+
+// CaptchaVerifier checks a token solved by a client
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, token, remoteIP string) (*Result, error)
+}
+
+// NewVerifier returns the verifier for the config's provider
+func NewVerifier(cfg Config, opts ...Option) (CaptchaVerifier, error) {
+	if cfg.SecretKey == "" {
+		return nil, fmt.Errorf("captcha secret key is required")
+	}
+
+	switch cfg.Provider {
+	case ProviderHCaptcha:
+		return NewHCaptchaVerifier(cfg.SecretKey, opts...), nil
+	case ProviderReCaptcha:
+		return NewReCaptchaVerifier(cfg.SecretKey, opts...), nil
+	case ProviderTurnstile:
+		return NewTurnstileVerifier(cfg.SecretKey, opts...), nil
+	default:
+		return nil, fmt.Errorf("unsupported captcha provider: %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,114 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// stubProvider answers siteverify calls with a fixed verdict and records the
+// last form it received
+func stubProvider(t *testing.T, verdict map[string]interface{}) (*httptest.Server, *http.Request) {
+	t.Helper()
+	received := &http.Request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parse form: %v", err)
+		}
+		*received = *r
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(verdict)
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func TestVerifierPostsTokenAndSecret(t *testing.T) {
+	server, received := stubProvider(t, map[string]interface{}{"success": true})
+
+	verifier, err := NewVerifier(Config{Provider: ProviderTurnstile, SecretKey: "site-secret"}, WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	result, err := verifier.Verify(context.Background(), "client-token", "203.0.113.7")
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	if !result.Success || result.HasScore {
+		t.Errorf("unexpected result %+v", result)
+	}
+	for field, want := range map[string]string{"secret": "site-secret", "response": "client-token", "remoteip": "203.0.113.7"} {
+		if got := received.PostForm.Get(field); got != want {
+			t.Errorf("%s = %q, want %q", field, got, want)
+		}
+	}
+}
+
+func TestResultPassesAppliesScoreThreshold(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider Provider
+		verdict  map[string]interface{}
+		want     bool
+	}{
+		{"recaptcha human", ProviderReCaptcha, map[string]interface{}{"success": true, "score": 0.9}, true},
+		{"recaptcha bot", ProviderReCaptcha, map[string]interface{}{"success": true, "score": 0.1}, false},
+		{"hcaptcha low risk", ProviderHCaptcha, map[string]interface{}{"success": true, "score": 0.1}, true},
+		{"hcaptcha high risk", ProviderHCaptcha, map[string]interface{}{"success": true, "score": 0.9}, false},
+		{"unscored success", ProviderTurnstile, map[string]interface{}{"success": true}, true},
+		{"failure", ProviderTurnstile, map[string]interface{}{"success": false, "error-codes": []string{"invalid-input-response"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := stubProvider(t, tt.verdict)
+			cfg := Config{Provider: tt.provider, SecretKey: "secret", MinScore: 0.5}
+
+			verifier, err := NewVerifier(cfg, WithEndpoint(server.URL))
+			if err != nil {
+				t.Fatalf("NewVerifier: %v", err)
+			}
+			result, err := verifier.Verify(context.Background(), "token", "")
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if got := result.Passes(cfg); got != tt.want {
+				t.Errorf("Passes = %v, want %v (result %+v)", got, tt.want, result)
+			}
+		})
+	}
+}
+
+func TestResultPassesChecksAction(t *testing.T) {
+	result := &Result{Success: true, Action: "login"}
+	if result.Passes(Config{Action: "register"}) {
+		t.Error("token issued for another action accepted")
+	}
+	if !result.Passes(Config{Action: "login"}) {
+		t.Error("token for the expected action rejected")
+	}
+}
+
+func TestVerifyReportsProviderErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	verifier := NewHCaptchaVerifier("secret", WithEndpoint(server.URL))
+	if _, err := verifier.Verify(context.Background(), "token", ""); err == nil {
+		t.Error("expected an error when the provider is unavailable")
+	}
+}
+
+func TestNewVerifierRejectsUnknownProvider(t *testing.T) {
+	if _, err := NewVerifier(Config{Provider: "friendlycaptcha", SecretKey: "secret"}); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+	if _, err := NewVerifier(Config{Provider: ProviderTurnstile}); err == nil {
+		t.Error("expected an error without a secret")
+	}
+}
@@ -0,0 +1,160 @@
+// Package clamav scans files with a clamd daemon over its INSTREAM protocol,
+// so files never need to be on a disk clamd can read.
+package clamav
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// ErrScanRejected is returned when clamd refuses to scan a stream, for
+// example because it exceeds StreamMaxLength. Retrying will not help.
+var ErrScanRejected = errors.New("clamav: scan rejected")
+
+// chunkSize is the size of the chunks streamed to clamd. It must stay below
+// clamd's StreamMaxLength.
+const chunkSize = 64 * 1024
+
+// Result is the outcome of a scan
+type Result struct {
+	Infected  bool
+	Signature string
+}
+
+// Client talks to a clamd daemon. Each call opens its own connection.
+type Client struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClient creates a clamd client. The address is host:port, tcp://host:port
+// or unix:///path/to/clamd.sock. The timeout bounds a whole scan.
+func NewClient(address string, timeout time.Duration) (*Client, error) {
+	network := "tcp"
+	switch {
+	case strings.HasPrefix(address, "unix://"):
+		network, address = "unix", strings.TrimPrefix(address, "unix://")
+	case strings.HasPrefix(address, "tcp://"):
+		address = strings.TrimPrefix(address, "tcp://")
+	}
+	if address == "" {
+		return nil, errors.New("clamav: address is required")
+	}
+	if timeout == 0 {
+		timeout = 2 * time.Minute
+	}
+
+	return &Client{network: network, address: address, timeout: timeout}, nil
+}
+
+// Ping checks that clamd is reachable
+func (c *Client) Ping(ctx context.Context) error {
+	reply, err := c.command(ctx, "PING", nil)
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("clamav: unexpected PING reply: %s", reply)
+	}
+	return nil
+}
+
+// Scan streams r to clamd and reports whether it contains a known threat. An
+// error means the content could not be scanned, not that it is unsafe.
+func (c *Client) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	reply, err := c.command(ctx, "INSTREAM", r)
+	if err != nil {
+		return nil, err
+	}
+	return parseScanReply(reply)
+}
+
+// command sends a null-terminated command, followed by the chunked stream
+// when one is given, and returns clamd's reply
+func (c *Client) command(ctx context.Context, name string, stream io.Reader) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, c.network, c.address)
+	if err != nil {
+		return "", fmt.Errorf("clamav: failed to connect: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	w := bufio.NewWriterSize(conn, chunkSize+4)
+	if _, err := w.WriteString("z" + name + "\x00"); err != nil {
+		return "", fmt.Errorf("clamav: failed to send command: %w", err)
+	}
+	if stream != nil {
+		if err := writeChunks(w, stream); err != nil {
+			return "", err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return "", fmt.Errorf("clamav: failed to send command: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !(errors.Is(err, io.EOF) && reply != "") {
+		return "", fmt.Errorf("clamav: failed to read reply: %w", err)
+	}
+	return strings.TrimRight(reply, "\x00\n"), nil
+}
+
+// writeChunks writes r as length-prefixed chunks ended by a zero-length chunk
+func writeChunks(w io.Writer, r io.Reader) error {
+	buf := make([]byte, chunkSize)
+	var size [4]byte
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size[:], uint32(n))
+			if _, werr := w.Write(size[:]); werr != nil {
+				return fmt.Errorf("clamav: failed to stream data: %w", werr)
+			}
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return fmt.Errorf("clamav: failed to stream data: %w", werr)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("clamav: failed to read data: %w", err)
+		}
+	}
+
+	binary.BigEndian.PutUint32(size[:], 0)
+	if _, err := w.Write(size[:]); err != nil {
+		return fmt.Errorf("clamav: failed to stream data: %w", err)
+	}
+	return nil
+}
+
+// parseScanReply parses "stream: OK", "stream: <signature> FOUND" or an
+// "... ERROR" reply
+func parseScanReply(reply string) (*Result, error) {
+	body := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case body == "OK":
+		return &Result{}, nil
+	case strings.HasSuffix(body, " FOUND"):
+		return &Result{Infected: true, Signature: strings.TrimSuffix(body, " FOUND")}, nil
+	case strings.HasSuffix(body, "ERROR"):
+		return nil, fmt.Errorf("%w: %s", ErrScanRejected, body)
+	default:
+		return nil, fmt.Errorf("clamav: unexpected reply: %s", reply)
+	}
+}
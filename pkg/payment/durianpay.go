@@ -0,0 +1,830 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// DurianPay API endpoints
+const (
+	DurianPaySandboxURL    = "https://api-sandbox.durianpay.id"
+	DurianPayProductionURL = "https://api.durianpay.id"
+)
+
+// durianPayLinkURL hosts the checkout pages of payment links
+const durianPayLinkURL = "https://links.durianpay.id/payment/"
+
+// durianPayLookupTimeout bounds the payment lookup confirming a legacy webhook
+const durianPayLookupTimeout = 15 * time.Second
+
+// snapNotificationMaxSkew is how far the signed timestamp of a SNAP
+// notification may be from our clock. Older notifications are rejected so a
+// captured one cannot be replayed.
+const snapNotificationMaxSkew = 5 * time.Minute
+
+// durianPayWebhookPath is the path SNAP notifications are signed for when no
+// webhook path is configured
+const durianPayWebhookPath = "/webhooks/payments/durianpay"
+
+// SNAP endpoints and headers
+const (
+	snapAccessTokenPath = "/v1.0/access-token/b2b"
+	snapDebitPath       = "/v1.0/debit/payment-host-to-host"
+	snapTimestampLayout = "2006-01-02T15:04:05-07:00"
+	snapChannelID       = "95221"
+)
+
+// DurianPayConfig holds the credentials of a DurianPay account. The legacy API
+// authenticates with the secret API key; SNAP signs every request with the
+// merchant's RSA private key.
+type DurianPayConfig struct {
+	APIKey  string
+	BaseURL string
+
+	// EnableSNAP creates charges through the SNAP API
+	EnableSNAP bool
+	ClientKey  string
+	MerchantID string
+	// PrivateKey is the merchant's PEM encoded RSA key signing SNAP requests
+	PrivateKey string
+	// PublicKey is DurianPay's PEM encoded RSA key verifying SNAP notifications
+	PublicKey string
+
+	// WebhookSecret verifies legacy webhooks. They are rejected while it is empty.
+	WebhookSecret string
+	// WebhookPath is the path of our webhook URL, part of what SNAP notifications sign
+	WebhookPath string
+
+	// MaxRetries is how often a call failing with a network or server error is retried
+	MaxRetries int
+}
+
+// DurianPayGateway creates DurianPay payment links and e-wallet charges
+type DurianPayGateway struct {
+	cfg        DurianPayConfig
+	baseURL    string
+	client     *http.Client
+	privateKey *rsa.PrivateKey
+	publicKey  *rsa.PublicKey
+	now        func() time.Time
+
+	mu          sync.Mutex
+	accessToken string
+	tokenExpiry time.Time
+}
+
+// NewDurianPayGateway creates a DurianPay gateway. It fails when SNAP is
+// enabled without a usable key pair.
+func NewDurianPayGateway(cfg DurianPayConfig, opts ...Option) (*DurianPayGateway, error) {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = DurianPaySandboxURL
+	}
+	if cfg.WebhookPath == "" {
+		cfg.WebhookPath = durianPayWebhookPath
+	}
+
+	g := &DurianPayGateway{
+		cfg:     cfg,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  buildOptions(opts).client,
+		now:     time.Now,
+	}
+
+	if cfg.EnableSNAP {
+		if cfg.ClientKey == "" || cfg.MerchantID == "" {
+			return nil, errors.New("durianpay: SNAP client key and merchant ID are required")
+		}
+		key, err := parseRSAPrivateKey(cfg.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("durianpay: invalid SNAP private key: %w", err)
+		}
+		g.privateKey = key
+	}
+	if cfg.PublicKey != "" {
+		key, err := parseRSAPublicKey(cfg.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("durianpay: invalid SNAP public key: %w", err)
+		}
+		g.publicKey = key
+	}
+
+	return g, nil
+}
+
+// Name identifies the gateway
+func (g *DurianPayGateway) Name() string {
+	return "durianpay"
+}
+
+// durianPayOrderRequest is the body of POST /v1/orders
+type durianPayOrderRequest struct {
+	Amount        string             `json:"amount"`
+	Currency      string             `json:"currency"`
+	OrderRefID    string             `json:"order_ref_id"`
+	Customer      *durianPayCustomer `json:"customer,omitempty"`
+	IsPaymentLink bool               `json:"is_payment_link,omitempty"`
+	ExpiryDate    string             `json:"expiry_date,omitempty"`
+}
+
+type durianPayCustomer struct {
+	Email  string `json:"email,omitempty"`
+	Mobile string `json:"mobile,omitempty"`
+}
+
+// durianPayOrder is an order as returned by the legacy API
+type durianPayOrder struct {
+	ID             string          `json:"id"`
+	OrderRefID     string          `json:"order_ref_id"`
+	Status         string          `json:"status"`
+	Amount         decimal.Decimal `json:"amount"`
+	Currency       string          `json:"currency"`
+	PaymentLinkURL string          `json:"payment_link_url"`
+	ExpireTime     *time.Time      `json:"expire_time"`
+}
+
+// durianPayChargeRequest is the body of POST /v1/payments/charge
+type durianPayChargeRequest struct {
+	Type    string                        `json:"type"`
+	Request durianPayEWalletChargeDetails `json:"request"`
+}
+
+type durianPayEWalletChargeDetails struct {
+	OrderID    string `json:"order_id"`
+	Amount     string `json:"amount"`
+	Mobile     string `json:"mobile,omitempty"`
+	WalletType string `json:"wallet_type"`
+}
+
+// durianPayChargeResponse is the data of a charge response
+type durianPayChargeResponse struct {
+	Type     string `json:"type"`
+	Response struct {
+		PaymentID      string     `json:"payment_id"`
+		OrderID        string     `json:"order_id"`
+		Status         string     `json:"status"`
+		CheckoutURL    string     `json:"checkout_url"`
+		ExpirationTime *time.Time `json:"expiration_time"`
+	} `json:"response"`
+}
+
+// durianPayWebhook is a legacy webhook delivery. Only the payment ID and
+// amount are covered by its signature.
+type durianPayWebhook struct {
+	Data struct {
+		ID        string          `json:"id"`
+		Amount    decimal.Decimal `json:"amount"`
+		Signature string          `json:"signature"`
+	} `json:"data"`
+}
+
+// durianPayPayment is a payment as returned by GET /v1/payments/:id
+type durianPayPayment struct {
+	ID         string          `json:"id"`
+	OrderID    string          `json:"order_id"`
+	Amount     decimal.Decimal `json:"amount"`
+	Status     string          `json:"status"`
+	Method     string          `json:"payment_method"`
+	WalletType string          `json:"wallet_type"`
+	Bank       string          `json:"bank_code"`
+	PaidAt     *time.Time      `json:"paid_at"`
+}
+
+// snapAmount is a SNAP money amount
+type snapAmount struct {
+	Value    string `json:"value"`
+	Currency string `json:"currency"`
+}
+
+// snapDebitRequest is the body of a SNAP host-to-host payment
+type snapDebitRequest struct {
+	PartnerReferenceNo string            `json:"partnerReferenceNo"`
+	Amount             snapAmount        `json:"amount"`
+	URLParams          []snapURLParam    `json:"urlParams,omitempty"`
+	ValidUpTo          string            `json:"validUpTo,omitempty"`
+	PayOptionDetails   []snapPayOption   `json:"payOptionDetails,omitempty"`
+	AdditionalInfo     map[string]string `json:"additionalInfo,omitempty"`
+}
+
+type snapURLParam struct {
+	URL        string `json:"url"`
+	Type       string `json:"type"`
+	IsDeeplink string `json:"isDeeplink"`
+}
+
+type snapPayOption struct {
+	PayMethod   string     `json:"payMethod"`
+	PayOption   string     `json:"payOption"`
+	TransAmount snapAmount `json:"transAmount"`
+}
+
+// snapDebitResponse is the answer to a SNAP host-to-host payment
+type snapDebitResponse struct {
+	ResponseCode       string `json:"responseCode"`
+	ResponseMessage    string `json:"responseMessage"`
+	ReferenceNo        string `json:"referenceNo"`
+	PartnerReferenceNo string `json:"partnerReferenceNo"`
+	WebRedirectURL     string `json:"webRedirectUrl"`
+	AppRedirectURL     string `json:"appRedirectUrl"`
+}
+
+// snapNotification is a SNAP payment notification
+type snapNotification struct {
+	OriginalPartnerReferenceNo string     `json:"originalPartnerReferenceNo"`
+	OriginalReferenceNo        string     `json:"originalReferenceNo"`
+	LatestTransactionStatus    string     `json:"latestTransactionStatus"`
+	Amount                     snapAmount `json:"amount"`
+	FinishedTime               *time.Time `json:"finishedTime"`
+	AdditionalInfo             struct {
+		PayMethod string `json:"payMethod"`
+		PayOption string `json:"payOption"`
+	} `json:"additionalInfo"`
+}
+
+// CreateInvoice creates a DurianPay payment link, or with SNAP a hosted
+// payment the customer picks a method on
+func (g *DurianPayGateway) CreateInvoice(ctx context.Context, req *InvoiceRequest) (*Charge, error) {
+	if g.cfg.EnableSNAP {
+		return g.snapDebit(ctx, req.ExternalID, req.Amount, req.Currency, "", req.Duration, req.SuccessRedirectURL, map[string]string{
+			"customerEmail": req.PayerEmail,
+			"description":   req.Description,
+		})
+	}
+
+	body := durianPayOrderRequest{
+		Amount:        req.Amount.String(),
+		Currency:      req.Currency,
+		OrderRefID:    req.ExternalID,
+		IsPaymentLink: true,
+	}
+	if req.PayerEmail != "" {
+		body.Customer = &durianPayCustomer{Email: req.PayerEmail}
+	}
+	if req.Duration > 0 {
+		body.ExpiryDate = g.now().Add(req.Duration).UTC().Format(time.RFC3339)
+	}
+
+	var order durianPayOrder
+	if err := g.legacyPost(ctx, "/v1/orders", body, &order); err != nil {
+		return nil, err
+	}
+
+	checkoutURL := order.PaymentLinkURL
+	if checkoutURL != "" && !strings.HasPrefix(checkoutURL, "http") {
+		checkoutURL = durianPayLinkURL + checkoutURL
+	}
+
+	return &Charge{
+		ID:          order.ID,
+		ExternalID:  order.OrderRefID,
+		Status:      ChargeStatusPending,
+		Amount:      order.Amount,
+		Currency:    order.Currency,
+		CheckoutURL: checkoutURL,
+		ExpiresAt:   order.ExpireTime,
+	}, nil
+}
+
+// CreateEWalletCharge charges an e-wallet. The legacy API needs a DurianPay
+// order to charge against; SNAP pays the wallet directly.
+func (g *DurianPayGateway) CreateEWalletCharge(ctx context.Context, req *EWalletChargeRequest) (*Charge, error) {
+	channel := strings.ToUpper(strings.TrimSpace(req.Channel))
+	if channel == "" {
+		return nil, fmt.Errorf("durianpay: e-wallet channel is required")
+	}
+
+	if g.cfg.EnableSNAP {
+		return g.snapDebit(ctx, req.ExternalID, req.Amount, req.Currency, channel, 0, req.SuccessRedirectURL, map[string]string{
+			"mobileNumber": req.MobileNumber,
+		})
+	}
+
+	var order durianPayOrder
+	if err := g.legacyPost(ctx, "/v1/orders", durianPayOrderRequest{
+		Amount:     req.Amount.String(),
+		Currency:   req.Currency,
+		OrderRefID: req.ExternalID,
+		Customer:   &durianPayCustomer{Mobile: req.MobileNumber},
+	}, &order); err != nil {
+		return nil, err
+	}
+
+	var charge durianPayChargeResponse
+	if err := g.legacyPost(ctx, "/v1/payments/charge", durianPayChargeRequest{
+		Type: "EWALLET",
+		Request: durianPayEWalletChargeDetails{
+			OrderID:    order.ID,
+			Amount:     req.Amount.String(),
+			Mobile:     req.MobileNumber,
+			WalletType: channel,
+		},
+	}, &charge); err != nil {
+		return nil, err
+	}
+
+	return &Charge{
+		ID:          order.ID,
+		ExternalID:  req.ExternalID,
+		Status:      ChargeStatusPending,
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		CheckoutURL: charge.Response.CheckoutURL,
+		ExpiresAt:   charge.Response.ExpirationTime,
+	}, nil
+}
+
+// ParseCallback verifies and decodes a webhook. SNAP notifications carry an
+// RSA signature in X-SIGNATURE checked with DurianPay's public key. Legacy
+// webhooks only sign the payment ID and amount with the webhook secret, so
+// the rest of the payment, including the order it pays, is read back from
+// the API by that ID.
+func (g *DurianPayGateway) ParseCallback(ctx context.Context, header http.Header, body []byte) (*CallbackEvent, error) {
+	if header.Get("X-SIGNATURE") != "" {
+		return g.parseSNAPNotification(header, body)
+	}
+
+	var webhook durianPayWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, fmt.Errorf("durianpay: invalid callback payload: %w", err)
+	}
+	data := webhook.Data
+
+	if g.cfg.WebhookSecret == "" || data.Signature == "" || data.ID == "" {
+		return nil, ErrInvalidCallback
+	}
+	mac := hmac.New(sha256.New, []byte(g.cfg.WebhookSecret))
+	mac.Write([]byte(data.ID + "|" + data.Amount.String()))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if subtle.ConstantTimeCompare([]byte(strings.ToLower(data.Signature)), []byte(expected)) != 1 {
+		return nil, ErrInvalidCallback
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, durianPayLookupTimeout)
+	defer cancel()
+
+	var paid durianPayPayment
+	if err := g.legacyGet(ctx, "/v1/payments/"+url.PathEscape(data.ID), &paid); err != nil {
+		return nil, err
+	}
+	if paid.ID != data.ID || paid.OrderID == "" {
+		return nil, fmt.Errorf("durianpay: payment %s not found", data.ID)
+	}
+	if !paid.Amount.Equal(data.Amount) {
+		return nil, ErrInvalidCallback
+	}
+
+	// Charges are referenced by their DurianPay order; our order ID is left
+	// out so the callback is matched by that reference alone
+	event := &CallbackEvent{
+		ChargeID: paid.OrderID,
+		Status:   durianPayPaymentStatus(paid.Status),
+		Method:   strings.ToUpper(paid.Method),
+		Channel:  paid.WalletType,
+	}
+	if event.Channel == "" {
+		event.Channel = paid.Bank
+	}
+	if event.Status == ChargeStatusPaid {
+		event.PaidAmount = paid.Amount
+		event.PaidAt = paid.PaidAt
+	}
+	return event, nil
+}
+
+func (g *DurianPayGateway) parseSNAPNotification(header http.Header, body []byte) (*CallbackEvent, error) {
+	if g.publicKey == nil {
+		return nil, ErrInvalidCallback
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(header.Get("X-SIGNATURE"))
+	if err != nil {
+		return nil, ErrInvalidCallback
+	}
+	stringToSign, err := snapStringToSign(http.MethodPost, g.cfg.WebhookPath, body, header.Get("X-TIMESTAMP"))
+	if err != nil {
+		return nil, fmt.Errorf("durianpay: invalid callback payload: %w", err)
+	}
+	digest := sha256.Sum256([]byte(stringToSign))
+	if err := rsa.VerifyPKCS1v15(g.publicKey, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, ErrInvalidCallback
+	}
+	if !g.snapTimestampFresh(header.Get("X-TIMESTAMP")) {
+		return nil, ErrInvalidCallback
+	}
+
+	var notification snapNotification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, fmt.Errorf("durianpay: invalid callback payload: %w", err)
+	}
+	if notification.OriginalReferenceNo == "" {
+		return nil, fmt.Errorf("durianpay: invalid callback payload: missing reference")
+	}
+
+	event := &CallbackEvent{
+		ChargeID:   notification.OriginalReferenceNo,
+		ExternalID: notification.OriginalPartnerReferenceNo,
+		Status:     snapTransactionStatus(notification.LatestTransactionStatus),
+		Method:     notification.AdditionalInfo.PayMethod,
+		Channel:    notification.AdditionalInfo.PayOption,
+	}
+	if event.Status == ChargeStatusPaid {
+		amount, err := decimal.NewFromString(notification.Amount.Value)
+		if err != nil {
+			return nil, fmt.Errorf("durianpay: invalid callback payload: invalid amount %q", notification.Amount.Value)
+		}
+		event.PaidAmount = amount
+		event.PaidAt = notification.FinishedTime
+	}
+	return event, nil
+}
+
+// snapTimestampFresh reports whether a notification timestamp is within
+// snapNotificationMaxSkew of now
+func (g *DurianPayGateway) snapTimestampFresh(timestamp string) bool {
+	signedAt, err := time.Parse(snapTimestampLayout, timestamp)
+	if err != nil {
+		return false
+	}
+	skew := g.now().Sub(signedAt)
+	return skew <= snapNotificationMaxSkew && skew >= -snapNotificationMaxSkew
+}
+
+// snapDebit creates a SNAP host-to-host payment. Without a channel the
+// customer picks the method on DurianPay's page.
+func (g *DurianPayGateway) snapDebit(ctx context.Context, externalID string, amount decimal.Decimal, currency, channel string, duration time.Duration, returnURL string, info map[string]string) (*Charge, error) {
+	total := snapAmount{Value: amount.StringFixed(2), Currency: currency}
+	body := snapDebitRequest{
+		PartnerReferenceNo: externalID,
+		Amount:             total,
+		AdditionalInfo:     map[string]string{},
+	}
+	for key, value := range info {
+		if value != "" {
+			body.AdditionalInfo[key] = value
+		}
+	}
+	if returnURL != "" {
+		body.URLParams = []snapURLParam{{URL: returnURL, Type: "PAY_RETURN", IsDeeplink: "N"}}
+	}
+	if channel != "" {
+		body.PayOptionDetails = []snapPayOption{{PayMethod: "EWALLET", PayOption: channel, TransAmount: total}}
+	}
+	var expiresAt *time.Time
+	if duration > 0 {
+		t := g.now().Add(duration)
+		expiresAt = &t
+		body.ValidUpTo = t.Format(snapTimestampLayout)
+	}
+
+	var resp snapDebitResponse
+	if err := g.snapPost(ctx, snapDebitPath, body, &resp); err != nil {
+		return nil, err
+	}
+
+	checkoutURL := resp.WebRedirectURL
+	if checkoutURL == "" {
+		checkoutURL = resp.AppRedirectURL
+	}
+
+	return &Charge{
+		ID:                resp.ReferenceNo,
+		ExternalID:        resp.PartnerReferenceNo,
+		Status:            ChargeStatusPending,
+		Amount:            amount,
+		Currency:          currency,
+		CheckoutURL:       checkoutURL,
+		MobileDeeplinkURL: resp.AppRedirectURL,
+		ExpiresAt:         expiresAt,
+	}, nil
+}
+
+// legacyPost calls the legacy API with basic auth and unwraps its data envelope
+func (g *DurianPayGateway) legacyPost(ctx context.Context, path string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("durianpay: failed to encode request: %w", err)
+	}
+
+	raw, err := g.do(ctx, path, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.baseURL+path, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.SetBasicAuth(g.cfg.APIKey, "")
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	return decodeDurianPayData(path, raw, out)
+}
+
+// legacyGet reads a legacy API resource with basic auth
+func (g *DurianPayGateway) legacyGet(ctx context.Context, path string, out interface{}) error {
+	raw, err := g.do(ctx, path, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+path, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.SetBasicAuth(g.cfg.APIKey, "")
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	return decodeDurianPayData(path, raw, out)
+}
+
+// decodeDurianPayData unwraps the data envelope of a legacy API response
+func decodeDurianPayData(path string, raw []byte, out interface{}) error {
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil || len(envelope.Data) == 0 {
+		return fmt.Errorf("durianpay: failed to decode %s response", path)
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("durianpay: failed to decode %s response: %w", path, err)
+	}
+	return nil
+}
+
+// snapPost calls a SNAP service with a bearer token and an RSA signature of
+// the request
+func (g *DurianPayGateway) snapPost(ctx context.Context, path string, body, out interface{}) error {
+	token, err := g.snapAccessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("durianpay: failed to encode request: %w", err)
+	}
+	// The same external ID on every attempt lets DurianPay drop duplicates
+	externalID := uuid.NewString()
+
+	raw, err := g.do(ctx, path, func() (*http.Request, error) {
+		timestamp := g.now().Format(snapTimestampLayout)
+		stringToSign, err := snapStringToSign(http.MethodPost, path, payload, timestamp)
+		if err != nil {
+			return nil, err
+		}
+		signature, err := g.sign(stringToSign)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.baseURL+path, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("X-TIMESTAMP", timestamp)
+		req.Header.Set("X-SIGNATURE", signature)
+		req.Header.Set("X-PARTNER-ID", g.cfg.MerchantID)
+		req.Header.Set("X-EXTERNAL-ID", externalID)
+		req.Header.Set("CHANNEL-ID", snapChannelID)
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("durianpay: failed to decode %s response: %w", path, err)
+	}
+	return nil
+}
+
+// snapAccessToken returns a cached B2B access token, requesting a new one
+// signed with the client key and timestamp when it is about to expire
+func (g *DurianPayGateway) snapAccessToken(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.accessToken != "" && g.now().Before(g.tokenExpiry) {
+		return g.accessToken, nil
+	}
+
+	payload := []byte(`{"grantType":"client_credentials"}`)
+	raw, err := g.do(ctx, snapAccessTokenPath, func() (*http.Request, error) {
+		timestamp := g.now().Format(snapTimestampLayout)
+		signature, err := g.sign(g.cfg.ClientKey + "|" + timestamp)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.baseURL+snapAccessTokenPath, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-TIMESTAMP", timestamp)
+		req.Header.Set("X-CLIENT-KEY", g.cfg.ClientKey)
+		req.Header.Set("X-SIGNATURE", signature)
+		return req, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		AccessToken string `json:"accessToken"`
+		ExpiresIn   string `json:"expiresIn"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil || resp.AccessToken == "" {
+		return "", fmt.Errorf("durianpay: failed to decode access token response")
+	}
+
+	lifetime, err := strconv.Atoi(resp.ExpiresIn)
+	if err != nil || lifetime <= 0 {
+		lifetime = 900
+	}
+	g.accessToken = resp.AccessToken
+	// Renew a minute early so a token never expires mid-request
+	g.tokenExpiry = g.now().Add(time.Duration(lifetime)*time.Second - time.Minute)
+
+	return g.accessToken, nil
+}
+
+// do sends a request built by newRequest, retrying network errors, rate
+// limits and server errors with exponential backoff, and returns the body of
+// a successful response
+func (g *DurianPayGateway) do(ctx context.Context, path string, newRequest func() (*http.Request, error)) ([]byte, error) {
+	var lastErr error
+	for attempt := 0; attempt <= g.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("durianpay: failed to call %s: %w", path, ctx.Err())
+			case <-time.After(time.Duration(1<<(attempt-1)) * 200 * time.Millisecond):
+			}
+		}
+
+		req, err := newRequest()
+		if err != nil {
+			return nil, fmt.Errorf("durianpay: failed to create request: %w", err)
+		}
+
+		resp, err := g.client.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("durianpay: failed to call %s: %w", path, err)
+			continue
+		}
+		raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+		if err != nil {
+			lastErr = fmt.Errorf("durianpay: failed to read %s response: %w", path, err)
+			continue
+		}
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return raw, nil
+		}
+		lastErr = fmt.Errorf("durianpay: %s returned status %d: %s", path, resp.StatusCode, durianPayErrorMessage(raw))
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return nil, lastErr
+		}
+	}
+	return nil, lastErr
+}
+
+// sign signs a string with the merchant's private key, base64 encoded
+func (g *DurianPayGateway) sign(stringToSign string) (string, error) {
+	if g.privateKey == nil {
+		return "", errors.New("durianpay: SNAP private key is not configured")
+	}
+	digest := sha256.Sum256([]byte(stringToSign))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, g.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("durianpay: failed to sign request: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// snapStringToSign builds METHOD:path:hex(sha256(minified body)):timestamp
+func snapStringToSign(method, path string, body []byte, timestamp string) (string, error) {
+	var minified bytes.Buffer
+	if len(body) > 0 {
+		if err := json.Compact(&minified, body); err != nil {
+			return "", err
+		}
+	}
+	hash := sha256.Sum256(minified.Bytes())
+	return method + ":" + path + ":" + strings.ToLower(hex.EncodeToString(hash[:])) + ":" + timestamp, nil
+}
+
+// durianPayErrorMessage extracts the message of a legacy or SNAP error body
+func durianPayErrorMessage(raw []byte) string {
+	var body struct {
+		Error           string `json:"error"`
+		Message         string `json:"message"`
+		ResponseCode    string `json:"responseCode"`
+		ResponseMessage string `json:"responseMessage"`
+	}
+	if json.Unmarshal(raw, &body) == nil {
+		switch {
+		case body.ResponseCode != "":
+			return body.ResponseCode + ": " + body.ResponseMessage
+		case body.Error != "" || body.Message != "":
+			return strings.TrimSpace(body.Error + " " + body.Message)
+		}
+	}
+	if len(raw) > 512 {
+		raw = raw[:512]
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+// durianPayPaymentStatus maps a legacy payment status to a charge status
+func durianPayPaymentStatus(status string) ChargeStatus {
+	switch strings.ToLower(status) {
+	case "completed":
+		return ChargeStatusPaid
+	case "failed":
+		return ChargeStatusFailed
+	case "expired":
+		return ChargeStatusExpired
+	default:
+		return ChargeStatusPending
+	}
+}
+
+// snapTransactionStatus maps a SNAP latestTransactionStatus to a charge status
+func snapTransactionStatus(status string) ChargeStatus {
+	switch status {
+	case "00":
+		return ChargeStatusPaid
+	case "05", "06":
+		return ChargeStatusFailed
+	default:
+		return ChargeStatusPending
+	}
+}
+
+// parseRSAPrivateKey reads a PKCS#1 or PKCS#8 PEM key. Keys kept in a single
+// line environment variable may use \n for line breaks.
+func parseRSAPrivateKey(value string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(strings.ReplaceAll(value, `\n`, "\n")))
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an RSA key")
+	}
+	return key, nil
+}
+
+// parseRSAPublicKey reads a PKIX or PKCS#1 PEM public key
+func parseRSAPublicKey(value string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(strings.ReplaceAll(value, `\n`, "\n")))
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("not an RSA key")
+	}
+	return key, nil
+}
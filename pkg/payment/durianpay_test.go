@@ -0,0 +1,339 @@
+package payment
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func newRSAKey(t *testing.T) (*rsa.PrivateKey, string, string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	private := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	public := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+	return key, string(private), string(public)
+}
+
+func rsaSign(t *testing.T, key *rsa.PrivateKey, s string) string {
+	t.Helper()
+	digest := sha256.Sum256([]byte(s))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(signature)
+}
+
+func writeJSONBody(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func TestDurianPayLegacyPaymentLink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, ok := r.BasicAuth(); !ok || user != "dp_test_key" {
+			writeJSONBody(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			return
+		}
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/v1/orders" || body["is_payment_link"] != true || body["amount"] != "150000" {
+			t.Errorf("unexpected request %s %v", r.URL.Path, body)
+		}
+		writeJSONBody(w, http.StatusCreated, map[string]interface{}{"data": map[string]interface{}{
+			"id":               "ord_123",
+			"order_ref_id":     body["order_ref_id"],
+			"status":           "started",
+			"amount":           "150000",
+			"currency":         "IDR",
+			"payment_link_url": "Abc123",
+		}})
+	}))
+	defer server.Close()
+
+	gateway, err := NewDurianPayGateway(DurianPayConfig{APIKey: "dp_test_key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewDurianPayGateway: %v", err)
+	}
+	charge, err := gateway.CreateInvoice(context.Background(), &InvoiceRequest{
+		ExternalID: "order-1",
+		Amount:     decimal.NewFromInt(150000),
+		Currency:   "IDR",
+		PayerEmail: "budi@example.com",
+		Duration:   time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("CreateInvoice: %v", err)
+	}
+	if charge.ID != "ord_123" || charge.ExternalID != "order-1" || charge.CheckoutURL != durianPayLinkURL+"Abc123" {
+		t.Errorf("unexpected charge %+v", charge)
+	}
+}
+
+func TestDurianPayLegacyEWalletCharge(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/v1/orders":
+			writeJSONBody(w, http.StatusCreated, map[string]interface{}{"data": map[string]interface{}{"id": "ord_456", "amount": "75000", "currency": "IDR"}})
+		case "/v1/payments/charge":
+			var body durianPayChargeRequest
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body.Type != "EWALLET" || body.Request.OrderID != "ord_456" || body.Request.WalletType != "DANA" {
+				t.Errorf("unexpected charge request %+v", body)
+			}
+			writeJSONBody(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+				"type":     "EWALLET",
+				"response": map[string]interface{}{"payment_id": "pay_1", "order_id": "ord_456", "checkout_url": "https://dana.example/pay"},
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	gateway, err := NewDurianPayGateway(DurianPayConfig{APIKey: "dp_test_key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewDurianPayGateway: %v", err)
+	}
+	charge, err := gateway.CreateEWalletCharge(context.Background(), &EWalletChargeRequest{
+		ExternalID: "order-2",
+		Amount:     decimal.NewFromInt(75000),
+		Currency:   "IDR",
+		Channel:    "dana",
+	})
+	if err != nil {
+		t.Fatalf("CreateEWalletCharge: %v", err)
+	}
+	if charge.ID != "ord_456" || charge.CheckoutURL != "https://dana.example/pay" || len(paths) != 2 {
+		t.Errorf("unexpected charge %+v after %v", charge, paths)
+	}
+}
+
+func TestDurianPayRetriesServerErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			writeJSONBody(w, http.StatusServiceUnavailable, map[string]string{"error": "unavailable"})
+			return
+		}
+		writeJSONBody(w, http.StatusCreated, map[string]interface{}{"data": map[string]interface{}{"id": "ord_789"}})
+	}))
+	defer server.Close()
+
+	gateway, err := NewDurianPayGateway(DurianPayConfig{APIKey: "key", BaseURL: server.URL, MaxRetries: 1})
+	if err != nil {
+		t.Fatalf("NewDurianPayGateway: %v", err)
+	}
+	if _, err := gateway.CreateInvoice(context.Background(), &InvoiceRequest{ExternalID: "order-3", Amount: decimal.NewFromInt(1000)}); err != nil {
+		t.Fatalf("CreateInvoice: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want the failed call retried once", calls)
+	}
+}
+
+func TestDurianPayLegacyWebhook(t *testing.T) {
+	var lookups int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&lookups, 1)
+		if user, _, ok := r.BasicAuth(); !ok || user != "key" || r.Method != http.MethodGet || r.URL.Path != "/v1/payments/pay_1" {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSONBody(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+			"id":             "pay_1",
+			"order_id":       "ord_123",
+			"amount":         "150000",
+			"status":         "completed",
+			"payment_method": "va",
+			"bank_code":      "BCA",
+		}})
+	}))
+	defer server.Close()
+
+	gateway, err := NewDurianPayGateway(DurianPayConfig{APIKey: "key", BaseURL: server.URL, WebhookSecret: "whsec"})
+	if err != nil {
+		t.Fatalf("NewDurianPayGateway: %v", err)
+	}
+
+	sign := func(paymentID, amount string) string {
+		mac := hmac.New(sha256.New, []byte("whsec"))
+		mac.Write([]byte(paymentID + "|" + amount))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	// The order fields are not signed, so they are set to someone else's order
+	webhook := func(paymentID, amount, signature string) []byte {
+		body, _ := json.Marshal(map[string]interface{}{
+			"event": "payment.completed",
+			"data": map[string]interface{}{
+				"id":           paymentID,
+				"order_id":     "ord_999",
+				"order_ref_id": "order-9",
+				"amount":       amount,
+				"status":       "completed",
+				"signature":    signature,
+			},
+		})
+		return body
+	}
+
+	event, err := gateway.ParseCallback(context.Background(), http.Header{}, webhook("pay_1", "150000", sign("pay_1", "150000")))
+	if err != nil {
+		t.Fatalf("ParseCallback: %v", err)
+	}
+	if event.ChargeID != "ord_123" || event.ExternalID != "" || event.Status != ChargeStatusPaid ||
+		!event.PaidAmount.Equal(decimal.NewFromInt(150000)) || event.Method != "VA" || event.Channel != "BCA" {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	if _, err := gateway.ParseCallback(context.Background(), http.Header{}, webhook("pay_1", "150000", "deadbeef")); !errors.Is(err, ErrInvalidCallback) {
+		t.Errorf("err = %v, want a forged signature rejected", err)
+	}
+	if _, err := gateway.ParseCallback(context.Background(), http.Header{}, webhook("pay_1", "1", sign("pay_1", "1"))); !errors.Is(err, ErrInvalidCallback) {
+		t.Errorf("err = %v, want an amount DurianPay does not confirm rejected", err)
+	}
+	if _, err := gateway.ParseCallback(context.Background(), http.Header{}, webhook("pay_2", "150000", sign("pay_2", "150000"))); err == nil {
+		t.Error("Expected a payment DurianPay does not know to be rejected")
+	}
+	// The lookup runs within the caller's request
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := gateway.ParseCallback(cancelled, http.Header{}, webhook("pay_1", "150000", sign("pay_1", "150000"))); err == nil {
+		t.Error("Expected the lookup to stop with the caller's context")
+	}
+	if lookups != 3 {
+		t.Errorf("lookups = %d, want only signed webhooks looked up", lookups)
+	}
+}
+
+func TestDurianPaySNAP(t *testing.T) {
+	merchantKey, merchantPrivate, _ := newRSAKey(t)
+	durianKey, _, durianPublic := newRSAKey(t)
+
+	var tokenCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp := r.Header.Get("X-TIMESTAMP")
+		signature, _ := base64.StdEncoding.DecodeString(r.Header.Get("X-SIGNATURE"))
+
+		var stringToSign string
+		switch r.URL.Path {
+		case snapAccessTokenPath:
+			atomic.AddInt32(&tokenCalls, 1)
+			stringToSign = r.Header.Get("X-CLIENT-KEY") + "|" + timestamp
+		case snapDebitPath:
+			if r.Header.Get("Authorization") != "Bearer snap-token" || r.Header.Get("X-PARTNER-ID") != "merchant-1" {
+				writeJSONBody(w, http.StatusUnauthorized, map[string]string{"responseCode": "4015400", "responseMessage": "Unauthorized"})
+				return
+			}
+			stringToSign, _ = snapStringToSign(http.MethodPost, snapDebitPath, body, timestamp)
+		}
+
+		digest := sha256.Sum256([]byte(stringToSign))
+		if err := rsa.VerifyPKCS1v15(&merchantKey.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+			writeJSONBody(w, http.StatusUnauthorized, map[string]string{"responseCode": "4017300", "responseMessage": "Unauthorized. Signature"})
+			return
+		}
+
+		if r.URL.Path == snapAccessTokenPath {
+			writeJSONBody(w, http.StatusOK, map[string]string{"responseCode": "2007300", "accessToken": "snap-token", "expiresIn": "900"})
+			return
+		}
+		var req snapDebitRequest
+		_ = json.Unmarshal(body, &req)
+		writeJSONBody(w, http.StatusOK, map[string]string{
+			"responseCode":       "2005400",
+			"referenceNo":        "ord_snap_" + req.PartnerReferenceNo,
+			"partnerReferenceNo": req.PartnerReferenceNo,
+			"webRedirectUrl":     "https://checkout.durianpay.example/" + req.PartnerReferenceNo,
+		})
+	}))
+	defer server.Close()
+
+	gateway, err := NewDurianPayGateway(DurianPayConfig{
+		BaseURL:    server.URL,
+		EnableSNAP: true,
+		ClientKey:  "client-1",
+		MerchantID: "merchant-1",
+		PrivateKey: merchantPrivate,
+		PublicKey:  durianPublic,
+	})
+	if err != nil {
+		t.Fatalf("NewDurianPayGateway: %v", err)
+	}
+
+	charge, err := gateway.CreateEWalletCharge(context.Background(), &EWalletChargeRequest{
+		ExternalID: "order-1",
+		Amount:     decimal.NewFromInt(50000),
+		Currency:   "IDR",
+		Channel:    "SHOPEEPAY",
+	})
+	if err != nil {
+		t.Fatalf("CreateEWalletCharge: %v", err)
+	}
+	if charge.ID != "ord_snap_order-1" || charge.CheckoutURL == "" {
+		t.Errorf("unexpected charge %+v", charge)
+	}
+	if _, err := gateway.CreateInvoice(context.Background(), &InvoiceRequest{ExternalID: "order-2", Amount: decimal.NewFromInt(50000), Currency: "IDR"}); err != nil {
+		t.Fatalf("CreateInvoice: %v", err)
+	}
+	if tokenCalls != 1 {
+		t.Errorf("access token requested %d times, want it reused", tokenCalls)
+	}
+
+	notification := []byte(`{"originalPartnerReferenceNo":"order-1","originalReferenceNo":"ord_snap_order-1","latestTransactionStatus":"00","amount":{"value":"50000.00","currency":"IDR"},"additionalInfo":{"payMethod":"EWALLET","payOption":"SHOPEEPAY"}}`)
+	timestamp := time.Now().Format(snapTimestampLayout)
+	stringToSign, _ := snapStringToSign(http.MethodPost, durianPayWebhookPath, notification, timestamp)
+	header := http.Header{}
+	header.Set("X-TIMESTAMP", timestamp)
+	header.Set("X-SIGNATURE", rsaSign(t, durianKey, stringToSign))
+
+	event, err := gateway.ParseCallback(context.Background(), header, notification)
+	if err != nil {
+		t.Fatalf("ParseCallback: %v", err)
+	}
+	if event.ChargeID != "ord_snap_order-1" || event.Status != ChargeStatusPaid || !event.PaidAmount.Equal(decimal.NewFromInt(50000)) || event.Channel != "SHOPEEPAY" {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	header.Set("X-SIGNATURE", rsaSign(t, merchantKey, stringToSign))
+	if _, err := gateway.ParseCallback(context.Background(), header, notification); !errors.Is(err, ErrInvalidCallback) {
+		t.Errorf("err = %v, want a notification not signed by DurianPay rejected", err)
+	}
+
+	// A captured notification cannot be replayed once its timestamp is stale
+	stale := time.Now().Add(-snapNotificationMaxSkew - time.Minute).Format(snapTimestampLayout)
+	stringToSign, _ = snapStringToSign(http.MethodPost, durianPayWebhookPath, notification, stale)
+	header.Set("X-TIMESTAMP", stale)
+	header.Set("X-SIGNATURE", rsaSign(t, durianKey, stringToSign))
+	if _, err := gateway.ParseCallback(context.Background(), header, notification); !errors.Is(err, ErrInvalidCallback) {
+		t.Errorf("err = %v, want a notification with a stale timestamp rejected", err)
+	}
+}
@@ -0,0 +1,129 @@
+// Package payment collects money for orders through hosted payment gateways.
+// A gateway creates an invoice or e-wallet charge the customer pays on the
+// gateway's pages, then reports the outcome with a callback to our server.
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// defaultTimeout bounds a gateway API call
+const defaultTimeout = 30 * time.Second
+
+// Option customizes a gateway
+type Option func(*options)
+
+type options struct {
+	client *http.Client
+}
+
+// WithHTTPClient sets the client used to call the gateway
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+func buildOptions(opts []Option) options {
+	o := options{client: &http.Client{Timeout: defaultTimeout}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// ErrInvalidCallback is returned when a callback fails authentication. The
+// payload must not be trusted.
+var ErrInvalidCallback = errors.New("payment: callback verification failed")
+
+// ChargeStatus is the gateway-neutral state of an invoice or charge
+type ChargeStatus string
+
+const (
+	ChargeStatusPending ChargeStatus = "pending"
+	ChargeStatusPaid    ChargeStatus = "paid"
+	ChargeStatusExpired ChargeStatus = "expired"
+	ChargeStatusFailed  ChargeStatus = "failed"
+)
+
+// InvoiceRequest asks for a hosted invoice the customer can pay with any
+// method the merchant account enables
+type InvoiceRequest struct {
+	// ExternalID is our reference for the payment; callbacks carry it back
+	ExternalID  string
+	Amount      decimal.Decimal
+	Currency    string
+	Description string
+	PayerEmail  string
+	// Duration is how long the invoice can be paid; zero uses the gateway default
+	Duration           time.Duration
+	SuccessRedirectURL string
+	FailureRedirectURL string
+}
+
+// EWalletChargeRequest asks for a one-time e-wallet payment
+type EWalletChargeRequest struct {
+	ExternalID string
+	Amount     decimal.Decimal
+	Currency   string
+	// Channel is the e-wallet, e.g. SHOPEEPAY, OVO, DANA
+	Channel string
+	// MobileNumber is required by wallets that push the payment to the app, e.g. OVO
+	MobileNumber       string
+	SuccessRedirectURL string
+	FailureRedirectURL string
+}
+
+// Charge is an invoice or e-wallet charge created at the gateway
+type Charge struct {
+	// ID is the gateway's identifier for the charge
+	ID         string
+	ExternalID string
+	Status     ChargeStatus
+	Amount     decimal.Decimal
+	Currency   string
+	// CheckoutURL is where the customer pays; empty for push-to-app wallets
+	CheckoutURL string
+	// MobileDeeplinkURL opens the wallet app directly, when the wallet supports it
+	MobileDeeplinkURL string
+	ExpiresAt         *time.Time
+}
+
+// CallbackEvent is a payment outcome reported by a gateway
+type CallbackEvent struct {
+	ChargeID string
+	// ExternalID is the reference the charge was created with. Gateways leave
+	// it empty when the callback does not authenticate it.
+	ExternalID string
+	Status     ChargeStatus
+	// PaidAmount is the amount received; zero unless Status is paid
+	PaidAmount decimal.Decimal
+	// Method is how the customer paid, e.g. EWALLET or BANK_TRANSFER, when known
+	Method string
+	// Channel is the bank or wallet the customer paid with, when known
+	Channel string
+	PaidAt  *time.Time
+}
+
+// PaymentGateway creates charges at a payment provider and reads its callbacks
+type PaymentGateway interface {
+	// Name identifies the gateway in callback URLs and logs, e.g. "xendit"
+	Name() string
+
+	// CreateInvoice creates a hosted invoice
+	CreateInvoice(ctx context.Context, req *InvoiceRequest) (*Charge, error)
+
+	// CreateEWalletCharge creates a one-time e-wallet charge
+	CreateEWalletCharge(ctx context.Context, req *EWalletChargeRequest) (*Charge, error)
+
+	// ParseCallback authenticates a callback and decodes its outcome. It
+	// returns ErrInvalidCallback when the request was not sent by the gateway.
+	// Gateways that confirm a callback with the API do so only after it is
+	// authenticated, within ctx.
+	ParseCallback(ctx context.Context, header http.Header, body []byte) (*CallbackEvent, error)
+}
@@ -0,0 +1,318 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// XenditBaseURL is Xendit's API endpoint
+const XenditBaseURL = "https://api.xendit.co"
+
+// XenditCallbackTokenHeader carries the callback verification token Xendit
+// shows in its dashboard
+const XenditCallbackTokenHeader = "X-Callback-Token"
+
+// XenditConfig holds the credentials of a Xendit account
+type XenditConfig struct {
+	APIKey  string
+	BaseURL string
+	// CallbackToken authenticates callbacks. Callbacks are rejected while it is empty.
+	CallbackToken string
+}
+
+// XenditGateway creates Xendit invoices and e-wallet charges
+type XenditGateway struct {
+	apiKey        string
+	baseURL       string
+	callbackToken string
+	client        *http.Client
+}
+
+// NewXenditGateway creates a Xendit gateway
+func NewXenditGateway(cfg XenditConfig, opts ...Option) *XenditGateway {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = XenditBaseURL
+	}
+
+	return &XenditGateway{
+		apiKey:        cfg.APIKey,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		callbackToken: cfg.CallbackToken,
+		client:        buildOptions(opts).client,
+	}
+}
+
+// Name identifies the gateway
+func (g *XenditGateway) Name() string {
+	return "xendit"
+}
+
+// xenditInvoiceRequest is the body of POST /v2/invoices
+type xenditInvoiceRequest struct {
+	ExternalID         string  `json:"external_id"`
+	Amount             float64 `json:"amount"`
+	Currency           string  `json:"currency,omitempty"`
+	Description        string  `json:"description,omitempty"`
+	PayerEmail         string  `json:"payer_email,omitempty"`
+	InvoiceDuration    int64   `json:"invoice_duration,omitempty"`
+	SuccessRedirectURL string  `json:"success_redirect_url,omitempty"`
+	FailureRedirectURL string  `json:"failure_redirect_url,omitempty"`
+}
+
+// xenditInvoice is an invoice as returned by the API and sent in callbacks
+type xenditInvoice struct {
+	ID             string          `json:"id"`
+	ExternalID     string          `json:"external_id"`
+	Status         string          `json:"status"`
+	Amount         decimal.Decimal `json:"amount"`
+	PaidAmount     decimal.Decimal `json:"paid_amount"`
+	Currency       string          `json:"currency"`
+	InvoiceURL     string          `json:"invoice_url"`
+	ExpiryDate     *time.Time      `json:"expiry_date"`
+	PaymentMethod  string          `json:"payment_method"`
+	PaymentChannel string          `json:"payment_channel"`
+	PaidAt         *time.Time      `json:"paid_at"`
+}
+
+// xenditEWalletChargeRequest is the body of POST /ewallets/charges
+type xenditEWalletChargeRequest struct {
+	ReferenceID       string            `json:"reference_id"`
+	Currency          string            `json:"currency"`
+	Amount            float64           `json:"amount"`
+	CheckoutMethod    string            `json:"checkout_method"`
+	ChannelCode       string            `json:"channel_code"`
+	ChannelProperties map[string]string `json:"channel_properties,omitempty"`
+}
+
+// xenditEWalletCharge is an e-wallet charge as returned by the API and sent in callbacks
+type xenditEWalletCharge struct {
+	ID            string          `json:"id"`
+	ReferenceID   string          `json:"reference_id"`
+	Status        string          `json:"status"`
+	Currency      string          `json:"currency"`
+	ChargeAmount  decimal.Decimal `json:"charge_amount"`
+	CaptureAmount decimal.Decimal `json:"capture_amount"`
+	ChannelCode   string          `json:"channel_code"`
+	Actions       struct {
+		DesktopWebCheckoutURL     string `json:"desktop_web_checkout_url"`
+		MobileWebCheckoutURL      string `json:"mobile_web_checkout_url"`
+		MobileDeeplinkCheckoutURL string `json:"mobile_deeplink_checkout_url"`
+	} `json:"actions"`
+	Updated *time.Time `json:"updated"`
+}
+
+// xenditEWalletCallback wraps e-wallet charges pushed to the callback URL
+type xenditEWalletCallback struct {
+	Event string               `json:"event"`
+	Data  *xenditEWalletCharge `json:"data"`
+}
+
+// xenditError is the body of a failed API call
+type xenditError struct {
+	ErrorCode string `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+// CreateInvoice creates a Xendit invoice
+func (g *XenditGateway) CreateInvoice(ctx context.Context, req *InvoiceRequest) (*Charge, error) {
+	body := xenditInvoiceRequest{
+		ExternalID:         req.ExternalID,
+		Amount:             req.Amount.InexactFloat64(),
+		Currency:           req.Currency,
+		Description:        req.Description,
+		PayerEmail:         req.PayerEmail,
+		InvoiceDuration:    int64(req.Duration / time.Second),
+		SuccessRedirectURL: req.SuccessRedirectURL,
+		FailureRedirectURL: req.FailureRedirectURL,
+	}
+
+	var invoice xenditInvoice
+	if err := g.post(ctx, "/v2/invoices", body, &invoice); err != nil {
+		return nil, err
+	}
+
+	return &Charge{
+		ID:          invoice.ID,
+		ExternalID:  invoice.ExternalID,
+		Status:      xenditInvoiceStatus(invoice.Status),
+		Amount:      invoice.Amount,
+		Currency:    invoice.Currency,
+		CheckoutURL: invoice.InvoiceURL,
+		ExpiresAt:   invoice.ExpiryDate,
+	}, nil
+}
+
+// CreateEWalletCharge creates a one-time Xendit e-wallet charge
+func (g *XenditGateway) CreateEWalletCharge(ctx context.Context, req *EWalletChargeRequest) (*Charge, error) {
+	channel := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(req.Channel)), "ID_")
+	if channel == "" {
+		return nil, fmt.Errorf("xendit: e-wallet channel is required")
+	}
+
+	properties := map[string]string{}
+	if req.SuccessRedirectURL != "" {
+		properties["success_redirect_url"] = req.SuccessRedirectURL
+	}
+	if req.FailureRedirectURL != "" {
+		properties["failure_redirect_url"] = req.FailureRedirectURL
+	}
+	if req.MobileNumber != "" {
+		properties["mobile_number"] = req.MobileNumber
+	}
+
+	body := xenditEWalletChargeRequest{
+		ReferenceID:       req.ExternalID,
+		Currency:          req.Currency,
+		Amount:            req.Amount.InexactFloat64(),
+		CheckoutMethod:    "ONE_TIME_PAYMENT",
+		ChannelCode:       "ID_" + channel,
+		ChannelProperties: properties,
+	}
+
+	var charge xenditEWalletCharge
+	if err := g.post(ctx, "/ewallets/charges", body, &charge); err != nil {
+		return nil, err
+	}
+
+	checkoutURL := charge.Actions.DesktopWebCheckoutURL
+	if checkoutURL == "" {
+		checkoutURL = charge.Actions.MobileWebCheckoutURL
+	}
+
+	return &Charge{
+		ID:                charge.ID,
+		ExternalID:        charge.ReferenceID,
+		Status:            xenditEWalletStatus(charge.Status),
+		Amount:            charge.ChargeAmount,
+		Currency:          charge.Currency,
+		CheckoutURL:       checkoutURL,
+		MobileDeeplinkURL: charge.Actions.MobileDeeplinkCheckoutURL,
+	}, nil
+}
+
+// ParseCallback checks the callback token and decodes an invoice or e-wallet
+// callback. E-wallet callbacks are wrapped in an event envelope; invoice
+// callbacks are the bare invoice.
+func (g *XenditGateway) ParseCallback(ctx context.Context, header http.Header, body []byte) (*CallbackEvent, error) {
+	token := header.Get(XenditCallbackTokenHeader)
+	if g.callbackToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(g.callbackToken)) != 1 {
+		return nil, ErrInvalidCallback
+	}
+
+	var envelope xenditEWalletCallback
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("xendit: invalid callback payload: %w", err)
+	}
+	if envelope.Event != "" && envelope.Data != nil {
+		charge := envelope.Data
+		event := &CallbackEvent{
+			ChargeID:   charge.ID,
+			ExternalID: charge.ReferenceID,
+			Status:     xenditEWalletStatus(charge.Status),
+			Method:     "EWALLET",
+			Channel:    strings.TrimPrefix(charge.ChannelCode, "ID_"),
+		}
+		if event.Status == ChargeStatusPaid {
+			event.PaidAmount = charge.CaptureAmount
+			if event.PaidAmount.IsZero() {
+				event.PaidAmount = charge.ChargeAmount
+			}
+			event.PaidAt = charge.Updated
+		}
+		return event, nil
+	}
+
+	var invoice xenditInvoice
+	if err := json.Unmarshal(body, &invoice); err != nil {
+		return nil, fmt.Errorf("xendit: invalid callback payload: %w", err)
+	}
+	if invoice.ID == "" {
+		return nil, fmt.Errorf("xendit: invalid callback payload: missing invoice id")
+	}
+
+	event := &CallbackEvent{
+		ChargeID:   invoice.ID,
+		ExternalID: invoice.ExternalID,
+		Status:     xenditInvoiceStatus(invoice.Status),
+		Method:     invoice.PaymentMethod,
+		Channel:    invoice.PaymentChannel,
+	}
+	if event.Status == ChargeStatusPaid {
+		event.PaidAmount = invoice.PaidAmount
+		if event.PaidAmount.IsZero() {
+			event.PaidAmount = invoice.Amount
+		}
+		event.PaidAt = invoice.PaidAt
+	}
+	return event, nil
+}
+
+// post sends a JSON request authenticated with the secret API key and decodes
+// the response into out
+func (g *XenditGateway) post(ctx context.Context, path string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("xendit: failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("xendit: failed to create request: %w", err)
+	}
+	req.SetBasicAuth(g.apiKey, "")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("xendit: failed to call %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		var apiErr xenditError
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.ErrorCode != "" {
+			return fmt.Errorf("xendit: %s returned status %d: %s: %s", path, resp.StatusCode, apiErr.ErrorCode, apiErr.Message)
+		}
+		return fmt.Errorf("xendit: %s returned status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("xendit: failed to decode %s response: %w", path, err)
+	}
+	return nil
+}
+
+// xenditInvoiceStatus maps an invoice status to a charge status
+func xenditInvoiceStatus(status string) ChargeStatus {
+	switch strings.ToUpper(status) {
+	case "PAID", "SETTLED":
+		return ChargeStatusPaid
+	case "EXPIRED":
+		return ChargeStatusExpired
+	default:
+		return ChargeStatusPending
+	}
+}
+
+// xenditEWalletStatus maps an e-wallet charge status to a charge status
+func xenditEWalletStatus(status string) ChargeStatus {
+	switch strings.ToUpper(status) {
+	case "SUCCEEDED":
+		return ChargeStatusPaid
+	case "FAILED", "VOIDED":
+		return ChargeStatusFailed
+	default:
+		return ChargeStatusPending
+	}
+}
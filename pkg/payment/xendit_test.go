@@ -0,0 +1,164 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kirimku/smartseller-backend/pkg/payment/xendittest"
+)
+
+const callbackToken = "callback-token"
+
+func newXendit(t *testing.T) (*XenditGateway, *xendittest.Server) {
+	t.Helper()
+	server := xendittest.NewServer("xnd_test_key")
+	t.Cleanup(server.Close)
+
+	gateway := NewXenditGateway(XenditConfig{
+		APIKey:        "xnd_test_key",
+		BaseURL:       server.URL(),
+		CallbackToken: callbackToken,
+	})
+	return gateway, server
+}
+
+func callbackHeader(token string) http.Header {
+	header := http.Header{}
+	header.Set(XenditCallbackTokenHeader, token)
+	return header
+}
+
+func TestXenditInvoiceRoundTrip(t *testing.T) {
+	gateway, server := newXendit(t)
+
+	charge, err := gateway.CreateInvoice(context.Background(), &InvoiceRequest{
+		ExternalID:  "order-1",
+		Amount:      decimal.NewFromInt(150000),
+		Currency:    "IDR",
+		Description: "Order ORD-1",
+		PayerEmail:  "budi@example.com",
+		Duration:    2 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("CreateInvoice: %v", err)
+	}
+	if charge.ID == "" || charge.CheckoutURL == "" || charge.Status != ChargeStatusPending {
+		t.Errorf("unexpected charge %+v", charge)
+	}
+	if !charge.Amount.Equal(decimal.NewFromInt(150000)) {
+		t.Errorf("amount = %s, want 150000", charge.Amount)
+	}
+	if charge.ExpiresAt == nil || time.Until(*charge.ExpiresAt) > 2*time.Hour {
+		t.Errorf("expires at %v, want within the requested duration", charge.ExpiresAt)
+	}
+
+	invoices := server.Invoices()
+	if len(invoices) != 1 || invoices[0].PayerEmail != "budi@example.com" {
+		t.Fatalf("server recorded %+v", invoices)
+	}
+
+	body, err := server.PayInvoice(charge.ID)
+	if err != nil {
+		t.Fatalf("PayInvoice: %v", err)
+	}
+	event, err := gateway.ParseCallback(context.Background(), callbackHeader(callbackToken), body)
+	if err != nil {
+		t.Fatalf("ParseCallback: %v", err)
+	}
+	if event.ChargeID != charge.ID || event.ExternalID != "order-1" || event.Status != ChargeStatusPaid {
+		t.Errorf("unexpected event %+v", event)
+	}
+	if !event.PaidAmount.Equal(decimal.NewFromInt(150000)) || event.Method != "BANK_TRANSFER" || event.PaidAt == nil {
+		t.Errorf("unexpected payment details %+v", event)
+	}
+}
+
+func TestXenditEWalletCharge(t *testing.T) {
+	gateway, server := newXendit(t)
+
+	charge, err := gateway.CreateEWalletCharge(context.Background(), &EWalletChargeRequest{
+		ExternalID:         "order-2",
+		Amount:             decimal.NewFromInt(75000),
+		Currency:           "IDR",
+		Channel:            "shopeepay",
+		SuccessRedirectURL: "https://shop.example.com/paid",
+	})
+	if err != nil {
+		t.Fatalf("CreateEWalletCharge: %v", err)
+	}
+	if charge.CheckoutURL == "" || charge.MobileDeeplinkURL == "" {
+		t.Errorf("unexpected charge %+v", charge)
+	}
+	if charges := server.EWalletCharges(); len(charges) != 1 || charges[0].ChannelCode != "ID_SHOPEEPAY" {
+		t.Fatalf("server recorded %+v", charges)
+	}
+
+	body, err := server.CompleteEWalletCharge(charge.ID, false)
+	if err != nil {
+		t.Fatalf("CompleteEWalletCharge: %v", err)
+	}
+	event, err := gateway.ParseCallback(context.Background(), callbackHeader(callbackToken), body)
+	if err != nil {
+		t.Fatalf("ParseCallback: %v", err)
+	}
+	if event.ChargeID != charge.ID || event.ExternalID != "order-2" || event.Status != ChargeStatusFailed || !event.PaidAmount.IsZero() {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	// OVO pushes the payment to the app and needs the customer's number
+	if _, err := gateway.CreateEWalletCharge(context.Background(), &EWalletChargeRequest{
+		ExternalID: "order-3",
+		Amount:     decimal.NewFromInt(75000),
+		Currency:   "IDR",
+		Channel:    "OVO",
+	}); err == nil {
+		t.Error("expected an error for an OVO charge without a mobile number")
+	}
+}
+
+func TestXenditRejectsBadCredentials(t *testing.T) {
+	_, server := newXendit(t)
+	gateway := NewXenditGateway(XenditConfig{APIKey: "wrong", BaseURL: server.URL()})
+
+	_, err := gateway.CreateInvoice(context.Background(), &InvoiceRequest{ExternalID: "order-1", Amount: decimal.NewFromInt(1000)})
+	if err == nil {
+		t.Fatal("expected an error for an invalid API key")
+	}
+}
+
+func TestXenditCallbackToken(t *testing.T) {
+	gateway, server := newXendit(t)
+
+	charge, err := gateway.CreateInvoice(context.Background(), &InvoiceRequest{ExternalID: "order-1", Amount: decimal.NewFromInt(1000)})
+	if err != nil {
+		t.Fatalf("CreateInvoice: %v", err)
+	}
+	body, err := server.ExpireInvoice(charge.ID)
+	if err != nil {
+		t.Fatalf("ExpireInvoice: %v", err)
+	}
+
+	for _, token := range []string{"", "forged"} {
+		if _, err := gateway.ParseCallback(context.Background(), callbackHeader(token), body); !errors.Is(err, ErrInvalidCallback) {
+			t.Errorf("token %q: err = %v, want ErrInvalidCallback", token, err)
+		}
+	}
+
+	unconfigured := NewXenditGateway(XenditConfig{APIKey: "xnd_test_key", BaseURL: server.URL()})
+	if _, err := unconfigured.ParseCallback(context.Background(), callbackHeader(""), body); !errors.Is(err, ErrInvalidCallback) {
+		t.Errorf("err = %v, want callbacks rejected without a configured token", err)
+	}
+
+	event, err := gateway.ParseCallback(context.Background(), callbackHeader(callbackToken), body)
+	if err != nil {
+		t.Fatalf("ParseCallback: %v", err)
+	}
+	if event.Status != ChargeStatusExpired {
+		t.Errorf("status = %s, want expired", event.Status)
+	}
+}
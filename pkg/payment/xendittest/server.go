@@ -0,0 +1,320 @@
+// Package xendittest provides an in-process Xendit stand-in for tests. It
+// implements the invoice and e-wallet charge endpoints used by the payment
+// package and builds the callbacks Xendit would send when a charge settles.
+package xendittest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultInvoiceDuration is Xendit's default invoice lifetime
+const defaultInvoiceDuration = 24 * time.Hour
+
+// Invoice is an invoice created on the server
+type Invoice struct {
+	ID          string
+	ExternalID  string
+	Status      string
+	Amount      float64
+	Currency    string
+	Description string
+	PayerEmail  string
+	InvoiceURL  string
+	ExpiryDate  time.Time
+	CreatedAt   time.Time
+}
+
+// EWalletCharge is an e-wallet charge created on the server
+type EWalletCharge struct {
+	ID                string
+	ReferenceID       string
+	Status            string
+	ChargeAmount      float64
+	Currency          string
+	ChannelCode       string
+	ChannelProperties map[string]string
+	CreatedAt         time.Time
+}
+
+// Server is a fake Xendit API accepting a single secret API key
+type Server struct {
+	server   *httptest.Server
+	apiKey   string
+	mu       sync.Mutex
+	seq      int
+	invoices map[string]*Invoice
+	charges  map[string]*EWalletCharge
+}
+
+// NewServer starts a server that accepts requests authenticated with apiKey
+func NewServer(apiKey string) *Server {
+	s := &Server{
+		apiKey:   apiKey,
+		invoices: make(map[string]*Invoice),
+		charges:  make(map[string]*EWalletCharge),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v2/invoices", s.authenticated(s.createInvoice))
+	mux.HandleFunc("POST /ewallets/charges", s.authenticated(s.createEWalletCharge))
+	s.server = httptest.NewServer(mux)
+
+	return s
+}
+
+// URL returns the base URL to configure the gateway with
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Invoices returns the invoices created so far, oldest first
+func (s *Server) Invoices() []Invoice {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invoices := make([]Invoice, 0, len(s.invoices))
+	for _, invoice := range s.invoices {
+		invoices = append(invoices, *invoice)
+	}
+	sort.Slice(invoices, func(i, j int) bool { return invoices[i].ID < invoices[j].ID })
+	return invoices
+}
+
+// EWalletCharges returns the e-wallet charges created so far, oldest first
+func (s *Server) EWalletCharges() []EWalletCharge {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	charges := make([]EWalletCharge, 0, len(s.charges))
+	for _, charge := range s.charges {
+		charges = append(charges, *charge)
+	}
+	sort.Slice(charges, func(i, j int) bool { return charges[i].ID < charges[j].ID })
+	return charges
+}
+
+// PayInvoice marks an invoice paid in full and returns the callback body
+// Xendit sends for it
+func (s *Server) PayInvoice(id string) ([]byte, error) {
+	return s.settleInvoice(id, "PAID")
+}
+
+// ExpireInvoice marks an invoice expired and returns the callback body
+// Xendit sends for it
+func (s *Server) ExpireInvoice(id string) ([]byte, error) {
+	return s.settleInvoice(id, "EXPIRED")
+}
+
+// CompleteEWalletCharge marks an e-wallet charge succeeded or failed and
+// returns the callback body Xendit sends for it
+func (s *Server) CompleteEWalletCharge(id string, succeeded bool) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	charge, ok := s.charges[id]
+	if !ok {
+		return nil, fmt.Errorf("xendittest: e-wallet charge %s not found", id)
+	}
+
+	charge.Status = "FAILED"
+	captured := 0.0
+	if succeeded {
+		charge.Status = "SUCCEEDED"
+		captured = charge.ChargeAmount
+	}
+
+	data := ewalletChargeBody(charge)
+	data["capture_amount"] = captured
+	data["updated"] = time.Now().UTC().Format(time.RFC3339)
+
+	return json.Marshal(map[string]interface{}{
+		"event":       "ewallet.capture",
+		"business_id": "xendittest",
+		"created":     time.Now().UTC().Format(time.RFC3339),
+		"data":        data,
+	})
+}
+
+func (s *Server) settleInvoice(id, status string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invoice, ok := s.invoices[id]
+	if !ok {
+		return nil, fmt.Errorf("xendittest: invoice %s not found", id)
+	}
+	if invoice.Status != "PENDING" {
+		return nil, fmt.Errorf("xendittest: invoice %s is already %s", id, invoice.Status)
+	}
+	invoice.Status = status
+
+	body := invoiceBody(invoice)
+	if status == "PAID" {
+		body["paid_amount"] = invoice.Amount
+		body["paid_at"] = time.Now().UTC().Format(time.RFC3339)
+		body["payment_method"] = "BANK_TRANSFER"
+		body["payment_channel"] = "BCA"
+	}
+	return json.Marshal(body)
+}
+
+// authenticated rejects requests without the server's API key as the basic
+// auth username, as Xendit does
+func (s *Server) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, _, ok := r.BasicAuth()
+		if !ok || username != s.apiKey {
+			writeError(w, http.StatusUnauthorized, "INVALID_API_KEY", "API key is invalid")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) createInvoice(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ExternalID      string  `json:"external_id"`
+		Amount          float64 `json:"amount"`
+		Currency        string  `json:"currency"`
+		Description     string  `json:"description"`
+		PayerEmail      string  `json:"payer_email"`
+		InvoiceDuration int64   `json:"invoice_duration"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "API_VALIDATION_ERROR", "request body is not valid JSON")
+		return
+	}
+	if req.ExternalID == "" || req.Amount <= 0 {
+		writeError(w, http.StatusBadRequest, "API_VALIDATION_ERROR", "external_id and a positive amount are required")
+		return
+	}
+
+	duration := defaultInvoiceDuration
+	if req.InvoiceDuration > 0 {
+		duration = time.Duration(req.InvoiceDuration) * time.Second
+	}
+	currency := req.Currency
+	if currency == "" {
+		currency = "IDR"
+	}
+
+	s.mu.Lock()
+	s.seq++
+	now := time.Now().UTC()
+	invoice := &Invoice{
+		ID:          fmt.Sprintf("inv_%06d", s.seq),
+		ExternalID:  req.ExternalID,
+		Status:      "PENDING",
+		Amount:      req.Amount,
+		Currency:    currency,
+		Description: req.Description,
+		PayerEmail:  req.PayerEmail,
+		ExpiryDate:  now.Add(duration),
+		CreatedAt:   now,
+	}
+	invoice.InvoiceURL = s.server.URL + "/web/invoices/" + invoice.ID
+	s.invoices[invoice.ID] = invoice
+	body := invoiceBody(invoice)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) createEWalletCharge(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ReferenceID       string            `json:"reference_id"`
+		Currency          string            `json:"currency"`
+		Amount            float64           `json:"amount"`
+		CheckoutMethod    string            `json:"checkout_method"`
+		ChannelCode       string            `json:"channel_code"`
+		ChannelProperties map[string]string `json:"channel_properties"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "API_VALIDATION_ERROR", "request body is not valid JSON")
+		return
+	}
+	if req.ReferenceID == "" || req.Currency == "" || req.Amount <= 0 || req.ChannelCode == "" || req.CheckoutMethod == "" {
+		writeError(w, http.StatusBadRequest, "API_VALIDATION_ERROR", "reference_id, currency, amount, checkout_method and channel_code are required")
+		return
+	}
+	if req.ChannelCode == "ID_OVO" && req.ChannelProperties["mobile_number"] == "" {
+		writeError(w, http.StatusBadRequest, "API_VALIDATION_ERROR", "channel_properties.mobile_number is required for ID_OVO")
+		return
+	}
+
+	s.mu.Lock()
+	s.seq++
+	charge := &EWalletCharge{
+		ID:                fmt.Sprintf("ewc_%06d", s.seq),
+		ReferenceID:       req.ReferenceID,
+		Status:            "PENDING",
+		ChargeAmount:      req.Amount,
+		Currency:          req.Currency,
+		ChannelCode:       req.ChannelCode,
+		ChannelProperties: req.ChannelProperties,
+		CreatedAt:         time.Now().UTC(),
+	}
+	s.charges[charge.ID] = charge
+	body := ewalletChargeBody(charge)
+	if req.ChannelCode != "ID_OVO" {
+		checkoutURL := s.server.URL + "/web/ewallets/" + charge.ID
+		body["actions"] = map[string]interface{}{
+			"desktop_web_checkout_url":     checkoutURL,
+			"mobile_web_checkout_url":      checkoutURL,
+			"mobile_deeplink_checkout_url": "xendittest://ewallets/" + charge.ID,
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusAccepted, body)
+}
+
+func invoiceBody(invoice *Invoice) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          invoice.ID,
+		"external_id": invoice.ExternalID,
+		"status":      invoice.Status,
+		"amount":      invoice.Amount,
+		"currency":    invoice.Currency,
+		"description": invoice.Description,
+		"payer_email": invoice.PayerEmail,
+		"invoice_url": invoice.InvoiceURL,
+		"expiry_date": invoice.ExpiryDate.Format(time.RFC3339),
+		"created":     invoice.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func ewalletChargeBody(charge *EWalletCharge) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                 charge.ID,
+		"reference_id":       charge.ReferenceID,
+		"status":             charge.Status,
+		"currency":           charge.Currency,
+		"charge_amount":      charge.ChargeAmount,
+		"checkout_method":    "ONE_TIME_PAYMENT",
+		"channel_code":       charge.ChannelCode,
+		"channel_properties": charge.ChannelProperties,
+		"created":            charge.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]string{"error_code": code, "message": message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
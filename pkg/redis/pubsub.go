@@ -0,0 +1,134 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Message is a message received on a subscribed channel
+type Message struct {
+	Channel string
+	Payload []byte
+}
+
+// PubSub is a subscription on a dedicated connection
+type PubSub struct {
+	cn        *conn
+	messages  chan Message
+	done      chan struct{}
+	closeOnce sync.Once
+	err       error
+	errMu     sync.Mutex
+}
+
+// Subscribe opens a connection subscribed to the given channels. The
+// subscription ends when it is closed or the connection is lost; callers
+// that need to keep listening subscribe again.
+func (c *Client) Subscribe(ctx context.Context, channels ...string) (*PubSub, error) {
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("redis: no channels to subscribe to")
+	}
+
+	cn, err := dial(ctx, c.opts)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]interface{}, 0, len(channels)+1)
+	args = append(args, "SUBSCRIBE")
+	for _, channel := range channels {
+		args = append(args, channel)
+	}
+
+	// One confirmation is sent per channel
+	if err := cn.netConn.SetDeadline(time.Now().Add(c.opts.WriteTimeout + c.opts.ReadTimeout)); err != nil {
+		cn.close()
+		return nil, err
+	}
+	if err := writeCommand(cn.writer, args...); err != nil {
+		cn.close()
+		return nil, err
+	}
+	for range channels {
+		reply, err := readReply(cn.reader)
+		if err != nil {
+			cn.close()
+			return nil, err
+		}
+		if replyErr, ok := reply.(Error); ok {
+			cn.close()
+			return nil, replyErr
+		}
+	}
+
+	// Messages arrive at any time, so reads wait without a deadline
+	if err := cn.netConn.SetDeadline(time.Time{}); err != nil {
+		cn.close()
+		return nil, err
+	}
+
+	ps := &PubSub{
+		cn:       cn,
+		messages: make(chan Message, 100),
+		done:     make(chan struct{}),
+	}
+	go ps.receive()
+
+	return ps, nil
+}
+
+// Channel returns the received messages. It is closed when the subscription ends.
+func (ps *PubSub) Channel() <-chan Message {
+	return ps.messages
+}
+
+// Err returns the error that ended the subscription, if any
+func (ps *PubSub) Err() error {
+	ps.errMu.Lock()
+	defer ps.errMu.Unlock()
+	return ps.err
+}
+
+// Close ends the subscription
+func (ps *PubSub) Close() error {
+	ps.closeOnce.Do(func() {
+		close(ps.done)
+		ps.cn.close()
+	})
+	return nil
+}
+
+// receive reads pushed messages until the connection is closed
+func (ps *PubSub) receive() {
+	defer close(ps.messages)
+
+	for {
+		reply, err := readReply(ps.cn.reader)
+		if err != nil {
+			ps.errMu.Lock()
+			ps.err = err
+			ps.errMu.Unlock()
+			ps.Close()
+			return
+		}
+
+		parts, ok := reply.([]interface{})
+		if !ok || len(parts) != 3 {
+			continue
+		}
+		kind, _ := toBytes(parts[0])
+		if string(kind) != "message" {
+			continue
+		}
+		channel, _ := toBytes(parts[1])
+		payload, _ := toBytes(parts[2])
+
+		select {
+		case ps.messages <- Message{Channel: string(channel), Payload: payload}:
+		case <-ps.done:
+			return
+		}
+	}
+}
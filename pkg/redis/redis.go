@@ -0,0 +1,458 @@
+// Package redis provides a small Redis client speaking the RESP2 protocol.
+// It covers the commands the application uses: key/value access with
+// expiry, counters, key scans and pub/sub.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNil is returned when a key does not exist
+var ErrNil = errors.New("redis: nil")
+
+// ErrClosed is returned when the client has been closed
+var ErrClosed = errors.New("redis: client is closed")
+
+// Error is an error reply from the server
+type Error string
+
+func (e Error) Error() string { return string(e) }
+
+// Options configure a client
+type Options struct {
+	Addr         string
+	Password     string
+	DB           int
+	PoolSize     int
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+// ParseURL parses a redis://[:password@]host:port[/db] URL into options
+func ParseURL(rawURL string) (*Options, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	if u.Scheme != "redis" {
+		return nil, fmt.Errorf("invalid redis URL scheme: %s", u.Scheme)
+	}
+
+	opts := &Options{Addr: u.Host}
+	if u.Port() == "" {
+		opts.Addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		if password, ok := u.User.Password(); ok {
+			opts.Password = password
+		} else {
+			opts.Password = u.User.Username()
+		}
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		opts.DB, err = strconv.Atoi(db)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis database: %s", db)
+		}
+	}
+
+	return opts, nil
+}
+
+// Client is a pooled Redis client safe for concurrent use
+type Client struct {
+	opts   Options
+	pool   chan *conn
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewClient creates a new client. Connections are opened on demand.
+func NewClient(opts *Options) *Client {
+	o := *opts
+	if o.PoolSize <= 0 {
+		o.PoolSize = 10
+	}
+	if o.DialTimeout <= 0 {
+		o.DialTimeout = 5 * time.Second
+	}
+	if o.ReadTimeout <= 0 {
+		o.ReadTimeout = 3 * time.Second
+	}
+	if o.WriteTimeout <= 0 {
+		o.WriteTimeout = o.ReadTimeout
+	}
+
+	return &Client{
+		opts: o,
+		pool: make(chan *conn, o.PoolSize),
+	}
+}
+
+// Do sends a command and returns its reply. Replies are string, int64,
+// []byte, []interface{} or nil for a missing value.
+func (c *Client) Do(ctx context.Context, args ...interface{}) (interface{}, error) {
+	cn, err := c.getConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cn.do(ctx, c.opts, args...)
+	c.putConn(cn, err)
+	if err != nil {
+		return nil, err
+	}
+	if replyErr, ok := reply.(Error); ok {
+		return nil, replyErr
+	}
+	return reply, nil
+}
+
+// Ping checks that the server is reachable
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+// Get returns the value of a key, or ErrNil if it does not exist
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	reply, err := c.Do(ctx, "GET", key)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, ErrNil
+	}
+	return toBytes(reply)
+}
+
+// Set stores a value. A zero ttl stores the value without expiry.
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []interface{}{"SET", key, value}
+	if ttl > 0 {
+		args = append(args, "PX", ttl.Milliseconds())
+	}
+	_, err := c.Do(ctx, args...)
+	return err
+}
+
+// Del removes keys and returns how many existed
+func (c *Client) Del(ctx context.Context, keys ...string) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	args := make([]interface{}, 0, len(keys)+1)
+	args = append(args, "DEL")
+	for _, key := range keys {
+		args = append(args, key)
+	}
+
+	reply, err := c.Do(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	return toInt(reply)
+}
+
+// Incr increments the integer value of a key, starting from 0 when it does
+// not exist, and returns the new value
+func (c *Client) Incr(ctx context.Context, key string) (int64, error) {
+	reply, err := c.Do(ctx, "INCR", key)
+	if err != nil {
+		return 0, err
+	}
+	return toInt(reply)
+}
+
+// PExpire sets a key's time to live and reports whether the key exists
+func (c *Client) PExpire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	reply, err := c.Do(ctx, "PEXPIRE", key, ttl.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+	n, err := toInt(reply)
+	return n == 1, err
+}
+
+// Scan returns one page of keys matching a glob pattern and the cursor of
+// the next page, which is 0 after the last page
+func (c *Client) Scan(ctx context.Context, cursor uint64, match string, count int) ([]string, uint64, error) {
+	reply, err := c.Do(ctx, "SCAN", cursor, "MATCH", match, "COUNT", count)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	parts, ok := reply.([]interface{})
+	if !ok || len(parts) != 2 {
+		return nil, 0, fmt.Errorf("redis: unexpected SCAN reply %T", reply)
+	}
+	next, err := toBytes(parts[0])
+	if err != nil {
+		return nil, 0, err
+	}
+	nextCursor, err := strconv.ParseUint(string(next), 10, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("redis: invalid SCAN cursor: %w", err)
+	}
+
+	items, _ := parts[1].([]interface{})
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		key, err := toBytes(item)
+		if err != nil {
+			return nil, 0, err
+		}
+		keys = append(keys, string(key))
+	}
+	return keys, nextCursor, nil
+}
+
+// Publish sends a message to a channel and returns the number of receivers
+func (c *Client) Publish(ctx context.Context, channel string, message []byte) (int64, error) {
+	reply, err := c.Do(ctx, "PUBLISH", channel, message)
+	if err != nil {
+		return 0, err
+	}
+	return toInt(reply)
+}
+
+// Close closes the client and its idle connections
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	close(c.pool)
+	for cn := range c.pool {
+		cn.close()
+	}
+	return nil
+}
+
+// getConn takes an idle connection from the pool or opens a new one
+func (c *Client) getConn(ctx context.Context) (*conn, error) {
+	c.mu.Lock()
+	closed := c.closed
+	c.mu.Unlock()
+	if closed {
+		return nil, ErrClosed
+	}
+
+	select {
+	case cn, ok := <-c.pool:
+		if ok {
+			return cn, nil
+		}
+		return nil, ErrClosed
+	default:
+	}
+
+	return dial(ctx, c.opts)
+}
+
+// putConn returns a connection to the pool. Connections that failed at the
+// network level are closed as their stream may be out of sync.
+func (c *Client) putConn(cn *conn, err error) {
+	if err != nil {
+		cn.close()
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		cn.close()
+		return
+	}
+	select {
+	case c.pool <- cn:
+	default:
+		cn.close()
+	}
+}
+
+// conn is a single connection to the server
+type conn struct {
+	netConn net.Conn
+	reader  *bufio.Reader
+	writer  *bufio.Writer
+}
+
+// dial opens a connection, authenticating and selecting the database
+func dial(ctx context.Context, opts Options) (*conn, error) {
+	dialer := net.Dialer{Timeout: opts.DialTimeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", opts.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis: failed to connect to %s: %w", opts.Addr, err)
+	}
+
+	cn := &conn{
+		netConn: netConn,
+		reader:  bufio.NewReader(netConn),
+		writer:  bufio.NewWriter(netConn),
+	}
+
+	if opts.Password != "" {
+		if err := cn.expectOK(ctx, opts, "AUTH", opts.Password); err != nil {
+			cn.close()
+			return nil, err
+		}
+	}
+	if opts.DB != 0 {
+		if err := cn.expectOK(ctx, opts, "SELECT", opts.DB); err != nil {
+			cn.close()
+			return nil, err
+		}
+	}
+
+	return cn, nil
+}
+
+// expectOK sends a command whose reply must not be an error
+func (cn *conn) expectOK(ctx context.Context, opts Options, args ...interface{}) error {
+	reply, err := cn.do(ctx, opts, args...)
+	if err != nil {
+		return err
+	}
+	if replyErr, ok := reply.(Error); ok {
+		return fmt.Errorf("redis: %s failed: %w", args[0], replyErr)
+	}
+	return nil
+}
+
+// do writes a command and reads its reply
+func (cn *conn) do(ctx context.Context, opts Options, args ...interface{}) (interface{}, error) {
+	deadline := time.Now().Add(opts.WriteTimeout + opts.ReadTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := cn.netConn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	if err := writeCommand(cn.writer, args...); err != nil {
+		return nil, err
+	}
+	return readReply(cn.reader)
+}
+
+func (cn *conn) close() {
+	cn.netConn.Close()
+}
+
+// writeCommand writes a command as an array of bulk strings
+func writeCommand(w *bufio.Writer, args ...interface{}) error {
+	fmt.Fprintf(w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		var b []byte
+		switch v := arg.(type) {
+		case string:
+			b = []byte(v)
+		case []byte:
+			b = v
+		case int:
+			b = strconv.AppendInt(nil, int64(v), 10)
+		case int64:
+			b = strconv.AppendInt(nil, v, 10)
+		case uint64:
+			b = strconv.AppendUint(nil, v, 10)
+		case float64:
+			b = strconv.AppendFloat(nil, v, 'f', -1, 64)
+		default:
+			return fmt.Errorf("redis: unsupported argument type %T", arg)
+		}
+		fmt.Fprintf(w, "$%d\r\n", len(b))
+		w.Write(b)
+		w.WriteString("\r\n")
+	}
+	return w.Flush()
+}
+
+// readReply reads a single reply. Error replies are returned as an Error
+// value so that the connection stays usable.
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return Error(body), nil
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk length %q", body)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:size], nil
+	case '*':
+		size, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed array length %q", body)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, size)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+	}
+}
+
+func toBytes(reply interface{}) ([]byte, error) {
+	switch v := reply.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %T", reply)
+	}
+}
+
+func toInt(reply interface{}) (int64, error) {
+	switch v := reply.(type) {
+	case int64:
+		return v, nil
+	case []byte:
+		return strconv.ParseInt(string(v), 10, 64)
+	default:
+		return 0, fmt.Errorf("redis: unexpected reply %T", reply)
+	}
+}
@@ -0,0 +1,141 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kirimku/smartseller-backend/pkg/redis"
+	"github.com/kirimku/smartseller-backend/pkg/redis/redistest"
+)
+
+func newTestClient(t *testing.T) (*redis.Client, *redistest.Server) {
+	t.Helper()
+
+	server, err := redistest.NewServer()
+	if err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	opts, err := redis.ParseURL(server.URL())
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %v", err)
+	}
+	client := redis.NewClient(opts)
+	t.Cleanup(func() { client.Close() })
+
+	return client, server
+}
+
+func TestParseURL(t *testing.T) {
+	opts, err := redis.ParseURL("redis://:secret@cache.internal/2")
+	if err != nil {
+		t.Fatalf("ParseURL() error = %v", err)
+	}
+	if opts.Addr != "cache.internal:6379" || opts.Password != "secret" || opts.DB != 2 {
+		t.Errorf("ParseURL() = %+v", opts)
+	}
+
+	if _, err := redis.ParseURL("http://cache.internal"); err == nil {
+		t.Error("Expected an error for a non-redis scheme")
+	}
+}
+
+func TestClientKeyValue(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	if _, err := client.Get(ctx, "missing"); !errors.Is(err, redis.ErrNil) {
+		t.Fatalf("Get() error = %v, want ErrNil", err)
+	}
+
+	if err := client.Set(ctx, "greeting", []byte("hello"), time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	value, err := client.Get(ctx, "greeting")
+	if err != nil || string(value) != "hello" {
+		t.Fatalf("Get() = %q, %v", value, err)
+	}
+
+	keys, cursor, err := client.Scan(ctx, 0, "greet*", 10)
+	if err != nil || cursor != 0 || len(keys) != 1 {
+		t.Fatalf("Scan() = %v, %d, %v", keys, cursor, err)
+	}
+
+	deleted, err := client.Del(ctx, "greeting", "missing")
+	if err != nil || deleted != 1 {
+		t.Fatalf("Del() = %d, %v", deleted, err)
+	}
+
+	if _, err := client.Do(ctx, "NOSUCHCOMMAND"); err == nil {
+		t.Error("Expected an error reply for an unknown command")
+	}
+	// The connection stays usable after an error reply
+	if err := client.Ping(ctx); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+}
+
+func TestClientCounters(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	for want := int64(1); want <= 3; want++ {
+		got, err := client.Incr(ctx, "hits")
+		if err != nil || got != want {
+			t.Fatalf("Incr() = %d, %v, want %d", got, err, want)
+		}
+	}
+
+	ok, err := client.PExpire(ctx, "hits", 20*time.Millisecond)
+	if err != nil || !ok {
+		t.Fatalf("PExpire() = %v, %v", ok, err)
+	}
+	if ok, _ := client.PExpire(ctx, "missing", time.Second); ok {
+		t.Error("PExpire() reported a missing key as existing")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if keys := server.Keys(); len(keys) != 0 {
+		t.Errorf("Expected the counter to expire, keys = %v", keys)
+	}
+	if got, _ := client.Incr(ctx, "hits"); got != 1 {
+		t.Errorf("Incr() after expiry = %d, want 1", got)
+	}
+}
+
+func TestClientPubSub(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	sub, err := client.Subscribe(ctx, "events")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	defer sub.Close()
+
+	if _, err := client.Publish(ctx, "events", []byte("created")); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	select {
+	case msg := <-sub.Channel():
+		if msg.Channel != "events" || string(msg.Payload) != "created" {
+			t.Errorf("Received %s %q", msg.Channel, msg.Payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for a message")
+	}
+
+	server.DropConnections()
+	select {
+	case _, ok := <-sub.Channel():
+		if ok {
+			t.Error("Expected the channel to close when the connection drops")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the subscription to end")
+	}
+}
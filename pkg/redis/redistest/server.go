@@ -0,0 +1,370 @@
+// Package redistest provides an in-process Redis stand-in for tests. It
+// speaks RESP2 and implements the commands used by the redis package.
+package redistest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server is an in-memory Redis server listening on a local port
+type Server struct {
+	listener    net.Listener
+	mu          sync.Mutex
+	data        map[string]entry
+	subscribers map[string]map[*client]struct{}
+	clients     map[*client]struct{}
+	wg          sync.WaitGroup
+}
+
+type entry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+type client struct {
+	conn    net.Conn
+	writeMu sync.Mutex
+}
+
+// NewServer starts a server on a random local port
+func NewServer() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+
+	s := &Server{
+		listener:    listener,
+		data:        make(map[string]entry),
+		subscribers: make(map[string]map[*client]struct{}),
+		clients:     make(map[*client]struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.accept()
+
+	return s, nil
+}
+
+// Addr returns the host:port the server listens on
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// URL returns a redis:// URL for the server
+func (s *Server) URL() string {
+	return "redis://" + s.Addr()
+}
+
+// Keys returns the names of the live keys, sorted
+func (s *Server) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+	for key := range s.data {
+		if s.live(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// DropConnections closes every client connection, as a server restart would
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c := range s.clients {
+		c.conn.Close()
+	}
+}
+
+// Close stops the server and closes its connections
+func (s *Server) Close() {
+	s.listener.Close()
+	s.DropConnections()
+	s.wg.Wait()
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		c := &client{conn: conn}
+		s.mu.Lock()
+		s.clients[c] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.serve(c)
+	}
+}
+
+func (s *Server) serve(c *client) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, c)
+		for _, subs := range s.subscribers {
+			delete(subs, c)
+		}
+		s.mu.Unlock()
+		c.conn.Close()
+	}()
+
+	reader := bufio.NewReader(c.conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+		c.write(s.execute(c, args))
+	}
+}
+
+// execute runs a command and returns its encoded reply
+func (s *Server) execute(c *client, args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cmd := strings.ToUpper(args[0])
+	switch cmd {
+	case "PING":
+		return "+PONG\r\n"
+
+	case "AUTH", "SELECT":
+		return "+OK\r\n"
+
+	case "GET":
+		if len(args) != 2 {
+			return wrongArgs(cmd)
+		}
+		if !s.live(args[1]) {
+			return "$-1\r\n"
+		}
+		return bulk(string(s.data[args[1]].value))
+
+	case "SET":
+		if len(args) < 3 {
+			return wrongArgs(cmd)
+		}
+		e := entry{value: []byte(args[2])}
+		for i := 3; i+1 < len(args); i += 2 {
+			n, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil {
+				return "-ERR value is not an integer or out of range\r\n"
+			}
+			switch strings.ToUpper(args[i]) {
+			case "PX":
+				e.expiresAt = time.Now().Add(time.Duration(n) * time.Millisecond)
+			case "EX":
+				e.expiresAt = time.Now().Add(time.Duration(n) * time.Second)
+			default:
+				return "-ERR syntax error\r\n"
+			}
+		}
+		s.data[args[1]] = e
+		return "+OK\r\n"
+
+	case "INCR":
+		if len(args) != 2 {
+			return wrongArgs(cmd)
+		}
+		e := entry{value: []byte("0")}
+		if s.live(args[1]) {
+			e = s.data[args[1]]
+		}
+		n, err := strconv.ParseInt(string(e.value), 10, 64)
+		if err != nil {
+			return "-ERR value is not an integer or out of range\r\n"
+		}
+		e.value = []byte(strconv.FormatInt(n+1, 10))
+		s.data[args[1]] = e
+		return integer(int(n + 1))
+
+	case "PEXPIRE":
+		if len(args) != 3 {
+			return wrongArgs(cmd)
+		}
+		ms, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return "-ERR value is not an integer or out of range\r\n"
+		}
+		if !s.live(args[1]) {
+			return integer(0)
+		}
+		e := s.data[args[1]]
+		e.expiresAt = time.Now().Add(time.Duration(ms) * time.Millisecond)
+		s.data[args[1]] = e
+		return integer(1)
+
+	case "DEL", "EXISTS":
+		if len(args) < 2 {
+			return wrongArgs(cmd)
+		}
+		count := 0
+		for _, key := range args[1:] {
+			if s.live(key) {
+				count++
+				if cmd == "DEL" {
+					delete(s.data, key)
+				}
+			}
+		}
+		return integer(count)
+
+	case "DBSIZE":
+		count := 0
+		for key := range s.data {
+			if s.live(key) {
+				count++
+			}
+		}
+		return integer(count)
+
+	case "FLUSHDB", "FLUSHALL":
+		s.data = make(map[string]entry)
+		return "+OK\r\n"
+
+	case "SCAN":
+		// Every key is returned in a single page
+		match := "*"
+		for i := 2; i+1 < len(args); i += 2 {
+			if strings.ToUpper(args[i]) == "MATCH" {
+				match = args[i+1]
+			}
+		}
+		var keys []string
+		for key := range s.data {
+			if ok, _ := path.Match(match, key); ok && s.live(key) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		reply := "*2\r\n" + bulk("0") + fmt.Sprintf("*%d\r\n", len(keys))
+		for _, key := range keys {
+			reply += bulk(key)
+		}
+		return reply
+
+	case "PUBLISH":
+		if len(args) != 3 {
+			return wrongArgs(cmd)
+		}
+		subs := s.subscribers[args[1]]
+		message := "*3\r\n" + bulk("message") + bulk(args[1]) + bulk(args[2])
+		for sub := range subs {
+			sub.write(message)
+		}
+		return integer(len(subs))
+
+	case "SUBSCRIBE":
+		if len(args) < 2 {
+			return wrongArgs(cmd)
+		}
+		var reply string
+		for i, channel := range args[1:] {
+			if s.subscribers[channel] == nil {
+				s.subscribers[channel] = make(map[*client]struct{})
+			}
+			s.subscribers[channel][c] = struct{}{}
+			reply += "*3\r\n" + bulk("subscribe") + bulk(channel) + integer(i+1)
+		}
+		return reply
+
+	default:
+		return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+	}
+}
+
+// live reports whether a key exists and has not expired, removing it if it
+// has. The caller holds the lock.
+func (s *Server) live(key string) bool {
+	e, ok := s.data[key]
+	if !ok {
+		return false
+	}
+	if !e.expiresAt.IsZero() && time.Now().After(e.expiresAt) {
+		delete(s.data, key)
+		return false
+	}
+	return true
+}
+
+func (c *client) write(reply string) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	io.WriteString(c.conn, reply)
+}
+
+// readCommand reads a command sent as an array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return strings.Fields(line), nil
+	}
+
+	count, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		header, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimPrefix(header, "$"))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+func integer(n int) string {
+	return fmt.Sprintf(":%d\r\n", n)
+}
+
+func wrongArgs(cmd string) string {
+	return fmt.Sprintf("-ERR wrong number of arguments for '%s' command\r\n", strings.ToLower(cmd))
+}